@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/email"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/oauth"
+	"neighborenexus/internal/payments"
+	"neighborenexus/internal/push"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/settings"
+	"neighborenexus/internal/storage"
+	"neighborenexus/internal/telemetry"
+	"neighborenexus/internal/vectors"
+	"neighborenexus/internal/webhooks"
+)
+
+// app holds the connections and services shared by every subcommand
+type app struct {
+	cfg *config.Config
+
+	mongoClient *database.MongoClient
+	redisClient *database.RedisClient
+	settings    *settings.Store
+
+	authService         *services.AuthService
+	passwordResets      *services.PasswordResetService
+	notificationService *services.NotificationService
+	mailer              email.Sender
+	embeddingService    *services.EmbeddingService
+	matchingService     *services.MatchingService
+	moderationService   *services.ModerationService
+	announcementService *services.AnnouncementService
+	auditService        *services.AuditService
+	velocityDetector    *services.VelocityDetector
+	exportService       *services.ExportService
+	erasureService      *services.ErasureService
+	uploadService       *services.UploadService
+	matchDecisions      *services.MatchDecisionLog
+	partnerSender       *webhooks.Sender
+	analyticsService    *services.AnalyticsService
+	analyticsSink       *webhooks.Sender
+	privacyService      *services.PrivacyService
+	groupService        *services.GroupService
+	eventService        *services.EventService
+	contributionService *services.ContributionService
+	emergencyService    *services.EmergencyService
+	postService         *services.PostService
+	offerService        *services.OfferService
+	partnerService      *services.PartnerService
+	feedbackService     *services.FeedbackService
+	kudosService        *services.KudosService
+	messageService      *services.MessageService
+	pointsService       *services.PointsService
+	trustService        *services.TrustService
+	ratingService       *services.RatingService
+	translationService  *services.TranslationService
+	recordService       *services.RecordService
+	mapService          *services.MapService
+	statsService        *services.StatsService
+	categoryService     *services.CategoryService
+	topicService        *services.TopicService
+	slaService          *services.SLAService
+	taskService         *services.TaskService
+	expiryService       *services.ExpiryService
+	waitlistService     *services.WaitlistService
+	boostService        *services.BoostService
+	needComposer        *services.NeedComposer
+	transcriber         services.Transcriber
+	referralService     *services.ReferralService
+	consentService      *services.ConsentService
+	pushService         *services.PushService
+	inboxService        *services.InboxService
+	websocketService    *services.WebSocketService
+
+	// shutdownTracing flushes buffered spans
+	shutdownTracing func(context.Context) error
+}
+
+// newApp connects to the datastores and wires up the services
+func newApp(cfg *config.Config) (*app, error) {
+	// Spans are no-ops until tracing is turned on
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.TracingEnabled {
+		shutdown, err := telemetry.Setup(context.Background(), "neighborenexus", cfg.TraceSampleRatio)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start tracing: %w", err)
+		}
+		shutdownTracing = shutdown
+	}
+
+	// Initialize database connections
+	// PII is sealed at rest once encryption keys are configured
+	var fieldCipher *database.FieldCipher
+	if len(cfg.FieldEncryptionKeys) > 0 {
+		keyWrapper, err := database.NewLocalKeyWrapper(cfg.FieldEncryptionKeys)
+		if err != nil {
+			return nil, err
+		}
+		fieldCipher = database.NewFieldCipher(keyWrapper)
+	}
+
+	mongoClient, err := database.NewMongoClient(cfg.MongoURI, fieldCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient := database.NewRedisClient(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+
+	// Rate limits default to the environment profile until an admin overrides them
+	defaults := settings.Defaults()
+	defaults.RateLimitAuthPerMinute = cfg.RateLimitAuthPerMinute
+	defaults.RateLimitAPIPerMinute = cfg.RateLimitAPIPerMinute
+	settingsStore := settings.NewStore(redisClient, cfg.SettingsCacheTTL, defaults)
+
+	// Notifications are queued so whichever instance holds the user's socket delivers them
+	notify := func(ctx context.Context, userIDs []string, message models.WebSocketMessage) error {
+		return jobs.EnqueueNotification(ctx, redisClient, userIDs, message)
+	}
+	notifyTopics := func(ctx context.Context, topics []string, message models.WebSocketMessage) error {
+		return jobs.EnqueueTopicNotification(ctx, redisClient, topics, message)
+	}
+
+	// Analytics events are queued so request handlers never wait on storage
+	var analyticsService *services.AnalyticsService
+	if cfg.AnalyticsPseudonymKey != "" {
+		analyticsService = services.NewAnalyticsService(cfg.AnalyticsPseudonymKey, func(ctx context.Context, event models.AnalyticsEvent) error {
+			return jobs.EnqueueAnalyticsEvent(ctx, redisClient, event)
+		})
+	}
+	var analyticsSinkURLs []string
+	if cfg.AnalyticsSinkURL != "" {
+		analyticsSinkURLs = []string{cfg.AnalyticsSinkURL}
+	}
+
+	// Incidents never suspend when the threshold is off
+	var incidentSuspendAt models.IncidentSeverity
+	if cfg.IncidentSuspendAt != "off" {
+		incidentSuspendAt = models.IncidentSeverity(cfg.IncidentSuspendAt)
+	}
+
+	// Voice notes are transcribed by Whisper when OpenAI is configured
+	var transcriber services.Transcriber
+	if cfg.OpenAIKey != "" {
+		transcriber = services.NewWhisperTranscriber(cfg.OpenAIKey)
+	}
+
+	// Email goes through SMTP or SendGrid when configured, and to the log otherwise
+	var mailer email.Sender = email.LogSender{}
+	switch {
+	case cfg.SMTPHost != "":
+		mailer = email.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.EmailFrom)
+	case cfg.SendGridAPIKey != "":
+		mailer = email.NewSendGridSender(cfg.SendGridAPIKey, cfg.EmailFrom)
+	}
+
+	// Notification email is sent by the worker when it runs separately
+	notificationMailer := mailer
+	if cfg.AsyncMatching {
+		notificationMailer = jobs.NewEmailQueue(redisClient)
+	}
+
+	// Push reaches users without a WebSocket connection on the platforms with credentials
+	var fcm, apns push.Sender
+	if cfg.FCMCredentialsFile != "" {
+		fcm, err = push.NewFCMClient(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.APNsKeyFile != "" {
+		apns, err = push.NewAPNsClient(cfg.APNsKeyFile, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic, cfg.APNsProduction)
+		if err != nil {
+			return nil, err
+		}
+	}
+	presence := services.NewPresence(redisClient)
+	pushService := services.NewPushService(mongoClient, presence, fcm, apns)
+
+	// Photos and need images are uploaded straight to the bucket, when one is set
+	var store *storage.S3Client
+	if cfg.S3Bucket != "" {
+		store, err = storage.NewS3Client(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKeyID, cfg.S3SecretAccessKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	uploadService := services.NewUploadService(mongoClient, store, cfg.MaxImageBytes)
+
+	// Users can sign in with the providers that have credentials
+	var providers []*oauth.Provider
+	callbackURL := func(provider models.OAuthProvider) string {
+		return strings.TrimSuffix(cfg.OAuthRedirectBaseURL, "/") + "/api/v2/auth/oauth/" + string(provider) + "/callback"
+	}
+	if cfg.GoogleClientID != "" {
+		providers = append(providers, oauth.NewGoogle(cfg.GoogleClientID, cfg.GoogleClientSecret, callbackURL(models.OAuthGoogle)))
+	}
+	if cfg.AppleServicesID != "" {
+		apple, err := oauth.NewApple(cfg.AppleServicesID, cfg.AppleTeamID, cfg.AppleKeyID, cfg.AppleKeyFile, callbackURL(models.OAuthApple))
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, apple)
+	}
+
+	// Messages sent straight to this instance's sockets are pushed to users it doesn't hold
+	var pushOffline func(ctx context.Context, userIDs []string, message models.WebSocketMessage) error
+	if pushService.Enabled() {
+		pushOffline = func(ctx context.Context, userIDs []string, message models.WebSocketMessage) error {
+			return jobs.EnqueuePush(ctx, redisClient, userIDs, message, true)
+		}
+	}
+
+	// Initialize services
+	sessionStore := services.NewSessionStore(redisClient)
+	embeddingService := services.NewEmbeddingService(cfg.OpenAIKey, redisClient, cfg.EmbeddingCacheTTL)
+	exportService := services.NewExportService(mongoClient, cfg.JWTSecret, cfg.ExportRetention, cfg.ExportLinkTTL)
+	emergencyService := services.NewEmergencyService(mongoClient)
+	matchDecisions := services.NewMatchDecisionLog(mongoClient, cfg.MatchDecisionRetention)
+	recordedDecisions := matchDecisions
+	if !cfg.MatchDecisionLog {
+		recordedDecisions = nil
+	}
+	// Matching searches Pinecone for candidates when it is configured, and scans otherwise
+	var vectorIndex vectors.Store
+	if cfg.PineconeAPIKey != "" {
+		vectorIndex = vectors.NewPineconeClient(cfg.PineconeAPIKey, cfg.PineconeIndex, cfg.PineconeHost)
+	}
+	matchingService := services.NewMatchingService(embeddingService, mongoClient, vectorIndex, settingsStore, emergencyService, recordedDecisions)
+	privacyService := services.NewPrivacyService(mongoClient, models.LocationPrecision(cfg.LocationPrecision), cfg.LocationBlockResolution, cfg.LocationNeighborhoodResolution)
+	groupService := services.NewGroupService(mongoClient, privacyService)
+	ratingService := services.NewRatingService(mongoClient, services.NewOutbox(mongoClient))
+	moderationService := services.NewModerationService(mongoClient, services.NewAbuseClassifier(cfg.OpenAIKey, cfg.ModerationBlocklist), notify, cfg.ModerationSLA, cfg.ModerationEscalationSLA)
+	notificationService := services.NewNotificationService(mongoClient, notificationMailer, cfg.AppBaseURL)
+	slaService := services.NewSLAService(mongoClient, matchingService, privacyService, analyticsService, settingsStore, notify, services.SLATargets{
+		FirstMatch:       cfg.UrgentMatchSLA,
+		Accepted:         cfg.UrgentAcceptSLA,
+		Completed:        cfg.UrgentCompleteSLA,
+		EscalationRadius: cfg.SLAEscalationRadius,
+	})
+	categoryService := services.NewCategoryService(mongoClient)
+	topicService := services.NewTopicService(mongoClient, privacyService, categoryService, notifyTopics)
+	auditService := services.NewAuditService(mongoClient)
+	inboxService := services.NewInboxService(services.NewMongoNotificationStore(mongoClient), cfg.NotificationRetention)
+	taskService := services.NewTaskService(mongoClient, ratingService, slaService, notificationService, notify, auditService, cfg.TaskConfirmWindow)
+	return &app{
+		cfg:                 cfg,
+		mongoClient:         mongoClient,
+		redisClient:         redisClient,
+		settings:            settingsStore,
+		authService:         services.NewAuthService(mongoClient, cfg.JWTSecret, privacyService, sessionStore, mailer, providers...),
+		passwordResets:      services.NewPasswordResetService(mongoClient, redisClient, sessionStore, mailer, cfg.PasswordResetTTL, cfg.PasswordResetURL),
+		notificationService: notificationService,
+		mailer:              mailer,
+		embeddingService:    embeddingService,
+		matchingService:     matchingService,
+		announcementService: services.NewAnnouncementService(mongoClient, matchingService),
+		auditService:        auditService,
+		exportService:       exportService,
+		uploadService:       uploadService,
+		erasureService:      services.NewErasureService(mongoClient, exportService, matchingService, uploadService),
+		matchDecisions:      matchDecisions,
+		partnerSender:       webhooks.NewSender(cfg.PartnerWebhookURLs, cfg.PartnerWebhookSecret),
+		analyticsService:    analyticsService,
+		analyticsSink:       webhooks.NewSender(analyticsSinkURLs, cfg.AnalyticsSinkSecret),
+		moderationService:   moderationService,
+		privacyService:      privacyService,
+		groupService:        groupService,
+		eventService:        services.NewEventService(mongoClient, groupService),
+		referralService:     services.NewReferralService(mongoClient, privacyService, cfg.InviteLinkBaseURL, cfg.ReferralBadgeThresholds),
+		emergencyService:    emergencyService,
+		postService:         services.NewPostService(mongoClient, privacyService),
+		offerService:        services.NewOfferService(mongoClient, privacyService),
+		partnerService:      services.NewPartnerService(mongoClient, privacyService, taskService, categoryService),
+		feedbackService:     services.NewFeedbackService(mongoClient, moderationService, ratingService, services.NewThemeSummarizer(cfg.OpenAIKey), cfg.FeedbackRevealWindow, cfg.FeedbackRequiredAfter, incidentSuspendAt),
+		kudosService:        services.NewKudosService(mongoClient, moderationService, cfg.KudosBadgeThresholds),
+		messageService:      services.NewMessageService(mongoClient, moderationService, notify),
+		pointsService:       services.NewPointsService(mongoClient, cfg.PointsDailyCap),
+		trustService:        services.NewTrustService(mongoClient),
+		ratingService:       ratingService,
+		recordService:       services.NewRecordService(mongoClient),
+		mapService:          services.NewMapService(mongoClient, privacyService),
+		statsService:        services.NewStatsService(mongoClient),
+		categoryService:     categoryService,
+		topicService:        topicService,
+		slaService:          slaService,
+		taskService:         taskService,
+		expiryService:       services.NewExpiryService(mongoClient, notificationService, notify),
+		waitlistService:     services.NewWaitlistService(mongoClient, matchingService, slaService, settingsStore, notify),
+		boostService:        services.NewBoostService(mongoClient, matchingService, slaService, settingsStore, notify, cfg.BoostCooldown, cfg.BoostLimit),
+		needComposer:        services.NewNeedComposer(cfg.OpenAIKey),
+		transcriber:         transcriber,
+		translationService:  services.NewTranslationService(mongoClient, services.NewTranslator(cfg.OpenAIKey)),
+		contributionService: services.NewContributionService(mongoClient, payments.NewStripeClient(cfg.StripeSecretKey), cfg.ContributionCurrency, cfg.ContributionReturnURL),
+		consentService:      services.NewConsentService(mongoClient, cfg.SettingsCacheTTL),
+		velocityDetector: services.NewVelocityDetector(redisClient, moderationService, services.VelocityLimits{
+			BurstLimit:        cfg.SpamBurstLimit,
+			BurstWindow:       cfg.SpamBurstWindow,
+			Throttle:          cfg.SpamThrottle,
+			DuplicateAccounts: cfg.SpamDuplicateAccounts,
+			AccountsPerIP:     cfg.SpamAccountsPerIP,
+			TrackingWindow:    cfg.SpamTrackingWindow,
+		}),
+		pushService:      pushService,
+		inboxService:     inboxService,
+		websocketService: services.NewWebSocketService(presence, pushOffline, inboxService, topicService),
+		shutdownTracing:  shutdownTracing,
+	}, nil
+}
+
+// Close closes the datastore connections
+func (a *app) Close() {
+	if err := a.mongoClient.Close(); err != nil {
+		log.Printf("Failed to close MongoDB connection: %v", err)
+	}
+	if err := a.redisClient.Close(); err != nil {
+		log.Printf("Failed to close Redis connection: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+} 
\ No newline at end of file
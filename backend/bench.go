@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/settings"
+)
+
+// benchArea is where synthetic data is placed: central Seattle, like the seed data
+var benchArea = services.SyntheticArea{Latitude: 47.6062, Longitude: -122.3321}
+
+// runBenchMatching measures matching latency and recall over synthetic
+// volunteers and needs. By default every variant runs in memory; --live
+// instead seeds the volunteers into the database and vector index and
+// drives a dry run of the real matching service with concurrent requests,
+// removing them afterwards.
+// It fails when a variant is slower or less accurate than the given
+// budgets, so it can gate releases.
+func runBenchMatching(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("bench-matching", flag.ExitOnError)
+	volunteers := flags.Int("volunteers", 10000, "synthetic volunteers to match against")
+	needs := flags.Int("needs", 200, "synthetic needs to match, one query each")
+	limit := flags.Int("limit", 10, "matches returned per need")
+	dimensions := flags.Int("dimensions", 1536, "embedding dimensions")
+	radius := flags.Float64("radius-km", 25, "radius of the area synthetic data is spread over")
+	seed := flags.Int64("seed", 1, "random seed; the same seed gives the same data")
+	variants := flags.String("variants", strings.Join(services.MatchVariants, ","), "comma-separated matching variants to compare")
+	live := flags.Bool("live", false, "seed the database and load-test the matching service instead")
+	concurrency := flags.Int("concurrency", 8, "concurrent matching requests with --live")
+	force := flags.Bool("force", false, "allow --live against a production database")
+	maxP95 := flags.Duration("max-p95", 0, "fail when a variant's p95 latency exceeds this; 0 disables")
+	minRecall := flags.Float64("min-recall", 0, "fail when a variant's recall falls below this")
+	flags.Parse(args)
+
+	if *volunteers < 1 || *needs < 1 || *limit < 1 || *dimensions < 1 || *radius <= 0 || *concurrency < 1 {
+		return errors.New("--volunteers, --needs, --limit, --dimensions, --radius-km, and --concurrency must be positive")
+	}
+
+	area := benchArea
+	area.RadiusKm = *radius
+	data := services.NewSyntheticData(*seed, area, *dimensions)
+	log.Printf("Generating %d volunteers and %d needs", *volunteers, *needs)
+	benchmark := &services.MatchBenchmark{
+		Volunteers: data.Volunteers(*volunteers),
+		Needs:      data.Needs(*needs),
+		Limit:      *limit,
+		Tunables:   settings.Defaults(),
+	}
+
+	var results []services.MatchBenchResult
+	var err error
+	if *live {
+		if cfg.Environment == config.EnvProduction && !*force {
+			return errors.New("refusing to load-test a production database without --force")
+		}
+		results, err = loadTestMatching(cfg, benchmark, *concurrency)
+	} else {
+		results, err = benchmark.Run(context.Background(), strings.Split(*variants, ","))
+	}
+	if err != nil {
+		return err
+	}
+
+	printBenchResults(results, *live)
+
+	var failures []string
+	for _, result := range results {
+		if *maxP95 > 0 && result.P95 > *maxP95 {
+			failures = append(failures, fmt.Sprintf("%s p95 %v exceeds %v", result.Variant, result.P95, *maxP95))
+		}
+		if !*live && result.Recall < *minRecall {
+			failures = append(failures, fmt.Sprintf("%s recall %.3f is below %.3f", result.Variant, result.Recall, *minRecall))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// loadTestMatching inserts the benchmark's volunteers and indexes their
+// vectors, matches its needs against them from concurrent workers, and
+// removes the volunteers again. Matching is a dry run, so it records no
+// decisions and saves no matches.
+func loadTestMatching(cfg *config.Config, benchmark *services.MatchBenchmark, concurrency int) ([]services.MatchBenchResult, error) {
+	a, err := newApp(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	matcher := a.matchingService.DryRun()
+	collection := a.mongoClient.GetCollection("volunteers")
+	ids := make([]primitive.ObjectID, len(benchmark.Volunteers))
+	documents := make([]interface{}, len(benchmark.Volunteers))
+	for i, volunteer := range benchmark.Volunteers {
+		ids[i] = volunteer.ID
+		documents[i] = volunteer
+	}
+	needIDs := make([]primitive.ObjectID, len(benchmark.Needs))
+	for i, need := range benchmark.Needs {
+		needIDs[i] = need.ID
+	}
+
+	log.Printf("Seeding %d synthetic volunteers", len(documents))
+	// Synthetic data is removed even when the run fails part way
+	defer removeSyntheticData(a, ids, needIDs)
+	if _, err := collection.InsertMany(ctx, documents); err != nil {
+		return nil, fmt.Errorf("failed to seed volunteers: %w", err)
+	}
+	if err := matcher.IndexVolunteers(ctx, benchmark.Volunteers); err != nil {
+		return nil, err
+	}
+
+	durations := make([]time.Duration, len(benchmark.Needs))
+	queue := make(chan int)
+	errs := make(chan error, concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				began := time.Now()
+				if _, err := matcher.FindMatchesForNeed(ctx, &benchmark.Needs[i], benchmark.Limit); err != nil {
+					errs <- fmt.Errorf("failed to match need %d: %w", i, err)
+					return
+				}
+				durations[i] = time.Since(began)
+			}
+		}()
+	}
+	for i := range benchmark.Needs {
+		select {
+		case queue <- i:
+		case err := <-errs:
+			close(queue)
+			wg.Wait()
+			return nil, err
+		}
+	}
+	close(queue)
+	wg.Wait()
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+	}
+	elapsed := time.Since(start)
+
+	log.Printf("Matched %d needs in %v (%.1f needs/s at concurrency %d)",
+		len(durations), elapsed.Round(time.Millisecond), float64(len(durations))/elapsed.Seconds(), concurrency)
+	return []services.MatchBenchResult{services.SummarizeMatchLatencies("live", durations)}, nil
+}
+
+// removeSyntheticData deletes the synthetic volunteers and their vectors,
+// along with any matches or match decisions naming them or the synthetic
+// needs, which a dry run should never have written
+func removeSyntheticData(a *app, volunteerIDs, needIDs []primitive.ObjectID) {
+	ctx := context.Background()
+	if err := a.matchingService.UnindexVolunteers(ctx, volunteerIDs); err != nil {
+		log.Printf("Failed to remove synthetic volunteer vectors: %v", err)
+	}
+	for _, target := range []struct {
+		collection string
+		filter     bson.M
+	}{
+		{"volunteers", bson.M{"_id": bson.M{"$in": volunteerIDs}}},
+		{"matches", bson.M{"$or": []bson.M{{"need_id": bson.M{"$in": needIDs}}, {"volunteer_id": bson.M{"$in": volunteerIDs}}}}},
+		{"match_decisions", bson.M{"subject_id": bson.M{"$in": append(append([]primitive.ObjectID(nil), needIDs...), volunteerIDs...)}}},
+	} {
+		deleted, err := a.mongoClient.GetCollection(target.collection).DeleteMany(ctx, target.filter)
+		if err != nil {
+			log.Printf("Failed to remove synthetic %s: %v", target.collection, err)
+			continue
+		}
+		if deleted.DeletedCount > 0 {
+			log.Printf("Removed %d synthetic %s", deleted.DeletedCount, target.collection)
+		}
+	}
+}
+
+// printBenchResults writes a table of results to stdout
+func printBenchResults(results []services.MatchBenchResult, live bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "variant\tqueries\tmean\tp50\tp95\tp99\tmax\trecall\t")
+	for _, r := range results {
+		recall := fmt.Sprintf("%.3f", r.Recall)
+		if live {
+			recall = "-"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%v\t%v\t%v\t%v\t%v\t%s\t\n", r.Variant, r.Queries,
+			r.Mean.Round(time.Microsecond), r.P50.Round(time.Microsecond), r.P95.Round(time.Microsecond),
+			r.P99.Round(time.Microsecond), r.Max.Round(time.Microsecond), recall)
+	}
+	w.Flush()
+} 
\ No newline at end of file
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/jobs"
+)
+
+// runDeadJobs lists the jobs that failed for good on each queue, or puts
+// them back on their queues once the cause is fixed
+func runDeadJobs(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("dead-jobs", flag.ExitOnError)
+	queue := flags.String("queue", "", "queue to inspect (default all)")
+	limit := flags.Int64("limit", 20, "maximum jobs listed per queue")
+	requeue := flags.Bool("requeue", false, "move the dead jobs back onto their queues")
+	flags.Parse(args)
+
+	queues := jobs.Queues
+	if *queue != "" {
+		if !contains(jobs.Queues, *queue) {
+			return fmt.Errorf("unknown --queue %q", *queue)
+		}
+		queues = []string{*queue}
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	for _, name := range queues {
+		if *requeue {
+			requeued, err := jobs.RequeueDeadJobs(ctx, a.redisClient, name)
+			if err != nil {
+				return fmt.Errorf("%s: after %d jobs: %w", name, requeued, err)
+			}
+			log.Printf("Requeued %d dead jobs on %s", requeued, name)
+			continue
+		}
+
+		dead, err := jobs.DeadJobs(ctx, a.redisClient, name, *limit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, job := range dead {
+			fmt.Printf("%s\t%s\t%d attempts\t%s\n\t%s\n", job.FailedAt.Format("2006-01-02 15:04:05"), job.Queue, job.Attempts, job.Error, job.Payload)
+		}
+	}
+	return nil
+} 
\ No newline at end of file
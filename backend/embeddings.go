@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/services"
+)
+
+// embeddingTargets maps the --kind flag to collections and job kinds
+var embeddingTargets = []struct {
+	kind       string
+	collection string
+}{
+	{kind: jobs.EmbeddingKindNeed, collection: "needs"},
+	{kind: jobs.EmbeddingKindVolunteer, collection: "volunteers"},
+	{kind: jobs.EmbeddingKindOffer, collection: "offers"},
+}
+
+// runBackfillEmbeddings generates embeddings for documents that have none
+func runBackfillEmbeddings(cfg *config.Config, args []string) error {
+	return runEmbeddings(cfg, "backfill-embeddings", args, bson.M{
+		"$or": []bson.M{
+			{"embedding": bson.M{"$exists": false}},
+			{"embedding": bson.M{"$size": 0}},
+		},
+	})
+}
+
+// runReindexVectors regenerates every embedding, e.g. after changing models
+func runReindexVectors(cfg *config.Config, args []string) error {
+	return runEmbeddings(cfg, "reindex-vectors", args, bson.M{})
+}
+
+// runEmbeddings regenerates embeddings for documents matching filter, either
+// inline or by queueing jobs for the worker
+func runEmbeddings(cfg *config.Config, name string, args []string, filter bson.M) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	kind := flags.String("kind", "all", "documents to process: need, volunteer, offer, or all")
+	enqueue := flags.Bool("enqueue", false, "queue jobs for the worker instead of processing inline")
+	limit := flags.Int64("limit", 0, "maximum documents per kind (0 for no limit)")
+	flags.Parse(args)
+
+	if *kind != "all" && *kind != jobs.EmbeddingKindNeed && *kind != jobs.EmbeddingKindVolunteer && *kind != jobs.EmbeddingKindOffer {
+		return fmt.Errorf("unknown --kind %q", *kind)
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	if !*enqueue && !a.embeddingService.IsAvailable() {
+		return errors.New("embedding service not available; set OPENAI_API_KEY or use --enqueue")
+	}
+
+	ctx := context.Background()
+
+	for _, target := range embeddingTargets {
+		if *kind != "all" && *kind != target.kind {
+			continue
+		}
+
+		opts := options.Find().SetProjection(bson.M{"_id": 1})
+		if *limit > 0 {
+			opts.SetLimit(*limit)
+		}
+		cursor, err := a.mongoClient.GetCollection(target.collection).Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query %s: %w", target.collection, err)
+		}
+
+		var docs []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return fmt.Errorf("failed to read %s: %w", target.collection, err)
+		}
+
+		processed, failed := 0, 0
+		for _, doc := range docs {
+			if *enqueue {
+				err = jobs.EnqueueEmbedding(ctx, a.redisClient, target.kind, doc.ID)
+			} else {
+				err = jobs.RegenerateEmbedding(ctx, a.matchingService, a.mongoClient, jobs.EmbeddingJob{Kind: target.kind, ID: doc.ID.Hex()})
+			}
+			if err != nil {
+				log.Printf("Failed to process %s %s: %v", target.kind, doc.ID.Hex(), err)
+				failed++
+				continue
+			}
+			processed++
+		}
+
+		verb := "Updated"
+		if *enqueue {
+			verb = "Queued"
+		}
+		log.Printf("%s %d %s embeddings (%d failed)", verb, processed, target.kind, failed)
+	}
+
+	return nil
+}
+
+// runSyncVectors stores the embeddings already in Mongo in the vector index,
+// e.g. after enabling vector search, without generating any
+func runSyncVectors(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("sync-vectors", flag.ExitOnError)
+	flags.Parse(args)
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	needs, volunteers, err := a.matchingService.SyncVectors(context.Background())
+	if errors.Is(err, services.ErrVectorSearchDisabled) {
+		return errors.New("vector search not configured; set PINECONE_API_KEY")
+	}
+	if err != nil {
+		return fmt.Errorf("after %d needs and %d volunteers: %w", needs, volunteers, err)
+	}
+	log.Printf("Synced %d need and %d volunteer vectors", needs, volunteers)
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// runExportMatchDecisions writes recorded match sets, one JSON line per
+// candidate with its features and outcome, for evaluating the scoring model
+// offline. IDs are pseudonymized like analytics events unless --raw-ids is set.
+func runExportMatchDecisions(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("export-match-decisions", flag.ExitOnError)
+	from := flags.String("from", "", "first day to export, YYYY-MM-DD (default 30 days ago)")
+	to := flags.String("to", "", "day to export up to, exclusive, YYYY-MM-DD (default now)")
+	kind := flags.String("kind", "all", "match sets to export: need, volunteer, or all")
+	out := flags.String("out", "", "file to write (default stdout)")
+	rawIDs := flags.Bool("raw-ids", false, "write database IDs instead of pseudonyms")
+	flags.Parse(args)
+
+	filter := services.MatchDecisionFilter{
+		From: time.Now().AddDate(0, 0, -30),
+		To:   time.Now(),
+	}
+	if *from != "" {
+		parsed, err := time.Parse(models.DateLayout, *from)
+		if err != nil {
+			return fmt.Errorf("invalid --from %q", *from)
+		}
+		filter.From = parsed
+	}
+	if *to != "" {
+		parsed, err := time.Parse(models.DateLayout, *to)
+		if err != nil {
+			return fmt.Errorf("invalid --to %q", *to)
+		}
+		filter.To = parsed
+	}
+	if !filter.To.After(filter.From) {
+		return errors.New("--to must be after --from")
+	}
+	switch *kind {
+	case "all":
+	case string(models.MatchDecisionNeed), string(models.MatchDecisionVolunteer):
+		filter.Kind = models.MatchDecisionKind(*kind)
+	default:
+		return fmt.Errorf("unknown --kind %q", *kind)
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	id := func(_ string, id primitive.ObjectID) string { return id.Hex() }
+	if !*rawIDs {
+		if a.analyticsService == nil {
+			return errors.New("pseudonyms need ANALYTICS_PSEUDONYM_KEY; set it or pass --raw-ids")
+		}
+		id = a.analyticsService.Pseudonym
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	written, err := a.matchDecisions.Export(context.Background(), filter, id, func(row models.MatchEvaluationRow) error {
+		return encoder.Encode(row)
+	})
+	if err != nil {
+		return fmt.Errorf("after %d rows: %w", written, err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	log.Printf("Exported %d match candidates from %s to %s", written, filter.From.Format(time.RFC3339), filter.To.Format(time.RFC3339))
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,56 @@
+package background
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Group runs long-lived background jobs and stops them together on shutdown
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewGroup creates an empty job group
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go runs fn in its own goroutine. fn must return promptly once its context
+// is cancelled.
+func (g *Group) Go(name string, fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Background job %s panicked: %v", name, r)
+			}
+		}()
+		fn(g.ctx)
+	}()
+}
+
+// Shutdown cancels all jobs and waits for them to return, giving up when ctx expires
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+} 
\ No newline at end of file
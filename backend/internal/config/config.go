@@ -2,12 +2,26 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	// Server settings
-	Port string
+	RunMode         string // subcommand to run when none is given on the command line
+	Port            string
+	ShutdownTimeout time.Duration // how long to wait for in-flight requests and jobs on shutdown
+	DrainDelay      time.Duration // how long /readyz reports draining before the server stops accepting requests
+
+	// TLS settings; when enabled, HTTPS is served on HTTPSPort and Port only redirects
+	HTTPSPort           string
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string // obtain certificates from Let's Encrypt for these hosts
+	TLSAutocertEmail    string
+	TLSAutocertCacheDir string
 
 	// Database settings
 	MongoURI      string
@@ -19,36 +33,428 @@ type Config struct {
 	JWTSecret string
 
 	// OpenAI settings
-	OpenAIKey string
+	OpenAIKey         string
+	EmbeddingCacheTTL time.Duration // how long embeddings are cached in Redis by the text they were made from; zero turns the cache off
 
 	// Pinecone settings
-	PineconeAPIKey string
+	PineconeAPIKey string // enables vector search for matching; empty scans every profile
 	PineconeIndex  string
+	PineconeHost   string // the index's data plane host; looked up from PineconeIndex when empty
+
+	// Email settings; without an SMTP host or SendGrid API key, email is
+	// written to the log
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string // sends through the SendGrid API instead of SMTP
+	EmailFrom      string // sender address, e.g. NeighborNexus <no-reply@example.org>
+	AppBaseURL     string // web app that notification emails link into; empty leaves links out
+
+	// Push notification settings, for users not connected over WebSocket;
+	// platforms without credentials get no push
+	FCMCredentialsFile string // Firebase service account JSON, for Android and web
+	APNsKeyFile        string // .p8 token signing key, for iOS
+	APNsKeyID          string
+	APNsTeamID         string
+	APNsTopic          string // the iOS app's bundle ID
+	APNsProduction     bool   // use the production APNs environment rather than the sandbox
+
+	// Upload settings. Profile photos and need images are uploaded straight
+	// to an S3-compatible bucket through presigned URLs; uploads are switched
+	// off when no bucket is set.
+	S3Endpoint        string // e.g. http://minio:9000; empty is AWS S3 in S3Region
+	S3Region          string
+	S3Bucket          string // empty disables uploads
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	MaxImageBytes     int64 // largest image a user may upload
+
+	// OAuth sign-in settings; providers without credentials are not offered
+	OAuthRedirectBaseURL string // public scheme and host providers redirect back to, e.g. https://api.example.org
+	OAuthCompleteURL     string // web app page sent the tokens in its URL fragment after signing in; empty responds with JSON
+	GoogleClientID       string
+	GoogleClientSecret   string
+	AppleServicesID      string // the Services ID Sign in with Apple is configured on
+	AppleTeamID          string
+	AppleKeyID           string
+	AppleKeyFile         string // .p8 key signing the client secret
+
+	// Password reset settings
+	PasswordResetURL string        // page reset emails link to with ?token=; empty sends the bare token
+	PasswordResetTTL time.Duration // how long a reset token is valid
+
+	// CORS settings
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+
+	// Security settings
+	SecurityHeaders      bool
+	HSTSMaxAge           time.Duration
+	WebSocketCheckOrigin bool
+
+	// Default rate limits; admins can override them at runtime
+	RateLimitAuthPerMinute int
+	RateLimitAPIPerMinute  int
+
+	// Webhook settings
+	WebhookBaseURL         string // public scheme and host, used by providers that sign the full URL
+	WebhookReplayWindow    time.Duration
+	StripeWebhookSecret    string
+	StripeWebhookTolerance time.Duration
+	PartnerWebhookURLs     []string // partner endpoints notified of events such as account deletion
+	PartnerWebhookSecret   string   // shared secret signing outbound partner webhooks
+	CheckrAPIKey           string
+	TwilioAuthToken        string
+
+	// Background job settings
+	AsyncMatching     bool          // match new needs on the worker instead of in the request
+	DigestInterval    time.Duration // how often volunteers get a digest of matching needs; 0 disables
+	EventReminderLead time.Duration // how long before their shift event volunteers are reminded; 0 disables
+	WorkerConcurrency int           // consumers per queue in each worker process
+	JobMaxAttempts    int           // tries before a failing job is moved to its queue's dead letters
+
+	// Moderation settings
+	ModerationSLA           time.Duration // time allowed to decide on a flagged item
+	ModerationEscalationSLA time.Duration // time allowed once an item is escalated
+	ModerationBlocklist     []string      // extra terms that queue content automatically, beyond the built-in scam rules
+
+	// Urgent need SLA settings, measured from when the need was posted; a
+	// zero target is not tracked
+	UrgentMatchSLA      time.Duration // time allowed for volunteers to be matched and notified
+	UrgentAcceptSLA     time.Duration // time allowed for a volunteer to take the need on
+	UrgentCompleteSLA   time.Duration // time allowed for the need to be met
+	SLAEscalationRadius float64       // stretches the matching distance of needs that miss a target
+
+	// Need boost settings
+	BoostCooldown time.Duration // time a requester waits after posting or boosting a need before boosting it again
+	BoostLimit    int           // boosts allowed per need
+
+	// Spam velocity settings; a zero limit disables that check
+	SpamBurstLimit        int           // needs one user may post within SpamBurstWindow before being throttled
+	SpamBurstWindow       time.Duration // window posting bursts are counted over
+	SpamThrottle          time.Duration // how long a user who bursts is blocked from posting
+	SpamDuplicateAccounts int           // accounts posting the same text before it is held for review
+	SpamAccountsPerIP     int           // accounts registered from one IP before their content is held for review
+	SpamTrackingWindow    time.Duration // how long duplicate text and per-IP accounts are remembered
+
+	// Data export settings
+	ExportRetention time.Duration // how long an assembled data export is kept
+	ExportLinkTTL   time.Duration // how long a signed export download link is valid
+
+	// Match decision log settings
+	MatchDecisionLog       bool          // record every computed match set for offline evaluation
+	MatchDecisionRetention time.Duration // how long recorded match sets are kept; zero keeps them forever
+
+	// Notification inbox settings
+	NotificationRetention time.Duration // how long pushed notifications are kept for users to catch up on; zero keeps them forever
+
+	// Field encryption settings
+	FieldEncryptionKeys []string // base64 32-byte keys wrapping the data keys that seal PII; the first wraps new keys, the rest are kept for rotation
+
+	// Analytics settings. Events are only emitted when a pseudonym key is set.
+	AnalyticsPseudonymKey string // HMAC key that replaces IDs in analytics events; changing it unlinks old events from new ones
+	AnalyticsSinkURL      string // optional external endpoint that also receives every event
+	AnalyticsSinkSecret   string // signs requests to AnalyticsSinkURL
+
+	// Referral settings
+	InviteLinkBaseURL       string // sign-up page that invite links point to; empty gives codes only
+	ReferralBadgeThresholds []int  // referred sign-ups that earn a referrer badge, ascending; empty disables badges
+
+	// Feedback settings
+	FeedbackRevealWindow  time.Duration // how long feedback stays hidden waiting for the other participant's
+	TaskConfirmWindow     time.Duration // how long a task marked completed by one participant waits on the other's confirmation
+	FeedbackRequiredAfter time.Duration // users with feedback owed longer than this after a task completed cannot accept needs; zero disables
+	KudosBadgeThresholds  []int         // kudos received that earn a kudos badge, ascending; empty disables badges
+	IncidentSuspendAt     string        // incident severity (minor, serious, critical) that suspends the reported user from matching pending review; off disables
+
+	// Points settings
+	PointsDailyCap int // most points a volunteer can earn per UTC day; zero is uncapped
+
+	// Contribution settings. Needs can only ask for material costs, paid
+	// through Stripe Checkout, when a Stripe secret key is set.
+	StripeSecretKey       string // Stripe API key; empty disables contributions
+	ContributionCurrency  string // ISO currency code for material costs, e.g. usd
+	ContributionReturnURL string // page payers return to after checkout
 
-	// Environment
+	// Location privacy settings. Stored H3 cells, and the approximate
+	// locations shown to other users, are at the block or neighborhood
+	// resolution depending on each user's chosen precision.
+	LocationPrecision              string // precision for users who have not chosen one: block or neighborhood
+	LocationBlockResolution        int    // H3 resolution for "share my block"
+	LocationNeighborhoodResolution int    // H3 resolution for "share my neighborhood"
+
+	// Runtime settings
+	SettingsCacheTTL time.Duration // how stale an instance's view of admin-tuned settings may be
+
+	// Request limits
+	MaxRequestBodyBytes int64
+	MaxUploadBytes      int64         // multipart uploads such as voice notes; Whisper accepts up to 25 MB
+	MaxInFlightRequests int           // requests served at once per instance; 0 disables load shedding
+	MaxQueuedRequests   int           // requests waiting for a slot; beyond this they get 503
+	RequestQueueTimeout time.Duration // how long a queued request waits before it gets 503
+
+	// Telemetry settings
+	TracingEnabled   bool    // export spans over OTLP/HTTP to the collector at OTEL_EXPORTER_OTLP_ENDPOINT
+	TraceSampleRatio float64 // share of new traces sampled, 0-1; traces callers propagate keep their decision
+	MetricsToken     string  // bearer token /metrics requires; empty serves it to anyone who can reach it
+
+	// API versioning settings
+	APIV1Sunset string // RFC 3339 date after which /api/v1 is retired; empty means not deprecated
+
+	// Environment and the profile-driven logging settings
 	Environment string
+	GinMode     string
+	LogLevel    string
 }
 
 // Load loads configuration from environment variables
 func Load() *Config {
+	environment := getEnv("ENVIRONMENT", EnvDevelopment)
+	profile := profileFor(environment)
+
 	return &Config{
-		Port:           getEnv("PORT", "8080"),
-		MongoURI:       getEnv("MONGO_URI", "mongodb://localhost:27017"),
-		RedisAddr:      getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPassword:  getEnv("REDIS_PASSWORD", ""),
-		RedisDB:        0, // Default Redis database
-		JWTSecret:      getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-		OpenAIKey:      getEnv("OPENAI_API_KEY", ""),
-		PineconeAPIKey: getEnv("PINECONE_API_KEY", ""),
-		PineconeIndex:  getEnv("PINECONE_INDEX", "neighborenexus"),
-		Environment:    getEnv("ENVIRONMENT", "development"),
+		Port:              getEnv("PORT", "8080"),
+		MongoURI:          getEnv("MONGO_URI", "mongodb://localhost:27017"),
+		RedisAddr:         getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword:     getEnv("REDIS_PASSWORD", ""),
+		RedisDB:           0, // Default Redis database
+		JWTSecret:         getEnv("JWT_SECRET", insecureJWTSecret),
+		OpenAIKey:         getEnv("OPENAI_API_KEY", ""),
+		EmbeddingCacheTTL: getEnvDuration("EMBEDDING_CACHE_TTL", 30*24*time.Hour),
+		PineconeAPIKey:    getEnv("PINECONE_API_KEY", ""),
+		PineconeIndex:     getEnv("PINECONE_INDEX", "neighborenexus"),
+		PineconeHost:      getEnv("PINECONE_HOST", ""),
+		Environment:       environment,
+
+		GinMode:  getEnv("GIN_MODE", profile.GinMode),
+		LogLevel: getEnv("LOG_LEVEL", profile.LogLevel),
+
+		RunMode:         getEnv("RUN_MODE", "serve"),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		DrainDelay:      getEnvDuration("SHUTDOWN_DRAIN_DELAY", 0),
+
+		SMTPHost:       getEnv("SMTP_HOST", ""),
+		SMTPPort:       int(getEnvInt64("SMTP_PORT", 587)),
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+		EmailFrom:      getEnv("EMAIL_FROM", ""),
+		AppBaseURL:     getEnv("APP_BASE_URL", ""),
+
+		FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+		APNsKeyFile:        getEnv("APNS_KEY_FILE", ""),
+		APNsKeyID:          getEnv("APNS_KEY_ID", ""),
+		APNsTeamID:         getEnv("APNS_TEAM_ID", ""),
+		APNsTopic:          getEnv("APNS_TOPIC", ""),
+		APNsProduction:     getEnvBool("APNS_PRODUCTION", environment == EnvProduction),
+
+		S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		MaxImageBytes:     getEnvInt64("MAX_IMAGE_BYTES", 5<<20),
+
+		OAuthRedirectBaseURL: getEnv("OAUTH_REDIRECT_BASE_URL", ""),
+		OAuthCompleteURL:     getEnv("OAUTH_COMPLETE_URL", ""),
+		GoogleClientID:       getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:   getEnv("GOOGLE_CLIENT_SECRET", ""),
+		AppleServicesID:      getEnv("APPLE_SERVICES_ID", ""),
+		AppleTeamID:          getEnv("APPLE_TEAM_ID", ""),
+		AppleKeyID:           getEnv("APPLE_KEY_ID", ""),
+		AppleKeyFile:         getEnv("APPLE_KEY_FILE", ""),
+
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", ""),
+		PasswordResetTTL: getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+
+		HTTPSPort:           getEnv("HTTPS_PORT", "443"),
+		TLSCertFile:         getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:          getEnv("TLS_KEY_FILE", ""),
+		TLSAutocertDomains:  getEnvList("TLS_AUTOCERT_DOMAINS", nil),
+		TLSAutocertEmail:    getEnv("TLS_AUTOCERT_EMAIL", ""),
+		TLSAutocertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "certs"),
+
+		CORSAllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS", profile.CORSAllowedOrigins),
+		CORSAllowedMethods:   getEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		CORSAllowedHeaders:   getEnvList("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
+
+		SecurityHeaders:      getEnvBool("SECURITY_HEADERS", profile.SecurityHeaders),
+		HSTSMaxAge:           getEnvDuration("HSTS_MAX_AGE", profile.HSTSMaxAge),
+		WebSocketCheckOrigin: getEnvBool("WEBSOCKET_CHECK_ORIGIN", profile.WebSocketCheckOrigin),
+
+		RateLimitAuthPerMinute: int(getEnvInt64("RATE_LIMIT_AUTH_PER_MINUTE", int64(profile.RateLimitAuthPerMinute))),
+		RateLimitAPIPerMinute:  int(getEnvInt64("RATE_LIMIT_API_PER_MINUTE", int64(profile.RateLimitAPIPerMinute))),
+
+		TracingEnabled:   getEnvBool("TRACING_ENABLED", false),
+		TraceSampleRatio: getEnvFloat("TRACE_SAMPLE_RATIO", 0.1),
+		MetricsToken:     getEnv("METRICS_TOKEN", ""),
+
+		APIV1Sunset: getEnv("API_V1_SUNSET", ""),
+
+		MaxRequestBodyBytes: getEnvInt64("MAX_REQUEST_BODY_BYTES", 1<<20),
+		MaxUploadBytes:      getEnvInt64("MAX_UPLOAD_BYTES", 10<<20),
+		MaxInFlightRequests: int(getEnvInt64("MAX_IN_FLIGHT_REQUESTS", int64(profile.MaxInFlightRequests))),
+		MaxQueuedRequests:   int(getEnvInt64("MAX_QUEUED_REQUESTS", int64(profile.MaxQueuedRequests))),
+		RequestQueueTimeout: getEnvDuration("REQUEST_QUEUE_TIMEOUT", 2*time.Second),
+
+		SettingsCacheTTL: getEnvDuration("SETTINGS_CACHE_TTL", 10*time.Second),
+
+		AsyncMatching:     getEnvBool("ASYNC_MATCHING", false),
+		DigestInterval:    getEnvDuration("DIGEST_INTERVAL", 24*time.Hour),
+		EventReminderLead: getEnvDuration("EVENT_REMINDER_LEAD", 24*time.Hour),
+		WorkerConcurrency: int(getEnvInt64("WORKER_CONCURRENCY", 2)),
+		JobMaxAttempts:    int(getEnvInt64("JOB_MAX_ATTEMPTS", 3)),
+
+		ModerationSLA:           getEnvDuration("MODERATION_SLA", 24*time.Hour),
+		ModerationEscalationSLA: getEnvDuration("MODERATION_ESCALATION_SLA", 4*time.Hour),
+		ModerationBlocklist:     getEnvList("MODERATION_BLOCKLIST", nil),
+
+		UrgentMatchSLA:      getEnvDuration("URGENT_MATCH_SLA", 15*time.Minute),
+		UrgentAcceptSLA:     getEnvDuration("URGENT_ACCEPT_SLA", time.Hour),
+		UrgentCompleteSLA:   getEnvDuration("URGENT_COMPLETE_SLA", 24*time.Hour),
+		SLAEscalationRadius: getEnvFloat("SLA_ESCALATION_RADIUS", 2),
+
+		BoostCooldown: getEnvDuration("BOOST_COOLDOWN", time.Hour),
+		BoostLimit:    int(getEnvInt64("BOOST_LIMIT", 3)),
+
+		SpamBurstLimit:        int(getEnvInt64("SPAM_BURST_LIMIT", 15)),
+		SpamBurstWindow:       getEnvDuration("SPAM_BURST_WINDOW", 10*time.Minute),
+		SpamThrottle:          getEnvDuration("SPAM_THROTTLE", time.Hour),
+		SpamDuplicateAccounts: int(getEnvInt64("SPAM_DUPLICATE_ACCOUNTS", 3)),
+		SpamAccountsPerIP:     int(getEnvInt64("SPAM_ACCOUNTS_PER_IP", 5)),
+		SpamTrackingWindow:    getEnvDuration("SPAM_TRACKING_WINDOW", 24*time.Hour),
+
+		ExportRetention: getEnvDuration("EXPORT_RETENTION", 7*24*time.Hour),
+		ExportLinkTTL:   getEnvDuration("EXPORT_LINK_TTL", 15*time.Minute),
+
+		MatchDecisionLog:       getEnvBool("MATCH_DECISION_LOG", true),
+		MatchDecisionRetention: getEnvDuration("MATCH_DECISION_RETENTION", 90*24*time.Hour),
+
+		NotificationRetention: getEnvDuration("NOTIFICATION_RETENTION", 90*24*time.Hour),
+
+		FieldEncryptionKeys: getEnvList("FIELD_ENCRYPTION_KEYS", nil),
+
+		AnalyticsPseudonymKey: getEnv("ANALYTICS_PSEUDONYM_KEY", ""),
+		AnalyticsSinkURL:      getEnv("ANALYTICS_SINK_URL", ""),
+		AnalyticsSinkSecret:   getEnv("ANALYTICS_SINK_SECRET", ""),
+
+		InviteLinkBaseURL:       getEnv("INVITE_LINK_BASE_URL", ""),
+		ReferralBadgeThresholds: getEnvIntList("REFERRAL_BADGE_THRESHOLDS", nil),
+
+		FeedbackRevealWindow:  getEnvDuration("FEEDBACK_REVEAL_WINDOW", 14*24*time.Hour),
+		TaskConfirmWindow:     getEnvDuration("TASK_CONFIRM_WINDOW", 72*time.Hour),
+		FeedbackRequiredAfter: getEnvDuration("FEEDBACK_REQUIRED_AFTER", 0),
+		KudosBadgeThresholds:  getEnvIntList("KUDOS_BADGE_THRESHOLDS", nil),
+		IncidentSuspendAt:     getEnv("INCIDENT_SUSPEND_SEVERITY", "critical"),
+
+		PointsDailyCap: int(getEnvInt64("POINTS_DAILY_CAP", 100)),
+
+		StripeSecretKey:       getEnv("STRIPE_SECRET_KEY", ""),
+		ContributionCurrency:  getEnv("CONTRIBUTION_CURRENCY", "usd"),
+		ContributionReturnURL: getEnv("CONTRIBUTION_RETURN_URL", ""),
+
+		LocationPrecision:              getEnv("LOCATION_PRECISION", "neighborhood"),
+		LocationBlockResolution:        int(getEnvInt64("LOCATION_BLOCK_RESOLUTION", 9)),
+		LocationNeighborhoodResolution: int(getEnvInt64("LOCATION_NEIGHBORHOOD_RESOLUTION", 7)),
+
+		WebhookBaseURL:         getEnv("WEBHOOK_BASE_URL", ""),
+		WebhookReplayWindow:    getEnvDuration("WEBHOOK_REPLAY_WINDOW", 72*time.Hour),
+		StripeWebhookSecret:    getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		StripeWebhookTolerance: getEnvDuration("STRIPE_WEBHOOK_TOLERANCE", 5*time.Minute),
+		PartnerWebhookURLs:     getEnvList("PARTNER_WEBHOOK_URLS", nil),
+		PartnerWebhookSecret:   getEnv("PARTNER_WEBHOOK_SECRET", ""),
+		CheckrAPIKey:           getEnv("CHECKR_API_KEY", ""),
+		TwilioAuthToken:        getEnv("TWILIO_AUTH_TOKEN", ""),
 	}
 }
 
+// TLSEnabled reports whether the server terminates TLS itself
+func (c *Config) TLSEnabled() bool {
+	return len(c.TLSAutocertDomains) > 0 || c.TLSCertFile != ""
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable as a slice or returns a default value
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
+// getEnvIntList gets a comma-separated list of integers or returns a default
+// value. Entries that are not integers read as 0, which validation rejects.
+func getEnvIntList(key string, defaultValue []int) []int {
+	items := getEnvList(key, nil)
+	if items == nil {
+		return defaultValue
+	}
+
+	values := make([]int, len(items))
+	for i, item := range items {
+		values[i], _ = strconv.Atoi(item)
+	}
+	return values
+}
+
+// getEnvBool gets a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt64 gets an integer environment variable or returns a default value
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets a decimal environment variable or returns a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30s", "5m") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
 } 
\ No newline at end of file
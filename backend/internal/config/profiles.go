@@ -0,0 +1,67 @@
+package config
+
+import "time"
+
+// Profile bundles the defaults for one environment. Any value can still be
+// overridden by its environment variable.
+type Profile struct {
+	GinMode  string // debug, release, or test
+	LogLevel string // debug, info, warn, or error
+
+	CORSAllowedOrigins []string
+
+	RateLimitAuthPerMinute int
+	RateLimitAPIPerMinute  int
+
+	MaxInFlightRequests int // requests served at once per instance; 0 disables load shedding
+	MaxQueuedRequests   int // requests waiting for a slot before new ones are refused
+
+	SecurityHeaders      bool
+	HSTSMaxAge           time.Duration // 0 omits Strict-Transport-Security
+	WebSocketCheckOrigin bool          // require WebSocket origins to be in CORSAllowedOrigins
+}
+
+// profiles are the presets selected by ENVIRONMENT
+var profiles = map[string]Profile{
+	EnvDevelopment: {
+		GinMode:            "debug",
+		LogLevel:           "debug",
+		CORSAllowedOrigins: []string{"http://localhost:3000", "http://localhost:5173"},
+		SecurityHeaders:    true,
+	},
+	EnvTest: {
+		GinMode:  "test",
+		LogLevel: "warn",
+	},
+	EnvStaging: {
+		GinMode:                "release",
+		LogLevel:               "info",
+		RateLimitAuthPerMinute: 60,
+		RateLimitAPIPerMinute:  600,
+		MaxInFlightRequests:    256,
+		MaxQueuedRequests:      512,
+		SecurityHeaders:        true,
+		HSTSMaxAge:             24 * time.Hour,
+		WebSocketCheckOrigin:   true,
+	},
+	EnvProduction: {
+		GinMode:                "release",
+		LogLevel:               "info",
+		RateLimitAuthPerMinute: 20,
+		RateLimitAPIPerMinute:  300,
+		MaxInFlightRequests:    512,
+		MaxQueuedRequests:      1024,
+		SecurityHeaders:        true,
+		HSTSMaxAge:             365 * 24 * time.Hour,
+		WebSocketCheckOrigin:   true,
+	},
+}
+
+// profileFor returns the preset for an environment, falling back to
+// development so Validate can report the unknown name
+func profileFor(environment string) Profile {
+	if profile, ok := profiles[environment]; ok {
+		return profile
+	}
+	return profiles[EnvDevelopment]
+} 
\ No newline at end of file
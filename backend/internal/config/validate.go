@@ -0,0 +1,393 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// insecureJWTSecret is the development fallback for JWT_SECRET
+const insecureJWTSecret = "your-secret-key-change-in-production"
+
+// minProductionSecretLength is the shortest JWT secret accepted in production
+const minProductionSecretLength = 32
+
+// Environments
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// ValidationError lists every configuration problem found at startup
+type ValidationError struct {
+	Problems []string
+}
+
+// Error formats the problems one per line
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks required values for the configured environment and
+// returns a *ValidationError describing how to fix each problem
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Environment {
+	case EnvDevelopment, EnvTest, EnvStaging, EnvProduction:
+	default:
+		add("ENVIRONMENT %q is not recognized; use development, test, staging, or production", c.Environment)
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT %q must be a number between 1 and 65535", c.Port)
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		add("SHUTDOWN_TIMEOUT must be a positive duration, e.g. 30s")
+	}
+	if c.DrainDelay < 0 || c.DrainDelay >= c.ShutdownTimeout {
+		add("SHUTDOWN_DRAIN_DELAY must not be negative and must be shorter than SHUTDOWN_TIMEOUT")
+	}
+
+	if c.TLSEnabled() {
+		if port, err := strconv.Atoi(c.HTTPSPort); err != nil || port < 1 || port > 65535 {
+			add("HTTPS_PORT %q must be a number between 1 and 65535", c.HTTPSPort)
+		} else if c.HTTPSPort == c.Port {
+			add("HTTPS_PORT must differ from PORT, which serves HTTP redirects when TLS is enabled")
+		}
+	}
+	switch {
+	case len(c.TLSAutocertDomains) > 0 && c.TLSCertFile != "":
+		add("set either TLS_AUTOCERT_DOMAINS or TLS_CERT_FILE/TLS_KEY_FILE, not both")
+	case (c.TLSCertFile == "") != (c.TLSKeyFile == ""):
+		add("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	case len(c.TLSAutocertDomains) > 0 && c.TLSAutocertCacheDir == "":
+		add("TLS_AUTOCERT_CACHE_DIR is required with TLS_AUTOCERT_DOMAINS so certificates survive restarts")
+	}
+
+	if c.DigestInterval < 0 || (c.DigestInterval > 0 && c.DigestInterval < time.Minute) {
+		add("DIGEST_INTERVAL must be at least 1m, or 0 to disable digests")
+	}
+
+	if c.EventReminderLead < 0 {
+		add("EVENT_REMINDER_LEAD cannot be negative; use 0 to disable event reminders")
+	}
+
+	if c.WorkerConcurrency < 1 || c.WorkerConcurrency > 32 {
+		add("WORKER_CONCURRENCY must be between 1 and 32")
+	}
+	if c.JobMaxAttempts < 1 {
+		add("JOB_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if c.ModerationSLA <= 0 || c.ModerationEscalationSLA <= 0 {
+		add("MODERATION_SLA and MODERATION_ESCALATION_SLA must be positive durations, e.g. 24h")
+	}
+
+	if c.UrgentMatchSLA < 0 || c.UrgentAcceptSLA < 0 || c.UrgentCompleteSLA < 0 {
+		add("URGENT_MATCH_SLA, URGENT_ACCEPT_SLA, and URGENT_COMPLETE_SLA cannot be negative; use 0 to stop tracking a target")
+	}
+
+	if c.SLAEscalationRadius < 1 || c.SLAEscalationRadius > 10 {
+		add("SLA_ESCALATION_RADIUS must be between 1 and 10")
+	}
+
+	if c.BoostCooldown < time.Minute {
+		add("BOOST_COOLDOWN must be at least 1m")
+	}
+
+	if c.BoostLimit < 0 {
+		add("BOOST_LIMIT cannot be negative; use 0 to disable boosts")
+	}
+
+	if c.MongoURI == "" {
+		add("MONGO_URI is required, e.g. mongodb://localhost:27017")
+	} else if !strings.HasPrefix(c.MongoURI, "mongodb://") && !strings.HasPrefix(c.MongoURI, "mongodb+srv://") {
+		add("MONGO_URI must start with mongodb:// or mongodb+srv://")
+	}
+
+	if c.RedisAddr == "" {
+		add("REDIS_ADDR is required, e.g. localhost:6379")
+	}
+
+	switch {
+	case c.JWTSecret == "":
+		add("JWT_SECRET is required")
+	case c.Environment == EnvProduction || c.Environment == EnvStaging:
+		if c.JWTSecret == insecureJWTSecret {
+			add("JWT_SECRET is set to the development default; generate a random secret (e.g. openssl rand -base64 48)")
+		} else if len(c.JWTSecret) < minProductionSecretLength {
+			add("JWT_SECRET must be at least %d characters in %s", minProductionSecretLength, c.Environment)
+		}
+	}
+
+	if c.MaxRequestBodyBytes <= 0 {
+		add("MAX_REQUEST_BODY_BYTES must be a positive number of bytes")
+	}
+	if c.MaxUploadBytes <= 0 || c.MaxUploadBytes > 25<<20 {
+		add("MAX_UPLOAD_BYTES must be a positive number of bytes, at most 26214400 (25 MB)")
+	}
+
+	if c.MaxInFlightRequests < 0 || c.MaxQueuedRequests < 0 {
+		add("MAX_IN_FLIGHT_REQUESTS and MAX_QUEUED_REQUESTS cannot be negative")
+	}
+	if c.MaxInFlightRequests > 0 && c.MaxQueuedRequests > 0 && c.RequestQueueTimeout <= 0 {
+		add("REQUEST_QUEUE_TIMEOUT must be a positive duration, e.g. 2s, when requests are queued")
+	}
+
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		add("TRACE_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	if c.APIV1Sunset != "" {
+		if _, err := time.Parse(time.RFC3339, c.APIV1Sunset); err != nil {
+			add("API_V1_SUNSET %q must be an RFC 3339 date, e.g. 2025-01-31T00:00:00Z", c.APIV1Sunset)
+		}
+	}
+
+	switch c.GinMode {
+	case "debug", "release", "test":
+	default:
+		add("GIN_MODE %q must be debug, release, or test", c.GinMode)
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		add("LOG_LEVEL %q must be debug, info, warn, or error", c.LogLevel)
+	}
+
+	if c.RateLimitAuthPerMinute < 0 || c.RateLimitAPIPerMinute < 0 {
+		add("RATE_LIMIT_AUTH_PER_MINUTE and RATE_LIMIT_API_PER_MINUTE cannot be negative")
+	}
+
+	if (c.Environment == EnvProduction || c.Environment == EnvStaging) && len(c.CORSAllowedOrigins) == 0 {
+		add("CORS_ALLOWED_ORIGINS is required in %s, e.g. https://app.example.org", c.Environment)
+	}
+
+	if c.CORSAllowCredentials {
+		for _, origin := range c.CORSAllowedOrigins {
+			if origin == "*" {
+				add("CORS_ALLOWED_ORIGINS cannot contain * when CORS_ALLOW_CREDENTIALS is true; list the allowed origins")
+				break
+			}
+		}
+	}
+
+	if c.TwilioAuthToken != "" {
+		if u, err := url.Parse(c.WebhookBaseURL); c.WebhookBaseURL == "" || err != nil || u.Scheme == "" || u.Host == "" {
+			add("WEBHOOK_BASE_URL must be the public URL of this server (e.g. https://api.example.org) when TWILIO_AUTH_TOKEN is set")
+		}
+	}
+
+	if c.SpamBurstLimit < 0 || c.SpamDuplicateAccounts < 0 || c.SpamAccountsPerIP < 0 {
+		add("SPAM_BURST_LIMIT, SPAM_DUPLICATE_ACCOUNTS, and SPAM_ACCOUNTS_PER_IP cannot be negative")
+	}
+	if c.SpamBurstLimit > 0 && c.SpamBurstWindow <= 0 {
+		add("SPAM_BURST_WINDOW must be positive when SPAM_BURST_LIMIT is set")
+	}
+	if c.SpamTrackingWindow <= 0 && (c.SpamDuplicateAccounts > 0 || c.SpamAccountsPerIP > 0) {
+		add("SPAM_TRACKING_WINDOW must be positive when duplicate or per-IP spam checks are enabled")
+	}
+
+	if len(c.PartnerWebhookURLs) > 0 && c.PartnerWebhookSecret == "" {
+		add("PARTNER_WEBHOOK_SECRET is required when PARTNER_WEBHOOK_URLS is set")
+	}
+	for _, partnerURL := range c.PartnerWebhookURLs {
+		if u, err := url.Parse(partnerURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("PARTNER_WEBHOOK_URLS entry %q must be an absolute URL", partnerURL)
+		}
+	}
+
+	if c.ExportRetention <= 0 || c.ExportLinkTTL <= 0 {
+		add("EXPORT_RETENTION and EXPORT_LINK_TTL must be positive durations, e.g. 168h and 15m")
+	}
+	if c.MatchDecisionRetention < 0 {
+		add("MATCH_DECISION_RETENTION must not be negative")
+	}
+	if c.NotificationRetention < 0 {
+		add("NOTIFICATION_RETENTION must not be negative")
+	}
+	if c.EmbeddingCacheTTL < 0 {
+		add("EMBEDDING_CACHE_TTL must not be negative")
+	}
+
+	for i, key := range c.FieldEncryptionKeys {
+		if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 32 {
+			add("FIELD_ENCRYPTION_KEYS entry %d must be 32 random bytes, base64-encoded (e.g. openssl rand -base64 32)", i+1)
+		}
+	}
+	if c.Environment == EnvProduction && len(c.FieldEncryptionKeys) == 0 {
+		add("FIELD_ENCRYPTION_KEYS is required in production to encrypt phone numbers and locations at rest")
+	}
+
+	if c.AnalyticsPseudonymKey != "" && len(c.AnalyticsPseudonymKey) < minProductionSecretLength {
+		add("ANALYTICS_PSEUDONYM_KEY must be at least %d characters so pseudonyms cannot be reversed by guessing IDs", minProductionSecretLength)
+	}
+	if c.AnalyticsSinkURL != "" {
+		if u, err := url.Parse(c.AnalyticsSinkURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("ANALYTICS_SINK_URL %q must be an absolute URL", c.AnalyticsSinkURL)
+		}
+		if c.AnalyticsSinkSecret == "" {
+			add("ANALYTICS_SINK_SECRET is required when ANALYTICS_SINK_URL is set")
+		}
+		if c.AnalyticsPseudonymKey == "" {
+			add("ANALYTICS_PSEUDONYM_KEY is required when ANALYTICS_SINK_URL is set, since no events are emitted without it")
+		}
+	}
+
+	switch {
+	case c.SMTPHost != "" && c.SendGridAPIKey != "":
+		add("set either SMTP_HOST or SENDGRID_API_KEY, not both")
+	case c.SMTPHost != "":
+		if c.SMTPPort < 1 || c.SMTPPort > 65535 {
+			add("SMTP_PORT %d must be a number between 1 and 65535", c.SMTPPort)
+		}
+		if c.EmailFrom == "" {
+			add("EMAIL_FROM is required when SMTP_HOST is set, e.g. no-reply@example.org")
+		}
+		if (c.SMTPUsername == "") != (c.SMTPPassword == "") {
+			add("SMTP_USERNAME and SMTP_PASSWORD must be set together")
+		}
+	case c.SendGridAPIKey != "":
+		if c.EmailFrom == "" {
+			add("EMAIL_FROM is required when SENDGRID_API_KEY is set, e.g. no-reply@example.org")
+		}
+	case c.Environment == EnvProduction || c.Environment == EnvStaging:
+		add("SMTP_HOST or SENDGRID_API_KEY is required in %s to send password reset and notification email", c.Environment)
+	}
+	if c.AppBaseURL != "" {
+		if u, err := url.Parse(c.AppBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("APP_BASE_URL must be an absolute URL, e.g. https://app.example.org")
+		}
+	}
+	apnsSet := 0
+	for _, value := range []string{c.APNsKeyFile, c.APNsKeyID, c.APNsTeamID, c.APNsTopic} {
+		if value != "" {
+			apnsSet++
+		}
+	}
+	if apnsSet > 0 && apnsSet < 4 {
+		add("APNS_KEY_FILE, APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC must be set together")
+	}
+
+	if c.S3Bucket != "" {
+		if c.S3AccessKeyID == "" || c.S3SecretAccessKey == "" {
+			add("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when S3_BUCKET is set")
+		}
+		if c.S3Region == "" {
+			add("S3_REGION is required when S3_BUCKET is set")
+		}
+		if u, err := url.Parse(c.S3Endpoint); c.S3Endpoint != "" && (err != nil || u.Scheme == "" || u.Host == "") {
+			add("S3_ENDPOINT must be the absolute URL of the object store (e.g. http://minio:9000), or empty for AWS S3")
+		}
+	}
+	if c.MaxImageBytes <= 0 || c.MaxImageBytes > 25<<20 {
+		add("MAX_IMAGE_BYTES must be a positive number of bytes, at most 26214400 (25 MB)")
+	}
+
+	if (c.GoogleClientID == "") != (c.GoogleClientSecret == "") {
+		add("GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET must be set together")
+	}
+	appleSet := 0
+	for _, value := range []string{c.AppleServicesID, c.AppleTeamID, c.AppleKeyID, c.AppleKeyFile} {
+		if value != "" {
+			appleSet++
+		}
+	}
+	if appleSet > 0 && appleSet < 4 {
+		add("APPLE_SERVICES_ID, APPLE_TEAM_ID, APPLE_KEY_ID and APPLE_KEY_FILE must be set together")
+	}
+	if c.GoogleClientID != "" || appleSet > 0 {
+		if u, err := url.Parse(c.OAuthRedirectBaseURL); c.OAuthRedirectBaseURL == "" || err != nil || u.Scheme == "" || u.Host == "" {
+			add("OAUTH_REDIRECT_BASE_URL must be the public URL of this server (e.g. https://api.example.org) when Google or Apple sign-in is set up")
+		}
+	}
+	if c.OAuthCompleteURL != "" {
+		if u, err := url.Parse(c.OAuthCompleteURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("OAUTH_COMPLETE_URL must be an absolute URL, e.g. https://app.example.org/signed-in")
+		}
+	}
+
+	if c.PasswordResetTTL < 5*time.Minute || c.PasswordResetTTL > 24*time.Hour {
+		add("PASSWORD_RESET_TTL must be between 5m and 24h")
+	}
+	if c.PasswordResetURL != "" {
+		if u, err := url.Parse(c.PasswordResetURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("PASSWORD_RESET_URL must be an absolute URL, e.g. https://app.example.org/reset-password")
+		}
+	}
+
+	if c.InviteLinkBaseURL != "" {
+		if u, err := url.Parse(c.InviteLinkBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("INVITE_LINK_BASE_URL must be an absolute URL, e.g. https://app.example.org/join")
+		}
+	}
+	for i, threshold := range c.ReferralBadgeThresholds {
+		if threshold <= 0 || (i > 0 && threshold <= c.ReferralBadgeThresholds[i-1]) {
+			add("REFERRAL_BADGE_THRESHOLDS must be positive whole numbers in ascending order, e.g. 1,5,25")
+			break
+		}
+	}
+	for i, threshold := range c.KudosBadgeThresholds {
+		if threshold <= 0 || (i > 0 && threshold <= c.KudosBadgeThresholds[i-1]) {
+			add("KUDOS_BADGE_THRESHOLDS must be positive whole numbers in ascending order, e.g. 1,10,50")
+			break
+		}
+	}
+	if c.PointsDailyCap < 0 {
+		add("POINTS_DAILY_CAP must not be negative")
+	}
+	if c.FeedbackRequiredAfter < 0 {
+		add("FEEDBACK_REQUIRED_AFTER must not be negative")
+	}
+	if c.TaskConfirmWindow <= 0 {
+		add("TASK_CONFIRM_WINDOW must be positive")
+	}
+	switch c.IncidentSuspendAt {
+	case "minor", "serious", "critical", "off":
+	default:
+		add("INCIDENT_SUSPEND_SEVERITY %q must be minor, serious, critical, or off", c.IncidentSuspendAt)
+	}
+
+	if c.StripeSecretKey != "" {
+		if c.StripeWebhookSecret == "" {
+			add("STRIPE_WEBHOOK_SECRET is required when STRIPE_SECRET_KEY is set, since contributions are confirmed by webhook")
+		}
+		if u, err := url.Parse(c.ContributionReturnURL); c.ContributionReturnURL == "" || err != nil || u.Scheme == "" || u.Host == "" {
+			add("CONTRIBUTION_RETURN_URL must be the absolute URL payers return to after checkout (e.g. https://app.example.org/contributions) when STRIPE_SECRET_KEY is set")
+		}
+		if len(c.ContributionCurrency) != 3 {
+			add("CONTRIBUTION_CURRENCY %q must be a three-letter ISO currency code, e.g. usd", c.ContributionCurrency)
+		}
+	}
+
+	switch c.LocationPrecision {
+	case "block", "neighborhood":
+	default:
+		add("LOCATION_PRECISION %q must be block or neighborhood", c.LocationPrecision)
+	}
+	if c.LocationBlockResolution < 0 || c.LocationBlockResolution > 15 || c.LocationNeighborhoodResolution < 0 || c.LocationNeighborhoodResolution > 15 {
+		add("LOCATION_BLOCK_RESOLUTION and LOCATION_NEIGHBORHOOD_RESOLUTION must be H3 resolutions between 0 and 15")
+	} else if c.LocationNeighborhoodResolution >= c.LocationBlockResolution {
+		add("LOCATION_NEIGHBORHOOD_RESOLUTION must be coarser (lower) than LOCATION_BLOCK_RESOLUTION")
+	}
+
+	if c.Environment == EnvProduction && c.OpenAIKey == "" {
+		add("OPENAI_API_KEY is required in production for matching")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+} 
\ No newline at end of file
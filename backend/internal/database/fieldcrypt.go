@@ -0,0 +1,452 @@
+package database
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"neighborenexus/internal/models"
+)
+
+// sealedSubtype is the user-defined BSON binary subtype holding a sealed value
+const sealedSubtype byte = 0x8e
+
+// sealedVersion is the format version leading every sealed value
+const sealedVersion byte = 1
+
+// dataKeyLifetime is how long one data key seals new values before a fresh
+// one is generated, bounding how much data any single data key protects
+const dataKeyLifetime = time.Hour
+
+// sealedLocationFields are the location fields sealed at rest. The H3 index
+// stays readable because neighborhoods and targeting query by it.
+var sealedLocationFields = []string{"latitude", "longitude", "address"}
+
+// ErrUnsealFailed is returned when a sealed value cannot be decrypted, usually
+// because the key that sealed it is no longer configured
+var ErrUnsealFailed = errors.New("failed to unseal field")
+
+// KeyWrapper encrypts and decrypts data keys with a key-encryption key held
+// outside the database, such as a key from config or one in a KMS
+type KeyWrapper interface {
+	// Wrap encrypts a data key, returning the ID of the key that wrapped it
+	Wrap(dataKey []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap decrypts a data key wrapped by the key with keyID
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyWrapper wraps data keys with AES-256-GCM keys from config
+type LocalKeyWrapper struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewLocalKeyWrapper creates a key wrapper from base64-encoded 32-byte keys.
+// The first key wraps new data keys; the rest only unwrap existing ones, so
+// keys can be rotated by prepending a new one.
+func NewLocalKeyWrapper(encodedKeys []string) (*LocalKeyWrapper, error) {
+	if len(encodedKeys) == 0 {
+		return nil, errors.New("no field encryption keys configured")
+	}
+
+	w := &LocalKeyWrapper{keys: map[string]cipher.AEAD{}}
+	for i, encoded := range encodedKeys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("field encryption key %d must be 32 bytes, base64-encoded", i+1)
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(key)
+		keyID := hex.EncodeToString(sum[:4])
+		w.keys[keyID] = aead
+		if i == 0 {
+			w.primary = keyID
+		}
+	}
+	return w, nil
+}
+
+// Wrap encrypts a data key with the primary key
+func (w *LocalKeyWrapper) Wrap(dataKey []byte) (string, []byte, error) {
+	wrapped, err := gcmSeal(w.keys[w.primary], dataKey)
+	return w.primary, wrapped, err
+}
+
+// Unwrap decrypts a data key with the key that wrapped it
+func (w *LocalKeyWrapper) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := w.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %s", ErrUnsealFailed, keyID)
+	}
+	return gcmOpen(aead, wrapped)
+}
+
+// dataKey is a data key together with its wrapped form, which is stored
+// alongside every value it seals
+type dataKey struct {
+	aead      cipher.AEAD
+	keyID     string
+	wrapped   []byte
+	createdAt time.Time
+}
+
+// FieldCipher seals individual BSON values with envelope encryption: each
+// value is encrypted with a data key, and the data key, wrapped by the
+// KeyWrapper, is stored next to it. Reading a leaked database then requires
+// the key-encryption key as well.
+type FieldCipher struct {
+	wrapper KeyWrapper
+
+	mu        sync.Mutex
+	current   *dataKey
+	unwrapped map[string]cipher.AEAD
+}
+
+// NewFieldCipher creates a field cipher whose data keys are wrapped by wrapper
+func NewFieldCipher(wrapper KeyWrapper) *FieldCipher {
+	return &FieldCipher{
+		wrapper:   wrapper,
+		unwrapped: map[string]cipher.AEAD{},
+	}
+}
+
+// Registry returns a BSON registry that seals PII fields on write and unseals
+// them on read. Values written before encryption was enabled still decode.
+func (c *FieldCipher) Registry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+
+	encryptedString := reflect.TypeOf(models.EncryptedString(""))
+	registry.RegisterTypeEncoder(encryptedString, bsoncodec.ValueEncoderFunc(c.encodeString))
+	registry.RegisterTypeDecoder(encryptedString, bsoncodec.ValueDecoderFunc(c.decodeString))
+
+	location := reflect.TypeOf(models.Location{})
+	registry.RegisterTypeEncoder(location, c.sealedStructEncoder(sealedLocationFields))
+	registry.RegisterTypeDecoder(location, bsoncodec.ValueDecoderFunc(c.decodeSealedStruct))
+
+	return registry
+}
+
+// encodeString seals a models.EncryptedString
+func (c *FieldCipher) encodeString(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	sealed, err := c.seal(bsontype.String, bsoncore.AppendString(nil, val.String()))
+	if err != nil {
+		return err
+	}
+	return vw.WriteBinaryWithSubtype(sealed, sealedSubtype)
+}
+
+// decodeString unseals a models.EncryptedString, accepting plaintext strings
+// written before encryption was enabled
+func (c *FieldCipher) decodeString(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	var s string
+	switch vr.Type() {
+	case bsontype.String:
+		var err error
+		if s, err = vr.ReadString(); err != nil {
+			return err
+		}
+	case bsontype.Binary:
+		data, subtype, err := vr.ReadBinary()
+		if err != nil {
+			return err
+		}
+		t, value, err := c.unsealBinary(subtype, data)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if s, _, ok = bsoncore.ReadString(value); t != bsontype.String || !ok {
+			return fmt.Errorf("%w: expected a string, got %s", ErrUnsealFailed, t)
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot decode %s into an encrypted string", vr.Type())
+	}
+
+	val.SetString(s)
+	return nil
+}
+
+// sealedStructEncoder encodes a struct with the default codecs and then seals
+// the named fields
+func (c *FieldCipher) sealedStructEncoder(fields []string) bsoncodec.ValueEncoderFunc {
+	sealed := map[string]bool{}
+	for _, field := range fields {
+		sealed[field] = true
+	}
+
+	return func(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+		plain, err := bson.MarshalWithRegistry(bson.DefaultRegistry, val.Interface())
+		if err != nil {
+			return err
+		}
+		elements, err := bsoncore.Document(plain).Elements()
+		if err != nil {
+			return err
+		}
+
+		idx, doc := bsoncore.AppendDocumentStart(nil)
+		for _, element := range elements {
+			value := element.Value()
+			if !sealed[element.Key()] {
+				doc = bsoncore.AppendValueElement(doc, element.Key(), value)
+				continue
+			}
+			data, err := c.seal(value.Type, value.Data)
+			if err != nil {
+				return err
+			}
+			doc = bsoncore.AppendBinaryElement(doc, element.Key(), sealedSubtype, data)
+		}
+		doc, err = bsoncore.AppendDocumentEnd(doc, idx)
+		if err != nil {
+			return err
+		}
+		return bsonrw.Copier{}.CopyDocumentFromBytes(vw, doc)
+	}
+}
+
+// decodeSealedStruct unseals a struct's fields and then decodes it with the
+// default codecs
+func (c *FieldCipher) decodeSealedStruct(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if vr.Type() == bsontype.Null {
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	}
+
+	doc, err := bsonrw.Copier{}.CopyDocumentToBytes(vr)
+	if err != nil {
+		return err
+	}
+	elements, err := bsoncore.Document(doc).Elements()
+	if err != nil {
+		return err
+	}
+
+	idx, plain := bsoncore.AppendDocumentStart(nil)
+	for _, element := range elements {
+		value := element.Value()
+		if value.Type == bsontype.Binary {
+			if subtype, data, ok := value.BinaryOK(); ok && subtype == sealedSubtype {
+				t, unsealed, err := c.unseal(data)
+				if err != nil {
+					return err
+				}
+				value = bsoncore.Value{Type: t, Data: unsealed}
+			}
+		}
+		plain = bsoncore.AppendValueElement(plain, element.Key(), value)
+	}
+	plain, err = bsoncore.AppendDocumentEnd(plain, idx)
+	if err != nil {
+		return err
+	}
+
+	decoded := reflect.New(val.Type())
+	if err := bson.UnmarshalWithRegistry(bson.DefaultRegistry, plain, decoded.Interface()); err != nil {
+		return err
+	}
+	val.Set(decoded.Elem())
+	return nil
+}
+
+// UnsealDocument replaces every sealed value in doc, at any depth, with its
+// plaintext. It is for documents decoded into bson.M, such as aggregation
+// results, which bypass the typed codecs.
+func (c *FieldCipher) UnsealDocument(doc bson.M) error {
+	for key, value := range doc {
+		unsealed, err := c.unsealValue(value)
+		if err != nil {
+			return err
+		}
+		doc[key] = unsealed
+	}
+	return nil
+}
+
+// unsealValue unseals value if it is sealed and recurses into documents and arrays
+func (c *FieldCipher) unsealValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case primitive.Binary:
+		if v.Subtype != sealedSubtype {
+			return v, nil
+		}
+		t, data, err := c.unseal(v.Data)
+		if err != nil {
+			return nil, err
+		}
+		var plain interface{}
+		err = bson.RawValue{Type: t, Value: data}.Unmarshal(&plain)
+		return plain, err
+	case bson.M:
+		return v, c.UnsealDocument(v)
+	case bson.A:
+		for i := range v {
+			unsealed, err := c.unsealValue(v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = unsealed
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// seal encrypts a BSON value of type t with the current data key. The result
+// is the version, the wrapping key ID, the wrapped data key, and the
+// encrypted type and value.
+func (c *FieldCipher) seal(t bsontype.Type, value []byte) ([]byte, error) {
+	key, err := c.currentKey()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := gcmSeal(key.aead, append([]byte{byte(t)}, value...))
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 0, 4+len(key.keyID)+len(key.wrapped)+len(ciphertext))
+	sealed = append(sealed, sealedVersion, byte(len(key.keyID)))
+	sealed = append(sealed, key.keyID...)
+	sealed = binary.BigEndian.AppendUint16(sealed, uint16(len(key.wrapped)))
+	sealed = append(sealed, key.wrapped...)
+	return append(sealed, ciphertext...), nil
+}
+
+// unsealBinary unseals a BSON binary, rejecting subtypes that were not sealed
+func (c *FieldCipher) unsealBinary(subtype byte, data []byte) (bsontype.Type, []byte, error) {
+	if subtype != sealedSubtype {
+		return 0, nil, fmt.Errorf("%w: unexpected binary subtype %#x", ErrUnsealFailed, subtype)
+	}
+	return c.unseal(data)
+}
+
+// unseal decrypts a value sealed by seal
+func (c *FieldCipher) unseal(sealed []byte) (bsontype.Type, []byte, error) {
+	if len(sealed) < 2 || sealed[0] != sealedVersion {
+		return 0, nil, fmt.Errorf("%w: unknown format", ErrUnsealFailed)
+	}
+	idLen := int(sealed[1])
+	if len(sealed) < 4+idLen {
+		return 0, nil, fmt.Errorf("%w: truncated value", ErrUnsealFailed)
+	}
+	keyID := string(sealed[2 : 2+idLen])
+	wrappedLen := int(binary.BigEndian.Uint16(sealed[2+idLen:]))
+	rest := sealed[4+idLen:]
+	if len(rest) < wrappedLen {
+		return 0, nil, fmt.Errorf("%w: truncated value", ErrUnsealFailed)
+	}
+
+	aead, err := c.dataKey(keyID, rest[:wrappedLen])
+	if err != nil {
+		return 0, nil, err
+	}
+	plaintext, err := gcmOpen(aead, rest[wrappedLen:])
+	if err != nil || len(plaintext) == 0 {
+		return 0, nil, fmt.Errorf("%w: %v", ErrUnsealFailed, err)
+	}
+	return bsontype.Type(plaintext[0]), plaintext[1:], nil
+}
+
+// currentKey returns the data key sealing new values, generating a fresh one
+// once the current key has outlived dataKeyLifetime
+func (c *FieldCipher) currentKey() (*dataKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil && time.Since(c.current.createdAt) < dataKeyLifetime {
+		return c.current, nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(raw)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := c.wrapper.Wrap(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	c.current = &dataKey{aead: aead, keyID: keyID, wrapped: wrapped, createdAt: time.Now()}
+	c.unwrapped[keyID+string(wrapped)] = aead
+	return c.current, nil
+}
+
+// dataKey returns the unwrapped data key, caching it so a KMS is called once
+// per data key rather than once per value
+func (c *FieldCipher) dataKey(keyID string, wrapped []byte) (cipher.AEAD, error) {
+	cacheKey := keyID + string(wrapped)
+	c.mu.Lock()
+	aead, ok := c.unwrapped[cacheKey]
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	raw, err := c.wrapper.Unwrap(keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if aead, err = newGCM(raw); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.unwrapped[cacheKey] = aead
+	c.mu.Unlock()
+	return aead, nil
+}
+
+// newGCM returns an AES-GCM AEAD for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// gcmSeal encrypts plaintext under a random nonce, which it prepends
+func gcmSeal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// gcmOpen decrypts a value produced by gcmSeal
+func gcmOpen(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+} 
\ No newline at end of file
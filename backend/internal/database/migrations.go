@@ -0,0 +1,354 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/models"
+)
+
+// enumField describes a string field restricted to a set of values and the
+// value stored when a legacy document holds something unrecognizable
+type enumField struct {
+	collection string
+	field      string
+	values     []string
+	fallback   string
+}
+
+// enumFields are the fields migrated by migrateEnumFields. Unknown statuses
+// fall back to cancelled so bad documents drop out of matching rather than
+// being reopened.
+var enumFields = []enumField{
+	{collection: "needs", field: "urgency", values: models.UrgencyMedium.Values(), fallback: string(models.UrgencyMedium)},
+	{collection: "needs", field: "status", values: models.NeedStatusRequested.Values(), fallback: string(models.NeedStatusCancelled)},
+	{collection: "tasks", field: "status", values: models.TaskStatusAccepted.Values(), fallback: string(models.TaskStatusCancelled)},
+}
+
+// migrateEnumFields rewrites documents written before enum validation existed
+func migrateEnumFields(ctx context.Context, db *mongo.Database) error {
+	for _, f := range enumFields {
+		if err := migrateEnumField(ctx, db, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateEnumField rewrites the documents holding a value of f outside its
+// values. Values differing only in case, spacing, or hyphenation are
+// normalized; anything else is replaced by the field's fallback.
+func migrateEnumField(ctx context.Context, db *mongo.Database, f enumField) error {
+	collection := db.Collection(f.collection)
+	filter := bson.M{f.field: bson.M{"$nin": f.values}}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{f.field: 1}))
+	if err != nil {
+		return err
+	}
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		current, _ := doc[f.field].(string)
+		value := normalizeEnumValue(current)
+		if !containsValue(f.values, value) {
+			value = f.fallback
+		}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$set": bson.M{f.field: value}}); err != nil {
+			return err
+		}
+	}
+
+	if len(docs) > 0 {
+		log.Printf("Migrated %d %s documents with invalid %s", len(docs), f.collection, f.field)
+	}
+	return nil
+}
+
+// seedCategories adds the default top-level categories to a taxonomy
+// written before they were managed, and rewrites needs filed under anything
+// else as for an enum. The other category, which cannot be deleted, is
+// seeded last, so once it exists admins manage the categories and needs
+// keep the category they were filed under even if it is later deleted.
+func seedCategories(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("categories")
+	seeded, err := collection.CountDocuments(ctx, bson.M{"path": string(models.CategoryOther)})
+	if err != nil {
+		return err
+	}
+	if seeded > 0 {
+		return nil
+	}
+
+	values := make([]string, len(models.DefaultCategories))
+	for i, category := range models.DefaultCategories {
+		values[i] = string(category)
+	}
+	err = migrateEnumField(ctx, db, enumField{
+		collection: "needs",
+		field:      "category",
+		values:     values,
+		fallback:   string(models.CategoryOther),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, category := range models.DefaultCategories {
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"path": string(category)},
+			bson.M{"$setOnInsert": models.NeedCategory{
+				ID:        primitive.NewObjectID(),
+				Path:      string(category),
+				Name:      category.Label(),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("Seeded %d top-level categories", len(models.DefaultCategories))
+	return nil
+}
+
+// backfillNeedPoints sets the GeoJSON point of needs written before radius
+// queries existed to the center of their stored H3 cell
+func backfillNeedPoints(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("needs")
+	filter := bson.M{"point": bson.M{"$exists": false}, "location.h3_index": bson.M{"$nin": []interface{}{"", nil}}}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"location.h3_index": 1}))
+	if err != nil {
+		return err
+	}
+
+	var docs []struct {
+		ID       interface{} `bson:"_id"`
+		Location struct {
+			H3Index string `bson:"h3_index"`
+		} `bson:"location"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+
+	migrated := 0
+	for _, doc := range docs {
+		cell := h3.Cell(h3.IndexFromString(doc.Location.H3Index))
+		if !cell.IsValid() {
+			continue
+		}
+		center := cell.LatLng()
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"point": models.NewGeoPoint(center.Lat, center.Lng)}}); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("Backfilled points for %d needs", migrated)
+	}
+	return nil
+}
+
+// backfillMatchCells sets the match cells of needs and volunteers written
+// before matching was narrowed to nearby cells. Locations without
+// coordinates are given an empty cell so reruns skip them.
+func backfillMatchCells(ctx context.Context, db *mongo.Database) error {
+	for _, collectionName := range []string{"needs", "volunteers"} {
+		collection := db.Collection(collectionName)
+		cursor, err := collection.Find(ctx, bson.M{"match_cell": bson.M{"$exists": false}}, options.Find().SetProjection(bson.M{"location": 1}))
+		if err != nil {
+			return err
+		}
+
+		var docs []struct {
+			ID       interface{}     `bson:"_id"`
+			Location models.Location `bson:"location"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return err
+		}
+
+		for _, doc := range docs {
+			if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"match_cell": doc.Location.MatchCell()}}); err != nil {
+				return err
+			}
+		}
+
+		if len(docs) > 0 {
+			log.Printf("Backfilled match cells for %d %s", len(docs), collectionName)
+		}
+	}
+	return nil
+}
+
+// indexNotFound is the server's error code for dropping a missing index
+const indexNotFound = 27
+
+// normalizeUserEmails lowercases and trims the addresses of users written
+// before sign-up and sign-in normalized them, so each address belongs to one
+// account. Where several accounts share an address but for case, the one
+// that proved it first, or else the oldest, keeps it; the others are moved
+// to a placeholder address, keeping the original in duplicate_email, for an
+// admin to merge. They stay signed in but can no longer sign in by email.
+func normalizeUserEmails(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("users")
+	normalized := bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}
+
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   normalized,
+			"users": bson.M{"$push": bson.M{"_id": "$_id", "email": "$email", "email_verified_at": "$email_verified_at", "created_at": "$created_at"}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Email string `bson:"_id"`
+		Users []struct {
+			ID              primitive.ObjectID `bson:"_id"`
+			Email           string             `bson:"email"`
+			EmailVerifiedAt *time.Time         `bson:"email_verified_at"`
+			CreatedAt       time.Time          `bson:"created_at"`
+		} `bson:"users"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		users := group.Users
+		sort.Slice(users, func(i, j int) bool {
+			a, b := users[i], users[j]
+			switch {
+			case (a.EmailVerifiedAt == nil) != (b.EmailVerifiedAt == nil):
+				return a.EmailVerifiedAt != nil
+			case a.EmailVerifiedAt != nil && !a.EmailVerifiedAt.Equal(*b.EmailVerifiedAt):
+				return a.EmailVerifiedAt.Before(*b.EmailVerifiedAt)
+			case !a.CreatedAt.Equal(b.CreatedAt):
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID.Hex() < b.ID.Hex()
+		})
+		for _, user := range users[1:] {
+			_, err := collection.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{
+				"email":           fmt.Sprintf("duplicate-%s@invalid", user.ID.Hex()),
+				"duplicate_email": user.Email,
+				"duplicate_of":    users[0].ID,
+			}})
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("Moved %d accounts sharing %s with %s aside for merging", len(users)-1, group.Email, users[0].ID.Hex())
+	}
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"$expr": bson.M{"$ne": bson.A{"$email", normalized}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"email": normalized}}}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		log.Printf("Normalized emails of %d users", result.ModifiedCount)
+	}
+
+	// Lookups by normalized address use the unique email index, replacing
+	// the case-insensitive one provider sign-in used
+	if _, err := collection.Indexes().DropOne(ctx, "email_case_insensitive"); err != nil {
+		var commandErr mongo.CommandError
+		if !errors.As(err, &commandErr) || !commandErr.HasErrorCode(indexNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// plaintextLocation matches documents whose location was written before
+// field encryption was enabled
+var plaintextLocation = []bson.M{
+	{"location.latitude": bson.M{"$type": "number"}},
+	{"location.longitude": bson.M{"$type": "number"}},
+	{"location.address": bson.M{"$type": "string"}},
+}
+
+// sealPlaintextFields encrypts PII written before field encryption was
+// enabled. Reading and writing back through the typed codecs seals it, and
+// sealed documents no longer match, so reruns only touch what is left.
+func sealPlaintextFields(ctx context.Context, db *mongo.Database) error {
+	plaintextPhone := bson.M{"phone": bson.M{"$type": "string"}}
+	filters := map[string]bson.M{
+		"users":      {"$or": append([]bson.M{plaintextPhone}, plaintextLocation...)},
+		"needs":      {"$or": plaintextLocation},
+		"volunteers": {"$or": plaintextLocation},
+	}
+
+	for collectionName, filter := range filters {
+		collection := db.Collection(collectionName)
+		cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"location": 1, "phone": 1}))
+		if err != nil {
+			return err
+		}
+
+		var docs []struct {
+			ID       interface{}            `bson:"_id"`
+			Location *models.Location       `bson:"location"`
+			Phone    models.EncryptedString `bson:"phone"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return err
+		}
+
+		for _, doc := range docs {
+			set := bson.M{}
+			if doc.Location != nil {
+				set["location"] = *doc.Location
+			}
+			if doc.Phone != "" {
+				set["phone"] = doc.Phone
+			}
+			if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": set}); err != nil {
+				return err
+			}
+		}
+
+		if len(docs) > 0 {
+			log.Printf("Encrypted PII in %d %s documents", len(docs), collectionName)
+		}
+	}
+	return nil
+}
+
+func normalizeEnumValue(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(value)
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
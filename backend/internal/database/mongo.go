@@ -2,25 +2,34 @@ package database
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/telemetry"
 )
 
 // MongoClient wraps the MongoDB client
 type MongoClient struct {
 	Client *mongo.Client
 	DB     *mongo.Database
+
+	fieldCipher *FieldCipher
 }
 
-// NewMongoClient creates a new MongoDB client
-func NewMongoClient(uri string) (*MongoClient, error) {
+// NewMongoClient creates a new MongoDB client. When fieldCipher is non-nil,
+// PII fields are sealed on write and unsealed on read.
+func NewMongoClient(uri string, fieldCipher *FieldCipher) (*MongoClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	opts := options.Client().ApplyURI(uri).SetMonitor(telemetry.MongoMonitor())
+	if fieldCipher != nil {
+		opts.SetRegistry(fieldCipher.Registry())
+	}
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -33,17 +42,50 @@ func NewMongoClient(uri string) (*MongoClient, error) {
 
 	db := client.Database("neighborenexus")
 
-	// Create indexes
-	if err := createIndexes(ctx, db); err != nil {
-		log.Printf("Warning: Failed to create indexes: %v", err)
-	}
-
 	return &MongoClient{
-		Client: client,
-		DB:     db,
+		Client:      client,
+		DB:          db,
+		fieldCipher: fieldCipher,
 	}, nil
 }
 
+// Unseal decrypts sealed fields in a document decoded into bson.M, which
+// bypasses the typed codecs that decrypt them otherwise
+func (m *MongoClient) Unseal(doc bson.M) error {
+	if m.fieldCipher == nil || doc == nil {
+		return nil
+	}
+	return m.fieldCipher.UnsealDocument(doc)
+}
+
+// Migrate creates indexes and rewrites legacy data. It is idempotent.
+func (m *MongoClient) Migrate(ctx context.Context) error {
+	if err := createIndexes(ctx, m.DB); err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+	if err := migrateEnumFields(ctx, m.DB); err != nil {
+		return fmt.Errorf("failed to migrate enum fields: %w", err)
+	}
+	if err := seedCategories(ctx, m.DB); err != nil {
+		return fmt.Errorf("failed to seed categories: %w", err)
+	}
+	if err := backfillNeedPoints(ctx, m.DB); err != nil {
+		return fmt.Errorf("failed to backfill need points: %w", err)
+	}
+	if err := backfillMatchCells(ctx, m.DB); err != nil {
+		return fmt.Errorf("failed to backfill match cells: %w", err)
+	}
+	if err := normalizeUserEmails(ctx, m.DB); err != nil {
+		return fmt.Errorf("failed to normalize user emails: %w", err)
+	}
+	if m.fieldCipher != nil {
+		if err := sealPlaintextFields(ctx, m.DB); err != nil {
+			return fmt.Errorf("failed to encrypt plaintext fields: %w", err)
+		}
+	}
+	return nil
+}
+
 // createIndexes creates necessary indexes for the application
 func createIndexes(ctx context.Context, db *mongo.Database) error {
 	// Users collection indexes
@@ -58,6 +100,26 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	// Trust scores are refreshed oldest first
+	_, err = usersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"trust.updated_at": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Users signing in with a provider are found by its account ID
+	_, err = usersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "linked_accounts.provider", Value: 1}, {Key: "linked_accounts.subject", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"linked_accounts.subject": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+
 	// Needs collection indexes
 	needsCollection := db.Collection("needs")
 	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -87,6 +149,35 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"updated_at": -1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Needs are browsed by distance from the cell centers stored in point
+	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"point": "2dsphere",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Matching loads the needs and volunteers in the cells near each other
+	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"match_cell": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Volunteers collection indexes
 	volunteersCollection := db.Collection("volunteers")
 	_, err = volunteersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -99,6 +190,15 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	_, err = volunteersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"match_cell": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Tasks collection indexes
 	tasksCollection := db.Collection("tasks")
 	_, err = tasksCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -128,6 +228,23 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	_, err = tasksCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"updated_at": -1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Tasks waiting on a participant's confirmation, by when they lapse
+	_, err = tasksCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "confirmation.due_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Feedback collection indexes
 	feedbackCollection := db.Collection("feedback")
 	_, err = feedbackCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -148,9 +265,498 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	// Each participant rates a task once, and the reveal job scans
+	// feedback still hidden by age
+	_, err = feedbackCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "from_user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = feedbackCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "revealed_at", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	// Approved testimonials, newest first, for highlights
+	_, err = feedbackCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "testimonial.approved_at", Value: -1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Kudos indexes: one per task from the person helped, and a volunteer's
+	// kudos newest first
+	kudosCollection := db.Collection("kudos")
+	_, err = kudosCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "from_user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = kudosCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "to_user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Points ledger indexes: one line per task, a volunteer's lines by
+	// date for daily caps, and lines by date for leaderboards
+	pointsCollection := db.Collection("points")
+	_, err = pointsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = pointsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = pointsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Moderation queue indexes: open items by deadline, and lookup by content
+	moderationCollection := db.Collection("moderation_items")
+	_, err = moderationCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "due_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = moderationCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "content_type", Value: 1}, {Key: "content_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Report indexes: per-user history in both directions, and by moderation item
+	reportsCollection := db.Collection("reports")
+	for _, field := range []string{"reporter_id", "subject_id"} {
+		_, err = reportsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: field, Value: 1}, {Key: "created_at", Value: -1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = reportsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"moderation_item_id": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Announcement index: scheduled announcements by publish time
+	_, err = db.Collection("announcements").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Policy version indexes: one record per version, and the version in effect
+	policyCollection := db.Collection("policy_versions")
+	_, err = policyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "document", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = policyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "document", Value: 1}, {Key: "effective_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Analytics event index: events of a type over time
+	_, err = db.Collection("analytics_events").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "type", Value: 1}, {Key: "occurred_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Group membership indexes: one membership per user and group, and a
+	// user's groups
+	groupMembersCollection := db.Collection("group_members")
+	_, err = groupMembersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = groupMembersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"user_id": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Event indexes: upcoming events by start time, one signup per user and
+	// slot, a user's signups, and signups due a reminder
+	_, err = db.Collection("events").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "starts_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	signupsCollection := db.Collection("event_signups")
+	_, err = signupsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "event_id", Value: 1}, {Key: "shift_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = signupsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"user_id": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = signupsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "reminded_at", Value: 1}, {Key: "starts_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Post indexes: a neighborhood's feed newest first, and one reaction per
+	// user and post
+	_, err = db.Collection("posts").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "neighborhood", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	reactionsCollection := db.Collection("post_reactions")
+	_, err = reactionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "post_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = reactionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]interface{}{
+			"user_id": 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Offer indexes: active offers by category and neighborhood newest
+	// first, and a user's own offers
+	offersCollection := db.Collection("offers")
+	_, err = offersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "category", Value: 1}, {Key: "neighborhood", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = offersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Partner indexes: organizations are looked up by API key hash, each
+	// organization's external IDs are unique, and the status job scans
+	// referrals not yet reported in a final status
+	_, err = db.Collection("partner_organizations").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "key_hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "partner.organization_id", Value: 1}, {Key: "partner.external_id", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"partner.external_id": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "partner.final", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"partner.organization_id": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Emergency index: emergencies in force, newest first
+	_, err = db.Collection("emergencies").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "ended_at", Value: 1}, {Key: "started_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Referral indexes: invite codes are unique, a user's invites newest
+	// first, one referral per sign-up, and referrals by inviter and by
+	// neighborhood over time
+	invitesCollection := db.Collection("invites")
+	_, err = invitesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = invitesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "inviter_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	referralsCollection := db.Collection("referrals")
+	_, err = referralsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "referred_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	for _, field := range []string{"inviter_id", "neighborhood"} {
+		_, err = referralsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: field, Value: 1}, {Key: "created_at", Value: -1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Contribution indexes: a user's contributions newest first, checkout
+	// lookups, and one payout per need, listed per volunteer or by status
+	_, err = db.Collection("contributions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "contributor_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection("contributions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "checkout_session_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	payoutsCollection := db.Collection("payouts")
+	_, err = payoutsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "need_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	for _, field := range []string{"volunteer_id", "status"} {
+		_, err = payoutsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: field, Value: 1}, {Key: "created_at", Value: -1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Match indexes: one match per need and volunteer, and a volunteer's
+	// matches by need
+	matchesCollection := db.Collection("matches")
+	_, err = matchesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "need_id", Value: 1}, {Key: "volunteer_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = matchesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "need_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Device indexes: one owner per push token, and a user's devices
+	devicesCollection := db.Collection("devices")
+	_, err = devicesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = devicesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Category indexes: one category per path, a category's children, and
+	// the needs filed beneath a subcategory
+	categoriesCollection := db.Collection("categories")
+	_, err = categoriesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "path", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = categoriesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "parent", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = needsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "subcategory", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Outbox index: claiming a topic's events oldest first
+	_, err = db.Collection("outbox").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "topic", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Data export index: a user's exports newest first
+	_, err = db.Collection("data_exports").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Message index: a task's conversation newest first
+	_, err = db.Collection("messages").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Match decision indexes: exporting and purging by age, and erasing a
+	// volunteer's candidacies
+	decisionsCollection := db.Collection("match_decisions")
+	_, err = decisionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = decisionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "candidates.user_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Notification indexes: a user's inbox newest first, their unread count,
+	// and purging by age
+	notificationsCollection := db.Collection("notifications")
+	for _, keys := range []bson.D{
+		{{Key: "user_id", Value: 1}, {Key: "_id", Value: -1}},
+		{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}},
+		{{Key: "created_at", Value: 1}},
+	} {
+		_, err = notificationsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys})
+		if err != nil {
+			return err
+		}
+	}
+
+	// A retried delivery stores each user's copy once
+	_, err = notificationsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"key": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Audit log indexes: time-ordered history by actor, target, and action
+	auditCollection := db.Collection("audit_logs")
+	for _, field := range []string{"actor_id", "target_id", "action"} {
+		_, err = auditCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: field, Value: 1}, {Key: "created_at", Value: -1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = auditCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Upload indexes: a user's uploads, unattached ones by age for purging,
+	// and need images by need
+	uploadsCollection := db.Collection("uploads")
+	_, err = uploadsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = uploadsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "attached", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = uploadsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "need_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Ping tests the MongoDB connection
+func (m *MongoClient) Ping(ctx context.Context) error {
+	return m.Client.Ping(ctx, nil)
+}
+
 // GetCollection returns a MongoDB collection
 func (m *MongoClient) GetCollection(name string) *mongo.Collection {
 	return m.DB.Collection(name)
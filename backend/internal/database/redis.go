@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"neighborenexus/internal/telemetry"
 )
 
 // RedisClient wraps the Redis client
@@ -12,6 +14,14 @@ type RedisClient struct {
 	Client *redis.Client
 }
 
+// Broker queues jobs for workers and publishes messages to subscribers.
+// RedisClient is the broker in production; tests can use the in-memory
+// one in the fakes package.
+type Broker interface {
+	EnqueueJob(ctx context.Context, queue string, job interface{}) error
+	Publish(ctx context.Context, channel string, message interface{}) error
+}
+
 // NewRedisClient creates a new Redis client
 func NewRedisClient(addr, password string, db int) *RedisClient {
 	client := redis.NewClient(&redis.Options{
@@ -19,6 +29,7 @@ func NewRedisClient(addr, password string, db int) *RedisClient {
 		Password: password,
 		DB:       db,
 	})
+	client.AddHook(telemetry.RedisHook{})
 
 	return &RedisClient{
 		Client: client,
@@ -40,6 +51,12 @@ func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
 	return r.Client.Get(ctx, key).Result()
 }
 
+// GetDel gets a value and deletes its key in one step, so only one caller
+// can take it
+func (r *RedisClient) GetDel(ctx context.Context, key string) (string, error) {
+	return r.Client.GetDel(ctx, key).Result()
+}
+
 // Del deletes a key
 func (r *RedisClient) Del(ctx context.Context, key string) error {
 	return r.Client.Del(ctx, key).Err()
@@ -51,6 +68,11 @@ func (r *RedisClient) Exists(ctx context.Context, key string) (bool, error) {
 	return result > 0, err
 }
 
+// SetNX sets a key only if it does not already exist, reporting whether it was set
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.Client.SetNX(ctx, key, value, expiration).Result()
+}
+
 // Incr increments a counter
 func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	return r.Client.Incr(ctx, key).Result()
@@ -61,6 +83,82 @@ func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Du
 	return r.Client.Expire(ctx, key, expiration).Err()
 }
 
+// TTL returns the remaining time to live of a key, or a negative duration
+// when the key does not exist or has no expiry
+func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.Client.TTL(ctx, key).Result()
+}
+
+// AddToSet adds member to a set, refreshes its expiration, and returns the
+// set's size
+func (r *RedisClient) AddToSet(ctx context.Context, key, member string, expiration time.Duration) (int64, error) {
+	pipe := r.Client.TxPipeline()
+	pipe.SAdd(ctx, key, member)
+	pipe.Expire(ctx, key, expiration)
+	size := pipe.SCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return size.Val(), nil
+}
+
+// SetMembers returns the members of a set
+func (r *RedisClient) SetMembers(ctx context.Context, key string) ([]string, error) {
+	return r.Client.SMembers(ctx, key).Result()
+}
+
+// RemoveFromSet removes members from a set
+func (r *RedisClient) RemoveFromSet(ctx context.Context, key string, members ...string) error {
+	values := make([]interface{}, len(members))
+	for i, member := range members {
+		values[i] = member
+	}
+	return r.Client.SRem(ctx, key, values...).Err()
+}
+
+// compareAndSet replaces a key's value only if it still holds the expected one
+var compareAndSet = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// CompareAndSet sets key to value with an expiration only if it still holds
+// old, reporting whether it was set. Concurrent writers racing on the same
+// old value can use it so exactly one wins.
+func (r *RedisClient) CompareAndSet(ctx context.Context, key, old, value string, expiration time.Duration) (bool, error) {
+	set, err := compareAndSet.Run(ctx, r.Client, []string{key}, old, value, expiration.Milliseconds()).Int()
+	return set == 1, err
+}
+
+// SetSize returns the number of members in a set
+func (r *RedisClient) SetSize(ctx context.Context, key string) (int64, error) {
+	return r.Client.SCard(ctx, key).Result()
+}
+
+// TouchPresence records member as present in key at now, keeping the key
+// for ttl after the last touch
+func (r *RedisClient) TouchPresence(ctx context.Context, key, member string, now time.Time, ttl time.Duration) error {
+	pipe := r.Client.TxPipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(now.Unix()), Member: member})
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RemovePresence removes member from key
+func (r *RedisClient) RemovePresence(ctx context.Context, key, member string) error {
+	return r.Client.ZRem(ctx, key, member).Err()
+}
+
+// PresentSince reports whether any member of key was touched at or after since
+func (r *RedisClient) PresentSince(ctx context.Context, key string, since time.Time) (bool, error) {
+	count, err := r.Client.ZCount(ctx, key, strconv.FormatInt(since.Unix(), 10), "+inf").Result()
+	return count > 0, err
+}
+
 // Close closes the Redis connection
 func (r *RedisClient) Close() error {
 	return r.Client.Close()
@@ -105,6 +203,56 @@ func (r *RedisClient) DequeueJob(ctx context.Context, queue string) (string, err
 	return result[1], nil
 }
 
+// DequeueJobTimeout waits up to timeout for a job, returning "" if none arrived
+func (r *RedisClient) DequeueJobTimeout(ctx context.Context, queue string, timeout time.Duration) (string, error) {
+	result, err := r.Client.BRPop(ctx, timeout, "queue:"+queue).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(result) < 2 {
+		return "", nil
+	}
+	return result[1], nil
+}
+
+// DeadLetterJob records a job that failed for good on its queue's dead
+// letters, keeping only the newest limit entries
+func (r *RedisClient) DeadLetterJob(ctx context.Context, queue string, entry interface{}, limit int64) error {
+	pipe := r.Client.TxPipeline()
+	pipe.LPush(ctx, "dead:"+queue, entry)
+	pipe.LTrim(ctx, "dead:"+queue, 0, limit-1)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeadJobs returns up to limit of a queue's dead letters, newest first
+func (r *RedisClient) DeadJobs(ctx context.Context, queue string, limit int64) ([]string, error) {
+	return r.Client.LRange(ctx, "dead:"+queue, 0, limit-1).Result()
+}
+
+// PopDeadJob removes and returns a queue's oldest dead letter, or "" when
+// there are none
+func (r *RedisClient) PopDeadJob(ctx context.Context, queue string) (string, error) {
+	entry, err := r.Client.RPop(ctx, "dead:"+queue).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return entry, err
+}
+
+// Publish sends a message to every subscriber of a channel
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.Client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe listens on the given channels; the caller must close the subscription
+func (r *RedisClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return r.Client.Subscribe(ctx, channels...)
+}
+
 // WebSocket session management
 func (r *RedisClient) AddWebSocketSession(ctx context.Context, userID, sessionID string) error {
 	return r.Set(ctx, "ws:"+userID, sessionID, 24*time.Hour)
@@ -0,0 +1,87 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"time"
+)
+
+// sendGridURL is SendGrid's v3 mail send endpoint
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridSender delivers email through the SendGrid web API, for
+// deployments that cannot reach an SMTP relay
+type SendGridSender struct {
+	client *http.Client
+	apiKey string
+	from   sendGridAddress
+}
+
+// sendGridAddress is an email address in SendGrid's request format
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// NewSendGridSender creates a SendGrid sender. from may include a display
+// name, e.g. NeighborNexus <no-reply@example.org>.
+func NewSendGridSender(apiKey, from string) *SendGridSender {
+	address := sendGridAddress{Email: from}
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		address = sendGridAddress{Email: parsed.Address, Name: parsed.Name}
+	}
+	return &SendGridSender{
+		client: &http.Client{Timeout: 10 * time.Second},
+		apiKey: apiKey,
+		from:   address,
+	}
+}
+
+// Send delivers msg
+func (s *SendGridSender) Send(ctx context.Context, msg Message) error {
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	type personalization struct {
+		To []sendGridAddress `json:"to"`
+	}
+	body, err := json.Marshal(struct {
+		Personalizations []personalization `json:"personalizations"`
+		From             sendGridAddress   `json:"from"`
+		Subject          string            `json:"subject"`
+		Content          []content         `json:"content"`
+	}{
+		Personalizations: []personalization{{To: []sendGridAddress{{Email: msg.To}}}},
+		From:             s.from,
+		Subject:          headerValue(msg.Subject),
+		Content:          []content{{Type: "text/plain", Value: msg.Body}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to send email: sendgrid returned %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+} 
\ No newline at end of file
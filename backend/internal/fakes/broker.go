@@ -0,0 +1,62 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Published is a message a Broker was asked to publish
+type Published struct {
+	Channel string
+	Message string
+}
+
+// Broker keeps queued jobs and published messages in memory instead of
+// Redis. Use it wherever a database.Broker is taken.
+type Broker struct {
+	mu        sync.Mutex
+	queues    map[string][]string
+	published []Published
+	// Err, when set, is returned from every call without recording anything
+	Err error
+}
+
+// EnqueueJob records job on queue
+func (b *Broker) EnqueueJob(ctx context.Context, queue string, job interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Err != nil {
+		return b.Err
+	}
+	if b.queues == nil {
+		b.queues = make(map[string][]string)
+	}
+	b.queues[queue] = append(b.queues[queue], fmt.Sprint(job))
+	return nil
+}
+
+// Publish records message on channel
+func (b *Broker) Publish(ctx context.Context, channel string, message interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Err != nil {
+		return b.Err
+	}
+	b.published = append(b.published, Published{Channel: channel, Message: fmt.Sprint(message)})
+	return nil
+}
+
+// Jobs returns the jobs queued on queue, oldest first
+func (b *Broker) Jobs(queue string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.queues[queue]...)
+}
+
+// Published returns the messages published so far, in order
+func (b *Broker) Published() []Published {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Published(nil), b.published...)
+} 
\ No newline at end of file
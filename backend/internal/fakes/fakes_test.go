@@ -0,0 +1,55 @@
+package fakes
+
+import (
+	"context"
+	"testing"
+
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/vectors"
+)
+
+func TestVectorStoreQueriesBySimilarity(t *testing.T) {
+	ctx := context.Background()
+	store := NewVectorStore()
+	err := store.Upsert(ctx, vectors.NamespaceNeeds, []vectors.Vector{
+		{ID: "near", Values: []float32{1, 0.1}},
+		{ID: "far", Values: []float32{-1, 0}},
+		{ID: "middle", Values: []float32{0.5, 0.5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := store.Query(ctx, vectors.NamespaceNeeds, []float32{1, 0}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ID != "near" || results[1].ID != "middle" {
+		t.Fatalf("got %+v, want near then middle", results)
+	}
+
+	if err := store.Delete(ctx, vectors.NamespaceNeeds, []string{"near"}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := store.IDs(vectors.NamespaceNeeds); len(ids) != 2 || ids[0] != "far" || ids[1] != "middle" {
+		t.Fatalf("got %v after delete, want far and middle", ids)
+	}
+	if ids := store.IDs(vectors.NamespaceVolunteers); len(ids) != 0 {
+		t.Fatalf("got %v in another namespace, want none", ids)
+	}
+}
+
+func TestWebSocketServiceRecordsMessages(t *testing.T) {
+	notifier := &Notifier{}
+	websocket := notifier.WebSocketService()
+
+	websocket.SendToUser("user-1", models.WebSocketMessage{Type: "new_match"})
+	websocket.SendToMultipleUsers([]string{"user-1", "user-2"}, models.WebSocketMessage{Type: "announcement"})
+
+	if got := notifier.SentTo("user-1"); len(got) != 2 || got[0].Type != "new_match" || got[1].Type != "announcement" {
+		t.Fatalf("got %+v for user-1, want new_match then announcement", got)
+	}
+	if got := notifier.SentTo("user-2"); len(got) != 1 || got[0].Type != "announcement" {
+		t.Fatalf("got %+v for user-2, want announcement", got)
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,32 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"neighborenexus/internal/email"
+)
+
+// Mailer records email instead of sending it. Use it wherever an
+// email.Sender is taken.
+type Mailer struct {
+	mu   sync.Mutex
+	sent []email.Message
+	// Err, when set, is returned from Send after recording the message
+	Err error
+}
+
+// Send records a message
+func (m *Mailer) Send(ctx context.Context, msg email.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return m.Err
+}
+
+// Sent returns the messages recorded so far, in order
+func (m *Mailer) Sent() []email.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]email.Message(nil), m.sent...)
+} 
\ No newline at end of file
@@ -0,0 +1,153 @@
+package fakes
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// NotificationStore keeps inbox notifications in memory instead of MongoDB.
+// Use it wherever a services.NotificationStore is taken.
+type NotificationStore struct {
+	mu            sync.Mutex
+	notifications []models.Notification
+	// Err, when set, is returned from every call without changing the store
+	Err error
+}
+
+// Insert adds notifications, skipping any with a key already stored for
+// their user
+func (s *NotificationStore) Insert(ctx context.Context, notifications []models.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+	for _, notification := range notifications {
+		if notification.Key == "" || !s.stored(notification.UserID, notification.Key) {
+			s.notifications = append(s.notifications, notification)
+		}
+	}
+	return nil
+}
+
+// stored reports whether userID has a notification stored under key
+func (s *NotificationStore) stored(userID primitive.ObjectID, key string) bool {
+	for _, notification := range s.notifications {
+		if notification.UserID == userID && notification.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the notifications matching query, newest first
+func (s *NotificationStore) Find(ctx context.Context, query services.NotificationQuery) ([]models.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	found := []models.Notification{}
+	for _, notification := range s.notifications {
+		if notification.UserID != query.UserID ||
+			(!query.Before.IsZero() && notification.ID.Hex() >= query.Before.Hex()) ||
+			(query.UnreadOnly && notification.ReadAt != nil) {
+			continue
+		}
+		found = append(found, notification)
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].ID.Hex() > found[j].ID.Hex() })
+	if query.Limit > 0 && int64(len(found)) > query.Limit {
+		found = found[:query.Limit]
+	}
+	return found, nil
+}
+
+// CountUnread returns how many of userID's notifications are unread
+func (s *NotificationStore) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return 0, s.Err
+	}
+
+	var unread int64
+	for _, notification := range s.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// MarkRead sets the read time of userID's notification id if it is unread
+func (s *NotificationStore) MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return false, s.Err
+	}
+
+	for i := range s.notifications {
+		notification := &s.notifications[i]
+		if notification.ID == id && notification.UserID == userID {
+			if notification.ReadAt == nil {
+				notification.ReadAt = &at
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MarkAllRead sets the read time of userID's unread notifications
+func (s *NotificationStore) MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return 0, s.Err
+	}
+
+	var read int64
+	for i := range s.notifications {
+		notification := &s.notifications[i]
+		if notification.UserID == userID && notification.ReadAt == nil {
+			notification.ReadAt = &at
+			read++
+		}
+	}
+	return read, nil
+}
+
+// DeleteCreatedBefore deletes notifications created before cutoff
+func (s *NotificationStore) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return 0, s.Err
+	}
+
+	kept := s.notifications[:0]
+	for _, notification := range s.notifications {
+		if notification.CreatedAt.After(cutoff) || notification.CreatedAt.Equal(cutoff) {
+			kept = append(kept, notification)
+		}
+	}
+	deleted := int64(len(s.notifications) - len(kept))
+	s.notifications = kept
+	return deleted, nil
+}
+
+// All returns every stored notification, in the order stored
+func (s *NotificationStore) All() []models.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.Notification(nil), s.notifications...)
+} 
\ No newline at end of file
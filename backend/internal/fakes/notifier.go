@@ -0,0 +1,60 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// Notification is one message a Notifier was asked to deliver
+type Notification struct {
+	UserIDs []string
+	Message models.WebSocketMessage
+}
+
+// Notifier records notifications instead of queueing them for WebSocket
+// delivery. Pass its Notify method wherever a services.Notifier is taken.
+type Notifier struct {
+	mu   sync.Mutex
+	sent []Notification
+	// Err, when set, is returned from Notify after recording the notification
+	Err error
+}
+
+// Notify records a notification
+func (n *Notifier) Notify(ctx context.Context, userIDs []string, message models.WebSocketMessage) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, Notification{UserIDs: append([]string(nil), userIDs...), Message: message})
+	return n.Err
+}
+
+// WebSocketService returns a WebSocket service that holds no connections and
+// records every message it sends to users with n, as a handoff to offline
+// delivery. Use it wherever a *services.WebSocketService is taken.
+func (n *Notifier) WebSocketService() *services.WebSocketService {
+	return services.NewWebSocketService(nil, n.Notify, nil, nil)
+}
+
+// Sent returns the notifications recorded so far, in order
+func (n *Notifier) Sent() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification(nil), n.sent...)
+}
+
+// SentTo returns the messages recorded for one user, in order
+func (n *Notifier) SentTo(userID string) []models.WebSocketMessage {
+	var messages []models.WebSocketMessage
+	for _, notification := range n.Sent() {
+		for _, id := range notification.UserIDs {
+			if id == userID {
+				messages = append(messages, notification.Message)
+				break
+			}
+		}
+	}
+	return messages
+} 
\ No newline at end of file
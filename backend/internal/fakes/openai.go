@@ -0,0 +1,105 @@
+// Package fakes provides stand-ins for the backend's external dependencies,
+// for integration tests here and in downstream forks, with the apitest
+// package serving handlers built on them. An OpenAI server answers
+// embedding requests with deterministic vectors, a VectorStore replaces the
+// Pinecone index, and a Notifier records the notifications, queued or sent
+// over a WebSocket service, that would otherwise reach users.
+//
+// Services that take a store interface rather than the MongoDB client, such
+// as the inbox's services.NotificationStore, have an in-memory store here,
+// and a Broker stands in for Redis wherever jobs are queued or messages
+// published through a database.Broker. Services that still read and write
+// MongoDB or Redis directly need real, disposable instances of both.
+package fakes
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+	"neighborenexus/internal/services"
+)
+
+// EmbeddingDimensions matches the OpenAI model the embedding service uses
+const EmbeddingDimensions = 1536
+
+// Embedding returns a deterministic unit vector for text, so the same text
+// always embeds the same way and different texts are dissimilar
+func Embedding(text string) []float32 {
+	hash := fnv.New64a()
+	hash.Write([]byte(text))
+	rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	vector := make([]float32, EmbeddingDimensions)
+	var norm float64
+	for i := range vector {
+		value := rng.NormFloat64()
+		vector[i] = float32(value)
+		norm += value * value
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
+
+// OpenAIServer is a local HTTP server speaking enough of the OpenAI API to
+// generate embeddings
+type OpenAIServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	inputs []string
+}
+
+// NewOpenAIServer starts a fake OpenAI server; Close it when done
+func NewOpenAIServer() *OpenAIServer {
+	s := &OpenAIServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/embeddings", s.embeddings)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// EmbeddingService returns an embedding service backed by the server
+func (s *OpenAIServer) EmbeddingService() *services.EmbeddingService {
+	config := openai.DefaultConfig("fake")
+	config.BaseURL = s.URL + "/v1"
+	return services.NewEmbeddingServiceWithConfig(config)
+}
+
+// Inputs returns every text embedded so far, in order
+func (s *OpenAIServer) Inputs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.inputs...)
+}
+
+// embeddings answers an embeddings request with Embedding of each input
+func (s *OpenAIServer) embeddings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Input []string `json:"input"`
+		Model string   `json:"model"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+		http.Error(w, `{"error":{"message":"invalid embeddings request"}}`, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.inputs = append(s.inputs, req.Input...)
+	s.mu.Unlock()
+
+	resp := openai.EmbeddingResponse{Object: "list", Model: openai.AdaEmbeddingV2}
+	for i, input := range req.Input {
+		resp.Data = append(resp.Data, openai.Embedding{Object: "embedding", Embedding: Embedding(input), Index: i})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+} 
\ No newline at end of file
@@ -0,0 +1,38 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"neighborenexus/internal/push"
+)
+
+// PushedMessage is a push notification recorded by PushSender
+type PushedMessage struct {
+	Token   string
+	Message push.Message
+}
+
+// PushSender records push notifications instead of sending them. Use it
+// wherever a push.Sender is taken.
+type PushSender struct {
+	mu   sync.Mutex
+	sent []PushedMessage
+	// Err, when set, is returned from Send after recording the message
+	Err error
+}
+
+// Send records a push notification
+func (s *PushSender) Send(ctx context.Context, token string, msg push.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, PushedMessage{Token: token, Message: msg})
+	return s.Err
+}
+
+// Sent returns the notifications recorded so far, in order
+func (s *PushSender) Sent() []PushedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]PushedMessage(nil), s.sent...)
+} 
\ No newline at end of file
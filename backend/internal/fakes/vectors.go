@@ -0,0 +1,114 @@
+package fakes
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"neighborenexus/internal/vectors"
+)
+
+// VectorStore keeps vectors in memory and queries them by exhaustive cosine
+// similarity, as the Pinecone index does approximately. Use it wherever a
+// vectors.Store is taken.
+type VectorStore struct {
+	mu         sync.Mutex
+	namespaces map[string]map[string][]float32
+	// Err, when set, is returned from every call without changing the store
+	Err error
+}
+
+// NewVectorStore creates an empty vector store
+func NewVectorStore() *VectorStore {
+	return &VectorStore{namespaces: make(map[string]map[string][]float32)}
+}
+
+// Upsert stores vectors in namespace, replacing any with the same IDs
+func (s *VectorStore) Upsert(ctx context.Context, namespace string, vectors []vectors.Vector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+
+	stored, ok := s.namespaces[namespace]
+	if !ok {
+		stored = make(map[string][]float32)
+		s.namespaces[namespace] = stored
+	}
+	for _, vector := range vectors {
+		stored[vector.ID] = append([]float32(nil), vector.Values...)
+	}
+	return nil
+}
+
+// Query returns the topK vectors in namespace most similar to values, most
+// similar first, breaking ties by ID
+func (s *VectorStore) Query(ctx context.Context, namespace string, values []float32, topK int) ([]vectors.ScoredVector, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	results := make([]vectors.ScoredVector, 0, len(s.namespaces[namespace]))
+	for id, stored := range s.namespaces[namespace] {
+		results = append(results, vectors.ScoredVector{ID: id, Score: cosine(values, stored)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// Delete removes the vectors with the given IDs from namespace
+func (s *VectorStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+
+	for _, id := range ids {
+		delete(s.namespaces[namespace], id)
+	}
+	return nil
+}
+
+// IDs returns the IDs stored in namespace, sorted
+func (s *VectorStore) IDs(namespace string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.namespaces[namespace]))
+	for id := range s.namespaces[namespace] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// cosine returns the cosine similarity of two vectors, or zero when their
+// lengths differ or either is zero
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+} 
\ No newline at end of file
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys before issuing one query
+const batchWait = 2 * time.Millisecond
+
+// Loader batches and caches lookups by key for the lifetime of one request,
+// collapsing the N+1 queries produced by nested GraphQL selections
+type Loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mutex sync.Mutex
+	cache map[K]*loaderResult[V]
+	batch *loaderBatch[K, V]
+}
+
+// loaderResult holds the outcome of a single key lookup
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+// loaderBatch is the set of keys waiting to be fetched together
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+}
+
+// NewLoader creates a loader bound to a request context
+func NewLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:   ctx,
+		fetch: fetch,
+		cache: make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load returns the value for a key, waiting for the batch it joins to be fetched
+func (l *Loader[K, V]) Load(key K) (V, bool, error) {
+	l.mutex.Lock()
+	result, ok := l.cache[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.cache[key] = result
+
+		if l.batch == nil {
+			batch := &loaderBatch[K, V]{}
+			l.batch = batch
+			time.AfterFunc(batchWait, func() { l.dispatch(batch) })
+		}
+		l.batch.keys = append(l.batch.keys, key)
+		l.batch.results = append(l.batch.results, result)
+	}
+	l.mutex.Unlock()
+
+	<-result.done
+	return result.value, result.found, result.err
+}
+
+// dispatch fetches every key in a batch with a single call
+func (l *Loader[K, V]) dispatch(batch *loaderBatch[K, V]) {
+	l.mutex.Lock()
+	if l.batch == batch {
+		l.batch = nil
+	}
+	l.mutex.Unlock()
+
+	values, err := l.fetch(l.ctx, batch.keys)
+	for i, key := range batch.keys {
+		result := batch.results[i]
+		if err != nil {
+			result.err = err
+		} else {
+			result.value, result.found = values[key]
+		}
+		close(result.done)
+	}
+} 
\ No newline at end of file
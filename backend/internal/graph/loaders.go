@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// loaders holds the per-request dataloaders used by nested resolvers
+type loaders struct {
+	users              *Loader[primitive.ObjectID, models.User]
+	needs              *Loader[primitive.ObjectID, models.Need]
+	volunteers         *Loader[primitive.ObjectID, models.Volunteer]
+	volunteersByUserID *Loader[primitive.ObjectID, models.Volunteer]
+	tasksByNeedID      *Loader[primitive.ObjectID, []models.Task]
+	feedbackByTaskID   *Loader[primitive.ObjectID, []models.Feedback]
+}
+
+// newLoaders creates a fresh set of loaders for a request
+func newLoaders(ctx context.Context, mongoClient *database.MongoClient) *loaders {
+	return &loaders{
+		users: NewLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]models.User, error) {
+			var users []models.User
+			if err := findByField(ctx, mongoClient, "users", "_id", ids, &users); err != nil {
+				return nil, err
+			}
+			result := make(map[primitive.ObjectID]models.User, len(users))
+			for _, user := range users {
+				user.Password = ""
+				result[user.ID] = user
+			}
+			return result, nil
+		}),
+		needs: needsLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID, needs *[]models.Need) error {
+			return findByField(ctx, mongoClient, "needs", "_id", ids, needs)
+		}),
+		volunteers: NewLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]models.Volunteer, error) {
+			var volunteers []models.Volunteer
+			if err := findByField(ctx, mongoClient, "volunteers", "_id", ids, &volunteers); err != nil {
+				return nil, err
+			}
+			result := make(map[primitive.ObjectID]models.Volunteer, len(volunteers))
+			for _, volunteer := range volunteers {
+				result[volunteer.ID] = volunteer
+			}
+			return result, nil
+		}),
+		volunteersByUserID: NewLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]models.Volunteer, error) {
+			var volunteers []models.Volunteer
+			if err := findByField(ctx, mongoClient, "volunteers", "user_id", ids, &volunteers); err != nil {
+				return nil, err
+			}
+			result := make(map[primitive.ObjectID]models.Volunteer, len(volunteers))
+			for _, volunteer := range volunteers {
+				result[volunteer.UserID] = volunteer
+			}
+			return result, nil
+		}),
+		tasksByNeedID: NewLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID][]models.Task, error) {
+			var tasks []models.Task
+			if err := findByField(ctx, mongoClient, "tasks", "need_id", ids, &tasks); err != nil {
+				return nil, err
+			}
+			result := make(map[primitive.ObjectID][]models.Task)
+			for _, task := range tasks {
+				result[task.NeedID] = append(result[task.NeedID], task)
+			}
+			return result, nil
+		}),
+		feedbackByTaskID: NewLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID][]models.Feedback, error) {
+			var feedback []models.Feedback
+			if err := findByField(ctx, mongoClient, "feedback", "task_id", ids, &feedback); err != nil {
+				return nil, err
+			}
+			result := make(map[primitive.ObjectID][]models.Feedback)
+			for _, item := range feedback {
+				result[item.TaskID] = append(result[item.TaskID], item)
+			}
+			return result, nil
+		}),
+	}
+}
+
+// needsLoader creates the needs loader over find. Needs hidden by a
+// moderator or held for review load only for their creator, as the REST
+// API shows them, so need, Task.need, and Match.need resolve to null for
+// anyone else.
+func needsLoader(ctx context.Context, find func(ctx context.Context, ids []primitive.ObjectID, needs *[]models.Need) error) *Loader[primitive.ObjectID, models.Need] {
+	return NewLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]models.Need, error) {
+		var needs []models.Need
+		if err := find(ctx, ids, &needs); err != nil {
+			return nil, err
+		}
+		viewerID := viewerFromContext(ctx)
+		result := make(map[primitive.ObjectID]models.Need, len(needs))
+		for _, need := range needs {
+			if need.UserID != viewerID && (need.Hidden || need.HeldForReview) {
+				continue
+			}
+			result[need.ID] = need
+		}
+		return result, nil
+	})
+}
+
+// findByField loads every document in a collection whose field is in ids
+func findByField(ctx context.Context, mongoClient *database.MongoClient, collectionName, field string, ids []primitive.ObjectID, results interface{}) error {
+	collection := mongoClient.GetCollection(collectionName)
+	cursor, err := collection.Find(ctx, bson.M{field: bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+} 
\ No newline at end of file
@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// maxListSize caps list arguments such as needs(first:)
+const maxListSize = 100
+
+// Resolver is the root GraphQL resolver
+type Resolver struct {
+	matchingService *services.MatchingService
+	privacy         *services.PrivacyService
+	analytics       *services.AnalyticsService
+	mongoClient     *database.MongoClient
+}
+
+// Me resolves the authenticated user
+func (r *Resolver) Me(ctx context.Context) (*UserResolver, error) {
+	user, found, err := loadersFromContext(ctx).users.Load(viewerFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("user not found")
+	}
+	return &UserResolver{root: r, user: user}, nil
+}
+
+// Need resolves a single need by ID
+func (r *Resolver) Need(ctx context.Context, args struct{ ID graphql.ID }) (*NeedResolver, error) {
+	return r.loadNeed(ctx, args.ID)
+}
+
+// Needs resolves the open needs feed with optional filters
+func (r *Resolver) Needs(ctx context.Context, args struct {
+	Status   *string
+	Category *string
+	First    int32
+}) ([]*NeedResolver, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"expires_at": bson.M{"$exists": false}},
+			{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+		"hidden":          bson.M{"$ne": true},
+		"held_for_review": bson.M{"$ne": true},
+	}
+	if args.Status != nil && *args.Status != "" {
+		filter["status"] = *args.Status
+	}
+	if args.Category != nil && *args.Category != "" {
+		for key, value := range services.CategoryFilter(*args.Category) {
+			filter[key] = value
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(clampLimit(args.First, 20)))
+
+	var needs []models.Need
+	if err := r.find(ctx, "needs", filter, opts, &needs); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*NeedResolver, len(needs))
+	for i, need := range needs {
+		resolvers[i] = &NeedResolver{root: r, need: need}
+	}
+	return resolvers, nil
+}
+
+// Tasks resolves the tasks where the viewer is the volunteer or the need creator
+func (r *Resolver) Tasks(ctx context.Context) ([]*TaskResolver, error) {
+	viewerID := viewerFromContext(ctx)
+
+	var ownNeeds []models.Need
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	if err := r.find(ctx, "needs", bson.M{"user_id": viewerID}, opts, &ownNeeds); err != nil {
+		return nil, err
+	}
+	needIDs := make([]primitive.ObjectID, len(ownNeeds))
+	for i, need := range ownNeeds {
+		needIDs[i] = need.ID
+	}
+
+	filter := bson.M{
+		"$or": []bson.M{
+			{"volunteer_id": viewerID},
+			{"need_id": bson.M{"$in": needIDs}},
+		},
+	}
+
+	var tasks []models.Task
+	if err := r.find(ctx, "tasks", filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}), &tasks); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*TaskResolver, len(tasks))
+	for i, task := range tasks {
+		resolvers[i] = &TaskResolver{root: r, task: task}
+	}
+	return resolvers, nil
+}
+
+// Task resolves a single task the viewer participates in
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*TaskResolver, error) {
+	taskID, err := primitive.ObjectIDFromHex(string(args.ID))
+	if err != nil {
+		return nil, errors.New("invalid task ID")
+	}
+
+	var task models.Task
+	err = r.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{"_id": taskID}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resolver := &TaskResolver{root: r, task: task}
+	allowed, err := resolver.isParticipant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, nil
+	}
+	return resolver, nil
+}
+
+// Volunteer resolves a volunteer profile by ID
+func (r *Resolver) Volunteer(ctx context.Context, args struct{ ID graphql.ID }) (*VolunteerResolver, error) {
+	volunteerID, err := primitive.ObjectIDFromHex(string(args.ID))
+	if err != nil {
+		return nil, errors.New("invalid volunteer ID")
+	}
+
+	volunteer, found, err := loadersFromContext(ctx).volunteers.Load(volunteerID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &VolunteerResolver{root: r, volunteer: volunteer}, nil
+}
+
+// MyVolunteerProfile resolves the viewer's volunteer profile, if any
+func (r *Resolver) MyVolunteerProfile(ctx context.Context) (*VolunteerResolver, error) {
+	volunteer, found, err := loadersFromContext(ctx).volunteersByUserID.Load(viewerFromContext(ctx))
+	if err != nil || !found {
+		return nil, err
+	}
+	return &VolunteerResolver{root: r, volunteer: volunteer}, nil
+}
+
+// MyMatches resolves needs matching the viewer's volunteer profile
+func (r *Resolver) MyMatches(ctx context.Context, args struct{ Limit int32 }) ([]*MatchResolver, error) {
+	if r.matchingService == nil {
+		return nil, nil
+	}
+
+	volunteer, found, err := loadersFromContext(ctx).volunteersByUserID.Load(viewerFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("volunteer profile not found")
+	}
+
+	matches, err := r.matchingService.FindMatchesForVolunteer(ctx, &volunteer, clampLimit(args.Limit, 10))
+	if err != nil {
+		return nil, err
+	}
+	return newMatchResolvers(r, matches), nil
+}
+
+// loadNeed loads a need through the request's dataloader
+func (r *Resolver) loadNeed(ctx context.Context, id graphql.ID) (*NeedResolver, error) {
+	needID, err := primitive.ObjectIDFromHex(string(id))
+	if err != nil {
+		return nil, errors.New("invalid need ID")
+	}
+
+	need, found, err := loadersFromContext(ctx).needs.Load(needID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &NeedResolver{root: r, need: need}, nil
+}
+
+// find runs a query and decodes every result
+func (r *Resolver) find(ctx context.Context, collectionName string, filter interface{}, opts *options.FindOptions, results interface{}) error {
+	cursor, err := r.mongoClient.GetCollection(collectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+// clampLimit applies a default and upper bound to a list size argument
+func clampLimit(limit int32, defaultLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxListSize {
+		return maxListSize
+	}
+	return int(limit)
+} 
\ No newline at end of file
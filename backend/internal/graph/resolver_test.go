@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+)
+
+func TestNeedHiddenFromEveryoneButItsCreator(t *testing.T) {
+	creatorID := primitive.NewObjectID()
+	needs := map[primitive.ObjectID]models.Need{}
+	for _, need := range []models.Need{
+		{ID: primitive.NewObjectID(), UserID: creatorID, Title: "visible"},
+		{ID: primitive.NewObjectID(), UserID: creatorID, Title: "hidden", Hidden: true},
+		{ID: primitive.NewObjectID(), UserID: creatorID, Title: "held", HeldForReview: true},
+	} {
+		needs[need.ID] = need
+	}
+
+	resolve := func(viewerID primitive.ObjectID) map[string]bool {
+		ctx := context.WithValue(context.Background(), viewerKey, viewerID)
+		ctx = context.WithValue(ctx, loadersKey, &loaders{
+			needs: needsLoader(ctx, func(ctx context.Context, ids []primitive.ObjectID, found *[]models.Need) error {
+				for _, id := range ids {
+					*found = append(*found, needs[id])
+				}
+				return nil
+			}),
+		})
+
+		resolved := make(map[string]bool)
+		for id, need := range needs {
+			resolver, err := (&Resolver{}).Need(ctx, struct{ ID graphql.ID }{graphql.ID(id.Hex())})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolved[need.Title] = resolver != nil
+		}
+		return resolved
+	}
+
+	if got := resolve(creatorID); !got["visible"] || !got["hidden"] || !got["held"] {
+		t.Errorf("creator resolved %v, want every need", got)
+	}
+	if got := resolve(primitive.NewObjectID()); !got["visible"] || got["hidden"] || got["held"] {
+		t.Errorf("another user resolved %v, want only the visible need", got)
+	}
+} 
\ No newline at end of file
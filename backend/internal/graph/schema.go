@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/services"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+// maxQueryDepth bounds nested selections so a single query can't fan out unboundedly
+const maxQueryDepth = 8
+
+type contextKey string
+
+const (
+	viewerKey  contextKey = "graph_viewer"
+	loadersKey contextKey = "graph_loaders"
+)
+
+// NewSchema parses the GraphQL schema and binds it to the root resolver
+func NewSchema(matchingService *services.MatchingService, privacyService *services.PrivacyService, analyticsService *services.AnalyticsService, mongoClient *database.MongoClient) (*graphql.Schema, error) {
+	resolver := &Resolver{
+		matchingService: matchingService,
+		privacy:         privacyService,
+		analytics:       analyticsService,
+		mongoClient:     mongoClient,
+	}
+	return graphql.ParseSchema(schemaString, resolver, graphql.MaxDepth(maxQueryDepth))
+}
+
+// NewContext attaches the authenticated user and fresh dataloaders to a request context
+func NewContext(ctx context.Context, mongoClient *database.MongoClient, userID string) (context.Context, error) {
+	viewerID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	ctx = context.WithValue(ctx, viewerKey, viewerID)
+	ctx = context.WithValue(ctx, loadersKey, newLoaders(ctx, mongoClient))
+	return ctx, nil
+}
+
+// viewerFromContext returns the authenticated user's ID
+func viewerFromContext(ctx context.Context) primitive.ObjectID {
+	viewerID, _ := ctx.Value(viewerKey).(primitive.ObjectID)
+	return viewerID
+}
+
+// loadersFromContext returns the request's dataloaders
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+} 
\ No newline at end of file
@@ -0,0 +1,560 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+	"neighborenexus/internal/services"
+)
+
+// UserResolver resolves User fields
+type UserResolver struct {
+	root *Resolver
+	user models.User
+}
+
+func (r *UserResolver) ID() graphql.ID          { return graphql.ID(r.user.ID.Hex()) }
+func (r *UserResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.user.CreatedAt} }
+
+// Email is only visible to the user themselves
+func (r *UserResolver) Email(ctx context.Context) *string {
+	if !r.isViewer(ctx) {
+		return nil
+	}
+	return &r.user.Email
+}
+
+// Phone is only visible to the user themselves
+func (r *UserResolver) Phone(ctx context.Context) *string {
+	if !r.isViewer(ctx) || r.user.Phone == "" {
+		return nil
+	}
+	phone := string(r.user.Phone)
+	return &phone
+}
+
+// Name resolves the user's name as they chose to show it to other users
+func (r *UserResolver) Name(ctx context.Context) string {
+	if r.isViewer(ctx) {
+		return r.user.Name
+	}
+	return r.user.DisplayName()
+}
+
+// Location is only visible to the user themselves
+func (r *UserResolver) Location(ctx context.Context) *LocationResolver {
+	if !r.isViewer(ctx) {
+		return nil
+	}
+	return &LocationResolver{location: r.user.Location}
+}
+
+// VolunteerProfile resolves the user's volunteer profile, if any
+func (r *UserResolver) VolunteerProfile(ctx context.Context) (*VolunteerResolver, error) {
+	volunteer, found, err := loadersFromContext(ctx).volunteersByUserID.Load(r.user.ID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &VolunteerResolver{root: r.root, volunteer: volunteer}, nil
+}
+
+// Reputation resolves the user's reputation, unless they hide their rating
+// from others
+func (r *UserResolver) Reputation(ctx context.Context) *ReputationResolver {
+	if r.user.Reputation == nil || (!r.isViewer(ctx) && r.user.Privacy.HideRating) {
+		return nil
+	}
+	return &ReputationResolver{reputation: *r.user.Reputation}
+}
+
+func (r *UserResolver) isViewer(ctx context.Context) bool {
+	return r.user.ID == viewerFromContext(ctx)
+}
+
+// ReputationResolver resolves Reputation fields
+type ReputationResolver struct {
+	reputation models.Reputation
+}
+
+func (r *ReputationResolver) Score() float64            { return r.reputation.Score }
+func (r *ReputationResolver) Average() float64          { return r.reputation.Average }
+func (r *ReputationResolver) Count() int32              { return int32(r.reputation.Count) }
+func (r *ReputationResolver) ClosedTasks() int32        { return int32(r.reputation.ClosedTasks) }
+func (r *ReputationResolver) CompletionRate() float64   { return r.reputation.CompletionRate }
+func (r *ReputationResolver) CancellationRate() float64 { return r.reputation.CancellationRate }
+
+// LocationResolver resolves Location fields
+type LocationResolver struct {
+	location models.Location
+}
+
+func (r *LocationResolver) Latitude() float64  { return r.location.Latitude }
+func (r *LocationResolver) Longitude() float64 { return r.location.Longitude }
+func (r *LocationResolver) H3Index() string    { return r.location.H3Index }
+func (r *LocationResolver) Approximate() bool  { return r.location.Approximate }
+
+// DistanceResolver resolves Distance fields
+type DistanceResolver struct {
+	distance models.Distance
+}
+
+func (r *DistanceResolver) Value() float64 { return r.distance.Value }
+func (r *DistanceResolver) Unit() string   { return r.distance.Unit }
+func (r *DistanceResolver) Text() string   { return r.distance.Text }
+
+// NeedResolver resolves Need fields
+type NeedResolver struct {
+	root *Resolver
+	need models.Need
+}
+
+func (r *NeedResolver) ID() graphql.ID           { return graphql.ID(r.need.ID.Hex()) }
+func (r *NeedResolver) Category() string         { return string(r.need.Category) }
+func (r *NeedResolver) Urgency() string          { return string(r.need.Urgency) }
+func (r *NeedResolver) Duration() int32          { return int32(r.need.Duration) }
+func (r *NeedResolver) Status() string           { return string(r.need.Status) }
+func (r *NeedResolver) Tags() []string           { return nonNilStrings(r.need.Tags) }
+func (r *NeedResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.need.CreatedAt} }
+func (r *NeedResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.need.UpdatedAt} }
+func (r *NeedResolver) ExpiresAt() *graphql.Time { return optionalTime(r.need.ExpiresAt) }
+
+// MinTrust resolves the lowest trust level the need is limited to, if any
+func (r *NeedResolver) MinTrust() *string {
+	if r.need.MinTrust == "" {
+		return nil
+	}
+	level := string(r.need.MinTrust)
+	return &level
+}
+
+// Subcategory resolves the need's subcategory path, if it has one
+func (r *NeedResolver) Subcategory() *string {
+	if r.need.Subcategory == "" {
+		return nil
+	}
+	return &r.need.Subcategory
+}
+
+// Title resolves the need's title, with contact details redacted unless the
+// viewer is a participant
+func (r *NeedResolver) Title(ctx context.Context) (string, error) {
+	revealed, err := r.revealed(ctx)
+	if err != nil || revealed {
+		return r.need.Title, err
+	}
+	return sanitize.RedactContacts(r.need.Title), nil
+}
+
+// Description resolves the need's description, with contact details
+// redacted unless the viewer is a participant
+func (r *NeedResolver) Description(ctx context.Context) (string, error) {
+	revealed, err := r.revealed(ctx)
+	if err != nil || revealed {
+		return r.need.Description, err
+	}
+	return sanitize.RedactContacts(r.need.Description), nil
+}
+
+// Location resolves the need's location, exact only for its creator and for a
+// volunteer whose task on it has been accepted
+func (r *NeedResolver) Location(ctx context.Context) (*LocationResolver, error) {
+	revealed, err := r.revealed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if revealed {
+		return &LocationResolver{location: r.need.Location}, nil
+	}
+	return &LocationResolver{location: r.root.privacy.ApproximateLocation(r.need.Location)}, nil
+}
+
+// revealed reports whether the viewer is the need's creator or a volunteer
+// whose task on it has been accepted
+func (r *NeedResolver) revealed(ctx context.Context) (bool, error) {
+	viewerID := viewerFromContext(ctx)
+	if r.need.UserID == viewerID {
+		return true, nil
+	}
+
+	tasks, _, err := loadersFromContext(ctx).tasksByNeedID.Load(r.need.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, task := range tasks {
+		if task.VolunteerID == viewerID && services.RevealsLocation(task.Status) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// User resolves the need creator
+func (r *NeedResolver) User(ctx context.Context) (*UserResolver, error) {
+	return loadUser(ctx, r.root, r.need.UserID)
+}
+
+// Tasks resolves the tasks created for the need, visible only to its creator
+func (r *NeedResolver) Tasks(ctx context.Context) ([]*TaskResolver, error) {
+	if r.need.UserID != viewerFromContext(ctx) {
+		return []*TaskResolver{}, nil
+	}
+
+	tasks, _, err := loadersFromContext(ctx).tasksByNeedID.Load(r.need.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*TaskResolver, len(tasks))
+	for i, task := range tasks {
+		resolvers[i] = &TaskResolver{root: r.root, task: task}
+	}
+	return resolvers, nil
+}
+
+// Matches resolves candidate volunteers for the need, visible only to its creator
+func (r *NeedResolver) Matches(ctx context.Context, args struct{ Limit int32 }) ([]*MatchResolver, error) {
+	if r.root.matchingService == nil || r.need.UserID != viewerFromContext(ctx) {
+		return []*MatchResolver{}, nil
+	}
+
+	matches, err := r.root.matchingService.FindMatchesForNeed(ctx, &r.need, clampLimit(args.Limit, 5))
+	if err != nil {
+		return nil, err
+	}
+
+	// Volunteers hidden from search still hear about the need, but are not listed
+	visible := matches[:0]
+	for _, match := range matches {
+		volunteer, found, err := loadersFromContext(ctx).volunteers.Load(match.VolunteerID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		user, found, err := loadersFromContext(ctx).users.Load(volunteer.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if found && !user.Privacy.HideFromSearch {
+			visible = append(visible, match)
+		}
+	}
+	r.root.analytics.MatchesShown(ctx, &r.need, visible, services.MatchSurfaceGraphQL)
+	return newMatchResolvers(r.root, visible), nil
+}
+
+// VolunteerResolver resolves Volunteer fields
+type VolunteerResolver struct {
+	root      *Resolver
+	volunteer models.Volunteer
+}
+
+func (r *VolunteerResolver) ID() graphql.ID      { return graphql.ID(r.volunteer.ID.Hex()) }
+func (r *VolunteerResolver) Skills() []string    { return nonNilStrings(r.volunteer.Skills) }
+func (r *VolunteerResolver) Interests() []string { return nonNilStrings(r.volunteer.Interests) }
+func (r *VolunteerResolver) TaskCount() int32    { return int32(r.volunteer.TaskCount) }
+func (r *VolunteerResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.volunteer.CreatedAt}
+}
+func (r *VolunteerResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.volunteer.UpdatedAt}
+}
+
+// Description resolves the volunteer's description, with contact details
+// redacted unless the viewer shares an accepted task with them
+func (r *VolunteerResolver) Description(ctx context.Context) (string, error) {
+	shared, err := r.root.privacy.SharesTask(ctx, viewerFromContext(ctx), r.volunteer.UserID)
+	if err != nil || shared {
+		return r.volunteer.Description, err
+	}
+	return sanitize.RedactContacts(r.volunteer.Description), nil
+}
+
+// Location resolves the volunteer's location, exact only for the volunteer
+// and for users they share an accepted task with
+func (r *VolunteerResolver) Location(ctx context.Context) (*LocationResolver, error) {
+	shared, err := r.root.privacy.SharesTask(ctx, viewerFromContext(ctx), r.volunteer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		return &LocationResolver{location: r.volunteer.Location}, nil
+	}
+	return &LocationResolver{location: r.root.privacy.ApproximateLocation(r.volunteer.Location)}, nil
+}
+
+// Rating resolves the volunteer's rating, unless they hide it from others
+func (r *VolunteerResolver) Rating(ctx context.Context) (*float64, error) {
+	if r.volunteer.UserID != viewerFromContext(ctx) {
+		user, found, err := loadersFromContext(ctx).users.Load(r.volunteer.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if found && user.Privacy.HideRating {
+			return nil, nil
+		}
+	}
+	return &r.volunteer.Rating, nil
+}
+
+// Kudos resolves the tally of thank-you notes the volunteer has received
+func (r *VolunteerResolver) Kudos() *KudosSummaryResolver {
+	if r.volunteer.Kudos == nil {
+		return nil
+	}
+	return &KudosSummaryResolver{summary: *r.volunteer.Kudos}
+}
+
+// KudosSummaryResolver resolves KudosSummary fields
+type KudosSummaryResolver struct {
+	summary models.KudosSummary
+}
+
+func (r *KudosSummaryResolver) Count() int32 { return int32(r.summary.Count) }
+
+// Tags resolves the tag counts, most frequent first
+func (r *KudosSummaryResolver) Tags() []*KudosTagCountResolver {
+	resolvers := make([]*KudosTagCountResolver, 0, len(r.summary.Tags))
+	for tag, count := range r.summary.Tags {
+		resolvers = append(resolvers, &KudosTagCountResolver{tag: tag, count: count})
+	}
+	sort.Slice(resolvers, func(i, j int) bool {
+		if resolvers[i].count != resolvers[j].count {
+			return resolvers[i].count > resolvers[j].count
+		}
+		return resolvers[i].tag < resolvers[j].tag
+	})
+	return resolvers
+}
+
+// KudosTagCountResolver resolves KudosTagCount fields
+type KudosTagCountResolver struct {
+	tag   models.KudosTag
+	count int
+}
+
+func (r *KudosTagCountResolver) Tag() string  { return string(r.tag) }
+func (r *KudosTagCountResolver) Count() int32 { return int32(r.count) }
+
+// Availability resolves the volunteer's weekly availability windows
+func (r *VolunteerResolver) Availability() []*AvailabilityResolver {
+	resolvers := make([]*AvailabilityResolver, len(r.volunteer.Availability))
+	for i, availability := range r.volunteer.Availability {
+		resolvers[i] = &AvailabilityResolver{availability: availability}
+	}
+	return resolvers
+}
+
+// User resolves the account behind the volunteer profile
+func (r *VolunteerResolver) User(ctx context.Context) (*UserResolver, error) {
+	return loadUser(ctx, r.root, r.volunteer.UserID)
+}
+
+// AvailabilityResolver resolves Availability fields
+type AvailabilityResolver struct {
+	availability models.Availability
+}
+
+func (r *AvailabilityResolver) DayOfWeek() int32  { return int32(r.availability.DayOfWeek) }
+func (r *AvailabilityResolver) StartTime() string { return r.availability.StartTime }
+func (r *AvailabilityResolver) EndTime() string   { return r.availability.EndTime }
+
+// TaskResolver resolves Task fields
+type TaskResolver struct {
+	root *Resolver
+	task models.Task
+}
+
+func (r *TaskResolver) ID() graphql.ID             { return graphql.ID(r.task.ID.Hex()) }
+func (r *TaskResolver) Status() string             { return string(r.task.Status) }
+func (r *TaskResolver) ScheduledAt() *graphql.Time { return optionalTime(r.task.ScheduledAt) }
+func (r *TaskResolver) CompletedAt() *graphql.Time { return optionalTime(r.task.CompletedAt) }
+func (r *TaskResolver) CreatedAt() graphql.Time    { return graphql.Time{Time: r.task.CreatedAt} }
+func (r *TaskResolver) UpdatedAt() graphql.Time    { return graphql.Time{Time: r.task.UpdatedAt} }
+
+// Notes resolves the task notes
+func (r *TaskResolver) Notes() *string {
+	if r.task.Notes == "" {
+		return nil
+	}
+	return &r.task.Notes
+}
+
+// Need resolves the need the task fulfils
+func (r *TaskResolver) Need(ctx context.Context) (*NeedResolver, error) {
+	return r.root.loadNeed(ctx, graphql.ID(r.task.NeedID.Hex()))
+}
+
+// Volunteer resolves the user who accepted the need
+func (r *TaskResolver) Volunteer(ctx context.Context) (*UserResolver, error) {
+	return loadUser(ctx, r.root, r.task.VolunteerID)
+}
+
+// Feedback resolves feedback left on the task, visible only to
+// participants, and to its recipient only once revealed
+func (r *TaskResolver) Feedback(ctx context.Context) ([]*FeedbackResolver, error) {
+	allowed, err := r.isParticipant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return []*FeedbackResolver{}, nil
+	}
+
+	feedback, _, err := loadersFromContext(ctx).feedbackByTaskID.Load(r.task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerID := viewerFromContext(ctx)
+	resolvers := make([]*FeedbackResolver, 0, len(feedback))
+	for _, item := range feedback {
+		if item.FromUserID == viewerID || (item.RevealedAt != nil && !item.Hidden) {
+			resolvers = append(resolvers, &FeedbackResolver{root: r.root, feedback: item})
+		}
+	}
+	return resolvers, nil
+}
+
+// isParticipant reports whether the viewer is the task's volunteer or the need creator
+func (r *TaskResolver) isParticipant(ctx context.Context) (bool, error) {
+	viewerID := viewerFromContext(ctx)
+	if r.task.VolunteerID == viewerID {
+		return true, nil
+	}
+
+	need, found, err := loadersFromContext(ctx).needs.Load(r.task.NeedID)
+	if err != nil {
+		return false, err
+	}
+	return found && need.UserID == viewerID, nil
+}
+
+// FeedbackResolver resolves Feedback fields
+type FeedbackResolver struct {
+	root     *Resolver
+	feedback models.Feedback
+}
+
+func (r *FeedbackResolver) ID() graphql.ID          { return graphql.ID(r.feedback.ID.Hex()) }
+func (r *FeedbackResolver) Rating() int32           { return int32(r.feedback.Rating) }
+func (r *FeedbackResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.feedback.CreatedAt} }
+
+// Comment resolves the optional feedback comment
+func (r *FeedbackResolver) Comment() *string {
+	if r.feedback.Comment == "" {
+		return nil
+	}
+	return &r.feedback.Comment
+}
+
+// From resolves the feedback author
+func (r *FeedbackResolver) From(ctx context.Context) (*UserResolver, error) {
+	return loadUser(ctx, r.root, r.feedback.FromUserID)
+}
+
+// To resolves the feedback recipient
+func (r *FeedbackResolver) To(ctx context.Context) (*UserResolver, error) {
+	return loadUser(ctx, r.root, r.feedback.ToUserID)
+}
+
+// Response resolves the recipient's reply, if any
+func (r *FeedbackResolver) Response() *FeedbackResponseResolver {
+	if r.feedback.Response == nil {
+		return nil
+	}
+	return &FeedbackResponseResolver{response: *r.feedback.Response}
+}
+
+// FeedbackResponseResolver resolves FeedbackResponse fields
+type FeedbackResponseResolver struct {
+	response models.FeedbackResponse
+}
+
+func (r *FeedbackResponseResolver) Text() string { return r.response.Text }
+func (r *FeedbackResponseResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.response.CreatedAt}
+}
+
+// MatchResolver resolves Match fields
+type MatchResolver struct {
+	root  *Resolver
+	match models.Match
+}
+
+func (r *MatchResolver) Score() float64    { return r.match.Score }
+func (r *MatchResolver) Distance() float64 { return r.match.Distance }
+func (r *MatchResolver) Trust() float64    { return r.match.Trust }
+
+// DistanceDisplay resolves the distance in the viewer's preferred units
+func (r *MatchResolver) DistanceDisplay(ctx context.Context) (*DistanceResolver, error) {
+	viewer, _, err := loadersFromContext(ctx).users.Load(viewerFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &DistanceResolver{distance: *services.LocalizeDistance(r.match.Distance, viewer.Units, viewer.Language)}, nil
+}
+
+// TrustLevel resolves the volunteer's trust level, if scored
+func (r *MatchResolver) TrustLevel() *string {
+	if r.match.TrustLevel == "" {
+		return nil
+	}
+	level := string(r.match.TrustLevel)
+	return &level
+}
+func (r *MatchResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.match.CreatedAt} }
+
+// Need resolves the matched need
+func (r *MatchResolver) Need(ctx context.Context) (*NeedResolver, error) {
+	return r.root.loadNeed(ctx, graphql.ID(r.match.NeedID.Hex()))
+}
+
+// Volunteer resolves the matched volunteer profile
+func (r *MatchResolver) Volunteer(ctx context.Context) (*VolunteerResolver, error) {
+	volunteer, found, err := loadersFromContext(ctx).volunteers.Load(r.match.VolunteerID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &VolunteerResolver{root: r.root, volunteer: volunteer}, nil
+}
+
+// newMatchResolvers wraps computed matches
+func newMatchResolvers(root *Resolver, matches []models.Match) []*MatchResolver {
+	resolvers := make([]*MatchResolver, len(matches))
+	for i, match := range matches {
+		resolvers[i] = &MatchResolver{root: root, match: match}
+	}
+	return resolvers
+}
+
+// loadUser loads a user through the request's dataloader
+func loadUser(ctx context.Context, root *Resolver, userID primitive.ObjectID) (*UserResolver, error) {
+	user, found, err := loadersFromContext(ctx).users.Load(userID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &UserResolver{root: root, user: user}, nil
+}
+
+// optionalTime converts a nullable timestamp
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+// nonNilStrings ensures non-null list fields never resolve to null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+} 
\ No newline at end of file
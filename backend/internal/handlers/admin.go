@@ -0,0 +1,571 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+	"neighborenexus/internal/services"
+)
+
+// Admin list defaults
+const (
+	adminDefaultLimit = 50
+	adminMaxLimit     = 200
+)
+
+// AdminHandler handles admin and moderator requests across all users' data
+type AdminHandler struct {
+	mongoClient  *database.MongoClient
+	auditService *services.AuditService
+	trustService *services.TrustService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(mongoClient *database.MongoClient, auditService *services.AuditService, trustService *services.TrustService) *AdminHandler {
+	return &AdminHandler{
+		mongoClient:  mongoClient,
+		auditService: auditService,
+		trustService: trustService,
+	}
+}
+
+// ListUsers lists and searches users by name or email
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	filter := bson.M{}
+	if q := c.Query("q"); q != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q), Options: "i"}
+		filter["$or"] = []bson.M{
+			{"name": pattern},
+			{"email": pattern},
+		}
+	}
+	if role := c.Query("role"); role != "" {
+		filter["role"] = role
+	}
+
+	var users []models.User
+	total, err := h.findPage(c, "users", filter, &users)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve users"})
+		return
+	}
+
+	for i := range users {
+		users[i].Password = ""
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users, "total": total})
+}
+
+// GetUser retrieves any user with their volunteer profile and activity counts
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var user models.User
+	err = h.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		return
+	}
+	user.Password = ""
+
+	response := gin.H{"user": user}
+
+	var volunteer models.Volunteer
+	err = h.mongoClient.GetCollection("volunteers").FindOne(ctx, bson.M{"user_id": objectID}).Decode(&volunteer)
+	if err == nil {
+		response["volunteer"] = volunteer
+	}
+
+	needCount, err := h.mongoClient.GetCollection("needs").CountDocuments(ctx, bson.M{"user_id": objectID})
+	if err == nil {
+		response["need_count"] = needCount
+	}
+	taskCount, err := h.mongoClient.GetCollection("tasks").CountDocuments(ctx, bson.M{"volunteer_id": objectID})
+	if err == nil {
+		response["task_count"] = taskCount
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateUserRole changes a user's role
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required,oneof=user moderator admin"`
+	}
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	var previous models.User
+	err = h.mongoClient.GetCollection("users").FindOneAndUpdate(
+		c.Request.Context(),
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"role": req.Role, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetProjection(bson.M{"role": 1}),
+	).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user role"})
+		return
+	}
+
+	recordAuditChange(c, h.auditService, models.AuditRoleChanged, models.AuditTargetUser, &objectID,
+		map[string]interface{}{"role": previous.Role}, map[string]interface{}{"role": req.Role})
+	c.JSON(http.StatusOK, gin.H{"message": "User role updated successfully"})
+}
+
+// UpdateSupervision verifies or revokes the supervising adult of a youth
+// group account, which cannot post or accept needs until verified
+func (h *AdminHandler) UpdateSupervision(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateSupervisionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	update := bson.M{"$unset": bson.M{"supervision.verified_by": "", "supervision.verified_at": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	if req.Verified {
+		update = bson.M{"$set": bson.M{
+			"supervision.verified_by": admin.ID,
+			"supervision.verified_at": time.Now(),
+			"updated_at":              time.Now(),
+		}}
+	}
+
+	result, err := h.mongoClient.GetCollection("users").UpdateOne(
+		c.Request.Context(),
+		bson.M{"_id": objectID, "account_type": models.AccountYouthGroup, "supervision": bson.M{"$exists": true}},
+		update,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update supervision"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Youth group account not found"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditSupervisionUpdated, models.AuditTargetUser, &objectID, map[string]interface{}{"verified": req.Verified})
+	c.JSON(http.StatusOK, gin.H{"message": "Supervision updated successfully"})
+}
+
+// UpdateOrganizer verifies or revokes a user as a community organizer, who
+// may post to neighborhood feeds
+func (h *AdminHandler) UpdateOrganizer(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateOrganizerRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	update := bson.M{"$unset": bson.M{"organizer": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	if req.Verified {
+		update = bson.M{"$set": bson.M{
+			"organizer":  models.OrganizerVerification{VerifiedBy: admin.ID, VerifiedAt: time.Now()},
+			"updated_at": time.Now(),
+		}}
+	}
+
+	result, err := h.mongoClient.GetCollection("users").UpdateOne(c.Request.Context(), bson.M{"_id": objectID}, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organizer"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditOrganizerUpdated, models.AuditTargetUser, &objectID, map[string]interface{}{"verified": req.Verified})
+	c.JSON(http.StatusOK, gin.H{"message": "Organizer updated successfully"})
+}
+
+// UpdateIdentity verifies or revokes a user's identity, which counts toward
+// their trust score
+func (h *AdminHandler) UpdateIdentity(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateIdentityRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	update := bson.M{"$unset": bson.M{"identity": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	if req.Verified {
+		update = bson.M{"$set": bson.M{
+			"identity":   models.IdentityVerification{VerifiedBy: admin.ID, VerifiedAt: time.Now()},
+			"updated_at": time.Now(),
+		}}
+	}
+
+	result, err := h.mongoClient.GetCollection("users").UpdateOne(c.Request.Context(), bson.M{"_id": objectID}, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update identity"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditIdentityUpdated, models.AuditTargetUser, &objectID, map[string]interface{}{"verified": req.Verified})
+	trust, err := h.trustService.Refresh(c.Request.Context(), objectID)
+	if err != nil {
+		log.Printf("Failed to refresh trust score for %s: %v", objectID.Hex(), err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Identity updated successfully", "trust": trust})
+}
+
+// SuspendUser locks a user out of the API. Moderators cannot suspend other
+// moderators or admins, and no one can suspend themselves.
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.SuspendUserRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	moderator, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if objectID == moderator.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot suspend your own account"})
+		return
+	}
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+		return
+	}
+
+	filter := bson.M{"_id": objectID}
+	if !moderator.HasRole(models.RoleAdmin) {
+		filter["role"] = bson.M{"$nin": []string{models.RoleModerator, models.RoleAdmin}}
+	}
+	suspension := models.AccountSuspension{
+		Reason:      sanitize.Text(req.Reason),
+		SuspendedBy: moderator.ID,
+		SuspendedAt: time.Now(),
+		Until:       req.Until,
+	}
+	result, err := h.mongoClient.GetCollection("users").UpdateOne(c.Request.Context(), filter,
+		bson.M{"$set": bson.M{"account_suspension": suspension, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suspend user"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	details := map[string]interface{}{"reason": suspension.Reason}
+	if req.Until != nil {
+		details["until"] = *req.Until
+	}
+	recordAudit(c, h.auditService, models.AuditUserSuspended, models.AuditTargetUser, &objectID, details)
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended successfully", "suspension": suspension})
+}
+
+// ReinstateUser lifts a user's suspension
+func (h *AdminHandler) ReinstateUser(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	result, err := h.mongoClient.GetCollection("users").UpdateOne(c.Request.Context(),
+		bson.M{"_id": objectID, "account_suspension": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"account_suspension": ""}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reinstate user"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Suspended user not found"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditUserReinstated, models.AuditTargetUser, &objectID, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "User reinstated successfully"})
+}
+
+// ListNeeds lists and searches needs across all users, including expired ones
+func (h *AdminHandler) ListNeeds(c *gin.Context) {
+	filter := bson.M{}
+	if q := c.Query("q"); q != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q), Options: "i"}
+		filter["$or"] = []bson.M{
+			{"title": pattern},
+			{"description": pattern},
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if category := c.Query("category"); category != "" {
+		filter["category"] = category
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		objectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter["user_id"] = objectID
+	}
+
+	var needs []models.Need
+	total, err := h.findPage(c, "needs", filter, &needs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve needs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"needs": needs, "total": total})
+}
+
+// DeleteNeed removes any need, e.g. abusive or spam posts
+func (h *AdminHandler) DeleteNeed(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+
+	var need models.Need
+	err = h.mongoClient.GetCollection("needs").FindOneAndDelete(c.Request.Context(), bson.M{"_id": objectID},
+		options.FindOneAndDelete().SetProjection(needSnapshotProjection)).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete need"})
+		return
+	}
+
+	recordAuditChange(c, h.auditService, models.AuditNeedDeleted, models.AuditTargetNeed, &objectID, needSnapshot(need), nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Need deleted successfully"})
+}
+
+// ListTasks lists tasks across all users
+func (h *AdminHandler) ListTasks(c *gin.Context) {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	for _, field := range []string{"need_id", "volunteer_id"} {
+		if value := c.Query(field); value != "" {
+			objectID, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + field})
+				return
+			}
+			filter[field] = objectID
+		}
+	}
+
+	var tasks []models.Task
+	total, err := h.findPage(c, "tasks", filter, &tasks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks, "total": total})
+}
+
+// ListReports lists low-rated feedback, the platform's signal of problem interactions
+func (h *AdminHandler) ListReports(c *gin.Context) {
+	maxRating := 2
+	if value := c.Query("max_rating"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_rating must be between 1 and 5"})
+			return
+		}
+		maxRating = parsed
+	}
+
+	filter := bson.M{"rating": bson.M{"$lte": maxRating}}
+	if userID := c.Query("user_id"); userID != "" {
+		objectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter["to_user_id"] = objectID
+	}
+
+	var feedback []models.Feedback
+	total, err := h.findPage(c, "feedback", filter, &feedback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": feedback, "total": total})
+}
+
+// GetMetrics returns platform-wide document counts
+func (h *AdminHandler) GetMetrics(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	users, err := h.mongoClient.GetCollection("users").CountDocuments(ctx, bson.M{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute metrics"})
+		return
+	}
+	volunteers, err := h.mongoClient.GetCollection("volunteers").CountDocuments(ctx, bson.M{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute metrics"})
+		return
+	}
+	needsByStatus, err := h.countByStatus(ctx, "needs")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute metrics"})
+		return
+	}
+	tasksByStatus, err := h.countByStatus(ctx, "tasks")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute metrics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":           users,
+		"volunteers":      volunteers,
+		"needs_by_status": needsByStatus,
+		"tasks_by_status": tasksByStatus,
+	})
+}
+
+// countByStatus groups a collection's documents by status
+func (h *AdminHandler) countByStatus(ctx context.Context, collectionName string) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := h.mongoClient.GetCollection(collectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(results))
+	for _, result := range results {
+		counts[result.Status] = result.Count
+	}
+	return counts, nil
+}
+
+// findPage runs a newest-first query honoring ?limit= and ?offset= and returns the total match count
+func (h *AdminHandler) findPage(c *gin.Context, collectionName string, filter bson.M, results interface{}) (int64, error) {
+	limit := adminDefaultLimit
+	if value, err := strconv.Atoi(c.Query("limit")); err == nil && value > 0 {
+		limit = value
+	}
+	if limit > adminMaxLimit {
+		limit = adminMaxLimit
+	}
+	offset := 0
+	if value, err := strconv.Atoi(c.Query("offset")); err == nil && value > 0 {
+		offset = value
+	}
+
+	ctx := c.Request.Context()
+	collection := h.mongoClient.GetCollection(collectionName)
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	return total, cursor.All(ctx, results)
+} 
\ No newline at end of file
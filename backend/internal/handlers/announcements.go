@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// AnnouncementHandler handles admin announcements and the user feed
+type AnnouncementHandler struct {
+	announcementService *services.AnnouncementService
+	auditService        *services.AuditService
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(announcementService *services.AnnouncementService, auditService *services.AuditService) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+		auditService:        auditService,
+	}
+}
+
+// CreateAnnouncement schedules an announcement for its target audience
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	adminID, err := primitive.ObjectIDFromHex(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.CreateAnnouncementRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	announcement, err := h.announcementService.Create(c.Request.Context(), adminID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create announcement"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditAnnouncementCreated, models.AuditTargetAnnouncement, &announcement.ID, map[string]interface{}{"title": announcement.Title})
+	c.JSON(http.StatusCreated, gin.H{"announcement": announcement})
+}
+
+// ListAnnouncements lists announcements, optionally filtered by ?status=
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	filter := bson.M{}
+	if status := models.AnnouncementStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		filter["status"] = status
+	}
+
+	announcements, err := h.announcementService.List(c.Request.Context(), filter, adminMaxLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve announcements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements})
+}
+
+// CancelAnnouncement withdraws a scheduled or published announcement
+func (h *AnnouncementHandler) CancelAnnouncement(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid announcement ID"})
+		return
+	}
+
+	if err := h.announcementService.Cancel(c.Request.Context(), id); err != nil {
+		if errors.Is(err, services.ErrAnnouncementNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel announcement"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditAnnouncementCancelled, models.AuditTargetAnnouncement, &id, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement cancelled"})
+}
+
+// GetMyAnnouncements lists the active announcements targeted at the current user
+func (h *AnnouncementHandler) GetMyAnnouncements(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	announcements, err := h.announcementService.ForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve announcements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements})
+} 
\ No newline at end of file
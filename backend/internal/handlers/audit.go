@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// auditExportMax caps the number of entries in one export
+const auditExportMax = 50000
+
+// AuditHandler lets admins search and export the audit log
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEntries searches the audit log newest first. Filters: ?user_id= for
+// entries by or about a user, ?actor_id=, ?target_type=, ?target_id=,
+// ?action= (comma-separated), and ?from=/?to= as RFC 3339 timestamps.
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	query, ok := parseAuditQuery(c)
+	if !ok {
+		return
+	}
+
+	limit := adminDefaultLimit
+	if value, err := strconv.Atoi(c.Query("limit")); err == nil && value > 0 && value <= adminMaxLimit {
+		limit = value
+	}
+	offset := 0
+	if value, err := strconv.Atoi(c.Query("offset")); err == nil && value > 0 {
+		offset = value
+	}
+
+	entries, total, err := h.auditService.Query(c.Request.Context(), query, int64(offset), int64(limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "total": total})
+}
+
+// ExportEntries downloads matching entries oldest first as CSV, or as
+// newline-delimited JSON with ?format=ndjson. Takes the same filters as
+// ListEntries.
+func (h *AuditHandler) ExportEntries(c *gin.Context) {
+	query, ok := parseAuditQuery(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or ndjson"})
+		return
+	}
+
+	filename := "audit-" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	var write func(models.AuditEntry) error
+	var flush func() error
+	if format == "ndjson" {
+		c.Header("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(c.Writer)
+		write = func(entry models.AuditEntry) error { return encoder.Encode(entry) }
+		flush = func() error { return nil }
+	} else {
+		c.Header("Content-Type", "text/csv")
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write([]string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "ip", "user_agent", "details", "before", "after"}); err != nil {
+			return
+		}
+		write = func(entry models.AuditEntry) error { return writer.Write(auditCSVRow(entry)) }
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	c.Status(http.StatusOK)
+	err := h.auditService.Export(c.Request.Context(), query, auditExportMax, write)
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		// Headers are already sent, so the truncated download is the only signal
+		c.Error(err)
+	}
+}
+
+// parseAuditQuery reads the audit filters from the query string, writing a
+// 400 response and returning false when one is malformed
+func parseAuditQuery(c *gin.Context) (services.AuditQuery, bool) {
+	var query services.AuditQuery
+
+	for param, dest := range map[string]**primitive.ObjectID{
+		"user_id":   &query.UserID,
+		"actor_id":  &query.ActorID,
+		"target_id": &query.TargetID,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return query, false
+		}
+		*dest = &id
+	}
+
+	query.TargetType = c.Query("target_type")
+	for _, action := range strings.Split(c.Query("action"), ",") {
+		if action = strings.TrimSpace(action); action != "" {
+			query.Actions = append(query.Actions, action)
+		}
+	}
+
+	for param, dest := range map[string]*time.Time{
+		"from": &query.From,
+		"to":   &query.To,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC 3339 timestamp"})
+			return query, false
+		}
+		*dest = t
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.To.After(query.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return query, false
+	}
+
+	return query, true
+}
+
+// auditCSVRow flattens an entry into export columns
+func auditCSVRow(entry models.AuditEntry) []string {
+	row := []string{entry.ID.Hex(), entry.CreatedAt.UTC().Format(time.RFC3339), "", entry.Action, entry.TargetType, "", entry.IP, entry.UserAgent,
+		auditCSVMap(entry.Details), auditCSVMap(entry.Before), auditCSVMap(entry.After)}
+	if entry.ActorID != nil {
+		row[2] = entry.ActorID.Hex()
+	}
+	if entry.TargetID != nil {
+		row[5] = entry.TargetID.Hex()
+	}
+	return row
+}
+
+// auditCSVMap flattens an entry's details or snapshot into one column as
+// JSON
+func auditCSVMap(values map[string]interface{}) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if encoded, err := json.Marshal(values); err == nil {
+		return string(encoded)
+	}
+	return fmt.Sprint(values)
+}
+
+// recordAudit records an action taken by the current user, if any. audit may
+// be nil, in which case nothing is recorded.
+func recordAudit(c *gin.Context, audit *services.AuditService, action, targetType string, targetID *primitive.ObjectID, details map[string]interface{}) {
+	audit.Record(c.Request.Context(), auditEntry(c, action, targetType, targetID, details))
+}
+
+// recordAuditChange records a change the current user made to a document,
+// with snapshots of the fields it touched before and after. after is nil
+// for deletions.
+func recordAuditChange(c *gin.Context, audit *services.AuditService, action, targetType string, targetID *primitive.ObjectID, before, after map[string]interface{}) {
+	entry := auditEntry(c, action, targetType, targetID, nil)
+	entry.Before, entry.After = before, after
+	audit.Record(c.Request.Context(), entry)
+}
+
+// needSnapshotProjection loads the fields needSnapshot keeps
+var needSnapshotProjection = bson.M{"user_id": 1, "title": 1, "category": 1, "urgency": 1, "status": 1, "created_at": 1}
+
+// needSnapshot is what the audit log keeps of a need
+func needSnapshot(need models.Need) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    need.UserID,
+		"title":      need.Title,
+		"category":   need.Category,
+		"urgency":    need.Urgency,
+		"status":     need.Status,
+		"created_at": need.CreatedAt,
+	}
+}
+
+// auditEntry builds an entry for an action taken by the current user, if
+// any, from the request's client address
+func auditEntry(c *gin.Context, action, targetType string, targetID *primitive.ObjectID, details map[string]interface{}) models.AuditEntry {
+	entry := models.AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		Details:    details,
+	}
+	if actorID, err := primitive.ObjectIDFromHex(middleware.GetUserID(c)); err == nil {
+		entry.ActorID = &actorID
+	}
+	return entry
+} 
\ No newline at end of file
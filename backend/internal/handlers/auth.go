@@ -1,39 +1,73 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"neighborenexus/internal/middleware"
 	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
 	"neighborenexus/internal/services"
 )
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	authService *services.AuthService
+	authService    *services.AuthService
+	auditService   *services.AuditService
+	velocity       *services.VelocityDetector
+	consentService *services.ConsentService
+	privacyService *services.PrivacyService
+	referrals      *services.ReferralService
+	passwordResets *services.PasswordResetService
+	// oauthCompleteURL is the web app page sent the outcome of signing in
+	// with a provider; empty responds with JSON
+	oauthCompleteURL string
 }
 
 // NewAuthHandler creates a new authentication handler
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, auditService *services.AuditService, velocityDetector *services.VelocityDetector, consentService *services.ConsentService, privacyService *services.PrivacyService, referralService *services.ReferralService, passwordResetService *services.PasswordResetService, oauthCompleteURL string) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:    authService,
+		auditService:   auditService,
+		velocity:       velocityDetector,
+		consentService: consentService,
+		privacyService: privacyService,
+		referrals:      referralService,
+		passwordResets: passwordResetService,
+
+		oauthCompleteURL: oauthCompleteURL,
 	}
 }
 
 // Register handles user registration
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
+	if req.Language == "" {
+		req.Language = models.Language(middleware.GetLocale(c))
+	}
 
-	// Generate H3 index for privacy-preserving location
-	// This would be done in the service layer, but for now we'll add it here
-	// In a real implementation, you'd want to use the matching service
+	// A bad invite code is turned away before the account exists, so the
+	// user can fix it rather than sign up unattributed
+	var invite *models.Invite
+	if req.InviteCode != "" {
+		var err error
+		if invite, err = h.referrals.LookupInvite(c.Request.Context(), req.InviteCode); err != nil {
+			if errors.Is(err, services.ErrInvalidInvite) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check invite code"})
+			return
+		}
+	}
 
 	user, err := h.authService.Register(c.Request.Context(), req)
 	if err != nil {
@@ -41,6 +75,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if invite != nil {
+		if err := h.referrals.Attribute(c.Request.Context(), invite, user); err != nil {
+			log.Printf("Failed to attribute sign-up %s to invite %s: %v", user.ID.Hex(), invite.ID.Hex(), err)
+		}
+	}
+
+	// Many signups from one address are held for review as likely sock puppets
+	h.velocity.RecordAccount(c.Request.Context(), c.ClientIP(), user)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User registered successfully",
 		"user":    user,
@@ -50,17 +93,25 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // Login handles user authentication
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	response, err := h.authService.Login(c.Request.Context(), req)
+	response, err := h.authService.Login(c.Request.Context(), req, sessionClient(c))
 	if err != nil {
+		recordAudit(c, h.auditService, models.AuditLoginFailed, "", nil, map[string]interface{}{"email": req.Email})
+		if errors.Is(err, services.ErrAccountSuspended) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
+	entry := auditEntry(c, models.AuditLogin, models.AuditTargetUser, &response.User.ID, nil)
+	entry.ActorID = &response.User.ID
+	h.auditService.Record(c.Request.Context(), entry)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -70,13 +121,16 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	response, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
+	response, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken, sessionClient(c))
 	if err != nil {
+		if errors.Is(err, services.ErrAccountSuspended) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -84,6 +138,110 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Logout ends the session of a refresh token. It succeeds even when the
+// session has already ended.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// ListSessions lists the current user's active sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, middleware.GetSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession ends one of the current user's sessions, signing that
+// device out
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// RevokeOtherSessions ends every session of the current user but the one
+// making the request
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	revoked, err := h.authService.RevokeOtherSessions(c.Request.Context(), userID, middleware.GetSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// sessionClient describes the device making the request
+func sessionClient(c *gin.Context) models.SessionClient {
+	return models.SessionClient{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+// ForgotPassword emails a password reset token. The response is the same
+// whether or not the address has an account.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.passwordResets.RequestReset(c.Request.Context(), req.Email); err != nil {
+		log.Printf("Failed to send password reset email: %v", err)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account uses that email, a password reset link has been sent to it"})
+}
+
+// ResetPassword sets a new password with an emailed reset token
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID, err := h.passwordResets.Reset(c.Request.Context(), req.Token, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	entry := auditEntry(c, models.AuditPasswordReset, models.AuditTargetUser, &userID, nil)
+	entry.ActorID = &userID
+	h.auditService.Record(c.Request.Context(), entry)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
 // GetProfile returns the current user's profile
 func (h *AuthHandler) GetProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -97,10 +255,31 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
+	user.ConsentState = h.consentService.State(c.Request.Context(), user)
 
 	c.JSON(http.StatusOK, gin.H{"user": user})
 }
 
+// profileSnapshot is what the audit log keeps of fields of user's profile.
+// Contact details, location, and date of birth are only recorded as
+// changed.
+func profileSnapshot(user *models.User, fields []string) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "name":
+			snapshot[field] = user.Name
+		case "language":
+			snapshot[field] = user.Language
+		case "units":
+			snapshot[field] = user.Units
+		default:
+			snapshot[field] = "[private]"
+		}
+	}
+	return snapshot
+}
+
 // UpdateProfile updates the current user's profile
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -110,15 +289,21 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	var req struct {
-		Name     string            `json:"name,omitempty"`
-		Phone    string            `json:"phone,omitempty"`
-		Location models.Location   `json:"location,omitempty"`
+		Name     string          `json:"name,omitempty" binding:"max=100"`
+		Phone    string          `json:"phone,omitempty" binding:"max=32"`
+		Location models.Location `json:"location,omitempty"`
+		// DateOfBirth can be set once by users who registered without one
+		DateOfBirth string          `json:"date_of_birth,omitempty" binding:"omitempty,datetime=2006-01-02"`
+		Language    models.Language `json:"language,omitempty" binding:"omitempty,enum"`
+		Units       models.Units    `json:"units,omitempty" binding:"omitempty,enum"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
+	req.Name = sanitize.Text(req.Name)
+	req.Phone = sanitize.Text(req.Phone)
+	req.Location.Address = sanitize.Text(req.Location.Address)
 
 	// Build update fields
 	updates := bson.M{}
@@ -126,10 +311,28 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		updates["name"] = req.Name
 	}
 	if req.Phone != "" {
-		updates["phone"] = req.Phone
+		updates["phone"] = models.EncryptedString(req.Phone)
 	}
 	if req.Location.Latitude != 0 || req.Location.Longitude != 0 {
-		updates["location"] = req.Location
+		updates["location"] = indexLocation(c, h.privacyService, req.Location)
+	}
+	if req.DateOfBirth != "" {
+		if user, ok := middleware.GetUser(c).(*models.User); ok && user.DateOfBirth != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Date of birth is already set; ask an administrator to correct it"})
+			return
+		}
+		dateOfBirth, err := services.ParseDateOfBirth(req.DateOfBirth, time.Now())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updates["date_of_birth"] = dateOfBirth
+	}
+	if req.Language != "" {
+		updates["language"] = req.Language
+	}
+	if req.Units != "" {
+		updates["units"] = req.Units
 	}
 
 	if len(updates) == 0 {
@@ -143,5 +346,18 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	if objectID, err := primitive.ObjectIDFromHex(userID); err == nil {
+		entry := auditEntry(c, models.AuditProfileUpdated, models.AuditTargetUser, &objectID, map[string]interface{}{"fields": fields})
+		if previous, ok := middleware.GetUser(c).(*models.User); ok {
+			entry.Before = profileSnapshot(previous, fields)
+		}
+		entry.After = profileSnapshot(user, fields)
+		h.auditService.Record(c.Request.Context(), entry)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"user": user})
 } 
\ No newline at end of file
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// maxAvailabilityExceptions bounds the exceptions one volunteer can keep
+const maxAvailabilityExceptions = 100
+
+// ListAvailabilityExceptions lists the current volunteer's availability
+// exceptions by date
+func (h *VolunteerHandler) ListAvailabilityExceptions(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var volunteer models.Volunteer
+	err := h.mongoClient.GetCollection("volunteers").FindOne(c.Request.Context(), bson.M{"user_id": userID},
+		options.FindOne().SetProjection(bson.M{"availability_exceptions": 1})).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer profile not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve volunteer profile"})
+		return
+	}
+
+	exceptions := volunteer.Exceptions
+	if exceptions == nil {
+		exceptions = []models.AvailabilityException{}
+	}
+	c.JSON(http.StatusOK, gin.H{"exceptions": exceptions})
+}
+
+// AddAvailabilityException marks the current volunteer unavailable, or
+// extra available, on a date
+func (h *VolunteerHandler) AddAvailabilityException(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	exception, ok := bindAvailabilityException(c, primitive.NewObjectID())
+	if !ok {
+		return
+	}
+
+	// Exceptions are kept in date order, up to the limit
+	var volunteer models.Volunteer
+	err := h.mongoClient.GetCollection("volunteers").FindOneAndUpdate(c.Request.Context(),
+		bson.M{"user_id": userID, "availability_exceptions.99": bson.M{"$exists": false}},
+		bson.M{
+			"$push": bson.M{"availability_exceptions": bson.M{"$each": []models.AvailabilityException{exception}, "$sort": bson.M{"date": 1}}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+		options.FindOneAndUpdate().SetProjection(bson.M{"_id": 1})).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		h.respondExceptionMissing(c, userID)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save availability exception"})
+		return
+	}
+	if exception.Available {
+		h.matchWaitlist(c.Request.Context(), volunteer.ID)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"exception": exception})
+}
+
+// UpdateAvailabilityException replaces one of the current volunteer's
+// availability exceptions
+func (h *VolunteerHandler) UpdateAvailabilityException(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	exceptionID, err := primitive.ObjectIDFromHex(c.Param("exceptionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exception ID"})
+		return
+	}
+	exception, ok := bindAvailabilityException(c, exceptionID)
+	if !ok {
+		return
+	}
+
+	var volunteer models.Volunteer
+	err = h.mongoClient.GetCollection("volunteers").FindOneAndUpdate(c.Request.Context(),
+		bson.M{"user_id": userID, "availability_exceptions._id": exceptionID},
+		bson.M{"$set": bson.M{"availability_exceptions.$": exception, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetProjection(bson.M{"_id": 1})).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Availability exception not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save availability exception"})
+		return
+	}
+	if exception.Available {
+		h.matchWaitlist(c.Request.Context(), volunteer.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exception": exception})
+}
+
+// DeleteAvailabilityException removes one of the current volunteer's
+// availability exceptions
+func (h *VolunteerHandler) DeleteAvailabilityException(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	exceptionID, err := primitive.ObjectIDFromHex(c.Param("exceptionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exception ID"})
+		return
+	}
+
+	result, err := h.mongoClient.GetCollection("volunteers").UpdateOne(c.Request.Context(),
+		bson.M{"user_id": userID, "availability_exceptions._id": exceptionID},
+		bson.M{
+			"$pull": bson.M{"availability_exceptions": bson.M{"_id": exceptionID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete availability exception"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Availability exception not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Availability exception deleted"})
+}
+
+// bindAvailabilityException reads an exception from the request body,
+// writing a 400 response when it is invalid
+func bindAvailabilityException(c *gin.Context, id primitive.ObjectID) (models.AvailabilityException, bool) {
+	var req models.AvailabilityExceptionRequest
+	if !bindJSON(c, &req) {
+		return models.AvailabilityException{}, false
+	}
+	if !req.ValidSpan() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be after start_time"})
+		return models.AvailabilityException{}, false
+	}
+	return models.AvailabilityException{
+		ID:        id,
+		Date:      req.Date,
+		Available: *req.Available,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Note:      sanitize.Text(req.Note),
+	}, true
+}
+
+// respondExceptionMissing explains why an exception could not be added:
+// there is no profile, or it already has as many exceptions as allowed
+func (h *VolunteerHandler) respondExceptionMissing(c *gin.Context, userID primitive.ObjectID) {
+	count, err := h.mongoClient.GetCollection("volunteers").CountDocuments(c.Request.Context(), bson.M{"user_id": userID})
+	switch {
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save availability exception"})
+	case count == 0:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer profile not found"})
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.Tf(middleware.GetLocale(c), "Volunteers can keep at most %d availability exceptions", maxAvailabilityExceptions)})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/services"
+)
+
+// BoostHandler lets requesters re-broadcast needs nobody has taken on
+type BoostHandler struct {
+	boostService *services.BoostService
+}
+
+// NewBoostHandler creates a new boost handler
+func NewBoostHandler(boostService *services.BoostService) *BoostHandler {
+	return &BoostHandler{boostService: boostService}
+}
+
+// BoostNeed re-runs matching for one of the current user's needs with
+// relaxed criteria and notifies the volunteers not told about it before
+func (h *BoostHandler) BoostNeed(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+
+	boost, err := h.boostService.Boost(c.Request.Context(), needID, userID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"boost": boost})
+	case errors.Is(err, services.ErrNeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+	case errors.Is(err, services.ErrNotNeedOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNeedClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrBoostTooSoon), errors.Is(err, services.ErrBoostLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Failed to boost need %s: %v", needID.Hex(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to boost need"})
+	}
+} 
\ No newline at end of file
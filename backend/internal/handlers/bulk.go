@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+	"neighborenexus/internal/services"
+)
+
+// Tag limits for needs
+const (
+	maxTagsPerNeed = 20
+	maxTagLength   = 32
+)
+
+// BulkCancelNeeds cancels several of the user's open needs at once
+func (h *NeedHandler) BulkCancelNeeds(c *gin.Context) {
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkCancelNeedsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response := runBulk(req.NeedIDs, func(needID primitive.ObjectID) error {
+		return h.cancelNeed(c.Request.Context(), needID, userObjectID)
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// cancelNeed cancels an owned open need and any tasks still working on it
+func (h *NeedHandler) cancelNeed(ctx context.Context, needID, userID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := h.mongoClient.GetCollection("needs").UpdateOne(
+		ctx,
+		bson.M{
+			"_id":     needID,
+			"user_id": userID,
+			"status":  bson.M{"$in": []models.NeedStatus{models.NeedStatusRequested, models.NeedStatusMatched}},
+		},
+		bson.M{"$set": bson.M{"status": models.NeedStatusCancelled, "updated_at": now}},
+	)
+	if err != nil {
+		return errors.New("failed to cancel need")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("need not found, not owned by user, or no longer open")
+	}
+
+	if err := h.tasks.CancelForNeed(ctx, needID); err != nil {
+		return errors.New("need cancelled but failed to cancel its tasks")
+	}
+
+	return nil
+}
+
+// BulkTagNeeds adds and removes tags on several of the user's needs at once
+func (h *NeedHandler) BulkTagNeeds(c *gin.Context) {
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkTagNeedsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	add, err := normalizeTags(req.Add)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	remove, err := normalizeTags(req.Remove)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No tags to add or remove"})
+		return
+	}
+
+	collection := h.mongoClient.GetCollection("needs")
+	response := runBulk(req.NeedIDs, func(needID primitive.ObjectID) error {
+		ctx := c.Request.Context()
+
+		var need models.Need
+		err := collection.FindOne(ctx, bson.M{"_id": needID, "user_id": userObjectID}).Decode(&need)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return errors.New("need not found or not owned by user")
+			}
+			return errors.New("failed to retrieve need")
+		}
+
+		tags := mergeTags(need.Tags, add, remove)
+		if len(tags) > maxTagsPerNeed {
+			return errors.New("too many tags")
+		}
+
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"_id": needID},
+			bson.M{"$set": bson.M{"tags": tags, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			return errors.New("failed to update tags")
+		}
+		return nil
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkUpdateTaskStatus updates the status of several tasks the user participates in
+func (h *NeedHandler) BulkUpdateTaskStatus(c *gin.Context) {
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkUpdateTaskStatusRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response := models.BulkResponse{Results: make([]models.BulkResult, 0, len(req.Updates))}
+	for _, update := range req.Updates {
+		err := h.updateTaskStatusAsParticipant(c.Request.Context(), userObjectID, update)
+		response.Add(update.TaskID, err)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// updateTaskStatusAsParticipant applies one status update if the user is the
+// task's volunteer or the creator of its need
+func (h *NeedHandler) updateTaskStatusAsParticipant(ctx context.Context, userID primitive.ObjectID, update models.BulkTaskStatusUpdate) error {
+	if !update.Status.Valid() {
+		return errors.New("invalid status")
+	}
+
+	taskID, err := primitive.ObjectIDFromHex(update.TaskID)
+	if err != nil {
+		return errors.New("invalid ID")
+	}
+
+	_, err = h.tasks.UpdateStatus(ctx, userID, taskID, models.UpdateTaskStatusRequest{Status: update.Status, Notes: update.Notes})
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		return errors.New("task not found")
+	case errors.Is(err, services.ErrInvalidTaskTransition), errors.Is(err, services.ErrTaskClosed), errors.Is(err, services.ErrTaskStatusConflict):
+		return err
+	case err != nil:
+		log.Printf("Failed to update task %s: %v", taskID.Hex(), err)
+		return errors.New("failed to update task")
+	}
+	return nil
+}
+
+// runBulk applies fn to each ID and collects per-item results
+func runBulk(ids []string, fn func(id primitive.ObjectID) error) models.BulkResponse {
+	response := models.BulkResponse{Results: make([]models.BulkResult, 0, len(ids))}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			response.Add(id, errors.New("duplicate ID"))
+			continue
+		}
+		seen[id] = true
+
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			response.Add(id, errors.New("invalid ID"))
+			continue
+		}
+		response.Add(id, fn(objectID))
+	}
+	return response
+}
+
+// requireUserObjectID gets the authenticated user's ObjectID, writing an error response if missing
+func requireUserObjectID(c *gin.Context) (primitive.ObjectID, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return primitive.NilObjectID, false
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return primitive.NilObjectID, false
+	}
+	return userObjectID, true
+}
+
+// normalizeTags lowercases, trims, and deduplicates tags
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(sanitize.Text(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, errors.New("tags must be at most 32 characters")
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// mergeTags applies additions and removals to an existing tag list
+func mergeTags(existing, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+
+	tags := []string{}
+	seen := make(map[string]bool)
+	candidates := append(append([]string{}, existing...), add...)
+	for _, tag := range candidates {
+		if removed[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+} 
\ No newline at end of file
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// CategoryHandler serves the need taxonomy and its admin management
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+	auditService    *services.AuditService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService *services.CategoryService, auditService *services.AuditService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		auditService:    auditService,
+	}
+}
+
+// ListCategories returns the taxonomy as a tree beneath the top-level
+// categories
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	tree, err := h.categoryService.Tree(c.Request.Context())
+	if err != nil {
+		respondCategoryError(c, err, "Failed to retrieve categories")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": tree})
+}
+
+// CreateCategory adds a top-level category or a subcategory
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	category, err := h.categoryService.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to create category")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditCategoryCreated, models.AuditTargetCategory, &category.ID, map[string]interface{}{"path": category.Path})
+
+	c.JSON(http.StatusCreated, gin.H{"category": category})
+}
+
+// UpdateCategory renames or redescribes a category
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	categoryID, ok := categoryID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	category, err := h.categoryService.Update(c.Request.Context(), categoryID, req)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to update category")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditCategoryUpdated, models.AuditTargetCategory, &category.ID, nil)
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// DeleteCategory removes a category that has no subcategories
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	categoryID, ok := categoryID(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.Delete(c.Request.Context(), categoryID)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to delete category")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditCategoryDeleted, models.AuditTargetCategory, &category.ID, map[string]interface{}{"path": category.Path})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted"})
+}
+
+// categoryID reads the :id category parameter, writing an error response
+// on failure
+func categoryID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// respondCategoryError maps category service errors to responses, falling
+// back to a 500 with message. Needs and volunteer profiles use it too.
+func respondCategoryError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+	case errors.Is(err, services.ErrCategoryExists), errors.Is(err, services.ErrCategoryHasChildren),
+		errors.Is(err, services.ErrCategoryRequired):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownCategory), errors.Is(err, services.ErrCategoryTooDeep),
+		errors.Is(err, services.ErrCategoryMismatch), errors.Is(err, services.ErrInvalidCategorySlug):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
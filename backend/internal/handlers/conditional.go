@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+)
+
+// changedSince is the lower bound of a delta request for a polled list
+type changedSince struct {
+	since time.Time
+	// exact is false for HTTP dates, which only carry whole seconds
+	exact bool
+}
+
+// parseChangedSince reads the updated_since query parameter (RFC 3339) or,
+// failing that, the If-Modified-Since header. It returns nil for full requests.
+func parseChangedSince(c *gin.Context) (*changedSince, error) {
+	if value := c.Query("updated_since"); value != "" {
+		since, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.New("updated_since must be an RFC 3339 timestamp")
+		}
+		return &changedSince{since: since, exact: true}, nil
+	}
+
+	if value := c.GetHeader("If-Modified-Since"); value != "" {
+		since, err := http.ParseTime(value)
+		if err != nil {
+			// Unparseable validators are ignored, as for any HTTP cache
+			return nil, nil
+		}
+		return &changedSince{since: since}, nil
+	}
+
+	return nil, nil
+}
+
+// apply restricts filter to documents updated after the lower bound
+func (cs *changedSince) apply(filter bson.M) {
+	filter["updated_at"] = bson.M{"$gt": cs.since}
+}
+
+// notModified reports whether nothing matching filter has changed since the
+// lower bound, comparing at the precision the client sent
+func (cs *changedSince) notModified(latest time.Time) bool {
+	if latest.IsZero() {
+		return true
+	}
+	if !cs.exact {
+		latest = latest.Truncate(time.Second)
+	}
+	return !latest.After(cs.since)
+}
+
+// latestUpdate returns the most recent updated_at of documents matching filter
+func latestUpdate(ctx context.Context, mongoClient *database.MongoClient, collectionName string, filter bson.M) (time.Time, error) {
+	var doc struct {
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetProjection(bson.M{"updated_at": 1})
+	err := mongoClient.GetCollection(collectionName).FindOne(ctx, filter, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	}
+	return doc.UpdatedAt, err
+}
+
+// checkModified prepares a polled list query. It narrows filter to changed
+// documents for delta requests, sets Last-Modified, and writes 304 Not
+// Modified when nothing has changed, returning false if the handler is done.
+func checkModified(c *gin.Context, mongoClient *database.MongoClient, collectionName string, filter bson.M) bool {
+	cs, err := parseChangedSince(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	latest, err := latestUpdate(c.Request.Context(), mongoClient, collectionName, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for changes"})
+		return false
+	}
+	if !latest.IsZero() {
+		c.Header("Last-Modified", latest.UTC().Format(http.TimeFormat))
+	}
+
+	if cs == nil {
+		return true
+	}
+	if cs.notModified(latest) {
+		c.Status(http.StatusNotModified)
+		return false
+	}
+
+	cs.apply(filter)
+	return true
+} 
\ No newline at end of file
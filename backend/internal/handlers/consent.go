@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// ConsentHandler handles policy publication and users' acceptance of policies
+type ConsentHandler struct {
+	consentService *services.ConsentService
+	auditService   *services.AuditService
+}
+
+// NewConsentHandler creates a new consent handler
+func NewConsentHandler(consentService *services.ConsentService, auditService *services.AuditService) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+		auditService:   auditService,
+	}
+}
+
+// GetConsent returns the current user's standing against each current policy
+func (h *ConsentHandler) GetConsent(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"consent": h.consentService.State(c.Request.Context(), user)})
+}
+
+// AcceptConsent records the current user accepting the current version of a policy
+func (h *ConsentHandler) AcceptConsent(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.AcceptPolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.consentService.Accept(c.Request.Context(), user.ID, req, c.ClientIP()); err != nil {
+		if err == services.ErrPolicyVersionNotCurrent {
+			c.JSON(http.StatusConflict, gin.H{"error": "That is not the current version of the policy"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record consent"})
+		return
+	}
+	user.Consents = append(user.Consents, models.Consent{Document: req.Document, Version: req.Version})
+
+	recordAudit(c, h.auditService, models.AuditConsentAccepted, models.AuditTargetUser, &user.ID, map[string]interface{}{
+		"document": req.Document,
+		"version":  req.Version,
+	})
+	c.JSON(http.StatusOK, gin.H{"consent": h.consentService.State(c.Request.Context(), user)})
+}
+
+// ListPolicies lists published policy versions, optionally filtered by ?document=
+func (h *ConsentHandler) ListPolicies(c *gin.Context) {
+	document := models.PolicyDocument(c.Query("document"))
+	if document != "" && !document.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document"})
+		return
+	}
+
+	versions, err := h.consentService.Versions(c.Request.Context(), document)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": versions})
+}
+
+// PublishPolicy publishes a new policy version that users must accept once it takes effect
+func (h *ConsentHandler) PublishPolicy(c *gin.Context) {
+	adminID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.PublishPolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	policy, err := h.consentService.Publish(c.Request.Context(), adminID, req)
+	if err != nil {
+		if err == services.ErrPolicyVersionExists {
+			c.JSON(http.StatusConflict, gin.H{"error": "That policy version has already been published"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish policy"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditPolicyPublished, models.AuditTargetPolicy, &policy.ID, map[string]interface{}{
+		"document": policy.Document,
+		"version":  policy.Version,
+	})
+	c.JSON(http.StatusCreated, gin.H{"policy": policy})
+}
+
+// currentUser returns the user loaded by AuthMiddleware
+func currentUser(c *gin.Context) (*models.User, bool) {
+	user, ok := middleware.GetUser(c).(*models.User)
+	if !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+	return user, true
+} 
\ No newline at end of file
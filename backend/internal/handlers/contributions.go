@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// ContributionHandler handles material costs, contributions, receipts, and
+// payouts on deployments with contributions enabled
+type ContributionHandler struct {
+	contributionService *services.ContributionService
+	auditService        *services.AuditService
+}
+
+// NewContributionHandler creates a new contribution handler
+func NewContributionHandler(contributionService *services.ContributionService, auditService *services.AuditService) *ContributionHandler {
+	return &ContributionHandler{
+		contributionService: contributionService,
+		auditService:        auditService,
+	}
+}
+
+// SetMaterialCost sets or clears the material cost of the current user's need
+func (h *ContributionHandler) SetMaterialCost(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+
+	var req models.SetMaterialCostRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	cost, err := h.contributionService.SetMaterialCost(c.Request.Context(), needID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to set material cost")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"material_cost": cost})
+}
+
+// Contribute starts a contribution toward a need's material cost and returns
+// the Stripe Checkout URL to send the payer to
+func (h *ContributionHandler) Contribute(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+
+	var req models.ContributeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	contribution, err := h.contributionService.Contribute(c.Request.Context(), needID, user, req.AmountCents)
+	if err != nil {
+		h.respondError(c, err, "Failed to start contribution")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"contribution": contribution})
+}
+
+// GetMyContributions lists the current user's contributions, newest first
+func (h *ContributionHandler) GetMyContributions(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	limit, offset := groupPage(c)
+
+	contributions, err := h.contributionService.ListForUser(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve contributions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contributions": contributions, "limit": limit, "offset": offset})
+}
+
+// GetReceipt returns the receipt for one of the current user's contributions
+func (h *ContributionHandler) GetReceipt(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	contributionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contribution ID"})
+		return
+	}
+
+	receipt, err := h.contributionService.Receipt(c.Request.Context(), contributionID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve receipt")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"receipt": receipt})
+}
+
+// GetMyPayouts lists the payouts owed or sent to the current user
+func (h *ContributionHandler) GetMyPayouts(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	limit, offset := groupPage(c)
+
+	payouts, err := h.contributionService.ListPayouts(c.Request.Context(), bson.M{"volunteer_id": userID}, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payouts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payouts": payouts, "limit": limit, "offset": offset})
+}
+
+// ListPayouts lists all payouts, optionally filtered by ?status=
+func (h *ContributionHandler) ListPayouts(c *gin.Context) {
+	filter := bson.M{}
+	if status := models.PayoutStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		filter["status"] = status
+	}
+	limit, offset := groupPage(c)
+
+	payouts, err := h.contributionService.ListPayouts(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payouts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payouts": payouts, "limit": limit, "offset": offset})
+}
+
+// MarkPayoutPaid records that a pending payout was sent to its volunteer
+func (h *ContributionHandler) MarkPayoutPaid(c *gin.Context) {
+	adminID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	payoutID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout ID"})
+		return
+	}
+
+	var req models.MarkPayoutPaidRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	payout, err := h.contributionService.MarkPayoutPaid(c.Request.Context(), payoutID, adminID, req.Reference)
+	if err != nil {
+		h.respondError(c, err, "Failed to update payout")
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditPayoutPaid, models.AuditTargetPayout, &payout.ID, map[string]interface{}{
+		"volunteer_id": payout.VolunteerID.Hex(),
+		"amount_cents": payout.AmountCents,
+		"currency":     payout.Currency,
+		"reference":    payout.Reference,
+	})
+	c.JSON(http.StatusOK, gin.H{"payout": payout})
+}
+
+// respondError maps contribution service errors to responses, falling back
+// to a 500 with message
+func (h *ContributionHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrContributionsDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+	case errors.Is(err, services.ErrContributionNotFound), errors.Is(err, services.ErrPayoutNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotNeedOwner), errors.Is(err, services.ErrOwnNeed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNeedClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNoMaterialCost), errors.Is(err, services.ErrContributionTooLarge), errors.Is(err, services.ErrMaterialCostBelowRaised):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
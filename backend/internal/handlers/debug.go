@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/buildinfo"
+)
+
+// DebugHandler serves runtime diagnostics for admins
+type DebugHandler struct {
+	startedAt time.Time
+}
+
+// NewDebugHandler creates a new debug handler
+func NewDebugHandler() *DebugHandler {
+	return &DebugHandler{
+		startedAt: time.Now(),
+	}
+}
+
+// GetVersion returns the build information of the running binary
+func (h *DebugHandler) GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
+
+// GetRuntime returns a snapshot of goroutine, memory, and GC statistics
+func (h *DebugHandler) GetRuntime(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var lastGC *time.Time
+	if mem.LastGC > 0 {
+		t := time.Unix(0, int64(mem.LastGC))
+		lastGC = &t
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"build":          buildinfo.Get(),
+		"started_at":     h.startedAt,
+		"uptime_seconds": int64(time.Since(h.startedAt).Seconds()),
+		"goroutines":     runtime.NumGoroutine(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"num_cpu":        runtime.NumCPU(),
+		"memory": gin.H{
+			"heap_alloc_bytes":  mem.HeapAlloc,
+			"heap_inuse_bytes":  mem.HeapInuse,
+			"heap_objects":      mem.HeapObjects,
+			"stack_inuse_bytes": mem.StackInuse,
+			"sys_bytes":         mem.Sys,
+			"total_alloc_bytes": mem.TotalAlloc,
+			"next_gc_bytes":     mem.NextGC,
+		},
+		"gc": gin.H{
+			"num_gc":         mem.NumGC,
+			"pause_total_ns": mem.PauseTotalNs,
+			"last_gc":        lastGC,
+			"cpu_fraction":   mem.GCCPUFraction,
+		},
+	})
+}
+
+// GetGoroutines dumps the stack of every goroutine as plain text
+func (h *DebugHandler) GetGoroutines(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	pprof.Lookup("goroutine").WriteTo(c.Writer, 2)
+} 
\ No newline at end of file
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// DeviceHandler registers the current user's devices for push
+// notifications
+type DeviceHandler struct {
+	pushService *services.PushService
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler(pushService *services.PushService) *DeviceHandler {
+	return &DeviceHandler{pushService: pushService}
+}
+
+// RegisterDevice stores a push token for the current user
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RegisterDeviceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	device, err := h.pushService.RegisterDevice(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"device": device})
+}
+
+// UnregisterDevice stops push notifications to one of the current user's
+// tokens, e.g. when they sign out of the app
+func (h *DeviceHandler) UnregisterDevice(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UnregisterDeviceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	err := h.pushService.UnregisterDevice(c.Request.Context(), userID, req.Token)
+	if errors.Is(err, services.ErrDeviceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unregister device"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered"})
+} 
\ No newline at end of file
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// EmergencyHandler handles emergency mode and the emergency needs feed
+type EmergencyHandler struct {
+	emergencyService    *services.EmergencyService
+	announcementService *services.AnnouncementService
+	privacyService      *services.PrivacyService
+	auditService        *services.AuditService
+}
+
+// NewEmergencyHandler creates a new emergency handler
+func NewEmergencyHandler(emergencyService *services.EmergencyService, announcementService *services.AnnouncementService, privacyService *services.PrivacyService, auditService *services.AuditService) *EmergencyHandler {
+	return &EmergencyHandler{
+		emergencyService:    emergencyService,
+		announcementService: announcementService,
+		privacyService:      privacyService,
+		auditService:        auditService,
+	}
+}
+
+// ListEmergencies lists the emergencies in force
+func (h *EmergencyHandler) ListEmergencies(c *gin.Context) {
+	emergencies, err := h.emergencyService.Active(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve emergencies"})
+		return
+	}
+	if emergencies == nil {
+		emergencies = services.ActiveEmergencies{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"emergencies": emergencies})
+}
+
+// GetFeed lists open needs inside active emergencies, priority categories
+// first, optionally filtered by ?category=
+func (h *EmergencyHandler) GetFeed(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	category := models.Category(c.Query("category"))
+	if category != "" && !category.WellFormed() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+		return
+	}
+	limit, offset := groupPage(c)
+
+	needs, err := h.emergencyService.Feed(c.Request.Context(), category, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve emergency feed"})
+		return
+	}
+	if err := shapeNeeds(c.Request.Context(), h.privacyService, userID, needs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve emergency feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"needs": needs, "limit": limit, "offset": offset})
+}
+
+// ListAllEmergencies lists emergencies for admins, including ended ones
+// with ?include_ended=true
+func (h *EmergencyHandler) ListAllEmergencies(c *gin.Context) {
+	limit, offset := groupPage(c)
+
+	emergencies, err := h.emergencyService.List(c.Request.Context(), c.Query("include_ended") == "true", limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve emergencies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"emergencies": emergencies, "limit": limit, "offset": offset})
+}
+
+// DeclareEmergency puts emergency mode in force and, when asked, broadcasts
+// an alert to everyone it covers
+func (h *EmergencyHandler) DeclareEmergency(c *gin.Context) {
+	adminID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DeclareEmergencyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	emergency, err := h.emergencyService.Declare(c.Request.Context(), adminID, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidEmergencyRegion) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to declare emergency"})
+		return
+	}
+
+	// The alert goes out on the next announcement delivery run
+	if req.Broadcast {
+		announcement, err := h.announcementService.Create(c.Request.Context(), adminID, models.CreateAnnouncementRequest{
+			Title:  emergency.Title,
+			Body:   emergency.Message,
+			Target: models.AnnouncementTarget{Regions: emergency.Regions, Resolution: emergency.Resolution},
+		})
+		if err == nil {
+			err = h.emergencyService.AttachAnnouncement(c.Request.Context(), emergency.ID, announcement.ID)
+			emergency.AnnouncementID = &announcement.ID
+		}
+		if err != nil {
+			log.Printf("Failed to broadcast emergency %s: %v", emergency.ID.Hex(), err)
+		}
+	}
+
+	recordAudit(c, h.auditService, models.AuditEmergencyDeclared, models.AuditTargetEmergency, &emergency.ID, map[string]interface{}{
+		"title":     emergency.Title,
+		"regions":   len(emergency.Regions),
+		"broadcast": emergency.AnnouncementID != nil,
+	})
+	c.JSON(http.StatusCreated, gin.H{"emergency": emergency})
+}
+
+// EndEmergency stands down an emergency and withdraws its broadcast alert
+// from announcement feeds
+func (h *EmergencyHandler) EndEmergency(c *gin.Context) {
+	adminID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	emergencyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid emergency ID"})
+		return
+	}
+
+	emergency, err := h.emergencyService.End(c.Request.Context(), emergencyID, adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrEmergencyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Emergency not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end emergency"})
+		return
+	}
+
+	if emergency.AnnouncementID != nil {
+		err := h.announcementService.Cancel(c.Request.Context(), *emergency.AnnouncementID)
+		if err != nil && !errors.Is(err, services.ErrAnnouncementNotFound) {
+			log.Printf("Failed to withdraw alert for emergency %s: %v", emergency.ID.Hex(), err)
+		}
+	}
+
+	recordAudit(c, h.auditService, models.AuditEmergencyEnded, models.AuditTargetEmergency, &emergency.ID, map[string]interface{}{"title": emergency.Title})
+	c.JSON(http.StatusOK, gin.H{"emergency": emergency})
+} 
\ No newline at end of file
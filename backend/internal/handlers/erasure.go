@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/webhooks"
+)
+
+// ErasureHandler handles permanent account erasure
+type ErasureHandler struct {
+	erasureService *services.ErasureService
+	authService    *services.AuthService
+	auditService   *services.AuditService
+	partnerEvents  *database.RedisClient
+}
+
+// NewErasureHandler creates a new erasure handler. When partnerEvents is
+// non-nil, a user.deleted event is queued there for partner systems.
+func NewErasureHandler(erasureService *services.ErasureService, authService *services.AuthService, auditService *services.AuditService, partnerEvents *database.RedisClient) *ErasureHandler {
+	return &ErasureHandler{
+		erasureService: erasureService,
+		authService:    authService,
+		auditService:   auditService,
+		partnerEvents:  partnerEvents,
+	}
+}
+
+// DeleteAccount erases the current user after they confirm their password
+func (h *ErasureHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := h.authService.VerifyPassword(c.Request.Context(), userID, req.Password); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		return
+	}
+
+	h.erase(c, userID)
+}
+
+// EraseUser erases any user, e.g. on a verified request received offline
+func (h *ErasureHandler) EraseUser(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	h.erase(c, userID)
+}
+
+// erase runs the erasure, records it, and tells partners
+func (h *ErasureHandler) erase(c *gin.Context, userID primitive.ObjectID) {
+	ctx := c.Request.Context()
+	report, err := h.erasureService.Erase(ctx, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		log.Printf("Erasure of user %s stopped partway: %v", userID.Hex(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to erase account; retry to finish"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditUserErased, models.AuditTargetUser, &userID, map[string]interface{}{
+		"deleted":    report.Deleted,
+		"anonymized": report.Anonymized,
+	})
+
+	if h.partnerEvents != nil {
+		event := webhooks.OutboundEvent{
+			ID:        primitive.NewObjectID().Hex(),
+			Type:      "user.deleted",
+			CreatedAt: report.ErasedAt,
+			Data:      map[string]interface{}{"user_id": userID.Hex()},
+		}
+		if err := jobs.EnqueuePartnerEvent(ctx, h.partnerEvents, event); err != nil {
+			log.Printf("Failed to queue deletion webhook for user %s: %v", userID.Hex(), err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account erased", "erasure": report})
+} 
\ No newline at end of file
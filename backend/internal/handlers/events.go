@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// EventHandler handles group volunteering events, signups, and attendance
+type EventHandler struct {
+	eventService   *services.EventService
+	privacyService *services.PrivacyService
+	auditService   *services.AuditService
+	redisClient    *database.RedisClient
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventService *services.EventService, privacyService *services.PrivacyService, auditService *services.AuditService, redisClient *database.RedisClient) *EventHandler {
+	return &EventHandler{
+		eventService:   eventService,
+		privacyService: privacyService,
+		auditService:   auditService,
+		redisClient:    redisClient,
+	}
+}
+
+// CreateEvent creates an event organized by the current user
+func (h *EventHandler) CreateEvent(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateEventRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	event, err := h.eventService.Create(c.Request.Context(), user, req, indexLocation(c, h.privacyService, req.Location))
+	if err != nil {
+		h.respondError(c, err, "Failed to create event")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"event": event})
+}
+
+// ListEvents lists upcoming events, soonest first. ?group_id= limits them to
+// one group, ?mine=true to events the user organizes or signed up for, and
+// ?limit=/?offset= page.
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var groupID *primitive.ObjectID
+	if value := c.Query("group_id"); value != "" {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			return
+		}
+		groupID = &id
+	}
+	mine, _ := strconv.ParseBool(c.Query("mine"))
+	limit, offset := groupPage(c)
+
+	events, err := h.eventService.List(c.Request.Context(), user, groupID, mine, limit, offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve events")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events, "limit": limit, "offset": offset})
+}
+
+// GetEvent returns an event along with the current user's signups for it
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	user, event, ok := h.eventRequest(c)
+	if !ok {
+		return
+	}
+
+	signups, err := h.eventService.Signups(c.Request.Context(), event.ID, &user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"event": event, "signups": signups})
+}
+
+// CancelEvent cancels an event and notifies everyone signed up. Organizers only.
+func (h *EventHandler) CancelEvent(c *gin.Context) {
+	user, event, ok := h.eventRequest(c)
+	if !ok || !h.authorize(c, event, user) {
+		return
+	}
+
+	userIDs, err := h.eventService.Cancel(c.Request.Context(), event.ID)
+	if err != nil {
+		h.respondError(c, err, "Failed to cancel event")
+		return
+	}
+
+	message := models.WebSocketMessage{
+		Type: "event_cancelled",
+		Payload: map[string]interface{}{
+			"event_id": event.ID.Hex(),
+			"title":    event.Title,
+		},
+	}
+	if err := jobs.EnqueueNotification(c.Request.Context(), h.redisClient, userIDs, message); err != nil {
+		log.Printf("Failed to notify signups of cancelled event %s: %v", event.ID.Hex(), err)
+	}
+
+	recordAudit(c, h.auditService, models.AuditEventCancelled, models.AuditTargetEvent, &event.ID, map[string]interface{}{
+		"signups": len(userIDs),
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Event cancelled"})
+}
+
+// SignUp takes a place at the event, or at the requested shift, for the
+// current user
+func (h *EventHandler) SignUp(c *gin.Context) {
+	user, event, ok := h.eventRequest(c)
+	if !ok {
+		return
+	}
+
+	// The body may be left out for events without shifts
+	var req models.EventSignupRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+	var shiftID *primitive.ObjectID
+	if req.ShiftID != "" {
+		id, err := primitive.ObjectIDFromHex(req.ShiftID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shift ID"})
+			return
+		}
+		shiftID = &id
+	}
+
+	signup, err := h.eventService.Signup(c.Request.Context(), event, user, shiftID)
+	if err != nil {
+		h.respondError(c, err, "Failed to sign up")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"signup": signup})
+}
+
+// CancelSignup frees a place. Volunteers may cancel their own signups and
+// organizers anyone's.
+func (h *EventHandler) CancelSignup(c *gin.Context) {
+	user, event, ok := h.eventRequest(c)
+	if !ok {
+		return
+	}
+	signupID, ok := signupParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.CancelSignup(c.Request.Context(), event, signupID, user); err != nil {
+		h.respondError(c, err, "Failed to cancel signup")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Signup cancelled"})
+}
+
+// GetSignups lists everyone signed up for an event. Organizers only.
+func (h *EventHandler) GetSignups(c *gin.Context) {
+	user, event, ok := h.eventRequest(c)
+	if !ok || !h.authorize(c, event, user) {
+		return
+	}
+
+	signups, err := h.eventService.Signups(c.Request.Context(), event.ID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve signups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signups": signups})
+}
+
+// RecordAttendance marks whether a volunteer attended. Organizers only.
+func (h *EventHandler) RecordAttendance(c *gin.Context) {
+	user, event, ok := h.eventRequest(c)
+	if !ok {
+		return
+	}
+	signupID, ok := signupParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.AttendanceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if !h.authorize(c, event, user) {
+		return
+	}
+
+	signup, err := h.eventService.RecordAttendance(c.Request.Context(), event.ID, signupID, req.Attendance)
+	if err != nil {
+		h.respondError(c, err, "Failed to record attendance")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signup": signup})
+}
+
+// eventRequest reads the current user and the :id event they may see,
+// writing an error response on failure
+func (h *EventHandler) eventRequest(c *gin.Context) (*models.User, *models.Event, bool) {
+	user, ok := currentUser(c)
+	if !ok {
+		return nil, nil, false
+	}
+	eventID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, nil, false
+	}
+
+	event, err := h.eventService.Get(c.Request.Context(), eventID, user)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve event")
+		return nil, nil, false
+	}
+	return user, event, true
+}
+
+// authorize checks that user may manage event, writing an error response
+// on failure
+func (h *EventHandler) authorize(c *gin.Context, event *models.Event, user *models.User) bool {
+	if err := h.eventService.Authorize(c.Request.Context(), event, user); err != nil {
+		h.respondError(c, err, "Failed to retrieve event")
+		return false
+	}
+	return true
+}
+
+// respondError maps event service errors to responses, falling back to a
+// 500 with message
+func (h *EventHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound), errors.Is(err, services.ErrSignupNotFound), errors.Is(err, services.ErrShiftNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrGroupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+	case errors.Is(err, services.ErrNotEventOrganizer), errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrNotGroupAdmin),
+		errors.Is(err, services.ErrSensitiveCategoryForMinor), errors.Is(err, services.ErrSupervisionUnverified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEventFull), errors.Is(err, services.ErrAlreadySignedUp), errors.Is(err, services.ErrEventClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEventInPast), errors.Is(err, services.ErrShiftRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// signupParam reads the :signupId parameter, writing an error response on
+// failure
+func signupParam(c *gin.Context) (primitive.ObjectID, bool) {
+	signupID, err := primitive.ObjectIDFromHex(c.Param("signupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signup ID"})
+		return primitive.NilObjectID, false
+	}
+	return signupID, true
+} 
\ No newline at end of file
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// DataExportHandler lets users download an archive of all their data
+type DataExportHandler struct {
+	exportService *services.ExportService
+	redisClient   *database.RedisClient
+}
+
+// NewDataExportHandler creates a new data export handler. Archives are
+// assembled by the worker's exports job.
+func NewDataExportHandler(exportService *services.ExportService, redisClient *database.RedisClient) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+		redisClient:   redisClient,
+	}
+}
+
+// RequestExport starts assembling the current user's data. Poll GetExport
+// for progress.
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	export, created, err := h.exportService.Request(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start export"})
+		return
+	}
+	if created {
+		if err := jobs.EnqueueExport(c.Request.Context(), h.redisClient, export); err != nil {
+			log.Printf("Failed to queue export %s: %v", export.ID.Hex(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start export"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"export": export})
+}
+
+// GetExport reports the status of the current user's latest export, with a
+// signed download link once it is ready
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.Latest(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No export requested"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve export"})
+		return
+	}
+
+	response := gin.H{"export": export}
+	if export.Status == models.ExportReady && export.FileID != nil {
+		base := strings.TrimSuffix(c.Request.URL.Path, "/profile/export")
+		response["download_url"] = h.exportService.SignedPath(base+"/exports/"+export.ID.Hex()+"/download", export)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadExport streams an export archive. It needs no session: the signed
+// link from GetExport is the credential.
+func (h *DataExportHandler) DownloadExport(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+
+	archive, export, err := h.exportService.Open(c.Request.Context(), id, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrExportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		case errors.Is(err, services.ErrExportExpired):
+			c.JSON(http.StatusGone, gin.H{"error": "Download link has expired; request a new export"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open export"})
+		}
+		return
+	}
+	defer archive.Close()
+
+	c.Header("Content-Disposition", `attachment; filename="neighbornexus-export-`+export.CreatedAt.UTC().Format("20060102")+`.zip"`)
+	c.Header("Cache-Control", "no-store")
+	c.DataFromReader(http.StatusOK, export.Size, "application/zip", archive, nil)
+} 
\ No newline at end of file
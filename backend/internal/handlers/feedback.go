@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// FeedbackHandler handles feedback the participants of a task leave each
+// other
+type FeedbackHandler struct {
+	feedbackService *services.FeedbackService
+}
+
+// NewFeedbackHandler creates a new feedback handler
+func NewFeedbackHandler(feedbackService *services.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackService: feedbackService}
+}
+
+// SubmitFeedback rates the other participant of a completed task. The
+// rating stays hidden from them until they rate back or the reveal window
+// passes.
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	userID, taskID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.FeedbackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	feedback, err := h.feedbackService.Submit(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to submit feedback")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Feedback submitted successfully",
+		"feedback": feedback,
+	})
+}
+
+// GetTaskFeedback lists the feedback on a task the current user may see:
+// what they gave, and what they received once revealed
+func (h *FeedbackHandler) GetTaskFeedback(c *gin.Context) {
+	userID, taskID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := h.feedbackService.TaskFeedback(c.Request.Context(), taskID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback})
+}
+
+// GetFeedbackInsights breaks down the ratings the current user received as a
+// volunteer over the last months months, 12 by default and at most 24
+func (h *FeedbackHandler) GetFeedbackInsights(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	months := 12
+	if parsed, err := strconv.Atoi(c.Query("months")); err == nil && parsed > 0 {
+		months = min(parsed, 24)
+	}
+
+	insights, err := h.feedbackService.Insights(c.Request.Context(), userID, months)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve feedback insights")
+		return
+	}
+
+	c.JSON(http.StatusOK, insights)
+}
+
+// RespondToFeedback posts the current user's public reply to a low rating
+// they received
+func (h *FeedbackHandler) RespondToFeedback(c *gin.Context) {
+	userID, feedbackID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.FeedbackResponseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	feedback, err := h.feedbackService.Respond(c.Request.Context(), feedbackID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to respond to feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback})
+}
+
+// AppealFeedback sends a low rating the current user received to moderators,
+// who can exclude it from their reputation
+func (h *FeedbackHandler) AppealFeedback(c *gin.Context) {
+	userID, feedbackID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.FeedbackAppealRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	feedback, err := h.feedbackService.Appeal(c.Request.Context(), feedbackID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to appeal feedback")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Appeal submitted for review",
+		"feedback": feedback,
+	})
+}
+
+// ShareTestimonial offers a comment the current user wrote as a public
+// testimonial, shown once its recipient approves it
+func (h *FeedbackHandler) ShareTestimonial(c *gin.Context) {
+	userID, feedbackID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := h.feedbackService.ShareTestimonial(c.Request.Context(), feedbackID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to share feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback})
+}
+
+// ApproveTestimonial shows a shared comment the current user received on
+// their public profile
+func (h *FeedbackHandler) ApproveTestimonial(c *gin.Context) {
+	userID, feedbackID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := h.feedbackService.ApproveTestimonial(c.Request.Context(), feedbackID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to approve testimonial")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback})
+}
+
+// WithdrawTestimonial takes a testimonial down, by its author or recipient
+func (h *FeedbackHandler) WithdrawTestimonial(c *gin.Context) {
+	userID, feedbackID, ok := h.feedbackRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.feedbackService.WithdrawTestimonial(c.Request.Context(), feedbackID, userID); err != nil {
+		h.respondError(c, err, "Failed to withdraw testimonial")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Testimonial withdrawn"})
+}
+
+// ListTestimonials lists the approved testimonials a volunteer received,
+// newest first and paged with ?limit= and ?cursor=. The :id parameter is
+// the volunteer's user ID.
+func (h *FeedbackHandler) ListTestimonials(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Testimonials are ordered by approval rather than ID, so they page by position
+	p, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	testimonials, err := h.feedbackService.Testimonials(c.Request.Context(), userID, p.fetch(), p.cursor.Offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve testimonials")
+		return
+	}
+	testimonials, next := pageOffset(p, testimonials)
+
+	c.JSON(http.StatusOK, gin.H{"testimonials": testimonials, "next_cursor": next})
+}
+
+// GetHighlights lists the community's newest glowing testimonials
+func (h *FeedbackHandler) GetHighlights(c *gin.Context) {
+	limit, _ := groupPage(c)
+	highlights, err := h.feedbackService.Highlights(c.Request.Context(), limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve highlights")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"highlights": highlights})
+}
+
+// feedbackRequest reads the current user's ID and the :id parameter, a task
+// or feedback ID depending on the route, writing an error response on failure
+func (h *FeedbackHandler) feedbackRequest(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return userID, taskID, true
+}
+
+// respondError maps feedback service errors to responses, falling back to
+// a 500 with message
+func (h *FeedbackHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, services.ErrFeedbackNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+	case errors.Is(err, services.ErrTaskNotCompleted), errors.Is(err, services.ErrRatingNotLow),
+		errors.Is(err, services.ErrNoComment), errors.Is(err, services.ErrNotShared):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrFeedbackExists), errors.Is(err, services.ErrFeedbackClosed),
+		errors.Is(err, services.ErrResponseExists), errors.Is(err, services.ErrAppealExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// resourceSpec describes the fields a resource exposes and the related
+// documents that can be embedded into it with ?expand=
+type resourceSpec struct {
+	fields     []string
+	computed   map[string]interface{} // projection expressions for fields derived from stored ones
+	expansions map[string]expansionSpec
+}
+
+// expansionSpec describes how to embed a related document
+type expansionSpec struct {
+	collection string
+	localField string
+	spec       resourceSpec
+}
+
+// userSummarySpec exposes only public user fields when a user is embedded
+var userSummarySpec = resourceSpec{
+	fields:   []string{"id", "name", "created_at"},
+	computed: map[string]interface{}{"name": displayNameExpression},
+}
+
+// displayNameExpression computes a user's name as other users see it,
+// mirroring models.User.DisplayName
+var displayNameExpression = bson.M{"$let": bson.M{
+	"vars": bson.M{"parts": bson.M{"$split": bson.A{bson.M{"$trim": bson.M{"input": "$name"}}, " "}}},
+	"in": bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{
+				"case": bson.M{"$eq": bson.A{"$privacy.name_display", models.NameDisplayAnonymous}},
+				"then": models.AnonymousName,
+			},
+			bson.M{
+				"case": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$privacy.name_display", models.NameDisplayFirstInitial}},
+					bson.M{"$gt": bson.A{bson.M{"$size": "$$parts"}, 1}},
+				}},
+				"then": bson.M{"$concat": bson.A{
+					bson.M{"$arrayElemAt": bson.A{"$$parts", 0}},
+					" ",
+					bson.M{"$substrCP": bson.A{bson.M{"$arrayElemAt": bson.A{"$$parts", -1}}, 0, 1}},
+					".",
+				}},
+			},
+		},
+		"default": "$name",
+	}},
+}}
+
+// needSpec describes the fields and expansions of a need
+var needSpec = resourceSpec{
+	fields: []string{
+		"id", "user_id", "title", "description", "category", "urgency", "duration",
+		"location", "status", "tags", "created_at", "updated_at", "expires_at",
+	},
+	expansions: map[string]expansionSpec{
+		"user": {collection: "users", localField: "user_id", spec: userSummarySpec},
+	},
+}
+
+// taskSpec describes the fields and expansions of a task
+var taskSpec = resourceSpec{
+	fields: []string{
+		"id", "need_id", "volunteer_id", "status", "scheduled_at", "completed_at",
+		"notes", "created_at", "updated_at",
+	},
+	expansions: map[string]expansionSpec{
+		"need":      {collection: "needs", localField: "need_id", spec: needSpec},
+		"volunteer": {collection: "users", localField: "volunteer_id", spec: userSummarySpec},
+	},
+}
+
+// fieldset is a parsed ?fields= and ?expand= request
+type fieldset struct {
+	spec   resourceSpec
+	fields []string
+	expand []string
+}
+
+// parseFieldset validates ?fields= (e.g. "title,status,need.title") and
+// ?expand= (e.g. "need.user") against a resource spec
+func parseFieldset(c *gin.Context, spec resourceSpec) (*fieldset, error) {
+	fs := &fieldset{spec: spec}
+
+	for _, path := range splitList(c.Query("expand")) {
+		if _, err := spec.resolveExpansion(strings.Split(path, ".")); err != nil {
+			return nil, err
+		}
+		// Expanding a nested path implies expanding each of its parents
+		parts := strings.Split(path, ".")
+		for i := range parts {
+			fs.addExpansion(strings.Join(parts[:i+1], "."))
+		}
+	}
+
+	for _, path := range splitList(c.Query("fields")) {
+		if err := fs.validateField(path); err != nil {
+			return nil, err
+		}
+		fs.fields = append(fs.fields, path)
+	}
+
+	return fs, nil
+}
+
+// requested reports whether the client asked for a custom shape
+func (fs *fieldset) requested() bool {
+	return len(fs.fields) > 0 || len(fs.expand) > 0
+}
+
+// addExpansion records an expansion path once
+func (fs *fieldset) addExpansion(path string) {
+	for _, existing := range fs.expand {
+		if existing == path {
+			return
+		}
+	}
+	fs.expand = append(fs.expand, path)
+}
+
+// validateField checks that a field path exists and, when it reaches into a
+// related document, that the relation is expanded
+func (fs *fieldset) validateField(path string) error {
+	parts := strings.Split(path, ".")
+	spec := fs.spec
+	for i, part := range parts {
+		// Subpaths of a field such as location.h3_index are allowed
+		if spec.hasField(part) {
+			return nil
+		}
+
+		child, ok := spec.expansions[part]
+		if !ok || !fs.isExpanded(strings.Join(parts[:i+1], ".")) {
+			break
+		}
+		if i == len(parts)-1 {
+			return nil
+		}
+		spec = child.spec
+	}
+	return fmt.Errorf("unknown field %q", path)
+}
+
+// isExpanded reports whether an expansion path was requested
+func (fs *fieldset) isExpanded(path string) bool {
+	for _, expanded := range fs.expand {
+		if expanded == path {
+			return true
+		}
+	}
+	return false
+}
+
+// hasField reports whether a top-level field is exposed
+func (spec resourceSpec) hasField(name string) bool {
+	for _, field := range spec.fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveExpansion walks a dotted expansion path
+func (spec resourceSpec) resolveExpansion(parts []string) (expansionSpec, error) {
+	var current expansionSpec
+	for _, part := range parts {
+		next, ok := spec.expansions[part]
+		if !ok {
+			return expansionSpec{}, fmt.Errorf("unknown expansion %q", strings.Join(parts, "."))
+		}
+		current = next
+		spec = next.spec
+	}
+	return current, nil
+}
+
+// pipeline builds the aggregation returning the requested shape
+func (fs *fieldset) pipeline(filter bson.M, sort bson.D, limit int64) mongo.Pipeline {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if len(sort) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	// Expansions are ordered parent-first, so "need" is embedded before "need.user"
+	for _, path := range fs.expand {
+		expansion, _ := fs.spec.resolveExpansion(strings.Split(path, "."))
+		localField := expansion.localField
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			localField = path[:i] + "." + localField
+		}
+
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from": expansion.collection,
+				"let":  bson.M{"id": "$" + localField},
+				"pipeline": mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$id"}}}}},
+					{{Key: "$project", Value: expansion.spec.projection()}},
+				},
+				"as": path,
+			}}},
+			bson.D{{Key: "$unwind", Value: bson.M{"path": "$" + path, "preserveNullAndEmptyArrays": true}}},
+		)
+	}
+
+	projection := bson.M{}
+	if len(fs.fields) > 0 {
+		for _, field := range fs.fields {
+			// Mongo rejects projecting both a document and one of its subfields
+			if !hasParentPath(fs.fields, field) {
+				projection[bsonPath(field)] = 1
+			}
+		}
+	} else {
+		for key, value := range fs.spec.projection() {
+			projection[key] = value
+		}
+		for _, path := range fs.expand {
+			if !strings.Contains(path, ".") {
+				projection[path] = 1
+			}
+		}
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+
+	return pipeline
+}
+
+// projection includes exactly the fields a spec exposes
+func (spec resourceSpec) projection() bson.M {
+	projection := bson.M{}
+	for _, field := range spec.fields {
+		if expression, ok := spec.computed[field]; ok {
+			projection[bsonPath(field)] = expression
+			continue
+		}
+		projection[bsonPath(field)] = 1
+	}
+	return projection
+}
+
+// hasParentPath reports whether paths contains a parent of path
+func hasParentPath(paths []string, path string) bool {
+	for _, other := range paths {
+		if strings.HasPrefix(path, other+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// bsonPath maps an API field path to its stored path
+func bsonPath(path string) string {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		if part == "id" {
+			parts[i] = "_id"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// aggregateFieldset runs the fieldset aggregation and returns API-shaped documents
+func aggregateFieldset(ctx context.Context, mongoClient *database.MongoClient, collectionName string, fs *fieldset, filter bson.M, sort bson.D, limit int64) ([]bson.M, error) {
+	cursor, err := mongoClient.GetCollection(collectionName).Aggregate(ctx, fs.pipeline(filter, sort, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		if err := mongoClient.Unseal(doc); err != nil {
+			return nil, err
+		}
+		renameIDs(doc)
+	}
+	return docs, nil
+}
+
+// renameIDs renames "_id" to "id" to match the JSON shape of the models
+func renameIDs(doc bson.M) {
+	if id, ok := doc["_id"]; ok {
+		doc["id"] = id
+		delete(doc, "_id")
+	}
+	for _, value := range doc {
+		if nested, ok := value.(bson.M); ok {
+			renameIDs(nested)
+		}
+	}
+}
+
+// splitList splits a comma-separated query parameter
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+} 
\ No newline at end of file
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/graph"
+	"neighborenexus/internal/middleware"
+)
+
+// GraphQLHandler serves the GraphQL endpoint
+type GraphQLHandler struct {
+	schema      *graphql.Schema
+	mongoClient *database.MongoClient
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(schema *graphql.Schema, mongoClient *database.MongoClient) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema:      schema,
+		mongoClient: mongoClient,
+	}
+}
+
+// Query executes a GraphQL query for the authenticated user
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req struct {
+		Query         string                 `json:"query" binding:"required"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	ctx, err := graph.NewContext(c.Request.Context(), h.mongoClient, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, response)
+} 
\ No newline at end of file
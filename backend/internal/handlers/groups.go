@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// Page sizes for group listings and feeds
+const (
+	groupDefaultLimit = 20
+	groupMaxLimit     = 100
+)
+
+// GroupHandler handles neighborhood groups, their members, and their feeds
+type GroupHandler struct {
+	groupService   *services.GroupService
+	privacyService *services.PrivacyService
+	auditService   *services.AuditService
+	mongoClient    *database.MongoClient
+}
+
+// NewGroupHandler creates a new group handler
+func NewGroupHandler(groupService *services.GroupService, privacyService *services.PrivacyService, auditService *services.AuditService, mongoClient *database.MongoClient) *GroupHandler {
+	return &GroupHandler{
+		groupService:   groupService,
+		privacyService: privacyService,
+		auditService:   auditService,
+		mongoClient:    mongoClient,
+	}
+}
+
+// CreateGroup creates a group with the current user as its admin
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateGroupRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	group, err := h.groupService.Create(c.Request.Context(), user.ID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create group")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"group": group})
+}
+
+// ListGroups lists groups, largest first. ?mine=true limits them to the
+// current user's groups, ?q= searches names, and ?limit=/?offset= page.
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var memberID *primitive.ObjectID
+	if mine, _ := strconv.ParseBool(c.Query("mine")); mine {
+		memberID = &user.ID
+	}
+	limit, offset := groupPage(c)
+
+	groups, err := h.groupService.List(c.Request.Context(), memberID, c.Query("q"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups, "limit": limit, "offset": offset})
+}
+
+// GetGroup returns a group along with the current user's membership, if any
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.groupService.Get(c.Request.Context(), groupID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve group")
+		return
+	}
+	membership, err := h.groupService.Membership(c.Request.Context(), groupID, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group": group, "membership": membership})
+}
+
+// UpdateGroup changes a group's details or area. Group admins only.
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateGroupRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req == (models.UpdateGroupRequest{}) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+	if !h.authorize(c, groupID, user, true) {
+		return
+	}
+
+	group, err := h.groupService.Update(c.Request.Context(), groupID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update group")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group": group})
+}
+
+// DeleteGroup removes a group and its memberships. Group admins only.
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok || !h.authorize(c, groupID, user, true) {
+		return
+	}
+
+	if err := h.groupService.Delete(c.Request.Context(), groupID); err != nil {
+		h.respondError(c, err, "Failed to delete group")
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditGroupDeleted, models.AuditTargetGroup, &groupID, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Group deleted"})
+}
+
+// JoinGroup adds the current user to a group
+func (h *GroupHandler) JoinGroup(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.groupService.Join(c.Request.Context(), groupID, user.ID)
+	if err != nil {
+		h.respondError(c, err, "Failed to join group")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"membership": member})
+}
+
+// LeaveGroup removes the current user from a group
+func (h *GroupHandler) LeaveGroup(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.groupService.RemoveMember(c.Request.Context(), groupID, user.ID); err != nil {
+		h.respondError(c, err, "Failed to leave group")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left group"})
+}
+
+// GetMembers lists a group's members, admins first. Members only.
+func (h *GroupHandler) GetMembers(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok || !h.authorize(c, groupID, user, false) {
+		return
+	}
+	limit, offset := groupPage(c)
+
+	members, err := h.groupService.Members(c.Request.Context(), groupID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members, "limit": limit, "offset": offset})
+}
+
+// UpdateMember changes a member's role. Group admins only.
+func (h *GroupHandler) UpdateMember(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok {
+		return
+	}
+	memberID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateGroupMemberRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if !h.authorize(c, groupID, user, true) {
+		return
+	}
+
+	member, err := h.groupService.SetRole(c.Request.Context(), groupID, memberID, req.Role)
+	if err != nil {
+		h.respondError(c, err, "Failed to update member")
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditGroupRoleChanged, models.AuditTargetGroup, &groupID, map[string]interface{}{
+		"user_id": memberID.Hex(),
+		"role":    req.Role,
+	})
+	c.JSON(http.StatusOK, gin.H{"membership": member})
+}
+
+// RemoveMember removes a member from a group. Group admins only.
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok {
+		return
+	}
+	memberID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !h.authorize(c, groupID, user, true) {
+		return
+	}
+
+	if err := h.groupService.RemoveMember(c.Request.Context(), groupID, memberID); err != nil {
+		h.respondError(c, err, "Failed to remove member")
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditGroupMemberRemoved, models.AuditTargetGroup, &groupID, map[string]interface{}{
+		"user_id": memberID.Hex(),
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// GetFeed lists the open needs inside a group's area, newest first.
+// Members only. ?category= filters and ?limit=/?offset= page.
+func (h *GroupHandler) GetFeed(c *gin.Context) {
+	user, groupID, ok := h.groupRequest(c)
+	if !ok || !h.authorize(c, groupID, user, false) {
+		return
+	}
+
+	filter, err := h.groupService.FeedFilter(c.Request.Context(), groupID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve group feed")
+		return
+	}
+	filter["status"] = bson.M{"$in": []models.NeedStatus{models.NeedStatusRequested, models.NeedStatusMatched, models.NeedStatusInProgress}}
+	filter["hidden"] = bson.M{"$ne": true}
+	filter["held_for_review"] = bson.M{"$ne": true}
+	filter["$or"] = []bson.M{
+		{"expires_at": bson.M{"$exists": false}},
+		{"expires_at": bson.M{"$gt": time.Now()}},
+	}
+	if category := models.Category(c.Query("category")); category != "" {
+		if !category.WellFormed() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+			return
+		}
+		filter["category"] = category
+	}
+	limit, offset := groupPage(c)
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(offset).SetLimit(limit)
+	cursor, err := h.mongoClient.GetCollection("needs").Find(c.Request.Context(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve group feed"})
+		return
+	}
+	defer cursor.Close(c.Request.Context())
+
+	needs := []models.Need{}
+	if err := cursor.All(c.Request.Context(), &needs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode needs"})
+		return
+	}
+	if err := shapeNeeds(c.Request.Context(), h.privacyService, user.ID, needs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve group feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"needs": needs, "limit": limit, "offset": offset})
+}
+
+// groupRequest reads the current user and the :id group parameter, writing
+// an error response on failure
+func (h *GroupHandler) groupRequest(c *gin.Context) (*models.User, primitive.ObjectID, bool) {
+	user, ok := currentUser(c)
+	if !ok {
+		return nil, primitive.NilObjectID, false
+	}
+	groupID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return nil, primitive.NilObjectID, false
+	}
+	return user, groupID, true
+}
+
+// authorize checks that user is a member, or a group admin when admin is
+// true, writing an error response on failure
+func (h *GroupHandler) authorize(c *gin.Context, groupID primitive.ObjectID, user *models.User, admin bool) bool {
+	if err := h.groupService.Authorize(c.Request.Context(), groupID, user, admin); err != nil {
+		h.respondError(c, err, "Failed to retrieve group")
+		return false
+	}
+	return true
+}
+
+// respondError maps group service errors to responses, falling back to a
+// 500 with message
+func (h *GroupHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrGroupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+	case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrNotGroupAdmin):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyGroupMember), errors.Is(err, services.ErrLastGroupAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidGroupArea), errors.Is(err, services.ErrGroupAreaTooLarge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// groupPage reads ?limit= and ?offset=, clamped to the group page sizes
+func groupPage(c *gin.Context) (int64, int64) {
+	limit := int64(groupDefaultLimit)
+	if parsed, err := strconv.ParseInt(c.Query("limit"), 10, 64); err == nil && parsed > 0 {
+		limit = parsed
+	}
+	if limit > groupMaxLimit {
+		limit = groupMaxLimit
+	}
+	var offset int64
+	if parsed, err := strconv.ParseInt(c.Query("offset"), 10, 64); err == nil && parsed > 0 {
+		offset = parsed
+	}
+	return limit, offset
+} 
\ No newline at end of file
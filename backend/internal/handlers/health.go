@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// healthCheckTimeout bounds each dependency check on /readyz
+	healthCheckTimeout = 2 * time.Second
+	// slowCheckInterval is how long the result of a check against a paid
+	// external API is reused, so probes do not call it every few seconds
+	slowCheckInterval = time.Minute
+)
+
+// HealthCheck tests one dependency the instance needs to serve requests
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Cached checks reuse their last result for slowCheckInterval
+	Cached bool
+}
+
+// HealthHandler serves liveness and readiness probes. The instance is live
+// while the process is serving, and ready while its dependencies are
+// reachable and it is not draining for shutdown.
+type HealthHandler struct {
+	checks   []HealthCheck
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	results map[string]cachedCheck
+}
+
+// cachedCheck is the last result of a cached check
+type cachedCheck struct {
+	err       error
+	checkedAt time.Time
+}
+
+// NewHealthHandler creates a new health handler running checks for readiness
+func NewHealthHandler(checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{
+		checks:  checks,
+		results: make(map[string]cachedCheck),
+	}
+}
+
+// Drain makes /readyz fail from now on, so load balancers stop routing new
+// requests here while the instance shuts down
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is up and serving requests
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "healthy", "service": "neighborenexus"})
+}
+
+// Ready reports whether the instance should receive traffic, with the
+// outcome of each dependency check
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+
+	errs := make([]error, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			errs[i] = h.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status, ready := http.StatusOK, "ready"
+	results := make(gin.H, len(h.checks))
+	for i, check := range h.checks {
+		results[check.Name] = "ok"
+		if errs[i] != nil {
+			results[check.Name] = errs[i].Error()
+			status, ready = http.StatusServiceUnavailable, "unavailable"
+		}
+	}
+	c.JSON(status, gin.H{"status": ready, "checks": results})
+}
+
+// run runs a check, or returns its cached result when still fresh
+func (h *HealthHandler) run(ctx context.Context, check HealthCheck) error {
+	if check.Cached {
+		h.mu.Lock()
+		last, ok := h.results[check.Name]
+		h.mu.Unlock()
+		if ok && time.Since(last.checkedAt) < slowCheckInterval {
+			return last.err
+		}
+	}
+
+	err := check.Check(ctx)
+	if check.Cached {
+		h.mu.Lock()
+		h.results[check.Name] = cachedCheck{err: err, checkedAt: time.Now()}
+		h.mu.Unlock()
+	}
+	return err
+} 
\ No newline at end of file
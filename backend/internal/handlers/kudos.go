@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// KudosHandler handles thank-you notes left for volunteers
+type KudosHandler struct {
+	kudosService *services.KudosService
+}
+
+// NewKudosHandler creates a new kudos handler
+func NewKudosHandler(kudosService *services.KudosService) *KudosHandler {
+	return &KudosHandler{kudosService: kudosService}
+}
+
+// SendKudos thanks the volunteer of a completed task the current user was
+// helped with
+func (h *KudosHandler) SendKudos(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.KudosRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	kudos, err := h.kudosService.Send(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to send kudos")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Kudos sent successfully",
+		"kudos":   kudos,
+	})
+}
+
+// ListKudos lists the kudos a volunteer has received, newest first. The :id
+// parameter is the volunteer's user ID.
+func (h *KudosHandler) ListKudos(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit, offset := groupPage(c)
+	kudos, err := h.kudosService.List(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve kudos")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"kudos": kudos})
+}
+
+// respondError maps kudos service errors to responses, falling back to a
+// 500 with message
+func (h *KudosHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, services.ErrKudosNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKudosNotCompleted):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKudosExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
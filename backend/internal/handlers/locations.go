@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+	"neighborenexus/internal/services"
+)
+
+// Exact need locations are only shown to the need's creator and to a
+// volunteer whose task on it has been accepted. Everyone else sees the
+// center of the surrounding H3 cell without the street address, and any
+// phone numbers or email addresses typed into the title or description are
+// redacted.
+
+// indexLocation sets location's H3 cell at the current user's location
+// precision
+func indexLocation(c *gin.Context, privacy *services.PrivacyService, location models.Location) models.Location {
+	var settings models.PrivacySettings
+	if user, ok := middleware.GetUser(c).(*models.User); ok && user != nil {
+		settings = user.Privacy
+	}
+	return privacy.IndexLocation(location, settings)
+}
+
+// Radius limits for browsing needs near a point
+const (
+	nearDefaultRadiusKm = 10
+	nearMaxRadiusKm     = 100
+)
+
+// earthRadiusKm converts distances to the radians $centerSphere takes
+const earthRadiusKm = 6378.1
+
+// nearQuery is a ?lat=&lng=&radius_km= search around a point
+type nearQuery struct {
+	point    *models.GeoPoint
+	radiusKm float64
+}
+
+// parseNearQuery reads ?lat=, ?lng=, and the optional ?radius_km=, writing
+// 400 and returning false when they are invalid. It returns nil when no
+// point was given.
+func parseNearQuery(c *gin.Context) (*nearQuery, bool) {
+	latValue, lngValue := c.Query("lat"), c.Query("lng")
+	if latValue == "" && lngValue == "" {
+		if c.Query("radius_km") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "radius_km requires lat and lng"})
+			return nil, false
+		}
+		return nil, true
+	}
+
+	lat, err := strconv.ParseFloat(latValue, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lat must be between -90 and 90"})
+		return nil, false
+	}
+	lng, err := strconv.ParseFloat(lngValue, 64)
+	if err != nil || lng < -180 || lng > 180 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lng must be between -180 and 180"})
+		return nil, false
+	}
+	radius := float64(nearDefaultRadiusKm)
+	if value := c.Query("radius_km"); value != "" {
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > nearMaxRadiusKm {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Tf(middleware.GetLocale(c), "radius_km must be greater than 0 and at most %d", nearMaxRadiusKm)})
+			return nil, false
+		}
+	}
+	return &nearQuery{point: models.NewGeoPoint(lat, lng), radiusKm: radius}, true
+}
+
+// nearest matches points within the radius, nearest first. It can only be
+// used with find, not in an aggregation.
+func (q *nearQuery) nearest() bson.M {
+	return bson.M{"$nearSphere": bson.M{
+		"$geometry":    q.point,
+		"$maxDistance": q.radiusKm * 1000,
+	}}
+}
+
+// within matches points within the radius in no particular order
+func (q *nearQuery) within() bson.M {
+	return bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{q.point.Coordinates, q.radiusKm / earthRadiusKm},
+	}}
+}
+
+// shapeNeeds coarsens the locations of needs the viewer may not see exactly
+// and redacts contact details from their text
+func shapeNeeds(ctx context.Context, privacy *services.PrivacyService, viewerID primitive.ObjectID, needs []models.Need) error {
+	needIDs := make([]primitive.ObjectID, 0, len(needs))
+	for _, need := range needs {
+		needIDs = append(needIDs, need.ID)
+	}
+	revealed, err := privacy.RevealedNeeds(ctx, viewerID, needIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range needs {
+		if !revealed[needs[i].ID] {
+			needs[i].Location = privacy.ApproximateLocation(needs[i].Location)
+			needs[i].Title = sanitize.RedactContacts(needs[i].Title)
+			needs[i].Description = sanitize.RedactContacts(needs[i].Description)
+			if needs[i].Translation != nil {
+				needs[i].Translation.Title = sanitize.RedactContacts(needs[i].Translation.Title)
+				needs[i].Translation.Description = sanitize.RedactContacts(needs[i].Translation.Description)
+			}
+		}
+	}
+	return nil
+}
+
+// shapeNeedDocs coarsens the locations of fieldset need documents the viewer
+// may not see exactly
+func shapeNeedDocs(ctx context.Context, privacy *services.PrivacyService, viewerID primitive.ObjectID, docs []bson.M) error {
+	needIDs := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		needIDs[i], _ = doc["id"].(primitive.ObjectID)
+	}
+	return approximateUnrevealed(ctx, privacy, viewerID, docs, needIDs)
+}
+
+// shapeTaskDocs coarsens the locations of needs expanded into fieldset task
+// documents
+func shapeTaskDocs(ctx context.Context, privacy *services.PrivacyService, viewerID primitive.ObjectID, docs []bson.M) error {
+	var needs []bson.M
+	var needIDs []primitive.ObjectID
+	for _, doc := range docs {
+		need, ok := doc["need"].(bson.M)
+		if !ok {
+			continue
+		}
+		needID, ok := need["id"].(primitive.ObjectID)
+		if !ok {
+			needID, _ = doc["need_id"].(primitive.ObjectID)
+		}
+		needs = append(needs, need)
+		needIDs = append(needIDs, needID)
+	}
+	return approximateUnrevealed(ctx, privacy, viewerID, needs, needIDs)
+}
+
+// approximateUnrevealed coarsens the location and redacts the contact details
+// of each need document whose ID is not revealed to the viewer. Documents whose ID was not projected are
+// always coarsened.
+func approximateUnrevealed(ctx context.Context, privacy *services.PrivacyService, viewerID primitive.ObjectID, docs []bson.M, needIDs []primitive.ObjectID) error {
+	revealed, err := privacy.RevealedNeeds(ctx, viewerID, needIDs)
+	if err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		if !revealed[needIDs[i]] {
+			approximateLocationDoc(privacy, doc)
+			redactContactsDoc(doc, "title", "description")
+		}
+	}
+	return nil
+}
+
+// approximateLocationDoc replaces doc's location with its approximation,
+// keeping only the location fields that were requested
+func approximateLocationDoc(privacy *services.PrivacyService, doc bson.M) {
+	stored, ok := doc["location"].(bson.M)
+	if !ok {
+		return
+	}
+
+	var location models.Location
+	if raw, err := bson.Marshal(stored); err == nil {
+		bson.Unmarshal(raw, &location)
+	}
+	approximate := privacy.ApproximateLocation(location)
+
+	shaped := bson.M{"approximate": true}
+	for key, value := range map[string]interface{}{
+		"latitude":  approximate.Latitude,
+		"longitude": approximate.Longitude,
+		"h3_index":  approximate.H3Index,
+	} {
+		if _, requested := stored[key]; requested {
+			shaped[key] = value
+		}
+	}
+	doc["location"] = shaped
+}
+
+// redactContactsDoc redacts contact details from the named text fields of doc
+// that were projected
+func redactContactsDoc(doc bson.M, fields ...string) {
+	for _, field := range fields {
+		if text, ok := doc[field].(string); ok {
+			doc[field] = sanitize.RedactContacts(text)
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// Web map zoom levels, from the whole world to a single building
+const (
+	mapMinZoom = 0
+	mapMaxZoom = 22
+)
+
+// coverageMinResolution is the coarsest coverage cell, about 1,770 km²
+const coverageMinResolution = 4
+
+// MapHandler serves aggregated map views
+type MapHandler struct {
+	mapService *services.MapService
+}
+
+// NewMapHandler creates a new map handler
+func NewMapHandler(mapService *services.MapService) *MapHandler {
+	return &MapHandler{
+		mapService: mapService,
+	}
+}
+
+// GetNeedMap clusters the open needs in ?bbox=west,south,east,north into H3
+// cells sized for ?zoom=, with counts by category and a few pins per cell
+func (h *MapHandler) GetNeedMap(c *gin.Context) {
+	bounds, ok := parseBounds(c.Query("bbox"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bbox must be west,south,east,north in degrees"})
+		return
+	}
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < mapMinZoom || zoom > mapMaxZoom {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "zoom must be between 0 and 22"})
+		return
+	}
+
+	needMap, err := h.mapService.NeedMap(c.Request.Context(), bounds, h.mapService.MapResolution(zoom))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build need map"})
+		return
+	}
+
+	c.JSON(http.StatusOK, needMap)
+}
+
+// GetCoverage compares active volunteers with open needs by category in each
+// H3 cell, cells where needs most outnumber volunteers first. ?resolution=
+// sets the cell size, at most neighborhood resolution, and ?bbox= optionally
+// limits cells to a map viewport.
+func (h *MapHandler) GetCoverage(c *gin.Context) {
+	maxResolution := h.mapService.MaxCoverageResolution()
+	resolution := maxResolution
+	if value := c.Query("resolution"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < coverageMinResolution || parsed > maxResolution {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Tf(middleware.GetLocale(c), "resolution must be between %d and %d", coverageMinResolution, maxResolution)})
+			return
+		}
+		resolution = parsed
+	}
+
+	var bounds *models.MapBounds
+	if value := c.Query("bbox"); value != "" {
+		parsed, ok := parseBounds(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bbox must be west,south,east,north in degrees"})
+			return
+		}
+		bounds = &parsed
+	}
+
+	cells, err := h.mapService.Coverage(c.Request.Context(), bounds, resolution)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute coverage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolution": resolution, "cells": cells})
+}
+
+// parseBounds parses a west,south,east,north bounding box
+func parseBounds(value string) (models.MapBounds, bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return models.MapBounds{}, false
+	}
+	var edges [4]float64
+	for i, part := range parts {
+		edge, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return models.MapBounds{}, false
+		}
+		edges[i] = edge
+	}
+	bounds := models.MapBounds{West: edges[0], South: edges[1], East: edges[2], North: edges[3]}
+	if bounds.South < -90 || bounds.North > 90 || bounds.South > bounds.North ||
+		bounds.West < -180 || bounds.West > 180 || bounds.East < -180 || bounds.East > 180 {
+		return models.MapBounds{}, false
+	}
+	return bounds, true
+} 
\ No newline at end of file
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// DeclineMatch stops matching the current volunteer with a need. The :id
+// parameter is the need's ID.
+func (h *VolunteerHandler) DeclineMatch(c *gin.Context) {
+	userID, needID, ok := h.matchRequest(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.matchingService.DeclineMatch(c.Request.Context(), userID, needID)
+	respondMatchState(c, match, err, "Failed to decline match")
+}
+
+// RestoreMatch undoes declining a match, so the need can be matched to the
+// current volunteer again. The :id parameter is the need's ID.
+func (h *VolunteerHandler) RestoreMatch(c *gin.Context) {
+	userID, needID, ok := h.matchRequest(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.matchingService.RestoreMatch(c.Request.Context(), userID, needID)
+	respondMatchState(c, match, err, "Failed to restore match")
+}
+
+// ExplainMatch breaks down the score of the current volunteer's match with a
+// need into its parts, in words. The :id parameter is the need's ID.
+func (h *VolunteerHandler) ExplainMatch(c *gin.Context) {
+	userID, needID, ok := h.matchRequest(c)
+	if !ok {
+		return
+	}
+
+	explanation, err := h.matchingService.ExplainMatch(c.Request.Context(), userID, needID, preferredUnits(c), preferredLanguage(c))
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"explanation": explanation})
+	case errors.Is(err, services.ErrMatchNotFound), errors.Is(err, services.ErrMatchUnexplained):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Failed to explain match of need %s: %v", needID.Hex(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to explain match"})
+	}
+}
+
+// matchRequest reads the current user's ID and the need ID in :id, writing
+// an error response on failure
+func (h *VolunteerHandler) matchRequest(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	// Without matching there are no matches to change
+	if h.matchingService == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": services.ErrMatchNotFound.Error()})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return userID, needID, true
+}
+
+// respondMatchState writes the result of changing a match's state
+func respondMatchState(c *gin.Context, match *models.Match, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrMatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMatchAccepted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	default:
+		c.JSON(http.StatusOK, gin.H{"match": match})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+	"neighborenexus/internal/services"
+)
+
+// MessageHandler handles chat between a need's creator and its volunteer
+type MessageHandler struct {
+	messageService *services.MessageService
+}
+
+// NewMessageHandler creates a new message handler
+func NewMessageHandler(messageService *services.MessageService) *MessageHandler {
+	return &MessageHandler{messageService: messageService}
+}
+
+// SendMessage sends a chat message to the other participant of a task
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.SendMessageRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	body := sanitize.Text(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body is required"})
+		return
+	}
+
+	message, err := h.messageService.Send(c.Request.Context(), taskID, userID, body)
+	if err != nil {
+		h.respondError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"chat_message": message})
+}
+
+// ListMessages lists the messages of a task the current user takes part
+// in, newest first
+func (h *MessageHandler) ListMessages(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	limit, offset := groupPage(c)
+	messages, err := h.messageService.List(c.Request.Context(), taskID, userID, limit, offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"messages": messages})
+}
+
+// MarkMessagesRead marks the messages the current user received on a task
+// as read
+func (h *MessageHandler) MarkMessagesRead(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	read, err := h.messageService.MarkRead(c.Request.Context(), taskID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to mark messages read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"read": read})
+}
+
+// respondError maps message service errors to responses, falling back to a
+// 500 with message
+func (h *MessageHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, services.ErrMessagingClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
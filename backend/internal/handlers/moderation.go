@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// ModerationHandler handles content reports and the moderator review queue
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+	mongoClient       *database.MongoClient
+	needs             *NeedHandler
+	feedback          *services.FeedbackService
+	kudos             *services.KudosService
+	auditService      *services.AuditService
+}
+
+// NewModerationHandler creates a new moderation handler. Needs held for
+// review are released through needHandler once a moderator clears them,
+// decisions on feedback settle appeals through feedbackService, and
+// decisions on kudos recount the recipient's through kudosService.
+func NewModerationHandler(moderationService *services.ModerationService, mongoClient *database.MongoClient, needHandler *NeedHandler, feedbackService *services.FeedbackService, kudosService *services.KudosService, auditService *services.AuditService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		mongoClient:       mongoClient,
+		needs:             needHandler,
+		feedback:          feedbackService,
+		kudos:             kudosService,
+		auditService:      auditService,
+	}
+}
+
+// ReportContent lets a user report a need, profile, message, or user
+func (h *ModerationHandler) ReportContent(c *gin.Context) {
+	reporterID, err := primitive.ObjectIDFromHex(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.ReportContentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := h.moderationService.Report(c.Request.Context(), reporterID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrContentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		case errors.Is(err, services.ErrDuplicateReport):
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already reported this content"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit report"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Report submitted", "report": report})
+}
+
+// ListMyReports lists the reports the current user has filed and their status
+func (h *ModerationHandler) ListMyReports(c *gin.Context) {
+	reporterID, err := primitive.ObjectIDFromHex(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	filter := bson.M{"reporter_id": reporterID}
+	if status := models.ReportStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		filter["status"] = status
+	}
+
+	reports, err := h.moderationService.Reports(c.Request.Context(), filter, adminMaxLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+// GetUserReports returns a user's report history for moderators: reports
+// against their content and reports they filed, with counts by status
+func (h *ModerationHandler) GetUserReports(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	against, err := h.moderationService.Reports(ctx, bson.M{"subject_id": userID}, adminMaxLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports"})
+		return
+	}
+	filed, err := h.moderationService.Reports(ctx, bson.M{"reporter_id": userID}, adminMaxLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports"})
+		return
+	}
+
+	counts := map[models.ReportStatus]int{}
+	for _, report := range against {
+		counts[report.Status]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"against":        against,
+		"against_counts": counts,
+		"filed":          filed,
+	})
+}
+
+// ListQueue lists flagged items soonest-due first. Open items are shown
+// unless ?status= is given; ?overdue=true keeps only items past their SLA.
+func (h *ModerationHandler) ListQueue(c *gin.Context) {
+	filter := bson.M{}
+	if status := models.ModerationStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		filter["status"] = status
+	} else {
+		filter["status"] = bson.M{"$in": []models.ModerationStatus{models.ModerationPending, models.ModerationEscalated}}
+	}
+	if contentType := models.ContentType(c.Query("content_type")); contentType != "" {
+		if !contentType.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content type"})
+			return
+		}
+		filter["content_type"] = contentType
+	}
+	if c.Query("overdue") == "true" {
+		filter["due_at"] = bson.M{"$lt": time.Now()}
+	}
+
+	limit := adminDefaultLimit
+	if value, err := strconv.Atoi(c.Query("limit")); err == nil && value > 0 && value <= adminMaxLimit {
+		limit = value
+	}
+	offset := 0
+	if value, err := strconv.Atoi(c.Query("offset")); err == nil && value > 0 {
+		offset = value
+	}
+
+	ctx := c.Request.Context()
+	collection := h.mongoClient.GetCollection("moderation_items")
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation queue"})
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "due_at", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation queue"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.ModerationItem{}
+	if err := cursor.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// GetItem returns a queue item with its reports and the content as it
+// currently stands; viewing it moves open reports into review
+func (h *ModerationHandler) GetItem(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid moderation item ID"})
+		return
+	}
+
+	item, content, reports, err := h.moderationService.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": item, "content": content, "reports": reports})
+}
+
+// Approve keeps the content up and closes the item
+func (h *ModerationHandler) Approve(c *gin.Context) {
+	if item := h.decide(c, h.moderationService.Approve); item != nil {
+		h.release(c.Request.Context(), item)
+		h.settle(c.Request.Context(), item)
+	}
+}
+
+// Hide takes the content down and closes the item
+func (h *ModerationHandler) Hide(c *gin.Context) {
+	if item := h.decide(c, h.moderationService.Hide); item != nil {
+		h.settle(c.Request.Context(), item)
+	}
+}
+
+// Escalate hands the item to senior moderators
+func (h *ModerationHandler) Escalate(c *gin.Context) {
+	h.decide(c, h.moderationService.Escalate)
+}
+
+// Edit rewrites the flagged text and closes the item
+func (h *ModerationHandler) Edit(c *gin.Context) {
+	id, moderatorID, ok := h.itemAndModerator(c)
+	if !ok {
+		return
+	}
+
+	var req models.ModerationEditRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := h.moderationService.Edit(c.Request.Context(), id, moderatorID, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.audit(c, item)
+	c.JSON(http.StatusOK, gin.H{"item": item})
+	h.release(c.Request.Context(), item)
+	h.settle(c.Request.Context(), item)
+}
+
+// audit records a moderator's decision on an item
+func (h *ModerationHandler) audit(c *gin.Context, item *models.ModerationItem) {
+	recordAudit(c, h.auditService, models.AuditModerationDecided, models.AuditTargetModeration, &item.ID, map[string]interface{}{
+		"status":       item.Status,
+		"content_type": item.ContentType,
+		"content_id":   item.ContentID.Hex(),
+	})
+}
+
+// release lets a need that was held for review reach volunteers
+func (h *ModerationHandler) release(ctx context.Context, item *models.ModerationItem) {
+	if item.ContentType == models.ContentNeed && h.needs != nil {
+		h.needs.releaseNeed(ctx, item.ContentID)
+	}
+}
+
+// settle applies a decision on feedback or kudos to what the recipient's
+// profile shows: appeals and reputation, or kudos tallies. Decisions on
+// safety incidents lift the matching suspensions they caused.
+func (h *ModerationHandler) settle(ctx context.Context, item *models.ModerationItem) {
+	if h.feedback != nil {
+		if err := h.feedback.ApplyDecision(ctx, item); err != nil {
+			log.Printf("Failed to settle feedback %s after moderation: %v", item.ContentID.Hex(), err)
+		}
+	}
+	if h.kudos != nil {
+		if err := h.kudos.ApplyDecision(ctx, item); err != nil {
+			log.Printf("Failed to recount kudos %s after moderation: %v", item.ContentID.Hex(), err)
+		}
+	}
+}
+
+// decide applies a decision that takes only an optional note, returning the
+// updated item or nil once an error response has been written
+func (h *ModerationHandler) decide(c *gin.Context, action func(ctx context.Context, id, moderatorID primitive.ObjectID, note string) (*models.ModerationItem, error)) *models.ModerationItem {
+	id, moderatorID, ok := h.itemAndModerator(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.ModerationDecisionRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return nil
+	}
+
+	item, err := action(c.Request.Context(), id, moderatorID, req.Note)
+	if err != nil {
+		h.respondError(c, err)
+		return nil
+	}
+
+	h.audit(c, item)
+	c.JSON(http.StatusOK, gin.H{"item": item})
+	return item
+}
+
+// itemAndModerator parses the item ID and the acting moderator
+func (h *ModerationHandler) itemAndModerator(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	moderatorID, err := primitive.ObjectIDFromHex(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid moderation item ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	return id, moderatorID, true
+}
+
+// respondError maps moderation service errors to responses
+func (h *ModerationHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrModerationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Moderation item not found"})
+	case errors.Is(err, services.ErrContentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+	case errors.Is(err, services.ErrModerationResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Moderation item already resolved"})
+	case errors.Is(err, services.ErrFieldNotEditable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update moderation item"})
+	}
+} 
\ No newline at end of file
@@ -2,35 +2,213 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"neighborenexus/internal/database"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/jobs"
 	"neighborenexus/internal/middleware"
 	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
 	"neighborenexus/internal/services"
+	"neighborenexus/internal/settings"
 )
 
 // NeedHandler handles need-related requests
 type NeedHandler struct {
-	matchingService   *services.MatchingService
-	websocketService  *services.WebSocketService
-	mongoClient       *database.MongoClient
+	matchingService  *services.MatchingService
+	websocketService *services.WebSocketService
+	mongoClient      *database.MongoClient
+	settings         *settings.Store
+	matchingQueue    *database.RedisClient
+	moderation       *services.ModerationService
+	velocity         *services.VelocityDetector
+	privacy          *services.PrivacyService
+	analytics        *services.AnalyticsService
+	feedback         *services.FeedbackService
+	tasks            *services.TaskService
+	translations     *services.TranslationService
+	composer         *services.NeedComposer
+	transcriber      services.Transcriber
+	sla              *services.SLAService
+	waitlist         *services.WaitlistService
+	notifications    *services.NotificationService
+	categories       *services.CategoryService
+	topics           *services.TopicService
+	expiry           *services.ExpiryService
+	audit            *services.AuditService
 }
 
-// NewNeedHandler creates a new need handler
-func NewNeedHandler(matchingService *services.MatchingService, websocketService *services.WebSocketService, mongoClient *database.MongoClient) *NeedHandler {
+// NewNeedHandler creates a new need handler. When matchingQueue is non-nil,
+// new needs are embedded and matched by the worker instead of in the request.
+// Users owing feedback past feedbackService's deadline cannot accept needs.
+// Task status changes go through taskService.
+// Needs are shown translated into each viewer's preferred language, and
+// needComposer drafts needs from free-form text, including voice notes
+// transcribed by transcriber. A nil transcriber disables voice notes.
+// slaService records when needs are matched, accepted, and completed, and
+// needs nobody matches wait on waitlistService for volunteers to join.
+// notificationService emails users when needs are matched, accepted, and
+// completed. Categories are checked against categoryService's taxonomy,
+// and new needs are published on topicService's topics. Creators extend
+// their needs through expiryService, and deletions are recorded on
+// auditService.
+func NewNeedHandler(matchingService *services.MatchingService, websocketService *services.WebSocketService, mongoClient *database.MongoClient, settingsStore *settings.Store, matchingQueue *database.RedisClient, moderationService *services.ModerationService, velocityDetector *services.VelocityDetector, privacyService *services.PrivacyService, analyticsService *services.AnalyticsService, feedbackService *services.FeedbackService, taskService *services.TaskService, translationService *services.TranslationService, needComposer *services.NeedComposer, transcriber services.Transcriber, slaService *services.SLAService, waitlistService *services.WaitlistService, notificationService *services.NotificationService, categoryService *services.CategoryService, topicService *services.TopicService, expiryService *services.ExpiryService, auditService *services.AuditService) *NeedHandler {
 	return &NeedHandler{
 		matchingService:  matchingService,
 		websocketService: websocketService,
 		mongoClient:      mongoClient,
+		settings:         settingsStore,
+		matchingQueue:    matchingQueue,
+		moderation:       moderationService,
+		velocity:         velocityDetector,
+		privacy:          privacyService,
+		analytics:        analyticsService,
+		feedback:         feedbackService,
+		tasks:            taskService,
+		translations:     translationService,
+		composer:         needComposer,
+		transcriber:      transcriber,
+		sla:              slaService,
+		waitlist:         waitlistService,
+		notifications:    notificationService,
+		categories:       categoryService,
+		topics:           topicService,
+		expiry:           expiryService,
+		audit:            auditService,
 	}
 }
 
+// DraftNeed turns a free-form description of what the user needs into a
+// need for them to review and post, located at their own location
+func (h *NeedHandler) DraftNeed(c *gin.Context) {
+	user, ok := middleware.GetUser(c).(*models.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.DraftNeedRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	loc, ok := requestTimezone(c, req.Timezone)
+	if !ok {
+		return
+	}
+
+	if !h.composer.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Drafting needs from text is not available"})
+		return
+	}
+	draft, ok := h.draftNeed(c, user, req.Text, loc)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"draft": draft})
+}
+
+// DraftNeedFromVoice transcribes a voice note, uploaded as the multipart
+// file "audio" with an optional "timezone" field, and drafts a need from
+// it for the user to review and post. The recording is not kept.
+func (h *NeedHandler) DraftNeedFromVoice(c *gin.Context) {
+	user, ok := middleware.GetUser(c).(*models.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	if h.transcriber == nil || !h.composer.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Voice notes are not available"})
+		return
+	}
+
+	header, err := c.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audio must be an uploaded voice note"})
+		return
+	}
+	if !services.IsAudioFormat(header.Filename) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Tf(middleware.GetLocale(c), "audio must be one of: %s", strings.Join(services.AudioFormats, ", "))})
+		return
+	}
+	loc, ok := requestTimezone(c, c.PostForm("timezone"))
+	if !ok {
+		return
+	}
+
+	audio, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audio must be an uploaded voice note"})
+		return
+	}
+	defer audio.Close()
+
+	transcript, err := h.transcriber.Transcribe(c.Request.Context(), header.Filename, audio, preferredLanguage(c))
+	if err != nil {
+		log.Printf("Failed to transcribe voice note for user %s: %v", user.ID.Hex(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to transcribe voice note"})
+		return
+	}
+	if transcript == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No speech was found in the voice note"})
+		return
+	}
+
+	draft, ok := h.draftNeed(c, user, transcript, loc)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transcript": transcript, "draft": draft})
+}
+
+// draftNeed drafts a need from text at the user's location, writing an
+// error response on failure
+func (h *NeedHandler) draftNeed(c *gin.Context, user *models.User, text string, loc *time.Location) (*models.CreateNeedRequest, bool) {
+	categories, err := h.categories.Roots(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to list categories for drafting: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to draft need"})
+		return nil, false
+	}
+	draft, err := h.composer.Draft(c.Request.Context(), text, categories, time.Now(), loc)
+	if err != nil {
+		log.Printf("Failed to draft need for user %s: %v", user.ID.Hex(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to draft need"})
+		return nil, false
+	}
+	draft.Location = user.Location
+	if draft.Language == "" {
+		draft.Language = preferredLanguage(c)
+	}
+	return draft, true
+}
+
+// requestTimezone loads an IANA time zone given by the client, defaulting
+// to UTC, writing an error response when it is unknown
+func requestTimezone(c *gin.Context, name string) (*time.Location, bool) {
+	if name == "" {
+		return time.UTC, true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timezone must be an IANA time zone name, e.g. America/Chicago"})
+		return nil, false
+	}
+	return loc, true
+}
+
 // CreateNeed creates a new need
 func (h *NeedHandler) CreateNeed(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -40,8 +218,7 @@ func (h *NeedHandler) CreateNeed(c *gin.Context) {
 	}
 
 	var req models.CreateNeedRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -52,6 +229,31 @@ func (h *NeedHandler) CreateNeed(c *gin.Context) {
 		return
 	}
 
+	// Minors and unsupervised youth groups are kept out of some needs
+	if user, ok := middleware.GetUser(c).(*models.User); ok {
+		if err := services.CheckTaskEligibility(user, req.Category, time.Now()); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := h.categories.CheckNeed(c.Request.Context(), req.Category, req.Subcategory); err != nil {
+		respondCategoryError(c, err, "Failed to create need")
+		return
+	}
+
+	// Users caught posting in bursts are blocked for a while
+	if wait := h.velocity.Throttled(c.Request.Context(), userObjectID); wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "You are posting too quickly; try again later"})
+		return
+	}
+
+	// Needs are assumed to be written in the creator's preferred language
+	language := req.Language
+	if language == "" {
+		language = preferredLanguage(c)
+	}
+
 	// Create need
 	need := models.Need{
 		ID:          primitive.NewObjectID(),
@@ -59,18 +261,31 @@ func (h *NeedHandler) CreateNeed(c *gin.Context) {
 		Title:       req.Title,
 		Description: req.Description,
 		Category:    req.Category,
+		Subcategory: req.Subcategory,
 		Urgency:     req.Urgency,
 		Duration:    req.Duration,
-		Location:    req.Location,
-		Status:      "requested",
+		Location:    indexLocation(c, h.privacy, req.Location),
+		Status:      models.NeedStatusRequested,
+		MinTrust:    req.MinTrust,
+		Language:    language,
+		Window:      req.Window,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
+	need.Point = h.privacy.LocationPoint(need.Location)
+	need.MatchCell = need.Location.MatchCell()
+
 	// Set expiration (default 7 days)
 	expiresAt := time.Now().Add(7 * 24 * time.Hour)
 	need.ExpiresAt = &expiresAt
 
+	// Hold suspected scams and spam for review before any volunteer sees them
+	text := need.Title + "\n\n" + need.Description
+	reasons := h.moderation.Classify(c.Request.Context(), text)
+	reasons = append(reasons, h.velocity.CheckPost(c.Request.Context(), userObjectID, c.ClientIP(), text)...)
+	need.HeldForReview = len(reasons) > 0
+
 	// Insert into database
 	collection := h.mongoClient.GetCollection("needs")
 	_, err = collection.InsertOne(c.Request.Context(), need)
@@ -79,60 +294,172 @@ func (h *NeedHandler) CreateNeed(c *gin.Context) {
 		return
 	}
 
-	// Generate embedding for the need
+	matches, err := h.publishNeed(c.Request.Context(), &need, text, reasons)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Need created but embedding generation failed"})
+		return
+	}
+
+	services.LocalizeMatches(matches, preferredUnits(c), preferredLanguage(c))
 	if h.matchingService != nil {
-		err = h.matchingService.UpdateNeedEmbedding(c.Request.Context(), &need)
-		if err != nil {
-			// Log error but don't fail the request
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Need created but embedding generation failed"})
-			return
+		if err := h.matchingService.SummarizeVolunteers(c.Request.Context(), matches); err != nil {
+			log.Printf("Failed to summarize volunteers matched to need %s: %v", need.ID.Hex(), err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, models.NeedResponse{
+		Need:    need,
+		Matches: matches,
+	})
+}
+
+// publishNeed sends a newly created need on its way: held needs to the
+// moderation queue, and the rest to matching. Matches are returned only when
+// matching runs inline.
+func (h *NeedHandler) publishNeed(ctx context.Context, need *models.Need, text string, reasons []string) ([]models.Match, error) {
+	h.analytics.NeedCreated(ctx, need)
+
+	if need.HeldForReview {
+		h.moderation.Queue(ctx, models.ContentNeed, need.ID, need.UserID, text, reasons)
+		return nil, nil
+	}
+	h.announceNeed(ctx, need)
+
+	// Hand matching off to the worker when it runs separately
+	if h.matchingQueue != nil {
+		if err := jobs.EnqueueMatching(ctx, h.matchingQueue, need.ID); err != nil {
+			log.Printf("Failed to queue matching for need %s: %v", need.ID.Hex(), err)
 		}
+		return nil, nil
+	}
+	return h.matchAndNotify(ctx, need)
+}
+
+// matchAndNotify embeds a need, finds matching volunteers, and notifies them.
+// Only embedding failures are returned; matching errors leave matches empty.
+func (h *NeedHandler) matchAndNotify(ctx context.Context, need *models.Need) ([]models.Match, error) {
+	if h.matchingService == nil {
+		return nil, nil
+	}
+
+	// Generate embedding for the need
+	if err := h.matchingService.UpdateNeedEmbedding(ctx, need); err != nil {
+		return nil, err
 	}
 
 	// Find matches for the need
-	var matches []models.Match
-	if h.matchingService != nil {
-		matches, err = h.matchingService.FindMatchesForNeed(c.Request.Context(), &need, 5)
+	fanout := h.settings.Get(ctx).NotificationFanout
+	matches, err := h.matchingService.FindMatchesForNeed(ctx, need, fanout)
+	if err != nil {
+		log.Printf("Failed to match need %s: %v", need.ID.Hex(), err)
+		return nil, nil
+	}
+	h.analytics.MatchesShown(ctx, need, matches, services.MatchSurfaceNotification)
+	if len(matches) == 0 {
+		if err := h.waitlist.Add(ctx, need); err != nil {
+			log.Printf("Failed to waitlist need %s: %v", need.ID.Hex(), err)
+		}
+		return matches, nil
+	}
+	h.sla.Record(ctx, need.ID, models.MilestoneFirstMatch, time.Now())
+
+	// Notify relevant volunteers via WebSocket and email
+	if h.websocketService != nil {
+		recipients, err := h.matchingService.Recipients(ctx, matches)
 		if err != nil {
-			// Log error but don't fail the request
+			log.Printf("Failed to look up volunteers to notify of need %s: %v", need.ID.Hex(), err)
 		}
+		h.websocketService.NotifyNewNeed(*need, recipients)
+		h.notifications.NewMatches(ctx, *need, recipients)
+		h.matchingService.MarkNotified(ctx, need.ID, recipients)
 	}
 
-	// Notify relevant volunteers via WebSocket
-	if h.websocketService != nil && len(matches) > 0 {
-		volunteerIDs := make([]string, len(matches))
-		for i, match := range matches {
-			volunteerIDs[i] = match.VolunteerID.Hex()
+	return matches, nil
+}
+
+// embedNeed regenerates a changed need's embedding, on the worker when it
+// runs separately. Failures leave the old embedding in place, so they are
+// logged.
+func (h *NeedHandler) embedNeed(ctx context.Context, need *models.Need) {
+	if h.matchingQueue != nil {
+		if err := jobs.EnqueueEmbedding(ctx, h.matchingQueue, jobs.EmbeddingKindNeed, need.ID); err != nil {
+			log.Printf("Failed to queue embedding for need %s: %v", need.ID.Hex(), err)
 		}
-		h.websocketService.NotifyNewNeed(need, volunteerIDs)
+		return
 	}
+	if h.matchingService == nil {
+		return
+	}
+	if err := h.matchingService.UpdateNeedEmbedding(ctx, need); err != nil {
+		log.Printf("Failed to embed need %s: %v", need.ID.Hex(), err)
+	}
+}
 
-	c.JSON(http.StatusCreated, models.NeedResponse{
-		Need:    need,
-		Matches: matches,
-	})
+// announceNeed publishes a need on its topics once it is visible. A failure
+// only means followers learn of the need from the list, so it is logged.
+func (h *NeedHandler) announceNeed(ctx context.Context, need *models.Need) {
+	if err := h.topics.PublishNeed(ctx, need); err != nil {
+		log.Printf("Failed to publish need %s to its topics: %v", need.ID.Hex(), err)
+	}
+}
+
+// releaseNeed clears a moderation hold and runs the matching that was
+// skipped when the need was created
+func (h *NeedHandler) releaseNeed(ctx context.Context, needID primitive.ObjectID) {
+	var need models.Need
+	err := h.mongoClient.GetCollection("needs").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": needID, "held_for_review": true, "hidden": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"held_for_review": false, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to release need %s: %v", needID.Hex(), err)
+		return
+	}
+	h.announceNeed(ctx, &need)
+	h.rematchNeed(ctx, &need)
 }
 
-// GetNeeds retrieves needs with optional filtering
+// rematchNeed runs matching for a need that was kept out of it, on the
+// worker when it runs separately
+func (h *NeedHandler) rematchNeed(ctx context.Context, need *models.Need) {
+	if h.matchingQueue != nil {
+		if err := jobs.EnqueueMatching(ctx, h.matchingQueue, need.ID); err != nil {
+			log.Printf("Failed to queue matching for need %s: %v", need.ID.Hex(), err)
+		}
+		return
+	}
+	if _, err := h.matchAndNotify(ctx, need); err != nil {
+		log.Printf("Failed to match need %s: %v", need.ID.Hex(), err)
+	}
+}
+
+// GetNeeds retrieves needs with optional filtering, newest first and paged
+// with ?limit= and ?cursor=. ?lat=&lng= with an optional ?radius_km= limits
+// them to needs near a point, nearest first.
 func (h *NeedHandler) GetNeeds(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
 		return
 	}
 
 	// Parse query parameters
 	status := c.Query("status")
 	category := c.Query("category")
-	limit := 20 // Default limit
 
-	// Build filter
-	filter := bson.M{}
+	// Build filter, leaving out needs hidden or held by moderation
+	filter := bson.M{"hidden": bson.M{"$ne": true}, "held_for_review": bson.M{"$ne": true}}
 	if status != "" {
 		filter["status"] = status
 	}
 	if category != "" {
-		filter["category"] = category
+		for key, value := range services.CategoryFilter(category) {
+			filter[key] = value
+		}
 	}
 
 	// Add expiration filter
@@ -141,10 +468,59 @@ func (h *NeedHandler) GetNeeds(c *gin.Context) {
 		{"expires_at": bson.M{"$gt": time.Now()}},
 	}
 
-	// Query database
+	// Browse needs near a point, matched against their cell centers
+	near, ok := parseNearQuery(c)
+	if !ok {
+		return
+	}
+	if near != nil {
+		filter["point"] = near.within()
+	}
+
+	fs, err := parseFieldset(c, needSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Nearest-first needs page by position; shaped ones stay newest first
+	nearest := near != nil && !fs.requested()
+	p, ok := parsePage(c, nearest)
+	if !ok {
+		return
+	}
+
+	// Only return needs changed since the client's last poll
+	if !checkModified(c, h.mongoClient, "needs", filter) {
+		return
+	}
+
+	if fs.requested() {
+		p.apply(filter)
+		needs, err := aggregateFieldset(c.Request.Context(), h.mongoClient, "needs", fs, filter, p.sort(), p.fetch())
+		var next *string
+		if err == nil {
+			needs, next = pageAfter(p, needs, docID)
+			err = shapeNeedDocs(c.Request.Context(), h.privacy, userObjectID, needs)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve needs"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"needs": needs, "next_cursor": next})
+		return
+	}
+
+	// Query database. Needs near a point come nearest first.
 	collection := h.mongoClient.GetCollection("needs")
-	opts := mongo.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
-	
+	opts := options.Find().SetSort(p.sort()).SetLimit(p.fetch())
+	if nearest {
+		filter["point"] = near.nearest()
+		opts = options.Find().SetSkip(p.cursor.Offset).SetLimit(p.fetch())
+	} else {
+		p.apply(filter)
+	}
+
 	cursor, err := collection.Find(c.Request.Context(), filter, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve needs"})
@@ -157,12 +533,29 @@ func (h *NeedHandler) GetNeeds(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode needs"})
 		return
 	}
+	var next *string
+	if nearest {
+		needs, next = pageOffset(p, needs)
+	} else {
+		needs, next = pageAfter(p, needs, func(need models.Need) primitive.ObjectID { return need.ID })
+	}
 
-	c.JSON(http.StatusOK, gin.H{"needs": needs})
+	h.translations.TranslateNeeds(c.Request.Context(), preferredLanguage(c), needs)
+	if err := shapeNeeds(c.Request.Context(), h.privacy, userObjectID, needs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve needs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"needs": needs, "next_cursor": next})
 }
 
 // GetNeed retrieves a specific need
 func (h *NeedHandler) GetNeed(c *gin.Context) {
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
 	needID := c.Param("id")
 	if needID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Need ID required"})
@@ -175,9 +568,38 @@ func (h *NeedHandler) GetNeed(c *gin.Context) {
 		return
 	}
 
+	fs, err := parseFieldset(c, needSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Needs moderation hid or holds for review are shown only to their owner
+	filter := bson.M{"_id": objectID, "$or": bson.A{
+		bson.M{"user_id": userObjectID},
+		bson.M{"hidden": bson.M{"$ne": true}, "held_for_review": bson.M{"$ne": true}},
+	}}
+
+	if fs.requested() {
+		needs, err := aggregateFieldset(c.Request.Context(), h.mongoClient, "needs", fs, filter, nil, 1)
+		if err == nil {
+			err = shapeNeedDocs(c.Request.Context(), h.privacy, userObjectID, needs)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve need"})
+			return
+		}
+		if len(needs) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"need": needs[0]})
+		return
+	}
+
 	collection := h.mongoClient.GetCollection("needs")
 	var need models.Need
-	err = collection.FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&need)
+	err = collection.FindOne(c.Request.Context(), filter).Decode(&need)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
@@ -186,15 +608,20 @@ func (h *NeedHandler) GetNeed(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve need"})
 		return
 	}
+	needs := []models.Need{need}
+	h.translations.TranslateNeeds(c.Request.Context(), preferredLanguage(c), needs)
+	if err := shapeNeeds(c.Request.Context(), h.privacy, userObjectID, needs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve need"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"need": need})
+	c.JSON(http.StatusOK, gin.H{"need": needs[0]})
 }
 
 // UpdateNeed updates a need
 func (h *NeedHandler) UpdateNeed(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, ok := requireUserObjectID(c)
+	if !ok {
 		return
 	}
 
@@ -211,18 +638,23 @@ func (h *NeedHandler) UpdateNeed(c *gin.Context) {
 	}
 
 	var req struct {
-		Title       string            `json:"title,omitempty"`
-		Description string            `json:"description,omitempty"`
-		Category    string            `json:"category,omitempty"`
-		Urgency     string            `json:"urgency,omitempty"`
-		Duration    int               `json:"duration,omitempty"`
-		Location    models.Location   `json:"location,omitempty"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		Title       string             `json:"title,omitempty" binding:"max=200"`
+		Description string             `json:"description,omitempty" binding:"max=5000"`
+		Category    models.Category    `json:"category,omitempty" binding:"omitempty,category"`
+		Subcategory *string            `json:"subcategory,omitempty" binding:"omitempty,max=200"`
+		Urgency     models.Urgency     `json:"urgency,omitempty" binding:"omitempty,enum"`
+		Duration    int                `json:"duration,omitempty"`
+		Location    models.Location    `json:"location,omitempty"`
+		Language    models.Language    `json:"language,omitempty" binding:"omitempty,enum"`
+		Window      *models.TimeWindow `json:"window,omitempty"`
+	}
+
+	if !bindJSON(c, &req) {
 		return
 	}
+	req.Title = sanitize.Text(req.Title)
+	req.Description = sanitize.Text(req.Description)
+	req.Location.Address = sanitize.Text(req.Location.Address)
 
 	// Build update fields
 	updates := bson.M{"updated_at": time.Now()}
@@ -242,7 +674,50 @@ func (h *NeedHandler) UpdateNeed(c *gin.Context) {
 		updates["duration"] = req.Duration
 	}
 	if req.Location.Latitude != 0 || req.Location.Longitude != 0 {
-		updates["location"] = req.Location
+		location := indexLocation(c, h.privacy, req.Location)
+		updates["location"] = location
+		updates["point"] = h.privacy.LocationPoint(location)
+		updates["match_cell"] = location.MatchCell()
+	}
+	if req.Language != "" {
+		updates["language"] = req.Language
+	}
+	if req.Window != nil {
+		updates["window"] = req.Window
+	}
+	update := bson.M{"$set": updates}
+	unset := bson.M{}
+
+	// A subcategory files the need under its category; a new category
+	// without one leaves the old subcategory behind
+	switch {
+	case req.Subcategory != nil && *req.Subcategory != "":
+		category := models.CategoryRoot(*req.Subcategory)
+		if req.Category != "" {
+			category = req.Category
+		}
+		if err := h.categories.CheckNeed(c.Request.Context(), category, *req.Subcategory); err != nil {
+			respondCategoryError(c, err, "Failed to update need")
+			return
+		}
+		updates["category"] = category
+		updates["subcategory"] = *req.Subcategory
+	case req.Subcategory != nil || req.Category != "":
+		if req.Category != "" {
+			if err := h.categories.CheckNeed(c.Request.Context(), req.Category, ""); err != nil {
+				respondCategoryError(c, err, "Failed to update need")
+				return
+			}
+		}
+		unset["subcategory"] = ""
+	}
+
+	// Cached translations are of the old text
+	if req.Title != "" || req.Description != "" || req.Language != "" {
+		unset["translations"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
 
 	// Update in database
@@ -250,7 +725,7 @@ func (h *NeedHandler) UpdateNeed(c *gin.Context) {
 	result, err := collection.UpdateOne(
 		c.Request.Context(),
 		bson.M{"_id": objectID, "user_id": userID}, // Only allow owner to update
-		bson.M{"$set": updates},
+		update,
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update need"})
@@ -262,12 +737,18 @@ func (h *NeedHandler) UpdateNeed(c *gin.Context) {
 		return
 	}
 
-	// Regenerate embedding if content changed
-	if req.Title != "" || req.Description != "" || req.Category != "" {
+	// Re-screen and regenerate embedding if content changed
+	if req.Title != "" || req.Description != "" || req.Category != "" || req.Subcategory != nil {
 		var need models.Need
 		err = collection.FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&need)
-		if err == nil && h.matchingService != nil {
-			h.matchingService.UpdateNeedEmbedding(c.Request.Context(), &need)
+		if err == nil && h.moderation.Screen(c.Request.Context(), models.ContentNeed, need.ID, need.UserID, need.Title+"\n\n"+need.Description) {
+			_, err = collection.UpdateOne(c.Request.Context(), bson.M{"_id": need.ID}, bson.M{"$set": bson.M{"held_for_review": true}})
+			if err != nil {
+				log.Printf("Failed to hold need %s for review: %v", need.ID.Hex(), err)
+			}
+		}
+		if err == nil {
+			h.embedNeed(c.Request.Context(), &need)
 		}
 	}
 
@@ -276,9 +757,8 @@ func (h *NeedHandler) UpdateNeed(c *gin.Context) {
 
 // DeleteNeed deletes a need
 func (h *NeedHandler) DeleteNeed(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, ok := requireUserObjectID(c)
+	if !ok {
 		return
 	}
 
@@ -294,22 +774,62 @@ func (h *NeedHandler) DeleteNeed(c *gin.Context) {
 		return
 	}
 
-	collection := h.mongoClient.GetCollection("needs")
-	result, err := collection.DeleteOne(
+	var need models.Need
+	err = h.mongoClient.GetCollection("needs").FindOneAndDelete(
 		c.Request.Context(),
 		bson.M{"_id": objectID, "user_id": userID}, // Only allow owner to delete
-	)
+		options.FindOneAndDelete().SetProjection(needSnapshotProjection),
+	).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found or not owned by user"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete need"})
 		return
 	}
 
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found or not owned by user"})
+	recordAuditChange(c, h.audit, models.AuditNeedDeleted, models.AuditTargetNeed, &objectID, needSnapshot(need), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Need deleted successfully"})
+}
+
+// ExtendNeed keeps one of the current user's needs open for another week
+// from now, once it is within two days of expiring. An expired need is
+// reopened and matched again, so volunteers hear of it anew.
+func (h *NeedHandler) ExtendNeed(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Need deleted successfully"})
+	need, reopened, err := h.expiry.Extend(c.Request.Context(), needID, userID)
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrNeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+		return
+	case errors.Is(err, services.ErrNotNeedOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrNeedClosed), errors.Is(err, services.ErrNeedNotExpiring):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	default:
+		log.Printf("Failed to extend need %s: %v", needID.Hex(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to extend need"})
+		return
+	}
+
+	if reopened && !need.Hidden && !need.HeldForReview {
+		h.rematchNeed(c.Request.Context(), need)
+	}
+	c.JSON(http.StatusOK, gin.H{"need": need})
 }
 
 // AcceptNeed accepts a need (creates a task)
@@ -341,7 +861,12 @@ func (h *NeedHandler) AcceptNeed(c *gin.Context) {
 	// Check if need exists and is available
 	needsCollection := h.mongoClient.GetCollection("needs")
 	var need models.Need
-	err = needsCollection.FindOne(c.Request.Context(), bson.M{"_id": needObjectID, "status": "requested"}).Decode(&need)
+	err = needsCollection.FindOne(c.Request.Context(), bson.M{
+		"_id":             needObjectID,
+		"status":          models.NeedStatusRequested,
+		"hidden":          bson.M{"$ne": true},
+		"held_for_review": bson.M{"$ne": true},
+	}).Decode(&need)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Need not found or already accepted"})
@@ -357,12 +882,44 @@ func (h *NeedHandler) AcceptNeed(c *gin.Context) {
 		return
 	}
 
+	// Minors and unsupervised youth groups are kept out of some needs, and
+	// requesters can keep sensitive needs to trusted volunteers
+	if user, ok := middleware.GetUser(c).(*models.User); ok {
+		if err := services.CheckTaskEligibility(user, need.Category, time.Now()); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if user.Suspension != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": services.ErrMatchingSuspended.Error()})
+			return
+		}
+		minTrust := services.RequiredTrust(&need, h.settings.Get(c.Request.Context()))
+		if minTrust != "" && (user.Trust == nil || !user.Trust.Level.AtLeast(minTrust)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": services.ErrTrustTooLow.Error(), "min_trust": minTrust})
+			return
+		}
+	}
+
+	// Deployments can require feedback on past tasks before taking new ones
+	if h.feedback != nil {
+		overdue, err := h.feedback.Overdue(c.Request.Context(), userObjectID)
+		if err != nil {
+			log.Printf("Failed to check overdue feedback for %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check pending feedback"})
+			return
+		}
+		if len(overdue) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": services.ErrFeedbackOverdue.Error(), "task_ids": overdue})
+			return
+		}
+	}
+
 	// Create task
 	task := models.Task{
 		ID:          primitive.NewObjectID(),
 		NeedID:      needObjectID,
 		VolunteerID: userObjectID,
-		Status:      "accepted",
+		Status:      models.TaskStatusAccepted,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -378,18 +935,27 @@ func (h *NeedHandler) AcceptNeed(c *gin.Context) {
 	_, err = needsCollection.UpdateOne(
 		c.Request.Context(),
 		bson.M{"_id": needObjectID},
-		bson.M{"$set": bson.M{"status": "matched", "updated_at": time.Now()}},
+		bson.M{"$set": bson.M{"status": models.NeedStatusMatched, "updated_at": time.Now()}},
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update need status"})
 		return
 	}
+	h.analytics.MatchAccepted(c.Request.Context(), &need, &task)
+	h.sla.Record(c.Request.Context(), needObjectID, models.MilestoneAccepted, task.CreatedAt)
+	if h.matchingService != nil {
+		h.matchingService.MarkAccepted(c.Request.Context(), userObjectID, needObjectID)
+	}
 
-	// Notify need creator via WebSocket
+	// Notify need creator via WebSocket and email
+	volunteerName := models.AnonymousName
+	if user, ok := middleware.GetUser(c).(*models.User); ok {
+		volunteerName = user.DisplayName()
+	}
 	if h.websocketService != nil {
-		needCreatorID := need.UserID.Hex()
-		h.websocketService.NotifyNeedAccepted(needID, userID, "Volunteer") // You'd get the actual volunteer name
+		h.websocketService.NotifyNeedAccepted(needID, userID, volunteerName)
 	}
+	h.notifications.NeedAccepted(c.Request.Context(), need, task, volunteerName)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Need accepted successfully",
@@ -397,7 +963,8 @@ func (h *NeedHandler) AcceptNeed(c *gin.Context) {
 	})
 }
 
-// GetTasks retrieves tasks for the current user
+// GetTasks retrieves tasks for the current user, newest first and paged
+// with ?limit= and ?cursor=
 func (h *NeedHandler) GetTasks(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -419,56 +986,58 @@ func (h *NeedHandler) GetTasks(c *gin.Context) {
 		},
 	}
 
-	cursor, err := collection.Find(c.Request.Context(), filter)
+	fs, err := parseFieldset(c, taskSpec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer cursor.Close(c.Request.Context())
-
-	var tasks []models.Task
-	if err = cursor.All(c.Request.Context(), &tasks); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode tasks"})
+	p, ok := parsePage(c, false)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
-}
+	// Only return tasks changed since the client's last poll
+	if !checkModified(c, h.mongoClient, "tasks", filter) {
+		return
+	}
+	p.apply(filter)
 
-// GetTask retrieves a specific task
-func (h *NeedHandler) GetTask(c *gin.Context) {
-	taskID := c.Param("id")
-	if taskID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Task ID required"})
+	if fs.requested() {
+		tasks, err := aggregateFieldset(c.Request.Context(), h.mongoClient, "tasks", fs, filter, p.sort(), p.fetch())
+		var next *string
+		if err == nil {
+			tasks, next = pageAfter(p, tasks, docID)
+			err = shapeTaskDocs(c.Request.Context(), h.privacy, userObjectID, tasks)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"tasks": tasks, "next_cursor": next})
 		return
 	}
 
-	objectID, err := primitive.ObjectIDFromHex(taskID)
+	cursor, err := collection.Find(c.Request.Context(), filter, options.Find().SetSort(p.sort()).SetLimit(p.fetch()))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
 		return
 	}
+	defer cursor.Close(c.Request.Context())
 
-	collection := h.mongoClient.GetCollection("tasks")
-	var task models.Task
-	err = collection.FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&task)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+	var tasks []models.Task
+	if err = cursor.All(c.Request.Context(), &tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode tasks"})
 		return
 	}
+	tasks, next := pageAfter(p, tasks, func(task models.Task) primitive.ObjectID { return task.ID })
 
-	c.JSON(http.StatusOK, gin.H{"task": task})
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks, "next_cursor": next})
 }
 
-// UpdateTaskStatus updates a task's status
-func (h *NeedHandler) UpdateTaskStatus(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+// GetTask retrieves a specific task
+func (h *NeedHandler) GetTask(c *gin.Context) {
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
 		return
 	}
 
@@ -478,133 +1047,89 @@ func (h *NeedHandler) UpdateTaskStatus(c *gin.Context) {
 		return
 	}
 
-	var req models.UpdateTaskStatusRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
-		return
-	}
-
 	objectID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
 		return
 	}
 
-	// Build update fields
-	updates := bson.M{
-		"status":     req.Status,
-		"updated_at": time.Now(),
-	}
-	if req.ScheduledAt != nil {
-		updates["scheduled_at"] = req.ScheduledAt
-	}
-	if req.Notes != "" {
-		updates["notes"] = req.Notes
-	}
-
-	// Update task
-	collection := h.mongoClient.GetCollection("tasks")
-	result, err := collection.UpdateOne(
-		c.Request.Context(),
-		bson.M{"_id": objectID},
-		bson.M{"$set": updates},
-	)
+	fs, err := parseFieldset(c, taskSpec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	if fs.requested() {
+		tasks, err := aggregateFieldset(c.Request.Context(), h.mongoClient, "tasks", fs, bson.M{"_id": objectID}, nil, 1)
+		if err == nil {
+			err = shapeTaskDocs(c.Request.Context(), h.privacy, userObjectID, tasks)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+			return
+		}
+		if len(tasks) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"task": tasks[0]})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Task status updated successfully"})
-}
-
-// SubmitFeedback submits feedback for a completed task
-func (h *NeedHandler) SubmitFeedback(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	collection := h.mongoClient.GetCollection("tasks")
+	var task models.Task
+	err = collection.FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
 		return
 	}
 
-	taskID := c.Param("id")
-	if taskID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Task ID required"})
-		return
-	}
+	c.JSON(http.StatusOK, gin.H{"task": task})
+}
 
-	var req models.FeedbackRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+// UpdateTaskStatus updates the status of a task the user participates in.
+// Marking a task completed waits on the other participant's confirmation.
+func (h *NeedHandler) UpdateTaskStatus(c *gin.Context) {
+	userObjectID, ok := requireUserObjectID(c)
+	if !ok {
 		return
 	}
 
-	objectID, err := primitive.ObjectIDFromHex(taskID)
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
 		return
 	}
 
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	var req models.UpdateTaskStatusRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	// Get task to determine who to give feedback to
-	collection := h.mongoClient.GetCollection("tasks")
-	var task models.Task
-	err = collection.FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&task)
+	task, err := h.tasks.UpdateStatus(c.Request.Context(), userObjectID, taskID, req)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		respondTaskError(c, err)
 		return
 	}
 
-	// Determine who is giving feedback to whom
-	var fromUserID, toUserID primitive.ObjectID
-	if task.VolunteerID == userObjectID {
-		// Volunteer is giving feedback to need creator
-		fromUserID = userObjectID
-		toUserID = task.NeedID // This should be the need creator's ID, but we need to get it from the need
-		
-		// Get the need to find the creator
-		needsCollection := h.mongoClient.GetCollection("needs")
-		var need models.Need
-		err = needsCollection.FindOne(c.Request.Context(), bson.M{"_id": task.NeedID}).Decode(&need)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get need details"})
-			return
-		}
-		toUserID = need.UserID
-	} else {
-		// Need creator is giving feedback to volunteer
-		fromUserID = userObjectID
-		toUserID = task.VolunteerID
-	}
-
-	// Create feedback
-	feedback := models.Feedback{
-		ID:         primitive.NewObjectID(),
-		TaskID:     objectID,
-		FromUserID: fromUserID,
-		ToUserID:   toUserID,
-		Rating:     req.Rating,
-		Comment:    req.Comment,
-		CreatedAt:  time.Now(),
-	}
+	c.JSON(http.StatusOK, gin.H{"message": "Task status updated successfully", "task": task})
+}
 
-	feedbackCollection := h.mongoClient.GetCollection("feedback")
-	_, err = feedbackCollection.InsertOne(c.Request.Context(), feedback)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit feedback"})
-		return
+// respondTaskError maps task status errors to responses
+func respondTaskError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, services.ErrInvalidTaskTransition):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTaskClosed), errors.Is(err, services.ErrTaskStatusConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Failed to update task: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
 	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Feedback submitted successfully",
-		"feedback": feedback,
-	})
 } 
\ No newline at end of file
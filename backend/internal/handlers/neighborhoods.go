@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/services"
+)
+
+// Neighborhood region sizes: resolution 7 cells are about 5 km², a
+// reasonable area for an organizer to recruit in
+const (
+	neighborhoodDefaultResolution = 7
+	neighborhoodMinResolution     = 4
+	neighborhoodMaxResolution     = 9
+)
+
+// NeighborhoodHandler reports supply and demand across neighborhoods
+type NeighborhoodHandler struct {
+	matchingService *services.MatchingService
+}
+
+// NewNeighborhoodHandler creates a new neighborhood handler
+func NewNeighborhoodHandler(matchingService *services.MatchingService) *NeighborhoodHandler {
+	return &NeighborhoodHandler{
+		matchingService: matchingService,
+	}
+}
+
+// GetHealth lists regions by how under-served they are. ?resolution= sets the
+// H3 region size and ?limit= caps the number of regions returned.
+func (h *NeighborhoodHandler) GetHealth(c *gin.Context) {
+	resolution := neighborhoodDefaultResolution
+	if value := c.Query("resolution"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < neighborhoodMinResolution || parsed > neighborhoodMaxResolution {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "resolution must be between 4 and 9"})
+			return
+		}
+		resolution = parsed
+	}
+
+	regions, err := h.matchingService.NeighborhoodHealth(c.Request.Context(), resolution)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute neighborhood health"})
+		return
+	}
+
+	total := len(regions)
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit < len(regions) {
+		regions = regions[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolution": resolution, "regions": regions, "total": total})
+} 
\ No newline at end of file
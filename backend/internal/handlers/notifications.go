@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// NotificationHandler handles the current user's email notification
+// preferences and their inbox of notifications
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+	inboxService        *services.InboxService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *services.NotificationService, inboxService *services.InboxService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService, inboxService: inboxService}
+}
+
+// ListNotifications lists the current user's notifications, newest first,
+// with how many are unread. ?unread=true lists only unread ones.
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	p, ok := parsePage(c, false)
+	if !ok {
+		return
+	}
+
+	notifications, err := h.inboxService.List(c.Request.Context(), services.NotificationQuery{
+		UserID:     userID,
+		Before:     p.cursor.After,
+		UnreadOnly: c.Query("unread") == "true",
+		Limit:      p.fetch(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notifications"})
+		return
+	}
+	unread, err := h.inboxService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count unread notifications"})
+		return
+	}
+	notifications, next := pageAfter(p, notifications, func(notification models.Notification) primitive.ObjectID { return notification.ID })
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread_count": unread, "next_cursor": next})
+}
+
+// MarkNotificationRead marks one of the current user's notifications as read
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = h.inboxService.MarkRead(c.Request.Context(), id, userID)
+	if errors.Is(err, services.ErrNotificationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification read"})
+		return
+	}
+	h.respondUnread(c, userID, gin.H{})
+}
+
+// MarkAllNotificationsRead marks all of the current user's notifications as
+// read
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	read, err := h.inboxService.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notifications read"})
+		return
+	}
+	h.respondUnread(c, userID, gin.H{"read": read})
+}
+
+// respondUnread responds with body and the user's unread count, which may
+// be nonzero after marking read if notifications arrived meanwhile
+func (h *NotificationHandler) respondUnread(c *gin.Context, userID primitive.ObjectID, body gin.H) {
+	unread, err := h.inboxService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count unread notifications"})
+		return
+	}
+	body["unread_count"] = unread
+	c.JSON(http.StatusOK, body)
+}
+
+// GetPreferences returns the current user's notification preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": user.Notifications})
+}
+
+// UpdatePreferences changes which events the current user is emailed about
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req == (models.UpdateNotificationPreferencesRequest{}) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.Request.Context(), user.ID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": preferences})
+} 
\ No newline at end of file
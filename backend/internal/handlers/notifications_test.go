@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/apitest"
+	"neighborenexus/internal/fakes"
+	"neighborenexus/internal/handlers"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+func TestGetNotificationPreferences(t *testing.T) {
+	server := apitest.New(t, func(api, protected *gin.RouterGroup) {
+		h := handlers.NewNotificationHandler(nil, nil)
+		protected.GET("/profile/notifications", h.GetPreferences)
+	})
+	token := server.SignIn(&models.User{
+		Name:          "Ada",
+		Notifications: models.NotificationPreferences{MuteNewMatches: true},
+	})
+
+	var body struct {
+		Notifications models.NotificationPreferences `json:"notifications"`
+	}
+	server.Get("/api/v1/profile/notifications", token).Expect(t, http.StatusOK).Decode(t, &body)
+	if !body.Notifications.MuteNewMatches || body.Notifications.MuteNeedAccepted {
+		t.Fatalf("got %+v, want only new matches muted", body.Notifications)
+	}
+
+	server.Get("/api/v1/profile/notifications", "").Expect(t, http.StatusUnauthorized)
+}
+func TestListAndReadNotifications(t *testing.T) {
+	store := &fakes.NotificationStore{}
+	inbox := services.NewInboxService(store, 0)
+	server := apitest.New(t, func(api, protected *gin.RouterGroup) {
+		h := handlers.NewNotificationHandler(nil, inbox)
+		protected.GET("/notifications", h.ListNotifications)
+		protected.POST("/notifications/:id/read", h.MarkNotificationRead)
+	})
+	ada := &models.User{Name: "Ada"}
+	token := server.SignIn(ada)
+	other := server.SignIn(&models.User{Name: "Grace"})
+
+	ctx := context.Background()
+	for _, messageType := range []string{"new_match", "need_accepted", "task_completed"} {
+		if err := inbox.Store(ctx, "", []string{ada.ID.Hex()}, models.WebSocketMessage{Type: messageType}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var page struct {
+		Notifications []models.Notification `json:"notifications"`
+		UnreadCount   int64                 `json:"unread_count"`
+		NextCursor    *string               `json:"next_cursor"`
+	}
+	server.Get("/api/v1/notifications?limit=2", token).Expect(t, http.StatusOK).Decode(t, &page)
+	if len(page.Notifications) != 2 || page.Notifications[0].Type != "task_completed" || page.UnreadCount != 3 || page.NextCursor == nil {
+		t.Fatalf("got %+v, want the newest two of three unread and a cursor", page)
+	}
+	newest := page.Notifications[0].ID.Hex()
+
+	server.Post("/api/v1/notifications/"+newest+"/read", other, nil).Expect(t, http.StatusNotFound)
+	server.Post("/api/v1/notifications/"+newest+"/read", token, nil).Expect(t, http.StatusOK)
+
+	page.NextCursor = nil
+	server.Get("/api/v1/notifications?unread=true", token).Expect(t, http.StatusOK).Decode(t, &page)
+	if len(page.Notifications) != 2 || page.UnreadCount != 2 || page.NextCursor != nil {
+		t.Fatalf("got %+v, want the two unread notifications on one page", page)
+	}
+	for _, notification := range page.Notifications {
+		if notification.ID.Hex() == newest {
+			t.Fatalf("got %s among unread after reading it", newest)
+		}
+	}
+} 
\ No newline at end of file
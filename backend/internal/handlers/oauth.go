@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/oauth"
+	"neighborenexus/internal/services"
+)
+
+// oauthStateCookie holds the state of a sign-in the browser started, so a
+// callback link sent to someone else cannot sign them into the sender's
+// account. Apple posts its callback cross-site, so the cookie is
+// SameSite=None.
+const oauthStateCookie = "oauth_state"
+
+// StartOAuth redirects to a provider's sign-in page. ?date_of_birth= lets
+// the first sign-in of someone without an account create one.
+func (h *AuthHandler) StartOAuth(c *gin.Context) {
+	dateOfBirth := c.Query("date_of_birth")
+	if dateOfBirth != "" {
+		if _, err := services.ParseDateOfBirth(dateOfBirth, time.Now()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	authURL, state, err := h.authService.StartOAuth(models.OAuthProvider(c.Param("provider")), dateOfBirth, models.Language(middleware.GetLocale(c)))
+	if err != nil {
+		h.respondOAuthError(c, err)
+		return
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/api",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OAuthCallback completes signing in with a provider and issues the same
+// tokens as logging in with a password. Google redirects here with a query;
+// Apple posts a form.
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	value := func(key string) string {
+		if v, ok := c.GetPostForm(key); ok {
+			return v
+		}
+		return c.Query(key)
+	}
+
+	// The state is single-use, so the cookie is cleared whatever happens
+	cookie, _ := c.Cookie(oauthStateCookie)
+	http.SetCookie(c.Writer, &http.Cookie{Name: oauthStateCookie, Path: "/api", MaxAge: -1, HttpOnly: true, Secure: true, SameSite: http.SameSiteNoneMode})
+
+	if reason := value("error"); reason != "" {
+		h.finishOAuth(c, http.StatusBadRequest, gin.H{"error": "Sign-in was cancelled or refused", "reason": reason})
+		return
+	}
+	state, code := value("state"), value("code")
+	if state == "" || cookie != state {
+		h.respondOAuthError(c, services.ErrInvalidOAuthState)
+		return
+	}
+	if code == "" {
+		h.finishOAuth(c, http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	provider := models.OAuthProvider(c.Param("provider"))
+	response, created, err := h.authService.OAuthLogin(c.Request.Context(), provider, code, state, appleName(value("user")), sessionClient(c))
+	if err != nil {
+		recordAudit(c, h.auditService, models.AuditLoginFailed, "", nil, map[string]interface{}{"provider": provider})
+		h.respondOAuthError(c, err)
+		return
+	}
+
+	// Accounts created here are checked for sock puppets like registrations
+	if created {
+		h.velocity.RecordAccount(c.Request.Context(), c.ClientIP(), &response.User)
+	}
+	entry := auditEntry(c, models.AuditLogin, models.AuditTargetUser, &response.User.ID, map[string]interface{}{"provider": provider})
+	entry.ActorID = &response.User.ID
+	h.auditService.Record(c.Request.Context(), entry)
+
+	if h.oauthCompleteURL != "" {
+		h.finishOAuth(c, http.StatusOK, gin.H{"token": response.Token, "refresh_token": response.RefreshToken})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// finishOAuth ends a sign-in with body: by redirecting to the web app's
+// completion page with it in the URL fragment, which stays out of server
+// logs, or else as JSON
+func (h *AuthHandler) finishOAuth(c *gin.Context, status int, body gin.H) {
+	if h.oauthCompleteURL == "" {
+		c.JSON(status, body)
+		return
+	}
+	fragment := url.Values{}
+	for key, value := range body {
+		if s, ok := value.(string); ok {
+			fragment.Set(key, s)
+		}
+	}
+	c.Redirect(http.StatusFound, h.oauthCompleteURL+"#"+fragment.Encode())
+}
+
+// respondOAuthError maps sign-in errors to responses
+func (h *AuthHandler) respondOAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrOAuthProviderUnavailable):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidOAuthState):
+		h.finishOAuth(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, oauth.ErrInvalidIDToken):
+		log.Printf("Rejected OAuth ID token: %v", err)
+		h.finishOAuth(c, http.StatusUnauthorized, gin.H{"error": "The provider's sign-in could not be verified"})
+	case errors.Is(err, services.ErrOAuthEmailUnverified):
+		h.finishOAuth(c, http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAccountSuspended):
+		h.finishOAuth(c, http.StatusForbidden, gin.H{"error": "Account suspended"})
+	case errors.Is(err, services.ErrOAuthSignupIncomplete):
+		h.finishOAuth(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "code": "date_of_birth_required"})
+	default:
+		log.Printf("Failed to sign in with OAuth: %v", err)
+		h.finishOAuth(c, http.StatusInternalServerError, gin.H{"error": "Failed to sign in"})
+	}
+}
+
+// appleName returns the name in the user field Apple posts the first time
+// someone signs in, or ""
+func appleName(user string) string {
+	if user == "" {
+		return ""
+	}
+	var parsed struct {
+		Name struct {
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		} `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(user), &parsed); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(parsed.Name.FirstName + " " + parsed.Name.LastName)
+} 
\ No newline at end of file
@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// OfferHandler handles volunteers' standing offers and their matching
+// against needs in both directions
+type OfferHandler struct {
+	offerService     *services.OfferService
+	matchingService  *services.MatchingService
+	websocketService *services.WebSocketService
+	mongoClient      *database.MongoClient
+	moderation       *services.ModerationService
+	privacy          *services.PrivacyService
+	analytics        *services.AnalyticsService
+	sla              *services.SLAService
+	embeddingQueue   *database.RedisClient
+}
+
+// NewOfferHandler creates a new offer handler. Needs taken on through offers
+// are recorded as accepted on slaService. When embeddingQueue is non-nil,
+// offers are embedded by the worker instead of in the request.
+func NewOfferHandler(offerService *services.OfferService, matchingService *services.MatchingService, websocketService *services.WebSocketService, mongoClient *database.MongoClient, moderationService *services.ModerationService, privacyService *services.PrivacyService, analyticsService *services.AnalyticsService, slaService *services.SLAService, embeddingQueue *database.RedisClient) *OfferHandler {
+	return &OfferHandler{
+		offerService:     offerService,
+		matchingService:  matchingService,
+		websocketService: websocketService,
+		mongoClient:      mongoClient,
+		moderation:       moderationService,
+		privacy:          privacyService,
+		analytics:        analyticsService,
+		sla:              slaService,
+		embeddingQueue:   embeddingQueue,
+	}
+}
+
+// embedOffer generates a new or changed offer's embedding, on the worker when
+// it runs separately. An offer without an embedding is still browsable, and
+// the backfill-embeddings command catches it up for matching.
+func (h *OfferHandler) embedOffer(ctx context.Context, offer *models.Offer) {
+	if h.embeddingQueue != nil {
+		if err := jobs.EnqueueEmbedding(ctx, h.embeddingQueue, jobs.EmbeddingKindOffer, offer.ID); err != nil {
+			log.Printf("Failed to queue embedding for offer %s: %v", offer.ID.Hex(), err)
+		}
+		return
+	}
+	if h.matchingService == nil {
+		return
+	}
+	if err := h.matchingService.UpdateOfferEmbedding(ctx, offer); err != nil {
+		log.Printf("Failed to embed offer %s: %v", offer.ID.Hex(), err)
+	}
+}
+
+// CreateOffer posts a standing offer and generates its embedding
+func (h *OfferHandler) CreateOffer(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOfferRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	offer, err := h.offerService.Create(c.Request.Context(), user, req, indexLocation(c, h.privacy, req.Location))
+	if err != nil {
+		h.respondError(c, err, "Failed to create offer")
+		return
+	}
+	h.moderation.Screen(c.Request.Context(), models.ContentOffer, offer.ID, offer.UserID, offer.Title+"\n\n"+offer.Description)
+
+	h.embedOffer(c.Request.Context(), offer)
+
+	c.JSON(http.StatusCreated, gin.H{"offer": offer})
+}
+
+// GetOffers browses active offers, optionally by ?category= and
+// ?neighborhood=, or lists the current user's own with ?mine=true
+func (h *OfferHandler) GetOffers(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	limit, offset := groupPage(c)
+
+	var offers []models.Offer
+	var err error
+	if c.Query("mine") == "true" {
+		offers, err = h.offerService.Mine(c.Request.Context(), userID, limit, offset)
+	} else {
+		category := models.Category(c.Query("category"))
+		if category != "" && !category.WellFormed() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+			return
+		}
+		offers, err = h.offerService.Browse(c.Request.Context(), userID, category, c.Query("neighborhood"), limit, offset)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve offers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offers": offers, "limit": limit, "offset": offset})
+}
+
+// GetOffer returns a single offer
+func (h *OfferHandler) GetOffer(c *gin.Context) {
+	userID, offerID, ok := h.offerRequest(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.offerService.Get(c.Request.Context(), offerID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve offer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offer": offer})
+}
+
+// UpdateOffer changes one of the current user's offers, re-screening and
+// re-embedding it when its text changes
+func (h *OfferHandler) UpdateOffer(c *gin.Context) {
+	userID, offerID, ok := h.offerRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOfferRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.Location != nil {
+		location := indexLocation(c, h.privacy, *req.Location)
+		req.Location = &location
+	}
+
+	offer, err := h.offerService.Update(c.Request.Context(), offerID, userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update offer")
+		return
+	}
+
+	if req.Title != nil || req.Description != nil || req.Category != "" || req.Schedule != nil {
+		h.moderation.Screen(c.Request.Context(), models.ContentOffer, offer.ID, offer.UserID, offer.Title+"\n\n"+offer.Description)
+		h.embedOffer(c.Request.Context(), offer)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offer": offer})
+}
+
+// CloseOffer withdraws one of the current user's offers
+func (h *OfferHandler) CloseOffer(c *gin.Context) {
+	userID, offerID, ok := h.offerRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.offerService.Close(c.Request.Context(), offerID, userID); err != nil {
+		h.respondError(c, err, "Failed to close offer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Offer closed"})
+}
+
+// GetOfferMatches lists open needs one of the current user's offers could
+// meet, for the offerer to accept through the usual need acceptance. Best
+// matches come first, paged with ?limit= and ?cursor=.
+func (h *OfferHandler) GetOfferMatches(c *gin.Context) {
+	userID, offerID, ok := h.offerRequest(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.offerService.Get(c.Request.Context(), offerID, userID)
+	if err == nil && offer.UserID != userID {
+		err = services.ErrOfferNotFound
+	}
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve offer")
+		return
+	}
+	p, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	if h.matchingService == nil {
+		c.JSON(http.StatusOK, gin.H{"matches": []models.OfferMatch{}, "next_cursor": nil})
+		return
+	}
+
+	matches, err := h.matchingService.FindNeedsForOffer(c.Request.Context(), offer, int(p.cursor.Offset+p.fetch()))
+	if err != nil {
+		h.respondError(c, err, "Failed to match offer")
+		return
+	}
+	matches, next := pageOffset(p, skipPages(p, matches))
+
+	// Attach the needs, shaped for the offerer like any other need listing
+	needIDs := make([]primitive.ObjectID, len(matches))
+	for i, match := range matches {
+		needIDs[i] = match.NeedID
+	}
+	cursor, err := h.mongoClient.GetCollection("needs").Find(c.Request.Context(), bson.M{"_id": bson.M{"$in": needIDs}})
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve matched needs")
+		return
+	}
+	var needs []models.Need
+	if err := cursor.All(c.Request.Context(), &needs); err != nil {
+		h.respondError(c, err, "Failed to retrieve matched needs")
+		return
+	}
+	if err := shapeNeeds(c.Request.Context(), h.privacy, userID, needs); err != nil {
+		h.respondError(c, err, "Failed to retrieve matched needs")
+		return
+	}
+	byID := make(map[primitive.ObjectID]*models.Need, len(needs))
+	for i := range needs {
+		byID[needs[i].ID] = &needs[i]
+	}
+	for i := range matches {
+		matches[i].Need = byID[matches[i].NeedID]
+	}
+	services.LocalizeOfferMatches(matches, preferredUnits(c), preferredLanguage(c))
+
+	c.JSON(http.StatusOK, gin.H{"matches": matches, "next_cursor": next})
+}
+
+// GetNeedOffers lists standing offers that could meet one of the current
+// user's needs
+func (h *OfferHandler) GetNeedOffers(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+
+	var need models.Need
+	err = h.mongoClient.GetCollection("needs").FindOne(c.Request.Context(), bson.M{"_id": needID, "user_id": userID}).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+		return
+	}
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve need")
+		return
+	}
+	if h.matchingService == nil || need.HeldForReview || need.Hidden {
+		c.JSON(http.StatusOK, gin.H{"matches": []models.OfferMatch{}})
+		return
+	}
+
+	limit, _ := groupPage(c)
+	matches, err := h.matchingService.FindOffersForNeed(c.Request.Context(), &need, int(limit))
+	if err != nil {
+		h.respondError(c, err, "Failed to match need")
+		return
+	}
+	for i := range matches {
+		offer, err := h.offerService.Get(c.Request.Context(), matches[i].OfferID, userID)
+		if err != nil && !errors.Is(err, services.ErrOfferNotFound) {
+			h.respondError(c, err, "Failed to retrieve matched offers")
+			return
+		}
+		matches[i].Offer = offer
+	}
+	services.LocalizeOfferMatches(matches, preferredUnits(c), preferredLanguage(c))
+
+	c.JSON(http.StatusOK, gin.H{"matches": matches})
+}
+
+// AcceptOffer takes up an offer for one of the current user's open needs,
+// creating a task with the offerer as volunteer
+func (h *OfferHandler) AcceptOffer(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	offerID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer ID"})
+		return
+	}
+
+	var req models.AcceptOfferRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(req.NeedID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+
+	offer, need, task, err := h.offerService.Accept(c.Request.Context(), offerID, user, needID)
+	if err != nil {
+		h.respondError(c, err, "Failed to accept offer")
+		return
+	}
+	h.analytics.MatchAccepted(c.Request.Context(), need, task)
+	h.sla.Record(c.Request.Context(), need.ID, models.MilestoneAccepted, task.CreatedAt)
+
+	if h.websocketService != nil {
+		h.websocketService.NotifyOfferAccepted(*offer, *task, user.DisplayName())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Offer accepted successfully",
+		"task":    task,
+	})
+}
+
+// offerRequest reads the current user's ID and the :id offer parameter,
+// writing an error response on failure
+func (h *OfferHandler) offerRequest(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	offerID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return userID, offerID, true
+}
+
+// respondError maps offer service errors to responses, falling back to a
+// 500 with message
+func (h *OfferHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrOfferNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+	case errors.Is(err, services.ErrNeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+	case errors.Is(err, services.ErrNotNeedOwner), errors.Is(err, services.ErrOffererIneligible):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOwnOffer):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOfferNotActive), errors.Is(err, services.ErrNeedClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page sizes for cursor-paged listings
+const (
+	pageDefaultLimit = 20
+	pageMaxLimit     = 100
+)
+
+// pageCursor is where the previous page ended. Newest-first listings resume
+// after the ID of the last item; listings in any other order, such as
+// matches by score, have no key to resume after, so they page by position.
+type pageCursor struct {
+	After  primitive.ObjectID `json:"after,omitempty"`
+	Offset int64              `json:"offset,omitempty"`
+}
+
+// page is a request for one page of a listing, read from ?limit= and the
+// opaque ?cursor= returned as next_cursor with the previous page
+type page struct {
+	limit  int64
+	cursor pageCursor
+}
+
+// parsePage reads ?limit= and ?cursor= for a listing paged by ID or, with
+// byPosition, by position, writing an error response for a cursor that is
+// malformed or from the other kind of listing
+func parsePage(c *gin.Context, byPosition bool) (page, bool) {
+	p := page{limit: pageDefaultLimit}
+	if parsed, err := strconv.ParseInt(c.Query("limit"), 10, 64); err == nil && parsed > 0 {
+		p.limit = parsed
+	}
+	if p.limit > pageMaxLimit {
+		p.limit = pageMaxLimit
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		data, err := base64.RawURLEncoding.DecodeString(raw)
+		if err == nil {
+			err = json.Unmarshal(data, &p.cursor)
+		}
+		if err != nil || p.cursor.Offset < 0 || (byPosition && !p.cursor.After.IsZero()) || (!byPosition && p.cursor.Offset > 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return page{}, false
+		}
+	}
+	return p, true
+}
+
+// sort orders a newest-first listing by ID, which follows creation time and
+// never ties
+func (p page) sort() bson.D {
+	return bson.D{{Key: "_id", Value: -1}}
+}
+
+// apply limits filter to the items after the cursor in a newest-first
+// listing
+func (p page) apply(filter bson.M) {
+	if !p.cursor.After.IsZero() {
+		filter["_id"] = bson.M{"$lt": p.cursor.After}
+	}
+}
+
+// fetch is how many items to load: one more than the page holds, to tell
+// whether another page follows
+func (p page) fetch() int64 {
+	return p.limit + 1
+}
+
+// pageAfter trims a newest-first page loaded with fetch to its size and
+// returns the cursor for the next page, or nil on the last page
+func pageAfter[T any](p page, items []T, id func(T) primitive.ObjectID) ([]T, *string) {
+	if int64(len(items)) <= p.limit {
+		return items, nil
+	}
+	items = items[:p.limit]
+	return items, encodeCursor(pageCursor{After: id(items[len(items)-1])})
+}
+
+// pageOffset trims a page paged by position loaded with fetch to its size and returns
+// the cursor for the next page, or nil on the last page
+func pageOffset[T any](p page, items []T) ([]T, *string) {
+	if int64(len(items)) <= p.limit {
+		return items, nil
+	}
+	return items[:p.limit], encodeCursor(pageCursor{Offset: p.cursor.Offset + p.limit})
+}
+
+// skipPages drops the items of earlier pages from a ranking loaded from the
+// top, for rankings that can't start partway through
+func skipPages[T any](p page, items []T) []T {
+	if int64(len(items)) <= p.cursor.Offset {
+		return nil
+	}
+	return items[p.cursor.Offset:]
+}
+
+// encodeCursor serializes a cursor for clients to pass back verbatim
+func encodeCursor(cursor pageCursor) *string {
+	data, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return &encoded
+}
+
+// docID returns the ID of a document shaped by a fieldset, whose "_id" has
+// been renamed to "id"
+func docID(doc bson.M) primitive.ObjectID {
+	id, _ := doc["id"].(primitive.ObjectID)
+	return id
+} 
\ No newline at end of file
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// PartnerHandler handles the partner intake API, through which outside case
+// managers refer clients without accounts, and the admin management of
+// partner organizations
+type PartnerHandler struct {
+	partnerService *services.PartnerService
+	needs          *NeedHandler
+	moderation     *services.ModerationService
+	auditService   *services.AuditService
+}
+
+// NewPartnerHandler creates a new partner handler. Referred needs are
+// published through needHandler, so they are held and matched like any other.
+func NewPartnerHandler(partnerService *services.PartnerService, needHandler *NeedHandler, moderationService *services.ModerationService, auditService *services.AuditService) *PartnerHandler {
+	return &PartnerHandler{
+		partnerService: partnerService,
+		needs:          needHandler,
+		moderation:     moderationService,
+		auditService:   auditService,
+	}
+}
+
+// CreateReferral creates a need for the calling organization's client.
+// Resending an external ID returns the existing referral with a 200.
+func (h *PartnerHandler) CreateReferral(c *gin.Context) {
+	org := middleware.GetPartner(c)
+
+	var req models.CreateReferralRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Referrals are screened like needs posted in the app
+	text := req.Title + "\n\n" + req.Description
+	reasons := h.moderation.Classify(c.Request.Context(), text)
+
+	need, created, err := h.partnerService.Refer(c.Request.Context(), org, req, len(reasons) > 0)
+	if err != nil {
+		h.respondError(c, err, "Failed to create referral")
+		return
+	}
+	if created {
+		if _, err := h.needs.publishNeed(c.Request.Context(), need, text, reasons); err != nil {
+			log.Printf("Failed to match referred need %s: %v", need.ID.Hex(), err)
+		}
+	}
+
+	state, err := h.partnerService.State(c.Request.Context(), need)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve referral")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"referral": state})
+}
+
+// GetReferral returns the state of one of the calling organization's
+// referrals, by its external ID
+func (h *PartnerHandler) GetReferral(c *gin.Context) {
+	org := middleware.GetPartner(c)
+
+	state, err := h.partnerService.Referral(c.Request.Context(), org.ID, c.Param("externalId"))
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve referral")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"referral": state})
+}
+
+// CancelReferral withdraws one of the calling organization's open
+// referrals, by its external ID
+func (h *PartnerHandler) CancelReferral(c *gin.Context) {
+	org := middleware.GetPartner(c)
+
+	state, err := h.partnerService.CancelReferral(c.Request.Context(), org.ID, c.Param("externalId"))
+	if err != nil {
+		h.respondError(c, err, "Failed to cancel referral")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"referral": state})
+}
+
+// ListPartners lists partner organizations, including revoked ones with
+// ?include_revoked=true
+func (h *PartnerHandler) ListPartners(c *gin.Context) {
+	limit, offset := groupPage(c)
+
+	orgs, err := h.partnerService.ListOrganizations(c.Request.Context(), c.Query("include_revoked") == "true", limit, offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve partners")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"partners": orgs, "limit": limit, "offset": offset})
+}
+
+// CreatePartner registers a partner organization. Its API key and webhook
+// secret are in the response and cannot be retrieved later.
+func (h *PartnerHandler) CreatePartner(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePartnerRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	creds, err := h.partnerService.CreateOrganization(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create partner")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditPartnerCreated, models.AuditTargetPartner, &creds.Organization.ID, map[string]interface{}{"name": creds.Organization.Name})
+
+	c.JSON(http.StatusCreated, creds)
+}
+
+// UpdatePartner changes a partner organization's details
+func (h *PartnerHandler) UpdatePartner(c *gin.Context) {
+	orgID, ok := partnerID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePartnerRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.partnerService.UpdateOrganization(c.Request.Context(), orgID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update partner")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditPartnerUpdated, models.AuditTargetPartner, &org.ID, nil)
+
+	c.JSON(http.StatusOK, gin.H{"partner": org})
+}
+
+// RotatePartnerKey issues a partner organization a new API key and webhook
+// secret, which replace the old ones at once
+func (h *PartnerHandler) RotatePartnerKey(c *gin.Context) {
+	orgID, ok := partnerID(c)
+	if !ok {
+		return
+	}
+
+	creds, err := h.partnerService.RotateCredentials(c.Request.Context(), orgID)
+	if err != nil {
+		h.respondError(c, err, "Failed to rotate partner key")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditPartnerKeyRotated, models.AuditTargetPartner, &orgID, map[string]interface{}{"key_prefix": creds.Organization.KeyPrefix})
+
+	c.JSON(http.StatusOK, creds)
+}
+
+// RevokePartner turns off a partner organization's access and status events
+func (h *PartnerHandler) RevokePartner(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	orgID, ok := partnerID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.partnerService.RevokeOrganization(c.Request.Context(), orgID, userID); err != nil {
+		h.respondError(c, err, "Failed to revoke partner")
+		return
+	}
+	recordAudit(c, h.auditService, models.AuditPartnerRevoked, models.AuditTargetPartner, &orgID, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Partner revoked"})
+}
+
+// partnerID reads the :id partner organization parameter, writing an error
+// response on failure
+func partnerID(c *gin.Context) (primitive.ObjectID, bool) {
+	orgID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partner ID"})
+		return primitive.NilObjectID, false
+	}
+	return orgID, true
+}
+
+// respondError maps partner service errors to responses, falling back to a
+// 500 with message
+func (h *PartnerHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrPartnerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Partner not found"})
+	case errors.Is(err, services.ErrReferralNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Referral not found"})
+	case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReferralClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/services"
+)
+
+// PointsHandler handles volunteer points, streaks, and leaderboards
+type PointsHandler struct {
+	pointsService *services.PointsService
+}
+
+// NewPointsHandler creates a new points handler
+func NewPointsHandler(pointsService *services.PointsService) *PointsHandler {
+	return &PointsHandler{pointsService: pointsService}
+}
+
+// GetMyPoints returns the current user's points, weekly streak, and latest
+// points ledger
+func (h *PointsHandler) GetMyPoints(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.pointsService.Summary(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve points")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetLeaderboard ranks volunteers by points earned this week, this month,
+// or overall, as chosen by the period query parameter
+func (h *PointsHandler) GetLeaderboard(c *gin.Context) {
+	period := c.DefaultQuery("period", "week")
+	limit, _ := groupPage(c)
+
+	entries, err := h.pointsService.Leaderboard(c.Request.Context(), period, limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"period": period, "leaderboard": entries})
+}
+
+// respondError maps points service errors to responses, falling back to a
+// 500 with message
+func (h *PointsHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// PostHandler handles neighborhood announcement feeds
+type PostHandler struct {
+	postService  *services.PostService
+	moderation   *services.ModerationService
+	auditService *services.AuditService
+}
+
+// NewPostHandler creates a new post handler
+func NewPostHandler(postService *services.PostService, moderationService *services.ModerationService, auditService *services.AuditService) *PostHandler {
+	return &PostHandler{
+		postService:  postService,
+		moderation:   moderationService,
+		auditService: auditService,
+	}
+}
+
+// CreatePost posts an organizer's update to a neighborhood feed. Posts the
+// abuse classifier flags are held for review.
+func (h *PostHandler) CreatePost(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePostRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	text := req.Title + "\n\n" + req.Body
+	reasons := h.moderation.Classify(c.Request.Context(), text)
+
+	post, err := h.postService.Create(c.Request.Context(), user, req, len(reasons) > 0)
+	if err != nil {
+		h.respondError(c, err, "Failed to create post")
+		return
+	}
+	h.moderation.Queue(c.Request.Context(), models.ContentPost, post.ID, post.AuthorID, text, reasons)
+
+	c.JSON(http.StatusCreated, gin.H{"post": post})
+}
+
+// GetFeed lists posts in ?neighborhood=, or the current user's own
+// neighborhood, newest first
+func (h *PostHandler) GetFeed(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	neighborhood, err := h.postService.Neighborhood(user, c.Query("neighborhood"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, offset := groupPage(c)
+
+	posts, err := h.postService.Feed(c.Request.Context(), neighborhood, user.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve posts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "neighborhood": neighborhood, "limit": limit, "offset": offset})
+}
+
+// GetPost returns a single post
+func (h *PostHandler) GetPost(c *gin.Context) {
+	userID, postID, ok := h.postRequest(c)
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.Get(c.Request.Context(), postID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve post")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"post": post})
+}
+
+// DeletePost deletes a post; admins deleting others' posts are audited
+func (h *PostHandler) DeletePost(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	postID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	if err := h.postService.Delete(c.Request.Context(), postID, user); err != nil {
+		h.respondError(c, err, "Failed to delete post")
+		return
+	}
+
+	if user.HasRole(models.RoleAdmin) {
+		recordAudit(c, h.auditService, models.AuditPostDeleted, models.AuditTargetPost, &postID, nil)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Post deleted"})
+}
+
+// React sets the current user's reaction to a post
+func (h *PostHandler) React(c *gin.Context) {
+	userID, postID, ok := h.postRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReactRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	post, err := h.postService.React(c.Request.Context(), postID, userID, req.Reaction)
+	if err != nil {
+		h.respondError(c, err, "Failed to react to post")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"post": post})
+}
+
+// Unreact removes the current user's reaction to a post
+func (h *PostHandler) Unreact(c *gin.Context) {
+	userID, postID, ok := h.postRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.postService.Unreact(c.Request.Context(), postID, userID); err != nil {
+		h.respondError(c, err, "Failed to remove reaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reaction removed"})
+}
+
+// postRequest reads the current user's ID and the :id post parameter,
+// writing an error response on failure
+func (h *PostHandler) postRequest(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	postID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return userID, postID, true
+}
+
+// respondError maps post service errors to responses, falling back to a
+// 500 with message
+func (h *PostHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrPostNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	case errors.Is(err, services.ErrNotOrganizer), errors.Is(err, services.ErrNotPostAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidNeighborhood):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// PrivacyHandler handles the current user's privacy settings
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+	auditService   *services.AuditService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(privacyService *services.PrivacyService, auditService *services.AuditService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+		auditService:   auditService,
+	}
+}
+
+// GetPrivacySettings returns the current user's privacy settings
+func (h *PrivacyHandler) GetPrivacySettings(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"privacy": user.Privacy})
+}
+
+// UpdatePrivacySettings changes the current user's privacy settings
+func (h *PrivacyHandler) UpdatePrivacySettings(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePrivacyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req == (models.UpdatePrivacyRequest{}) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	privacy, err := h.privacyService.UpdateSettings(c.Request.Context(), user.ID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update privacy settings"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditPrivacyUpdated, models.AuditTargetUser, &user.ID, map[string]interface{}{
+		"before": user.Privacy,
+		"after":  privacy,
+	})
+	c.JSON(http.StatusOK, gin.H{"privacy": privacy})
+}
+
+// GetTaskContact discloses the phone number and email of the current user's
+// partner on a task, once both are bound to it. Every disclosure is audited.
+func (h *PrivacyHandler) GetTaskContact(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	contact, err := h.privacyService.TaskContact(c.Request.Context(), user.ID, taskID)
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	case errors.Is(err, services.ErrContactNotShared):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve contact details"})
+		return
+	}
+
+	disclosed := []string{}
+	if contact.Phone != "" {
+		disclosed = append(disclosed, "phone")
+	}
+	if contact.Email != "" {
+		disclosed = append(disclosed, "email")
+	}
+	target := models.AuditTargetUser
+	if contact.Organization != "" {
+		target = models.AuditTargetPartner
+	}
+	recordAudit(c, h.auditService, models.AuditContactDisclosed, target, &contact.UserID, map[string]interface{}{
+		"task_id": taskID.Hex(),
+		"fields":  disclosed,
+	})
+	c.JSON(http.StatusOK, gin.H{"contact": contact})
+} 
\ No newline at end of file
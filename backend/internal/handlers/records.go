@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// recordFlushEvery is how many rows are written between flushes, so
+// downloads start promptly without a flush per row
+const recordFlushEvery = 200
+
+// activityDefaultDays and activityMaxDays bound the aggregate export window
+const (
+	activityDefaultDays = 90
+	activityMaxDays     = 366
+)
+
+// RecordHandler streams CSV and NDJSON exports of users' records and of
+// anonymized community activity
+type RecordHandler struct {
+	recordService *services.RecordService
+}
+
+// NewRecordHandler creates a new record handler
+func NewRecordHandler(recordService *services.RecordService) *RecordHandler {
+	return &RecordHandler{recordService: recordService}
+}
+
+// ExportNeeds streams the current user's needs. ?format= is csv (default)
+// or ndjson.
+func (h *RecordHandler) ExportNeeds(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	stream := startRecordStream(c, "needs", []string{"id", "created_at", "title", "description", "category", "urgency", "duration", "status", "expires_at"})
+	if stream == nil {
+		return
+	}
+	stream.finish(h.recordService.Needs(c.Request.Context(), userID, func(need models.Need) error {
+		return stream.write(need, []string{
+			need.ID.Hex(), formatTime(&need.CreatedAt), need.Title, need.Description, string(need.Category),
+			string(need.Urgency), strconv.Itoa(need.Duration), string(need.Status), formatTime(need.ExpiresAt),
+		})
+	}))
+}
+
+// ExportTasks streams the tasks the current user volunteered for or asked
+// for. ?format= is csv (default) or ndjson.
+func (h *RecordHandler) ExportTasks(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	stream := startRecordStream(c, "tasks", []string{"id", "need_id", "volunteer_id", "status", "scheduled_at", "completed_at", "notes", "created_at"})
+	if stream == nil {
+		return
+	}
+	stream.finish(h.recordService.Tasks(c.Request.Context(), userID, func(task models.Task) error {
+		return stream.write(task, []string{
+			task.ID.Hex(), task.NeedID.Hex(), task.VolunteerID.Hex(), string(task.Status),
+			formatTime(task.ScheduledAt), formatTime(task.CompletedAt), task.Notes, formatTime(&task.CreatedAt),
+		})
+	}))
+}
+
+// ExportHours streams the tasks the current user completed as a volunteer
+// with the minutes each took. ?format= is csv (default) or ndjson.
+func (h *RecordHandler) ExportHours(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	stream := startRecordStream(c, "volunteer-hours", []string{"task_id", "need_id", "category", "minutes", "completed_at"})
+	if stream == nil {
+		return
+	}
+	stream.finish(h.recordService.Hours(c.Request.Context(), userID, func(hours models.VolunteerHours) error {
+		return stream.write(hours, []string{
+			hours.TaskID.Hex(), hours.NeedID.Hex(), string(hours.Category), strconv.Itoa(hours.Minutes), formatTime(hours.CompletedAt),
+		})
+	}))
+}
+
+// ExportActivity streams anonymized daily activity per category for admins.
+// ?from= and ?to= are RFC 3339 timestamps spanning at most a year, by
+// default the last 90 days. ?format= is csv (default) or ndjson.
+func (h *RecordHandler) ExportActivity(c *gin.Context) {
+	from, to, ok := timeRange(c, activityDefaultDays)
+	if !ok {
+		return
+	}
+	if to.Sub(from) > activityMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Activity exports span at most 366 days"})
+		return
+	}
+
+	stream := startRecordStream(c, "activity", []string{"day", "category", "needs_created", "tasks_completed", "volunteer_minutes"})
+	if stream == nil {
+		return
+	}
+	stream.finish(h.recordService.Activity(c.Request.Context(), from, to, func(activity models.ActivityAggregate) error {
+		return stream.write(activity, []string{
+			activity.Day, string(activity.Category), strconv.Itoa(activity.NeedsCreated),
+			strconv.Itoa(activity.TasksCompleted), strconv.Itoa(activity.VolunteerMinutes),
+		})
+	}))
+}
+
+// timeRange reads the RFC 3339 ?from= and ?to= parameters, by default the
+// last defaultDays days, writing a 400 response when they are invalid
+func timeRange(c *gin.Context, defaultDays int) (time.Time, time.Time, bool) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -defaultDays)
+	for param, dest := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC 3339 timestamp"})
+			return time.Time{}, time.Time{}, false
+		}
+		*dest = t
+	}
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// recordStream writes records to a CSV or NDJSON download as they are read
+type recordStream struct {
+	c       *gin.Context
+	csv     *csv.Writer
+	encoder *json.Encoder
+	rows    int
+}
+
+// startRecordStream validates ?format= and sends the download headers and,
+// for CSV, the header row. It writes a 400 response and returns nil when
+// the format is unknown.
+func startRecordStream(c *gin.Context, name string, header []string) *recordStream {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or ndjson"})
+		return nil
+	}
+
+	filename := name + "-" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	stream := &recordStream{c: c}
+	if format == "ndjson" {
+		c.Header("Content-Type", "application/x-ndjson")
+		stream.encoder = json.NewEncoder(c.Writer)
+		return stream
+	}
+	c.Header("Content-Type", "text/csv")
+	stream.csv = csv.NewWriter(c.Writer)
+	if err := stream.csv.Write(header); err != nil {
+		c.Error(err)
+	}
+	return stream
+}
+
+// write writes one record, as row for CSV or as JSON otherwise
+func (s *recordStream) write(record interface{}, row []string) error {
+	var err error
+	if s.encoder != nil {
+		err = s.encoder.Encode(record)
+	} else {
+		err = s.csv.Write(row)
+	}
+	if err != nil {
+		return err
+	}
+	s.rows++
+	if s.rows%recordFlushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush sends what has been written so far to the client
+func (s *recordStream) flush() error {
+	if s.csv != nil {
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// finish flushes the download. Headers are already sent, so a truncated
+// download is the only signal of an error, which is recorded on the context.
+func (s *recordStream) finish(err error) {
+	if err == nil {
+		err = s.flush()
+	}
+	if err != nil {
+		s.c.Error(err)
+	}
+}
+
+// formatTime formats an optional timestamp for CSV
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+} 
\ No newline at end of file
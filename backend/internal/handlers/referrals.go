@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// referralDefaultDays is the window of the admin referral report when ?days=
+// is not given
+const referralDefaultDays = 30
+
+// ReferralHandler handles invites and referral counts
+type ReferralHandler struct {
+	referralService *services.ReferralService
+}
+
+// NewReferralHandler creates a new referral handler
+func NewReferralHandler(referralService *services.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// CreateInvite issues a new invite code and link for the current user
+func (h *ReferralHandler) CreateInvite(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateInviteRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	invite, err := h.referralService.CreateInvite(c.Request.Context(), user, req)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyInvites) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invite": invite})
+}
+
+// GetMyInvites lists the current user's invites, newest first
+func (h *ReferralHandler) GetMyInvites(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	limit, offset := groupPage(c)
+
+	invites, err := h.referralService.ListInvites(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invites": invites, "limit": limit, "offset": offset})
+}
+
+// RevokeInvite stops one of the current user's invites from being used
+func (h *ReferralHandler) RevokeInvite(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	inviteID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite ID"})
+		return
+	}
+
+	if err := h.referralService.RevokeInvite(c.Request.Context(), inviteID, userID); err != nil {
+		if errors.Is(err, services.ErrInviteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invite revoked"})
+}
+
+// GetMyReferrals returns how many neighbors the current user has brought in
+// and the referrer badges they have earned
+func (h *ReferralHandler) GetMyReferrals(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.referralService.Stats(c.Request.Context(), user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve referrals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"referrals": stats})
+}
+
+// GetNeighborhoodReferrals reports referred sign-ups per neighborhood over
+// the last ?days= days
+func (h *ReferralHandler) GetNeighborhoodReferrals(c *gin.Context) {
+	days := referralDefaultDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+		days = parsed
+	}
+	limit, _ := groupPage(c)
+
+	since := time.Now().AddDate(0, 0, -days)
+	neighborhoods, err := h.referralService.NeighborhoodReferrals(c.Request.Context(), since, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve referrals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"neighborhoods": neighborhoods, "days": days})
+} 
\ No newline at end of file
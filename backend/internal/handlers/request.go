@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+)
+
+// bindJSON binds and validates a JSON request body, then sanitizes any
+// user-supplied text. It writes an error response and returns false on failure.
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return false
+		}
+		if details := validationDetails(err, middleware.GetLocale(c)); details != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": details})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return false
+	}
+
+	if sanitizer, ok := req.(models.Sanitizer); ok {
+		if err := sanitizer.Sanitize(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return false
+		}
+	}
+
+	return true
+}
+
+// preferredLanguage returns the current user's preferred language, falling
+// back to the one negotiated for the request
+func preferredLanguage(c *gin.Context) models.Language {
+	if user, ok := middleware.GetUser(c).(*models.User); ok && user.Language != "" {
+		return user.Language
+	}
+	return models.Language(middleware.GetLocale(c))
+}
+
+// preferredUnits returns the measurement system the current user reads
+// distances in
+func preferredUnits(c *gin.Context) models.Units {
+	if user, ok := middleware.GetUser(c).(*models.User); ok {
+		return user.Units
+	}
+	return ""
+} 
\ No newline at end of file
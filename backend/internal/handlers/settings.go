@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/settings"
+)
+
+// SettingsHandler lets admins view and change runtime tunables
+type SettingsHandler struct {
+	store        *settings.Store
+	categories   *services.CategoryService
+	auditService *services.AuditService
+}
+
+// NewSettingsHandler creates a new settings handler. Categories given
+// overrides are checked against categoryService's taxonomy.
+func NewSettingsHandler(store *settings.Store, categoryService *services.CategoryService, auditService *services.AuditService) *SettingsHandler {
+	return &SettingsHandler{
+		store:        store,
+		categories:   categoryService,
+		auditService: auditService,
+	}
+}
+
+// GetSettings returns the current tunables and their defaults
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"settings": h.store.Get(c.Request.Context()),
+		"defaults": h.store.Defaults(),
+	})
+}
+
+// UpdateSettings changes the given tunables, leaving the rest unchanged
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	var patch settings.Patch
+	if !bindJSON(c, &patch) {
+		return
+	}
+	// Overrides of deleted categories can still be removed
+	for category, override := range patch.Categories {
+		if override == nil {
+			continue
+		}
+		if err := h.categories.CheckNeed(c.Request.Context(), category, ""); err != nil {
+			if errors.Is(err, services.ErrUnknownCategory) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update settings", "details": fmt.Sprintf("categories: %q is not a category", category)})
+				return
+			}
+			log.Printf("Failed to check settings categories: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update settings"})
+			return
+		}
+	}
+
+	updated, err := h.store.Update(c.Request.Context(), patch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update settings", "details": err.Error()})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditSettingsUpdated, models.AuditTargetSettings, nil, map[string]interface{}{"settings": updated})
+	c.JSON(http.StatusOK, gin.H{"settings": updated})
+}
+
+// ResetSettings restores every tunable to its default
+func (h *SettingsHandler) ResetSettings(c *gin.Context) {
+	defaults, err := h.store.Reset(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset settings"})
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditSettingsReset, models.AuditTargetSettings, nil, nil)
+	c.JSON(http.StatusOK, gin.H{"settings": defaults})
+} 
\ No newline at end of file
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/services"
+)
+
+// slaDefaultDays and slaMaxDays bound the SLA metrics window
+const (
+	slaDefaultDays = 30
+	slaMaxDays     = 366
+)
+
+// SLAHandler reports how quickly needs are matched, accepted, and completed
+type SLAHandler struct {
+	slaService *services.SLAService
+}
+
+// NewSLAHandler creates a new SLA handler
+func NewSLAHandler(slaService *services.SLAService) *SLAHandler {
+	return &SLAHandler{slaService: slaService}
+}
+
+// GetMetrics returns the time from posting to each milestone, and the
+// breaches and escalations of urgent needs, for needs created in the window.
+// ?from= and ?to= are RFC 3339 timestamps spanning at most a year, by
+// default the last 30 days.
+func (h *SLAHandler) GetMetrics(c *gin.Context) {
+	from, to, ok := timeRange(c, slaDefaultDays)
+	if !ok {
+		return
+	}
+	if to.Sub(from) > slaMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SLA metrics span at most 366 days"})
+		return
+	}
+
+	metrics, err := h.slaService.Metrics(c.Request.Context(), from, to)
+	if err != nil {
+		log.Printf("Failed to compute SLA metrics: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute SLA metrics"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"metrics": metrics})
+} 
\ No newline at end of file
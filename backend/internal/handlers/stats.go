@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// statsDefaultDays and statsMaxDays bound the dashboard statistics window
+const (
+	statsDefaultDays = 30
+	statsMaxDays     = 366
+)
+
+// StatsHandler serves the aggregate statistics of the admin dashboard
+type StatsHandler struct {
+	statsService *services.StatsService
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// GetOverview returns the open needs now, and the active volunteers,
+// completed tasks, and match scores in the window. ?from= and ?to= are RFC
+// 3339 timestamps spanning at most a year, by default the last 30 days.
+func (h *StatsHandler) GetOverview(c *gin.Context) {
+	from, to, ok := statsRange(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.statsService.Overview(c.Request.Context(), from, to)
+	if err != nil {
+		log.Printf("Failed to compute platform stats: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute stats"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+// GetTimeToMatch returns how long needs created in the window waited for
+// their first match, bucketed by minutes. ?urgency= narrows it to one
+// urgency.
+func (h *StatsHandler) GetTimeToMatch(c *gin.Context) {
+	from, to, ok := statsRange(c)
+	if !ok {
+		return
+	}
+	urgency := models.Urgency(c.Query("urgency"))
+	if urgency != "" && !urgency.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid urgency"})
+		return
+	}
+
+	stats, err := h.statsService.TimeToMatch(c.Request.Context(), from, to, urgency)
+	if err != nil {
+		log.Printf("Failed to compute time to match: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute stats"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"time_to_match": stats})
+}
+
+// statsRange parses the statistics window, responding with an error when
+// it is invalid or too long
+func statsRange(c *gin.Context) (time.Time, time.Time, bool) {
+	from, to, ok := timeRange(c, statsDefaultDays)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Sub(from) > statsMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stats span at most 366 days"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+} 
\ No newline at end of file
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// UploadHandler handles profile photo and need image uploads. Files go
+// straight from the client to object storage through presigned URLs, and
+// are attached once uploaded.
+type UploadHandler struct {
+	uploadService *services.UploadService
+}
+
+// NewUploadHandler creates a new upload handler
+func NewUploadHandler(uploadService *services.UploadService) *UploadHandler {
+	return &UploadHandler{uploadService: uploadService}
+}
+
+// CreateUpload returns a presigned URL the current user can upload an image
+// to, for attaching afterwards
+func (h *UploadHandler) CreateUpload(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	var req models.CreateUploadRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	upload, err := h.uploadService.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to create upload")
+		return
+	}
+	c.JSON(http.StatusCreated, upload)
+}
+
+// GetUpload redirects to a short-lived URL for an uploaded file
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	uploadID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	url, err := h.uploadService.URL(c.Request.Context(), userID, uploadID)
+	if err != nil {
+		h.respondError(c, err, "Failed to load upload")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Redirect(http.StatusFound, url)
+}
+
+// SetPhoto makes an uploaded image the current user's profile photo
+func (h *UploadHandler) SetPhoto(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	var req models.SetPhotoRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	uploadID, err := primitive.ObjectIDFromHex(req.UploadID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	photo, err := h.uploadService.SetPhoto(c.Request.Context(), userID, uploadID)
+	if err != nil {
+		h.respondError(c, err, "Failed to set profile photo")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"photo": photo})
+}
+
+// RemovePhoto removes the current user's profile photo
+func (h *UploadHandler) RemovePhoto(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	if err := h.uploadService.RemovePhoto(c.Request.Context(), userID); err != nil {
+		h.respondError(c, err, "Failed to remove profile photo")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetNeedImages replaces the images on one of the current user's needs
+func (h *UploadHandler) SetNeedImages(c *gin.Context) {
+	userID, ok := requireUserObjectID(c)
+	if !ok {
+		return
+	}
+	needID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid need ID"})
+		return
+	}
+	var req models.SetNeedImagesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	uploadIDs := make([]primitive.ObjectID, 0, len(req.UploadIDs))
+	for _, id := range req.UploadIDs {
+		uploadID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+			return
+		}
+		uploadIDs = append(uploadIDs, uploadID)
+	}
+
+	images, err := h.uploadService.SetNeedImages(c.Request.Context(), userID, needID, uploadIDs)
+	if err != nil {
+		h.respondError(c, err, "Failed to set need images")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
+
+// respondError maps upload service errors to responses, falling back to a
+// 500 with message
+func (h *UploadHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrUploadsDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUploadNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+	case errors.Is(err, services.ErrNeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Need not found"})
+	case errors.Is(err, services.ErrNotNeedOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNeedClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "max_bytes": h.uploadService.MaxBytes()})
+	case errors.Is(err, services.ErrUnsupportedImageType), errors.Is(err, services.ErrWrongUploadPurpose),
+		errors.Is(err, services.ErrTooManyImages):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUploadIncomplete), errors.Is(err, services.ErrUploadInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUploadMismatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+)
+
+// RegisterValidators adds the custom binding validators used by request
+// payloads and reports field names by their JSON keys
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	if err := v.RegisterValidation("enum", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(models.Enum)
+		return ok && value.Valid()
+	}); err != nil {
+		return err
+	}
+
+	// Categories are admin-managed, so binding only checks their form and
+	// handlers check them against the taxonomy
+	return v.RegisterValidation("category", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(models.Category)
+		return ok && value.WellFormed()
+	})
+}
+
+// validationDetails converts binding validation errors into a map of
+// field path to message in lang, or returns nil for other errors
+func validationDetails(err error, lang string) map[string]string {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	details := make(map[string]string, len(validationErrs))
+	for _, fe := range validationErrs {
+		details[fieldPath(fe)] = validationMessage(fe, lang)
+	}
+	return details
+}
+
+// fieldPath strips the top-level struct name from a namespace like "CreateNeedRequest.location.address"
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+func validationMessage(fe validator.FieldError, lang string) string {
+	switch fe.Tag() {
+	case "required":
+		return i18n.T(lang, "is required")
+	case "email":
+		return i18n.T(lang, "must be a valid email address")
+	case "enum":
+		if value, ok := fe.Value().(models.Enum); ok {
+			return i18n.Tf(lang, "must be one of: %s", strings.Join(value.Values(), ", "))
+		}
+		return i18n.T(lang, "is not a recognized value")
+	case "category":
+		return i18n.T(lang, "is not a category; see GET /categories")
+	case "oneof":
+		return i18n.Tf(lang, "must be one of: %s", strings.Join(strings.Fields(fe.Param()), ", "))
+	case "min":
+		if fe.Kind() == reflect.String {
+			return i18n.Tf(lang, "must be at least %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice {
+			return i18n.Tf(lang, "must contain at least %s items", fe.Param())
+		}
+		return i18n.Tf(lang, "must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return i18n.Tf(lang, "must be at most %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice {
+			return i18n.Tf(lang, "must contain at most %s items", fe.Param())
+		}
+		return i18n.Tf(lang, "must be at most %s", fe.Param())
+	case "gtfield":
+		return i18n.Tf(lang, "must be after %s", strings.ToLower(fe.Param()))
+	case "datetime":
+		return i18n.Tf(lang, "must match the layout %s", fe.Param())
+	}
+	return i18n.Tf(lang, "failed %s validation", fe.Tag())
+} 
\ No newline at end of file
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"time"
 
@@ -9,8 +11,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"neighborenexus/internal/database"
+	"neighborenexus/internal/jobs"
 	"neighborenexus/internal/middleware"
 	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
 	"neighborenexus/internal/services"
 )
 
@@ -19,17 +23,67 @@ type VolunteerHandler struct {
 	matchingService  *services.MatchingService
 	websocketService *services.WebSocketService
 	mongoClient      *database.MongoClient
+	moderation       *services.ModerationService
+	privacy          *services.PrivacyService
+	translations     *services.TranslationService
+	waitlist         *services.WaitlistService
+	matchingQueue    *database.RedisClient
+	categories       *services.CategoryService
 }
 
-// NewVolunteerHandler creates a new volunteer handler
-func NewVolunteerHandler(matchingService *services.MatchingService, websocketService *services.WebSocketService, mongoClient *database.MongoClient) *VolunteerHandler {
+// NewVolunteerHandler creates a new volunteer handler. Matched needs are
+// translated into the volunteer's preferred language. New and changed
+// profiles are embedded and matched against waitlistService's needs, by the
+// worker when matchingQueue is non-nil. The categories volunteers filter
+// matches by are checked against categoryService's taxonomy.
+func NewVolunteerHandler(matchingService *services.MatchingService, websocketService *services.WebSocketService, mongoClient *database.MongoClient, moderationService *services.ModerationService, privacyService *services.PrivacyService, translationService *services.TranslationService, waitlistService *services.WaitlistService, matchingQueue *database.RedisClient, categoryService *services.CategoryService) *VolunteerHandler {
 	return &VolunteerHandler{
 		matchingService:  matchingService,
 		websocketService: websocketService,
 		mongoClient:      mongoClient,
+		moderation:       moderationService,
+		privacy:          privacyService,
+		translations:     translationService,
+		waitlist:         waitlistService,
+		matchingQueue:    matchingQueue,
+		categories:       categoryService,
 	}
 }
 
+// matchWaitlist matches a new or changed volunteer profile against the
+// waitlisted needs. Failures only leave the needs waiting, so they are logged.
+func (h *VolunteerHandler) matchWaitlist(ctx context.Context, volunteerID primitive.ObjectID) {
+	if h.matchingQueue != nil {
+		if err := jobs.EnqueueVolunteerMatching(ctx, h.matchingQueue, volunteerID); err != nil {
+			log.Printf("Failed to queue waitlist matching for volunteer %s: %v", volunteerID.Hex(), err)
+		}
+		return
+	}
+	if _, err := h.waitlist.MatchVolunteer(ctx, volunteerID); err != nil {
+		log.Printf("Failed to match volunteer %s against the waitlist: %v", volunteerID.Hex(), err)
+	}
+}
+
+// embedAndMatch generates a new or changed volunteer profile's embedding and
+// then matches it against the waitlisted needs, both on the worker when it
+// runs separately. Only embedding failures in the request are returned.
+func (h *VolunteerHandler) embedAndMatch(ctx context.Context, volunteer *models.Volunteer) error {
+	if h.matchingQueue != nil {
+		if err := jobs.EnqueueVolunteerEmbedding(ctx, h.matchingQueue, volunteer.ID); err != nil {
+			log.Printf("Failed to queue embedding for volunteer %s: %v", volunteer.ID.Hex(), err)
+		}
+		return nil
+	}
+
+	if h.matchingService != nil {
+		if err := h.matchingService.UpdateVolunteerEmbedding(ctx, volunteer); err != nil {
+			return err
+		}
+	}
+	h.matchWaitlist(ctx, volunteer.ID)
+	return nil
+}
+
 // CreateProfile creates a volunteer profile
 func (h *VolunteerHandler) CreateProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -39,8 +93,14 @@ func (h *VolunteerHandler) CreateProfile(c *gin.Context) {
 	}
 
 	var req models.CreateVolunteerRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, ok := requestTimezone(c, req.Timezone); !ok {
+		return
+	}
+	if err := h.categories.CheckAll(c.Request.Context(), req.Categories); err != nil {
+		respondCategoryError(c, err, "Failed to create volunteer profile")
 		return
 	}
 
@@ -62,17 +122,20 @@ func (h *VolunteerHandler) CreateProfile(c *gin.Context) {
 
 	// Create volunteer profile
 	volunteer := models.Volunteer{
-		ID:          primitive.NewObjectID(),
-		UserID:      userObjectID,
-		Skills:      req.Skills,
-		Interests:   req.Interests,
-		Description: req.Description,
+		ID:           primitive.NewObjectID(),
+		UserID:       userObjectID,
+		Skills:       req.Skills,
+		Interests:    req.Interests,
+		Categories:   req.Categories,
+		Description:  req.Description,
 		Availability: req.Availability,
-		Location:    req.Location,
-		Rating:      0.0,
-		TaskCount:   0,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Timezone:     req.Timezone,
+		Location:     indexLocation(c, h.privacy, req.Location),
+		MatchCell:    req.Location.MatchCell(),
+		Rating:       0.0,
+		TaskCount:    0,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
 	}
 
 	// Insert into database
@@ -81,15 +144,12 @@ func (h *VolunteerHandler) CreateProfile(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create volunteer profile"})
 		return
 	}
+	h.moderation.Screen(c.Request.Context(), models.ContentProfile, volunteer.ID, volunteer.UserID, volunteer.Description)
 
 	// Generate embedding for the volunteer
-	if h.matchingService != nil {
-		err = h.matchingService.UpdateVolunteerEmbedding(c.Request.Context(), &volunteer)
-		if err != nil {
-			// Log error but don't fail the request
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Profile created but embedding generation failed"})
-			return
-		}
+	if err := h.embedAndMatch(c.Request.Context(), &volunteer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Profile created but embedding generation failed"})
+		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -142,17 +202,32 @@ func (h *VolunteerHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	var req struct {
-		Skills      []string             `json:"skills,omitempty"`
-		Interests   []string             `json:"interests,omitempty"`
-		Description string               `json:"description,omitempty"`
+		Skills       []string              `json:"skills,omitempty" binding:"max=50,dive,max=64"`
+		Interests    []string              `json:"interests,omitempty" binding:"max=50,dive,max=64"`
+		Description  string                `json:"description,omitempty" binding:"max=5000"`
 		Availability []models.Availability `json:"availability,omitempty"`
-		Location    models.Location      `json:"location,omitempty"`
+		Timezone     string                `json:"timezone,omitempty" binding:"max=64"`
+		Location     models.Location       `json:"location,omitempty"`
+		// Categories replaces the match filters; an empty list clears them
+		Categories *[]string `json:"categories,omitempty" binding:"omitempty,max=50,dive,max=200"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, ok := requestTimezone(c, req.Timezone); !ok {
 		return
 	}
+	if req.Categories != nil {
+		if err := h.categories.CheckAll(c.Request.Context(), *req.Categories); err != nil {
+			respondCategoryError(c, err, "Failed to update volunteer profile")
+			return
+		}
+	}
+	req.Skills = sanitize.Strings(req.Skills)
+	req.Interests = sanitize.Strings(req.Interests)
+	req.Description = sanitize.Text(req.Description)
+	req.Location.Address = sanitize.Text(req.Location.Address)
 
 	// Build update fields
 	updates := bson.M{"updated_at": time.Now()}
@@ -168,8 +243,15 @@ func (h *VolunteerHandler) UpdateProfile(c *gin.Context) {
 	if len(req.Availability) > 0 {
 		updates["availability"] = req.Availability
 	}
+	if req.Timezone != "" {
+		updates["timezone"] = req.Timezone
+	}
+	if req.Categories != nil {
+		updates["categories"] = *req.Categories
+	}
 	if req.Location.Latitude != 0 || req.Location.Longitude != 0 {
-		updates["location"] = req.Location
+		updates["location"] = indexLocation(c, h.privacy, req.Location)
+		updates["match_cell"] = req.Location.MatchCell()
 	}
 
 	// Update in database
@@ -190,18 +272,37 @@ func (h *VolunteerHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	// Regenerate embedding if content changed
-	if len(req.Skills) > 0 || len(req.Interests) > 0 || req.Description != "" {
-		var volunteer models.Volunteer
-		err = collection.FindOne(c.Request.Context(), bson.M{"user_id": userObjectID}).Decode(&volunteer)
-		if err == nil && h.matchingService != nil {
-			h.matchingService.UpdateVolunteerEmbedding(c.Request.Context(), &volunteer)
+	var volunteer models.Volunteer
+	err = collection.FindOne(c.Request.Context(), bson.M{"user_id": userObjectID}).Decode(&volunteer)
+	if err == nil && (len(req.Skills) > 0 || len(req.Interests) > 0 || req.Description != "") {
+		if req.Description != "" {
+			h.moderation.Screen(c.Request.Context(), models.ContentProfile, volunteer.ID, volunteer.UserID, volunteer.Description)
+		}
+		if err := h.embedAndMatch(c.Request.Context(), &volunteer); err != nil {
+			log.Printf("Failed to embed volunteer %s: %v", volunteer.ID.Hex(), err)
 		}
+	} else if err == nil {
+		// A changed profile may now match needs nobody could help with
+		h.matchWaitlist(c.Request.Context(), volunteer.ID)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Volunteer profile updated successfully"})
 }
 
-// GetMatches retrieves matching needs for the current volunteer
+// markViewed records that the volunteer was shown matches they hadn't seen
+func (h *VolunteerHandler) markViewed(ctx context.Context, userID primitive.ObjectID, matches []models.Match) {
+	var needIDs []primitive.ObjectID
+	for i := range matches {
+		if matches[i].State == models.MatchStateSuggested {
+			needIDs = append(needIDs, matches[i].NeedID)
+			matches[i].State = models.MatchStateViewed
+		}
+	}
+	h.matchingService.MarkViewed(ctx, userID, needIDs)
+}
+
+// GetMatches retrieves matching needs for the current volunteer, best
+// first and paged with ?limit= and ?cursor=
 func (h *VolunteerHandler) GetMatches(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -228,18 +329,35 @@ func (h *VolunteerHandler) GetMatches(c *gin.Context) {
 		return
 	}
 
-	// Find matches for the volunteer
+	p, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+
+	// Find matches for the volunteer, ranking every page up to this one
 	var matches []models.Match
+	var next *string
 	if h.matchingService != nil {
-		matches, err = h.matchingService.FindMatchesForVolunteer(c.Request.Context(), &volunteer, 10)
+		matches, err = h.matchingService.FindMatchesForVolunteer(c.Request.Context(), &volunteer, int(p.cursor.Offset+p.fetch()))
+		if err == nil {
+			matches, next = pageOffset(p, skipPages(p, matches))
+			h.markViewed(c.Request.Context(), userObjectID, matches)
+			err = h.matchingService.SummarizeNeeds(c.Request.Context(), matches)
+		}
+		if err == nil {
+			err = h.translations.TranslateMatches(c.Request.Context(), preferredLanguage(c), matches)
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find matches"})
 			return
 		}
 	}
 
+	services.LocalizeMatches(matches, preferredUnits(c), preferredLanguage(c))
+
 	c.JSON(http.StatusOK, models.VolunteerResponse{
-		Volunteer: volunteer,
-		Matches:   matches,
+		Volunteer:  volunteer,
+		Matches:    matches,
+		NextCursor: next,
 	})
 } 
\ No newline at end of file
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/webhooks"
+)
+
+// WebhookHandler handles inbound webhooks from external services
+type WebhookHandler struct {
+	receiver *webhooks.Receiver
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(receiver *webhooks.Receiver) *WebhookHandler {
+	return &WebhookHandler{
+		receiver: receiver,
+	}
+}
+
+// Receive verifies and dispatches a webhook for the provider in the path
+func (h *WebhookHandler) Receive(c *gin.Context) {
+	provider := c.Param("provider")
+	body := middleware.GetRawBody(c)
+
+	err := h.receiver.Receive(c.Request.Context(), provider, c.Request, body)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"received": true})
+	case errors.Is(err, webhooks.ErrDuplicateEvent):
+		// Acknowledge so the provider stops redelivering
+		c.JSON(http.StatusOK, gin.H{"received": true, "duplicate": true})
+	case errors.Is(err, webhooks.ErrUnknownProvider):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown webhook provider"})
+	case errors.Is(err, webhooks.ErrMissingSignature),
+		errors.Is(err, webhooks.ErrInvalidSignature),
+		errors.Is(err, webhooks.ErrStaleTimestamp):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Failed to process %s webhook: %v", provider, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+	}
+} 
\ No newline at end of file
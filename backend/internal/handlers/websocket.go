@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"neighborenexus/internal/i18n"
 	"neighborenexus/internal/middleware"
 	"neighborenexus/internal/models"
 	"neighborenexus/internal/services"
@@ -16,12 +17,34 @@ import (
 // WebSocketHandler handles WebSocket connections
 type WebSocketHandler struct {
 	websocketService *services.WebSocketService
+	upgrader         websocket.Upgrader
 }
 
-// NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(websocketService *services.WebSocketService) *WebSocketHandler {
+// NewWebSocketHandler creates a new WebSocket handler. When allowedOrigins is
+// nil any origin may connect; otherwise the Origin header must match one of them.
+func NewWebSocketHandler(websocketService *services.WebSocketService, allowedOrigins []string) *WebSocketHandler {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+	if allowedOrigins != nil {
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			for _, allowed := range allowedOrigins {
+				if origin == allowed {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
 	return &WebSocketHandler{
 		websocketService: websocketService,
+		upgrader:         upgrader,
 	}
 }
 
@@ -34,7 +57,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	}
 
 	// Upgrade HTTP connection to WebSocket
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
 		return
@@ -42,11 +65,11 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 
 	// Create WebSocket client
 	client := &services.WebSocketClient{
-		ID:       uuid.New().String(),
-		UserID:   userID,
-		Conn:     conn,
-		Send:     make(chan []byte, 256),
-		Service:  h.websocketService,
+		ID:      uuid.New().String(),
+		UserID:  userID,
+		Conn:    conn,
+		Send:    make(chan []byte, 256),
+		Service: h.websocketService,
 	}
 
 	// Register client
@@ -61,7 +84,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		Type: "connected",
 		Payload: map[string]interface{}{
 			"user_id": userID,
-			"message": "Connected to NeighborNexus",
+			"message": i18n.T(middleware.GetLocale(c), "Connected to NeighborNexus"),
 		},
 	}
 
@@ -69,13 +92,4 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	if err == nil {
 		client.Send <- data
 	}
-}
-
-// upgrader is the WebSocket upgrader configuration
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for development
-	},
 } 
\ No newline at end of file
@@ -0,0 +1,493 @@
+package i18n
+
+// spanish translates the API's messages into Spanish, grouped by the area
+// of the API they come from
+var spanish = map[string]string{
+	// Validation
+	"Invalid request data":                              "Datos de solicitud no válidos",
+	"Request body too large":                            "El cuerpo de la solicitud es demasiado grande",
+	"Validation failed":                                 "La validación falló",
+	"at least one skill is required":                    "se requiere al menos una habilidad",
+	"at least one tag or a message is required":         "se requiere al menos una etiqueta o un mensaje",
+	"body is required":                                  "el cuerpo es obligatorio",
+	"date_of_birth is required":                         "date_of_birth es obligatorio",
+	"date_of_birth must be a date in YYYY-MM-DD format": "date_of_birth debe ser una fecha en formato AAAA-MM-DD",
+	"description cannot be empty":                       "la descripción no puede estar vacía",
+	"description is required":                           "la descripción es obligatoria",
+	"duplicate ID":                                      "ID duplicado",
+	"external_id is required":                           "external_id es obligatorio",
+	"failed %s validation":                              "no superó la validación %s",
+	"incident description is required":                  "la descripción del incidente es obligatoria",
+	"is not a category; see GET /categories":            "no es una categoría; consulta GET /categories",
+	"is not a recognized value":                         "no es un valor reconocido",
+	"is required":                                       "es obligatorio",
+	"message is required":                               "el mensaje es obligatorio",
+	"must be a valid email address":                     "debe ser un correo electrónico válido",
+	"must be after %s":                                  "debe ser posterior a %s",
+	"must be at least %s":                               "debe ser al menos %s",
+	"must be at least %s characters":                    "debe tener al menos %s caracteres",
+	"must be at most %s":                                "debe ser como máximo %s",
+	"must be at most %s characters":                     "debe tener como máximo %s caracteres",
+	"must be one of: %s":                                "debe ser uno de: %s",
+	"must contain at least %s items":                    "debe contener al menos %s elementos",
+	"must contain at most %s items":                     "debe contener como máximo %s elementos",
+	"must match the layout %s":                          "debe seguir el formato %s",
+	"name cannot be empty":                              "el nombre no puede estar vacío",
+	"name is required":                                  "el nombre es obligatorio",
+	"reason is required":                                "el motivo es obligatorio",
+	"reference is required":                             "la referencia es obligatoria",
+	"tags must be at most 32 characters":                "las etiquetas deben tener como máximo 32 caracteres",
+	"text is required":                                  "el texto es obligatorio",
+	"title cannot be empty":                             "el título no puede estar vacío",
+	"title is required":                                 "el título es obligatorio",
+	"too many tags":                                     "demasiadas etiquetas",
+
+	// Authentication and accounts
+	"API key required":              "Se requiere una clave de API",
+	"Account erased":                "Cuenta eliminada",
+	"Account suspended":             "Cuenta suspendida",
+	"Authorization header required": "Se requiere el encabezado Authorization",
+	"Date of birth is already set; ask an administrator to correct it": "La fecha de nacimiento ya está registrada; pide a un administrador que la corrija",
+	"Drafting needs from text is not available":                        "La redacción de necesidades a partir de texto no está disponible",
+	"Failed to authenticate":                                           "No se pudo autenticar",
+	"Failed to build need map":                                         "No se pudo crear el mapa de necesidades",
+	"Failed to compute coverage":                                       "No se pudo calcular la cobertura",
+	"Failed to draft need":                                             "No se pudo redactar la necesidad",
+	"Failed to erase account; retry to finish":                         "No se pudo eliminar la cuenta; vuelve a intentarlo para terminar",
+	"Failed to log out":                                                "No se pudo cerrar la sesión",
+	"Failed to reset password":                                         "No se pudo restablecer la contraseña",
+	"Failed to retrieve sessions":                                      "No se pudieron obtener las sesiones",
+	"Failed to revoke session":                                         "No se pudo revocar la sesión",
+	"Failed to revoke sessions":                                        "No se pudieron revocar las sesiones",
+	"Failed to sign in":                                                "No se pudo iniciar sesión",
+	"Failed to transcribe voice note":                                  "No se pudo transcribir la nota de voz",
+	"Hi %s,\n\nSomeone asked to reset the password for your NeighborNexus account. Use this to choose a new one within %d minutes:\n\n%s\n\nIf you did not ask for this, you can ignore this email; your password has not changed.":                                                                                                                                                           "Hola %s,\n\nAlguien pidió restablecer la contraseña de tu cuenta de NeighborNexus. Usa esto para elegir una nueva en un plazo de %d minutos:\n\n%s\n\nSi no lo pediste, puedes ignorar este correo; tu contraseña no ha cambiado.",
+	"Hi %s,\n\nYou signed in to NeighborNexus with %s, so the account registered with this email address is now yours. It had not verified the address, so its password was removed and everyone signed in to it was signed out.\n\nIf you did not register this account, someone else did; check its profile and posts. To sign in with a password as well, reset it from the sign-in page.": "Hola %s,\n\nIniciaste sesión en NeighborNexus con %s, así que la cuenta registrada con esta dirección de correo ahora es tuya. No había verificado la dirección, así que se eliminó su contraseña y se cerró la sesión de todos los que la usaban.\n\nSi no registraste esta cuenta, lo hizo otra persona; revisa su perfil y sus publicaciones. Para iniciar sesión también con contraseña, restablécela desde la página de inicio de sesión.",
+	"If an account uses that email, a password reset link has been sent to it": "Si alguna cuenta usa ese correo, se le ha enviado un enlace para restablecer la contraseña",
+	"Insufficient permissions":                                            "Permisos insuficientes",
+	"Invalid authorization header format":                                 "Formato del encabezado Authorization no válido",
+	"Invalid or expired token":                                            "Token no válido o vencido",
+	"Invalid or revoked API key":                                          "Clave de API no válida o revocada",
+	"Logged out successfully":                                             "Sesión cerrada correctamente",
+	"No speech was found in the voice note":                               "No se encontró voz en la nota de voz",
+	"Password is incorrect":                                               "La contraseña es incorrecta",
+	"Password reset successfully":                                         "Contraseña restablecida correctamente",
+	"Reset your NeighborNexus password":                                   "Restablece tu contraseña de NeighborNexus",
+	"Server is busy, please retry shortly":                                "El servidor está ocupado; vuelve a intentarlo en breve",
+	"Session not found":                                                   "Sesión no encontrada",
+	"Session revoked successfully":                                        "Sesión revocada correctamente",
+	"Sign-in was cancelled or refused":                                    "El inicio de sesión se canceló o fue rechazado",
+	"The provider's sign-in could not be verified":                        "No se pudo verificar el inicio de sesión del proveedor",
+	"Too many requests":                                                   "Demasiadas solicitudes",
+	"User authentication required":                                        "Se requiere autenticación",
+	"User not authenticated":                                              "Usuario no autenticado",
+	"User not found":                                                      "Usuario no encontrado",
+	"User registered successfully":                                        "Usuario registrado correctamente",
+	"Voice notes are not available":                                       "Las notas de voz no están disponibles",
+	"Your NeighborNexus account is now linked":                            "Tu cuenta de NeighborNexus ya está vinculada",
+	"Youth group account not found":                                       "Cuenta de grupo juvenil no encontrada",
+	"audio must be an uploaded voice note":                                "audio debe ser una nota de voz subida",
+	"audio must be one of: %s":                                            "audio debe ser uno de: %s",
+	"bbox must be west,south,east,north in degrees":                       "bbox debe ser west,south,east,north en grados",
+	"code is required":                                                    "code es obligatorio",
+	"date_of_birth is required to create an account; start again with it": "date_of_birth es obligatorio para crear una cuenta; vuelve a empezar indicándolo",
+	"invalid credentials":                                                 "credenciales no válidas",
+	"invalid or revoked API key":                                          "clave de API no válida o revocada",
+	"invalid refresh token":                                               "token de actualización no válido",
+	"invalid token":                                                       "token no válido",
+	"invalid token claims":                                                "datos del token no válidos",
+	"invalid token type":                                                  "tipo de token no válido",
+	"invalid user ID in token":                                            "ID de usuario no válido en el token",
+	"reset token is invalid or has expired":                               "el token de restablecimiento no es válido o ha vencido",
+	"resolution must be between %d and %d":                                "resolution debe estar entre %d y %d",
+	"sign-in link is invalid or has expired; start again":                 "el enlace de inicio de sesión no es válido o ha vencido; vuelve a empezar",
+	"sign-in provider is not available":                                   "el proveedor de inicio de sesión no está disponible",
+	"the provider has not verified this account's email address":          "el proveedor no ha verificado el correo electrónico de esta cuenta",
+	"timezone must be an IANA time zone name, e.g. America/Chicago":       "timezone debe ser un nombre de zona horaria IANA, p. ej. America/Chicago",
+	"user already exists":                                                 "el usuario ya existe",
+	"user not found":                                                      "usuario no encontrado",
+	"youth group accounts must name a supervising adult with supervisor_name and supervisor_email": "las cuentas de grupos juveniles deben indicar un adulto supervisor con supervisor_name y supervisor_email",
+	"youth group accounts need a verified supervising adult before posting or accepting needs":     "las cuentas de grupos juveniles necesitan un adulto supervisor verificado antes de publicar o aceptar necesidades",
+	"zoom must be between 0 and 22": "zoom debe estar entre 0 y 22",
+
+	// Identifiers
+	"Invalid ID":                 "ID no válido",
+	"Invalid announcement ID":    "ID de anuncio no válido",
+	"Invalid category":           "Categoría no válida",
+	"Invalid category ID":        "ID de categoría no válido",
+	"Invalid content type":       "Tipo de contenido no válido",
+	"Invalid contribution ID":    "ID de aporte no válido",
+	"Invalid cursor":             "Cursor no válido",
+	"Invalid document":           "Documento no válido",
+	"Invalid emergency ID":       "ID de emergencia no válido",
+	"Invalid event ID":           "ID de evento no válido",
+	"Invalid group ID":           "ID de grupo no válido",
+	"Invalid invite ID":          "ID de invitación no válido",
+	"Invalid moderation item ID": "ID de elemento de moderación no válido",
+	"Invalid need ID":            "ID de necesidad no válido",
+	"Invalid offer ID":           "ID de oferta no válido",
+	"Invalid partner ID":         "ID de socio no válido",
+	"Invalid payout ID":          "ID de pago no válido",
+	"Invalid post ID":            "ID de publicación no válido",
+	"Invalid shift ID":           "ID de turno no válido",
+	"Invalid signup ID":          "ID de inscripción no válido",
+	"Invalid status":             "Estado no válido",
+	"Invalid task ID":            "ID de tarea no válido",
+	"Invalid upload ID":          "ID de subida no válido",
+	"Invalid urgency":            "Urgencia no válida",
+	"Invalid user ID":            "ID de usuario no válido",
+	"Need ID required":           "Se requiere el ID de la necesidad",
+	"Task ID required":           "Se requiere el ID de la tarea",
+	"invalid ID":                 "ID no válido",
+	"invalid status":             "estado no válido",
+	"invalid user ID":            "ID de usuario no válido",
+
+	// Needs and tasks
+	"Cannot accept your own need":                                 "No puedes aceptar tu propia necesidad",
+	"Category deleted":                                            "Categoría eliminada",
+	"Category not found":                                          "Categoría no encontrada",
+	"Failed to cancel announcement":                               "No se pudo cancelar el anuncio",
+	"Failed to create category":                                   "No se pudo crear la categoría",
+	"Failed to create need":                                       "No se pudo crear la necesidad",
+	"Failed to create task":                                       "No se pudo crear la tarea",
+	"Failed to decode needs":                                      "No se pudieron leer las necesidades",
+	"Failed to decode tasks":                                      "No se pudieron leer las tareas",
+	"Failed to delete category":                                   "No se pudo eliminar la categoría",
+	"Failed to delete need":                                       "No se pudo eliminar la necesidad",
+	"Failed to extend need":                                       "No se pudo extender la necesidad",
+	"Failed to find matches":                                      "No se pudieron encontrar coincidencias",
+	"Failed to retrieve categories":                               "No se pudieron obtener las categorías",
+	"Failed to retrieve contact details":                          "No se pudieron obtener los datos de contacto",
+	"Failed to retrieve need":                                     "No se pudo obtener la necesidad",
+	"Failed to retrieve needs":                                    "No se pudieron obtener las necesidades",
+	"Failed to retrieve task":                                     "No se pudo obtener la tarea",
+	"Failed to retrieve tasks":                                    "No se pudieron obtener las tareas",
+	"Failed to set need images":                                   "No se pudieron establecer las imágenes de la necesidad",
+	"Failed to update category":                                   "No se pudo actualizar la categoría",
+	"Failed to update need":                                       "No se pudo actualizar la necesidad",
+	"Failed to update need status":                                "No se pudo actualizar el estado de la necesidad",
+	"Failed to update task":                                       "No se pudo actualizar la tarea",
+	"Need accepted successfully":                                  "Necesidad aceptada correctamente",
+	"Need created but embedding generation failed":                "Necesidad creada, pero falló la generación del embedding",
+	"Need deleted successfully":                                   "Necesidad eliminada correctamente",
+	"Need not found":                                              "Necesidad no encontrada",
+	"Need not found or already accepted":                          "Necesidad no encontrada o ya aceptada",
+	"Need not found or not owned by user":                         "Necesidad no encontrada o no pertenece al usuario",
+	"Need updated successfully":                                   "Necesidad actualizada correctamente",
+	"No fields to update":                                         "No hay campos para actualizar",
+	"No tags to add or remove":                                    "No hay etiquetas para agregar o quitar",
+	"Task not found":                                              "Tarea no encontrada",
+	"Task status updated successfully":                            "Estado de la tarea actualizado correctamente",
+	"You are posting too quickly; try again later":                "Estás publicando demasiado rápido; inténtalo más tarde",
+	"a category with this slug already exists beneath its parent": "ya existe una categoría con este identificador bajo su categoría superior",
+	"categories can be nested at most 4 levels deep":              "las categorías se pueden anidar como máximo 4 niveles",
+	"category has subcategories; delete them first":               "la categoría tiene subcategorías; elimínelas primero",
+	"contact details are only shared while a task is accepted or in progress": "los datos de contacto solo se comparten mientras una tarea está aceptada o en curso",
+	"failed to cancel need":                                              "no se pudo cancelar la necesidad",
+	"failed to retrieve need":                                            "no se pudo obtener la necesidad",
+	"failed to retrieve task":                                            "no se pudo obtener la tarea",
+	"failed to update tags":                                              "no se pudieron actualizar las etiquetas",
+	"failed to update task":                                              "no se pudo actualizar la tarea",
+	"lat must be between -90 and 90":                                     "lat debe estar entre -90 y 90",
+	"lng must be between -180 and 180":                                   "lng debe estar entre -180 y 180",
+	"need cancelled but failed to cancel its tasks":                      "la necesidad se canceló, pero no se pudieron cancelar sus tareas",
+	"need is no longer open":                                             "la necesidad ya no está abierta",
+	"need not found":                                                     "necesidad no encontrada",
+	"need not found or not owned by user":                                "necesidad no encontrada o no pertenece al usuario",
+	"need not found, not owned by user, or no longer open":               "necesidad no encontrada, no pertenece al usuario o ya no está abierta",
+	"needs can have at most 5 images":                                    "las necesidades pueden tener como máximo 5 imágenes",
+	"needs can only be extended in the 2 days before they expire":        "las necesidades solo se pueden extender en los 2 días antes de que caduquen",
+	"only the need's creator can do this":                                "solo quien creó la necesidad puede hacer esto",
+	"radius_km must be greater than 0 and at most %d":                    "radius_km debe ser mayor que 0 y como máximo %d",
+	"radius_km requires lat and lng":                                     "radius_km requiere lat y lng",
+	"slug must be lowercase letters and digits separated by underscores": "el identificador debe tener letras minúsculas y dígitos separados por guiones bajos",
+	"subcategory must be beneath the need's category":                    "la subcategoría debe estar bajo la categoría de la necesidad",
+	"task is already completed or cancelled":                             "la tarea ya está completada o cancelada",
+	"task not found":                                                     "tarea no encontrada",
+	"task was changed at the same time; try again":                       "la tarea se modificó al mismo tiempo; inténtalo de nuevo",
+	"tasks cannot go back to accepted, and are completed by both participants marking them completed": "las tareas no pueden volver a aceptadas y se completan cuando ambos participantes las marcan como completadas",
+	"the other category is the fallback for needs and cannot be deleted":                              "la categoría other es la categoría de respaldo de las necesidades y no se puede eliminar",
+	"this need cannot be boosted again":                                                               "esta necesidad no se puede volver a impulsar",
+	"this need is limited to volunteers with a higher trust level":                                    "esta necesidad está limitada a voluntarios con un nivel de confianza más alto",
+	"this need was boosted recently; try again later":                                                 "esta necesidad se impulsó hace poco; inténtalo más tarde",
+	"unknown category; see GET /categories":                                                           "categoría desconocida; consulte GET /categories",
+	"your account is paused from matching while moderators review a safety report":                    "tu cuenta está en pausa para las coincidencias mientras los moderadores revisan un reporte de seguridad",
+
+	// Volunteers
+	"An active emergency puts this need first":                                            "Una emergencia activa pone esta necesidad primero",
+	"Availability exception deleted":                                                      "Excepción de disponibilidad eliminada",
+	"Availability exception not found":                                                    "Excepción de disponibilidad no encontrada",
+	"Failed to create volunteer profile":                                                  "No se pudo crear el perfil de voluntario",
+	"Failed to decline match":                                                             "No se pudo rechazar la coincidencia",
+	"Failed to delete availability exception":                                             "No se pudo eliminar la excepción de disponibilidad",
+	"Failed to explain match":                                                             "No se pudo explicar la coincidencia",
+	"Failed to restore match":                                                             "No se pudo restaurar la coincidencia",
+	"Failed to retrieve volunteer profile":                                                "No se pudo obtener el perfil de voluntario",
+	"Failed to save availability exception":                                               "No se pudo guardar la excepción de disponibilidad",
+	"Failed to update volunteer profile":                                                  "No se pudo actualizar el perfil de voluntario",
+	"Invalid exception ID":                                                                "ID de excepción no válido",
+	"Profile created but embedding generation failed":                                     "Perfil creado, pero falló la generación del embedding",
+	"The need is %s away; closer needs score higher":                                      "La necesidad está a %s; las necesidades más cercanas puntúan más alto",
+	"The need is in a category you chose to help with":                                    "La necesidad está en una categoría con la que elegiste ayudar",
+	"Volunteer profile already exists":                                                    "El perfil de voluntario ya existe",
+	"Volunteer profile not found":                                                         "Perfil de voluntario no encontrado",
+	"Volunteer profile updated successfully":                                              "Perfil de voluntario actualizado correctamente",
+	"Volunteers can keep at most %d availability exceptions":                              "Los voluntarios pueden tener como máximo %d excepciones de disponibilidad",
+	"You are free for as long as the need takes":                                          "Tienes tiempo libre durante todo lo que lleva la necesidad",
+	"You have some time free, though less than the need takes or later than it is wanted": "Tienes algo de tiempo libre, aunque menos del que lleva la necesidad o más tarde de lo que se quiere",
+	"You help with needs in every category":                                               "Ayudas con necesidades de todas las categorías",
+	"Your skills and interests are a good fit for this need":                              "Tus habilidades e intereses encajan bien con esta necesidad",
+	"Your skills and interests are a partial fit for this need":                           "Tus habilidades e intereses encajan en parte con esta necesidad",
+	"Your skills and interests are a strong fit for this need":                            "Tus habilidades e intereses encajan muy bien con esta necesidad",
+	"end_time must be after start_time":                                                   "end_time debe ser posterior a start_time",
+	"match not found":                                                                     "coincidencia no encontrada",
+	"match was already accepted":                                                          "la coincidencia ya fue aceptada",
+	"this match has no breakdown yet; it gets one the next time matching runs":            "esta coincidencia aún no tiene desglose; lo tendrá la próxima vez que se busquen coincidencias",
+
+	// Messages
+	"Failed to mark messages read":                       "No se pudieron marcar los mensajes como leídos",
+	"Failed to retrieve messages":                        "No se pudieron obtener los mensajes",
+	"Failed to send message":                             "No se pudo enviar el mensaje",
+	"messages can only be sent while the task is active": "solo se pueden enviar mensajes mientras la tarea está activa",
+
+	// Feedback, kudos, and points
+	"Failed to check pending feedback":                                  "No se pudieron revisar las opiniones pendientes",
+	"Feedback not found":                                                "Opinión no encontrada",
+	"Kudos sent successfully":                                           "Reconocimiento enviado correctamente",
+	"Testimonial withdrawn":                                             "Testimonio retirado",
+	"feedback can only be left on completed tasks":                      "solo se pueden dejar opiniones sobre tareas completadas",
+	"feedback not found":                                                "opinión no encontrada",
+	"kudos can only be sent for completed tasks":                        "solo se pueden enviar reconocimientos por tareas completadas",
+	"leave feedback on your completed tasks before accepting new needs": "deja tu opinión sobre tus tareas completadas antes de aceptar nuevas necesidades",
+	"max_rating must be between 1 and 5":                                "max_rating debe estar entre 1 y 5",
+	"only feedback with a comment can be shared":                        "solo se pueden compartir opiniones con comentario",
+	"only ratings of 2 stars or fewer can be responded to or appealed":  "solo se puede responder o apelar calificaciones de 2 estrellas o menos",
+	"only the person helped can send kudos for a task":                  "solo la persona ayudada puede enviar un reconocimiento por una tarea",
+	"period must be week, month, or all":                                "period debe ser week, month o all",
+	"the author has not shared this feedback":                           "quien escribió esta opinión no la ha compartido",
+	"the feedback window for this task has closed":                      "el plazo para opinar sobre esta tarea ha terminado",
+	"this feedback has already been appealed":                           "esta opinión ya fue apelada",
+	"you have already left feedback on this task":                       "ya dejaste tu opinión sobre esta tarea",
+	"you have already responded to this feedback":                       "ya respondiste a esta opinión",
+	"you have already sent kudos for this task":                         "ya enviaste un reconocimiento por esta tarea",
+
+	// Offers and contributions
+	"Failed to retrieve contributions":                             "No se pudieron obtener los aportes",
+	"Failed to retrieve offers":                                    "No se pudieron obtener las ofertas",
+	"Failed to retrieve payouts":                                   "No se pudieron obtener los pagos",
+	"Offer accepted successfully":                                  "Oferta aceptada correctamente",
+	"Offer closed":                                                 "Oferta cerrada",
+	"Offer not found":                                              "Oferta no encontrada",
+	"contribution exceeds the amount still needed":                 "el aporte supera el monto que aún se necesita",
+	"contribution not found":                                       "aporte no encontrado",
+	"contributions are not enabled on this deployment":             "los aportes no están habilitados en esta instalación",
+	"material cost cannot be lower than the amount already raised": "el costo de materiales no puede ser menor que el monto ya recaudado",
+	"need has no material cost":                                    "la necesidad no tiene costo de materiales",
+	"offer is not taking new needs":                                "la oferta no está aceptando nuevas necesidades",
+	"offer not found":                                              "oferta no encontrada",
+	"payout not found":                                             "pago no encontrado",
+	"this offer cannot take on needs in that category":             "esta oferta no puede atender necesidades de esa categoría",
+	"you cannot accept your own offer":                             "no puedes aceptar tu propia oferta",
+	"you cannot contribute to your own need":                       "no puedes aportar a tu propia necesidad",
+
+	// Groups, events, and posts
+	"Event cancelled":               "Evento cancelado",
+	"Failed to retrieve event":      "No se pudo obtener el evento",
+	"Failed to retrieve group":      "No se pudo obtener el grupo",
+	"Failed to retrieve group feed": "No se pudo obtener la actividad del grupo",
+	"Failed to retrieve groups":     "No se pudieron obtener los grupos",
+	"Failed to retrieve members":    "No se pudieron obtener los miembros",
+	"Failed to retrieve posts":      "No se pudieron obtener las publicaciones",
+	"Failed to retrieve signups":    "No se pudieron obtener las inscripciones",
+	"Group deleted":                 "Grupo eliminado",
+	"Group not found":               "Grupo no encontrado",
+	"Left group":                    "Saliste del grupo",
+	"Member removed":                "Miembro eliminado",
+	"Post deleted":                  "Publicación eliminada",
+	"Post not found":                "Publicación no encontrada",
+	"Reaction removed":              "Reacción eliminada",
+	"Signup cancelled":              "Inscripción cancelada",
+	"a group must keep at least one admin; promote another member first":        "un grupo debe tener al menos un administrador; primero asciende a otro miembro",
+	"already a member of this group":                                            "ya eres miembro de este grupo",
+	"already signed up":                                                         "ya estás inscrito",
+	"area must be a list of valid H3 cells or a boundary polygon, but not both": "area debe ser una lista de celdas H3 válidas o un polígono de límite, pero no ambos",
+	"capacity is required for events without shifts":                            "la capacidad es obligatoria para eventos sin turnos",
+	"each shift must end after it starts":                                       "cada turno debe terminar después de empezar",
+	"ends_at must be after starts_at":                                           "ends_at debe ser posterior a starts_at",
+	"event is no longer taking signups":                                         "el evento ya no acepta inscripciones",
+	"event must start in the future":                                            "el evento debe comenzar en el futuro",
+	"event not found":                                                           "evento no encontrado",
+	"group area is too large":                                                   "el área del grupo es demasiado grande",
+	"group not found":                                                           "grupo no encontrado",
+	"no places left":                                                            "no quedan lugares",
+	"not a member of this group":                                                "no eres miembro de este grupo",
+	"only group admins can do this":                                             "solo los administradores del grupo pueden hacer esto",
+	"only the author can delete this post":                                      "solo quien escribió la publicación puede eliminarla",
+	"only the event's organizers can do this":                                   "solo los organizadores del evento pueden hacer esto",
+	"only verified organizers can post to neighborhood feeds":                   "solo los organizadores verificados pueden publicar en la actividad del vecindario",
+	"post not found":                                                            "publicación no encontrada",
+	"shift not found":                                                           "turno no encontrado",
+	"shift_id is required for events with shifts":                               "shift_id es obligatorio para eventos con turnos",
+	"shifts must fall within the event":                                         "los turnos deben estar dentro del horario del evento",
+	"signup not found":                                                          "inscripción no encontrada",
+
+	// Announcements and emergencies
+	"Announcement cancelled":              "Anuncio cancelado",
+	"Announcement not found":              "Anuncio no encontrado",
+	"Emergency not found":                 "Emergencia no encontrada",
+	"Failed to create announcement":       "No se pudo crear el anuncio",
+	"Failed to declare emergency":         "No se pudo declarar la emergencia",
+	"Failed to end emergency":             "No se pudo finalizar la emergencia",
+	"Failed to retrieve announcements":    "No se pudieron obtener los anuncios",
+	"Failed to retrieve emergencies":      "No se pudieron obtener las emergencias",
+	"Failed to retrieve emergency feed":   "No se pudo obtener la actividad de la emergencia",
+	"announcement not found":              "anuncio no encontrado",
+	"emergency not found":                 "emergencia no encontrada",
+	"expires_at must be after publish_at": "expires_at debe ser posterior a publish_at",
+
+	// Referrals and partners
+	"Failed to check invite code":                       "No se pudo verificar el código de invitación",
+	"Failed to create invite":                           "No se pudo crear la invitación",
+	"Failed to retrieve invites":                        "No se pudieron obtener las invitaciones",
+	"Failed to retrieve referrals":                      "No se pudieron obtener las derivaciones",
+	"Failed to revoke invite":                           "No se pudo revocar la invitación",
+	"Invite not found":                                  "Invitación no encontrada",
+	"Invite revoked":                                    "Invitación revocada",
+	"Partner not found":                                 "Socio no encontrado",
+	"Partner revoked":                                   "Socio revocado",
+	"Referral not found":                                "Derivación no encontrada",
+	"invite code is invalid or has expired":             "el código de invitación no es válido o ha vencido",
+	"invite not found":                                  "invitación no encontrada",
+	"partner organization not found":                    "organización socia no encontrada",
+	"referral is no longer open":                        "la derivación ya no está abierta",
+	"referral not found":                                "derivación no encontrada",
+	"too many active invites; revoke one first":         "demasiadas invitaciones activas; revoca una primero",
+	"webhook URL must be an absolute http or https URL": "la URL del webhook debe ser una URL http o https absoluta",
+
+	// Moderation and reports
+	"Content not found":                      "Contenido no encontrado",
+	"Failed to retrieve moderation queue":    "No se pudo obtener la cola de moderación",
+	"Failed to retrieve reports":             "No se pudieron obtener los reportes",
+	"Failed to submit report":                "No se pudo enviar el reporte",
+	"Failed to update moderation item":       "No se pudo actualizar el elemento de moderación",
+	"Moderation item already resolved":       "El elemento de moderación ya fue resuelto",
+	"Moderation item not found":              "Elemento de moderación no encontrado",
+	"Report submitted":                       "Reporte enviado",
+	"You have already reported this content": "Ya reportaste este contenido",
+	"content already reported":               "contenido ya reportado",
+	"content not found":                      "contenido no encontrado",
+	"field cannot be edited":                 "el campo no se puede editar",
+	"moderation item already resolved":       "el elemento de moderación ya fue resuelto",
+	"moderation item not found":              "elemento de moderación no encontrado",
+
+	// Privacy, consent, and exports
+	"Download link has expired; request a new export": "El enlace de descarga venció; solicita una nueva exportación",
+	"Export not found":                                                "Exportación no encontrada",
+	"Failed to create upload":                                         "No se pudo crear la subida",
+	"Failed to load upload":                                           "No se pudo cargar la subida",
+	"Failed to open export":                                           "No se pudo abrir la exportación",
+	"Failed to publish policy":                                        "No se pudo publicar la política",
+	"Failed to record consent":                                        "No se pudo registrar el consentimiento",
+	"Failed to remove profile photo":                                  "No se pudo quitar la foto de perfil",
+	"Failed to retrieve export":                                       "No se pudo obtener la exportación",
+	"Failed to retrieve policies":                                     "No se pudieron obtener las políticas",
+	"Failed to set profile photo":                                     "No se pudo establecer la foto de perfil",
+	"Failed to start export":                                          "No se pudo iniciar la exportación",
+	"Failed to update privacy settings":                               "No se pudo actualizar la configuración de privacidad",
+	"No export requested":                                             "No se solicitó ninguna exportación",
+	"That is not the current version of the policy":                   "Esa no es la versión vigente de la política",
+	"That policy version has already been published":                  "Esa versión de la política ya fue publicada",
+	"Upload not found":                                                "Subida no encontrada",
+	"export has expired":                                              "la exportación venció",
+	"export not found":                                                "exportación no encontrada",
+	"format must be csv or ndjson":                                    "format debe ser csv o ndjson",
+	"image is larger than the upload limit":                           "la imagen supera el límite de subida",
+	"images must be JPEG, PNG, WebP, or GIF":                          "las imágenes deben ser JPEG, PNG, WebP o GIF",
+	"policy version already published":                                "versión de la política ya publicada",
+	"policy version is not the current version":                       "la versión de la política no es la vigente",
+	"upload has not finished; send the file to the upload URL first":  "la subida no ha terminado; envía primero el archivo a la URL de subida",
+	"upload is already attached":                                      "la subida ya está adjunta",
+	"upload was made for a different purpose":                         "la subida se hizo para otro fin",
+	"uploaded file does not match the declared content type and size": "el archivo subido no coincide con el tipo de contenido y el tamaño declarados",
+	"uploads are not enabled on this deployment":                      "las subidas no están habilitadas en esta instalación",
+
+	// Administration
+	"Activity exports span at most 366 days": "Las exportaciones de actividad abarcan como máximo 366 días",
+	"Failed to boost need":                   "No se pudo impulsar la necesidad",
+	"Failed to check for changes":            "No se pudieron revisar los cambios",
+	"Failed to compute SLA metrics":          "No se pudieron calcular las métricas de SLA",
+	"Failed to compute metrics":              "No se pudieron calcular las métricas",
+	"Failed to compute neighborhood health":  "No se pudo calcular el estado del vecindario",
+	"Failed to compute stats":                "No se pudieron calcular las estadísticas",
+	"Failed to process webhook":              "No se pudo procesar el webhook",
+	"Failed to read request body":            "No se pudo leer el cuerpo de la solicitud",
+	"Failed to reinstate user":               "No se pudo restituir al usuario",
+	"Failed to reset settings":               "No se pudo restablecer la configuración",
+	"Failed to retrieve audit log":           "No se pudo obtener el registro de auditoría",
+	"Failed to retrieve user":                "No se pudo obtener el usuario",
+	"Failed to retrieve users":               "No se pudieron obtener los usuarios",
+	"Failed to suspend user":                 "No se pudo suspender al usuario",
+	"Failed to update identity":              "No se pudo actualizar la identidad",
+	"Failed to update organizer":             "No se pudo actualizar el organizador",
+	"Failed to update settings":              "No se pudo actualizar la configuración",
+	"Failed to update supervision":           "No se pudo actualizar la supervisión",
+	"Failed to update user role":             "No se pudo actualizar el rol del usuario",
+	"Identity updated successfully":          "Identidad actualizada correctamente",
+	"Organizer updated successfully":         "Organizador actualizado correctamente",
+	"SLA metrics span at most 366 days":      "Las métricas de SLA abarcan como máximo 366 días",
+	"Stats span at most 366 days":            "Las estadísticas abarcan como máximo 366 días",
+	"Supervision updated successfully":       "Supervisión actualizada correctamente",
+	"Suspended user not found":               "Usuario suspendido no encontrado",
+	"Unknown webhook provider":               "Proveedor de webhook desconocido",
+	"User reinstated successfully":           "Usuario restituido correctamente",
+	"User role updated successfully":         "Rol del usuario actualizado correctamente",
+	"User suspended successfully":            "Usuario suspendido correctamente",
+	"You cannot suspend your own account":    "No puedes suspender tu propia cuenta",
+	"days must be between 1 and 365":         "days debe estar entre 1 y 365",
+	"from must be an RFC 3339 timestamp":     "from debe ser una marca de tiempo RFC 3339",
+	"neighborhood must be a valid H3 cell at neighborhood resolution or finer": "neighborhood debe ser una celda H3 válida con resolución de vecindario o más fina",
+	"regions must be valid H3 cells at the given resolution":                   "regions debe contener celdas H3 válidas con la resolución indicada",
+	"resolution must be between 4 and 9":                                       "resolution debe estar entre 4 y 9",
+	"to must be after from":                                                    "to debe ser posterior a from",
+	"to must be an RFC 3339 timestamp":                                         "to debe ser una marca de tiempo RFC 3339",
+	"until must be in the future":                                              "until debe estar en el futuro",
+	"updated_since must be an RFC 3339 timestamp":                              "updated_since debe ser una marca de tiempo RFC 3339",
+
+	// Notifications
+	"A need is overdue":                         "Una necesidad está atrasada",
+	"A neighbor near you needs help":            "Un vecino cerca de ti necesita ayuda",
+	"A neighbor took up your offer":             "Un vecino aceptó tu oferta",
+	"A safety incident was reported":            "Se reportó un incidente de seguridad",
+	"A task was completed":                      "Se completó una tarea",
+	"A task was updated":                        "Se actualizó una tarea",
+	"A volunteer accepted your need":            "Un voluntario aceptó tu necesidad",
+	"An event you signed up for was cancelled":  "Se canceló un evento al que te inscribiste",
+	"Connected to NeighborNexus":                "Conectado a NeighborNexus",
+	"Device not found":                          "Dispositivo no encontrado",
+	"Device unregistered":                       "Dispositivo dado de baja",
+	"Failed to count unread notifications":      "No se pudieron contar las notificaciones no leídas",
+	"Failed to mark notification read":          "No se pudo marcar la notificación como leída",
+	"Failed to mark notifications read":         "No se pudieron marcar las notificaciones como leídas",
+	"Failed to register device":                 "No se pudo registrar el dispositivo",
+	"Failed to retrieve notifications":          "No se pudieron obtener las notificaciones",
+	"Failed to unregister device":               "No se pudo dar de baja el dispositivo",
+	"Failed to update notification preferences": "No se pudieron actualizar las preferencias de notificación",
+	"Hi %s,\n\n%s offered to help with \"%s\". You can coordinate with them in the task chat.":                                                                     "Hola %s,\n\n%s se ofreció a ayudar con \"%s\". Puedes coordinarte en el chat de la tarea.",
+	"Hi %s,\n\nA neighbor %s away needs help with \"%s\", and your volunteer profile is a good match.":                                                             "Hola %s,\n\nUn vecino a %s necesita ayuda con \"%s\", y tu perfil de voluntario encaja bien.",
+	"Hi %s,\n\nNobody took on \"%s\" before it expired, so volunteers no longer see it. If you still need help, you can extend it by a week from the need's page.": "Hola %s,\n\nNadie se hizo cargo de \"%s\" antes de que venciera, así que los voluntarios ya no la ven. Si todavía necesitas ayuda, puedes extenderla una semana desde la página de la necesidad.",
+	"Hi %s,\n\nThe task for \"%s\" was marked as completed. Thank you for helping your neighborhood!":                                                              "Hola %s,\n\nLa tarea de \"%s\" se marcó como completada. ¡Gracias por ayudar a tu vecindario!",
+	"Invalid notification ID":                                            "ID de notificación no válido",
+	"New announcement":                                                   "Nuevo anuncio",
+	"Notification not found":                                             "Notificación no encontrada",
+	"Reminder: your volunteer shift is coming up":                        "Recordatorio: se acerca tu turno de voluntariado",
+	"Volunteers are now available for your need":                         "Ya hay voluntarios disponibles para tu necesidad",
+	"You can choose which emails you get in your notification settings.": "Puedes elegir qué correos recibes en tu configuración de notificaciones.",
+	"You have a new match":                                               "Tienes una nueva coincidencia",
+	"You have a new message":                                             "Tienes un nuevo mensaje",
+	"Your digest of needs near you":                                      "Tu resumen de necesidades cerca de ti",
+	"Your need expired":                                                  "Tu necesidad venció",
+	"Your report was reviewed":                                           "Tu reporte fue revisado",
+
+	// Labels
+	"Automatically translated; the original may differ": "Traducido automáticamente; el original puede diferir",
+} 
\ No newline at end of file
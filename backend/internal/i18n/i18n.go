@@ -0,0 +1,97 @@
+// Package i18n translates the API's user-facing text. Messages are keyed by
+// their English text, so English needs no catalog and any message missing
+// from a catalog falls back to English.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages the API can answer in
+const (
+	English = "en"
+	Spanish = "es"
+)
+
+// Default is the language used when the client accepts none we support
+const Default = English
+
+// decimalCommas lists the languages that write decimals with a comma
+var decimalCommas = map[string]bool{Spanish: true}
+
+// catalogs maps each language but English to its translations
+var catalogs = map[string]map[string]string{
+	Spanish: spanish,
+}
+
+// Supported reports whether lang is a language the API can answer in
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok || lang == English
+}
+
+// Languages lists the languages the API can answer in
+func Languages() []string {
+	return []string{English, Spanish}
+}
+
+// Negotiate picks the most preferred supported language from an
+// Accept-Language header, matching on the primary subtag so es-MX gets
+// Spanish. It returns Default when nothing matches.
+func Negotiate(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 && (lang == "*" || Supported(lang)) {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+
+	// A stable sort keeps the client's order among equal qualities
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	if len(candidates) == 0 || candidates[0].lang == "*" {
+		return Default
+	}
+	return candidates[0].lang
+}
+
+// T translates message into lang, falling back to the English message
+func T(lang, message string) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// FormatDecimal formats value to one decimal place in lang's notation
+func FormatDecimal(lang string, value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', 1, 64)
+	if decimalCommas[lang] {
+		formatted = strings.Replace(formatted, ".", ",", 1)
+	}
+	return formatted
+}
+
+// Tf translates format into lang and formats it with args
+func Tf(lang, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(lang, format), args...)
+} 
\ No newline at end of file
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/webhooks"
+)
+
+// QueueAnalytics is the queue of analytics events awaiting storage
+const QueueAnalytics = "analytics"
+
+// EnqueueAnalyticsEvent queues a pseudonymized analytics event
+func EnqueueAnalyticsEvent(ctx context.Context, broker database.Broker, event models.AnalyticsEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueAnalytics, string(payload))
+}
+
+// AnalyticsHandler stores queued events in the analytics_events collection
+// and forwards them to sink when it is enabled. Events are stored by ID, so a
+// redelivered event is not counted twice.
+func AnalyticsHandler(mongoClient *database.MongoClient, sink *webhooks.Sender) Handler {
+	return func(ctx context.Context, payload string) error {
+		var event models.AnalyticsEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return Permanent(fmt.Errorf("invalid analytics event %q: %w", payload, err))
+		}
+
+		_, err := mongoClient.GetCollection("analytics_events").InsertOne(ctx, event)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to store analytics event %s: %w", event.ID, err)
+		}
+
+		if !sink.Enabled() {
+			return nil
+		}
+		return sink.Send(ctx, webhooks.OutboundEvent{
+			ID:        event.ID,
+			Type:      "analytics." + string(event.Type),
+			CreatedAt: event.OccurredAt,
+			Data: map[string]interface{}{
+				"actor":      event.Actor,
+				"properties": event.Properties,
+			},
+		})
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// announcementCheckInterval is how often workers look for announcements due to publish
+const announcementCheckInterval = time.Minute
+
+// announcementBatchSize caps the recipients of each queued notification
+const announcementBatchSize = 500
+
+// Announcements returns a job that publishes scheduled announcements once
+// their publish time passes, notifying every targeted user
+func Announcements(announcementService *services.AnnouncementService, redisClient *database.RedisClient) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(announcementCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := publishAnnouncements(ctx, announcementService, redisClient); err != nil && ctx.Err() == nil {
+				log.Printf("Announcement delivery failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// publishAnnouncements publishes every announcement that is due
+func publishAnnouncements(ctx context.Context, announcementService *services.AnnouncementService, redisClient *database.RedisClient) error {
+	for {
+		announcement, err := announcementService.ClaimDue(ctx)
+		if err != nil || announcement == nil {
+			return err
+		}
+		if announcement.ExpiresAt != nil && announcement.ExpiresAt.Before(time.Now()) {
+			continue
+		}
+
+		userIDs, err := announcementService.Recipients(ctx, announcement)
+		if err != nil {
+			return err
+		}
+
+		message := models.WebSocketMessage{
+			Type: "announcement",
+			Payload: map[string]interface{}{
+				"id":    announcement.ID.Hex(),
+				"title": announcement.Title,
+				"body":  announcement.Body,
+			},
+		}
+		for start := 0; start < len(userIDs); start += announcementBatchSize {
+			end := start + announcementBatchSize
+			if end > len(userIDs) {
+				end = len(userIDs)
+			}
+			if err := EnqueueNotification(ctx, redisClient, userIDs[start:end], message); err != nil {
+				return err
+			}
+		}
+
+		if err := announcementService.MarkDelivered(ctx, announcement.ID, len(userIDs)); err != nil {
+			return err
+		}
+		log.Printf("Published announcement %s to %d users", announcement.ID.Hex(), len(userIDs))
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"neighborenexus/internal/database"
+)
+
+// pollTimeout bounds each blocking dequeue so consumers notice shutdown
+const pollTimeout = 5 * time.Second
+
+// retryBackoff is the wait before a failed job's first retry, doubling
+// before each one after
+const retryBackoff = time.Second
+
+// Handler processes one job payload from a queue
+type Handler func(ctx context.Context, payload string) error
+
+// permanentError marks a job failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps a handler error, such as a malformed payload, so the job
+// is dead-lettered without being retried
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Consumer pulls jobs from a Redis queue and hands them to a Handler
+type Consumer struct {
+	redisClient *database.RedisClient
+	queue       string
+	handler     Handler
+	attempts    int
+}
+
+// NewConsumer creates a consumer for the named queue. Failed jobs are
+// retried with exponential backoff until they have been tried attempts
+// times, then moved to the queue's dead letters.
+func NewConsumer(redisClient *database.RedisClient, queue string, handler Handler, attempts int) *Consumer {
+	return &Consumer{
+		redisClient: redisClient,
+		queue:       queue,
+		handler:     handler,
+		attempts:    attempts,
+	}
+}
+
+// Run processes jobs until ctx is cancelled. A job that is being handled
+// when ctx is cancelled runs to completion with the cancelled context.
+func (c *Consumer) Run(ctx context.Context) {
+	log.Printf("Consuming jobs from queue %s", c.queue)
+	for ctx.Err() == nil {
+		payload, err := c.redisClient.DequeueJobTimeout(ctx, c.queue, pollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("Failed to dequeue from %s: %v", c.queue, err)
+			time.Sleep(time.Second)
+			continue
+		}
+		if payload == "" {
+			continue
+		}
+
+		c.handle(ctx, payload)
+	}
+	log.Printf("Stopped consuming jobs from queue %s", c.queue)
+}
+
+// handle runs one job, retrying it until it succeeds, fails permanently, or
+// runs out of attempts. A job waiting to be retried at shutdown goes back on
+// the queue.
+func (c *Consumer) handle(ctx context.Context, payload string) {
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.handler(ctx, payload)
+		if err == nil {
+			return
+		}
+
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt >= c.attempts {
+			log.Printf("Job on queue %s failed after %d attempts: %v", c.queue, attempt, err)
+			c.deadLetter(payload, err, attempt)
+			return
+		}
+		log.Printf("Job on queue %s failed, retrying in %s: %v", c.queue, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			if err := c.redisClient.EnqueueJob(context.Background(), c.queue, payload); err != nil {
+				log.Printf("Failed to requeue job on %s at shutdown: %v", c.queue, err)
+			}
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// deadLetter records a failed job for inspection and requeueing
+func (c *Consumer) deadLetter(payload string, err error, attempts int) {
+	if err := recordDeadJob(context.Background(), c.redisClient, DeadJob{
+		Queue:    c.queue,
+		Payload:  payload,
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	}); err != nil {
+		log.Printf("Failed to dead-letter job on %s: %v", c.queue, err)
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"neighborenexus/internal/database"
+)
+
+// deadJobLimit is how many dead letters each queue keeps
+const deadJobLimit = 1000
+
+// Queues lists every queue with a consumer, for tools that inspect them
+var Queues = []string{QueueEmbeddings, QueueMatching, QueueNotifications, QueueExports, QueuePartnerEvents, QueueReferralEvents, QueueAnalytics, QueueEmails, QueuePush}
+
+// DeadJob is a job that failed on every attempt, or failed in a way
+// retrying could not fix
+type DeadJob struct {
+	Queue    string    `json:"queue"`
+	Payload  string    `json:"payload"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// recordDeadJob adds job to its queue's dead letters
+func recordDeadJob(ctx context.Context, redisClient *database.RedisClient, job DeadJob) error {
+	entry, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return redisClient.DeadLetterJob(ctx, job.Queue, string(entry), deadJobLimit)
+}
+
+// DeadJobs returns up to limit of queue's dead letters, newest first
+func DeadJobs(ctx context.Context, redisClient *database.RedisClient, queue string, limit int64) ([]DeadJob, error) {
+	entries, err := redisClient.DeadJobs(ctx, queue, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]DeadJob, 0, len(entries))
+	for _, entry := range entries {
+		var job DeadJob
+		if err := json.Unmarshal([]byte(entry), &job); err != nil {
+			return nil, fmt.Errorf("invalid dead letter on %s: %w", queue, err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// RequeueDeadJobs moves queue's dead letters back onto it, oldest first,
+// returning how many were requeued. Requeued jobs get a fresh set of attempts.
+func RequeueDeadJobs(ctx context.Context, redisClient *database.RedisClient, queue string) (int, error) {
+	requeued := 0
+	for {
+		entry, err := redisClient.PopDeadJob(ctx, queue)
+		if err != nil {
+			return requeued, err
+		}
+		if entry == "" {
+			return requeued, nil
+		}
+
+		var job DeadJob
+		if err := json.Unmarshal([]byte(entry), &job); err != nil {
+			return requeued, fmt.Errorf("invalid dead letter on %s: %w", queue, err)
+		}
+		if err := redisClient.EnqueueJob(ctx, queue, job.Payload); err != nil {
+			// Keep the dead letter rather than losing the job
+			if err := redisClient.DeadLetterJob(ctx, queue, entry, deadJobLimit); err != nil {
+				return requeued, fmt.Errorf("failed to restore dead letter on %s: %w", queue, err)
+			}
+			return requeued, err
+		}
+		requeued++
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// digestSize is the number of open needs included in each volunteer's digest
+const digestSize = 5
+
+// digestCheckInterval is how often workers check whether a digest is due
+const digestCheckInterval = time.Minute
+
+// Digests returns a job that sends every volunteer a digest of their best
+// matching open needs and the announcements targeted at them once per
+// interval. Workers race for a per-period Redis lock, so running several
+// workers still sends each digest once.
+func Digests(matchingService *services.MatchingService, announcementService *services.AnnouncementService, mongoClient *database.MongoClient, redisClient *database.RedisClient, interval time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			period := time.Now().Unix() / int64(interval.Seconds())
+			acquired, err := redisClient.SetNX(ctx, "lock:digest:"+strconv.FormatInt(period, 10), time.Now().Unix(), interval)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to acquire digest lock: %v", err)
+			}
+			if acquired {
+				sent, err := sendDigests(ctx, matchingService, announcementService, mongoClient, redisClient)
+				if err != nil {
+					log.Printf("Digest run failed after %d digests: %v", sent, err)
+				} else {
+					log.Printf("Sent %d volunteer digests", sent)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// sendDigests queues a digest notification for each volunteer with matches
+// or announcements
+func sendDigests(ctx context.Context, matchingService *services.MatchingService, announcementService *services.AnnouncementService, mongoClient *database.MongoClient, redisClient *database.RedisClient) (int, error) {
+	announcements, err := announcementService.Active(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	cursor, err := mongoClient.GetCollection("volunteers").Find(ctx, bson.M{"hidden": bson.M{"$ne": true}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	sent := 0
+	for cursor.Next(ctx) {
+		var volunteer models.Volunteer
+		if err := cursor.Decode(&volunteer); err != nil {
+			return sent, err
+		}
+
+		needIDs := []string{}
+		if len(volunteer.Embedding) > 0 {
+			matches, err := matchingService.FindMatchesForVolunteer(ctx, &volunteer, digestSize)
+			if err == nil {
+				for _, match := range matches {
+					needIDs = append(needIDs, match.NeedID.Hex())
+				}
+			}
+		}
+
+		announcementIDs := []string{}
+		if len(announcements) > 0 {
+			var user models.User
+			if err := mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": volunteer.UserID}).Decode(&user); err == nil {
+				for _, announcement := range announcementService.Filter(announcements, &user, &volunteer) {
+					announcementIDs = append(announcementIDs, announcement.ID.Hex())
+				}
+			}
+		}
+
+		if len(needIDs) == 0 && len(announcementIDs) == 0 {
+			continue
+		}
+
+		err = EnqueueNotification(ctx, redisClient, []string{volunteer.UserID.Hex()}, models.WebSocketMessage{
+			Type: "digest",
+			Payload: map[string]interface{}{
+				"need_ids":         needIDs,
+				"announcement_ids": announcementIDs,
+			},
+		})
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, cursor.Err()
+} 
\ No newline at end of file
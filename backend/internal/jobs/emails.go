@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/email"
+)
+
+// QueueEmails is the queue of email awaiting sending
+const QueueEmails = "emails"
+
+// EmailQueue is an email.Sender that queues messages for the worker to send,
+// so request handlers never wait on the mail provider
+type EmailQueue struct {
+	redisClient *database.RedisClient
+}
+
+// NewEmailQueue creates an email queue
+func NewEmailQueue(redisClient *database.RedisClient) *EmailQueue {
+	return &EmailQueue{redisClient: redisClient}
+}
+
+// Send queues msg
+func (q *EmailQueue) Send(ctx context.Context, msg email.Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return q.redisClient.EnqueueJob(ctx, QueueEmails, string(payload))
+}
+
+// EmailHandler sends queued email through sender
+func EmailHandler(sender email.Sender) Handler {
+	return func(ctx context.Context, payload string) error {
+		var msg email.Message
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			return Permanent(fmt.Errorf("invalid email job: %w", err))
+		}
+		return sender.Send(ctx, msg)
+	}
+} 
\ No newline at end of file
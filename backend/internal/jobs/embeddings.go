@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// QueueEmbeddings is the queue of documents whose embeddings need (re)generating
+const QueueEmbeddings = "embeddings"
+
+// Embedding job kinds
+const (
+	EmbeddingKindNeed      = "need"
+	EmbeddingKindVolunteer = "volunteer"
+	EmbeddingKindOffer     = "offer"
+)
+
+// EmbeddingJob asks the worker to regenerate one document's embedding.
+// MatchWaitlist queues a volunteer for waitlist matching once their new
+// embedding is stored.
+type EmbeddingJob struct {
+	Kind          string `json:"kind"`
+	ID            string `json:"id"`
+	MatchWaitlist bool   `json:"match_waitlist,omitempty"`
+}
+
+// EnqueueEmbedding queues an embedding job for a need, volunteer, or offer
+func EnqueueEmbedding(ctx context.Context, broker database.Broker, kind string, id primitive.ObjectID) error {
+	payload, err := json.Marshal(EmbeddingJob{Kind: kind, ID: id.Hex()})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueEmbeddings, string(payload))
+}
+
+// EnqueueVolunteerEmbedding queues an embedding job for a new or changed
+// volunteer profile, followed by matching it against the waitlisted needs
+func EnqueueVolunteerEmbedding(ctx context.Context, broker database.Broker, volunteerID primitive.ObjectID) error {
+	payload, err := json.Marshal(EmbeddingJob{Kind: EmbeddingKindVolunteer, ID: volunteerID.Hex(), MatchWaitlist: true})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueEmbeddings, string(payload))
+}
+
+// EmbeddingHandler regenerates the embedding named by an EmbeddingJob
+// payload. Documents deleted before the job ran are skipped.
+func EmbeddingHandler(matchingService *services.MatchingService, mongoClient *database.MongoClient, redisClient *database.RedisClient) Handler {
+	return func(ctx context.Context, payload string) error {
+		var job EmbeddingJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			return Permanent(fmt.Errorf("invalid embedding job %q: %w", payload, err))
+		}
+
+		err := RegenerateEmbedding(ctx, matchingService, mongoClient, job)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil || !job.MatchWaitlist {
+			return err
+		}
+
+		volunteerID, _ := primitive.ObjectIDFromHex(job.ID)
+		return EnqueueVolunteerMatching(ctx, redisClient, volunteerID)
+	}
+}
+
+// RegenerateEmbedding loads the need, volunteer, or offer named by job and updates its embedding
+func RegenerateEmbedding(ctx context.Context, matchingService *services.MatchingService, mongoClient *database.MongoClient, job EmbeddingJob) error {
+	id, err := primitive.ObjectIDFromHex(job.ID)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid embedding job ID %q", job.ID))
+	}
+
+	switch job.Kind {
+	case EmbeddingKindNeed:
+		var need models.Need
+		if err := mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": id}).Decode(&need); err != nil {
+			return fmt.Errorf("failed to load need %s: %w", job.ID, err)
+		}
+		return matchingService.UpdateNeedEmbedding(ctx, &need)
+	case EmbeddingKindVolunteer:
+		var volunteer models.Volunteer
+		if err := mongoClient.GetCollection("volunteers").FindOne(ctx, bson.M{"_id": id}).Decode(&volunteer); err != nil {
+			return fmt.Errorf("failed to load volunteer %s: %w", job.ID, err)
+		}
+		return matchingService.UpdateVolunteerEmbedding(ctx, &volunteer)
+	case EmbeddingKindOffer:
+		var offer models.Offer
+		if err := mongoClient.GetCollection("offers").FindOne(ctx, bson.M{"_id": id}).Decode(&offer); err != nil {
+			return fmt.Errorf("failed to load offer %s: %w", job.ID, err)
+		}
+		return matchingService.UpdateOfferEmbedding(ctx, &offer)
+	default:
+		return Permanent(fmt.Errorf("unknown embedding job kind %q", job.Kind))
+	}
+} 
\ No newline at end of file
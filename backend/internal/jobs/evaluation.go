@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// matchDecisionPurgeInterval is how often workers delete match sets past
+// their retention
+const matchDecisionPurgeInterval = time.Hour
+
+// PurgeMatchDecisions returns a job that periodically deletes recorded match
+// sets past their retention
+func PurgeMatchDecisions(decisions *services.MatchDecisionLog) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(matchDecisionPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := decisions.PurgeExpired(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Match decision purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d expired match decisions", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// eventReminderInterval is how often workers look for signups due a reminder
+const eventReminderInterval = 5 * time.Minute
+
+// EventReminders returns a job that reminds volunteers of event shifts
+// starting within lead
+func EventReminders(eventService *services.EventService, redisClient *database.RedisClient, lead time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(eventReminderInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := sendEventReminders(ctx, eventService, redisClient, lead); err != nil && ctx.Err() == nil {
+				log.Printf("Event reminders failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// sendEventReminders reminds every signup that is due
+func sendEventReminders(ctx context.Context, eventService *services.EventService, redisClient *database.RedisClient, lead time.Duration) error {
+	for {
+		signup, event, err := eventService.ClaimDueReminder(ctx, lead)
+		if err != nil || signup == nil {
+			return err
+		}
+		if event == nil || event.Status != models.EventScheduled {
+			continue
+		}
+
+		payload := map[string]interface{}{
+			"event_id":  event.ID.Hex(),
+			"title":     event.Title,
+			"starts_at": signup.StartsAt,
+			"location":  event.Location,
+		}
+		if signup.ShiftID != nil {
+			payload["shift_id"] = signup.ShiftID.Hex()
+		}
+
+		// Flag signups the volunteer has since marked themselves unavailable for
+		unavailable, err := eventService.Unavailable(ctx, signup.UserID, signup.StartsAt)
+		if err != nil {
+			return err
+		}
+		if unavailable {
+			payload["unavailable"] = true
+		}
+		message := models.WebSocketMessage{Type: "event_reminder", Payload: payload}
+		if err := EnqueueNotification(ctx, redisClient, []string{signup.UserID.Hex()}, message); err != nil {
+			return err
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// needExpiryInterval is how often workers look for needs past their expiry
+const needExpiryInterval = 15 * time.Minute
+
+// NeedExpiry returns a job that closes needs nobody took on before they
+// expired and tells their creators
+func NeedExpiry(expiryService *services.ExpiryService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(needExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			expired, err := expiryService.ExpireDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Need expiry failed: %v", err)
+			}
+			if expired > 0 {
+				log.Printf("Expired %d needs", expired)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
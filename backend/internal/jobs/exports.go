@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// QueueExports is the queue of data exports awaiting assembly
+const QueueExports = "exports"
+
+// exportPurgeInterval is how often workers delete expired export archives
+const exportPurgeInterval = time.Hour
+
+// ExportJob asks the worker to assemble one user's data export
+type ExportJob struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+// EnqueueExport queues a data export for assembly
+func EnqueueExport(ctx context.Context, broker database.Broker, export *models.DataExport) error {
+	payload, err := json.Marshal(ExportJob{ID: export.ID.Hex(), UserID: export.UserID.Hex()})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueExports, string(payload))
+}
+
+// ExportHandler assembles the export named by an ExportJob payload and tells
+// the user it is ready to download
+func ExportHandler(exportService *services.ExportService, redisClient *database.RedisClient) Handler {
+	return func(ctx context.Context, payload string) error {
+		var job ExportJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			return Permanent(fmt.Errorf("invalid export job %q: %w", payload, err))
+		}
+		id, err := primitive.ObjectIDFromHex(job.ID)
+		if err != nil {
+			return Permanent(fmt.Errorf("invalid export ID %q: %w", job.ID, err))
+		}
+
+		if err := exportService.Build(ctx, id); err != nil {
+			return fmt.Errorf("export %s: %w", job.ID, err)
+		}
+		return EnqueueNotification(ctx, redisClient, []string{job.UserID}, models.WebSocketMessage{
+			Type:    "export_ready",
+			Payload: map[string]interface{}{"export_id": job.ID},
+		})
+	}
+}
+
+// PurgeExports returns a job that periodically deletes export archives past
+// their retention
+func PurgeExports(exportService *services.ExportService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(exportPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := exportService.PurgeExpired(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Export purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d expired data exports", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
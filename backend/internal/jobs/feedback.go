@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// feedbackRevealInterval is how often workers look for feedback whose
+// reveal window has passed
+const feedbackRevealInterval = time.Hour
+
+// FeedbackReveal returns a job that reveals feedback the other participant
+// did not answer in time, updating the recipients' reputations
+func FeedbackReveal(feedbackService *services.FeedbackService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(feedbackRevealInterval)
+		defer ticker.Stop()
+
+		for {
+			revealed, err := feedbackService.RevealDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Feedback reveal failed: %v", err)
+			}
+			if revealed > 0 {
+				log.Printf("Revealed %d unanswered feedback", revealed)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
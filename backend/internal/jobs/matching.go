@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/settings"
+)
+
+// QueueMatching is the queue of new needs, and of volunteers new or changed
+// since waitlisted needs were posted, awaiting matching
+const QueueMatching = "matching"
+
+// MatchingJob asks the worker to match a need and notify the volunteers
+// found, or to match a volunteer against the waitlisted needs
+type MatchingJob struct {
+	NeedID      string `json:"need_id,omitempty"`
+	VolunteerID string `json:"volunteer_id,omitempty"`
+}
+
+// EnqueueMatching queues a need for matching
+func EnqueueMatching(ctx context.Context, broker database.Broker, needID primitive.ObjectID) error {
+	payload, err := json.Marshal(MatchingJob{NeedID: needID.Hex()})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueMatching, string(payload))
+}
+
+// EnqueueVolunteerMatching queues a volunteer for matching against the
+// waitlisted needs
+func EnqueueVolunteerMatching(ctx context.Context, broker database.Broker, volunteerID primitive.ObjectID) error {
+	payload, err := json.Marshal(MatchingJob{VolunteerID: volunteerID.Hex()})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueMatching, string(payload))
+}
+
+// MatchingHandler embeds a need if necessary, finds matching volunteers, and
+// queues a new_need notification to them and emails them, recording when the
+// need was first matched. Needs nobody matches are waitlisted, needs deleted
+// before the job ran are skipped, and volunteer jobs are matched against the
+// waitlist.
+func MatchingHandler(matchingService *services.MatchingService, analyticsService *services.AnalyticsService, slaService *services.SLAService, waitlistService *services.WaitlistService, notificationService *services.NotificationService, mongoClient *database.MongoClient, redisClient *database.RedisClient, settingsStore *settings.Store) Handler {
+	return func(ctx context.Context, payload string) error {
+		var job MatchingJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			return Permanent(fmt.Errorf("invalid matching job %q: %w", payload, err))
+		}
+
+		if job.VolunteerID != "" {
+			volunteerID, err := primitive.ObjectIDFromHex(job.VolunteerID)
+			if err != nil {
+				return Permanent(fmt.Errorf("invalid matching job volunteer ID %q", job.VolunteerID))
+			}
+			_, err = waitlistService.MatchVolunteer(ctx, volunteerID)
+			return err
+		}
+
+		needID, err := primitive.ObjectIDFromHex(job.NeedID)
+		if err != nil {
+			return Permanent(fmt.Errorf("invalid matching job need ID %q", job.NeedID))
+		}
+
+		var need models.Need
+		err = mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": needID}).Decode(&need)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load need %s: %w", job.NeedID, err)
+		}
+		if need.Hidden || need.HeldForReview {
+			return nil
+		}
+
+		if len(need.Embedding) == 0 {
+			if err := matchingService.UpdateNeedEmbedding(ctx, &need); err != nil {
+				return err
+			}
+		}
+
+		matches, err := matchingService.FindMatchesForNeed(ctx, &need, settingsStore.Get(ctx).NotificationFanout)
+		if err != nil {
+			return err
+		}
+
+		recipients, err := matchingService.Recipients(ctx, matches)
+		if err != nil {
+			return err
+		}
+		analyticsService.MatchesShown(ctx, &need, matches, services.MatchSurfaceNotification)
+		if len(matches) == 0 {
+			return waitlistService.Add(ctx, &need)
+		}
+		slaService.Record(ctx, need.ID, models.MilestoneFirstMatch, time.Now())
+
+		// Each volunteer is told the distance in their own units
+		for _, recipient := range recipients {
+			if err := EnqueueNotification(ctx, redisClient, []string{recipient.UserID}, services.NewNeedMessage(need, recipient)); err != nil {
+				return err
+			}
+		}
+		notificationService.NewMatches(ctx, need, recipients)
+		matchingService.MarkNotified(ctx, need.ID, recipients)
+		return nil
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// QueueNotifications is the queue of notifications awaiting delivery
+const QueueNotifications = "notifications"
+
+// notificationsChannel fans notifications out to every API instance, since
+// only the instance holding a user's WebSocket can deliver to it
+const notificationsChannel = "notifications:websocket"
+
+// notificationPurgeInterval is how often workers delete inbox notifications
+// past their retention
+const notificationPurgeInterval = time.Hour
+
+// NotificationJob delivers a message to a set of users, or to the clients
+// following any of a set of topics. ID identifies the job across retries.
+type NotificationJob struct {
+	ID      string                  `json:"id,omitempty"`
+	UserIDs []string                `json:"user_ids"`
+	Topics  []string                `json:"topics,omitempty"`
+	Message models.WebSocketMessage `json:"message"`
+}
+
+// EnqueueNotification queues a message for delivery to the given users
+func EnqueueNotification(ctx context.Context, broker database.Broker, userIDs []string, message models.WebSocketMessage) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(NotificationJob{ID: primitive.NewObjectID().Hex(), UserIDs: userIDs, Message: message})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueNotifications, string(payload))
+}
+
+// EnqueueTopicNotification queues a message for delivery to the clients
+// following any of topics. Topic messages are never pushed.
+func EnqueueTopicNotification(ctx context.Context, broker database.Broker, topics []string, message models.WebSocketMessage) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(NotificationJob{Topics: topics, Message: message})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueNotifications, string(payload))
+}
+
+// NotificationHandler keeps queued notifications in their users' inboxes,
+// publishes them to the API instances, and queues a push to whichever users
+// turn out not to be connected to any
+func NotificationHandler(broker database.Broker, pushService *services.PushService, inboxService *services.InboxService) Handler {
+	return func(ctx context.Context, payload string) error {
+		var job NotificationJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			return Permanent(fmt.Errorf("invalid notification job %q: %w", payload, err))
+		}
+		// A retry stores nothing already stored under the job's ID, so a
+		// failed publish can be retried without duplicating inbox entries.
+		// Jobs queued before IDs were added store on every attempt.
+		if err := inboxService.Store(ctx, job.ID, job.UserIDs, job.Message); err != nil {
+			return err
+		}
+		if err := broker.Publish(ctx, notificationsChannel, payload); err != nil {
+			return err
+		}
+
+		// Retrying would publish the notification again, so a failed push is only logged
+		if pushService.Enabled() {
+			if err := EnqueuePush(ctx, broker, job.UserIDs, job.Message, true); err != nil {
+				log.Printf("Failed to queue push for %s notification: %v", job.Message.Type, err)
+			}
+		}
+		return nil
+	}
+}
+
+// DeliverNotifications relays published notifications to this instance's
+// WebSocket clients until ctx is cancelled. Users connected elsewhere are
+// left to their own instance, and offline users to the push queue.
+func DeliverNotifications(redisClient *database.RedisClient, websocketService *services.WebSocketService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		subscription := redisClient.Subscribe(ctx, notificationsChannel)
+		defer subscription.Close()
+
+		messages := subscription.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var job NotificationJob
+				if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+					log.Printf("Dropping invalid notification: %v", err)
+					continue
+				}
+				if len(job.Topics) > 0 {
+					websocketService.SendToTopics(job.Topics, job.Message)
+					continue
+				}
+				websocketService.SendToConnected(job.UserIDs, job.Message)
+			}
+		}
+	}
+}
+
+// PurgeNotifications returns a job that periodically deletes inbox
+// notifications past their retention
+func PurgeNotifications(inboxService *services.InboxService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(notificationPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := inboxService.PurgeExpired(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Notification purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d expired notifications", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
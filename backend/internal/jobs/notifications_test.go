@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/fakes"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+func TestNotificationRetryStoresOnce(t *testing.T) {
+	ctx := context.Background()
+	broker := &fakes.Broker{}
+	store := &fakes.NotificationStore{}
+	handle := NotificationHandler(broker, services.NewPushService(nil, nil, nil, nil), services.NewInboxService(store, 0))
+
+	userIDs := []string{primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()}
+	if err := EnqueueNotification(ctx, broker, userIDs, models.WebSocketMessage{Type: "new_match"}); err != nil {
+		t.Fatal(err)
+	}
+	queued := broker.Jobs(QueueNotifications)
+	if len(queued) != 1 {
+		t.Fatalf("got %d queued jobs, want 1", len(queued))
+	}
+	var job NotificationJob
+	if err := json.Unmarshal([]byte(queued[0]), &job); err != nil || job.ID == "" {
+		t.Fatalf("got job %q (%v), want one with an ID", queued[0], err)
+	}
+
+	broker.Err = errors.New("publish failed")
+	if err := handle(ctx, queued[0]); err == nil {
+		t.Fatal("got no error when publishing failed")
+	}
+	broker.Err = nil
+	if err := handle(ctx, queued[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored := store.All(); len(stored) != len(userIDs) {
+		t.Fatalf("got %d inbox notifications after a retry, want one per user", len(stored))
+	}
+	if published := broker.Published(); len(published) != 1 {
+		t.Fatalf("got %d published messages, want 1", len(published))
+	}
+} 
\ No newline at end of file
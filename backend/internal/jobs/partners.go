@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/webhooks"
+)
+
+// QueuePartnerEvents is the queue of events awaiting delivery to partners
+const QueuePartnerEvents = "partner_events"
+
+// QueueReferralEvents is the queue of referral status events awaiting
+// delivery to the partner organization that made the referral
+const QueueReferralEvents = "referral_events"
+
+// referralStatusInterval is how often workers look for referrals whose
+// status has changed
+const referralStatusInterval = time.Minute
+
+// PartnerEventAttempts is how many times delivery is tried before the
+// event is dead-lettered. Partners are given longer than other jobs to
+// recover from an outage.
+const PartnerEventAttempts = 5
+
+// EnqueuePartnerEvent queues an event for delivery to partner endpoints
+func EnqueuePartnerEvent(ctx context.Context, broker database.Broker, event webhooks.OutboundEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueuePartnerEvents, string(payload))
+}
+
+// PartnerEventHandler delivers queued events. Every endpoint is retried when
+// any fails, relying on partners to deduplicate by event ID.
+func PartnerEventHandler(sender *webhooks.Sender) Handler {
+	return func(ctx context.Context, payload string) error {
+		var event webhooks.OutboundEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return Permanent(fmt.Errorf("invalid partner event %q: %w", payload, err))
+		}
+		if !sender.Enabled() {
+			return nil
+		}
+		return sender.Send(ctx, event)
+	}
+}
+
+// ReferralEventJob is a referral status event for one partner
+// organization's webhook
+type ReferralEventJob struct {
+	OrganizationID primitive.ObjectID     `json:"organization_id"`
+	Event          webhooks.OutboundEvent `json:"event"`
+}
+
+// EnqueueReferralEvent queues a referral status event for delivery
+func EnqueueReferralEvent(ctx context.Context, broker database.Broker, job ReferralEventJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueueReferralEvents, string(payload))
+}
+
+// ReferralEventHandler delivers queued referral events to the
+// organization's webhook, signed with its own secret. Events for organizations without a webhook URL, or since
+// revoked, are dropped.
+func ReferralEventHandler(partnerService *services.PartnerService) Handler {
+	return func(ctx context.Context, payload string) error {
+		var job ReferralEventJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			return Permanent(fmt.Errorf("invalid referral event %q: %w", payload, err))
+		}
+
+		org, err := partnerService.Organization(ctx, job.OrganizationID)
+		if errors.Is(err, services.ErrPartnerNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if org.WebhookURL == "" {
+			return nil
+		}
+		return webhooks.NewSender([]string{org.WebhookURL}, org.WebhookSecret).Send(ctx, job.Event)
+	}
+}
+
+// ReferralStatus returns a job that queues a referral.status_changed event
+// for each referral whose status has changed, once per change
+func ReferralStatus(partnerService *services.PartnerService, redisClient *database.RedisClient) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(referralStatusInterval)
+		defer ticker.Stop()
+
+		for {
+			reports, err := partnerService.DueReports(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Referral status check failed: %v", err)
+			}
+			for _, report := range reports {
+				claimed, err := partnerService.ClaimReport(ctx, report)
+				if err != nil {
+					log.Printf("Failed to claim referral report for need %s: %v", report.Need.ID.Hex(), err)
+					continue
+				}
+				if !claimed {
+					continue
+				}
+
+				job := ReferralEventJob{
+					OrganizationID: report.Need.Partner.OrganizationID,
+					Event: webhooks.OutboundEvent{
+						ID:        primitive.NewObjectID().Hex(),
+						Type:      "referral.status_changed",
+						CreatedAt: time.Now(),
+						Data: map[string]interface{}{
+							"referral":        report.State,
+							"previous_status": report.Need.Partner.ReportedStatus,
+						},
+					},
+				}
+				if err := EnqueueReferralEvent(ctx, redisClient, job); err != nil {
+					log.Printf("Failed to queue referral event for need %s: %v", report.Need.ID.Hex(), err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
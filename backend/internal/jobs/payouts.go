@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// payoutCheckInterval is how often workers look for completed needs owed a payout
+const payoutCheckInterval = 10 * time.Minute
+
+// Payouts returns a job that records a pending payout for each completed
+// need with money raised, for admins to send to the volunteer
+func Payouts(contributionService *services.ContributionService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(payoutCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			created, err := contributionService.CreateDuePayouts(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Payout tracking failed: %v", err)
+			}
+			if created > 0 {
+				log.Printf("Recorded %d pending payouts", created)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// pointsInterval is how often workers score newly completed tasks
+const pointsInterval = 5 * time.Minute
+
+// VolunteerPoints returns a job that awards points and streaks for
+// completed tasks, however they were completed
+func VolunteerPoints(pointsService *services.PointsService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(pointsInterval)
+		defer ticker.Stop()
+
+		for {
+			scored, err := pointsService.AwardDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Volunteer points failed: %v", err)
+			}
+			if scored > 0 {
+				log.Printf("Scored %d completed tasks", scored)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// QueuePush is the queue of push notifications awaiting sending
+const QueuePush = "push"
+
+// PushJob pushes a message to a set of users' devices. OnlyOffline skips
+// the users connected over WebSocket when the job runs, who got the
+// message there.
+type PushJob struct {
+	UserIDs     []string                `json:"user_ids"`
+	Message     models.WebSocketMessage `json:"message"`
+	OnlyOffline bool                    `json:"only_offline,omitempty"`
+}
+
+// EnqueuePush queues a message for pushing to the given users
+func EnqueuePush(ctx context.Context, broker database.Broker, userIDs []string, message models.WebSocketMessage, onlyOffline bool) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(PushJob{UserIDs: userIDs, Message: message, OnlyOffline: onlyOffline})
+	if err != nil {
+		return err
+	}
+	return broker.EnqueueJob(ctx, QueuePush, string(payload))
+}
+
+// PushHandler sends queued push notifications
+func PushHandler(pushService *services.PushService) Handler {
+	return func(ctx context.Context, payload string) error {
+		var job PushJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			return Permanent(fmt.Errorf("invalid push job %q: %w", payload, err))
+		}
+		if job.OnlyOffline {
+			return pushService.SendOffline(ctx, job.UserIDs, job.Message)
+		}
+		return pushService.Send(ctx, job.UserIDs, job.Message)
+	}
+} 
\ No newline at end of file
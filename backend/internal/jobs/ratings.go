@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// ratingsInterval is how often workers drain the ratings outbox
+const ratingsInterval = 15 * time.Second
+
+// Ratings returns a job that recomputes the reputations, ratings, and task
+// counts of users whose feedback or tasks changed
+func Ratings(ratingService *services.RatingService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(ratingsInterval)
+		defer ticker.Stop()
+
+		for {
+			recomputed, err := ratingService.ProcessPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Rating recompute failed: %v", err)
+			}
+			if recomputed > 0 {
+				log.Printf("Recomputed ratings for %d users", recomputed)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
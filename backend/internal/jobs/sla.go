@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// slaInterval is how often workers look for urgent needs past a target
+const slaInterval = time.Minute
+
+// NeedSLAs returns a job that escalates urgent needs that miss their
+// match, accept, or completion targets
+func NeedSLAs(slaService *services.SLAService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(slaInterval)
+		defer ticker.Stop()
+
+		for {
+			breaches, err := slaService.CheckBreaches(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Need SLA check failed: %v", err)
+			}
+			if breaches > 0 {
+				log.Printf("Escalated %d need SLA breaches", breaches)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
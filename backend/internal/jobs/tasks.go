@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// taskConfirmationInterval is how often workers look for tasks whose
+// confirmation window has passed
+const taskConfirmationInterval = 15 * time.Minute
+
+// TaskConfirmations returns a job that completes tasks one participant
+// marked completed when the other did not confirm in time
+func TaskConfirmations(taskService *services.TaskService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(taskConfirmationInterval)
+		defer ticker.Stop()
+
+		for {
+			completed, err := taskService.ConfirmDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Task confirmation failed: %v", err)
+			}
+			if completed > 0 {
+				log.Printf("Completed %d unconfirmed tasks", completed)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// trustInterval is how often workers look for stale trust scores
+const trustInterval = time.Hour
+
+// TrustScores returns a job that recomputes stale trust scores, picking up
+// account age, finished tasks, ratings, and moderation outcomes
+func TrustScores(trustService *services.TrustService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(trustInterval)
+		defer ticker.Stop()
+
+		for {
+			refreshed, err := trustService.RefreshStale(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Trust score refresh failed: %v", err)
+			}
+			if refreshed > 0 {
+				log.Printf("Refreshed %d trust scores", refreshed)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"neighborenexus/internal/services"
+)
+
+// uploadPurgeInterval is how often workers delete uploads nothing uses
+const uploadPurgeInterval = time.Hour
+
+// UploadPurge returns a job that deletes uploads never attached, and images
+// of deleted needs, from the database and object storage
+func UploadPurge(uploadService *services.UploadService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(uploadPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			deleted, err := uploadService.PurgeAbandoned(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Upload purge failed: %v", err)
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d unused uploads", deleted)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+} 
\ No newline at end of file
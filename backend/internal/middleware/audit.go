@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/services"
+)
+
+// AuditClient records the client address and user agent on each request's
+// context, so actions services audit themselves are attributed to the
+// client that asked for them
+func AuditClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := services.WithAuditClient(c.Request.Context(), c.ClientIP(), c.Request.UserAgent())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+} 
\ No newline at end of file
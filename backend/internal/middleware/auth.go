@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"neighborenexus/internal/services"
@@ -30,7 +31,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Validate token
-		userID, err := authService.ValidateToken(token)
+		userID, sessionID, err := authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
@@ -39,6 +40,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		// Set user ID in context
 		c.Set("user_id", userID)
+		c.Set("session_id", sessionID)
 
 		// Get user details
 		user, err := authService.GetUserByID(c.Request.Context(), userID)
@@ -48,6 +50,13 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		// Suspended users keep their tokens but cannot use them
+		if user.AccountSuspended(time.Now()) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+			c.Abort()
+			return
+		}
+
 		// Set user in context
 		c.Set("user", user)
 
@@ -75,7 +84,7 @@ func OptionalAuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Validate token
-		userID, err := authService.ValidateToken(token)
+		userID, sessionID, err := authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			c.Next()
 			return
@@ -83,6 +92,7 @@ func OptionalAuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		// Set user ID in context
 		c.Set("user_id", userID)
+		c.Set("session_id", sessionID)
 
 		// Get user details
 		user, err := authService.GetUserByID(c.Request.Context(), userID)
@@ -106,6 +116,15 @@ func GetUserID(c *gin.Context) string {
 	return ""
 }
 
+// GetSessionID gets the ID of the session the request's access token was
+// issued to, or "" for tokens issued before sessions were tracked
+func GetSessionID(c *gin.Context) string {
+	if sessionID, exists := c.Get("session_id"); exists {
+		return sessionID.(string)
+	}
+	return ""
+}
+
 // GetUser gets the user from the context
 func GetUser(c *gin.Context) interface{} {
 	if user, exists := c.Get("user"); exists {
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit rejects request bodies larger than maxBytes, or uploadBytes for
+// multipart file uploads such as voice notes. Requests declaring a larger
+// Content-Length are refused up front with 413; bodies without a length are
+// cut off while reading, which surfaces as a bind error in the handler.
+func BodyLimit(maxBytes, uploadBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := maxBytes
+		if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+			limit = uploadBytes
+		}
+		if c.Request.ContentLength > limit {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Request body too large (max %d bytes)", limit),
+			})
+			c.Abort()
+			return
+		}
+
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+
+		c.Next()
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Supported content encodings, in order of preference
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+var gzipPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	},
+}
+
+var brotliPool = sync.Pool{
+	New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+	},
+}
+
+// compressWriter compresses the response body with the negotiated encoding
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	writer   io.WriteCloser
+}
+
+// Write lazily starts the compressor so empty responses (204, 304) stay empty
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.writer == nil {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+
+		switch w.encoding {
+		case encodingBrotli:
+			bw := brotliPool.Get().(*brotli.Writer)
+			bw.Reset(w.ResponseWriter)
+			w.writer = bw
+		default:
+			gw := gzipPool.Get().(*gzip.Writer)
+			gw.Reset(w.ResponseWriter)
+			w.writer = gw
+		}
+	}
+	return w.writer.Write(data)
+}
+
+// WriteString writes a string through the compressor
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeader drops any precomputed Content-Length since the body will change size
+func (w *compressWriter) WriteHeader(code int) {
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush pushes what the compressor has buffered to the client, so streamed
+// responses such as exports arrive as they are written
+func (w *compressWriter) Flush() {
+	switch writer := w.writer.(type) {
+	case *brotli.Writer:
+		writer.Flush()
+	case *gzip.Writer:
+		writer.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close flushes the compressor and returns it to its pool
+func (w *compressWriter) close() {
+	if w.writer == nil {
+		return
+	}
+	w.writer.Close()
+
+	switch writer := w.writer.(type) {
+	case *brotli.Writer:
+		writer.Reset(io.Discard)
+		brotliPool.Put(writer)
+	case *gzip.Writer:
+		writer.Reset(io.Discard)
+		gzipPool.Put(writer)
+	}
+	w.writer = nil
+}
+
+// Compression compresses responses with brotli or gzip based on Accept-Encoding
+func Compression() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead || isWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+		}
+		c.Writer = writer
+		defer writer.close()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks the preferred encoding the client accepts
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" {
+			continue
+		}
+
+		// Honor explicit opt-outs such as "gzip;q=0"
+		disabled := false
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == "q=0" || param == "q=0.0" || param == "q=0.00" || param == "q=0.000" {
+				disabled = true
+			}
+		}
+		accepted[name] = !disabled
+	}
+
+	switch {
+	case accepted[encodingBrotli]:
+		return encodingBrotli
+	case accepted[encodingGzip]:
+		return encodingGzip
+	default:
+		return ""
+	}
+}
+
+// isWebSocketUpgrade reports whether the request is a WebSocket handshake
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+} 
\ No newline at end of file
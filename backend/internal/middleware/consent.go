@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// RequireConsent rejects requests from users who have not accepted the
+// current terms and privacy policy, listing what they still need to accept.
+// It must run after AuthMiddleware, which loads the user into the context.
+func RequireConsent(consentService *services.ConsentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetUser(c).(*models.User)
+		if !ok || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User authentication required"})
+			c.Abort()
+			return
+		}
+
+		if outstanding := consentService.Outstanding(c.Request.Context(), user); len(outstanding) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Updated policies must be accepted to continue",
+				"details": gin.H{"consent_required": outstanding},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter captures the response body so it can be hashed before sending
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write buffers the response body
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString buffers the response body
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ETag computes an ETag for successful GET responses and answers
+// If-None-Match requests with 304 Not Modified when the body is unchanged
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{
+			ResponseWriter: original,
+			body:           &bytes.Buffer{},
+		}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+
+		if writer.Status() != http.StatusOK {
+			original.Write(writer.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(writer.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		original.Header().Set("ETag", etag)
+		original.Header().Set("Cache-Control", "private, no-cache")
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			original.Header().Del("Content-Type")
+			original.Header().Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+
+		original.Write(writer.body.Bytes())
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches the given ETag,
+// using weak comparison since compression may change the representation
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == target {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
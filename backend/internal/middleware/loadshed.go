@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadShed caps the requests this instance handles at once. Up to maxInFlight
+// run concurrently; up to maxQueued more wait as long as queueTimeout for a
+// slot, and anything beyond that is refused with 503 and Retry-After so
+// traffic spikes back off instead of piling onto Mongo and OpenAI. Zero
+// maxInFlight disables shedding. WebSocket upgrades are not counted, since
+// they hold their connection for the life of the session.
+func LoadShed(maxInFlight, maxQueued int, queueTimeout time.Duration) gin.HandlerFunc {
+	if maxInFlight <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	slots := make(chan struct{}, maxInFlight)
+	var queued int64
+	retryAfter := strconv.Itoa(int(math.Max(1, math.Ceil(queueTimeout.Seconds()))))
+
+	shed := func(c *gin.Context) {
+		c.Header("Retry-After", retryAfter)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is busy, please retry shortly"})
+		c.Abort()
+	}
+
+	return func(c *gin.Context) {
+		if isWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			// Every slot is taken, so wait in the queue if it has room
+			if atomic.AddInt64(&queued, 1) > int64(maxQueued) {
+				atomic.AddInt64(&queued, -1)
+				shed(c)
+				return
+			}
+			timer := time.NewTimer(queueTimeout)
+			select {
+			case slots <- struct{}{}:
+				timer.Stop()
+				atomic.AddInt64(&queued, -1)
+			case <-timer.C:
+				atomic.AddInt64(&queued, -1)
+				shed(c)
+				return
+			case <-c.Request.Context().Done():
+				timer.Stop()
+				atomic.AddInt64(&queued, -1)
+				c.Abort()
+				return
+			}
+		}
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/i18n"
+)
+
+// localizedFields are the top-level JSON fields holding user-facing text:
+// v1 errors and messages and their details, or the whole v2 error object
+var localizedFields = []string{"error", "message", "details"}
+
+// Locale negotiates the response language from Accept-Language and
+// translates the error and message text of JSON responses into it.
+// Handlers can read the language with GetLocale to localize other text.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set("locale", lang)
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		if lang == i18n.Default || isWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &jsonWriter{ResponseWriter: original}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		if writer.body != nil {
+			original.Write(localizeBody(lang, writer.body.Bytes()))
+		}
+	}
+}
+
+// jsonWriter holds back JSON bodies so middleware can rewrite them,
+// streaming anything else, such as export downloads, straight through
+type jsonWriter struct {
+	gin.ResponseWriter
+	body    *bytes.Buffer
+	decided bool
+}
+
+// Write buffers JSON bodies and passes others through
+func (w *jsonWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			w.body = &bytes.Buffer{}
+		}
+	}
+	if w.body == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+// WriteString writes a string through Write
+func (w *jsonWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// GetLocale returns the language negotiated for the request
+func GetLocale(c *gin.Context) string {
+	if lang, exists := c.Get("locale"); exists {
+		return lang.(string)
+	}
+	return i18n.Default
+}
+
+// localizeBody translates the user-facing fields of a JSON object, returning
+// other bodies unchanged
+func localizeBody(lang string, body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+
+	changed := false
+	for _, key := range localizedFields {
+		if value, ok := fields[key]; ok {
+			if localized, ok := localizeValue(lang, value); ok {
+				fields[key] = localized
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return body
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+// localizeValue translates a string, or every string in an object such as
+// validation details or a v2 error, reporting whether anything changed
+func localizeValue(lang string, value json.RawMessage) (json.RawMessage, bool) {
+	if len(value) == 0 {
+		return value, false
+	}
+
+	switch value[0] {
+	case '"':
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return value, false
+		}
+		translated := i18n.T(lang, text)
+		if translated == text {
+			return value, false
+		}
+		data, err := json.Marshal(translated)
+		if err != nil {
+			return value, false
+		}
+		return data, true
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return value, false
+		}
+		changed := false
+		for key, field := range fields {
+			if localized, ok := localizeValue(lang, field); ok {
+				fields[key] = localized
+				changed = true
+			}
+		}
+		if !changed {
+			return value, false
+		}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return value, false
+		}
+		return data, true
+	}
+	return value, false
+} 
\ No newline at end of file
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+)
+
+// PartnerAuth validates a partner organization's API key, sent as a bearer
+// token, and sets the organization in the context
+func PartnerAuth(partnerService *services.PartnerService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			c.Abort()
+			return
+		}
+
+		org, err := partnerService.Authenticate(c.Request.Context(), strings.TrimPrefix(authHeader, "Bearer "))
+		if errors.Is(err, services.ErrInvalidPartnerKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or revoked API key"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			log.Printf("Partner authentication failed: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate"})
+			c.Abort()
+			return
+		}
+
+		c.Set("partner", org)
+		c.Next()
+	}
+}
+
+// GetPartner gets the authenticated partner organization from the context
+func GetPartner(c *gin.Context) *models.PartnerOrganization {
+	if org, exists := c.Get("partner"); exists {
+		return org.(*models.PartnerOrganization)
+	}
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/database"
+)
+
+// rateLimitWindow is the fixed window rate limits are counted over
+const rateLimitWindow = time.Minute
+
+// RateLimit allows each caller limit(ctx) requests per minute in the given
+// scope, keyed by authenticated user or partner organization, or else client
+// IP. The limit is read on every request so it can be tuned at runtime; zero
+// disables limiting. Redis errors fail open.
+func RateLimit(redisClient *database.RedisClient, scope string, limit func(ctx context.Context) int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		max := limit(c.Request.Context())
+		if max <= 0 {
+			c.Next()
+			return
+		}
+
+		identity := GetUserID(c)
+		if org := GetPartner(c); identity == "" && org != nil {
+			identity = "partner:" + org.ID.Hex()
+		}
+		if identity == "" {
+			identity = "ip:" + c.ClientIP()
+		}
+		seconds := int64(rateLimitWindow.Seconds())
+		now := time.Now().Unix()
+		window := now / seconds
+		key := "ratelimit:" + scope + ":" + identity + ":" + strconv.FormatInt(window, 10)
+
+		limited, err := redisClient.IsRateLimited(c.Request.Context(), key, max, rateLimitWindow)
+		if err != nil {
+			log.Printf("Rate limit check failed: %v", err)
+			c.Next()
+			return
+		}
+
+		if limited {
+			c.Header("Retry-After", strconv.FormatInt(seconds-now%seconds, 10))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+} 
\ No newline at end of file
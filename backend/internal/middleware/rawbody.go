@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RawBody reads the request body into the context before any binding so
+// signature checks can run over the exact bytes that were sent
+func RawBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			c.Set("raw_body", []byte{})
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Set("raw_body", body)
+		c.Next()
+	}
+}
+
+// GetRawBody gets the raw request body captured by RawBody
+func GetRawBody(c *gin.Context) []byte {
+	if body, exists := c.Get("raw_body"); exists {
+		return body.([]byte)
+	}
+	return nil
+} 
\ No newline at end of file
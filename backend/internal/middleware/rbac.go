@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/models"
+)
+
+// RequireRole ensures the authenticated user has one of the given roles.
+// It must run after AuthMiddleware, which loads the user into the context.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetUser(c).(*models.User)
+		if !ok || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User authentication required"})
+			c.Abort()
+			return
+		}
+
+		if !user.HasRole(roles...) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+} 
\ No newline at end of file
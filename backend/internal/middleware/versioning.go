@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersion records the API version serving the request and echoes it back
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", version)
+		c.Header("X-API-Version", version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion gets the API version from the context
+func GetAPIVersion(c *gin.Context) string {
+	if version, exists := c.Get("api_version"); exists {
+		return version.(string)
+	}
+	return ""
+}
+
+// Deprecation marks every response of a route group as deprecated, advertising
+// the sunset date and the successor version so clients can migrate in time
+func Deprecation(sunset, successor string) gin.HandlerFunc {
+	var sunsetHeader string
+	if sunset != "" {
+		sunsetDate, err := time.Parse("2006-01-02", sunset)
+		if err != nil {
+			sunsetDate, err = time.Parse(time.RFC3339, sunset)
+		}
+		if err != nil {
+			log.Printf("Warning: Invalid API sunset date %q: %v", sunset, err)
+		} else {
+			sunsetHeader = sunsetDate.UTC().Format(http.TimeFormat)
+		}
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		if sunsetHeader != "" {
+			c.Header("Sunset", sunsetHeader)
+		}
+		if successor != "" {
+			c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		}
+		c.Next()
+	}
+}
+
+// ResponseEnvelope rewrites handler responses into the v2 response shape:
+// successful responses become {"data": ..., "meta": {...}} and errors become
+// {"error": {"code": ..., "message": ..., "details": ...}}
+func ResponseEnvelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &jsonWriter{ResponseWriter: original}
+		c.Writer = writer
+
+		c.Next()
+
+		// Only JSON bodies are held back; downloads have already streamed
+		c.Writer = original
+		if writer.body == nil {
+			return
+		}
+
+		status := writer.Status()
+		body := writer.body.Bytes()
+
+		var envelope interface{}
+		if status >= http.StatusBadRequest {
+			envelope = errorEnvelope(status, body)
+		} else {
+			envelope = dataEnvelope(body)
+		}
+
+		data, err := json.Marshal(envelope)
+		if err != nil {
+			original.Write(body)
+			return
+		}
+		original.Write(data)
+	}
+}
+
+// errorEnvelope converts a v1 {"error": "...", "details": "..."} body into a structured error
+func errorEnvelope(status int, body []byte) gin.H {
+	var v1 struct {
+		Error   string          `json:"error"`
+		Details json.RawMessage `json:"details,omitempty"`
+	}
+	json.Unmarshal(body, &v1)
+
+	message := v1.Error
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	apiError := gin.H{
+		"code":    strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_")),
+		"message": message,
+	}
+	if len(v1.Details) > 0 {
+		apiError["details"] = v1.Details
+	}
+
+	return gin.H{"error": apiError}
+}
+
+// dataEnvelope moves the primary payload of a v1 body under "data" and any
+// remaining fields (counts, cursors, messages) under "meta"
+func dataEnvelope(body []byte) gin.H {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return gin.H{"data": json.RawMessage(body)}
+	}
+
+	// A single field is the resource itself, e.g. {"need": {...}}
+	if len(fields) == 1 {
+		for _, value := range fields {
+			return gin.H{"data": value}
+		}
+	}
+
+	// A list plus scalar fields is a collection, e.g. {"needs": [...], "next_cursor": "..."}
+	listKey := ""
+	for key, value := range fields {
+		if len(value) == 0 {
+			continue
+		}
+		switch value[0] {
+		case '[':
+			if listKey != "" {
+				return gin.H{"data": json.RawMessage(body)}
+			}
+			listKey = key
+		case '{':
+			return gin.H{"data": json.RawMessage(body)}
+		}
+	}
+
+	if listKey != "" {
+		meta := make(map[string]json.RawMessage, len(fields)-1)
+		for key, value := range fields {
+			if key != listKey {
+				meta[key] = value
+			}
+		}
+		return gin.H{"data": fields[listKey], "meta": meta}
+	}
+
+	return gin.H{"data": json.RawMessage(body)}
+} 
\ No newline at end of file
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// AnalyticsEventType names a product analytics event
+type AnalyticsEventType string
+
+// Analytics event types
+const (
+	AnalyticsNeedCreated   AnalyticsEventType = "need_created"
+	AnalyticsMatchShown    AnalyticsEventType = "match_shown"
+	AnalyticsMatchAccepted AnalyticsEventType = "match_accepted"
+	AnalyticsSLABreached   AnalyticsEventType = "need_sla_breached"
+)
+
+// AnalyticsEvent is a pseudonymized record of something that happened in the
+// product. IDs are replaced by keyed hashes, and events never carry free
+// text, contact details, or exact locations.
+type AnalyticsEvent struct {
+	ID         string                 `bson:"_id" json:"id"`
+	Type       AnalyticsEventType     `bson:"type" json:"type"`
+	Actor      string                 `bson:"actor,omitempty" json:"actor,omitempty"` // pseudonym of the user who caused the event
+	Properties map[string]interface{} `bson:"properties,omitempty" json:"properties,omitempty"`
+	OccurredAt time.Time              `bson:"occurred_at" json:"occurred_at"`
+} 
\ No newline at end of file
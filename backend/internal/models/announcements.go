@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnnouncementStatus is where an announcement is in its lifecycle
+type AnnouncementStatus string
+
+// Announcement statuses
+const (
+	AnnouncementScheduled AnnouncementStatus = "scheduled"
+	AnnouncementPublished AnnouncementStatus = "published"
+	AnnouncementCancelled AnnouncementStatus = "cancelled"
+)
+
+var announcementStatuses = []string{"scheduled", "published", "cancelled"}
+
+// Valid reports whether s is a known announcement status
+func (s AnnouncementStatus) Valid() bool { return contains(announcementStatuses, string(s)) }
+
+// Values lists the known announcement statuses
+func (s AnnouncementStatus) Values() []string { return announcementStatuses }
+
+// AudienceVolunteer targets users with a volunteer profile, alongside the user roles
+const AudienceVolunteer = "volunteer"
+
+// AnnouncementTarget selects who receives an announcement. Each non-empty
+// field must match; within a field any value matches. An empty target
+// reaches everyone.
+type AnnouncementTarget struct {
+	Regions    []string   `bson:"regions,omitempty" json:"regions,omitempty" binding:"max=100"`                               // H3 cells at Resolution
+	Resolution int        `bson:"resolution,omitempty" json:"resolution,omitempty" binding:"min=0,max=15"`                    // resolution of Regions
+	Roles      []string   `bson:"roles,omitempty" json:"roles,omitempty" binding:"dive,oneof=user moderator admin volunteer"` // user roles, or volunteer
+	Categories []Category `bson:"categories,omitempty" json:"categories,omitempty" binding:"dive,category"`                   // matched against volunteer skills and interests
+}
+
+// Announcement is a message from admins to a targeted audience
+type Announcement struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title       string             `bson:"title" json:"title"`
+	Body        string             `bson:"body" json:"body"`
+	Target      AnnouncementTarget `bson:"target" json:"target"`
+	Status      AnnouncementStatus `bson:"status" json:"status"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	PublishAt   time.Time          `bson:"publish_at" json:"publish_at"`
+	ExpiresAt   *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	PublishedAt *time.Time         `bson:"published_at,omitempty" json:"published_at,omitempty"`
+	Recipients  int                `bson:"recipients" json:"recipients"` // users notified when published
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CreateAnnouncementRequest schedules an announcement; without publish_at
+// it goes out on the next delivery run
+type CreateAnnouncementRequest struct {
+	Title     string             `json:"title" binding:"required,max=200"`
+	Body      string             `json:"body" binding:"required,max=5000"`
+	Target    AnnouncementTarget `json:"target"`
+	PublishAt *time.Time         `json:"publish_at,omitempty"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+} 
\ No newline at end of file
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audited actions
+const (
+	AuditLogin                 = "auth.login"
+	AuditLoginFailed           = "auth.login_failed"
+	AuditPasswordReset         = "auth.password_reset"
+	AuditProfileUpdated        = "user.profile_updated"
+	AuditRoleChanged           = "user.role_changed"
+	AuditUserErased            = "user.erased"
+	AuditUserSuspended         = "user.suspended"
+	AuditUserReinstated        = "user.reinstated"
+	AuditNeedDeleted           = "need.deleted"
+	AuditTaskStatusChanged     = "task.status_changed"
+	AuditSettingsUpdated       = "settings.updated"
+	AuditSettingsReset         = "settings.reset"
+	AuditModerationDecided     = "moderation.decided"
+	AuditAnnouncementCreated   = "announcement.created"
+	AuditAnnouncementCancelled = "announcement.cancelled"
+	AuditConsentAccepted       = "user.consent_accepted"
+	AuditPrivacyUpdated        = "user.privacy_updated"
+	AuditPolicyPublished       = "policy.published"
+	AuditContactDisclosed      = "user.contact_disclosed"
+	AuditSupervisionUpdated    = "user.supervision_updated"
+	AuditOrganizerUpdated      = "user.organizer_updated"
+	AuditIdentityUpdated       = "user.identity_updated"
+	AuditGroupDeleted          = "group.deleted"
+	AuditGroupRoleChanged      = "group.role_changed"
+	AuditGroupMemberRemoved    = "group.member_removed"
+	AuditEventCancelled        = "event.cancelled"
+	AuditPayoutPaid            = "payout.paid"
+	AuditEmergencyDeclared     = "emergency.declared"
+	AuditEmergencyEnded        = "emergency.ended"
+	AuditPostDeleted           = "post.deleted"
+	AuditPartnerCreated        = "partner.created"
+	AuditPartnerUpdated        = "partner.updated"
+	AuditPartnerKeyRotated     = "partner.key_rotated"
+	AuditPartnerRevoked        = "partner.revoked"
+	AuditCategoryCreated       = "category.created"
+	AuditCategoryUpdated       = "category.updated"
+	AuditCategoryDeleted       = "category.deleted"
+)
+
+// Audit target types
+const (
+	AuditTargetUser         = "user"
+	AuditTargetNeed         = "need"
+	AuditTargetTask         = "task"
+	AuditTargetSettings     = "settings"
+	AuditTargetModeration   = "moderation_item"
+	AuditTargetAnnouncement = "announcement"
+	AuditTargetPolicy       = "policy"
+	AuditTargetGroup        = "group"
+	AuditTargetEvent        = "event"
+	AuditTargetPayout       = "payout"
+	AuditTargetEmergency    = "emergency"
+	AuditTargetPost         = "post"
+	AuditTargetPartner      = "partner"
+	AuditTargetCategory     = "category"
+)
+
+// AuditEntry records who did what to which document, for investigating
+// disputes and suspected account compromise. Entries are never updated.
+// Changes carry snapshots of the fields they touched, as they were before
+// and after; deletions have only a before snapshot.
+type AuditEntry struct {
+	ID         primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	ActorID    *primitive.ObjectID    `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // empty for anonymous actions such as failed logins
+	Action     string                 `bson:"action" json:"action"`
+	TargetType string                 `bson:"target_type,omitempty" json:"target_type,omitempty"`
+	TargetID   *primitive.ObjectID    `bson:"target_id,omitempty" json:"target_id,omitempty"`
+	IP         string                 `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string                 `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Details    map[string]interface{} `bson:"details,omitempty" json:"details,omitempty"`
+	Before     map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After      map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`
+	CreatedAt  time.Time              `bson:"created_at" json:"created_at"`
+} 
\ No newline at end of file
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Layouts of availability dates and times of day
+const (
+	DateLayout      = "2006-01-02"
+	TimeOfDayLayout = "15:04"
+)
+
+// exceptionHorizon bounds how many days availability is checked across
+const exceptionHorizon = 14
+
+// AvailabilityException overrides a volunteer's weekly availability on one
+// date, such as "unavailable Dec 24" or "extra availability this Saturday".
+// Without times it covers the whole day.
+type AvailabilityException struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Date      string             `bson:"date" json:"date"`                                 // "2026-12-24", in the volunteer's time zone
+	Available bool               `bson:"available" json:"available"`                       // true adds availability, false takes it away
+	StartTime string             `bson:"start_time,omitempty" json:"start_time,omitempty"` // "09:00"
+	EndTime   string             `bson:"end_time,omitempty" json:"end_time,omitempty"`     // "17:00"
+	Note      string             `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+// AvailabilityExceptionRequest adds or replaces an availability exception.
+// Times are given together, with the end after the start.
+type AvailabilityExceptionRequest struct {
+	Date      string `json:"date" binding:"required,datetime=2006-01-02"`
+	Available *bool  `json:"available" binding:"required"`
+	StartTime string `json:"start_time,omitempty" binding:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time,omitempty" binding:"required_with=StartTime,omitempty,datetime=15:04"`
+	Note      string `json:"note,omitempty" binding:"max=200"`
+}
+
+// TimeZone returns the time zone the volunteer's availability is in,
+// falling back to UTC
+func (v *Volunteer) TimeZone() *time.Location {
+	if v.Timezone != "" {
+		if loc, err := time.LoadLocation(v.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// UnavailableAt reports whether an exception takes the volunteer's
+// availability away at t
+func (v *Volunteer) UnavailableAt(t time.Time) bool {
+	t = t.In(v.TimeZone())
+	date := t.Format(DateLayout)
+	minute := t.Hour()*60 + t.Minute()
+	for _, exception := range v.Exceptions {
+		if exception.Date != date || exception.Available {
+			continue
+		}
+		if span := exceptionSpan(exception); minute >= span.start && minute < span.end {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableWindows returns the parts of start to end that fall in the
+// volunteer's weekly availability as adjusted by their exceptions, in order
+// and merged, looking at most exceptionHorizon days ahead. Volunteers
+// without a weekly schedule count as available whenever their exceptions
+// allow.
+func (v *Volunteer) AvailableWindows(start, end time.Time) []TimeWindow {
+	loc := v.TimeZone()
+	start, end = start.In(loc), end.In(loc)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+
+	var windows []TimeWindow
+	for i := 0; i < exceptionHorizon && day.Before(end); i++ {
+		for _, span := range mergeSpans(v.daySpans(day)) {
+			from := day.Add(time.Duration(span.start) * time.Minute)
+			to := day.Add(time.Duration(span.end) * time.Minute)
+			if from.Before(start) {
+				from = start
+			}
+			if to.After(end) {
+				to = end
+			}
+			if !from.Before(to) {
+				continue
+			}
+
+			// Availability running past midnight continues the last window
+			if n := len(windows); n > 0 && !windows[n-1].End.Before(from) {
+				if to.After(windows[n-1].End) {
+					windows[n-1].End = to
+				}
+				continue
+			}
+			windows = append(windows, TimeWindow{Start: from, End: to})
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return windows
+}
+
+// minuteSpan is a span of a day in minutes since midnight, end exclusive
+type minuteSpan struct {
+	start, end int
+}
+
+// daySpans returns the volunteer's available spans on day: the weekly
+// schedule, less unavailable exceptions, plus available ones
+func (v *Volunteer) daySpans(day time.Time) []minuteSpan {
+	var spans []minuteSpan
+	if len(v.Availability) == 0 {
+		spans = []minuteSpan{{0, 24 * 60}}
+	}
+	for _, slot := range v.Availability {
+		if slot.DayOfWeek == int(day.Weekday()) {
+			if span, ok := parseSpan(slot.StartTime, slot.EndTime); ok {
+				spans = append(spans, span)
+			}
+		}
+	}
+
+	date := day.Format(DateLayout)
+	for _, exception := range v.Exceptions {
+		if exception.Date == date && !exception.Available {
+			spans = subtractSpan(spans, exceptionSpan(exception))
+		}
+	}
+	for _, exception := range v.Exceptions {
+		if exception.Date == date && exception.Available {
+			spans = append(spans, exceptionSpan(exception))
+		}
+	}
+	return spans
+}
+
+// exceptionSpan returns the part of its day an exception covers
+func exceptionSpan(exception AvailabilityException) minuteSpan {
+	if span, ok := parseSpan(exception.StartTime, exception.EndTime); ok {
+		return span
+	}
+	return minuteSpan{0, 24 * 60}
+}
+
+// ValidSpan reports whether the request leaves out both times, covering the
+// whole day, or gives an end after the start
+func (r AvailabilityExceptionRequest) ValidSpan() bool {
+	if r.StartTime == "" && r.EndTime == "" {
+		return true
+	}
+	_, ok := parseSpan(r.StartTime, r.EndTime)
+	return ok
+}
+
+// parseSpan parses "09:00" and "17:00" style times of day
+func parseSpan(start, end string) (minuteSpan, bool) {
+	from, err := time.Parse(TimeOfDayLayout, start)
+	if err != nil {
+		return minuteSpan{}, false
+	}
+	to, err := time.Parse(TimeOfDayLayout, end)
+	if err != nil {
+		return minuteSpan{}, false
+	}
+	span := minuteSpan{from.Hour()*60 + from.Minute(), to.Hour()*60 + to.Minute()}
+	return span, span.end > span.start
+}
+
+// mergeSpans sorts spans and joins those that overlap or touch
+func mergeSpans(spans []minuteSpan) []minuteSpan {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var merged []minuteSpan
+	for _, span := range spans {
+		if n := len(merged); n > 0 && span.start <= merged[n-1].end {
+			if span.end > merged[n-1].end {
+				merged[n-1].end = span.end
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// subtractSpan removes cut from each span, splitting those it falls inside
+func subtractSpan(spans []minuteSpan, cut minuteSpan) []minuteSpan {
+	var result []minuteSpan
+	for _, span := range spans {
+		if cut.end <= span.start || cut.start >= span.end {
+			result = append(result, span)
+			continue
+		}
+		if span.start < cut.start {
+			result = append(result, minuteSpan{span.start, cut.start})
+		}
+		if cut.end < span.end {
+			result = append(result, minuteSpan{cut.end, span.end})
+		}
+	}
+	return result
+} 
\ No newline at end of file
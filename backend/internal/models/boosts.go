@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// NeedBoost is a re-broadcast of a need to volunteers its earlier
+// notifications did not reach, matched by relaxed criteria
+type NeedBoost struct {
+	At               time.Time `bson:"at" json:"at"`
+	MatchThreshold   float64   `bson:"match_threshold" json:"match_threshold"`     // the relaxed minimum score
+	RadiusMultiplier float64   `bson:"radius_multiplier" json:"radius_multiplier"` // how far the distance was stretched
+	Notified         int       `bson:"notified" json:"notified"`                   // volunteers told about the need
+} 
\ No newline at end of file
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategorySeparator joins the slugs of a category path, from the root down,
+// e.g. transportation/medical_appointments
+const CategorySeparator = "/"
+
+// categorySlug is the form of each step of a category path
+var categorySlug = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// NeedCategory is a category in the need taxonomy. The top-level
+// categories are its roots, seeded with DefaultCategories; admins add
+// others, and subcategories beneath them and beneath each other.
+type NeedCategory struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Path        string             `bson:"path" json:"path"`
+	Parent      string             `bson:"parent" json:"parent"` // path of the category above; empty for a top-level category
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"` // zero for the seeded categories
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CategoryNode is a category in the taxonomy tree with its subcategories
+type CategoryNode struct {
+	ID          primitive.ObjectID `json:"id"`
+	Path        string             `json:"path"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Children    []CategoryNode     `json:"children,omitempty"`
+}
+
+// CreateCategoryRequest adds a subcategory beneath parent, the path of
+// another category, or a top-level category when parent is empty
+type CreateCategoryRequest struct {
+	Parent      string `json:"parent,omitempty" binding:"max=200"`
+	Slug        string `json:"slug" binding:"required,max=50"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description,omitempty" binding:"max=500"`
+}
+
+// UpdateCategoryRequest renames or redescribes a subcategory; omitted
+// fields are left alone. Its path cannot change.
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,max=100"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=500"`
+}
+
+// CategoryRoot returns the top-level category of a category path
+func CategoryRoot(path string) Category {
+	root, _, _ := strings.Cut(path, CategorySeparator)
+	return Category(root)
+}
+
+// CategoryAncestors returns the paths from a category path's root down to
+// the path itself
+func CategoryAncestors(path string) []string {
+	slugs := strings.Split(path, CategorySeparator)
+	ancestors := make([]string, len(slugs))
+	for i := range slugs {
+		ancestors[i] = strings.Join(slugs[:i+1], CategorySeparator)
+	}
+	return ancestors
+}
+
+// ValidCategorySlug reports whether slug is well formed as one step of a
+// category path: lowercase letters and digits separated by underscores
+func ValidCategorySlug(slug string) bool {
+	return categorySlug.MatchString(slug)
+}
+
+// WellFormed reports whether c could name a top-level category. Whether one
+// exists is up to the taxonomy.
+func (c Category) WellFormed() bool {
+	return ValidCategorySlug(string(c))
+}
+
+// Label returns a category's default display name, e.g. "Pet care"
+func (c Category) Label() string {
+	label := strings.ReplaceAll(string(c), "_", " ")
+	if label == "" {
+		return ""
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// CategoryPath returns the need's place in the category taxonomy: its
+// subcategory's path, or else its category
+func (n *Need) CategoryPath() string {
+	if n.Subcategory != "" {
+		return n.Subcategory
+	}
+	return string(n.Category)
+}
+
+// Wants reports whether a need falls within the categories the volunteer
+// asked to be matched on, which is every need when they named none
+func (v *Volunteer) Wants(need *Need) bool {
+	if len(v.Categories) == 0 {
+		return true
+	}
+	for _, ancestor := range CategoryAncestors(need.CategoryPath()) {
+		for _, category := range v.Categories {
+			if category == ancestor {
+				return true
+			}
+		}
+	}
+	return false
+} 
\ No newline at end of file
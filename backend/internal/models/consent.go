@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PolicyDocument is a policy users must accept to use the service
+type PolicyDocument string
+
+// Policy documents
+const (
+	PolicyTerms   PolicyDocument = "terms"
+	PolicyPrivacy PolicyDocument = "privacy"
+)
+
+var policyDocuments = []string{"terms", "privacy"}
+
+// Valid reports whether d is a known policy document
+func (d PolicyDocument) Valid() bool { return contains(policyDocuments, string(d)) }
+
+// Values lists the known policy documents
+func (d PolicyDocument) Values() []string { return policyDocuments }
+
+// PolicyVersion is a published version of a policy. The latest version whose
+// effective time has passed is the one users must have accepted.
+type PolicyVersion struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Document    PolicyDocument     `bson:"document" json:"document"`
+	Version     string             `bson:"version" json:"version"`
+	URL         string             `bson:"url,omitempty" json:"url,omitempty"`
+	EffectiveAt time.Time          `bson:"effective_at" json:"effective_at"`
+	PublishedBy primitive.ObjectID `bson:"published_by" json:"published_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Consent records a user accepting one version of a policy
+type Consent struct {
+	Document   PolicyDocument `bson:"document" json:"document"`
+	Version    string         `bson:"version" json:"version"`
+	AcceptedAt time.Time      `bson:"accepted_at" json:"accepted_at"`
+	IP         string         `bson:"ip,omitempty" json:"-"`
+}
+
+// ConsentState is a user's standing against the current version of a policy
+type ConsentState struct {
+	Document        PolicyDocument `json:"document"`
+	CurrentVersion  string         `json:"current_version"`
+	URL             string         `json:"url,omitempty"`
+	AcceptedVersion string         `json:"accepted_version,omitempty"` // latest version the user accepted
+	AcceptedAt      *time.Time     `json:"accepted_at,omitempty"`
+	Required        bool           `json:"required"` // the current version still needs accepting
+}
+
+// PublishPolicyRequest publishes a new policy version
+type PublishPolicyRequest struct {
+	Document    PolicyDocument `json:"document" binding:"required,enum"`
+	Version     string         `json:"version" binding:"required,max=64"`
+	URL         string         `json:"url,omitempty" binding:"omitempty,url,max=500"`
+	EffectiveAt *time.Time     `json:"effective_at,omitempty"` // defaults to now
+}
+
+// AcceptPolicyRequest accepts the current version of a policy
+type AcceptPolicyRequest struct {
+	Document PolicyDocument `json:"document" binding:"required,enum"`
+	Version  string         `json:"version" binding:"required,max=64"`
+} 
\ No newline at end of file
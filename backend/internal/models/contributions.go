@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaterialCost is money a need's creator asks neighbors to chip in for,
+// such as groceries or a prescription, on deployments with contributions
+// enabled
+type MaterialCost struct {
+	AmountCents int64               `bson:"amount_cents" json:"amount_cents"`
+	Currency    string              `bson:"currency" json:"currency"`
+	Description string              `bson:"description,omitempty" json:"description,omitempty"`
+	RaisedCents int64               `bson:"raised_cents" json:"raised_cents"`               // paid contributions, less refunds
+	PayoutID    *primitive.ObjectID `bson:"payout_id,omitempty" json:"payout_id,omitempty"` // set once the need is completed and a payout recorded
+}
+
+// ContributionStatus is where a contribution is in the payment flow
+type ContributionStatus string
+
+// Contribution statuses
+const (
+	ContributionPending  ContributionStatus = "pending"  // checkout started
+	ContributionPaid     ContributionStatus = "paid"     // payment confirmed by webhook
+	ContributionExpired  ContributionStatus = "expired"  // checkout abandoned
+	ContributionRefunded ContributionStatus = "refunded" // refunded in Stripe
+)
+
+var contributionStatuses = []string{"pending", "paid", "expired", "refunded"}
+
+// Valid reports whether s is a known contribution status
+func (s ContributionStatus) Valid() bool { return contains(contributionStatuses, string(s)) }
+
+// Values lists the known contribution statuses
+func (s ContributionStatus) Values() []string { return contributionStatuses }
+
+// Contribution is one payment toward a need's material cost
+type Contribution struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	NeedID            primitive.ObjectID `bson:"need_id" json:"need_id"`
+	ContributorID     primitive.ObjectID `bson:"contributor_id" json:"contributor_id"`
+	AmountCents       int64              `bson:"amount_cents" json:"amount_cents"`
+	Currency          string             `bson:"currency" json:"currency"`
+	Status            ContributionStatus `bson:"status" json:"status"`
+	CheckoutSessionID string             `bson:"checkout_session_id" json:"-"`
+	CheckoutURL       string             `bson:"checkout_url,omitempty" json:"checkout_url,omitempty"`
+	PaymentIntentID   string             `bson:"payment_intent_id,omitempty" json:"-"`
+	ReceiptURL        string             `bson:"receipt_url,omitempty" json:"receipt_url,omitempty"` // Stripe-hosted receipt
+	PaidAt            *time.Time         `bson:"paid_at,omitempty" json:"paid_at,omitempty"`
+	RefundedAt        *time.Time         `bson:"refunded_at,omitempty" json:"refunded_at,omitempty"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ContributionReceipt is the in-app receipt for a paid contribution
+type ContributionReceipt struct {
+	ContributionID primitive.ObjectID `json:"contribution_id"`
+	NeedID         primitive.ObjectID `json:"need_id"`
+	NeedTitle      string             `json:"need_title"`
+	AmountCents    int64              `json:"amount_cents"`
+	Currency       string             `json:"currency"`
+	Status         ContributionStatus `json:"status"`
+	PaidAt         *time.Time         `json:"paid_at,omitempty"`
+	RefundedAt     *time.Time         `json:"refunded_at,omitempty"`
+	ReceiptURL     string             `json:"receipt_url,omitempty"`
+}
+
+// PayoutStatus is whether money raised for a need has reached its volunteer
+type PayoutStatus string
+
+// Payout statuses
+const (
+	PayoutPending PayoutStatus = "pending"
+	PayoutPaid    PayoutStatus = "paid"
+)
+
+var payoutStatuses = []string{"pending", "paid"}
+
+// Valid reports whether s is a known payout status
+func (s PayoutStatus) Valid() bool { return contains(payoutStatuses, string(s)) }
+
+// Values lists the known payout statuses
+func (s PayoutStatus) Values() []string { return payoutStatuses }
+
+// Payout tracks the money raised for a completed need owed to the volunteer
+// who fulfilled it. Admins send the money outside the app and record the
+// transfer reference here.
+type Payout struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	NeedID      primitive.ObjectID  `bson:"need_id" json:"need_id"`
+	TaskID      primitive.ObjectID  `bson:"task_id" json:"task_id"`
+	VolunteerID primitive.ObjectID  `bson:"volunteer_id" json:"volunteer_id"`
+	AmountCents int64               `bson:"amount_cents" json:"amount_cents"`
+	Currency    string              `bson:"currency" json:"currency"`
+	Status      PayoutStatus        `bson:"status" json:"status"`
+	Reference   string              `bson:"reference,omitempty" json:"reference,omitempty"`
+	PaidBy      *primitive.ObjectID `bson:"paid_by,omitempty" json:"paid_by,omitempty"`
+	PaidAt      *time.Time          `bson:"paid_at,omitempty" json:"paid_at,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// SetMaterialCostRequest sets or clears a need's material cost; an amount
+// of zero clears it
+type SetMaterialCostRequest struct {
+	AmountCents int64  `json:"amount_cents" binding:"min=0,max=100000"`
+	Description string `json:"description,omitempty" binding:"max=300"`
+}
+
+// ContributeRequest starts a contribution toward a need's material cost
+type ContributeRequest struct {
+	AmountCents int64 `json:"amount_cents" binding:"required,min=100,max=100000"`
+}
+
+// MarkPayoutPaidRequest records that a payout was sent
+type MarkPayoutPaidRequest struct {
+	Reference string `json:"reference" binding:"required,max=200"`
+} 
\ No newline at end of file
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DevicePlatform is the kind of app a device token was issued to, which
+// decides the push service that delivers to it
+type DevicePlatform string
+
+// Device platforms. Android and web apps receive push through FCM, and iOS
+// apps through APNs.
+const (
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformWeb     DevicePlatform = "web"
+)
+
+var devicePlatforms = []string{"android", "ios", "web"}
+
+// Valid reports whether p is a known device platform
+func (p DevicePlatform) Valid() bool { return contains(devicePlatforms, string(p)) }
+
+// Values lists the known device platforms
+func (p DevicePlatform) Values() []string { return devicePlatforms }
+
+// Device is an app install registered for push notifications while its
+// user is not connected over WebSocket. A token belongs to whichever user
+// registered it last.
+type Device struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"-"`
+	Token     string             `bson:"token" json:"token"`
+	Platform  DevicePlatform     `bson:"platform" json:"platform"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// RegisterDeviceRequest registers a push token for the current user
+type RegisterDeviceRequest struct {
+	Token    string         `json:"token" binding:"required,max=4096"`
+	Platform DevicePlatform `json:"platform" binding:"required,enum"`
+}
+
+// UnregisterDeviceRequest stops push notifications to a token, e.g. on
+// sign-out
+type UnregisterDeviceRequest struct {
+	Token string `json:"token" binding:"required,max=4096"`
+} 
\ No newline at end of file
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Emergency is a declared disaster response, such as a storm or heat wave,
+// covering the whole deployment or a set of regions. While it is in force,
+// needs inside it are matched with volunteers from further away, needs in
+// its priority categories come first, and they are collected in an
+// emergency feed.
+type Emergency struct {
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title              string              `bson:"title" json:"title"`
+	Message            string              `bson:"message" json:"message"`
+	Regions            []string            `bson:"regions,omitempty" json:"regions,omitempty"`       // H3 cells at Resolution; empty covers the whole deployment
+	Resolution         int                 `bson:"resolution,omitempty" json:"resolution,omitempty"` // resolution of Regions
+	PriorityCategories []Category          `bson:"priority_categories,omitempty" json:"priority_categories,omitempty"`
+	RadiusMultiplier   float64             `bson:"radius_multiplier" json:"radius_multiplier"`                 // stretches the matching distance decay
+	AnnouncementID     *primitive.ObjectID `bson:"announcement_id,omitempty" json:"announcement_id,omitempty"` // broadcast alert, when one was sent
+	DeclaredBy         primitive.ObjectID  `bson:"declared_by" json:"declared_by"`
+	StartedAt          time.Time           `bson:"started_at" json:"started_at"`
+	EndedAt            *time.Time          `bson:"ended_at,omitempty" json:"ended_at,omitempty"`
+	EndedBy            *primitive.ObjectID `bson:"ended_by,omitempty" json:"ended_by,omitempty"`
+}
+
+// DeclareEmergencyRequest puts emergency mode in force. Without regions it
+// covers the whole deployment; without a radius multiplier the default is
+// used. Broadcast also sends an alert to everyone in the regions.
+type DeclareEmergencyRequest struct {
+	Title              string     `json:"title" binding:"required,max=200"`
+	Message            string     `json:"message" binding:"required,max=5000"`
+	Regions            []string   `json:"regions,omitempty" binding:"max=100"`
+	Resolution         int        `json:"resolution,omitempty" binding:"min=0,max=15"`
+	PriorityCategories []Category `json:"priority_categories,omitempty" binding:"max=20,dive,category"`
+	RadiusMultiplier   float64    `json:"radius_multiplier,omitempty" binding:"omitempty,min=1,max=10"`
+	Broadcast          bool       `json:"broadcast,omitempty"`
+} 
\ No newline at end of file
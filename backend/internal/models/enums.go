@@ -0,0 +1,129 @@
+package models
+
+// Enum is implemented by string types restricted to a fixed set of values
+type Enum interface {
+	Valid() bool
+	Values() []string
+}
+
+// Urgency is how soon a need must be met
+type Urgency string
+
+// Need urgencies
+const (
+	UrgencyLow    Urgency = "low"
+	UrgencyMedium Urgency = "medium"
+	UrgencyHigh   Urgency = "high"
+)
+
+var urgencies = []string{"low", "medium", "high"}
+
+// Valid reports whether u is a known urgency
+func (u Urgency) Valid() bool { return contains(urgencies, string(u)) }
+
+// Values lists the known urgencies
+func (u Urgency) Values() []string { return urgencies }
+
+// Category is the kind of help a need asks for
+type Category string
+
+// Need categories
+const (
+	CategoryErrands        Category = "errands"
+	CategoryGroceries      Category = "groceries"
+	CategoryTransportation Category = "transportation"
+	CategoryMeals          Category = "meals"
+	CategoryChildcare      Category = "childcare"
+	CategoryEldercare      Category = "eldercare"
+	CategoryPetCare        Category = "pet_care"
+	CategoryHomeRepair     Category = "home_repair"
+	CategoryYardWork       Category = "yard_work"
+	CategoryMoving         Category = "moving"
+	CategoryTechnology     Category = "technology"
+	CategoryTutoring       Category = "tutoring"
+	CategoryCompanionship  Category = "companionship"
+	CategoryOther          Category = "other"
+)
+
+// DefaultCategories are the top-level categories the taxonomy is seeded
+// with. Admins can add others and remove any but CategoryOther, so check
+// categories against the taxonomy rather than this list.
+var DefaultCategories = []Category{
+	CategoryErrands, CategoryGroceries, CategoryTransportation, CategoryMeals, CategoryChildcare,
+	CategoryEldercare, CategoryPetCare, CategoryHomeRepair, CategoryYardWork, CategoryMoving,
+	CategoryTechnology, CategoryTutoring, CategoryCompanionship, CategoryOther,
+}
+
+// NeedStatus is where a need is in its lifecycle
+type NeedStatus string
+
+// Need statuses
+const (
+	NeedStatusRequested  NeedStatus = "requested"
+	NeedStatusMatched    NeedStatus = "matched"
+	NeedStatusInProgress NeedStatus = "in_progress"
+	NeedStatusCompleted  NeedStatus = "completed"
+	NeedStatusCancelled  NeedStatus = "cancelled"
+	NeedStatusExpired    NeedStatus = "expired" // nobody took it on before it expired; its creator can extend it
+)
+
+var needStatuses = []string{"requested", "matched", "in_progress", "completed", "cancelled", "expired"}
+
+// Valid reports whether s is a known need status
+func (s NeedStatus) Valid() bool { return contains(needStatuses, string(s)) }
+
+// Values lists the known need statuses
+func (s NeedStatus) Values() []string { return needStatuses }
+
+// TaskStatus is where a task is in its lifecycle
+type TaskStatus string
+
+// Task statuses
+const (
+	TaskStatusAccepted   TaskStatus = "accepted"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusCompleted  TaskStatus = "completed"
+	TaskStatusCancelled  TaskStatus = "cancelled"
+
+	// TaskStatusAwaitingConfirmation is a task one participant has marked
+	// completed, waiting on the other to confirm
+	TaskStatusAwaitingConfirmation TaskStatus = "awaiting_confirmation"
+)
+
+var taskStatuses = []string{"accepted", "in_progress", "awaiting_confirmation", "completed", "cancelled"}
+
+// Valid reports whether s is a known task status
+func (s TaskStatus) Valid() bool { return contains(taskStatuses, string(s)) }
+
+// Values lists the known task statuses
+func (s TaskStatus) Values() []string { return taskStatuses }
+
+// MatchState is where a match stands with its volunteer
+type MatchState string
+
+// Match states. Matches start suggested, become viewed once listed to the
+// volunteer, and end declined by them or accepted as a task. Declined pairs
+// are not matched again.
+const (
+	MatchStateSuggested MatchState = "suggested"
+	MatchStateViewed    MatchState = "viewed"
+	MatchStateDeclined  MatchState = "declined"
+	MatchStateAccepted  MatchState = "accepted"
+)
+
+var matchStates = []string{"suggested", "viewed", "declined", "accepted"}
+
+// Valid reports whether s is a known match state
+func (s MatchState) Valid() bool { return contains(matchStates, string(s)) }
+
+// Values lists the known match states
+func (s MatchState) Values() []string { return matchStates }
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
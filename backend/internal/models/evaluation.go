@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchDecisionKind is the side of the marketplace a match set was computed for
+type MatchDecisionKind string
+
+const (
+	MatchDecisionNeed      MatchDecisionKind = "need"      // volunteers scored for a need
+	MatchDecisionVolunteer MatchDecisionKind = "volunteer" // needs scored for a volunteer
+)
+
+// MatchOutcome is what became of a candidate in a match set
+type MatchOutcome string
+
+const (
+	MatchOutcomeNotShown  MatchOutcome = "not_shown" // the volunteer was never told of the need
+	MatchOutcomeShown     MatchOutcome = "shown"     // told of the need, which is still open to them
+	MatchOutcomeDeclined  MatchOutcome = "declined"  // told of the need but let it go: someone else took it on, or they cancelled
+	MatchOutcomeAccepted  MatchOutcome = "accepted"  // took on the need, which is not yet done
+	MatchOutcomeCompleted MatchOutcome = "completed" // took on the need and completed it
+)
+
+// MatchFeatures are the inputs a match score was computed from
+type MatchFeatures struct {
+	Similarity       float64 `bson:"similarity" json:"similarity"`               // cosine similarity of the embeddings
+	Distance         float64 `bson:"distance" json:"distance"`                   // meters
+	DistanceScore    float64 `bson:"distance_score" json:"distance_score"`       // distance decay applied to the similarity
+	DecayKm          float64 `bson:"decay_km" json:"decay_km"`                   // distance at which the distance score falls to 1/e
+	Availability     float64 `bson:"availability" json:"availability"`           // how well the volunteer's availability suits the need, applied to the similarity
+	ReputationWeight float64 `bson:"reputation_weight" json:"reputation_weight"` // multiplier from the volunteer's reputation; 1 when not applied
+	Trust            float64 `bson:"trust" json:"trust"`
+	Threshold        float64 `bson:"threshold" json:"threshold"` // minimum combined score for the match
+	Priority         bool    `bson:"priority,omitempty" json:"priority"`
+}
+
+// MatchCandidate is one need and volunteer pair in a match set
+type MatchCandidate struct {
+	NeedID      primitive.ObjectID `bson:"need_id" json:"need_id"`
+	VolunteerID primitive.ObjectID `bson:"volunteer_id" json:"volunteer_id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"` // the volunteer's account, which tasks and notifications refer to
+	Category    Category           `bson:"category" json:"category"`
+	Rank        int                `bson:"rank" json:"rank"`
+	Score       float64            `bson:"score" json:"score"`
+	Features    MatchFeatures      `bson:"features" json:"features"`
+}
+
+// MatchDecision records a match set the matcher computed, so the scoring
+// model can be evaluated offline against what became of each candidate
+type MatchDecision struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind       MatchDecisionKind  `bson:"kind" json:"kind"`
+	SubjectID  primitive.ObjectID `bson:"subject_id" json:"subject_id"` // the need or volunteer profile matched
+	Candidates []MatchCandidate   `bson:"candidates" json:"candidates"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// MatchEvaluationRow is one candidate of a recorded match set with its
+// outcome, as exported for offline evaluation. IDs may be pseudonyms.
+type MatchEvaluationRow struct {
+	DecisionID string            `json:"decision_id"`
+	Kind       MatchDecisionKind `json:"kind"`
+	Subject    string            `json:"subject"`
+	Need       string            `json:"need"`
+	Volunteer  string            `json:"volunteer"`
+	Category   Category          `json:"category"`
+	Rank       int               `json:"rank"`
+	SetSize    int               `json:"set_size"`
+	Score      float64           `json:"score"`
+	Features   MatchFeatures     `json:"features"`
+	Outcome    MatchOutcome      `json:"outcome"`
+	ComputedAt time.Time         `json:"computed_at"`
+} 
\ No newline at end of file
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventStatus is where a volunteering event is in its lifecycle
+type EventStatus string
+
+// Event statuses
+const (
+	EventScheduled EventStatus = "scheduled"
+	EventCancelled EventStatus = "cancelled"
+)
+
+var eventStatuses = []string{"scheduled", "cancelled"}
+
+// Valid reports whether s is a known event status
+func (s EventStatus) Valid() bool { return contains(eventStatuses, string(s)) }
+
+// Values lists the known event statuses
+func (s EventStatus) Values() []string { return eventStatuses }
+
+// Attendance records whether a signed-up volunteer turned up
+type Attendance string
+
+// Attendance outcomes; signups start without one
+const (
+	AttendanceAttended Attendance = "attended"
+	AttendanceNoShow   Attendance = "no_show"
+)
+
+var attendances = []string{"attended", "no_show"}
+
+// Valid reports whether a is a known attendance outcome
+func (a Attendance) Valid() bool { return contains(attendances, string(a)) }
+
+// Values lists the known attendance outcomes
+func (a Attendance) Values() []string { return attendances }
+
+// EventShift is a time slot volunteers sign up for within an event
+type EventShift struct {
+	ID       primitive.ObjectID `bson:"id" json:"id"`
+	Name     string             `bson:"name,omitempty" json:"name,omitempty"`
+	StartsAt time.Time          `bson:"starts_at" json:"starts_at"`
+	EndsAt   time.Time          `bson:"ends_at" json:"ends_at"`
+	Capacity int                `bson:"capacity" json:"capacity"`
+	SignedUp int                `bson:"signed_up" json:"signed_up"`
+}
+
+// Event is a group volunteering event, such as a park cleanup or food drive,
+// that many volunteers sign up for. Events with shifts take signups per
+// shift; events without take them for the whole event, up to Capacity.
+type Event struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title       string              `bson:"title" json:"title"`
+	Description string              `bson:"description" json:"description"`
+	Category    Category            `bson:"category,omitempty" json:"category,omitempty"`
+	Location    Location            `bson:"location" json:"location"`
+	StartsAt    time.Time           `bson:"starts_at" json:"starts_at"`
+	EndsAt      time.Time           `bson:"ends_at" json:"ends_at"`
+	Capacity    int                 `bson:"capacity" json:"capacity"`   // total volunteers; the sum of shift capacities when there are shifts
+	SignedUp    int                 `bson:"signed_up" json:"signed_up"` // signups across all shifts
+	Shifts      []EventShift        `bson:"shifts,omitempty" json:"shifts,omitempty"`
+	GroupID     *primitive.ObjectID `bson:"group_id,omitempty" json:"group_id,omitempty"` // set for events open only to a group's members
+	OrganizerID primitive.ObjectID  `bson:"organizer_id" json:"organizer_id"`
+	Status      EventStatus         `bson:"status" json:"status"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// EventSignup is one volunteer's place at an event, or at one of its shifts
+type EventSignup struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	EventID     primitive.ObjectID  `bson:"event_id" json:"event_id"`
+	ShiftID     *primitive.ObjectID `bson:"shift_id,omitempty" json:"shift_id,omitempty"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	StartsAt    time.Time           `bson:"starts_at" json:"starts_at"` // start of the shift, or of the event; drives reminders
+	Attendance  Attendance          `bson:"attendance,omitempty" json:"attendance,omitempty"`
+	CheckedInAt *time.Time          `bson:"checked_in_at,omitempty" json:"checked_in_at,omitempty"`
+	RemindedAt  *time.Time          `bson:"reminded_at,omitempty" json:"reminded_at,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// CreateEventShiftRequest describes one shift of a new event
+type CreateEventShiftRequest struct {
+	Name     string    `json:"name,omitempty" binding:"max=100"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Capacity int       `json:"capacity" binding:"required,min=1,max=1000"`
+}
+
+// CreateEventRequest creates an event. Capacity is required without
+// shifts and ignored with them.
+type CreateEventRequest struct {
+	Title       string                    `json:"title" binding:"required,max=200"`
+	Description string                    `json:"description" binding:"required,max=5000"`
+	Category    Category                  `json:"category,omitempty" binding:"omitempty,category"`
+	Location    Location                  `json:"location" binding:"required"`
+	StartsAt    time.Time                 `json:"starts_at" binding:"required"`
+	EndsAt      time.Time                 `json:"ends_at" binding:"required"`
+	Capacity    int                       `json:"capacity,omitempty" binding:"min=0,max=10000"`
+	Shifts      []CreateEventShiftRequest `json:"shifts,omitempty" binding:"max=50,dive"`
+	GroupID     string                    `json:"group_id,omitempty"`
+}
+
+// EventSignupRequest signs the current user up; ShiftID is required for
+// events with shifts
+type EventSignupRequest struct {
+	ShiftID string `json:"shift_id,omitempty"`
+}
+
+// AttendanceRequest records whether a volunteer attended
+type AttendanceRequest struct {
+	Attendance Attendance `json:"attendance" binding:"required,enum"`
+} 
\ No newline at end of file
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportStatus is where a data export is in its lifecycle
+type ExportStatus string
+
+// Export statuses
+const (
+	ExportPending    ExportStatus = "pending"
+	ExportProcessing ExportStatus = "processing"
+	ExportReady      ExportStatus = "ready"
+	ExportFailed     ExportStatus = "failed"
+)
+
+// DataExport is a user's request for an archive of all their data
+type DataExport struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Status      ExportStatus        `bson:"status" json:"status"`
+	FileID      *primitive.ObjectID `bson:"file_id,omitempty" json:"-"` // GridFS file holding the archive
+	Size        int64               `bson:"size,omitempty" json:"size,omitempty"`
+	Error       string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+	CompletedAt *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // when the archive is deleted
+}
+
+// VolunteerHours is one completed task in a volunteer's hours export
+type VolunteerHours struct {
+	TaskID      primitive.ObjectID `bson:"_id" json:"task_id"`
+	NeedID      primitive.ObjectID `bson:"need_id" json:"need_id"`
+	Category    Category           `bson:"category" json:"category"`
+	Minutes     int                `bson:"minutes" json:"minutes"` // the need's estimated duration
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// ActivityAggregate counts a day's activity in one category across the
+// community, with nothing that identifies the users involved
+type ActivityAggregate struct {
+	Day              string   `bson:"day" json:"day"` // YYYY-MM-DD, UTC
+	Category         Category `bson:"category" json:"category"`
+	NeedsCreated     int      `bson:"needs_created" json:"needs_created"`
+	TasksCompleted   int      `bson:"tasks_completed" json:"tasks_completed"`
+	VolunteerMinutes int      `bson:"volunteer_minutes" json:"volunteer_minutes"`
+} 
\ No newline at end of file
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reputation rolls up the revealed ratings a user has received and how
+// the tasks they took on as a volunteer closed. It is kept on both the user
+// and their volunteer profile.
+type Reputation struct {
+	Score            float64   `bson:"score" json:"score"`     // 0-1, pulled toward neutral while ratings are few
+	Average          float64   `bson:"average" json:"average"` // mean rating, 1-5
+	Count            int       `bson:"count" json:"count"`
+	ClosedTasks      int       `bson:"closed_tasks" json:"closed_tasks"`           // tasks completed or cancelled as volunteer
+	CompletionRate   float64   `bson:"completion_rate" json:"completion_rate"`     // share of closed tasks completed, 0-1
+	CancellationRate float64   `bson:"cancellation_rate" json:"cancellation_rate"` // share of closed tasks cancelled, 0-1
+	UpdatedAt        time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// FeedbackResponse is the recipient's public reply to a low rating, shown
+// alongside it
+type FeedbackResponse struct {
+	Text      string    `bson:"text" json:"text"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// AppealStatus is where a feedback appeal stands
+type AppealStatus string
+
+// Appeal statuses
+const (
+	AppealPending AppealStatus = "pending"
+	AppealUpheld  AppealStatus = "upheld" // the rating is hidden and left out of the reputation
+	AppealDenied  AppealStatus = "denied"
+)
+
+var appealStatuses = []string{"pending", "upheld", "denied"}
+
+// Valid reports whether s is a known appeal status
+func (s AppealStatus) Valid() bool { return contains(appealStatuses, string(s)) }
+
+// Values lists the known appeal statuses
+func (s AppealStatus) Values() []string { return appealStatuses }
+
+// FeedbackAppeal asks moderators to exclude a low rating from the
+// recipient's reputation
+type FeedbackAppeal struct {
+	Reason    string       `bson:"reason" json:"reason"`
+	Status    AppealStatus `bson:"status" json:"status"`
+	FiledAt   time.Time    `bson:"filed_at" json:"filed_at"`
+	DecidedAt *time.Time   `bson:"decided_at,omitempty" json:"decided_at,omitempty"`
+}
+
+// FeedbackResponseRequest replies publicly to a low rating
+type FeedbackResponseRequest struct {
+	Text string `json:"text" binding:"required,max=1000"`
+}
+
+// FeedbackAppealRequest appeals a low rating
+type FeedbackAppealRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// FeedbackThemes are the praise and criticism themes summarized from the
+// comments a volunteer received, cached on their profile until more arrive
+type FeedbackThemes struct {
+	Praise      []string  `bson:"praise" json:"praise"`
+	Criticism   []string  `bson:"criticism" json:"criticism"`
+	Comments    int       `bson:"comments" json:"comments"` // how many comments were summarized
+	GeneratedAt time.Time `bson:"generated_at" json:"generated_at"`
+}
+
+// RatingPeriod is a volunteer's average rating over one calendar month
+type RatingPeriod struct {
+	Month   string  `bson:"_id" json:"month"` // YYYY-MM, UTC
+	Average float64 `bson:"average" json:"average"`
+	Count   int     `bson:"count" json:"count"`
+}
+
+// CategoryRating is a volunteer's average rating for needs in one category
+type CategoryRating struct {
+	Category Category `bson:"_id" json:"category"`
+	Average  float64  `bson:"average" json:"average"`
+	Count    int      `bson:"count" json:"count"`
+}
+
+// FeedbackInsights break down the revealed ratings a volunteer received so
+// they can improve. Themes is nil when summaries are unavailable.
+type FeedbackInsights struct {
+	Monthly    []RatingPeriod   `json:"monthly"`
+	Categories []CategoryRating `json:"categories"`
+	Themes     *FeedbackThemes  `json:"themes,omitempty"`
+}
+
+// Testimonial marks a feedback comment its author agreed to share publicly.
+// It is shown on the recipient's profile once they approve it, until either
+// of them withdraws.
+type Testimonial struct {
+	SharedAt   time.Time  `bson:"shared_at" json:"shared_at"`
+	ApprovedAt *time.Time `bson:"approved_at,omitempty" json:"approved_at,omitempty"`
+}
+
+// PublicTestimonial is an approved testimonial as anyone may see it, with
+// names shown as their owners' privacy settings allow
+type PublicTestimonial struct {
+	ID          primitive.ObjectID `json:"id"`
+	Rating      int                `json:"rating"`
+	Comment     string             `json:"comment"`
+	Author      string             `json:"author"`
+	RecipientID primitive.ObjectID `json:"recipient_id"`
+	Recipient   string             `json:"recipient"`
+	CreatedAt   time.Time          `json:"created_at"`
+} 
\ No newline at end of file
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GroupRole is a member's role within a group
+type GroupRole string
+
+// Group roles
+const (
+	GroupRoleMember GroupRole = "member"
+	GroupRoleAdmin  GroupRole = "admin" // may edit the group and manage its members
+)
+
+var groupRoles = []string{"member", "admin"}
+
+// Valid reports whether r is a known group role
+func (r GroupRole) Valid() bool { return contains(groupRoles, string(r)) }
+
+// Values lists the known group roles
+func (r GroupRole) Values() []string { return groupRoles }
+
+// Coordinate is a point on a group boundary
+type Coordinate struct {
+	Latitude  float64 `bson:"latitude" json:"latitude" binding:"min=-90,max=90"`
+	Longitude float64 `bson:"longitude" json:"longitude" binding:"min=-180,max=180"`
+}
+
+// GroupArea is the part of the deployment a group covers. Cells is always
+// set, at the block location resolution; Boundary is kept when the area was
+// drawn as a polygon.
+type GroupArea struct {
+	Cells    []string     `bson:"cells" json:"cells"`
+	Boundary []Coordinate `bson:"boundary,omitempty" json:"boundary,omitempty"`
+}
+
+// Group is a micro-community, such as an apartment building or block
+// association, with a feed of the needs inside its area
+type Group struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	Area        GroupArea          `bson:"area" json:"area"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	MemberCount int                `bson:"member_count" json:"member_count"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// GroupMember is one user's membership of a group
+type GroupMember struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupID  primitive.ObjectID `bson:"group_id" json:"group_id"`
+	UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Role     GroupRole          `bson:"role" json:"role"`
+	JoinedAt time.Time          `bson:"joined_at" json:"joined_at"`
+}
+
+// GroupAreaRequest gives a group area as H3 cells of any resolution or as a
+// custom boundary polygon, but not both
+type GroupAreaRequest struct {
+	H3Cells  []string     `json:"h3_cells,omitempty" binding:"max=500"`
+	Boundary []Coordinate `json:"boundary,omitempty" binding:"omitempty,min=3,max=200,dive"`
+}
+
+// CreateGroupRequest creates a group with the caller as its first admin
+type CreateGroupRequest struct {
+	Name        string           `json:"name" binding:"required,max=100"`
+	Description string           `json:"description" binding:"max=2000"`
+	Area        GroupAreaRequest `json:"area"`
+}
+
+// UpdateGroupRequest changes the fields that are set
+type UpdateGroupRequest struct {
+	Name        *string           `json:"name,omitempty" binding:"omitempty,max=100"`
+	Description *string           `json:"description,omitempty" binding:"omitempty,max=2000"`
+	Area        *GroupAreaRequest `json:"area,omitempty"`
+}
+
+// UpdateGroupMemberRequest changes a member's role
+type UpdateGroupMemberRequest struct {
+	Role GroupRole `json:"role" binding:"required,enum"`
+} 
\ No newline at end of file
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IncidentSeverity grades a safety incident reported in feedback
+type IncidentSeverity string
+
+// Incident severities, least severe first
+const (
+	IncidentMinor    IncidentSeverity = "minor"    // uncomfortable or inappropriate, no one at risk
+	IncidentSerious  IncidentSeverity = "serious"  // threatening behavior, harassment, or property damage
+	IncidentCritical IncidentSeverity = "critical" // violence, abuse, or someone put in danger
+)
+
+var incidentSeverities = []string{"minor", "serious", "critical"}
+
+// Valid reports whether s is a known incident severity
+func (s IncidentSeverity) Valid() bool { return contains(incidentSeverities, string(s)) }
+
+// Values lists the known incident severities
+func (s IncidentSeverity) Values() []string { return incidentSeverities }
+
+// incidentRanks orders the incident severities; the empty severity ranks
+// below all of them
+var incidentRanks = map[IncidentSeverity]int{IncidentMinor: 1, IncidentSerious: 2, IncidentCritical: 3}
+
+// AtLeast reports whether s is min or more severe. Nothing is at least the
+// empty severity, so an empty min never matches.
+func (s IncidentSeverity) AtLeast(min IncidentSeverity) bool {
+	return min != "" && incidentRanks[s] >= incidentRanks[min]
+}
+
+// FeedbackIncident is a safety incident the author reported with their
+// feedback. It is kept apart from the rating: moderators review it whatever
+// the rating was, and it is never shown to the reported user.
+type FeedbackIncident struct {
+	Severity         IncidentSeverity   `bson:"severity" json:"severity"`
+	Description      string             `bson:"description" json:"description"`
+	ModerationItemID primitive.ObjectID `bson:"moderation_item_id" json:"moderation_item_id"`
+	ReportedAt       time.Time          `bson:"reported_at" json:"reported_at"`
+}
+
+// MatchingSuspension keeps a user out of matching while moderators review a
+// safety incident reported against them. It is kept on both the user and
+// their volunteer profile and lifted when the review is decided.
+type MatchingSuspension struct {
+	FeedbackID       primitive.ObjectID `bson:"feedback_id" json:"-"` // withheld so the reporter stays anonymous
+	ModerationItemID primitive.ObjectID `bson:"moderation_item_id" json:"moderation_item_id"`
+	Severity         IncidentSeverity   `bson:"severity" json:"severity"`
+	SuspendedAt      time.Time          `bson:"suspended_at" json:"suspended_at"`
+}
+
+// IncidentRequest reports a safety incident along with feedback
+type IncidentRequest struct {
+	Severity    IncidentSeverity `json:"severity" binding:"required,enum"`
+	Description string           `json:"description" binding:"required,max=2000"`
+} 
\ No newline at end of file
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KudosTag is a pre-set word of appreciation
+type KudosTag string
+
+// Kudos tags
+const (
+	KudosReliable       KudosTag = "reliable"
+	KudosKind           KudosTag = "kind"
+	KudosOnTime         KudosTag = "on_time"
+	KudosCommunicator   KudosTag = "great_communicator"
+	KudosSkilled        KudosTag = "skilled"
+	KudosAboveAndBeyond KudosTag = "above_and_beyond"
+)
+
+var kudosTags = []string{"reliable", "kind", "on_time", "great_communicator", "skilled", "above_and_beyond"}
+
+// Valid reports whether t is a known kudos tag
+func (t KudosTag) Valid() bool { return contains(kudosTags, string(t)) }
+
+// Values lists the known kudos tags
+func (t KudosTag) Values() []string { return kudosTags }
+
+// Kudos is a thank-you note the person helped leaves their volunteer after a
+// completed task. Unlike feedback it carries no rating and is shown on the
+// volunteer's profile straight away; the sender is never shown.
+type Kudos struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TaskID     primitive.ObjectID `bson:"task_id" json:"task_id"`
+	FromUserID primitive.ObjectID `bson:"from_user_id" json:"-"`
+	ToUserID   primitive.ObjectID `bson:"to_user_id" json:"to_user_id"`
+	Tags       []KudosTag         `bson:"tags,omitempty" json:"tags,omitempty"`
+	Message    string             `bson:"message,omitempty" json:"message,omitempty"`
+	Hidden     bool               `bson:"hidden,omitempty" json:"-"` // hidden by a moderator
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// KudosSummary counts the visible kudos a volunteer has received, kept on
+// their profile
+type KudosSummary struct {
+	Count int              `bson:"count" json:"count"`
+	Tags  map[KudosTag]int `bson:"tags" json:"tags"`
+}
+
+// KudosRequest sends kudos; at least one tag or a message is required
+type KudosRequest struct {
+	Tags    []KudosTag `json:"tags,omitempty" binding:"max=3,dive,enum"`
+	Message string     `json:"message,omitempty" binding:"max=500"`
+} 
\ No newline at end of file
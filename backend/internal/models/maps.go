@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// MapBounds is a map viewport. A west edge east of the east edge is a
+// viewport crossing the antimeridian.
+type MapBounds struct {
+	West  float64 `json:"west"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	North float64 `json:"north"`
+}
+
+// Contains reports whether a point falls within the bounds
+func (b MapBounds) Contains(lat, lng float64) bool {
+	if lat < b.South || lat > b.North {
+		return false
+	}
+	if b.West <= b.East {
+		return lng >= b.West && lng <= b.East
+	}
+	return lng >= b.West || lng <= b.East
+}
+
+// NeedCluster counts the open needs in one H3 cell of a map. Its location
+// is the cell's center, never a need's own location.
+type NeedCluster struct {
+	Cell       string           `json:"cell"`
+	Location   Location         `json:"location"`
+	Count      int              `json:"count"`
+	Categories map[Category]int `json:"categories"`
+	Pins       []NeedPin        `json:"pins"` // the newest needs in the cell
+}
+
+// NeedPin is a need shown on a map, at the center of its stored H3 cell
+type NeedPin struct {
+	ID        string    `bson:"id" json:"id"`
+	Title     string    `bson:"title" json:"title"`
+	Category  Category  `bson:"category" json:"category"`
+	Urgency   Urgency   `bson:"urgency" json:"urgency"`
+	Location  Location  `bson:"-" json:"location"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// NeedMap is the open needs within a map viewport, clustered into H3 cells
+type NeedMap struct {
+	Resolution int           `json:"resolution"`
+	Clusters   []NeedCluster `json:"clusters"`
+	Total      int           `json:"total"` // open needs within the viewport
+}
+
+// CategoryCoverage compares supply and demand for one category in a cell
+type CategoryCoverage struct {
+	Volunteers int `json:"volunteers"` // volunteers whose skills or interests name the category
+	OpenNeeds  int `json:"open_needs"`
+}
+
+// CellCoverage compares active volunteers with open needs in one H3 cell
+type CellCoverage struct {
+	Cell       string                        `json:"cell"`
+	Location   Location                      `json:"location"` // the cell's center
+	Volunteers int                           `json:"volunteers"`
+	OpenNeeds  int                           `json:"open_needs"`
+	Categories map[Category]CategoryCoverage `json:"categories"`
+	Uncovered  []Category                    `json:"uncovered"` // categories with open needs and no volunteer offering them
+} 
\ No newline at end of file
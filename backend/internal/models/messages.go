@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Message is a chat message between a need's creator and the volunteer who
+// took it on, sent on their task so they can agree a time and place
+type Message struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TaskID      primitive.ObjectID `bson:"task_id" json:"task_id"`
+	NeedID      primitive.ObjectID `bson:"need_id" json:"need_id"`
+	SenderID    primitive.ObjectID `bson:"sender_id" json:"sender_id"`
+	RecipientID primitive.ObjectID `bson:"recipient_id" json:"recipient_id"`
+	Body        string             `bson:"body" json:"body"`
+	Hidden      bool               `bson:"hidden,omitempty" json:"-"` // hidden by a moderator
+	ReadAt      *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// SendMessageRequest sends a chat message on a task
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+} 
\ No newline at end of file
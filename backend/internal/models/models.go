@@ -3,100 +3,308 @@ package models
 import (
 	"time"
 
+	"github.com/uber/h3-go/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // User represents a user in the system
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email     string            `bson:"email" json:"email"`
-	Password  string            `bson:"password" json:"-"`
-	Name      string            `bson:"name" json:"name"`
-	Phone     string            `bson:"phone,omitempty" json:"phone,omitempty"`
-	Location  Location          `bson:"location" json:"location"`
-	CreatedAt time.Time         `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time         `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Email       string                 `bson:"email" json:"email"`
+	Password    string                 `bson:"password" json:"-"`
+	Name        string                 `bson:"name" json:"name"`
+	Phone       EncryptedString        `bson:"phone,omitempty" json:"phone,omitempty"`
+	Location    Location               `bson:"location" json:"location"`
+	Role        string                 `bson:"role,omitempty" json:"role,omitempty"`                 // user, moderator, admin
+	AccountType AccountType            `bson:"account_type,omitempty" json:"account_type,omitempty"` // empty is an individual
+	DateOfBirth *time.Time             `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+	Supervision *AdultSupervision      `bson:"supervision,omitempty" json:"supervision,omitempty"` // youth group accounts only
+	Organizer   *OrganizerVerification `bson:"organizer,omitempty" json:"organizer,omitempty"`     // verified community organizers only
+	Hidden      bool                   `bson:"hidden,omitempty" json:"hidden,omitempty"`           // profile hidden by a moderator
+	Consents    []Consent              `bson:"consents,omitempty" json:"consents,omitempty"`       // every policy version accepted, oldest first
+	Privacy     PrivacySettings        `bson:"privacy,omitempty" json:"privacy"`
+	Badges      []Badge                `bson:"badges,omitempty" json:"badges,omitempty"`
+	Reputation  *Reputation            `bson:"reputation,omitempty" json:"reputation,omitempty"` // from ratings received as requester or volunteer
+	Identity    *IdentityVerification  `bson:"identity,omitempty" json:"identity,omitempty"`     // identity checked by an admin
+	Trust       *TrustScore            `bson:"trust,omitempty" json:"trust,omitempty"`
+	Suspension  *MatchingSuspension    `bson:"matching_suspension,omitempty" json:"matching_suspension,omitempty"` // kept out of matching pending a safety review
+	Language    Language               `bson:"language,omitempty" json:"language,omitempty"`                       // preferred language and locale; needs in others are shown translated
+	Units       Units                  `bson:"units,omitempty" json:"units,omitempty"`                             // distances are shown in these; empty is metric
+	Photo       *Attachment            `bson:"photo,omitempty" json:"photo,omitempty"`                             // profile photo
+	// EmailVerifiedAt is when the user proved they receive mail at Email,
+	// through a password reset link or a provider that verified it
+	EmailVerifiedAt *time.Time `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty"`
+	// EmailPending is set on accounts registered with a password until they
+	// verify their email. Only these are handed to a provider account with
+	// the address; accounts registered before the flag keep their password.
+	EmailPending bool `bson:"email_pending,omitempty" json:"-"`
+	// LinkedAccounts are the Google or Apple accounts the user signs in with
+	LinkedAccounts []LinkedAccount `bson:"linked_accounts,omitempty" json:"linked_accounts,omitempty"`
+	// AccountSuspension locks the user out of the API, set by a moderator
+	AccountSuspension *AccountSuspension `bson:"account_suspension,omitempty" json:"account_suspension,omitempty"`
+	// Notifications choose which events the user is emailed about
+	Notifications NotificationPreferences `bson:"notifications,omitempty" json:"notifications"`
+	// ConsentState is the user's standing against the current policies, filled in for their own profile
+	ConsentState []ConsentState `bson:"-" json:"consent_state,omitempty"`
+	CreatedAt    time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `bson:"updated_at" json:"updated_at"`
 }
 
-// Location represents a user's location (privacy-preserving)
+// User roles. Moderators work the moderation queue and act on abusive
+// content and users; admins can do everything.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// HasRole reports whether the user has one of the given roles
+// (users created before roles existed are treated as regular users)
+func (u *User) HasRole(roles ...string) bool {
+	role := u.Role
+	if role == "" {
+		role = RoleUser
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountSuspension locks a user out of the API, for abuse that hiding
+// their content does not stop
+type AccountSuspension struct {
+	Reason      string             `bson:"reason" json:"reason"`
+	SuspendedBy primitive.ObjectID `bson:"suspended_by" json:"suspended_by"`
+	SuspendedAt time.Time          `bson:"suspended_at" json:"suspended_at"`
+	Until       *time.Time         `bson:"until,omitempty" json:"until,omitempty"` // lifted automatically then; nil lasts until reinstated
+}
+
+// AccountSuspended reports whether the user is suspended at now
+func (u *User) AccountSuspended(now time.Time) bool {
+	return u.AccountSuspension != nil && (u.AccountSuspension.Until == nil || now.Before(*u.AccountSuspension.Until))
+}
+
+// SuspendUserRequest suspends a user's account
+type SuspendUserRequest struct {
+	Reason string     `json:"reason" binding:"required,max=500"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+// EncryptedString is a string sealed at rest when field encryption is enabled
+type EncryptedString string
+
+// Location represents a user's location (privacy-preserving). Its
+// coordinates and address are sealed at rest when field encryption is enabled.
 type Location struct {
 	Latitude  float64 `bson:"latitude" json:"latitude"`
 	Longitude float64 `bson:"longitude" json:"longitude"`
 	H3Index   string  `bson:"h3_index" json:"h3_index"` // Privacy-preserving location bucket
-	Address   string  `bson:"address,omitempty" json:"address,omitempty"`
+	Address   string  `bson:"address,omitempty" json:"address,omitempty" binding:"max=300"`
+	// Approximate marks a location coarsened for a viewer who is not a participant
+	Approximate bool `bson:"-" json:"approximate,omitempty"`
+}
+
+// MatchCellResolution is the H3 resolution needs and volunteers are indexed
+// at so matching only loads candidates from nearby cells. Its cells have
+// edges of about 3.7km, whatever the owner's location precision.
+const MatchCellResolution = 6
+
+// MatchCell returns the H3 cell at MatchCellResolution holding the exact
+// coordinates, or "" for a location without coordinates
+func (l Location) MatchCell() string {
+	if l.Latitude == 0 && l.Longitude == 0 {
+		return ""
+	}
+	return h3.LatLngToCell(h3.LatLng{Lat: l.Latitude, Lng: l.Longitude}, MatchCellResolution).String()
+}
+
+// GeoPoint is a GeoJSON point for 2dsphere queries. Coordinates are
+// longitude first, as GeoJSON requires.
+type GeoPoint struct {
+	Type        string     `bson:"type" json:"type"`
+	Coordinates [2]float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint creates a GeoJSON point at latitude and longitude
+func NewGeoPoint(latitude, longitude float64) *GeoPoint {
+	return &GeoPoint{Type: "Point", Coordinates: [2]float64{longitude, latitude}}
 }
 
 // Need represents a user's request for help
 type Need struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Title       string            `bson:"title" json:"title"`
-	Description string            `bson:"description" json:"description"`
-	Category    string            `bson:"category" json:"category"`
-	Urgency     string            `bson:"urgency" json:"urgency"` // low, medium, high
-	Duration    int               `bson:"duration" json:"duration"` // estimated minutes
-	Location    Location          `bson:"location" json:"location"`
-	Status      string            `bson:"status" json:"status"` // requested, matched, in_progress, completed, cancelled
-	Embedding   []float32         `bson:"embedding,omitempty" json:"-"`
-	CreatedAt   time.Time         `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time         `bson:"updated_at" json:"updated_at"`
-	ExpiresAt   *time.Time        `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID   `bson:"user_id" json:"user_id"`
+	Title         string               `bson:"title" json:"title"`
+	Description   string               `bson:"description" json:"description"`
+	Category      Category             `bson:"category" json:"category"`
+	Subcategory   string               `bson:"subcategory,omitempty" json:"subcategory,omitempty"` // path of a managed subcategory beneath Category
+	Urgency       Urgency              `bson:"urgency" json:"urgency"`                             // low, medium, high
+	Duration      int                  `bson:"duration" json:"duration"`                           // estimated minutes
+	Location      Location             `bson:"location" json:"location"`
+	Point         *GeoPoint            `bson:"point,omitempty" json:"-"`      // center of the location's H3 cell, for radius queries; the exact coordinates are sealed
+	MatchCell     string               `bson:"match_cell,omitempty" json:"-"` // H3 cell of the exact coordinates at MatchCellResolution, for narrowing matching
+	Status        NeedStatus           `bson:"status" json:"status"`          // requested, matched, in_progress, completed, cancelled, expired
+	Tags          []string             `bson:"tags,omitempty" json:"tags,omitempty"`
+	Embedding     []float32            `bson:"embedding,omitempty" json:"-"`
+	CreatedAt     time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time            `bson:"updated_at" json:"updated_at"`
+	ExpiresAt     *time.Time           `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	Hidden        bool                 `bson:"hidden,omitempty" json:"hidden,omitempty"`                   // hidden by a moderator
+	HeldForReview bool                 `bson:"held_for_review,omitempty" json:"held_for_review,omitempty"` // flagged as possible abuse; not matched until a moderator releases it
+	MaterialCost  *MaterialCost        `bson:"material_cost,omitempty" json:"material_cost,omitempty"`     // money asked of neighbors, where contributions are enabled
+	Partner       *PartnerReferral     `bson:"partner,omitempty" json:"partner,omitempty"`                 // set on needs referred through the partner intake API
+	MinTrust      TrustLevel           `bson:"min_trust,omitempty" json:"min_trust,omitempty"`             // lowest trust level a volunteer needs to be matched or accept; sensitive categories only
+	Language      Language             `bson:"language,omitempty" json:"language,omitempty"`               // language the title and description are written in
+	Window        *TimeWindow          `bson:"window,omitempty" json:"window,omitempty"`                   // when the requester needs help, if they said
+	SLA           *NeedSLA             `bson:"sla,omitempty" json:"sla,omitempty"`                         // how quickly the need was matched, accepted, and completed
+	WaitlistedAt  *time.Time           `bson:"waitlisted_at,omitempty" json:"waitlisted_at,omitempty"`     // set while no volunteer has matched; volunteers who join later are matched against it
+	Notified      []primitive.ObjectID `bson:"notified,omitempty" json:"-"`                                // users told about the need, whom boosts skip
+	Boosts        []NeedBoost          `bson:"boosts,omitempty" json:"boosts,omitempty"`                   // re-broadcasts the requester asked for, oldest first
+	Images        []Attachment         `bson:"images,omitempty" json:"images,omitempty"`                   // pictures of what needs doing, at most MaxNeedImages
+	// Translations caches machine translations of the need by language
+	Translations map[Language]NeedTranslation `bson:"translations,omitempty" json:"-"`
+	// Translation is the need in the viewer's preferred language, when that
+	// differs from the need's own
+	Translation *NeedTranslation `bson:"-" json:"translation,omitempty"`
 }
 
 // Volunteer represents a volunteer's profile
 type Volunteer struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Skills      []string          `bson:"skills" json:"skills"`
-	Interests   []string          `bson:"interests" json:"interests"`
-	Description string            `bson:"description" json:"description"`
-	Availability []Availability    `bson:"availability" json:"availability"`
-	Location    Location          `bson:"location" json:"location"`
-	Embedding   []float32         `bson:"embedding,omitempty" json:"-"`
-	Rating      float64           `bson:"rating" json:"rating"`
-	TaskCount   int               `bson:"task_count" json:"task_count"`
-	CreatedAt   time.Time         `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time         `bson:"updated_at" json:"updated_at"`
+	ID           primitive.ObjectID      `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID      `bson:"user_id" json:"user_id"`
+	Skills       []string                `bson:"skills" json:"skills"`
+	Interests    []string                `bson:"interests" json:"interests"`
+	Categories   []string                `bson:"categories,omitempty" json:"categories,omitempty"` // category paths the volunteer is matched on; none means all
+	Description  string                  `bson:"description" json:"description"`
+	Availability []Availability          `bson:"availability" json:"availability"`
+	Exceptions   []AvailabilityException `bson:"availability_exceptions,omitempty" json:"availability_exceptions,omitempty"` // date-specific overrides of the weekly availability
+	Timezone     string                  `bson:"timezone,omitempty" json:"timezone,omitempty"`                               // IANA zone availability is given in; empty is UTC
+	Location     Location                `bson:"location" json:"location"`
+	MatchCell    string                  `bson:"match_cell,omitempty" json:"-"` // H3 cell of the exact coordinates at MatchCellResolution, for narrowing matching
+	Embedding    []float32               `bson:"embedding,omitempty" json:"-"`
+	Rating       float64                 `bson:"rating" json:"rating"` // the reputation's average
+	Reputation   *Reputation             `bson:"reputation,omitempty" json:"reputation,omitempty"`
+	Trust        *TrustScore             `bson:"trust,omitempty" json:"trust,omitempty"`
+	Kudos        *KudosSummary           `bson:"kudos,omitempty" json:"kudos,omitempty"`
+	Points       int                     `bson:"points,omitempty" json:"points"`
+	Streak       *Streak                 `bson:"streak,omitempty" json:"streak,omitempty"`
+	Photo        *Attachment             `bson:"photo,omitempty" json:"photo,omitempty"` // the user's profile photo
+	Themes       *FeedbackThemes         `bson:"feedback_themes,omitempty" json:"-"`
+	TaskCount    int                     `bson:"task_count" json:"task_count"`
+	Hidden       bool                    `bson:"hidden,omitempty" json:"hidden,omitempty"` // hidden by a moderator
+	Suspension   *MatchingSuspension     `bson:"matching_suspension,omitempty" json:"-"`
+	CreatedAt    time.Time               `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time               `bson:"updated_at" json:"updated_at"`
 }
 
 // Availability represents when a volunteer is available
 type Availability struct {
 	DayOfWeek int    `bson:"day_of_week" json:"day_of_week"` // 0=Sunday, 1=Monday, etc.
-	StartTime string `bson:"start_time" json:"start_time"`    // "09:00"
-	EndTime   string `bson:"end_time" json:"end_time"`        // "17:00"
+	StartTime string `bson:"start_time" json:"start_time"`   // "09:00"
+	EndTime   string `bson:"end_time" json:"end_time"`       // "17:00"
 }
 
 // Task represents a matched need that is being worked on
 type Task struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	NeedID       primitive.ObjectID `bson:"need_id" json:"need_id"`
-	VolunteerID  primitive.ObjectID `bson:"volunteer_id" json:"volunteer_id"`
-	Status       string            `bson:"status" json:"status"` // accepted, in_progress, completed, cancelled
-	ScheduledAt  *time.Time        `bson:"scheduled_at,omitempty" json:"scheduled_at,omitempty"`
-	CompletedAt  *time.Time        `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
-	Notes        string            `bson:"notes,omitempty" json:"notes,omitempty"`
-	CreatedAt    time.Time         `bson:"created_at" json:"created_at"`
-	UpdatedAt    time.Time         `bson:"updated_at" json:"updated_at"`
-}
-
-// Feedback represents feedback given after task completion
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	NeedID      primitive.ObjectID  `bson:"need_id" json:"need_id"`
+	VolunteerID primitive.ObjectID  `bson:"volunteer_id" json:"volunteer_id"`
+	OfferID     *primitive.ObjectID `bson:"offer_id,omitempty" json:"offer_id,omitempty"` // set when the need's creator accepted a standing offer
+	Status      TaskStatus          `bson:"status" json:"status"`                         // accepted, in_progress, awaiting_confirmation, completed, cancelled
+	ScheduledAt *time.Time          `bson:"scheduled_at,omitempty" json:"scheduled_at,omitempty"`
+	CompletedAt *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	Notes       string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	Scored      bool                `bson:"scored,omitempty" json:"-"` // volunteer points have been awarded
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+	// Confirmation tracks each participant marking the task completed
+	Confirmation *TaskConfirmation `bson:"confirmation,omitempty" json:"confirmation,omitempty"`
+}
+
+// TaskConfirmation records when each participant marked a task completed.
+// The task completes once both have, or at DueAt if the other participant
+// has not answered by then.
+type TaskConfirmation struct {
+	VolunteerAt *time.Time `bson:"volunteer_at,omitempty" json:"volunteer_at,omitempty"`
+	RequesterAt *time.Time `bson:"requester_at,omitempty" json:"requester_at,omitempty"`
+	DueAt       time.Time  `bson:"due_at" json:"due_at"`
+	Lapsed      bool       `bson:"lapsed,omitempty" json:"lapsed,omitempty"` // completed at DueAt without the other participant
+}
+
+// Feedback represents feedback given after task completion. It is hidden
+// from the recipient, and left out of their reputation, until both
+// participants have rated each other or the reveal window has passed.
+// Feedback a moderator hides, such as on a successful appeal, is left out
+// for good.
 type Feedback struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	TaskID       primitive.ObjectID `bson:"task_id" json:"task_id"`
-	FromUserID   primitive.ObjectID `bson:"from_user_id" json:"from_user_id"`
-	ToUserID     primitive.ObjectID `bson:"to_user_id" json:"to_user_id"`
-	Rating       int               `bson:"rating" json:"rating"` // 1-5 stars
-	Comment      string            `bson:"comment,omitempty" json:"comment,omitempty"`
-	CreatedAt    time.Time         `bson:"created_at" json:"created_at"`
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TaskID      primitive.ObjectID `bson:"task_id" json:"task_id"`
+	FromUserID  primitive.ObjectID `bson:"from_user_id" json:"from_user_id"`
+	ToUserID    primitive.ObjectID `bson:"to_user_id" json:"to_user_id"`
+	Rating      int                `bson:"rating" json:"rating"` // 1-5 stars
+	Comment     string             `bson:"comment,omitempty" json:"comment,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	RevealedAt  *time.Time         `bson:"revealed_at,omitempty" json:"revealed_at,omitempty"`
+	Response    *FeedbackResponse  `bson:"response,omitempty" json:"response,omitempty"`
+	Appeal      *FeedbackAppeal    `bson:"appeal,omitempty" json:"appeal,omitempty"`
+	Testimonial *Testimonial       `bson:"testimonial,omitempty" json:"testimonial,omitempty"`
+	Incident    *FeedbackIncident  `bson:"incident,omitempty" json:"incident,omitempty"` // shown to its author and moderators only
+	FromUser    *UserSummary       `bson:"-" json:"from_user,omitempty"`                 // the author as shown to the viewer
+	Hidden      bool               `bson:"hidden,omitempty" json:"hidden,omitempty"`     // hidden by a moderator
 }
 
 // Match represents a potential match between a need and volunteer
 type Match struct {
 	NeedID      primitive.ObjectID `bson:"need_id" json:"need_id"`
 	VolunteerID primitive.ObjectID `bson:"volunteer_id" json:"volunteer_id"`
-	Score       float64            `bson:"score" json:"score"` // similarity score
-	Distance    float64            `bson:"distance" json:"distance"` // distance in meters
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UserID      primitive.ObjectID `bson:"user_id,omitempty" json:"-"` // the volunteer's account
+	Score       float64            `bson:"score" json:"score"`         // similarity score
+	Distance    float64            `bson:"distance" json:"distance"`   // distance in meters
+	// DistanceDisplay is the distance in the reader's preferred units
+	DistanceDisplay *Distance        `bson:"-" json:"distance_display,omitempty"`
+	Priority        bool             `bson:"priority,omitempty" json:"priority,omitempty"` // need is in a category an active emergency prioritizes
+	Trust           float64          `bson:"trust,omitempty" json:"trust"`                 // the volunteer's trust score
+	TrustLevel      TrustLevel       `bson:"trust_level,omitempty" json:"trust_level,omitempty"`
+	Translation     *NeedTranslation `bson:"-" json:"translation,omitempty"` // the need in the volunteer's preferred language, when that differs from the need's own
+	// Need and Volunteer embed display details of either side, so clients
+	// need not fetch them one by one
+	Need      *NeedSummary      `bson:"-" json:"need,omitempty"`
+	Volunteer *VolunteerSummary `bson:"-" json:"volunteer,omitempty"`
+	Features  *MatchFeatures    `bson:"-" json:"-"` // what the score was computed from, for the decision log
+	// Components are what the score multiplies together, for explaining it
+	Components *MatchComponents `bson:"components,omitempty" json:"components,omitempty"`
+	// State is where the match stands with the volunteer, kept in the
+	// matches collection across recomputations
+	State     MatchState `bson:"state,omitempty" json:"state,omitempty"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// MatchComponents are the parts of a match's score, which is their product
+// weighed by the volunteer's reputation
+type MatchComponents struct {
+	Similarity    float64 `bson:"similarity" json:"similarity"`         // semantic similarity of the need and the volunteer's profile
+	DistanceScore float64 `bson:"distance_score" json:"distance_score"` // 1 next door, falling with distance
+	Availability  float64 `bson:"availability" json:"availability"`     // how well the volunteer's availability suits the need
+	CategoryMatch bool    `bson:"category_match" json:"category_match"` // the need is in a category the volunteer chose; false when they take every category
+}
+
+// MatchExplanation breaks a match's score down in words for the volunteer
+type MatchExplanation struct {
+	NeedID     primitive.ObjectID `json:"need_id"`
+	Score      float64            `json:"score"`
+	Components MatchComponents    `json:"components"`
+	Reasons    []MatchReason      `json:"reasons"`
+}
+
+// MatchReason is one part of a match explanation
+type MatchReason struct {
+	Component string   `json:"component"`       // similarity, distance, availability, category, or priority
+	Score     *float64 `json:"score,omitempty"` // the component's score, for those that are scored
+	Text      string   `json:"text"`
 }
 
 // WebSocketMessage represents a message sent via WebSocket
@@ -106,6 +314,13 @@ type WebSocketMessage struct {
 	UserID  string      `json:"user_id,omitempty"`
 }
 
+// WebSocketCommand is a message a client sends over its WebSocket to
+// subscribe to or unsubscribe from a topic
+type WebSocketCommand struct {
+	Type  string `json:"type"` // subscribe, unsubscribe
+	Topic string `json:"topic"`
+}
+
 // API Response structures
 type AuthResponse struct {
 	Token        string `json:"token"`
@@ -114,22 +329,35 @@ type AuthResponse struct {
 }
 
 type NeedResponse struct {
-	Need    Need     `json:"need"`
-	Matches []Match  `json:"matches,omitempty"`
+	Need    Need    `json:"need"`
+	Matches []Match `json:"matches,omitempty"`
 }
 
 type VolunteerResponse struct {
 	Volunteer Volunteer `json:"volunteer"`
 	Matches   []Match   `json:"matches,omitempty"`
+	// NextCursor pages through the matches; nil on the last page
+	NextCursor *string `json:"next_cursor"`
 }
 
 // Request structures
 type RegisterRequest struct {
-	Email    string   `json:"email" binding:"required,email"`
-	Password string   `json:"password" binding:"required,min=6"`
-	Name     string   `json:"name" binding:"required"`
-	Phone    string   `json:"phone,omitempty"`
+	Email    string   `json:"email" binding:"required,email,max=254"`
+	Password string   `json:"password" binding:"required,min=6,max=72"`
+	Name     string   `json:"name" binding:"required,max=100"`
+	Phone    string   `json:"phone,omitempty" binding:"max=32"`
 	Location Location `json:"location" binding:"required"`
+	// DateOfBirth (YYYY-MM-DD) is required for individuals
+	DateOfBirth string      `json:"date_of_birth,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	AccountType AccountType `json:"account_type,omitempty" binding:"omitempty,enum"`
+	// Supervisor details are required for youth group accounts
+	SupervisorName  string `json:"supervisor_name,omitempty" binding:"max=100"`
+	SupervisorEmail string `json:"supervisor_email,omitempty" binding:"omitempty,email,max=254"`
+	// InviteCode attributes the sign-up to the neighbor who invited them
+	InviteCode string `json:"invite_code,omitempty" binding:"max=32"`
+	// Language defaults to the one negotiated from Accept-Language
+	Language Language `json:"language,omitempty" binding:"omitempty,enum"`
+	Units    Units    `json:"units,omitempty" binding:"omitempty,enum"`
 }
 
 type LoginRequest struct {
@@ -137,30 +365,124 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// ForgotPasswordRequest asks for a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+// ResetPasswordRequest sets a new password with an emailed reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required,max=128"`
+	Password string `json:"password" binding:"required,min=6,max=72"`
+}
+
+// DeleteAccountRequest confirms a user's request to erase their account
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
 type CreateNeedRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Category    string   `json:"category" binding:"required"`
-	Urgency     string   `json:"urgency" binding:"required"`
+	Title       string   `json:"title" binding:"required,max=200"`
+	Description string   `json:"description" binding:"required,max=5000"`
+	Category    Category `json:"category" binding:"required,category"`
+	Urgency     Urgency  `json:"urgency" binding:"required,enum"`
 	Duration    int      `json:"duration" binding:"required"`
 	Location    Location `json:"location" binding:"required"`
+	// Subcategory is the path of a managed subcategory beneath Category
+	Subcategory string `json:"subcategory,omitempty" binding:"max=200"`
+	// MinTrust limits a need in a sensitive category to volunteers of at
+	// least this trust level
+	MinTrust TrustLevel `json:"min_trust,omitempty" binding:"omitempty,enum"`
+	// Language defaults to the creator's preferred language
+	Language Language `json:"language,omitempty" binding:"omitempty,enum"`
+	// Window is when help is needed, if the requester has a time in mind
+	Window *TimeWindow `json:"window,omitempty"`
+}
+
+// TimeWindow is a span of time when a need should be done
+type TimeWindow struct {
+	Start time.Time `bson:"start" json:"start" binding:"required"`
+	End   time.Time `bson:"end" json:"end" binding:"required,gtfield=Start"`
+}
+
+// DraftNeedRequest is free-form text to turn into a need for the user to confirm
+type DraftNeedRequest struct {
+	Text string `json:"text" binding:"required,max=2000"`
+	// Timezone is the IANA zone relative times such as "Thursday morning"
+	// are read in; it defaults to UTC
+	Timezone string `json:"timezone,omitempty" binding:"max=64"`
 }
 
 type CreateVolunteerRequest struct {
-	Skills      []string       `json:"skills" binding:"required"`
-	Interests   []string       `json:"interests"`
-	Description string         `json:"description" binding:"required"`
+	Skills       []string       `json:"skills" binding:"required,max=50,dive,max=64"`
+	Interests    []string       `json:"interests" binding:"max=50,dive,max=64"`
+	Description  string         `json:"description" binding:"required,max=5000"`
 	Availability []Availability `json:"availability"`
-	Location    Location       `json:"location" binding:"required"`
+	Timezone     string         `json:"timezone,omitempty" binding:"max=64"`
+	Location     Location       `json:"location" binding:"required"`
+	// Categories limits matching to needs within these category paths
+	Categories []string `json:"categories,omitempty" binding:"max=50,dive,max=200"`
 }
 
 type UpdateTaskStatusRequest struct {
-	Status      string     `json:"status" binding:"required"`
+	Status      TaskStatus `json:"status" binding:"required,enum"`
 	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
-	Notes       string     `json:"notes,omitempty"`
+	Notes       string     `json:"notes,omitempty" binding:"max=2000"`
 }
 
 type FeedbackRequest struct {
 	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
-	Comment string `json:"comment,omitempty"`
+	Comment string `json:"comment,omitempty" binding:"max=2000"`
+	// Shareable offers the comment as a public testimonial, shown once the
+	// recipient approves it
+	Shareable bool `json:"shareable,omitempty"`
+	// Incident reports a safety incident for moderators to review,
+	// separately from the rating
+	Incident *IncidentRequest `json:"incident,omitempty"`
+}
+
+// Bulk request structures
+type BulkCancelNeedsRequest struct {
+	NeedIDs []string `json:"need_ids" binding:"required,min=1,max=100"`
+}
+
+type BulkTagNeedsRequest struct {
+	NeedIDs []string `json:"need_ids" binding:"required,min=1,max=100"`
+	Add     []string `json:"add,omitempty" binding:"max=20"`
+	Remove  []string `json:"remove,omitempty" binding:"max=20"`
+}
+
+type BulkTaskStatusUpdate struct {
+	TaskID string     `json:"task_id" binding:"required"`
+	Status TaskStatus `json:"status" binding:"required,enum"`
+	Notes  string     `json:"notes,omitempty" binding:"max=2000"`
+}
+
+type BulkUpdateTaskStatusRequest struct {
+	Updates []BulkTaskStatusUpdate `json:"updates" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkResult reports the outcome for one item of a bulk request
+type BulkResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkResponse reports per-item outcomes of a bulk request
+type BulkResponse struct {
+	Results   []BulkResult `json:"results"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+}
+
+// Add records the outcome for one item of a bulk request
+func (r *BulkResponse) Add(id string, err error) {
+	if err != nil {
+		r.Results = append(r.Results, BulkResult{ID: id, Error: err.Error()})
+		r.Failed++
+		return
+	}
+	r.Results = append(r.Results, BulkResult{ID: id, Success: true})
+	r.Succeeded++
 } 
\ No newline at end of file
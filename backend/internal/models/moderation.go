@@ -0,0 +1,140 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContentType is a kind of user-generated content that can be moderated
+type ContentType string
+
+// Moderated content types
+const (
+	ContentNeed     ContentType = "need"
+	ContentProfile  ContentType = "profile"
+	ContentMessage  ContentType = "message"
+	ContentUser     ContentType = "user"
+	ContentPost     ContentType = "post"
+	ContentOffer    ContentType = "offer"
+	ContentFeedback ContentType = "feedback"
+	ContentKudos    ContentType = "kudos"
+)
+
+var contentTypes = []string{"need", "profile", "message", "user", "post", "offer", "feedback", "kudos"}
+
+// Valid reports whether t is a known content type
+func (t ContentType) Valid() bool { return contains(contentTypes, string(t)) }
+
+// Values lists the known content types
+func (t ContentType) Values() []string { return contentTypes }
+
+// ModerationStatus is where a flagged item is in review
+type ModerationStatus string
+
+// Moderation statuses; pending and escalated items are still open
+const (
+	ModerationPending   ModerationStatus = "pending"
+	ModerationEscalated ModerationStatus = "escalated"
+	ModerationApproved  ModerationStatus = "approved"
+	ModerationEdited    ModerationStatus = "edited"
+	ModerationHidden    ModerationStatus = "hidden"
+)
+
+var moderationStatuses = []string{"pending", "escalated", "approved", "edited", "hidden"}
+
+// Valid reports whether s is a known moderation status
+func (s ModerationStatus) Valid() bool { return contains(moderationStatuses, string(s)) }
+
+// Values lists the known moderation statuses
+func (s ModerationStatus) Values() []string { return moderationStatuses }
+
+// Open reports whether the item still awaits a moderator decision
+func (s ModerationStatus) Open() bool {
+	return s == ModerationPending || s == ModerationEscalated
+}
+
+// Moderation sources
+const (
+	ModerationSourceReport    = "report"
+	ModerationSourceAutomated = "automated"
+	ModerationSourceAppeal    = "appeal"   // the recipient of a low rating appealed it
+	ModerationSourceIncident  = "incident" // a safety incident was reported in feedback
+)
+
+// ModerationItem is one piece of flagged content in the review queue. Repeat
+// flags on content that is still open are merged into the same item.
+type ModerationItem struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ContentType ContentType         `bson:"content_type" json:"content_type"`
+	ContentID   primitive.ObjectID  `bson:"content_id" json:"content_id"`
+	OwnerID     primitive.ObjectID  `bson:"owner_id" json:"owner_id"`
+	Status      ModerationStatus    `bson:"status" json:"status"`
+	Sources     []string            `bson:"sources" json:"sources"`                     // report, automated
+	Reasons     []string            `bson:"reasons,omitempty" json:"reasons,omitempty"` // automated findings
+	ReportCount int                 `bson:"report_count" json:"report_count"`
+	Snapshot    string              `bson:"snapshot" json:"snapshot"` // flagged text when first queued
+	ReviewedBy  *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	Note        string              `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+	DueAt       time.Time           `bson:"due_at" json:"due_at"` // SLA deadline for a decision
+	EscalatedAt *time.Time          `bson:"escalated_at,omitempty" json:"escalated_at,omitempty"`
+	ResolvedAt  *time.Time          `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+}
+
+// ReportStatus is where a user's report is in its lifecycle
+type ReportStatus string
+
+// Report statuses; open and reviewing reports are unresolved
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportReviewing ReportStatus = "reviewing"
+	ReportActioned  ReportStatus = "actioned"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+var reportStatuses = []string{"open", "reviewing", "actioned", "dismissed"}
+
+// Valid reports whether s is a known report status
+func (s ReportStatus) Valid() bool { return contains(reportStatuses, string(s)) }
+
+// Values lists the known report statuses
+func (s ReportStatus) Values() []string { return reportStatuses }
+
+// AbuseReport is a user's report against a need, profile, message, or user.
+// Reports on the same content share one moderation item and are resolved
+// together when a moderator decides on it.
+type AbuseReport struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReporterID       primitive.ObjectID `bson:"reporter_id" json:"reporter_id"`
+	ContentType      ContentType        `bson:"content_type" json:"content_type"`
+	ContentID        primitive.ObjectID `bson:"content_id" json:"content_id"`
+	SubjectID        primitive.ObjectID `bson:"subject_id" json:"subject_id"` // user responsible for the content
+	ModerationItemID primitive.ObjectID `bson:"moderation_item_id" json:"moderation_item_id"`
+	Reason           string             `bson:"reason" json:"reason"`
+	Details          string             `bson:"details,omitempty" json:"details,omitempty"`
+	Status           ReportStatus       `bson:"status" json:"status"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+	ResolvedAt       *time.Time         `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+}
+
+// ReportContentRequest flags content for moderator review
+type ReportContentRequest struct {
+	ContentType ContentType `json:"content_type" binding:"required,enum"`
+	ContentID   string      `json:"content_id" binding:"required"`
+	Reason      string      `json:"reason" binding:"required,max=100"`
+	Details     string      `json:"details,omitempty" binding:"max=2000"`
+}
+
+// ModerationDecisionRequest carries the moderator's note for a decision
+type ModerationDecisionRequest struct {
+	Note string `json:"note,omitempty" binding:"max=2000"`
+}
+
+// ModerationEditRequest replaces text fields of flagged content
+type ModerationEditRequest struct {
+	Fields map[string]string `json:"fields" binding:"required,min=1"`
+	Note   string            `json:"note,omitempty" binding:"max=2000"`
+} 
\ No newline at end of file
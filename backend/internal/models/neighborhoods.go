@@ -0,0 +1,12 @@
+package models
+
+// NeighborhoodHealth compares demand and supply within one H3 region
+type NeighborhoodHealth struct {
+	Region          string         `json:"region"` // H3 cell
+	OpenNeeds       int            `json:"open_needs"`
+	UnmatchedNeeds  int            `json:"unmatched_needs"` // open needs nobody has accepted yet
+	Volunteers      int            `json:"volunteers"`
+	Gap             int            `json:"gap"` // unmatched needs minus volunteers; positive means under-served
+	NeedsByCategory map[string]int `json:"needs_by_category"`
+	UnmetCategories []string       `json:"unmet_categories"` // categories with unmatched needs and no volunteer offering them
+} 
\ No newline at end of file
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification is a message pushed to a user, kept so they can catch up on
+// what they missed while offline. Payload is the message's payload as it
+// was sent over the WebSocket.
+type Notification struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID     `bson:"user_id" json:"-"`
+	Type      string                 `bson:"type" json:"type"`
+	Payload   map[string]interface{} `bson:"payload,omitempty" json:"payload"`
+	ReadAt    *time.Time             `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`
+	Key       string                 `bson:"key,omitempty" json:"-"` // the delivery that stored it, which stores it only once
+}
+
+// NotificationPreferences choose which events a user is emailed about. The
+// zero value emails every event.
+type NotificationPreferences struct {
+	MuteNeedAccepted  bool `bson:"mute_need_accepted,omitempty" json:"mute_need_accepted"`   // a volunteer accepted one of their needs
+	MuteTaskCompleted bool `bson:"mute_task_completed,omitempty" json:"mute_task_completed"` // a task they requested or volunteered for was completed
+	MuteNewMatches    bool `bson:"mute_new_matches,omitempty" json:"mute_new_matches"`       // a new need matched their volunteer profile
+	MuteNeedExpired   bool `bson:"mute_need_expired,omitempty" json:"mute_need_expired"`     // one of their needs expired before anyone took it on
+}
+
+// UpdateNotificationPreferencesRequest changes notification preferences;
+// omitted fields are left unchanged
+type UpdateNotificationPreferencesRequest struct {
+	MuteNeedAccepted  *bool `json:"mute_need_accepted,omitempty"`
+	MuteTaskCompleted *bool `json:"mute_task_completed,omitempty"`
+	MuteNewMatches    *bool `json:"mute_new_matches,omitempty"`
+	MuteNeedExpired   *bool `json:"mute_need_expired,omitempty"`
+} 
\ No newline at end of file
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// OAuthProvider is an identity provider users can sign in with
+type OAuthProvider string
+
+// OAuth providers
+const (
+	OAuthGoogle OAuthProvider = "google"
+	OAuthApple  OAuthProvider = "apple"
+)
+
+var oauthProviders = []string{"google", "apple"}
+
+// Valid reports whether p is a known OAuth provider
+func (p OAuthProvider) Valid() bool { return contains(oauthProviders, string(p)) }
+
+// Values lists the known OAuth providers
+func (p OAuthProvider) Values() []string { return oauthProviders }
+
+// DisplayName is the provider's name as shown to users
+func (p OAuthProvider) DisplayName() string {
+	switch p {
+	case OAuthGoogle:
+		return "Google"
+	case OAuthApple:
+		return "Apple"
+	}
+	return string(p)
+}
+
+// LinkedAccount is a provider account that signs a user in, linked the
+// first time they signed in with it
+type LinkedAccount struct {
+	Provider OAuthProvider `bson:"provider" json:"provider"`
+	Subject  string        `bson:"subject" json:"-"` // the provider's stable ID for the account
+	LinkedAt time.Time     `bson:"linked_at" json:"linked_at"`
+} 
\ No newline at end of file
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OfferStatus is whether an offer is open to new needs
+type OfferStatus string
+
+// Offer statuses
+const (
+	OfferActive OfferStatus = "active"
+	OfferPaused OfferStatus = "paused" // kept, but not browsed or matched
+	OfferClosed OfferStatus = "closed"
+)
+
+var offerStatuses = []string{"active", "paused", "closed"}
+
+// Valid reports whether s is a known offer status
+func (s OfferStatus) Valid() bool { return contains(offerStatuses, string(s)) }
+
+// Values lists the known offer statuses
+func (s OfferStatus) Values() []string { return offerStatuses }
+
+// Offer is a volunteer's standing offer of help, such as free math tutoring
+// on Tuesdays: the inverse of a need. Neighbors with a matching need accept
+// it, which creates a task with the offerer as volunteer.
+type Offer struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Title        string             `bson:"title" json:"title"`
+	Description  string             `bson:"description" json:"description"`
+	Category     Category           `bson:"category" json:"category"`
+	Schedule     string             `bson:"schedule,omitempty" json:"schedule,omitempty"` // free text, e.g. "Tuesdays 4-6pm"
+	Location     Location           `bson:"location" json:"location"`
+	Neighborhood string             `bson:"neighborhood,omitempty" json:"neighborhood,omitempty"` // H3 cell at neighborhood resolution
+	Status       OfferStatus        `bson:"status" json:"status"`
+	Accepted     int                `bson:"accepted" json:"accepted"` // tasks created from the offer
+	Embedding    []float32          `bson:"embedding,omitempty" json:"-"`
+	Hidden       bool               `bson:"hidden,omitempty" json:"hidden,omitempty"` // hidden by a moderator
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+	// OffererName is filled in for the viewer
+	OffererName string `bson:"-" json:"offerer_name,omitempty"`
+}
+
+// OfferMatch pairs an offer with a need it could meet
+type OfferMatch struct {
+	OfferID  primitive.ObjectID `json:"offer_id"`
+	NeedID   primitive.ObjectID `json:"need_id"`
+	Score    float64            `json:"score"`
+	Distance float64            `json:"distance"` // meters
+	// DistanceDisplay is the distance in the reader's preferred units
+	DistanceDisplay *Distance `json:"distance_display,omitempty"`
+	Priority        bool      `json:"priority,omitempty"` // the need is in a category an emergency prioritizes
+	Offer           *Offer    `json:"offer,omitempty"`
+	Need            *Need     `json:"need,omitempty"`
+}
+
+// CreateOfferRequest posts a standing offer
+type CreateOfferRequest struct {
+	Title       string   `json:"title" binding:"required,max=200"`
+	Description string   `json:"description" binding:"required,max=5000"`
+	Category    Category `json:"category" binding:"required,category"`
+	Schedule    string   `json:"schedule,omitempty" binding:"max=200"`
+	Location    Location `json:"location" binding:"required"`
+}
+
+// UpdateOfferRequest changes an offer; omitted fields are left alone
+type UpdateOfferRequest struct {
+	Title       *string     `json:"title,omitempty" binding:"omitempty,max=200"`
+	Description *string     `json:"description,omitempty" binding:"omitempty,max=5000"`
+	Category    Category    `json:"category,omitempty" binding:"omitempty,category"`
+	Schedule    *string     `json:"schedule,omitempty" binding:"omitempty,max=200"`
+	Location    *Location   `json:"location,omitempty"`
+	Status      OfferStatus `json:"status,omitempty" binding:"omitempty,enum"`
+}
+
+// AcceptOfferRequest takes up an offer for one of the current user's open
+// needs
+type AcceptOfferRequest struct {
+	NeedID string `json:"need_id" binding:"required"`
+} 
\ No newline at end of file
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxTopic is what an outbox event asks a worker to do
+type OutboxTopic string
+
+// Outbox topics
+const (
+	OutboxRatings OutboxTopic = "ratings" // recompute the subject user's reputation and task count
+)
+
+// OutboxEvent records that something changed for a worker to act on. Events
+// for the same topic and subject are coalesced until a worker claims them,
+// and handling one must be idempotent: a claim that is not completed within
+// its lease is retried.
+type OutboxEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Topic     OutboxTopic        `bson:"topic" json:"topic"`
+	SubjectID primitive.ObjectID `bson:"subject_id" json:"subject_id"`
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	LastError string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ClaimedAt *time.Time         `bson:"claimed_at,omitempty" json:"claimed_at,omitempty"`
+} 
\ No newline at end of file
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PartnerOrganization is an outside agency, such as a case management
+// system, allowed to refer clients through the intake API with its own API
+// key
+type PartnerOrganization struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name          string              `bson:"name" json:"name"`
+	ContactEmail  string              `bson:"contact_email,omitempty" json:"contact_email,omitempty"` // shared with volunteers on referred needs
+	ContactPhone  EncryptedString     `bson:"contact_phone,omitempty" json:"contact_phone,omitempty"`
+	KeyPrefix     string              `bson:"key_prefix" json:"key_prefix"` // start of the API key, to tell keys apart
+	KeyHash       string              `bson:"key_hash" json:"-"`
+	WebhookURL    string              `bson:"webhook_url,omitempty" json:"webhook_url,omitempty"` // receives referral status events
+	WebhookSecret string              `bson:"webhook_secret,omitempty" json:"-"`
+	CreatedBy     primitive.ObjectID  `bson:"created_by" json:"created_by"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+	RevokedAt     *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedBy     *primitive.ObjectID `bson:"revoked_by,omitempty" json:"revoked_by,omitempty"`
+}
+
+// PartnerCredentials are an organization's API key and webhook signing
+// secret, shown only when they are issued
+type PartnerCredentials struct {
+	Organization  *PartnerOrganization `json:"organization"`
+	APIKey        string               `json:"api_key"`
+	WebhookSecret string               `json:"webhook_secret"`
+}
+
+// PartnerReferral marks a need a partner created on behalf of a client
+// without an account. The need is owned by the organization, and the
+// client's details go only to the volunteer who takes it on.
+type PartnerReferral struct {
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	ExternalID     string             `bson:"external_id" json:"-"` // the partner's own case or referral ID
+	ClientName     EncryptedString    `bson:"client_name,omitempty" json:"-"`
+	ClientPhone    EncryptedString    `bson:"client_phone,omitempty" json:"-"`
+	ReportedStatus ReferralStatus     `bson:"reported_status,omitempty" json:"-"` // last status sent to the partner
+	Final          bool               `bson:"final,omitempty" json:"-"`           // a final status was sent, so no more are
+}
+
+// ReferralStatus is a referred need's progress as reported to its partner
+type ReferralStatus string
+
+// Referral statuses
+const (
+	ReferralPendingReview ReferralStatus = "pending_review" // held by moderation
+	ReferralOpen          ReferralStatus = "open"           // waiting for a volunteer
+	ReferralMatched       ReferralStatus = "matched"        // a volunteer accepted
+	ReferralInProgress    ReferralStatus = "in_progress"
+	ReferralCompleted     ReferralStatus = "completed"
+	ReferralCancelled     ReferralStatus = "cancelled"
+	ReferralExpired       ReferralStatus = "expired"
+)
+
+var referralStatuses = []string{"pending_review", "open", "matched", "in_progress", "completed", "cancelled", "expired"}
+
+// Valid reports whether s is a known referral status
+func (s ReferralStatus) Valid() bool { return contains(referralStatuses, string(s)) }
+
+// Values lists the known referral statuses
+func (s ReferralStatus) Values() []string { return referralStatuses }
+
+// Final reports whether no further status follows s
+func (s ReferralStatus) Final() bool {
+	return s == ReferralCompleted || s == ReferralCancelled || s == ReferralExpired
+}
+
+// ReferralState is what a partner sees of a referred need
+type ReferralState struct {
+	ExternalID  string             `json:"external_id"`
+	NeedID      primitive.ObjectID `json:"need_id"`
+	Status      ReferralStatus     `json:"status"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	AcceptedAt  *time.Time         `json:"accepted_at,omitempty"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+}
+
+// CreatePartnerRequest registers a partner organization
+type CreatePartnerRequest struct {
+	Name         string `json:"name" binding:"required,max=200"`
+	ContactEmail string `json:"contact_email,omitempty" binding:"omitempty,email,max=254"`
+	ContactPhone string `json:"contact_phone,omitempty" binding:"max=32"`
+	WebhookURL   string `json:"webhook_url,omitempty" binding:"omitempty,url,max=500"`
+}
+
+// UpdatePartnerRequest changes a partner organization; omitted fields are
+// left alone and an empty webhook URL turns status events off
+type UpdatePartnerRequest struct {
+	Name         *string `json:"name,omitempty" binding:"omitempty,max=200"`
+	ContactEmail *string `json:"contact_email,omitempty" binding:"omitempty,email,max=254"`
+	ContactPhone *string `json:"contact_phone,omitempty" binding:"omitempty,max=32"`
+	WebhookURL   *string `json:"webhook_url,omitempty" binding:"omitempty,max=500"`
+}
+
+// CreateReferralRequest is a partner's structured referral for a client.
+// Resending an external ID returns the existing referral.
+type CreateReferralRequest struct {
+	ExternalID  string   `json:"external_id" binding:"required,max=100"`
+	Title       string   `json:"title" binding:"required,max=200"`
+	Description string   `json:"description" binding:"required,max=5000"`
+	Category    Category `json:"category" binding:"required,category"`
+	Urgency     Urgency  `json:"urgency" binding:"required,enum"`
+	Duration    int      `json:"duration" binding:"required,min=1"`
+	Location    Location `json:"location" binding:"required"`
+	ClientName  string   `json:"client_name,omitempty" binding:"max=100"`
+	ClientPhone string   `json:"client_phone,omitempty" binding:"max=32"`
+} 
\ No newline at end of file
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PointsReason explains why a completed task earned fewer points than its
+// rules give, if it did
+type PointsReason string
+
+// Points reasons
+const (
+	PointsSelfCreated PointsReason = "self_created" // the volunteer created the need; no points
+	PointsDailyCap    PointsReason = "daily_cap"    // trimmed to what was left of the day's cap
+)
+
+// PointsEntry is the ledger line for one completed task. Every completed
+// task gets exactly one, including those that earned nothing.
+type PointsEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TaskID    primitive.ObjectID `bson:"task_id" json:"task_id"`
+	Points    int                `bson:"points" json:"points"`
+	Reason    PointsReason       `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"` // when the task was completed
+}
+
+// Streak counts consecutive weeks, Monday to Sunday UTC, in which a
+// volunteer completed at least one task
+type Streak struct {
+	Current  int `bson:"current" json:"current"`
+	Longest  int `bson:"longest" json:"longest"`
+	LastWeek int `bson:"last_week" json:"-"` // weeks since the Monday before the Unix epoch
+}
+
+// PointsSummary is a volunteer's points, streak, and latest ledger lines
+type PointsSummary struct {
+	Points int           `json:"points"`
+	Streak Streak        `json:"streak"`
+	Recent []PointsEntry `json:"recent"`
+}
+
+// LeaderboardEntry is one volunteer's place on a leaderboard
+type LeaderboardEntry struct {
+	Rank   int                `json:"rank"`
+	UserID primitive.ObjectID `json:"user_id"`
+	Name   string             `json:"name"`
+	Points int                `json:"points"`
+	Streak int                `json:"streak"`
+} 
\ No newline at end of file
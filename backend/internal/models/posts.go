@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrganizerVerification marks a user an admin has verified as a community
+// organizer, who may post to neighborhood feeds
+type OrganizerVerification struct {
+	VerifiedBy primitive.ObjectID `bson:"verified_by" json:"verified_by"`
+	VerifiedAt time.Time          `bson:"verified_at" json:"verified_at"`
+}
+
+// CanPost reports whether the user may post to neighborhood feeds: verified
+// organizers and admins
+func (u *User) CanPost() bool {
+	return u.Organizer != nil || u.HasRole(RoleAdmin)
+}
+
+// UpdateOrganizerRequest verifies or revokes a community organizer
+type UpdateOrganizerRequest struct {
+	Verified bool `json:"verified"`
+}
+
+// PostReaction is a reader's reaction to a post
+type PostReaction string
+
+// Post reactions
+const (
+	ReactionLike    PostReaction = "like"
+	ReactionThanks  PostReaction = "thanks"
+	ReactionHelpful PostReaction = "helpful"
+)
+
+var postReactions = []string{"like", "thanks", "helpful"}
+
+// Valid reports whether r is a known reaction
+func (r PostReaction) Valid() bool { return contains(postReactions, string(r)) }
+
+// Values lists the known reactions
+func (r PostReaction) Values() []string { return postReactions }
+
+// Post is an organizer's update to a neighborhood, such as a road closure
+// or a cooling center opening, kept separate from needs
+type Post struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	AuthorID     primitive.ObjectID   `bson:"author_id" json:"author_id"`
+	Neighborhood string               `bson:"neighborhood" json:"neighborhood"` // H3 cell at neighborhood resolution
+	Title        string               `bson:"title" json:"title"`
+	Body         string               `bson:"body" json:"body"`
+	Reactions    map[PostReaction]int `bson:"reactions,omitempty" json:"reactions"`
+	Hidden       bool                 `bson:"hidden,omitempty" json:"hidden,omitempty"` // taken down, or held for review
+	CreatedAt    time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time            `bson:"updated_at" json:"updated_at"`
+	// AuthorName and MyReaction are filled in for the viewer
+	AuthorName string       `bson:"-" json:"author_name,omitempty"`
+	MyReaction PostReaction `bson:"-" json:"my_reaction,omitempty"`
+}
+
+// PostReactionRecord is one user's reaction to a post
+type PostReactionRecord struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PostID    primitive.ObjectID `bson:"post_id" json:"post_id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Reaction  PostReaction       `bson:"reaction" json:"reaction"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// CreatePostRequest posts to a neighborhood feed; without a neighborhood the
+// post goes to the author's own
+type CreatePostRequest struct {
+	Title        string `json:"title" binding:"required,max=200"`
+	Body         string `json:"body" binding:"required,max=5000"`
+	Neighborhood string `json:"neighborhood,omitempty" binding:"max=16"`
+}
+
+// ReactRequest sets the current user's reaction to a post
+type ReactRequest struct {
+	Reaction PostReaction `json:"reaction" binding:"required,enum"`
+} 
\ No newline at end of file
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnonymousName is shown in place of the name of a user who hides it
+const AnonymousName = "Neighbor"
+
+// NameDisplay is how a user's name is shown to other users
+type NameDisplay string
+
+// Name display options
+const (
+	NameDisplayFull         NameDisplay = "full"          // "Jane Doe"
+	NameDisplayFirstInitial NameDisplay = "first_initial" // "Jane D."
+	NameDisplayAnonymous    NameDisplay = "anonymous"     // AnonymousName
+)
+
+var nameDisplays = []string{"full", "first_initial", "anonymous"}
+
+// Valid reports whether d is a known name display option
+func (d NameDisplay) Valid() bool { return contains(nameDisplays, string(d)) }
+
+// Values lists the known name display options
+func (d NameDisplay) Values() []string { return nameDisplays }
+
+// LocationPrecision is how precisely a user's location is stored and shown
+// to users they have no accepted task with
+type LocationPrecision string
+
+// Location precision options
+const (
+	LocationPrecisionBlock        LocationPrecision = "block"        // "share my block"
+	LocationPrecisionNeighborhood LocationPrecision = "neighborhood" // "share my neighborhood"
+)
+
+var locationPrecisions = []string{"block", "neighborhood"}
+
+// Valid reports whether p is a known location precision
+func (p LocationPrecision) Valid() bool { return contains(locationPrecisions, string(p)) }
+
+// Values lists the known location precisions
+func (p LocationPrecision) Values() []string { return locationPrecisions }
+
+// PrivacySettings control what other users see of a user. The zero value
+// shows everything.
+type PrivacySettings struct {
+	NameDisplay          NameDisplay       `bson:"name_display,omitempty" json:"name_display,omitempty"`
+	HideRating           bool              `bson:"hide_rating,omitempty" json:"hide_rating"`
+	HideFromSearch       bool              `bson:"hide_from_search,omitempty" json:"hide_from_search"`               // left out of volunteer matches shown to need creators
+	HideFromLeaderboards bool              `bson:"hide_from_leaderboards,omitempty" json:"hide_from_leaderboards"`   // left out of public rankings
+	LocationPrecision    LocationPrecision `bson:"location_precision,omitempty" json:"location_precision,omitempty"` // empty uses the deployment default
+	HideContact          bool              `bson:"hide_contact,omitempty" json:"hide_contact"`                       // phone and email withheld even from task partners
+}
+
+// UpdatePrivacyRequest changes privacy settings; omitted fields are left unchanged
+type UpdatePrivacyRequest struct {
+	NameDisplay          *NameDisplay       `json:"name_display,omitempty" binding:"omitempty,enum"`
+	HideRating           *bool              `json:"hide_rating,omitempty"`
+	HideFromSearch       *bool              `json:"hide_from_search,omitempty"`
+	HideFromLeaderboards *bool              `json:"hide_from_leaderboards,omitempty"`
+	LocationPrecision    *LocationPrecision `json:"location_precision,omitempty" binding:"omitempty,enum"`
+	HideContact          *bool              `json:"hide_contact,omitempty"`
+}
+
+// Apply returns settings with the request's non-nil fields applied
+func (r UpdatePrivacyRequest) Apply(settings PrivacySettings) PrivacySettings {
+	if r.NameDisplay != nil {
+		settings.NameDisplay = *r.NameDisplay
+	}
+	if r.HideRating != nil {
+		settings.HideRating = *r.HideRating
+	}
+	if r.HideFromSearch != nil {
+		settings.HideFromSearch = *r.HideFromSearch
+	}
+	if r.HideFromLeaderboards != nil {
+		settings.HideFromLeaderboards = *r.HideFromLeaderboards
+	}
+	if r.LocationPrecision != nil {
+		settings.LocationPrecision = *r.LocationPrecision
+	}
+	if r.HideContact != nil {
+		settings.HideContact = *r.HideContact
+	}
+	return settings
+}
+
+// ContactDetails are how to reach a task partner outside the app. Phone and
+// Email are empty when the partner keeps contact in the app. On needs a
+// partner organization referred, they are the client's, backed by the
+// organization's, and UserID is the organization's ID.
+type ContactDetails struct {
+	UserID       primitive.ObjectID `json:"user_id"`
+	Name         string             `json:"name"`
+	Phone        string             `json:"phone,omitempty"`
+	Email        string             `json:"email,omitempty"`
+	InAppOnly    bool               `json:"in_app_only"`
+	Organization string             `json:"organization,omitempty"` // the referring partner organization
+}
+
+// DisplayName returns the user's name as other users see it
+func (u *User) DisplayName() string {
+	switch u.Privacy.NameDisplay {
+	case NameDisplayAnonymous:
+		return AnonymousName
+	case NameDisplayFirstInitial:
+		parts := strings.Fields(u.Name)
+		if len(parts) < 2 {
+			return u.Name
+		}
+		last := []rune(parts[len(parts)-1])
+		return parts[0] + " " + string(last[0]) + "."
+	}
+	return u.Name
+} 
\ No newline at end of file
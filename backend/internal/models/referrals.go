@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invite is a code, or link carrying it, that a user shares to bring
+// neighbors onto the platform. Sign-ups through it are attributed to the
+// inviter and the neighborhood the invite was made in.
+type Invite struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code         string             `bson:"code" json:"code"`
+	InviterID    primitive.ObjectID `bson:"inviter_id" json:"inviter_id"`
+	Neighborhood string             `bson:"neighborhood,omitempty" json:"neighborhood,omitempty"` // inviter's H3 cell at neighborhood resolution
+	MaxUses      int                `bson:"max_uses,omitempty" json:"max_uses,omitempty"`         // zero is unlimited
+	Uses         int                `bson:"uses" json:"uses"`
+	ExpiresAt    *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt    *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	// Link is the shareable sign-up link, when the deployment has one
+	Link string `bson:"-" json:"link,omitempty"`
+}
+
+// Referral attributes a sign-up to the invite it came through
+type Referral struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	InviteID     primitive.ObjectID `bson:"invite_id" json:"invite_id"`
+	InviterID    primitive.ObjectID `bson:"inviter_id" json:"inviter_id"`
+	ReferredID   primitive.ObjectID `bson:"referred_id" json:"referred_id"`
+	Neighborhood string             `bson:"neighborhood,omitempty" json:"neighborhood,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// BadgeKind is what a badge was earned for
+type BadgeKind string
+
+// Badge kinds
+const (
+	BadgeReferrer BadgeKind = "referrer" // Level is the number of sign-ups referred
+	BadgeKudos    BadgeKind = "kudos"    // Level is the number of kudos received
+)
+
+// Badge is a reward shown on a user's profile
+type Badge struct {
+	Kind      BadgeKind `bson:"kind" json:"kind"`
+	Level     int       `bson:"level" json:"level"`
+	AwardedAt time.Time `bson:"awarded_at" json:"awarded_at"`
+}
+
+// ReferralStats summarizes a user's invites and the sign-ups they brought
+type ReferralStats struct {
+	ActiveInvites int     `json:"active_invites"`
+	SignUps       int64   `json:"sign_ups"`
+	NextBadgeAt   int     `json:"next_badge_at,omitempty"` // sign-ups needed for the next referrer badge
+	Badges        []Badge `json:"badges"`
+}
+
+// NeighborhoodReferrals counts referred sign-ups in a neighborhood
+type NeighborhoodReferrals struct {
+	Neighborhood string `bson:"_id" json:"neighborhood"`
+	SignUps      int    `bson:"sign_ups" json:"sign_ups"`
+	Referrers    int    `bson:"referrers" json:"referrers"`
+}
+
+// CreateInviteRequest creates an invite; zero values mean unlimited uses
+// and no expiry
+type CreateInviteRequest struct {
+	MaxUses       int `json:"max_uses,omitempty" binding:"min=0,max=1000"`
+	ExpiresInDays int `json:"expires_in_days,omitempty" binding:"min=0,max=365"`
+} 
\ No newline at end of file
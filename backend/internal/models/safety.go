@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Age limits
+const (
+	AdultAge   = 18 // users younger than this are minors
+	MinimumAge = 13 // users younger than this cannot register
+)
+
+// DateOfBirthLayout is the format of dates of birth in requests
+const DateOfBirthLayout = "2006-01-02"
+
+// AccountType is whether an account belongs to a person or a group
+type AccountType string
+
+// Account types
+const (
+	AccountIndividual AccountType = "individual"
+	AccountYouthGroup AccountType = "youth_group" // e.g. a scout troop or school club, acting under adult supervision
+)
+
+var accountTypes = []string{"individual", "youth_group"}
+
+// Valid reports whether t is a known account type
+func (t AccountType) Valid() bool { return contains(accountTypes, string(t)) }
+
+// Values lists the known account types
+func (t AccountType) Values() []string { return accountTypes }
+
+// sensitiveCategories are the categories in which a minor may not take part
+// in a one-to-one task, on either side
+var sensitiveCategories = []string{"childcare", "eldercare", "transportation", "companionship"}
+
+// Sensitive reports whether minors are kept out of tasks in c
+func (c Category) Sensitive() bool { return contains(sensitiveCategories, string(c)) }
+
+// SensitiveCategories lists the categories minors are kept out of
+func SensitiveCategories() []string { return sensitiveCategories }
+
+// AdultSupervision names the adult responsible for a youth group account.
+// The group cannot post or take on needs until an admin has verified it.
+type AdultSupervision struct {
+	SupervisorName  string              `bson:"supervisor_name" json:"supervisor_name"`
+	SupervisorEmail string              `bson:"supervisor_email" json:"supervisor_email"`
+	VerifiedBy      *primitive.ObjectID `bson:"verified_by,omitempty" json:"verified_by,omitempty"`
+	VerifiedAt      *time.Time          `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
+}
+
+// Verified reports whether an admin has verified the supervising adult
+func (s *AdultSupervision) Verified() bool {
+	return s != nil && s.VerifiedAt != nil
+}
+
+// Age returns the user's age in whole years at now, and false if their date
+// of birth is unknown
+func (u *User) Age(now time.Time) (int, bool) {
+	if u.DateOfBirth == nil {
+		return 0, false
+	}
+	return yearsBetween(*u.DateOfBirth, now), true
+}
+
+// IsMinor reports whether the user is known to be under AdultAge. Youth
+// group accounts count as minors.
+func (u *User) IsMinor(now time.Time) bool {
+	if u.AccountType == AccountYouthGroup {
+		return true
+	}
+	age, known := u.Age(now)
+	return known && age < AdultAge
+}
+
+// yearsBetween returns the number of whole years from birth to now
+func yearsBetween(birth, now time.Time) int {
+	years := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		years--
+	}
+	return years
+}
+
+// UpdateSupervisionRequest verifies or revokes a youth group's supervising adult
+type UpdateSupervisionRequest struct {
+	Verified bool `json:"verified"`
+} 
\ No newline at end of file
@@ -0,0 +1,342 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"neighborenexus/internal/sanitize"
+)
+
+// Sanitizer is implemented by request payloads carrying user-supplied text.
+// Sanitize strips markup in place and reports required fields left empty.
+type Sanitizer interface {
+	Sanitize() error
+}
+
+// Sanitize cleans the registration payload
+func (r *RegisterRequest) Sanitize() error {
+	r.Name = sanitize.Text(r.Name)
+	r.Phone = sanitize.Text(r.Phone)
+	r.Location.Address = sanitize.Text(r.Location.Address)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the need payload
+func (r *CreateNeedRequest) Sanitize() error {
+	r.Title = sanitize.Text(r.Title)
+	r.Description = sanitize.Text(r.Description)
+	r.Location.Address = sanitize.Text(r.Location.Address)
+	switch {
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Description == "":
+		return errors.New("description is required")
+	case r.MinTrust != "" && !r.Category.Sensitive():
+		return errors.New("min_trust can only be set on needs in these categories: " + strings.Join(SensitiveCategories(), ", "))
+	}
+	return nil
+}
+
+// Sanitize cleans the volunteer profile payload
+func (r *CreateVolunteerRequest) Sanitize() error {
+	r.Skills = sanitize.Strings(r.Skills)
+	r.Interests = sanitize.Strings(r.Interests)
+	r.Description = sanitize.Text(r.Description)
+	r.Location.Address = sanitize.Text(r.Location.Address)
+	switch {
+	case len(r.Skills) == 0:
+		return errors.New("at least one skill is required")
+	case r.Description == "":
+		return errors.New("description is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the task status payload
+func (r *UpdateTaskStatusRequest) Sanitize() error {
+	r.Notes = sanitize.Text(r.Notes)
+	return nil
+}
+
+// Sanitize cleans the feedback payload
+func (r *FeedbackRequest) Sanitize() error {
+	r.Comment = sanitize.Text(r.Comment)
+	if r.Incident != nil {
+		r.Incident.Description = sanitize.Text(r.Incident.Description)
+		if r.Incident.Description == "" {
+			return errors.New("incident description is required")
+		}
+	}
+	return nil
+}
+
+// Sanitize cleans the notes of each status update
+func (r *BulkUpdateTaskStatusRequest) Sanitize() error {
+	for i := range r.Updates {
+		r.Updates[i].Notes = sanitize.Text(r.Updates[i].Notes)
+	}
+	return nil
+}
+
+// Sanitize cleans the report payload
+func (r *ReportContentRequest) Sanitize() error {
+	r.Reason = sanitize.Text(r.Reason)
+	r.Details = sanitize.Text(r.Details)
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the announcement payload
+func (r *CreateAnnouncementRequest) Sanitize() error {
+	r.Title = sanitize.Text(r.Title)
+	r.Body = sanitize.Text(r.Body)
+	switch {
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Body == "":
+		return errors.New("body is required")
+	case r.PublishAt != nil && r.ExpiresAt != nil && !r.ExpiresAt.After(*r.PublishAt):
+		return errors.New("expires_at must be after publish_at")
+	}
+	return nil
+}
+
+// Sanitize cleans the group payload
+func (r *CreateGroupRequest) Sanitize() error {
+	r.Name = sanitize.Text(r.Name)
+	r.Description = sanitize.Text(r.Description)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the group update payload
+func (r *UpdateGroupRequest) Sanitize() error {
+	if r.Name != nil {
+		*r.Name = sanitize.Text(*r.Name)
+		if *r.Name == "" {
+			return errors.New("name cannot be empty")
+		}
+	}
+	if r.Description != nil {
+		*r.Description = sanitize.Text(*r.Description)
+	}
+	return nil
+}
+
+// Sanitize cleans the event payload and checks its times and capacity
+func (r *CreateEventRequest) Sanitize() error {
+	r.Title = sanitize.Text(r.Title)
+	r.Description = sanitize.Text(r.Description)
+	r.Location.Address = sanitize.Text(r.Location.Address)
+	for i := range r.Shifts {
+		r.Shifts[i].Name = sanitize.Text(r.Shifts[i].Name)
+	}
+	switch {
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Description == "":
+		return errors.New("description is required")
+	case !r.EndsAt.After(r.StartsAt):
+		return errors.New("ends_at must be after starts_at")
+	case len(r.Shifts) == 0 && r.Capacity == 0:
+		return errors.New("capacity is required for events without shifts")
+	}
+	for _, shift := range r.Shifts {
+		if !shift.EndsAt.After(shift.StartsAt) {
+			return errors.New("each shift must end after it starts")
+		}
+		if shift.StartsAt.Before(r.StartsAt) || shift.EndsAt.After(r.EndsAt) {
+			return errors.New("shifts must fall within the event")
+		}
+	}
+	return nil
+}
+
+// Sanitize cleans the material cost payload
+func (r *SetMaterialCostRequest) Sanitize() error {
+	r.Description = sanitize.Text(r.Description)
+	return nil
+}
+
+// Sanitize cleans the payout payload
+func (r *MarkPayoutPaidRequest) Sanitize() error {
+	r.Reference = sanitize.Text(r.Reference)
+	if r.Reference == "" {
+		return errors.New("reference is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the emergency payload
+func (r *DeclareEmergencyRequest) Sanitize() error {
+	r.Title = sanitize.Text(r.Title)
+	r.Message = sanitize.Text(r.Message)
+	switch {
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Message == "":
+		return errors.New("message is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the post payload
+func (r *CreatePostRequest) Sanitize() error {
+	r.Title = sanitize.Text(r.Title)
+	r.Body = sanitize.Text(r.Body)
+	switch {
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Body == "":
+		return errors.New("body is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the offer payload
+func (r *CreateOfferRequest) Sanitize() error {
+	r.Title = sanitize.Text(r.Title)
+	r.Description = sanitize.Text(r.Description)
+	r.Schedule = sanitize.Text(r.Schedule)
+	r.Location.Address = sanitize.Text(r.Location.Address)
+	switch {
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Description == "":
+		return errors.New("description is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the offer update payload
+func (r *UpdateOfferRequest) Sanitize() error {
+	if r.Title != nil {
+		*r.Title = sanitize.Text(*r.Title)
+		if *r.Title == "" {
+			return errors.New("title cannot be empty")
+		}
+	}
+	if r.Description != nil {
+		*r.Description = sanitize.Text(*r.Description)
+		if *r.Description == "" {
+			return errors.New("description cannot be empty")
+		}
+	}
+	if r.Schedule != nil {
+		*r.Schedule = sanitize.Text(*r.Schedule)
+	}
+	if r.Location != nil {
+		r.Location.Address = sanitize.Text(r.Location.Address)
+	}
+	return nil
+}
+
+// Sanitize cleans the partner organization payload
+func (r *CreatePartnerRequest) Sanitize() error {
+	r.Name = sanitize.Text(r.Name)
+	r.ContactPhone = sanitize.Text(r.ContactPhone)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the partner organization update payload
+func (r *UpdatePartnerRequest) Sanitize() error {
+	if r.Name != nil {
+		*r.Name = sanitize.Text(*r.Name)
+		if *r.Name == "" {
+			return errors.New("name cannot be empty")
+		}
+	}
+	if r.ContactPhone != nil {
+		*r.ContactPhone = sanitize.Text(*r.ContactPhone)
+	}
+	return nil
+}
+
+// Sanitize cleans the partner referral payload
+func (r *CreateReferralRequest) Sanitize() error {
+	r.ExternalID = sanitize.Text(r.ExternalID)
+	r.Title = sanitize.Text(r.Title)
+	r.Description = sanitize.Text(r.Description)
+	r.ClientName = sanitize.Text(r.ClientName)
+	r.ClientPhone = sanitize.Text(r.ClientPhone)
+	r.Location.Address = sanitize.Text(r.Location.Address)
+	switch {
+	case r.ExternalID == "":
+		return errors.New("external_id is required")
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Description == "":
+		return errors.New("description is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the feedback response
+func (r *FeedbackResponseRequest) Sanitize() error {
+	r.Text = sanitize.Text(r.Text)
+	if r.Text == "" {
+		return errors.New("text is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the feedback appeal
+func (r *FeedbackAppealRequest) Sanitize() error {
+	r.Reason = sanitize.Text(r.Reason)
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the kudos message and drops repeated tags
+func (r *KudosRequest) Sanitize() error {
+	r.Message = sanitize.Text(r.Message)
+	seen := map[KudosTag]bool{}
+	tags := r.Tags[:0]
+	for _, tag := range r.Tags {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	r.Tags = tags
+	if len(r.Tags) == 0 && r.Message == "" {
+		return errors.New("at least one tag or a message is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the category payload
+func (r *CreateCategoryRequest) Sanitize() error {
+	r.Name = sanitize.Text(r.Name)
+	r.Description = sanitize.Text(r.Description)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Sanitize cleans the category update payload
+func (r *UpdateCategoryRequest) Sanitize() error {
+	if r.Name != nil {
+		*r.Name = sanitize.Text(*r.Name)
+		if *r.Name == "" {
+			return errors.New("name cannot be empty")
+		}
+	}
+	if r.Description != nil {
+		*r.Description = sanitize.Text(*r.Description)
+	}
+	return nil
+} 
\ No newline at end of file
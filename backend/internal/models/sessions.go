@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Session is one sign-in on one device. It lasts as long as its refresh
+// token, is extended each time the token is rotated, and ends when the user
+// logs out or revokes it.
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"` // when the refresh token was last rotated
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current marks the session making the request
+	Current bool `json:"current,omitempty"`
+}
+
+// SessionClient describes the device signing in
+type SessionClient struct {
+	UserAgent string
+	IP        string
+} 
\ No newline at end of file
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// SLAMilestone is a step a need passes on its way to being met
+type SLAMilestone string
+
+// SLA milestones, in the order a need reaches them
+const (
+	MilestoneFirstMatch SLAMilestone = "first_match" // volunteers were first matched and notified
+	MilestoneAccepted   SLAMilestone = "accepted"    // a volunteer first took the need on
+	MilestoneCompleted  SLAMilestone = "completed"   // a task for the need was first completed
+)
+
+// SLAMilestones lists the milestones in the order a need reaches them
+var SLAMilestones = []SLAMilestone{MilestoneFirstMatch, MilestoneAccepted, MilestoneCompleted}
+
+// Field returns the need field recording when the milestone was reached
+func (m SLAMilestone) Field() string {
+	return "sla." + string(m) + "_at"
+}
+
+// NeedSLA records when a need reached each milestone and whether urgent
+// needs missed their targets
+type NeedSLA struct {
+	FirstMatchAt     *time.Time     `bson:"first_match_at,omitempty" json:"first_match_at,omitempty"`
+	AcceptedAt       *time.Time     `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	CompletedAt      *time.Time     `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	Breached         []SLAMilestone `bson:"breached,omitempty" json:"breached,omitempty"`                   // milestones reached late or not at all
+	EscalatedAt      *time.Time     `bson:"escalated_at,omitempty" json:"escalated_at,omitempty"`           // when matching was widened and coordinators alerted
+	RadiusMultiplier float64        `bson:"radius_multiplier,omitempty" json:"radius_multiplier,omitempty"` // stretches the matching distance once escalated
+}
+
+// Reached returns when the milestone was reached, or nil
+func (s *NeedSLA) Reached(milestone SLAMilestone) *time.Time {
+	if s == nil {
+		return nil
+	}
+	switch milestone {
+	case MilestoneFirstMatch:
+		return s.FirstMatchAt
+	case MilestoneAccepted:
+		return s.AcceptedAt
+	case MilestoneCompleted:
+		return s.CompletedAt
+	}
+	return nil
+}
+
+// EscalationRadius returns how far an escalated need's matching reaches
+// beyond the usual distance, or 1 for needs that were not escalated
+func (n *Need) EscalationRadius() float64 {
+	if n.SLA == nil || n.SLA.RadiusMultiplier <= 0 {
+		return 1
+	}
+	return n.SLA.RadiusMultiplier
+}
+
+// SLAMetrics summarizes how quickly needs created in a period reached each
+// milestone, by urgency
+type SLAMetrics struct {
+	From      time.Time           `json:"from"`
+	To        time.Time           `json:"to"`
+	Urgencies []UrgencySLAMetrics `json:"urgencies"`
+}
+
+// UrgencySLAMetrics summarizes the needs of one urgency
+type UrgencySLAMetrics struct {
+	Urgency    Urgency            `json:"urgency"`
+	Needs      int                `json:"needs"`
+	Escalated  int                `json:"escalated"`
+	Milestones []MilestoneMetrics `json:"milestones"`
+}
+
+// MilestoneMetrics summarizes the time from creation to one milestone
+type MilestoneMetrics struct {
+	Milestone     SLAMilestone `json:"milestone"`
+	Reached       int          `json:"reached"`
+	MedianMinutes float64      `json:"median_minutes"`
+	P90Minutes    float64      `json:"p90_minutes"`
+	TargetMinutes float64      `json:"target_minutes,omitempty"` // only urgent needs have targets
+	Breached      int          `json:"breached"`
+} 
\ No newline at end of file
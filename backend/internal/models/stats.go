@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// PlatformStats summarizes platform health for community organizers. Open
+// needs are counted as of now; the rest cover the period from From to To.
+type PlatformStats struct {
+	From               time.Time         `json:"from"`
+	To                 time.Time         `json:"to"`
+	OpenNeeds          int               `json:"open_needs"`
+	OpenNeedsByUrgency map[Urgency]int   `json:"open_needs_by_urgency"`
+	ActiveVolunteers   int               `json:"active_volunteers"` // volunteers who accepted or completed a task
+	CompletedTasks     int               `json:"completed_tasks"`
+	Matches            MatchScoreSummary `json:"matches"`
+}
+
+// MatchScoreSummary summarizes the scores of matches first suggested in a
+// period
+type MatchScoreSummary struct {
+	Count        int     `json:"count"`
+	AverageScore float64 `json:"average_score"`
+	AcceptedRate float64 `json:"accepted_rate"` // share of the matches volunteers accepted
+}
+
+// TimeToMatchStats is the distribution of the time from posting to first
+// match for needs created in a period
+type TimeToMatchStats struct {
+	From      time.Time           `json:"from"`
+	To        time.Time           `json:"to"`
+	Urgency   Urgency             `json:"urgency,omitempty"`
+	Matched   int                 `json:"matched"`
+	Unmatched int                 `json:"unmatched"` // needs not yet matched
+	Buckets   []TimeToMatchBucket `json:"buckets"`
+}
+
+// TimeToMatchBucket counts the needs first matched within a span of minutes
+// after posting. The last bucket has no upper bound.
+type TimeToMatchBucket struct {
+	MinMinutes float64  `json:"min_minutes"`
+	MaxMinutes *float64 `json:"max_minutes"`
+	Needs      int      `json:"needs"`
+} 
\ No newline at end of file
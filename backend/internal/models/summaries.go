@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSummary is how a user is shown embedded in another resource
+type UserSummary struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"` // as the user chose to show it
+}
+
+// Summary returns the user as shown embedded in another resource
+func (u *User) Summary() *UserSummary {
+	return &UserSummary{ID: u.ID, Name: u.DisplayName()}
+}
+
+// NeedSummary is the part of a need shown alongside a match. Its title has
+// contact details redacted, as the volunteer is not yet bound to the need.
+type NeedSummary struct {
+	ID        primitive.ObjectID `json:"id"`
+	Title     string             `json:"title"`
+	Category  Category           `json:"category"`
+	Urgency   Urgency            `json:"urgency"`
+	Duration  int                `json:"duration"`
+	Status    NeedStatus         `json:"status"`
+	Requester *UserSummary       `json:"requester,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+}
+
+// VolunteerSummary is how a volunteer is shown alongside a match
+type VolunteerSummary struct {
+	ID         primitive.ObjectID `json:"id"`
+	User       *UserSummary       `json:"user,omitempty"`
+	Rating     *float64           `json:"rating,omitempty"` // left out when the volunteer hides it
+	TaskCount  int                `json:"task_count"`
+	TrustLevel TrustLevel         `json:"trust_level,omitempty"`
+} 
\ No newline at end of file
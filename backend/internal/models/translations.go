@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"neighborenexus/internal/i18n"
+)
+
+// Language is a language the API is localized into
+type Language string
+
+// Valid reports whether l is a supported language
+func (l Language) Valid() bool { return i18n.Supported(string(l)) }
+
+// Values lists the supported languages
+func (l Language) Values() []string { return i18n.Languages() }
+
+// NeedTranslation is a need's title and description machine translated
+// into another language. It is always labeled as a machine translation so
+// readers know the wording is not the requester's own.
+type NeedTranslation struct {
+	Language       Language  `bson:"language" json:"language"`
+	SourceLanguage Language  `bson:"source_language" json:"source_language"`
+	Title          string    `bson:"title" json:"title"`
+	Description    string    `bson:"description" json:"description"`
+	TranslatedAt   time.Time `bson:"translated_at" json:"translated_at"`
+	// MachineTranslated is always true; Label says so in the translation's language
+	MachineTranslated bool   `bson:"-" json:"machine_translated"`
+	Label             string `bson:"-" json:"label"`
+} 
\ No newline at end of file
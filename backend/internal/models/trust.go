@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TrustLevel buckets a trust score for people to reason about
+type TrustLevel string
+
+// Trust levels, lowest first
+const (
+	TrustNew         TrustLevel = "new"
+	TrustEstablished TrustLevel = "established"
+	TrustTrusted     TrustLevel = "trusted"
+)
+
+var trustLevels = []string{"new", "established", "trusted"}
+
+// Valid reports whether l is a known trust level
+func (l TrustLevel) Valid() bool { return contains(trustLevels, string(l)) }
+
+// Values lists the known trust levels
+func (l TrustLevel) Values() []string { return trustLevels }
+
+// trustRanks orders the trust levels; the empty level, for users not yet
+// scored, ranks below all of them
+var trustRanks = map[TrustLevel]int{TrustNew: 1, TrustEstablished: 2, TrustTrusted: 3}
+
+// AtLeast reports whether l is min or higher. Every level is at least the
+// empty level.
+func (l TrustLevel) AtLeast(min TrustLevel) bool {
+	return trustRanks[l] >= trustRanks[min]
+}
+
+// TrustLevelFor buckets a 0-1 trust score
+func TrustLevelFor(score float64) TrustLevel {
+	switch {
+	case score >= 0.75:
+		return TrustTrusted
+	case score >= 0.5:
+		return TrustEstablished
+	}
+	return TrustNew
+}
+
+// TrustFactors are the inputs a trust score was computed from
+type TrustFactors struct {
+	Verified       bool    `bson:"verified" json:"verified"` // identity, organizer, or youth group supervisor verified by an admin
+	AccountAgeDays int     `bson:"account_age_days" json:"account_age_days"`
+	Completed      int     `bson:"completed" json:"completed"` // tasks completed as volunteer
+	Cancelled      int     `bson:"cancelled" json:"cancelled"` // tasks cancelled as volunteer
+	Reputation     float64 `bson:"reputation" json:"reputation"`
+	UpheldReports  int     `bson:"upheld_reports" json:"upheld_reports"` // reports against the user a moderator acted on
+}
+
+// TrustScore combines verification, account age, reliability, ratings, and
+// dispute history into one 0-1 score. It is kept on both the user and their
+// volunteer profile.
+type TrustScore struct {
+	Score     float64      `bson:"score" json:"score"`
+	Level     TrustLevel   `bson:"level" json:"level"`
+	Factors   TrustFactors `bson:"factors" json:"factors"`
+	UpdatedAt time.Time    `bson:"updated_at" json:"updated_at"`
+}
+
+// IdentityVerification marks a user whose identity an admin has checked
+type IdentityVerification struct {
+	VerifiedBy primitive.ObjectID `bson:"verified_by" json:"verified_by"`
+	VerifiedAt time.Time          `bson:"verified_at" json:"verified_at"`
+}
+
+// UpdateIdentityRequest verifies or revokes a user's identity
+type UpdateIdentityRequest struct {
+	Verified bool `json:"verified"`
+} 
\ No newline at end of file
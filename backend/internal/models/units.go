@@ -0,0 +1,25 @@
+package models
+
+// Units is the measurement system a user reads distances in
+type Units string
+
+// Measurement systems
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+)
+
+var unitSystems = []string{"metric", "imperial"}
+
+// Valid reports whether u is a known measurement system
+func (u Units) Valid() bool { return contains(unitSystems, string(u)) }
+
+// Values lists the known measurement systems
+func (u Units) Values() []string { return unitSystems }
+
+// Distance is a distance rendered for a reader in their preferred units
+type Distance struct {
+	Value float64 `json:"value"` // rounded to one decimal place
+	Unit  string  `json:"unit"`  // km or mi
+	Text  string  `json:"text"`  // value and unit formatted for the reader's locale
+} 
\ No newline at end of file
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadPurpose is what an uploaded file is for, which decides where it may
+// be attached
+type UploadPurpose string
+
+// Upload purposes
+const (
+	UploadPurposeProfilePhoto UploadPurpose = "profile_photo"
+	UploadPurposeNeedImage    UploadPurpose = "need_image"
+)
+
+var uploadPurposes = []string{"profile_photo", "need_image"}
+
+// Valid reports whether p is a known upload purpose
+func (p UploadPurpose) Valid() bool { return contains(uploadPurposes, string(p)) }
+
+// Values lists the known upload purposes
+func (p UploadPurpose) Values() []string { return uploadPurposes }
+
+// MaxNeedImages is how many images a need can carry
+const MaxNeedImages = 5
+
+// Upload is a file a user uploads straight to object storage through a
+// presigned URL. It stays pending until it is checked and attached to a
+// profile or need; pending uploads never attached are deleted.
+type Upload struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"-"`
+	Purpose     UploadPurpose       `bson:"purpose" json:"purpose"`
+	Key         string              `bson:"key" json:"-"` // object key in the bucket
+	ContentType string              `bson:"content_type" json:"content_type"`
+	Size        int64               `bson:"size" json:"size"`
+	Attached    bool                `bson:"attached,omitempty" json:"attached"`
+	NeedID      *primitive.ObjectID `bson:"need_id,omitempty" json:"-"` // the need an attached need image is on
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// Attachment references an upload from the profile or need it is attached
+// to. Its file is served from /uploads/:id.
+type Attachment struct {
+	UploadID    primitive.ObjectID `bson:"upload_id" json:"upload_id"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	AttachedAt  time.Time          `bson:"attached_at" json:"attached_at"`
+}
+
+// CreateUploadRequest asks for a URL to upload an image to. The image must
+// then be sent with exactly this content type and size.
+type CreateUploadRequest struct {
+	Purpose     UploadPurpose `json:"purpose" binding:"required,enum"`
+	ContentType string        `json:"content_type" binding:"required,max=100"`
+	Size        int64         `json:"size" binding:"required,min=1"`
+}
+
+// PresignedUpload is where and how to upload a file. The upload must be
+// sent as a PUT to URL with Headers before ExpiresAt.
+type PresignedUpload struct {
+	Upload    *Upload           `json:"upload"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// SetPhotoRequest attaches an uploaded profile photo to the current user
+type SetPhotoRequest struct {
+	UploadID string `json:"upload_id" binding:"required"`
+}
+
+// SetNeedImagesRequest replaces a need's images with these uploads, in order
+type SetNeedImagesRequest struct {
+	UploadIDs []string `json:"upload_ids" binding:"max=5"` // at most MaxNeedImages
+} 
\ No newline at end of file
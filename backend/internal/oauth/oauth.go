@@ -0,0 +1,239 @@
+// Package oauth signs users in with third-party identity providers such as
+// Google and Apple. It runs the OAuth 2.0 authorization code flow and
+// verifies the OpenID Connect ID token the code is exchanged for.
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidIDToken is returned for an ID token that is not validly signed
+// by the provider for this client, or has expired
+var ErrInvalidIDToken = errors.New("oauth: invalid ID token")
+
+// keySetLifetime is how long a provider's signing keys are reused before
+// they are fetched again. Unknown key IDs are looked up sooner, as providers
+// rotate keys.
+const keySetLifetime = time.Hour
+
+// Identity is who a provider vouches the user is
+type Identity struct {
+	Subject       string // the provider's stable ID for the user
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider is an OpenID Connect identity provider registered with one
+// client ID
+type Provider struct {
+	name        string
+	client      *http.Client
+	clientID    string
+	secret      func() (string, error)
+	redirectURL string
+
+	authURL  string
+	tokenURL string
+	jwksURL  string
+	issuers  []string
+	scopes   []string
+	params   url.Values // extra authorization request parameters
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// Name returns the provider's name, such as google
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the provider's sign-in page, which redirects back to
+// the callback with a code and state. nonce is bound into the ID token.
+func (p *Provider) AuthCodeURL(state, nonce string) string {
+	query := url.Values{}
+	for key, values := range p.params {
+		query[key] = values
+	}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.clientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", strings.Join(p.scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	return p.authURL + "?" + query.Encode()
+}
+
+// Exchange redeems an authorization code and returns the identity in the ID
+// token it yields, which must carry nonce
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (Identity, error) {
+	secret, err := p.secret()
+	if err != nil {
+		return Identity{}, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.clientID},
+		"client_secret": {secret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Identity{}, fmt.Errorf("oauth: %s token exchange failed: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Identity{}, err
+	}
+	if resp.StatusCode >= 300 {
+		return Identity{}, fmt.Errorf("oauth: %s token exchange returned %d: %s", p.name, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil || tokens.IDToken == "" {
+		return Identity{}, fmt.Errorf("oauth: %s token response has no ID token", p.name)
+	}
+	return p.verify(ctx, tokens.IDToken, nonce)
+}
+
+// verify checks an ID token's signature, audience, issuer, expiry, and
+// nonce, and returns the identity it asserts
+func (p *Provider) verify(ctx context.Context, idToken, nonce string) (Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithAudience(p.clientID))
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	issuer, _ := claims.GetIssuer()
+	expiresAt, _ := claims.GetExpirationTime()
+	tokenNonce, _ := claims["nonce"].(string)
+	subject, _ := claims.GetSubject()
+	switch {
+	case !containsString(p.issuers, issuer):
+		return Identity{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, issuer)
+	case expiresAt == nil:
+		return Identity{}, fmt.Errorf("%w: no expiry", ErrInvalidIDToken)
+	case tokenNonce != nonce:
+		return Identity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	case subject == "":
+		return Identity{}, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	identity := Identity{Subject: subject}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	// Apple sends email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	return identity, nil
+}
+
+// key returns the provider's signing key kid, fetching the key set when it
+// is stale or does not have it
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok && time.Since(p.fetchedAt) < keySetLifetime {
+		return key, nil
+	}
+	// Refetching for every unknown kid would let forged tokens hammer the
+	// provider, so the set is fetched at most once a minute
+	if time.Since(p.fetchedAt) > time.Minute {
+		keys, err := p.fetchKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.keys, p.fetchedAt = keys, time.Now()
+	}
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetchKeys downloads the provider's JSON Web Key Set
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth: failed to fetch %s signing keys: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("oauth: %s signing keys returned %d", p.name, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("oauth: invalid %s signing keys: %w", p.name, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// appleClientSecretLifetime is how long each signed Apple client secret is
+// valid; a fresh one is signed for every exchange
+const appleClientSecretLifetime = 5 * time.Minute
+
+// NewGoogle creates the Google provider for an OAuth client ID and secret.
+// redirectURL is the callback registered for the client.
+func NewGoogle(clientID, clientSecret, redirectURL string) *Provider {
+	return &Provider{
+		name:        "google",
+		client:      &http.Client{Timeout: 10 * time.Second},
+		clientID:    clientID,
+		secret:      func() (string, error) { return clientSecret, nil },
+		redirectURL: redirectURL,
+		authURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+		tokenURL:    "https://oauth2.googleapis.com/token",
+		jwksURL:     "https://www.googleapis.com/oauth2/v3/certs",
+		issuers:     []string{"https://accounts.google.com", "accounts.google.com"},
+		scopes:      []string{"openid", "email", "profile"},
+		params:      url.Values{"prompt": {"select_account"}},
+	}
+}
+
+// NewApple creates the Sign in with Apple provider for a Services ID.
+// Apple's client secret is a JWT signed with a .p8 key from the team's
+// developer account. Apple posts the callback as a form, and sends the
+// user's name alongside it only the first time they sign in.
+func NewApple(servicesID, teamID, keyID, keyFile, redirectURL string) (*Provider, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Apple sign-in key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Apple sign-in key: %w", err)
+	}
+
+	secret := func() (string, error) {
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"iss": teamID,
+			"iat": now.Unix(),
+			"exp": now.Add(appleClientSecretLifetime).Unix(),
+			"aud": "https://appleid.apple.com",
+			"sub": servicesID,
+		})
+		token.Header["kid"] = keyID
+		return token.SignedString(key)
+	}
+
+	return &Provider{
+		name:        "apple",
+		client:      &http.Client{Timeout: 10 * time.Second},
+		clientID:    servicesID,
+		secret:      secret,
+		redirectURL: redirectURL,
+		authURL:     "https://appleid.apple.com/auth/authorize",
+		tokenURL:    "https://appleid.apple.com/auth/token",
+		jwksURL:     "https://appleid.apple.com/auth/keys",
+		issuers:     []string{"https://appleid.apple.com"},
+		scopes:      []string{"name", "email"},
+		params:      url.Values{"response_mode": {"form_post"}},
+	}, nil
+} 
\ No newline at end of file
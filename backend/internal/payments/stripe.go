@@ -0,0 +1,151 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stripeAPIBase is the Stripe REST API root
+const stripeAPIBase = "https://api.stripe.com/v1"
+
+// Stripe event types the contribution flow reconciles
+const (
+	EventCheckoutCompleted = "checkout.session.completed"
+	EventCheckoutExpired   = "checkout.session.expired"
+	EventChargeSucceeded   = "charge.succeeded"
+	EventChargeRefunded    = "charge.refunded"
+)
+
+// StripeClient creates Checkout sessions through the Stripe REST API
+type StripeClient struct {
+	client    *http.Client
+	secretKey string
+}
+
+// NewStripeClient creates a Stripe client, or returns nil when secretKey is
+// empty so deployments without Stripe leave contributions switched off
+func NewStripeClient(secretKey string) *StripeClient {
+	if secretKey == "" {
+		return nil
+	}
+	return &StripeClient{
+		client:    &http.Client{Timeout: 15 * time.Second},
+		secretKey: secretKey,
+	}
+}
+
+// CheckoutParams describes a one-off payment through Stripe Checkout
+type CheckoutParams struct {
+	AmountCents   int64
+	Currency      string
+	ProductName   string
+	CustomerEmail string // receives Stripe's receipt
+	SuccessURL    string
+	CancelURL     string
+	Reference     string // client_reference_id, echoed back in webhooks
+	Metadata      map[string]string
+}
+
+// CheckoutSession is the part of a Stripe Checkout session the app uses
+type CheckoutSession struct {
+	ID                string            `json:"id"`
+	URL               string            `json:"url"`
+	PaymentStatus     string            `json:"payment_status"`
+	PaymentIntent     string            `json:"payment_intent"`
+	ClientReferenceID string            `json:"client_reference_id"`
+	AmountTotal       int64             `json:"amount_total"`
+	Currency          string            `json:"currency"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+// Charge is the part of a Stripe charge the app uses
+type Charge struct {
+	ID             string            `json:"id"`
+	PaymentIntent  string            `json:"payment_intent"`
+	AmountRefunded int64             `json:"amount_refunded"`
+	Refunded       bool              `json:"refunded"`
+	ReceiptURL     string            `json:"receipt_url"`
+	Metadata       map[string]string `json:"metadata"` // copied from the payment intent
+}
+
+// Event is a Stripe webhook event; Object is decoded according to Type
+type Event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// APIError is an error response from Stripe
+type APIError struct {
+	Status  int
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Error formats the Stripe error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("stripe: %s (%d): %s", e.Type, e.Status, e.Message)
+}
+
+// CreateCheckoutSession starts a Checkout payment and returns the session,
+// whose URL the payer is sent to
+func (c *StripeClient) CreateCheckoutSession(ctx context.Context, params CheckoutParams) (*CheckoutSession, error) {
+	form := url.Values{
+		"mode":                                   {"payment"},
+		"success_url":                            {params.SuccessURL},
+		"cancel_url":                             {params.CancelURL},
+		"client_reference_id":                    {params.Reference},
+		"line_items[0][quantity]":                {"1"},
+		"line_items[0][price_data][currency]":    {params.Currency},
+		"line_items[0][price_data][unit_amount]": {strconv.FormatInt(params.AmountCents, 10)},
+		"line_items[0][price_data][product_data][name]": {params.ProductName},
+	}
+	if params.CustomerEmail != "" {
+		form.Set("customer_email", params.CustomerEmail)
+		form.Set("payment_intent_data[receipt_email]", params.CustomerEmail)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+		form.Set("payment_intent_data[metadata]["+key+"]", value)
+	}
+
+	var session CheckoutSession
+	if err := c.post(ctx, "/checkout/sessions", form, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// post sends a form-encoded request and decodes the JSON response into out
+func (c *StripeClient) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stripeAPIBase+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var body struct {
+			Error APIError `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		body.Error.Status = resp.StatusCode
+		return &body.Error
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+} 
\ No newline at end of file
@@ -0,0 +1,144 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// APNs hosts
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+)
+
+// apnsTokenLifetime is how long a provider token is reused. Apple rejects
+// tokens older than an hour and refreshing more than every 20 minutes.
+const apnsTokenLifetime = 50 * time.Minute
+
+// APNsClient sends notifications to iOS apps through the Apple Push
+// Notification service, authenticating with a token signing key
+type APNsClient struct {
+	client *http.Client
+	host   string
+	keyID  string
+	teamID string
+	topic  string
+	key    interface{}
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewAPNsClient creates an APNs client from a .p8 signing key. topic is the
+// app's bundle ID; production selects the production environment over the
+// sandbox used by development builds.
+func NewAPNsClient(keyFile, keyID, teamID, topic string, production bool) (*APNsClient, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APNs key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+
+	host := apnsSandboxHost
+	if production {
+		host = apnsProductionHost
+	}
+	// The default transport negotiates the HTTP/2 APNs requires
+	return &APNsClient{
+		client: &http.Client{Timeout: 10 * time.Second},
+		host:   host,
+		keyID:  keyID,
+		teamID: teamID,
+		topic:  topic,
+		key:    key,
+	}, nil
+}
+
+// Send delivers msg to an iOS device
+func (c *APNsClient) Send(ctx context.Context, token string, msg Message) error {
+	providerToken, err := c.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for key, value := range msg.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", c.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode == http.StatusGone || result.Reason == "BadDeviceToken" || result.Reason == "Unregistered" {
+		return ErrInvalidToken
+	}
+	return fmt.Errorf("apns: status %d: %s", resp.StatusCode, result.Reason)
+}
+
+// providerToken returns the signed token APNs authenticates requests with
+func (c *APNsClient) providerToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": c.teamID,
+		"iat": time.Now().Unix(),
+	})
+	token.Header["kid"] = c.keyID
+	signed, err := token.SignedString(c.key)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = signed
+	c.expiresAt = time.Now().Add(apnsTokenLifetime)
+	return c.token, nil
+} 
\ No newline at end of file
@@ -0,0 +1,26 @@
+// Package push sends notifications to mobile and web devices through
+// Firebase Cloud Messaging and the Apple Push Notification service.
+package push
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidToken is returned when the push service no longer accepts a
+// device token, e.g. because the app was uninstalled. The token should be
+// forgotten.
+var ErrInvalidToken = errors.New("push: device token is no longer valid")
+
+// Message is a notification shown on a device. Data is passed to the app
+// alongside it.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers a notification to one device token
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+} 
\ No newline at end of file
@@ -0,0 +1,45 @@
+package sanitize
+
+import (
+	"regexp"
+	"unicode"
+)
+
+// ContactPlaceholder replaces contact details redacted from free text
+const ContactPlaceholder = "[contact hidden]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	// phonePattern finds runs of digits and phone punctuation; runs are only
+	// redacted if they hold as many digits as a phone number
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().\-]{5,}\d`)
+	datePattern  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// Phone numbers have between 7 digits (local) and 15 (E.164)
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+// RedactContacts replaces email addresses and phone numbers in free text with
+// ContactPlaceholder, so users can't exchange contact details before they are
+// bound to a task
+func RedactContacts(s string) string {
+	s = emailPattern.ReplaceAllString(s, ContactPlaceholder)
+	return phonePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if datePattern.MatchString(match) {
+			return match
+		}
+		digits := 0
+		for _, r := range match {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if digits < minPhoneDigits || digits > maxPhoneDigits {
+			return match
+		}
+		return ContactPlaceholder
+	})
+} 
\ No newline at end of file
@@ -0,0 +1,76 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Text strips HTML markup from user-supplied text, dropping the contents of
+// script and style elements, and trims surrounding whitespace. Entities in
+// the text left between tags are decoded once, so the result is the plain
+// text a reader saw; it may still contain < or &, and is escaped wherever
+// it is rendered as HTML.
+func Text(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return strings.TrimSpace(stripControl(s))
+	}
+
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	skipDepth := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(stripControl(b.String()))
+		case html.StartTagToken:
+			if isRawTextElement(tokenizer) {
+				skipDepth++
+			}
+		case html.EndTagToken:
+			if isRawTextElement(tokenizer) && skipDepth > 0 {
+				skipDepth--
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.Write(tokenizer.Text())
+			}
+		}
+	}
+}
+
+// Strings sanitizes each element of a slice and drops empty results
+func Strings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	cleaned := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = Text(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	return cleaned
+}
+
+// isRawTextElement reports whether the current tag's content should be discarded
+func isRawTextElement(tokenizer *html.Tokenizer) bool {
+	name, _ := tokenizer.TagName()
+	switch string(name) {
+	case "script", "style", "iframe", "object", "noscript":
+		return true
+	}
+	return false
+}
+
+// stripControl removes control characters other than newlines and tabs
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+} 
\ No newline at end of file
@@ -0,0 +1,24 @@
+package sanitize
+
+import "testing"
+
+func TestText(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"Tom & Jerry", "Tom & Jerry"},
+		{"I can't", "I can't"},
+		{"I can't <b>x</b>", "I can't x"},
+		{"  Fix the <i>fence</i>\n", "Fix the fence"},
+		{"&lt;script&gt;", "<script>"},
+		{"&amp;lt;b&amp;gt;", "&lt;b&gt;"},
+		{"<<b>script>", "<script>"},
+		{"Hi<script>alert(1)</script> there", "Hi there"},
+		{"<style>p { color: red }</style>Paint", "Paint"},
+		{"Bell\x07 rings", "Bell rings"},
+	} {
+		if got := Text(tc.in); got != tc.want {
+			t.Errorf("Text(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+} 
\ No newline at end of file
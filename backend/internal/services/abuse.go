@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// abuseRule flags text matching a pattern that is common in scams
+type abuseRule struct {
+	reason  string
+	pattern *regexp.Regexp
+}
+
+// abuseRules catch the scams seen on mutual aid boards: asking for money,
+// steering payment off-platform, and harvesting phone numbers
+var abuseRules = []abuseRule{
+	{"money_request", regexp.MustCompile(`(?i)\b(send|lend|loan|wire|transfer|pay|donate)\b[^.!?\n]{0,40}(\$\s?\d|\d+\s?(dollars|usd|bucks)\b|\bmoney\b|\bcash\b|\bfunds\b)`)},
+	{"untraceable_payment", regexp.MustCompile(`(?i)\b(gift ?cards?|western union|moneygram|bitcoin|btc|crypto(currency)?|usdt)\b`)},
+	{"off_platform_payment", regexp.MustCompile(`(?i)(paypal\.me/|venmo\.com/|cash\.app/|\bcash ?app\b|\bvenmo\b|\bzelle\b|(^|\s)\$[a-z][a-z0-9_]{2,})`)},
+	{"phone_harvesting", regexp.MustCompile(`(?i)\b(send|share|give|leave|text|dm)\b[^.!?\n]{0,20}\b(your|ur)\s+(phone|cell|mobile|number)`)},
+	{"phone_harvesting", regexp.MustCompile(`(?i)\b(text|call|whatsapp|message)\s+(me|us)\b[^.!?\n]{0,30}\d{3}[\s.)-]{0,2}\d{3}[\s.-]?\d{4}`)},
+	{"off_platform_contact", regexp.MustCompile(`(?i)\b(whatsapp|telegram|signal app)\b`)},
+}
+
+// AbuseClassifier screens user-written text for scams, solicitation, and
+// content OpenAI's moderation endpoint flags
+type AbuseClassifier struct {
+	client    *openai.Client
+	blocklist []string
+}
+
+// NewAbuseClassifier creates a classifier. Without an API key only the
+// heuristic rules and blocklist are applied.
+func NewAbuseClassifier(apiKey string, blocklist []string) *AbuseClassifier {
+	terms := make([]string, 0, len(blocklist))
+	for _, term := range blocklist {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	classifier := &AbuseClassifier{blocklist: terms}
+	if apiKey != "" {
+		classifier.client = newOpenAIClient(apiKey)
+	}
+	return classifier
+}
+
+// Classify returns the reasons text looks abusive, or nil when it looks fine.
+// OpenAI failures are logged and the heuristic result is used alone.
+func (a *AbuseClassifier) Classify(ctx context.Context, text string) []string {
+	if a == nil || strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	var reasons []string
+	add := func(reason string) {
+		if !contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	for _, rule := range abuseRules {
+		if rule.pattern.MatchString(text) {
+			add(rule.reason)
+		}
+	}
+
+	lower := strings.ToLower(text)
+	for _, term := range a.blocklist {
+		if strings.Contains(lower, term) {
+			add("blocked_term:" + term)
+		}
+	}
+
+	if a.client != nil {
+		categories, err := a.moderate(ctx, text)
+		if err != nil {
+			log.Printf("OpenAI moderation failed, using heuristics only: %v", err)
+		}
+		for _, category := range categories {
+			add("openai:" + category)
+		}
+	}
+
+	return reasons
+}
+
+// moderate returns the OpenAI moderation categories flagged for text
+func (a *AbuseClassifier) moderate(ctx context.Context, text string) ([]string, error) {
+	resp, err := a.client.Moderations(ctx, openai.ModerationRequest{
+		Input: text,
+		Model: openai.ModerationTextLatest,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var flagged []string
+	for _, result := range resp.Results {
+		if !result.Flagged {
+			continue
+		}
+		categories := result.Categories
+		for name, hit := range map[string]bool{
+			"hate":             categories.Hate,
+			"hate/threatening": categories.HateThreatening,
+			"self-harm":        categories.SelfHarm,
+			"sexual":           categories.Sexual,
+			"sexual/minors":    categories.SexualMinors,
+			"violence":         categories.Violence,
+			"violence/graphic": categories.ViolenceGraphic,
+		} {
+			if hit {
+				flagged = append(flagged, name)
+			}
+		}
+		if len(flagged) == 0 {
+			flagged = append(flagged, "flagged")
+		}
+	}
+	sort.Strings(flagged)
+	return flagged, nil
+} 
\ No newline at end of file
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+)
+
+// analyticsRegionResolution is the H3 resolution of the region reported with
+// events; cells average about 36 km²
+const analyticsRegionResolution = 6
+
+// Surfaces on which matches are shown, reported with match_shown events
+const (
+	MatchSurfaceNotification = "notification" // new_need notifications to volunteers
+	MatchSurfaceGraphQL      = "graphql"      // the need creator's match list
+	MatchSurfaceEscalation   = "escalation"   // notifications widened after an urgent need missed a target
+)
+
+// AnalyticsService emits pseudonymized domain events for product analysis.
+// Every ID is replaced by an HMAC under a key that never leaves the API, so
+// the events cannot be joined back to user data by whoever analyzes them.
+type AnalyticsService struct {
+	key     []byte
+	publish func(ctx context.Context, event models.AnalyticsEvent) error
+}
+
+// NewAnalyticsService creates an analytics service that hands events to
+// publish. A nil *AnalyticsService emits nothing.
+func NewAnalyticsService(pseudonymKey string, publish func(ctx context.Context, event models.AnalyticsEvent) error) *AnalyticsService {
+	return &AnalyticsService{
+		key:     []byte(pseudonymKey),
+		publish: publish,
+	}
+}
+
+// Pseudonym returns the stable pseudonym of an ID of the given kind, such as
+// "user" or "need"
+func (s *AnalyticsService) Pseudonym(kind string, id primitive.ObjectID) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(kind + ":" + id.Hex()))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// NeedCreated records that a user posted a need
+func (s *AnalyticsService) NeedCreated(ctx context.Context, need *models.Need) {
+	if s == nil {
+		return
+	}
+	s.emit(ctx, models.AnalyticsNeedCreated, need.UserID, map[string]interface{}{
+		"need":            s.Pseudonym("need", need.ID),
+		"category":        need.Category,
+		"urgency":         need.Urgency,
+		"duration":        need.Duration,
+		"region":          analyticsRegion(need.Location),
+		"held_for_review": need.HeldForReview,
+	})
+}
+
+// MatchesShown records the volunteers matched to a need, in rank order, and
+// where they were shown
+func (s *AnalyticsService) MatchesShown(ctx context.Context, need *models.Need, matches []models.Match, surface string) {
+	if s == nil || len(matches) == 0 {
+		return
+	}
+
+	results := make([]map[string]interface{}, len(matches))
+	for i, match := range matches {
+		results[i] = map[string]interface{}{
+			"volunteer":   s.Pseudonym("volunteer", match.VolunteerID),
+			"rank":        i + 1,
+			"score":       math.Round(match.Score*1000) / 1000,
+			"distance_km": math.Round(match.Distance / 1000),
+		}
+	}
+	s.emit(ctx, models.AnalyticsMatchShown, need.UserID, map[string]interface{}{
+		"need":     s.Pseudonym("need", need.ID),
+		"category": need.Category,
+		"surface":  surface,
+		"matches":  results,
+	})
+}
+
+// MatchAccepted records that a volunteer took on a need
+func (s *AnalyticsService) MatchAccepted(ctx context.Context, need *models.Need, task *models.Task) {
+	if s == nil {
+		return
+	}
+	s.emit(ctx, models.AnalyticsMatchAccepted, task.VolunteerID, map[string]interface{}{
+		"need":              s.Pseudonym("need", need.ID),
+		"creator":           s.Pseudonym("user", need.UserID),
+		"category":          need.Category,
+		"urgency":           need.Urgency,
+		"region":            analyticsRegion(need.Location),
+		"seconds_to_accept": int64(task.CreatedAt.Sub(need.CreatedAt).Seconds()),
+	})
+}
+
+// SLABreached records that an urgent need missed the target for a milestone
+func (s *AnalyticsService) SLABreached(ctx context.Context, need *models.Need, milestone models.SLAMilestone, overdue time.Duration) {
+	if s == nil {
+		return
+	}
+	s.emit(ctx, models.AnalyticsSLABreached, need.UserID, map[string]interface{}{
+		"need":            s.Pseudonym("need", need.ID),
+		"category":        need.Category,
+		"urgency":         need.Urgency,
+		"region":          analyticsRegion(need.Location),
+		"milestone":       milestone,
+		"seconds_overdue": int64(overdue.Seconds()),
+	})
+}
+
+// emit publishes an event caused by actorID. Analytics are best-effort, so
+// failures are logged rather than returned.
+func (s *AnalyticsService) emit(ctx context.Context, eventType models.AnalyticsEventType, actorID primitive.ObjectID, properties map[string]interface{}) {
+	event := models.AnalyticsEvent{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		Actor:      s.Pseudonym("user", actorID),
+		Properties: properties,
+		OccurredAt: time.Now(),
+	}
+	if err := s.publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s analytics event: %v", eventType, err)
+	}
+}
+
+// analyticsRegion returns the coarse H3 cell containing location, or "" if
+// it has none
+func analyticsRegion(location models.Location) string {
+	cell := h3.Cell(h3.IndexFromString(location.H3Index))
+	switch {
+	case location.H3Index != "" && cell.IsValid():
+		if cell.Resolution() > analyticsRegionResolution {
+			cell = cell.Parent(analyticsRegionResolution)
+		}
+	case location.Latitude != 0 || location.Longitude != 0:
+		cell = h3.LatLngToCell(h3.LatLng{Lat: location.Latitude, Lng: location.Longitude}, analyticsRegionResolution)
+	default:
+		return ""
+	}
+	return cell.String()
+} 
\ No newline at end of file
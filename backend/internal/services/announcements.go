@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// defaultAnnouncementResolution is used for region targets given without a resolution
+const defaultAnnouncementResolution = 7
+
+// ErrAnnouncementNotFound is returned for unknown or already cancelled announcements
+var ErrAnnouncementNotFound = errors.New("announcement not found")
+
+// AnnouncementService schedules admin announcements and works out who they reach
+type AnnouncementService struct {
+	mongoClient     *database.MongoClient
+	matchingService *MatchingService
+}
+
+// NewAnnouncementService creates a new announcement service
+func NewAnnouncementService(mongoClient *database.MongoClient, matchingService *MatchingService) *AnnouncementService {
+	return &AnnouncementService{
+		mongoClient:     mongoClient,
+		matchingService: matchingService,
+	}
+}
+
+// Create schedules an announcement
+func (s *AnnouncementService) Create(ctx context.Context, createdBy primitive.ObjectID, req models.CreateAnnouncementRequest) (*models.Announcement, error) {
+	now := time.Now()
+	announcement := models.Announcement{
+		ID:        primitive.NewObjectID(),
+		Title:     req.Title,
+		Body:      req.Body,
+		Target:    req.Target,
+		Status:    models.AnnouncementScheduled,
+		CreatedBy: createdBy,
+		PublishAt: now,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.PublishAt != nil {
+		announcement.PublishAt = *req.PublishAt
+	}
+	if len(announcement.Target.Regions) > 0 && announcement.Target.Resolution == 0 {
+		announcement.Target.Resolution = defaultAnnouncementResolution
+	}
+
+	if _, err := s.mongoClient.GetCollection("announcements").InsertOne(ctx, announcement); err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// List returns announcements matching filter, newest first
+func (s *AnnouncementService) List(ctx context.Context, filter bson.M, limit int64) ([]models.Announcement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "publish_at", Value: -1}}).SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("announcements").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	announcements := []models.Announcement{}
+	if err := cursor.All(ctx, &announcements); err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
+
+// Cancel withdraws an announcement. Published announcements stop showing in
+// feeds and digests, though notifications already sent stay delivered.
+func (s *AnnouncementService) Cancel(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.mongoClient.GetCollection("announcements").UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": bson.M{"$ne": models.AnnouncementCancelled}},
+		bson.M{"$set": bson.M{"status": models.AnnouncementCancelled, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+// Active returns published announcements that have not expired
+func (s *AnnouncementService) Active(ctx context.Context) ([]models.Announcement, error) {
+	return s.List(ctx, bson.M{
+		"status": models.AnnouncementPublished,
+		"$or": []bson.M{
+			{"expires_at": bson.M{"$exists": false}},
+			{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+	}, 0)
+}
+
+// ForUser returns the active announcements targeted at a user
+func (s *AnnouncementService) ForUser(ctx context.Context, userID primitive.ObjectID) ([]models.Announcement, error) {
+	announcements, err := s.Active(ctx)
+	if err != nil || len(announcements) == 0 {
+		return announcements, err
+	}
+
+	var user models.User
+	if err := s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		return nil, err
+	}
+	volunteer, err := s.findVolunteer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Filter(announcements, &user, volunteer), nil
+}
+
+// Filter keeps the announcements targeted at a user; volunteer may be nil
+func (s *AnnouncementService) Filter(announcements []models.Announcement, user *models.User, volunteer *models.Volunteer) []models.Announcement {
+	targeted := []models.Announcement{}
+	for _, announcement := range announcements {
+		if s.Matches(announcement.Target, user, volunteer) {
+			targeted = append(targeted, announcement)
+		}
+	}
+	return targeted
+}
+
+// Matches reports whether a target selects the user; volunteer may be nil
+func (s *AnnouncementService) Matches(target models.AnnouncementTarget, user *models.User, volunteer *models.Volunteer) bool {
+	if len(target.Roles) > 0 {
+		role := user.Role
+		if role == "" {
+			role = models.RoleUser
+		}
+		if !contains(target.Roles, role) && !(volunteer != nil && contains(target.Roles, models.AudienceVolunteer)) {
+			return false
+		}
+	}
+
+	if len(target.Regions) > 0 {
+		location := user.Location
+		if location.Latitude == 0 && location.Longitude == 0 && volunteer != nil {
+			location = volunteer.Location
+		}
+		if location.Latitude == 0 && location.Longitude == 0 {
+			return false
+		}
+		cell := s.matchingService.GenerateH3Index(location.Latitude, location.Longitude, target.Resolution)
+		if !contains(target.Regions, cell) {
+			return false
+		}
+	}
+
+	if len(target.Categories) > 0 {
+		if volunteer == nil {
+			return false
+		}
+		offered := map[string]bool{}
+		for _, term := range append(volunteer.Skills, volunteer.Interests...) {
+			offered[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(term), " ", "_"))] = true
+		}
+		found := false
+		for _, category := range target.Categories {
+			if offered[string(category)] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ClaimDue marks the next scheduled announcement whose publish time has
+// passed as published and returns it, or nil when none is due. The claim is
+// atomic, so concurrent workers publish each announcement once.
+func (s *AnnouncementService) ClaimDue(ctx context.Context) (*models.Announcement, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status":     models.AnnouncementScheduled,
+		"publish_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       models.AnnouncementPublished,
+		"published_at": now,
+		"updated_at":   now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "publish_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var announcement models.Announcement
+	err := s.mongoClient.GetCollection("announcements").FindOneAndUpdate(ctx, filter, update, opts).Decode(&announcement)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// Recipients returns the IDs of every user an announcement targets
+func (s *AnnouncementService) Recipients(ctx context.Context, announcement *models.Announcement) ([]string, error) {
+	volunteers := map[primitive.ObjectID]*models.Volunteer{}
+	cursor, err := s.mongoClient.GetCollection("volunteers").Find(ctx, bson.M{"hidden": bson.M{"$ne": true}}, options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err != nil {
+		return nil, err
+	}
+	for cursor.Next(ctx) {
+		var volunteer models.Volunteer
+		if err := cursor.Decode(&volunteer); err != nil {
+			cursor.Close(ctx)
+			return nil, err
+		}
+		volunteers[volunteer.UserID] = &volunteer
+	}
+	cursor.Close(ctx)
+
+	cursor, err = s.mongoClient.GetCollection("users").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"password": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var userIDs []string
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, err
+		}
+		if s.Matches(announcement.Target, &user, volunteers[user.ID]) {
+			userIDs = append(userIDs, user.ID.Hex())
+		}
+	}
+	return userIDs, cursor.Err()
+}
+
+// MarkDelivered records how many users a published announcement reached
+func (s *AnnouncementService) MarkDelivered(ctx context.Context, id primitive.ObjectID, recipients int) error {
+	_, err := s.mongoClient.GetCollection("announcements").UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"recipients": recipients, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// findVolunteer returns the user's volunteer profile, or nil if they have none
+func (s *AnnouncementService) findVolunteer(ctx context.Context, userID primitive.ObjectID) (*models.Volunteer, error) {
+	var volunteer models.Volunteer
+	err := s.mongoClient.GetCollection("volunteers").FindOne(ctx, bson.M{"user_id": userID, "hidden": bson.M{"$ne": true}}).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &volunteer, nil
+} 
\ No newline at end of file
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// AuditService records sensitive actions and answers queries over them
+type AuditService struct {
+	mongoClient *database.MongoClient
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(mongoClient *database.MongoClient) *AuditService {
+	return &AuditService{
+		mongoClient: mongoClient,
+	}
+}
+
+// auditClientKey is the context key of the client a request came from
+type auditClientKey struct{}
+
+// auditClient is the address and user agent of the client a request came
+// from
+type auditClient struct {
+	ip        string
+	userAgent string
+}
+
+// WithAuditClient returns a copy of ctx carrying the address and user agent
+// of the client a request came from, which Record attributes entries to
+// when they do not name a client of their own
+func WithAuditClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, auditClientKey{}, auditClient{ip: ip, userAgent: userAgent})
+}
+
+// AuditQuery filters audit entries; zero fields match everything. UserID
+// matches entries the user made or that were made to their account.
+type AuditQuery struct {
+	UserID     *primitive.ObjectID
+	ActorID    *primitive.ObjectID
+	TargetType string
+	TargetID   *primitive.ObjectID
+	Actions    []string
+	From       time.Time
+	To         time.Time
+}
+
+// Record stores an audit entry. Failures are logged rather than returned so
+// that auditing never blocks the action being audited.
+func (s *AuditService) Record(ctx context.Context, entry models.AuditEntry) {
+	if s == nil {
+		return
+	}
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	if client, ok := ctx.Value(auditClientKey{}).(auditClient); ok && entry.IP == "" {
+		entry.IP, entry.UserAgent = client.ip, client.userAgent
+	}
+	if _, err := s.mongoClient.GetCollection("audit_logs").InsertOne(ctx, entry); err != nil {
+		log.Printf("Failed to record audit entry %s: %v", entry.Action, err)
+	}
+}
+
+// Query returns a page of matching entries, newest first, and the total count
+func (s *AuditService) Query(ctx context.Context, query AuditQuery, offset, limit int64) ([]models.AuditEntry, int64, error) {
+	collection := s.mongoClient.GetCollection("audit_logs")
+	filter := query.filter()
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Export streams matching entries oldest first to fn, stopping after limit
+// entries or at the first error fn returns
+func (s *AuditService) Export(ctx context.Context, query AuditQuery, limit int64, fn func(models.AuditEntry) error) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("audit_logs").Find(ctx, query.filter(), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry models.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// filter builds the Mongo filter for a query
+func (q AuditQuery) filter() bson.M {
+	filter := bson.M{}
+	if q.UserID != nil {
+		filter["$or"] = []bson.M{
+			{"actor_id": *q.UserID},
+			{"target_type": models.AuditTargetUser, "target_id": *q.UserID},
+		}
+	}
+	if q.ActorID != nil {
+		filter["actor_id"] = *q.ActorID
+	}
+	if q.TargetType != "" {
+		filter["target_type"] = q.TargetType
+	}
+	if q.TargetID != nil {
+		filter["target_id"] = *q.TargetID
+	}
+	if len(q.Actions) > 0 {
+		filter["action"] = bson.M{"$in": q.Actions}
+	}
+
+	createdAt := bson.M{}
+	if !q.From.IsZero() {
+		createdAt["$gte"] = q.From
+	}
+	if !q.To.IsZero() {
+		createdAt["$lt"] = q.To
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+	return filter
+} 
\ No newline at end of file
@@ -3,28 +3,49 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
 	"neighborenexus/internal/database"
+	"neighborenexus/internal/email"
 	"neighborenexus/internal/models"
+	"neighborenexus/internal/oauth"
 )
 
+// ErrAccountSuspended is returned when a suspended user signs in
+var ErrAccountSuspended = errors.New("account suspended")
+
 // AuthService handles authentication and user management
 type AuthService struct {
 	mongoClient *database.MongoClient
 	jwtSecret   string
+	privacy     *PrivacyService
+	sessions    *SessionStore
+	mailer      email.Sender
+	providers   map[models.OAuthProvider]*oauth.Provider
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(mongoClient *database.MongoClient, jwtSecret string) *AuthService {
+// NewAuthService creates a new authentication service. Each login starts a
+// session in sessionStore that its refresh tokens continue. Users can also
+// sign in with any of providers; mailer tells the owner of an address when
+// signing in that way takes over an account registered with it.
+func NewAuthService(mongoClient *database.MongoClient, jwtSecret string, privacyService *PrivacyService, sessionStore *SessionStore, mailer email.Sender, providers ...*oauth.Provider) *AuthService {
+	byName := make(map[models.OAuthProvider]*oauth.Provider, len(providers))
+	for _, provider := range providers {
+		byName[models.OAuthProvider(provider.Name())] = provider
+	}
 	return &AuthService{
 		mongoClient: mongoClient,
 		jwtSecret:   jwtSecret,
+		privacy:     privacyService,
+		sessions:    sessionStore,
+		mailer:      mailer,
+		providers:   byName,
 	}
 }
 
@@ -33,27 +54,41 @@ func (a *AuthService) Register(ctx context.Context, req models.RegisterRequest)
 	// Check if user already exists
 	collection := a.mongoClient.GetCollection("users")
 	var existingUser models.User
-	err := collection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&existingUser)
+	address := normalizeEmail(req.Email)
+	err := collection.FindOne(ctx, bson.M{"email": address}).Decode(&existingUser)
 	if err == nil {
 		return nil, errors.New("user already exists")
 	}
 
+	dateOfBirth, supervision, err := registrationSafety(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create user
+	// Create user. New users have the default privacy settings, so their
+	// location is indexed at the deployment's default precision.
 	user := models.User{
-		ID:        primitive.NewObjectID(),
-		Email:     req.Email,
-		Password:  string(hashedPassword),
-		Name:      req.Name,
-		Phone:     req.Phone,
-		Location:  req.Location,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:           primitive.NewObjectID(),
+		Email:        address,
+		Password:     string(hashedPassword),
+		Name:         req.Name,
+		Phone:        models.EncryptedString(req.Phone),
+		Location:     a.privacy.IndexLocation(req.Location, models.PrivacySettings{}),
+		Role:         models.RoleUser,
+		AccountType:  req.AccountType,
+		DateOfBirth:  dateOfBirth,
+		Supervision:  supervision,
+		Language:     req.Language,
+		Units:        req.Units,
+		EmailPending: true,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
 	}
 
 	// Insert user into database
@@ -67,12 +102,13 @@ func (a *AuthService) Register(ctx context.Context, req models.RegisterRequest)
 	return &user, nil
 }
 
-// Login authenticates a user and returns JWT tokens
-func (a *AuthService) Login(ctx context.Context, req models.LoginRequest) (*models.AuthResponse, error) {
+// Login authenticates a user, starts a session for the client, and returns
+// JWT tokens
+func (a *AuthService) Login(ctx context.Context, req models.LoginRequest, client models.SessionClient) (*models.AuthResponse, error) {
 	// Find user by email
 	collection := a.mongoClient.GetCollection("users")
 	var user models.User
-	err := collection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
+	err := collection.FindOne(ctx, bson.M{"email": normalizeEmail(req.Email)}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.New("invalid credentials")
@@ -85,14 +121,27 @@ func (a *AuthService) Login(ctx context.Context, req models.LoginRequest) (*mode
 	if err != nil {
 		return nil, errors.New("invalid credentials")
 	}
+	if user.AccountSuspended(time.Now()) {
+		return nil, ErrAccountSuspended
+	}
+	return a.startSession(ctx, user, client)
+}
+
+// startSession starts a session for user on client and returns its JWT
+// tokens
+func (a *AuthService) startSession(ctx context.Context, user models.User, client models.SessionClient) (*models.AuthResponse, error) {
+	session, tokenID, err := a.sessions.Start(ctx, user.ID.Hex(), client)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate JWT tokens
-	accessToken, err := a.generateAccessToken(user.ID.Hex(), user.Email)
+	accessToken, err := a.generateAccessToken(user.ID.Hex(), user.Email, session.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := a.generateRefreshToken(user.ID.Hex())
+	refreshToken, err := a.generateRefreshToken(user.ID.Hex(), session.ID, tokenID)
 	if err != nil {
 		return nil, err
 	}
@@ -107,26 +156,13 @@ func (a *AuthService) Login(ctx context.Context, req models.LoginRequest) (*mode
 	}, nil
 }
 
-// RefreshToken generates a new access token using a refresh token
-func (a *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
-	// Parse and validate refresh token
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		return []byte(a.jwtSecret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return nil, errors.New("invalid refresh token")
-	}
-
-	// Extract user ID from token
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
-	}
-
-	userID, ok := claims["user_id"].(string)
-	if !ok {
-		return nil, errors.New("invalid user ID in token")
+// RefreshToken generates a new access token using a refresh token. The
+// refresh token is rotated: the one returned replaces it, and presenting the
+// old one again ends the session.
+func (a *AuthService) RefreshToken(ctx context.Context, refreshToken string, client models.SessionClient) (*models.AuthResponse, error) {
+	userID, sessionID, tokenID, err := a.parseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get user from database
@@ -141,14 +177,25 @@ func (a *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*m
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
+	if user.AccountSuspended(time.Now()) {
+		return nil, ErrAccountSuspended
+	}
+
+	newTokenID, err := a.sessions.Rotate(ctx, userID, sessionID, tokenID, client)
+	if errors.Is(err, ErrSessionNotFound) || errors.Is(err, errRefreshTokenReused) {
+		return nil, errors.New("invalid refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate new tokens
-	accessToken, err := a.generateAccessToken(user.ID.Hex(), user.Email)
+	accessToken, err := a.generateAccessToken(user.ID.Hex(), user.Email, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := a.generateRefreshToken(user.ID.Hex())
+	newRefreshToken, err := a.generateRefreshToken(user.ID.Hex(), sessionID, newTokenID)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +210,55 @@ func (a *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*m
 	}, nil
 }
 
+// Logout ends the session a refresh token belongs to. Tokens that are
+// invalid or whose session has already ended are ignored.
+func (a *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	userID, sessionID, _, err := a.parseRefreshToken(refreshToken)
+	if err != nil {
+		return nil
+	}
+	if err := a.sessions.Revoke(ctx, userID, sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
+
+// ListSessions returns a user's active sessions, marking currentSessionID
+func (a *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]models.Session, error) {
+	sessions, err := a.sessions.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of a user's sessions
+func (a *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	return a.sessions.Revoke(ctx, userID, sessionID)
+}
+
+// RevokeOtherSessions ends every session of a user but keep, returning how
+// many were ended
+func (a *AuthService) RevokeOtherSessions(ctx context.Context, userID, keep string) (int, error) {
+	return a.sessions.RevokeAll(ctx, userID, keep)
+}
+
+// VerifyPassword checks a user's password, e.g. before an irreversible action
+func (a *AuthService) VerifyPassword(ctx context.Context, userID primitive.ObjectID, password string) error {
+	var user models.User
+	err := a.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("invalid credentials")
+	}
+	return nil
+}
+
 // GetUserByID retrieves a user by ID
 func (a *AuthService) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
 	collection := a.mongoClient.GetCollection("users")
@@ -211,11 +307,18 @@ func (a *AuthService) UpdateUser(ctx context.Context, userID string, updates bso
 	return a.GetUserByID(ctx, userID)
 }
 
-// generateAccessToken creates a JWT access token
-func (a *AuthService) generateAccessToken(userID, email string) (string, error) {
+// normalizeEmail returns the form addresses are stored and looked up in, so
+// an address belongs to one account however it is typed
+func normalizeEmail(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// generateAccessToken creates a JWT access token for a session
+func (a *AuthService) generateAccessToken(userID, email, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"email":   email,
+		"sid":     sessionID,
 		"type":    "access",
 		"exp":     time.Now().Add(24 * time.Hour).Unix(),
 		"iat":     time.Now().Unix(),
@@ -225,12 +328,15 @@ func (a *AuthService) generateAccessToken(userID, email string) (string, error)
 	return token.SignedString([]byte(a.jwtSecret))
 }
 
-// generateRefreshToken creates a JWT refresh token
-func (a *AuthService) generateRefreshToken(userID string) (string, error) {
+// generateRefreshToken creates a JWT refresh token continuing a session.
+// tokenID, the jti, must match the session's for the token to be accepted.
+func (a *AuthService) generateRefreshToken(userID, sessionID, tokenID string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
+		"sid":     sessionID,
+		"jti":     tokenID,
 		"type":    "refresh",
-		"exp":     time.Now().Add(7 * 24 * time.Hour).Unix(),
+		"exp":     time.Now().Add(refreshTokenLifetime).Unix(),
 		"iat":     time.Now().Unix(),
 	}
 
@@ -238,30 +344,75 @@ func (a *AuthService) generateRefreshToken(userID string) (string, error) {
 	return token.SignedString([]byte(a.jwtSecret))
 }
 
-// ValidateToken validates a JWT token and returns the user ID
-func (a *AuthService) ValidateToken(tokenString string) (string, error) {
+// parseRefreshToken validates a refresh token and returns its user, session,
+// and token IDs
+func (a *AuthService) parseRefreshToken(refreshToken string) (userID, sessionID, tokenID string, err error) {
+	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
+		return []byte(a.jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return "", "", "", errors.New("invalid refresh token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", "", "", errors.New("invalid token claims")
+	}
+
+	userID, ok = claims["user_id"].(string)
+	if !ok {
+		return "", "", "", errors.New("invalid user ID in token")
+	}
+
+	// Refresh tokens issued before sessions were tracked carry no session
+	// and cannot be revoked, so they are no longer accepted
+	tokenType, _ := claims["type"].(string)
+	sessionID, _ = claims["sid"].(string)
+	tokenID, _ = claims["jti"].(string)
+	if tokenType != "refresh" || sessionID == "" || tokenID == "" {
+		return "", "", "", errors.New("invalid refresh token")
+	}
+	return userID, sessionID, tokenID, nil
+}
+
+// ValidateToken validates a JWT access token and returns the user ID and the
+// session it was issued to. Tokens of sessions that have ended are rejected.
+func (a *AuthService) ValidateToken(ctx context.Context, tokenString string) (string, string, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return []byte(a.jwtSecret), nil
 	})
 
 	if err != nil || !token.Valid {
-		return "", errors.New("invalid token")
+		return "", "", errors.New("invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", errors.New("invalid token claims")
+		return "", "", errors.New("invalid token claims")
 	}
 
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		return "", errors.New("invalid user ID in token")
+		return "", "", errors.New("invalid user ID in token")
 	}
 
 	tokenType, ok := claims["type"].(string)
 	if !ok || tokenType != "access" {
-		return "", errors.New("invalid token type")
+		return "", "", errors.New("invalid token type")
+	}
+
+	// Access tokens issued before sessions were tracked carry no session and
+	// stay valid until they expire
+	sessionID, _ := claims["sid"].(string)
+	if sessionID != "" {
+		active, err := a.sessions.Active(ctx, sessionID)
+		if err != nil {
+			return "", "", err
+		}
+		if !active {
+			return "", "", errors.New("session has ended")
+		}
 	}
 
-	return userID, nil
+	return userID, sessionID, nil
 } 
\ No newline at end of file
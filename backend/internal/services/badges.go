@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// awardBadges gives userID a badge of kind for each of thresholds, in
+// ascending order, that count has reached and they do not already hold
+func awardBadges(ctx context.Context, mongoClient *database.MongoClient, userID primitive.ObjectID, kind models.BadgeKind, count int64, thresholds []int) error {
+	users := mongoClient.GetCollection("users")
+	for _, threshold := range thresholds {
+		if count < int64(threshold) {
+			break
+		}
+		// The filter skips users who already hold the badge, so concurrent
+		// updates award it once
+		_, err := users.UpdateOne(ctx, bson.M{
+			"_id":    userID,
+			"badges": bson.M{"$not": bson.M{"$elemMatch": bson.M{"kind": kind, "level": threshold}}},
+		}, bson.M{"$push": bson.M{"badges": models.Badge{Kind: kind, Level: threshold, AwardedAt: time.Now()}}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextBadgeAt returns the first of thresholds count has not reached, or
+// zero once all are
+func nextBadgeAt(count int64, thresholds []int) int {
+	for _, threshold := range thresholds {
+		if count < int64(threshold) {
+			return threshold
+		}
+	}
+	return 0
+} 
\ No newline at end of file
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+)
+
+// Matching algorithm variants the benchmark compares
+const (
+	// VariantExhaustive scores every volunteer on one goroutine and sorts
+	// them all; its results are the reference recall is measured against
+	VariantExhaustive = "exhaustive"
+	// VariantPool is production matching: every volunteer scored across
+	// the worker pool, keeping a top-k heap per worker
+	VariantPool = "pool"
+	// VariantH3 only scores volunteers in the H3 cells near the need,
+	// trading recall at the edge of the cutoff for fewer candidates
+	VariantH3 = "h3"
+)
+
+// MatchVariants lists the variants in the order they are reported
+var MatchVariants = []string{VariantExhaustive, VariantPool, VariantH3}
+
+// benchH3Resolution buckets synthetic volunteers for the H3 variant
+const benchH3Resolution = 7
+
+// benchTopics is how many clusters synthetic embeddings are drawn around, so
+// needs resemble some volunteers far more than others
+const benchTopics = 16
+
+// SyntheticArea is the circle synthetic needs and volunteers are placed in
+type SyntheticArea struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// SyntheticData generates volunteers and needs with clustered embeddings
+// and locations spread over an area. The same seed always gives the same data.
+type SyntheticData struct {
+	rng        *rand.Rand
+	area       SyntheticArea
+	dimensions int
+	topics     [][]float32
+}
+
+// NewSyntheticData creates a generator of embeddings with the given dimensions
+func NewSyntheticData(seed int64, area SyntheticArea, dimensions int) *SyntheticData {
+	data := &SyntheticData{
+		rng:        rand.New(rand.NewSource(seed)),
+		area:       area,
+		dimensions: dimensions,
+	}
+	for i := 0; i < benchTopics; i++ {
+		data.topics = append(data.topics, data.direction(nil, 0))
+	}
+	return data
+}
+
+// Volunteers generates n active volunteers, each with their own user ID
+func (d *SyntheticData) Volunteers(n int) []models.Volunteer {
+	now := time.Now()
+	volunteers := make([]models.Volunteer, n)
+	for i := range volunteers {
+		volunteers[i] = models.Volunteer{
+			ID:          primitive.NewObjectID(),
+			UserID:      primitive.NewObjectID(),
+			Description: fmt.Sprintf("Synthetic volunteer %d", i),
+			Location:    d.location(),
+			Embedding:   d.embedding(),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	return volunteers
+}
+
+// Needs generates n open needs
+func (d *SyntheticData) Needs(n int) []models.Need {
+	now := time.Now()
+	categories := models.DefaultCategories
+	needs := make([]models.Need, n)
+	for i := range needs {
+		needs[i] = models.Need{
+			ID:          primitive.NewObjectID(),
+			UserID:      primitive.NewObjectID(),
+			Title:       fmt.Sprintf("Synthetic need %d", i),
+			Description: "Generated for matching benchmarks",
+			Category:    categories[d.rng.Intn(len(categories))],
+			Urgency:     models.UrgencyMedium,
+			Duration:    60,
+			Location:    d.location(),
+			Status:      models.NeedStatusRequested,
+			Embedding:   d.embedding(),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	return needs
+}
+
+// embedding is a unit vector near one of the topics
+func (d *SyntheticData) embedding() []float32 {
+	return d.direction(d.topics[d.rng.Intn(len(d.topics))], 0.6)
+}
+
+// direction is a random unit vector, centered on around when given, with
+// each component perturbed by up to spread
+func (d *SyntheticData) direction(around []float32, spread float64) []float32 {
+	vector := make([]float32, d.dimensions)
+	var norm float64
+	for i := range vector {
+		value := d.rng.NormFloat64()
+		if around != nil {
+			value = float64(around[i]) + spread*value/math.Sqrt(float64(d.dimensions))
+		}
+		vector[i] = float32(value)
+		norm += value * value
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
+
+// location is a point spread uniformly over the area
+func (d *SyntheticData) location() models.Location {
+	const kmPerDegree = 111.32
+	distance := d.area.RadiusKm * math.Sqrt(d.rng.Float64())
+	bearing := 2 * math.Pi * d.rng.Float64()
+	lat := d.area.Latitude + distance*math.Cos(bearing)/kmPerDegree
+	lng := d.area.Longitude + distance*math.Sin(bearing)/(kmPerDegree*math.Cos(d.area.Latitude*math.Pi/180))
+	return models.Location{
+		Latitude:  lat,
+		Longitude: lng,
+		H3Index:   h3.LatLngToCell(h3.LatLng{Lat: lat, Lng: lng}, benchH3Resolution).String(),
+	}
+}
+
+// MatchBenchmark runs every need against the same volunteers with each
+// variant, scoring with the given tunables
+type MatchBenchmark struct {
+	Volunteers []models.Volunteer
+	Needs      []models.Need
+	Limit      int
+	Tunables   settings.Tunables
+}
+
+// MatchBenchResult summarizes one variant's run
+type MatchBenchResult struct {
+	Variant string
+	Queries int
+	Mean    time.Duration
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+	// Recall is the share of the exhaustive variant's matches the variant
+	// also returned, over all needs
+	Recall float64
+}
+
+// Run benchmarks the variants, always running the exhaustive one first as
+// the reference for recall
+func (b *MatchBenchmark) Run(ctx context.Context, variants []string) ([]MatchBenchResult, error) {
+	expected := make([][]models.Match, len(b.Needs))
+	var results []MatchBenchResult
+	for _, variant := range append([]string{VariantExhaustive}, variants...) {
+		if variant == VariantExhaustive && results != nil {
+			continue
+		}
+		find, err := b.finder(ctx, variant)
+		if err != nil {
+			return nil, err
+		}
+
+		durations := make([]time.Duration, len(b.Needs))
+		found, relevant := 0, 0
+		for i := range b.Needs {
+			start := time.Now()
+			matches, err := find(&b.Needs[i])
+			if err != nil {
+				return nil, err
+			}
+			durations[i] = time.Since(start)
+
+			if variant == VariantExhaustive {
+				expected[i] = matches
+			}
+			found += overlap(expected[i], matches)
+			relevant += len(expected[i])
+		}
+
+		result := SummarizeMatchLatencies(variant, durations)
+		result.Recall = 1
+		if relevant > 0 {
+			result.Recall = float64(found) / float64(relevant)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// finder returns the function that matches one need with variant
+func (b *MatchBenchmark) finder(ctx context.Context, variant string) (func(need *models.Need) ([]models.Match, error), error) {
+	m := &MatchingService{embeddingService: &EmbeddingService{}}
+	switch variant {
+	case VariantExhaustive:
+		return func(need *models.Need) ([]models.Match, error) {
+			return b.exhaustive(m, need), nil
+		}, nil
+	case VariantPool:
+		return func(need *models.Need) ([]models.Match, error) {
+			return scoreTopK(ctx, len(b.Volunteers), b.Limit, byScore, func(i int) (models.Match, bool) {
+				return m.scoreVolunteer(need, &b.Volunteers[i], b.criteria(need))
+			})
+		}, nil
+	case VariantH3:
+		cells := make(map[string][]int)
+		for i, volunteer := range b.Volunteers {
+			cells[volunteer.Location.H3Index] = append(cells[volunteer.Location.H3Index], i)
+		}
+		return func(need *models.Need) ([]models.Match, error) {
+			candidates := b.nearby(need, cells)
+			return scoreTopK(ctx, len(candidates), b.Limit, byScore, func(i int) (models.Match, bool) {
+				return m.scoreVolunteer(need, &b.Volunteers[candidates[i]], b.criteria(need))
+			})
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown matching variant %q", variant)
+	}
+}
+
+// criteria scores a need with the benchmark's tunables, outside any emergency
+func (b *MatchBenchmark) criteria(need *models.Need) needCriteria {
+	matching := b.Tunables.ForCategory(need.Category)
+	return needCriteria{
+		decayKm:       matching.DistanceDecayKm,
+		maxDistanceKm: matching.MaxDistanceKm,
+		threshold:     matching.MatchThreshold,
+		minTrust:      RequiredTrust(need, b.Tunables),
+	}
+}
+
+// exhaustive scores every volunteer serially and sorts all the matches
+func (b *MatchBenchmark) exhaustive(m *MatchingService, need *models.Need) []models.Match {
+	var matches []models.Match
+	for i := range b.Volunteers {
+		if match, ok := m.scoreVolunteer(need, &b.Volunteers[i], b.criteria(need)); ok {
+			matches = append(matches, match)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return byScore(matches[i], matches[j]) })
+	if len(matches) > b.Limit {
+		matches = matches[:b.Limit]
+	}
+	return matches
+}
+
+// nearby returns the volunteers in cells close enough to the need to clear
+// the match threshold even with a perfect similarity
+func (b *MatchBenchmark) nearby(need *models.Need, cells map[string][]int) []int {
+	origin := h3.Cell(h3.IndexFromString(need.Location.H3Index))
+	if !origin.IsValid() {
+		return nil
+	}
+	matching := b.Tunables.ForCategory(need.Category)
+	cutoffKm := -matching.DistanceDecayKm * math.Log(matching.MatchThreshold)
+	if matching.MaxDistanceKm > 0 {
+		cutoffKm = math.Min(cutoffKm, matching.MaxDistanceKm)
+	}
+	rings := int(math.Ceil(cutoffKm / (1.5 * h3.HexagonEdgeLengthAvgKm(benchH3Resolution))))
+
+	var candidates []int
+	for _, cell := range h3.GridDisk(origin, rings) {
+		candidates = append(candidates, cells[cell.String()]...)
+	}
+	return candidates
+}
+
+// overlap counts the matches in got that are also in want
+func overlap(want, got []models.Match) int {
+	seen := make(map[primitive.ObjectID]bool, len(want))
+	for _, match := range want {
+		seen[match.VolunteerID] = true
+	}
+	count := 0
+	for _, match := range got {
+		if seen[match.VolunteerID] {
+			count++
+		}
+	}
+	return count
+}
+
+// SummarizeMatchLatencies reports the mean and percentiles of matching latencies
+func SummarizeMatchLatencies(variant string, durations []time.Duration) MatchBenchResult {
+	result := MatchBenchResult{Variant: variant, Queries: len(durations)}
+	if len(durations) == 0 {
+		return result
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	percentile := func(p float64) time.Duration {
+		return durations[min(int(math.Ceil(p*float64(len(durations))))-1, len(durations)-1)]
+	}
+	result.Mean = total / time.Duration(len(durations))
+	result.P50 = percentile(0.50)
+	result.P95 = percentile(0.95)
+	result.P99 = percentile(0.99)
+	result.Max = durations[len(durations)-1]
+	return result
+} 
\ No newline at end of file
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+)
+
+// benchDimensions keeps synthetic embeddings the size production uses, so
+// scoring costs what it does in production
+const benchDimensions = 1536
+
+// newMatchBenchmark generates a benchmark over the same area as the
+// bench-matching command, the same for every run
+func newMatchBenchmark(volunteers, needs, dimensions int) *MatchBenchmark {
+	data := NewSyntheticData(1, SyntheticArea{Latitude: 47.6062, Longitude: -122.3321, RadiusKm: 25}, dimensions)
+	return &MatchBenchmark{
+		Volunteers: data.Volunteers(volunteers),
+		Needs:      data.Needs(needs),
+		Limit:      10,
+		Tunables:   settings.Defaults(),
+	}
+}
+
+// BenchmarkScoreTopK measures scoring every volunteer for one need across
+// the worker pool, as production matching does
+func BenchmarkScoreTopK(b *testing.B) {
+	for _, volunteers := range []int{1000, 10000, 50000} {
+		b.Run(fmt.Sprintf("volunteers=%d", volunteers), func(b *testing.B) {
+			bench := newMatchBenchmark(volunteers, 50, benchDimensions)
+			m := &MatchingService{embeddingService: &EmbeddingService{}}
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				need := &bench.Needs[i%len(bench.Needs)]
+				criteria := bench.criteria(need)
+				_, err := scoreTopK(ctx, len(bench.Volunteers), bench.Limit, byScore, func(j int) (models.Match, bool) {
+					return m.scoreVolunteer(need, &bench.Volunteers[j], criteria)
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFindMatchesForNeed measures each matching variant against 10,000
+// volunteers and reports its recall against the exhaustive variant
+func BenchmarkFindMatchesForNeed(b *testing.B) {
+	bench := newMatchBenchmark(10000, 50, benchDimensions)
+	ctx := context.Background()
+	for _, variant := range MatchVariants {
+		b.Run(variant, func(b *testing.B) {
+			find, err := bench.finder(ctx, variant)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := find(&bench.Needs[i%len(bench.Needs)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			b.ReportMetric(matchRecall(b, bench, variant), "recall")
+		})
+	}
+}
+
+// TestMatchVariantRecall guards matching accuracy: the pool variant is an
+// exact top k, and the H3 variant only drops candidates at the edge of the
+// distance cutoff
+func TestMatchVariantRecall(t *testing.T) {
+	bench := newMatchBenchmark(3000, 30, 64)
+	for variant, minRecall := range map[string]float64{VariantPool: 1, VariantH3: 0.95} {
+		if recall := matchRecall(t, bench, variant); recall < minRecall {
+			t.Errorf("%s recall = %.3f, want at least %.2f", variant, recall, minRecall)
+		}
+	}
+}
+
+// matchRecall runs every need of bench through variant and returns the
+// share of the exhaustive variant's matches it also found
+func matchRecall(tb testing.TB, bench *MatchBenchmark, variant string) float64 {
+	tb.Helper()
+	results, err := bench.Run(context.Background(), []string{variant})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return results[len(results)-1].Recall
+} 
\ No newline at end of file
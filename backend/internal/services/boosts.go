@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+)
+
+// Each boost relaxes matching further than the last: the threshold falls
+// and the distance stretches by these factors per boost
+const (
+	boostThresholdFactor = 0.75
+	boostRadiusFactor    = 1.5
+)
+
+var (
+	// ErrBoostTooSoon is returned when a need was posted or boosted within
+	// the cooldown
+	ErrBoostTooSoon = errors.New("this need was boosted recently; try again later")
+	// ErrBoostLimit is returned when a need has been boosted as often as allowed
+	ErrBoostLimit = errors.New("this need cannot be boosted again")
+)
+
+// BoostService re-broadcasts needs to the volunteers their earlier
+// notifications did not reach, relaxing matching a little more each time
+type BoostService struct {
+	mongoClient *database.MongoClient
+	matching    *MatchingService
+	sla         *SLAService
+	settings    *settings.Store
+	notify      Notifier
+	cooldown    time.Duration
+	limit       int
+}
+
+// NewBoostService creates a boost service allowing limit boosts per need,
+// each at least cooldown after the need was posted or last boosted
+func NewBoostService(mongoClient *database.MongoClient, matchingService *MatchingService, slaService *SLAService, settingsStore *settings.Store, notify Notifier, cooldown time.Duration, limit int) *BoostService {
+	return &BoostService{
+		mongoClient: mongoClient,
+		matching:    matchingService,
+		sla:         slaService,
+		settings:    settingsStore,
+		notify:      notify,
+		cooldown:    cooldown,
+		limit:       limit,
+	}
+}
+
+// Boost re-runs matching for a need its creator asks to boost, with a lower
+// threshold and a longer reach, and notifies the volunteers who match but
+// were not told about it before. The boost is recorded on the need.
+func (s *BoostService) Boost(ctx context.Context, needID, userID primitive.ObjectID) (*models.NeedBoost, error) {
+	needs := s.mongoClient.GetCollection("needs")
+	var need models.Need
+	if err := needs.FindOne(ctx, bson.M{"_id": needID}).Decode(&need); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNeedNotFound
+		}
+		return nil, err
+	}
+	switch {
+	case need.UserID != userID:
+		return nil, ErrNotNeedOwner
+	case need.Status != models.NeedStatusRequested || need.Hidden || need.HeldForReview:
+		return nil, ErrNeedClosed
+	case len(need.Boosts) >= s.limit:
+		return nil, ErrBoostLimit
+	}
+	now := time.Now()
+	last := need.CreatedAt
+	if len(need.Boosts) > 0 {
+		last = need.Boosts[len(need.Boosts)-1].At
+	}
+	if now.Before(last.Add(s.cooldown)) {
+		return nil, ErrBoostTooSoon
+	}
+
+	if len(need.Embedding) == 0 {
+		if err := s.matching.UpdateNeedEmbedding(ctx, &need); err != nil {
+			return nil, err
+		}
+	}
+
+	// Volunteers already told about the need are left out, so the boost
+	// reaches the next tier
+	round := float64(len(need.Boosts) + 1)
+	boost := models.NeedBoost{
+		At:               now,
+		RadiusMultiplier: math.Pow(boostRadiusFactor, round),
+	}
+	matches, err := s.matching.findMatchesForNeed(ctx, &need, s.settings.Get(ctx).NotificationFanout, func(criteria *needCriteria) {
+		criteria.threshold *= math.Pow(boostThresholdFactor, round)
+		criteria.decayKm *= boost.RadiusMultiplier
+		criteria.maxDistanceKm *= boost.RadiusMultiplier
+		for _, id := range need.Notified {
+			criteria.excluded[id] = true
+		}
+		boost.MatchThreshold = math.Round(criteria.threshold*1000) / 1000
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipients, err := s.matching.Recipients(ctx, matches)
+	if err != nil {
+		return nil, err
+	}
+	boost.Notified = len(recipients)
+
+	// Recording the boost against the history read above keeps two boosts
+	// from going out at once
+	result, err := needs.UpdateOne(ctx,
+		bson.M{"_id": need.ID, "boosts." + strconv.Itoa(len(need.Boosts)): bson.M{"$exists": false}},
+		bson.M{"$push": bson.M{"boosts": boost}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record boost of need %s: %w", need.ID.Hex(), err)
+	}
+	if result.ModifiedCount == 0 {
+		return nil, ErrBoostTooSoon
+	}
+
+	for _, recipient := range recipients {
+		if err := s.notify(ctx, []string{recipient.UserID}, NewNeedMessage(need, recipient)); err != nil {
+			log.Printf("Failed to notify volunteer of boosted need %s: %v", need.ID.Hex(), err)
+		}
+	}
+	s.matching.MarkNotified(ctx, need.ID, recipients)
+	if len(recipients) > 0 {
+		s.sla.Record(ctx, need.ID, models.MilestoneFirstMatch, now)
+	}
+	return &boost, nil
+} 
\ No newline at end of file
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// categoryMaxDepth is how many levels the taxonomy may have, counting the
+// top-level categories
+const categoryMaxDepth = 4
+
+// Errors returned when managing or using the category taxonomy
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrUnknownCategory     = errors.New("unknown category; see GET /categories")
+	ErrCategoryExists      = errors.New("a category with this slug already exists beneath its parent")
+	ErrCategoryHasChildren = errors.New("category has subcategories; delete them first")
+	ErrCategoryTooDeep     = errors.New("categories can be nested at most 4 levels deep")
+	ErrCategoryMismatch    = errors.New("subcategory must be beneath the need's category")
+	ErrInvalidCategorySlug = errors.New("slug must be lowercase letters and digits separated by underscores")
+	ErrCategoryRequired    = errors.New("the other category is the fallback for needs and cannot be deleted")
+)
+
+// CategoryService manages the need taxonomy: the top-level categories and
+// the subcategories admins add beneath them
+type CategoryService struct {
+	mongoClient *database.MongoClient
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(mongoClient *database.MongoClient) *CategoryService {
+	return &CategoryService{mongoClient: mongoClient}
+}
+
+// Tree returns the taxonomy, each level by name with its subcategories
+// beneath it
+func (s *CategoryService) Tree(ctx context.Context) ([]models.CategoryNode, error) {
+	cursor, err := s.mongoClient.GetCollection("categories").Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var managed []models.NeedCategory
+	if err := cursor.All(ctx, &managed); err != nil {
+		return nil, err
+	}
+	children := make(map[string][]models.NeedCategory, len(managed))
+	for _, category := range managed {
+		children[category.Parent] = append(children[category.Parent], category)
+	}
+
+	var build func(parent string) []models.CategoryNode
+	build = func(parent string) []models.CategoryNode {
+		var nodes []models.CategoryNode
+		for _, category := range children[parent] {
+			nodes = append(nodes, models.CategoryNode{
+				ID:          category.ID,
+				Path:        category.Path,
+				Name:        category.Name,
+				Description: category.Description,
+				Children:    build(category.Path),
+			})
+		}
+		return nodes
+	}
+
+	return build(""), nil
+}
+
+// Roots returns the paths of the top-level categories by name
+func (s *CategoryService) Roots(ctx context.Context) ([]string, error) {
+	cursor, err := s.mongoClient.GetCollection("categories").Find(ctx, bson.M{"parent": ""},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetProjection(bson.M{"path": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var roots []models.NeedCategory
+	if err := cursor.All(ctx, &roots); err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(roots))
+	for i, root := range roots {
+		paths[i] = root.Path
+	}
+	return paths, nil
+}
+
+// Create adds a subcategory beneath an existing category, or a top-level
+// category when the request has no parent
+func (s *CategoryService) Create(ctx context.Context, createdBy primitive.ObjectID, req models.CreateCategoryRequest) (*models.NeedCategory, error) {
+	if !models.ValidCategorySlug(req.Slug) {
+		return nil, ErrInvalidCategorySlug
+	}
+	path := req.Slug
+	if req.Parent != "" {
+		if err := s.Check(ctx, req.Parent); err != nil {
+			return nil, err
+		}
+		if len(models.CategoryAncestors(req.Parent)) >= categoryMaxDepth {
+			return nil, ErrCategoryTooDeep
+		}
+		path = req.Parent + models.CategorySeparator + req.Slug
+	}
+
+	now := time.Now()
+	category := models.NeedCategory{
+		ID:          primitive.NewObjectID(),
+		Path:        path,
+		Parent:      req.Parent,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := s.mongoClient.GetCollection("categories").InsertOne(ctx, category); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCategoryExists
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Update renames or redescribes a category
+func (s *CategoryService) Update(ctx context.Context, id primitive.ObjectID, req models.UpdateCategoryRequest) (*models.NeedCategory, error) {
+	set := bson.M{"updated_at": time.Now()}
+	if req.Name != nil {
+		set["name"] = *req.Name
+	}
+	if req.Description != nil {
+		set["description"] = *req.Description
+	}
+
+	var category models.NeedCategory
+	err := s.mongoClient.GetCollection("categories").FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&category)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Delete removes a category with no subcategories, other than the other
+// category, and drops it from volunteers' match filters. Needs filed under
+// it keep its path.
+func (s *CategoryService) Delete(ctx context.Context, id primitive.ObjectID) (*models.NeedCategory, error) {
+	collection := s.mongoClient.GetCollection("categories")
+	var category models.NeedCategory
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&category)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if category.Path == string(models.CategoryOther) {
+		return nil, ErrCategoryRequired
+	}
+
+	children, err := collection.CountDocuments(ctx, bson.M{"parent": category.Path})
+	if err != nil {
+		return nil, err
+	}
+	if children > 0 {
+		return nil, ErrCategoryHasChildren
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, ErrCategoryNotFound
+	}
+	_, err = s.mongoClient.GetCollection("volunteers").UpdateMany(ctx,
+		bson.M{"categories": category.Path},
+		bson.M{"$pull": bson.M{"categories": category.Path}})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Check returns ErrUnknownCategory unless path is in the taxonomy
+func (s *CategoryService) Check(ctx context.Context, path string) error {
+	count, err := s.mongoClient.GetCollection("categories").CountDocuments(ctx, bson.M{"path": path})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrUnknownCategory
+	}
+	return nil
+}
+
+// CheckAll checks each of paths, as for a volunteer's match filters
+func (s *CategoryService) CheckAll(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		if err := s.Check(ctx, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckNeed checks that a need's category is a top-level category and that
+// its subcategory, if it has one, is beneath it
+func (s *CategoryService) CheckNeed(ctx context.Context, category models.Category, subcategory string) error {
+	if subcategory == "" {
+		if !category.WellFormed() {
+			return ErrUnknownCategory
+		}
+		return s.Check(ctx, string(category))
+	}
+	if models.CategoryRoot(subcategory) != category || !strings.Contains(subcategory, models.CategorySeparator) {
+		return ErrCategoryMismatch
+	}
+	return s.Check(ctx, subcategory)
+}
+
+// CategoryFilter matches needs within a category path: those in a top-level
+// category, or filed under a subcategory or any beneath it
+func CategoryFilter(path string) bson.M {
+	if !strings.Contains(path, models.CategorySeparator) {
+		return bson.M{"category": path}
+	}
+	return bson.M{"subcategory": bson.M{"$in": bson.A{
+		path,
+		primitive.Regex{Pattern: "^" + regexp.QuoteMeta(path+models.CategorySeparator)},
+	}}}
+} 
\ No newline at end of file
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+var (
+	// ErrPolicyVersionExists is returned when publishing a version twice
+	ErrPolicyVersionExists = errors.New("policy version already published")
+	// ErrPolicyVersionNotCurrent is returned when accepting a version other than the current one
+	ErrPolicyVersionNotCurrent = errors.New("policy version is not the current version")
+)
+
+// ConsentService tracks published policy versions and which of them each
+// user has accepted. Current versions are cached in-process, since every
+// authenticated request checks them.
+type ConsentService struct {
+	mongoClient *database.MongoClient
+	cacheTTL    time.Duration
+
+	mutex    sync.RWMutex
+	current  map[models.PolicyDocument]models.PolicyVersion
+	loadedAt time.Time
+}
+
+// NewConsentService creates a consent service that re-reads the current
+// policy versions at most once per cacheTTL
+func NewConsentService(mongoClient *database.MongoClient, cacheTTL time.Duration) *ConsentService {
+	return &ConsentService{
+		mongoClient: mongoClient,
+		cacheTTL:    cacheTTL,
+	}
+}
+
+// Publish records a new policy version. Once it takes effect, users must
+// accept it before using the API again.
+func (s *ConsentService) Publish(ctx context.Context, publishedBy primitive.ObjectID, req models.PublishPolicyRequest) (*models.PolicyVersion, error) {
+	now := time.Now()
+	policy := models.PolicyVersion{
+		ID:          primitive.NewObjectID(),
+		Document:    req.Document,
+		Version:     req.Version,
+		URL:         req.URL,
+		EffectiveAt: now,
+		PublishedBy: publishedBy,
+		CreatedAt:   now,
+	}
+	if req.EffectiveAt != nil {
+		policy.EffectiveAt = *req.EffectiveAt
+	}
+
+	if _, err := s.mongoClient.GetCollection("policy_versions").InsertOne(ctx, policy); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrPolicyVersionExists
+		}
+		return nil, err
+	}
+
+	// Take effect on this instance immediately; others pick it up within the cache TTL
+	s.mutex.Lock()
+	s.loadedAt = time.Time{}
+	s.mutex.Unlock()
+	return &policy, nil
+}
+
+// Versions lists published policy versions, newest first
+func (s *ConsentService) Versions(ctx context.Context, document models.PolicyDocument) ([]models.PolicyVersion, error) {
+	filter := bson.M{}
+	if document != "" {
+		filter["document"] = document
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "effective_at", Value: -1}})
+	cursor, err := s.mongoClient.GetCollection("policy_versions").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := []models.PolicyVersion{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Current returns the version in effect for each policy that has one. A
+// database failure keeps serving the last known versions.
+func (s *ConsentService) Current(ctx context.Context) map[models.PolicyDocument]models.PolicyVersion {
+	s.mutex.RLock()
+	current, fresh := s.current, time.Since(s.loadedAt) < s.cacheTTL
+	s.mutex.RUnlock()
+	if fresh {
+		return current
+	}
+
+	loaded, err := s.loadCurrent(ctx)
+	if err != nil {
+		log.Printf("Failed to load policy versions, using cached versions: %v", err)
+		loaded = current
+	}
+
+	s.mutex.Lock()
+	s.current, s.loadedAt = loaded, time.Now()
+	s.mutex.Unlock()
+	return loaded
+}
+
+// State returns the user's standing against each current policy
+func (s *ConsentService) State(ctx context.Context, user *models.User) []models.ConsentState {
+	current := s.Current(ctx)
+	states := []models.ConsentState{}
+	for _, document := range models.PolicyTerms.Values() {
+		policy, ok := current[models.PolicyDocument(document)]
+		if !ok {
+			continue
+		}
+
+		state := models.ConsentState{
+			Document:       policy.Document,
+			CurrentVersion: policy.Version,
+			URL:            policy.URL,
+			Required:       true,
+		}
+		for i := range user.Consents {
+			consent := &user.Consents[i]
+			if consent.Document != policy.Document {
+				continue
+			}
+			state.AcceptedVersion, state.AcceptedAt = consent.Version, &consent.AcceptedAt
+			if consent.Version == policy.Version {
+				state.Required = false
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// Outstanding returns the current policies the user has yet to accept
+func (s *ConsentService) Outstanding(ctx context.Context, user *models.User) []models.ConsentState {
+	var outstanding []models.ConsentState
+	for _, state := range s.State(ctx, user) {
+		if state.Required {
+			outstanding = append(outstanding, state)
+		}
+	}
+	return outstanding
+}
+
+// Accept records the user accepting the current version of a policy.
+// Accepting a version already accepted changes nothing.
+func (s *ConsentService) Accept(ctx context.Context, userID primitive.ObjectID, req models.AcceptPolicyRequest, ip string) error {
+	policy, ok := s.Current(ctx)[req.Document]
+	if !ok || policy.Version != req.Version {
+		return ErrPolicyVersionNotCurrent
+	}
+
+	consent := models.Consent{
+		Document:   req.Document,
+		Version:    req.Version,
+		AcceptedAt: time.Now(),
+		IP:         ip,
+	}
+	_, err := s.mongoClient.GetCollection("users").UpdateOne(
+		ctx,
+		bson.M{
+			"_id":      userID,
+			"consents": bson.M{"$not": bson.M{"$elemMatch": bson.M{"document": req.Document, "version": req.Version}}},
+		},
+		bson.M{"$push": bson.M{"consents": consent}},
+	)
+	return err
+}
+
+// loadCurrent reads the latest version in effect for each policy
+func (s *ConsentService) loadCurrent(ctx context.Context) (map[models.PolicyDocument]models.PolicyVersion, error) {
+	current := map[models.PolicyDocument]models.PolicyVersion{}
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_at", Value: -1}})
+	for _, document := range models.PolicyTerms.Values() {
+		var policy models.PolicyVersion
+		err := s.mongoClient.GetCollection("policy_versions").FindOne(ctx, bson.M{
+			"document":     document,
+			"effective_at": bson.M{"$lte": time.Now()},
+		}, opts).Decode(&policy)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		current[policy.Document] = policy
+	}
+	return current, nil
+} 
\ No newline at end of file
@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/payments"
+)
+
+// contributableStatuses are the need statuses that accept contributions
+var contributableStatuses = []models.NeedStatus{
+	models.NeedStatusRequested,
+	models.NeedStatusMatched,
+	models.NeedStatusInProgress,
+}
+
+var (
+	// ErrContributionsDisabled is returned when the deployment has no Stripe key
+	ErrContributionsDisabled = errors.New("contributions are not enabled on this deployment")
+	// ErrNeedNotFound is returned for a need that does not exist or is not visible
+	ErrNeedNotFound = errors.New("need not found")
+	// ErrNotNeedOwner is returned when someone other than a need's creator
+	// changes its material cost
+	ErrNotNeedOwner = errors.New("only the need's creator can do this")
+	// ErrNeedClosed is returned for needs that are completed or cancelled
+	ErrNeedClosed = errors.New("need is no longer open")
+	// ErrNoMaterialCost is returned when contributing to a need without one
+	ErrNoMaterialCost = errors.New("need has no material cost")
+	// ErrOwnNeed is returned when a need's creator contributes to it
+	ErrOwnNeed = errors.New("you cannot contribute to your own need")
+	// ErrContributionTooLarge is returned for contributions beyond what is
+	// still needed
+	ErrContributionTooLarge = errors.New("contribution exceeds the amount still needed")
+	// ErrMaterialCostBelowRaised is returned when lowering a material cost
+	// below what has already been raised
+	ErrMaterialCostBelowRaised = errors.New("material cost cannot be lower than the amount already raised")
+	// ErrContributionNotFound is returned for a contribution that does not
+	// exist or belongs to someone else
+	ErrContributionNotFound = errors.New("contribution not found")
+	// ErrPayoutNotFound is returned for a payout that does not exist or is
+	// already paid
+	ErrPayoutNotFound = errors.New("payout not found")
+)
+
+// ContributionService takes contributions toward needs' material costs
+// through Stripe Checkout, reconciles them from Stripe webhooks, and tracks
+// payouts of the money raised to the volunteers who fulfil the needs. It is
+// switched off on deployments without a Stripe key.
+type ContributionService struct {
+	mongoClient *database.MongoClient
+	stripe      *payments.StripeClient
+	currency    string
+	returnURL   string
+}
+
+// NewContributionService creates a new contribution service. Payers return
+// to returnURL, with the need and outcome in the query, after checkout.
+func NewContributionService(mongoClient *database.MongoClient, stripe *payments.StripeClient, currency, returnURL string) *ContributionService {
+	return &ContributionService{
+		mongoClient: mongoClient,
+		stripe:      stripe,
+		currency:    strings.ToLower(currency),
+		returnURL:   returnURL,
+	}
+}
+
+// Enabled reports whether contributions are switched on
+func (s *ContributionService) Enabled() bool {
+	return s != nil && s.stripe != nil
+}
+
+// SetMaterialCost sets the material cost of a need its owner created. An
+// amount of zero clears it, which is only allowed before anything is raised.
+func (s *ContributionService) SetMaterialCost(ctx context.Context, needID, ownerID primitive.ObjectID, req models.SetMaterialCostRequest) (*models.MaterialCost, error) {
+	if !s.Enabled() {
+		return nil, ErrContributionsDisabled
+	}
+	need, err := s.findOpenNeed(ctx, needID)
+	if err != nil {
+		return nil, err
+	}
+	if need.UserID != ownerID {
+		return nil, ErrNotNeedOwner
+	}
+
+	var raised int64
+	if need.MaterialCost != nil {
+		raised = need.MaterialCost.RaisedCents
+	}
+	if req.AmountCents < raised || (req.AmountCents == 0 && raised > 0) {
+		return nil, ErrMaterialCostBelowRaised
+	}
+
+	needs := s.mongoClient.GetCollection("needs")
+	if req.AmountCents == 0 {
+		_, err = needs.UpdateOne(ctx, bson.M{"_id": needID}, bson.M{
+			"$unset": bson.M{"material_cost": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		})
+		return nil, err
+	}
+
+	cost := models.MaterialCost{
+		AmountCents: req.AmountCents,
+		Currency:    s.currency,
+		Description: req.Description,
+		RaisedCents: raised,
+	}
+	_, err = needs.UpdateOne(ctx, bson.M{"_id": needID}, bson.M{"$set": bson.M{
+		"material_cost.amount_cents": cost.AmountCents,
+		"material_cost.currency":     cost.Currency,
+		"material_cost.description":  cost.Description,
+		"material_cost.raised_cents": cost.RaisedCents,
+		"updated_at":                 time.Now(),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return &cost, nil
+}
+
+// Contribute records a pending contribution and opens a Stripe Checkout
+// session for it. The contribution counts toward the need once Stripe
+// confirms payment by webhook.
+func (s *ContributionService) Contribute(ctx context.Context, needID primitive.ObjectID, contributor *models.User, amountCents int64) (*models.Contribution, error) {
+	if !s.Enabled() {
+		return nil, ErrContributionsDisabled
+	}
+	need, err := s.findOpenNeed(ctx, needID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case need.MaterialCost == nil:
+		return nil, ErrNoMaterialCost
+	case need.UserID == contributor.ID:
+		return nil, ErrOwnNeed
+	case amountCents > need.MaterialCost.AmountCents-need.MaterialCost.RaisedCents:
+		return nil, ErrContributionTooLarge
+	}
+
+	now := time.Now()
+	contribution := models.Contribution{
+		ID:            primitive.NewObjectID(),
+		NeedID:        needID,
+		ContributorID: contributor.ID,
+		AmountCents:   amountCents,
+		Currency:      need.MaterialCost.Currency,
+		Status:        models.ContributionPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	contributions := s.mongoClient.GetCollection("contributions")
+	if _, err := contributions.InsertOne(ctx, contribution); err != nil {
+		return nil, err
+	}
+
+	session, err := s.stripe.CreateCheckoutSession(ctx, payments.CheckoutParams{
+		AmountCents:   amountCents,
+		Currency:      contribution.Currency,
+		ProductName:   "Contribution: " + need.Title,
+		CustomerEmail: contributor.Email,
+		SuccessURL:    s.returnLink(needID, "success"),
+		CancelURL:     s.returnLink(needID, "cancelled"),
+		Reference:     contribution.ID.Hex(),
+		Metadata: map[string]string{
+			"contribution_id": contribution.ID.Hex(),
+			"need_id":         needID.Hex(),
+		},
+	})
+	if err != nil {
+		contributions.DeleteOne(ctx, bson.M{"_id": contribution.ID})
+		return nil, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	contribution.CheckoutSessionID = session.ID
+	contribution.CheckoutURL = session.URL
+	_, err = contributions.UpdateOne(ctx, bson.M{"_id": contribution.ID}, bson.M{"$set": bson.M{
+		"checkout_session_id": session.ID,
+		"checkout_url":        session.URL,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+// returnLink builds the URL payers come back to after checkout
+func (s *ContributionService) returnLink(needID primitive.ObjectID, outcome string) string {
+	query := url.Values{"need_id": {needID.Hex()}, "contribution": {outcome}}
+	separator := "?"
+	if strings.Contains(s.returnURL, "?") {
+		separator = "&"
+	}
+	return s.returnURL + separator + query.Encode()
+}
+
+// HandleStripeEvent reconciles contributions from a verified Stripe webhook
+// body. Each transition only applies from the expected status, so
+// redelivered events change nothing.
+func (s *ContributionService) HandleStripeEvent(ctx context.Context, body []byte) error {
+	var event payments.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid stripe event: %w", err)
+	}
+
+	switch event.Type {
+	case payments.EventCheckoutCompleted, payments.EventCheckoutExpired:
+		var session payments.CheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return fmt.Errorf("invalid checkout session: %w", err)
+		}
+		contributionID, err := primitive.ObjectIDFromHex(session.ClientReferenceID)
+		if err != nil {
+			// Not a contribution checkout
+			return nil
+		}
+		if event.Type == payments.EventCheckoutExpired {
+			_, err := s.transitionOne(ctx, contributionID, models.ContributionPending, models.ContributionExpired, nil)
+			return err
+		}
+		if session.PaymentStatus != "paid" {
+			return nil
+		}
+		return s.markPaid(ctx, contributionID, session.PaymentIntent)
+
+	case payments.EventChargeSucceeded, payments.EventChargeRefunded:
+		var charge payments.Charge
+		if err := json.Unmarshal(event.Data.Object, &charge); err != nil {
+			return fmt.Errorf("invalid charge: %w", err)
+		}
+		contributionID, err := primitive.ObjectIDFromHex(charge.Metadata["contribution_id"])
+		if err != nil {
+			return nil
+		}
+		if event.Type == payments.EventChargeSucceeded {
+			if charge.ReceiptURL == "" {
+				return nil
+			}
+			_, err := s.mongoClient.GetCollection("contributions").UpdateOne(ctx, bson.M{"_id": contributionID},
+				bson.M{"$set": bson.M{"receipt_url": charge.ReceiptURL, "updated_at": time.Now()}})
+			return err
+		}
+		if !charge.Refunded {
+			// Partial refunds are left for admins to reconcile by hand
+			log.Printf("Partial refund of %d on contribution %s not applied", charge.AmountRefunded, contributionID.Hex())
+			return nil
+		}
+		return s.markRefunded(ctx, contributionID)
+	}
+	return nil
+}
+
+// markPaid confirms a pending contribution and adds it to its need's total
+func (s *ContributionService) markPaid(ctx context.Context, contributionID primitive.ObjectID, paymentIntentID string) error {
+	now := time.Now()
+	contribution, err := s.transitionOne(ctx, contributionID, models.ContributionPending, models.ContributionPaid, bson.M{
+		"paid_at":           now,
+		"payment_intent_id": paymentIntentID,
+	})
+	if err != nil || contribution == nil {
+		return err
+	}
+	return s.adjustRaised(ctx, contribution.NeedID, contribution.AmountCents)
+}
+
+// markRefunded reverses a paid contribution and takes it off its need's total
+func (s *ContributionService) markRefunded(ctx context.Context, contributionID primitive.ObjectID) error {
+	contribution, err := s.transitionOne(ctx, contributionID, models.ContributionPaid, models.ContributionRefunded, bson.M{
+		"refunded_at": time.Now(),
+	})
+	if err != nil || contribution == nil {
+		return err
+	}
+	return s.adjustRaised(ctx, contribution.NeedID, -contribution.AmountCents)
+}
+
+// transitionOne moves a contribution from one status to another and
+// returns it, or nil if it was not in from
+func (s *ContributionService) transitionOne(ctx context.Context, contributionID primitive.ObjectID, from, to models.ContributionStatus, set bson.M) (*models.Contribution, error) {
+	if set == nil {
+		set = bson.M{}
+	}
+	set["status"] = to
+	set["updated_at"] = time.Now()
+
+	var contribution models.Contribution
+	err := s.mongoClient.GetCollection("contributions").FindOneAndUpdate(ctx,
+		bson.M{"_id": contributionID, "status": from}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&contribution)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+// adjustRaised adds delta to the amount raised for a need
+func (s *ContributionService) adjustRaised(ctx context.Context, needID primitive.ObjectID, delta int64) error {
+	_, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx,
+		bson.M{"_id": needID, "material_cost": bson.M{"$exists": true}},
+		bson.M{"$inc": bson.M{"material_cost.raised_cents": delta}})
+	return err
+}
+
+// ListForUser returns a user's contributions, newest first
+func (s *ContributionService) ListForUser(ctx context.Context, userID primitive.ObjectID, limit, offset int64) ([]models.Contribution, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("contributions").Find(ctx, bson.M{"contributor_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	contributions := []models.Contribution{}
+	if err := cursor.All(ctx, &contributions); err != nil {
+		return nil, err
+	}
+	return contributions, nil
+}
+
+// Receipt returns the receipt for one of a user's paid or refunded
+// contributions
+func (s *ContributionService) Receipt(ctx context.Context, contributionID, userID primitive.ObjectID) (*models.ContributionReceipt, error) {
+	var contribution models.Contribution
+	err := s.mongoClient.GetCollection("contributions").FindOne(ctx, bson.M{
+		"_id":            contributionID,
+		"contributor_id": userID,
+		"status":         bson.M{"$in": []models.ContributionStatus{models.ContributionPaid, models.ContributionRefunded}},
+	}).Decode(&contribution)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrContributionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &models.ContributionReceipt{
+		ContributionID: contribution.ID,
+		NeedID:         contribution.NeedID,
+		AmountCents:    contribution.AmountCents,
+		Currency:       contribution.Currency,
+		Status:         contribution.Status,
+		PaidAt:         contribution.PaidAt,
+		RefundedAt:     contribution.RefundedAt,
+		ReceiptURL:     contribution.ReceiptURL,
+	}
+	var need models.Need
+	err = s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": contribution.NeedID},
+		options.FindOne().SetProjection(bson.M{"title": 1})).Decode(&need)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	receipt.NeedTitle = need.Title
+	return receipt, nil
+}
+
+// CreateDuePayouts records a pending payout for every completed need with
+// money raised, owed to the volunteer whose task completed it, and returns
+// how many it created
+func (s *ContributionService) CreateDuePayouts(ctx context.Context) (int, error) {
+	needs := s.mongoClient.GetCollection("needs")
+	cursor, err := needs.Find(ctx, bson.M{
+		"status":                     models.NeedStatusCompleted,
+		"material_cost.raised_cents": bson.M{"$gt": 0},
+		"material_cost.payout_id":    bson.M{"$exists": false},
+	}, options.Find().SetProjection(bson.M{"material_cost": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var due []models.Need
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, need := range due {
+		var task models.Task
+		err := s.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{
+			"need_id": need.ID,
+			"status":  models.TaskStatusCompleted,
+		}, options.FindOne().SetSort(bson.D{{Key: "updated_at", Value: -1}})).Decode(&task)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return created, err
+		}
+
+		now := time.Now()
+		payout := models.Payout{
+			ID:          primitive.NewObjectID(),
+			NeedID:      need.ID,
+			TaskID:      task.ID,
+			VolunteerID: task.VolunteerID,
+			AmountCents: need.MaterialCost.RaisedCents,
+			Currency:    need.MaterialCost.Currency,
+			Status:      models.PayoutPending,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		payouts := s.mongoClient.GetCollection("payouts")
+		if _, err := payouts.InsertOne(ctx, payout); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				return created, err
+			}
+			// A previous run recorded the payout but stopped before linking it
+			if err := payouts.FindOne(ctx, bson.M{"need_id": need.ID}).Decode(&payout); err != nil {
+				return created, err
+			}
+			created--
+		}
+		if _, err := needs.UpdateOne(ctx, bson.M{"_id": need.ID}, bson.M{"$set": bson.M{"material_cost.payout_id": payout.ID}}); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// ListPayouts returns payouts matching filter, newest first
+func (s *ContributionService) ListPayouts(ctx context.Context, filter bson.M, limit, offset int64) ([]models.Payout, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("payouts").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	payouts := []models.Payout{}
+	if err := cursor.All(ctx, &payouts); err != nil {
+		return nil, err
+	}
+	return payouts, nil
+}
+
+// MarkPayoutPaid records that a pending payout was sent
+func (s *ContributionService) MarkPayoutPaid(ctx context.Context, payoutID, adminID primitive.ObjectID, reference string) (*models.Payout, error) {
+	now := time.Now()
+	var payout models.Payout
+	err := s.mongoClient.GetCollection("payouts").FindOneAndUpdate(ctx,
+		bson.M{"_id": payoutID, "status": models.PayoutPending},
+		bson.M{"$set": bson.M{
+			"status":     models.PayoutPaid,
+			"reference":  reference,
+			"paid_by":    adminID,
+			"paid_at":    now,
+			"updated_at": now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&payout)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPayoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+// findOpenNeed returns a visible need that still accepts contributions
+func (s *ContributionService) findOpenNeed(ctx context.Context, needID primitive.ObjectID) (*models.Need, error) {
+	var need models.Need
+	err := s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{
+		"_id":             needID,
+		"hidden":          bson.M{"$ne": true},
+		"held_for_review": bson.M{"$ne": true},
+	}).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range contributableStatuses {
+		if need.Status == status {
+			return &need, nil
+		}
+	}
+	return nil, ErrNeedClosed
+} 
\ No newline at end of file
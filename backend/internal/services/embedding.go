@@ -2,21 +2,33 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"strings"
+	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/sashabaranov/go-openai"
+	"neighborenexus/internal/database"
 )
 
 // EmbeddingService handles OpenAI embeddings for semantic matching
 type EmbeddingService struct {
-	client *openai.Client
+	client   *openai.Client
+	cache    *database.RedisClient
+	cacheTTL time.Duration
 }
 
-// NewEmbeddingService creates a new embedding service
-func NewEmbeddingService(apiKey string) *EmbeddingService {
+// NewEmbeddingService creates a new embedding service. Embeddings are cached
+// on cache for cacheTTL by the text they were made from, so text that comes
+// back unchanged, as when a profile is saved without edits, is not sent to
+// OpenAI again. A nil cache or zero cacheTTL turns caching off.
+func NewEmbeddingService(apiKey string, cache *database.RedisClient, cacheTTL time.Duration) *EmbeddingService {
 	if apiKey == "" {
 		log.Println("Warning: OpenAI API key not provided, embedding service will not work")
 		return &EmbeddingService{
@@ -24,9 +36,31 @@ func NewEmbeddingService(apiKey string) *EmbeddingService {
 		}
 	}
 
+	if cacheTTL <= 0 {
+		cache = nil
+	}
+	return &EmbeddingService{
+		client:   newOpenAIClient(apiKey),
+		cache:    cache,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// NewEmbeddingServiceWithConfig creates an embedding service for any
+// OpenAI-compatible API, such as a proxy or the fake in package fakes
+func NewEmbeddingServiceWithConfig(config openai.ClientConfig) *EmbeddingService {
 	return &EmbeddingService{
-		client: openai.NewClient(apiKey),
+		client: openai.NewClientWithConfig(config),
+	}
+}
+
+// Ping tests that the OpenAI API is reachable with the configured key
+func (e *EmbeddingService) Ping(ctx context.Context) error {
+	if e.client == nil {
+		return fmt.Errorf("OpenAI client not initialized")
 	}
+	_, err := e.client.ListModels(ctx)
+	return err
 }
 
 // GenerateEmbedding creates an embedding for the given text
@@ -46,6 +80,11 @@ func (e *EmbeddingService) GenerateEmbedding(ctx context.Context, text string) (
 		text = text[:8000]
 	}
 
+	key := embeddingCacheKey(text)
+	if embedding, ok := e.cached(ctx, key); ok {
+		return embedding, nil
+	}
+
 	resp, err := e.client.CreateEmbeddings(
 		ctx,
 		openai.EmbeddingRequest{
@@ -62,9 +101,56 @@ func (e *EmbeddingService) GenerateEmbedding(ctx context.Context, text string) (
 		return nil, fmt.Errorf("no embedding data returned")
 	}
 
+	e.store(ctx, key, resp.Data[0].Embedding)
 	return resp.Data[0].Embedding, nil
 }
 
+// embeddingCacheKey is the cache key of the embedding of text: a hash of
+// the text with case and runs of whitespace evened out, under the model
+func embeddingCacheKey(text string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return "embeddings:" + openai.AdaEmbeddingV2.String() + ":" + hex.EncodeToString(sum[:])
+}
+
+// cached returns the embedding cached under key. Cache failures count as
+// misses, so they only cost an API call.
+func (e *EmbeddingService) cached(ctx context.Context, key string) ([]float32, bool) {
+	if e.cache == nil {
+		return nil, false
+	}
+	value, err := e.cache.GetCache(ctx, key)
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Failed to read cached embedding: %v", err)
+		}
+		return nil, false
+	}
+	if len(value) == 0 || len(value)%4 != 0 {
+		return nil, false
+	}
+
+	embedding := make([]float32, len(value)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32([]byte(value[i*4 : i*4+4])))
+	}
+	return embedding, true
+}
+
+// store caches embedding under key as packed little-endian floats
+func (e *EmbeddingService) store(ctx context.Context, key string, embedding []float32) {
+	if e.cache == nil {
+		return
+	}
+	packed := make([]byte, len(embedding)*4)
+	for i, value := range embedding {
+		binary.LittleEndian.PutUint32(packed[i*4:], math.Float32bits(value))
+	}
+	if err := e.cache.SetCache(ctx, key, packed, e.cacheTTL); err != nil {
+		log.Printf("Failed to cache embedding: %v", err)
+	}
+}
+
 // GenerateNeedEmbedding creates an embedding for a need description
 func (e *EmbeddingService) GenerateNeedEmbedding(ctx context.Context, title, description, category string) ([]float32, error) {
 	// Combine title, description, and category for better semantic matching
@@ -82,6 +168,16 @@ func (e *EmbeddingService) GenerateVolunteerEmbedding(ctx context.Context, skill
 	return e.GenerateEmbedding(ctx, text)
 }
 
+// GenerateOfferEmbedding creates an embedding for a volunteer's standing
+// offer, laid out like a need so offers and needs compare directly
+func (e *EmbeddingService) GenerateOfferEmbedding(ctx context.Context, title, description, category, schedule string) ([]float32, error) {
+	text := fmt.Sprintf("Title: %s\nDescription: %s\nCategory: %s", title, description, category)
+	if schedule != "" {
+		text += "\nSchedule: " + schedule
+	}
+	return e.GenerateEmbedding(ctx, text)
+}
+
 // BatchGenerateEmbeddings creates embeddings for multiple texts
 func (e *EmbeddingService) BatchGenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
 	if e.client == nil {
@@ -159,7 +255,7 @@ func sqrt(x float64) float64 {
 	if x <= 0 {
 		return 0
 	}
-	
+
 	// Newton's method for square root
 	z := x
 	for i := 0; i < 10; i++ {
@@ -176,8 +272,8 @@ func (e *EmbeddingService) IsAvailable() bool {
 // GetEmbeddingInfo returns information about the embedding service
 func (e *EmbeddingService) GetEmbeddingInfo() map[string]interface{} {
 	return map[string]interface{}{
-		"available": e.IsAvailable(),
-		"model":     "text-embedding-ada-002",
+		"available":  e.IsAvailable(),
+		"model":      "text-embedding-ada-002",
 		"dimensions": 1536,
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// defaultEmergencyRadiusMultiplier triples the matching distance decay, so
+// with the default 10km decay a volunteer 30km away scores as one 10km away
+// would normally
+const defaultEmergencyRadiusMultiplier = 3
+
+// emergencyFeedScanLimit caps the open needs read to build the emergency
+// feed, newest first
+const emergencyFeedScanLimit = 5000
+
+var (
+	// ErrEmergencyNotFound is returned for an emergency that does not exist
+	// or has already ended
+	ErrEmergencyNotFound = errors.New("emergency not found")
+	// ErrInvalidEmergencyRegion is returned for regions that are not valid
+	// H3 cells at the given resolution
+	ErrInvalidEmergencyRegion = errors.New("regions must be valid H3 cells at the given resolution")
+)
+
+// EmergencyService declares and ends emergencies and answers which of them
+// cover a location
+type EmergencyService struct {
+	mongoClient *database.MongoClient
+}
+
+// NewEmergencyService creates a new emergency service
+func NewEmergencyService(mongoClient *database.MongoClient) *EmergencyService {
+	return &EmergencyService{mongoClient: mongoClient}
+}
+
+// Declare puts an emergency in force
+func (s *EmergencyService) Declare(ctx context.Context, declaredBy primitive.ObjectID, req models.DeclareEmergencyRequest) (*models.Emergency, error) {
+	emergency := models.Emergency{
+		ID:                 primitive.NewObjectID(),
+		Title:              req.Title,
+		Message:            req.Message,
+		Regions:            req.Regions,
+		Resolution:         req.Resolution,
+		PriorityCategories: req.PriorityCategories,
+		RadiusMultiplier:   req.RadiusMultiplier,
+		DeclaredBy:         declaredBy,
+		StartedAt:          time.Now(),
+	}
+	if emergency.RadiusMultiplier == 0 {
+		emergency.RadiusMultiplier = defaultEmergencyRadiusMultiplier
+	}
+	if len(emergency.Regions) > 0 {
+		if emergency.Resolution == 0 {
+			emergency.Resolution = defaultAnnouncementResolution
+		}
+		for _, region := range emergency.Regions {
+			cell := h3.Cell(h3.IndexFromString(region))
+			if !cell.IsValid() || cell.Resolution() != emergency.Resolution {
+				return nil, ErrInvalidEmergencyRegion
+			}
+		}
+	} else {
+		emergency.Resolution = 0
+	}
+
+	if _, err := s.mongoClient.GetCollection("emergencies").InsertOne(ctx, emergency); err != nil {
+		return nil, err
+	}
+	return &emergency, nil
+}
+
+// AttachAnnouncement records the broadcast alert sent for an emergency
+func (s *EmergencyService) AttachAnnouncement(ctx context.Context, emergencyID, announcementID primitive.ObjectID) error {
+	_, err := s.mongoClient.GetCollection("emergencies").UpdateOne(ctx, bson.M{"_id": emergencyID},
+		bson.M{"$set": bson.M{"announcement_id": announcementID}})
+	return err
+}
+
+// End stands down an emergency in force, returning it as ended
+func (s *EmergencyService) End(ctx context.Context, emergencyID, endedBy primitive.ObjectID) (*models.Emergency, error) {
+	var emergency models.Emergency
+	err := s.mongoClient.GetCollection("emergencies").FindOneAndUpdate(ctx,
+		bson.M{"_id": emergencyID, "ended_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"ended_at": time.Now(), "ended_by": endedBy}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&emergency)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrEmergencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &emergency, nil
+}
+
+// List returns emergencies, newest first, including ended ones when
+// includeEnded is set
+func (s *EmergencyService) List(ctx context.Context, includeEnded bool, limit, offset int64) ([]models.Emergency, error) {
+	filter := bson.M{}
+	if !includeEnded {
+		filter["ended_at"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("emergencies").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	emergencies := []models.Emergency{}
+	if err := cursor.All(ctx, &emergencies); err != nil {
+		return nil, err
+	}
+	return emergencies, nil
+}
+
+// Active returns the emergencies in force. A nil service has none.
+func (s *EmergencyService) Active(ctx context.Context) (ActiveEmergencies, error) {
+	if s == nil {
+		return nil, nil
+	}
+	cursor, err := s.mongoClient.GetCollection("emergencies").Find(ctx, bson.M{"ended_at": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	var emergencies ActiveEmergencies
+	if err := cursor.All(ctx, &emergencies); err != nil {
+		return nil, err
+	}
+	return emergencies, nil
+}
+
+// Feed returns the open needs inside active emergencies, those in a
+// priority category first and otherwise newest first, optionally limited to
+// one category
+func (s *EmergencyService) Feed(ctx context.Context, category models.Category, limit, offset int64) ([]models.Need, error) {
+	active, err := s.Active(ctx)
+	if err != nil || len(active) == 0 {
+		return []models.Need{}, err
+	}
+
+	filter := bson.M{
+		"status":          bson.M{"$in": []models.NeedStatus{models.NeedStatusRequested, models.NeedStatusMatched, models.NeedStatusInProgress}},
+		"hidden":          bson.M{"$ne": true},
+		"held_for_review": bson.M{"$ne": true},
+		"$or": []bson.M{
+			{"expires_at": bson.M{"$exists": false}},
+			{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+	}
+	if category != "" {
+		filter["category"] = category
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(emergencyFeedScanLimit).
+		SetProjection(bson.M{"embedding": 0})
+	cursor, err := s.mongoClient.GetCollection("needs").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var open []models.Need
+	if err := cursor.All(ctx, &open); err != nil {
+		return nil, err
+	}
+
+	needs := []models.Need{}
+	priority := map[primitive.ObjectID]bool{}
+	for _, need := range open {
+		if active.Covers(need.Location) {
+			needs = append(needs, need)
+			priority[need.ID] = active.Prioritizes(need.Location, need.Category)
+		}
+	}
+	sort.SliceStable(needs, func(i, j int) bool {
+		return priority[needs[i].ID] && !priority[needs[j].ID]
+	})
+
+	if offset >= int64(len(needs)) {
+		return []models.Need{}, nil
+	}
+	needs = needs[offset:]
+	if int64(len(needs)) > limit {
+		needs = needs[:limit]
+	}
+	return needs, nil
+}
+
+// ActiveEmergencies are the emergencies in force
+type ActiveEmergencies []models.Emergency
+
+// Covers reports whether any emergency covers location
+func (a ActiveEmergencies) Covers(location models.Location) bool {
+	for i := range a {
+		if emergencyCovers(&a[i], location) {
+			return true
+		}
+	}
+	return false
+}
+
+// RadiusMultiplier returns how far to stretch the matching distance decay
+// for a need at location: the largest multiplier of the emergencies that
+// cover it, or 1 outside any emergency
+func (a ActiveEmergencies) RadiusMultiplier(location models.Location) float64 {
+	multiplier := 1.0
+	for i := range a {
+		if a[i].RadiusMultiplier > multiplier && emergencyCovers(&a[i], location) {
+			multiplier = a[i].RadiusMultiplier
+		}
+	}
+	return multiplier
+}
+
+// Prioritizes reports whether an emergency covering location prioritizes
+// category
+func (a ActiveEmergencies) Prioritizes(location models.Location, category models.Category) bool {
+	for i := range a {
+		for _, priority := range a[i].PriorityCategories {
+			if priority == category && emergencyCovers(&a[i], location) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// emergencyCovers reports whether location is inside the emergency's
+// regions. Locations with only a stored cell finer than the regions use its
+// parent; coarser cells cannot be placed and are left out.
+func emergencyCovers(emergency *models.Emergency, location models.Location) bool {
+	if len(emergency.Regions) == 0 {
+		return true
+	}
+
+	var cell h3.Cell
+	switch stored := h3.Cell(h3.IndexFromString(location.H3Index)); {
+	case location.Latitude != 0 || location.Longitude != 0:
+		cell = h3.LatLngToCell(h3.LatLng{Lat: location.Latitude, Lng: location.Longitude}, emergency.Resolution)
+	case location.H3Index != "" && stored.IsValid() && stored.Resolution() >= emergency.Resolution:
+		cell = stored.Parent(emergency.Resolution)
+	default:
+		return false
+	}
+	return contains(emergency.Regions, cell.String())
+} 
\ No newline at end of file
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// ErrUserNotFound is returned when erasing a user who does not exist
+var ErrUserNotFound = errors.New("user not found")
+
+// ErasureReport summarizes what erasing a user removed or anonymized, by collection
+type ErasureReport struct {
+	UserID     primitive.ObjectID `json:"user_id"`
+	ErasedAt   time.Time          `json:"erased_at"`
+	Deleted    map[string]int64   `json:"deleted"`
+	Anonymized map[string]int64   `json:"anonymized"`
+}
+
+// ErasureService permanently erases a user. Records other people still rely
+// on, such as tasks they took part in and feedback they gave, are kept but
+// re-pointed at a random placeholder ID and stripped of free text, so they
+// can no longer be tied back to the erased user.
+type ErasureService struct {
+	mongoClient     *database.MongoClient
+	exportService   *ExportService
+	matchingService *MatchingService
+	uploadService   *UploadService
+}
+
+// NewErasureService creates a new erasure service
+func NewErasureService(mongoClient *database.MongoClient, exportService *ExportService, matchingService *MatchingService, uploadService *UploadService) *ErasureService {
+	return &ErasureService{
+		mongoClient:     mongoClient,
+		exportService:   exportService,
+		matchingService: matchingService,
+		uploadService:   uploadService,
+	}
+}
+
+// Erase removes or anonymizes everything stored about a user. The user
+// document goes last, so an erasure interrupted partway can be run again.
+// The audit log is kept as the record that the erasure happened.
+func (s *ErasureService) Erase(ctx context.Context, userID primitive.ObjectID) (*ErasureReport, error) {
+	users := s.mongoClient.GetCollection("users")
+	if err := users.FindOne(ctx, bson.M{"_id": userID}).Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	report := &ErasureReport{
+		UserID:     userID,
+		Deleted:    map[string]int64{},
+		Anonymized: map[string]int64{},
+	}
+	placeholder := primitive.NewObjectID()
+
+	steps := []func() error{
+		// Vectors are found through the needs and profile, so they go first
+		func() error { return s.matchingService.DeleteVectors(ctx, userID) },
+		// Uploads, photos and need images included, go with their files
+		func() error {
+			deleted, err := s.uploadService.DeleteOwned(ctx, userID)
+			report.Deleted["uploads"] += deleted
+			return err
+		},
+		func() error { return s.eraseNeeds(ctx, userID, placeholder, report) },
+		func() error {
+			return s.anonymize(ctx, report, "tasks", bson.M{"volunteer_id": userID},
+				bson.M{"$set": bson.M{"volunteer_id": placeholder}, "$unset": bson.M{"notes": ""}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "feedback", bson.M{"from_user_id": userID},
+				bson.M{"$set": bson.M{"from_user_id": placeholder}, "$unset": bson.M{"comment": "", "testimonial": ""}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "feedback", bson.M{"to_user_id": userID},
+				bson.M{"$set": bson.M{"to_user_id": placeholder}, "$unset": bson.M{"comment": "", "testimonial": ""}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "kudos", bson.M{"from_user_id": userID},
+				bson.M{"$set": bson.M{"from_user_id": placeholder}, "$unset": bson.M{"message": ""}})
+		},
+		func() error { return s.delete(ctx, report, "kudos", bson.M{"to_user_id": userID}) },
+		func() error { return s.delete(ctx, report, "points", bson.M{"user_id": userID}) },
+		func() error {
+			return s.anonymize(ctx, report, "reports", bson.M{"reporter_id": userID},
+				bson.M{"$set": bson.M{"reporter_id": placeholder}, "$unset": bson.M{"details": ""}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "reports", bson.M{"subject_id": userID},
+				bson.M{"$set": bson.M{"subject_id": placeholder}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "moderation_items", bson.M{"owner_id": userID},
+				bson.M{"$set": bson.M{"owner_id": placeholder, "snapshot": ""}})
+		},
+		// Volunteer profiles carry the user's embedding, so deleting them removes it
+		func() error { return s.delete(ctx, report, "volunteers", bson.M{"user_id": userID}) },
+		func() error { return s.delete(ctx, report, "offers", bson.M{"user_id": userID}) },
+		func() error {
+			return s.delete(ctx, report, "messages", bson.M{"$or": []bson.M{{"sender_id": userID}, {"recipient_id": userID}}})
+		},
+		func() error { return s.delete(ctx, report, "notifications", bson.M{"user_id": userID}) },
+		func() error { return s.delete(ctx, report, "devices", bson.M{"user_id": userID}) },
+		func() error { return s.delete(ctx, report, "matches", bson.M{"user_id": userID}) },
+		// Match sets keep their other candidates for evaluating the matcher
+		func() error {
+			return s.anonymize(ctx, report, "match_decisions", bson.M{"candidates.user_id": userID},
+				bson.M{"$pull": bson.M{"candidates": bson.M{"user_id": userID}}})
+		},
+		func() error {
+			return s.delete(ctx, report, "match_decisions", bson.M{"candidates": bson.M{"$size": 0}})
+		},
+		func() error { return s.eraseGroupMemberships(ctx, userID, report) },
+		func() error { return s.eraseEventSignups(ctx, userID, report) },
+		func() error { return s.erasePosts(ctx, userID, report) },
+		func() error {
+			return s.anonymize(ctx, report, "events", bson.M{"organizer_id": userID},
+				bson.M{"$set": bson.M{"organizer_id": placeholder}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "emergencies", bson.M{"declared_by": userID},
+				bson.M{"$set": bson.M{"declared_by": placeholder}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "emergencies", bson.M{"ended_by": userID},
+				bson.M{"$set": bson.M{"ended_by": placeholder}})
+		},
+		// Referrals stay so inviters keep their counts and badges
+		func() error { return s.delete(ctx, report, "invites", bson.M{"inviter_id": userID}) },
+		func() error {
+			return s.anonymize(ctx, report, "referrals", bson.M{"inviter_id": userID},
+				bson.M{"$set": bson.M{"inviter_id": placeholder}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "referrals", bson.M{"referred_id": userID},
+				bson.M{"$set": bson.M{"referred_id": placeholder}})
+		},
+		// Contributions and payouts are financial records, so they stay
+		func() error {
+			return s.anonymize(ctx, report, "contributions", bson.M{"contributor_id": userID},
+				bson.M{"$set": bson.M{"contributor_id": placeholder}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "payouts", bson.M{"volunteer_id": userID},
+				bson.M{"$set": bson.M{"volunteer_id": placeholder}})
+		},
+		func() error {
+			return s.anonymize(ctx, report, "groups", bson.M{"created_by": userID},
+				bson.M{"$set": bson.M{"created_by": placeholder}})
+		},
+		func() error {
+			deleted, err := s.exportService.DeleteForUser(ctx, userID)
+			report.Deleted["data_exports"] += deleted
+			return err
+		},
+		func() error { return s.delete(ctx, report, "users", bson.M{"_id": userID}) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return report, err
+		}
+	}
+
+	report.ErasedAt = time.Now()
+	return report, nil
+}
+
+// eraseNeeds deletes the user's needs that nobody took on. Needs with tasks
+// stay for the volunteers' history but lose their text, location, and
+// embedding.
+func (s *ErasureService) eraseNeeds(ctx context.Context, userID, placeholder primitive.ObjectID, report *ErasureReport) error {
+	needs := s.mongoClient.GetCollection("needs")
+	needIDs, err := needs.Distinct(ctx, "_id", bson.M{"user_id": userID})
+	if err != nil || len(needIDs) == 0 {
+		return err
+	}
+
+	withTasks, err := s.mongoClient.GetCollection("tasks").Distinct(ctx, "need_id", bson.M{"need_id": bson.M{"$in": needIDs}})
+	if err != nil {
+		return err
+	}
+
+	if len(withTasks) > 0 {
+		err = s.anonymize(ctx, report, "needs", bson.M{"_id": bson.M{"$in": withTasks}}, bson.M{
+			"$set": bson.M{
+				"user_id":     placeholder,
+				"title":       "[deleted]",
+				"description": "",
+				"location":    models.Location{},
+				"updated_at":  time.Now(),
+			},
+			"$unset": bson.M{"tags": "", "embedding": "", "translations": "", "point": "", "match_cell": ""},
+		})
+		if err != nil {
+			return err
+		}
+		err = s.anonymize(ctx, report, "tasks", bson.M{"need_id": bson.M{"$in": withTasks}}, bson.M{"$unset": bson.M{"notes": ""}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.delete(ctx, report, "matches", bson.M{"need_id": bson.M{"$in": needIDs}}); err != nil {
+		return err
+	}
+	return s.delete(ctx, report, "needs", bson.M{"user_id": userID})
+}
+
+// eraseGroupMemberships removes the user from their groups. Groups they were
+// the last admin of keep their other members and can be managed by site
+// admins.
+func (s *ErasureService) eraseGroupMemberships(ctx context.Context, userID primitive.ObjectID, report *ErasureReport) error {
+	groupIDs, err := s.mongoClient.GetCollection("group_members").Distinct(ctx, "group_id", bson.M{"user_id": userID})
+	if err != nil || len(groupIDs) == 0 {
+		return err
+	}
+	if err := s.delete(ctx, report, "group_members", bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err = s.mongoClient.GetCollection("groups").UpdateMany(ctx, bson.M{"_id": bson.M{"$in": groupIDs}},
+		bson.M{"$inc": bson.M{"member_count": -1}})
+	return err
+}
+
+// erasePosts deletes the user's posts with their reactions, and the user's
+// reactions to other posts, taking them off those posts' counts
+func (s *ErasureService) erasePosts(ctx context.Context, userID primitive.ObjectID, report *ErasureReport) error {
+	postIDs, err := s.mongoClient.GetCollection("posts").Distinct(ctx, "_id", bson.M{"author_id": userID})
+	if err != nil {
+		return err
+	}
+	if len(postIDs) > 0 {
+		if err := s.delete(ctx, report, "post_reactions", bson.M{"post_id": bson.M{"$in": postIDs}}); err != nil {
+			return err
+		}
+		if err := s.delete(ctx, report, "posts", bson.M{"author_id": userID}); err != nil {
+			return err
+		}
+	}
+
+	cursor, err := s.mongoClient.GetCollection("post_reactions").Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return err
+	}
+	var reactions []models.PostReactionRecord
+	if err := cursor.All(ctx, &reactions); err != nil {
+		return err
+	}
+	for _, reaction := range reactions {
+		_, err := s.mongoClient.GetCollection("posts").UpdateOne(ctx, bson.M{"_id": reaction.PostID},
+			bson.M{"$inc": bson.M{"reactions." + string(reaction.Reaction): -1}})
+		if err != nil {
+			return err
+		}
+	}
+	return s.delete(ctx, report, "post_reactions", bson.M{"user_id": userID})
+}
+
+// eraseEventSignups deletes the user's event signups, giving back their
+// places at events and shifts
+func (s *ErasureService) eraseEventSignups(ctx context.Context, userID primitive.ObjectID, report *ErasureReport) error {
+	signups := s.mongoClient.GetCollection("event_signups")
+	cursor, err := signups.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return err
+	}
+	var docs []models.EventSignup
+	if err := cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+
+	for _, signup := range docs {
+		filter := bson.M{"_id": signup.EventID}
+		inc := bson.M{"signed_up": -1}
+		if signup.ShiftID != nil {
+			filter["shifts.id"] = *signup.ShiftID
+			inc["shifts.$.signed_up"] = -1
+		}
+		if _, err := s.mongoClient.GetCollection("events").UpdateOne(ctx, filter, bson.M{"$inc": inc}); err != nil {
+			return err
+		}
+		if err := s.delete(ctx, report, "event_signups", bson.M{"_id": signup.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anonymize applies update to the matching documents and counts them
+func (s *ErasureService) anonymize(ctx context.Context, report *ErasureReport, collectionName string, filter, update bson.M) error {
+	result, err := s.mongoClient.GetCollection(collectionName).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	report.Anonymized[collectionName] += result.ModifiedCount
+	return nil
+}
+
+// delete removes the matching documents and counts them
+func (s *ErasureService) delete(ctx context.Context, report *ErasureReport, collectionName string, filter bson.M) error {
+	result, err := s.mongoClient.GetCollection(collectionName).DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+	report.Deleted[collectionName] += result.DeletedCount
+	return nil
+} 
\ No newline at end of file
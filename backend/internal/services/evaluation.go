@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// evaluationBatchSize is how many match sets have their outcomes resolved at once
+const evaluationBatchSize = 200
+
+// MatchDecisionLog records every match set the matcher computes, with the
+// features behind each score, so the scoring model can be tuned and
+// validated offline against real outcomes. A nil *MatchDecisionLog records
+// nothing.
+type MatchDecisionLog struct {
+	mongoClient *database.MongoClient
+	retention   time.Duration
+}
+
+// NewMatchDecisionLog creates a decision log keeping match sets for
+// retention, or forever when it is zero
+func NewMatchDecisionLog(mongoClient *database.MongoClient, retention time.Duration) *MatchDecisionLog {
+	return &MatchDecisionLog{
+		mongoClient: mongoClient,
+		retention:   retention,
+	}
+}
+
+// Record stores a match set computed for the need or volunteer profile
+// subjectID, in rank order. owner gives each match's volunteer account and
+// need category. The log is best-effort, so failures are logged rather than
+// returned.
+func (l *MatchDecisionLog) Record(ctx context.Context, kind models.MatchDecisionKind, subjectID primitive.ObjectID, matches []models.Match, owner func(models.Match) (primitive.ObjectID, models.Category)) {
+	if l == nil || len(matches) == 0 {
+		return
+	}
+
+	candidates := make([]models.MatchCandidate, len(matches))
+	for i, match := range matches {
+		userID, category := owner(match)
+		candidates[i] = models.MatchCandidate{
+			NeedID:      match.NeedID,
+			VolunteerID: match.VolunteerID,
+			UserID:      userID,
+			Category:    category,
+			Rank:        i + 1,
+			Score:       match.Score,
+		}
+		if match.Features != nil {
+			candidates[i].Features = *match.Features
+		}
+	}
+
+	decision := models.MatchDecision{
+		ID:         primitive.NewObjectID(),
+		Kind:       kind,
+		SubjectID:  subjectID,
+		Candidates: candidates,
+		CreatedAt:  time.Now(),
+	}
+	if _, err := l.mongoClient.GetCollection("match_decisions").InsertOne(ctx, decision); err != nil {
+		log.Printf("Failed to record %s match decision for %s: %v", kind, subjectID.Hex(), err)
+	}
+}
+
+// PurgeExpired deletes match sets older than the retention, returning how
+// many were deleted
+func (l *MatchDecisionLog) PurgeExpired(ctx context.Context) (int64, error) {
+	if l.retention <= 0 {
+		return 0, nil
+	}
+	result, err := l.mongoClient.GetCollection("match_decisions").DeleteMany(ctx,
+		bson.M{"created_at": bson.M{"$lt": time.Now().Add(-l.retention)}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// MatchDecisionFilter selects the match sets to export
+type MatchDecisionFilter struct {
+	From, To time.Time
+	Kind     models.MatchDecisionKind // empty for both kinds
+}
+
+// Export writes a row for every candidate of the match sets computed between
+// filter.From and filter.To, oldest first, with the outcome each has reached
+// so far. id renders IDs, e.g. as pseudonyms. It returns how many rows it
+// wrote.
+func (l *MatchDecisionLog) Export(ctx context.Context, filter MatchDecisionFilter, id func(kind string, id primitive.ObjectID) string, write func(models.MatchEvaluationRow) error) (int, error) {
+	query := bson.M{"created_at": bson.M{"$gte": filter.From, "$lt": filter.To}}
+	if filter.Kind != "" {
+		query["kind"] = filter.Kind
+	}
+	cursor, err := l.mongoClient.GetCollection("match_decisions").Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetBatchSize(evaluationBatchSize))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	written := 0
+	batch := make([]models.MatchDecision, 0, evaluationBatchSize)
+	flush := func() error {
+		outcomes, err := l.resolveOutcomes(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, decision := range batch {
+			subjectKind := "need"
+			if decision.Kind == models.MatchDecisionVolunteer {
+				subjectKind = "volunteer"
+			}
+			for _, candidate := range decision.Candidates {
+				row := models.MatchEvaluationRow{
+					DecisionID: id("match_decision", decision.ID),
+					Kind:       decision.Kind,
+					Subject:    id(subjectKind, decision.SubjectID),
+					Need:       id("need", candidate.NeedID),
+					Volunteer:  id("volunteer", candidate.VolunteerID),
+					Category:   candidate.Category,
+					Rank:       candidate.Rank,
+					SetSize:    len(decision.Candidates),
+					Score:      candidate.Score,
+					Features:   candidate.Features,
+					Outcome:    outcomes.of(decision, candidate),
+					ComputedAt: decision.CreatedAt,
+				}
+				if err := write(row); err != nil {
+					return err
+				}
+				written++
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var decision models.MatchDecision
+		if err := cursor.Decode(&decision); err != nil {
+			return written, err
+		}
+		batch = append(batch, decision)
+		if len(batch) == evaluationBatchSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return written, err
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// pairKey identifies a volunteer account's involvement with a need
+type pairKey struct {
+	needID, userID primitive.ObjectID
+}
+
+// matchOutcomes is what is known of the needs in a batch of match sets
+type matchOutcomes struct {
+	tasks    map[pairKey]models.TaskStatus // each volunteer's task on a need
+	taken    map[primitive.ObjectID]int    // live or completed tasks per need
+	notified map[pairKey]bool              // volunteers told of each need
+}
+
+// resolveOutcomes loads the tasks and notifications of every need in decisions
+func (l *MatchDecisionLog) resolveOutcomes(ctx context.Context, decisions []models.MatchDecision) (*matchOutcomes, error) {
+	seen := map[primitive.ObjectID]bool{}
+	var needIDs []primitive.ObjectID
+	for _, decision := range decisions {
+		for _, candidate := range decision.Candidates {
+			if !seen[candidate.NeedID] {
+				seen[candidate.NeedID] = true
+				needIDs = append(needIDs, candidate.NeedID)
+			}
+		}
+	}
+
+	outcomes := &matchOutcomes{
+		tasks:    map[pairKey]models.TaskStatus{},
+		taken:    map[primitive.ObjectID]int{},
+		notified: map[pairKey]bool{},
+	}
+
+	cursor, err := l.mongoClient.GetCollection("tasks").Find(ctx, bson.M{"need_id": bson.M{"$in": needIDs}},
+		options.Find().SetProjection(bson.M{"need_id": 1, "volunteer_id": 1, "status": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		key := pairKey{task.NeedID, task.VolunteerID}
+		// A volunteer who cancelled and took the need on again counts as taking it
+		if existing, ok := outcomes.tasks[key]; !ok || existing == models.TaskStatusCancelled {
+			outcomes.tasks[key] = task.Status
+		}
+		if task.Status != models.TaskStatusCancelled {
+			outcomes.taken[task.NeedID]++
+		}
+	}
+
+	cursor, err = l.mongoClient.GetCollection("needs").Find(ctx, bson.M{"_id": bson.M{"$in": needIDs}},
+		options.Find().SetProjection(bson.M{"notified": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var needs []models.Need
+	if err := cursor.All(ctx, &needs); err != nil {
+		return nil, err
+	}
+	for _, need := range needs {
+		for _, userID := range need.Notified {
+			outcomes.notified[pairKey{need.ID, userID}] = true
+		}
+	}
+	return outcomes, nil
+}
+
+// of returns the outcome a candidate has reached. Volunteers are shown the
+// needs matched for them; volunteers matched for a need are shown it once
+// notified.
+func (o *matchOutcomes) of(decision models.MatchDecision, candidate models.MatchCandidate) models.MatchOutcome {
+	key := pairKey{candidate.NeedID, candidate.UserID}
+	if status, ok := o.tasks[key]; ok {
+		switch status {
+		case models.TaskStatusCompleted:
+			return models.MatchOutcomeCompleted
+		case models.TaskStatusCancelled:
+			return models.MatchOutcomeDeclined
+		default:
+			return models.MatchOutcomeAccepted
+		}
+	}
+
+	if decision.Kind != models.MatchDecisionVolunteer && !o.notified[key] {
+		return models.MatchOutcomeNotShown
+	}
+	if o.taken[candidate.NeedID] > 0 {
+		return models.MatchOutcomeDeclined
+	}
+	return models.MatchOutcomeShown
+} 
\ No newline at end of file
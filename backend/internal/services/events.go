@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+var (
+	// ErrEventNotFound is returned for an event that does not exist or that
+	// the user cannot see
+	ErrEventNotFound = errors.New("event not found")
+	// ErrEventInPast is returned when creating an event that has already started
+	ErrEventInPast = errors.New("event must start in the future")
+	// ErrEventClosed is returned when signing up for a cancelled or finished event
+	ErrEventClosed = errors.New("event is no longer taking signups")
+	// ErrEventFull is returned when the event or shift has no places left
+	ErrEventFull = errors.New("no places left")
+	// ErrShiftRequired is returned when signing up for an event with shifts
+	// without choosing one
+	ErrShiftRequired = errors.New("shift_id is required for events with shifts")
+	// ErrShiftNotFound is returned for a shift the event does not have
+	ErrShiftNotFound = errors.New("shift not found")
+	// ErrAlreadySignedUp is returned when signing up for the same slot twice
+	ErrAlreadySignedUp = errors.New("already signed up")
+	// ErrSignupNotFound is returned for a signup that does not exist
+	ErrSignupNotFound = errors.New("signup not found")
+	// ErrNotEventOrganizer is returned when the user may not manage the event
+	ErrNotEventOrganizer = errors.New("only the event's organizers can do this")
+)
+
+// EventService manages group volunteering events and their signups
+type EventService struct {
+	mongoClient  *database.MongoClient
+	groupService *GroupService
+}
+
+// NewEventService creates a new event service
+func NewEventService(mongoClient *database.MongoClient, groupService *GroupService) *EventService {
+	return &EventService{
+		mongoClient:  mongoClient,
+		groupService: groupService,
+	}
+}
+
+// Create creates an event organized by organizer. Events for a group may
+// only be created by its admins.
+func (s *EventService) Create(ctx context.Context, organizer *models.User, req models.CreateEventRequest, location models.Location) (*models.Event, error) {
+	now := time.Now()
+	if !req.StartsAt.After(now) {
+		return nil, ErrEventInPast
+	}
+
+	event := models.Event{
+		ID:          primitive.NewObjectID(),
+		Title:       req.Title,
+		Description: req.Description,
+		Category:    req.Category,
+		Location:    location,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Capacity:    req.Capacity,
+		OrganizerID: organizer.ID,
+		Status:      models.EventScheduled,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if req.GroupID != "" {
+		groupID, err := primitive.ObjectIDFromHex(req.GroupID)
+		if err != nil {
+			return nil, ErrGroupNotFound
+		}
+		if err := s.groupService.Authorize(ctx, groupID, organizer, true); err != nil {
+			return nil, err
+		}
+		event.GroupID = &groupID
+	}
+	if len(req.Shifts) > 0 {
+		event.Capacity = 0
+		for _, shift := range req.Shifts {
+			event.Shifts = append(event.Shifts, models.EventShift{
+				ID:       primitive.NewObjectID(),
+				Name:     shift.Name,
+				StartsAt: shift.StartsAt,
+				EndsAt:   shift.EndsAt,
+				Capacity: shift.Capacity,
+			})
+			event.Capacity += shift.Capacity
+		}
+	}
+
+	if _, err := s.mongoClient.GetCollection("events").InsertOne(ctx, event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Get returns an event the viewer may see. Group events are visible to
+// the group's members and to site admins.
+func (s *EventService) Get(ctx context.Context, eventID primitive.ObjectID, viewer *models.User) (*models.Event, error) {
+	var event models.Event
+	err := s.mongoClient.GetCollection("events").FindOne(ctx, bson.M{"_id": eventID}).Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if event.GroupID != nil && event.OrganizerID != viewer.ID {
+		if err := s.groupService.Authorize(ctx, *event.GroupID, viewer, false); err != nil {
+			if errors.Is(err, ErrNotGroupMember) || errors.Is(err, ErrGroupNotFound) {
+				return nil, ErrEventNotFound
+			}
+			return nil, err
+		}
+	}
+	return &event, nil
+}
+
+// List returns the scheduled events that have not ended, soonest first.
+// Group events are only listed for the group's members. A non-nil groupID
+// limits the list to that group's events, and mine to events the viewer
+// organizes or has signed up for.
+func (s *EventService) List(ctx context.Context, viewer *models.User, groupID *primitive.ObjectID, mine bool, limit, offset int64) ([]models.Event, error) {
+	filter := bson.M{
+		"status":  models.EventScheduled,
+		"ends_at": bson.M{"$gt": time.Now()},
+	}
+	if groupID != nil {
+		if err := s.groupService.Authorize(ctx, *groupID, viewer, false); err != nil {
+			return nil, err
+		}
+		filter["group_id"] = *groupID
+	} else if viewer.Role != models.RoleAdmin {
+		groupIDs, err := s.mongoClient.GetCollection("group_members").Distinct(ctx, "group_id", bson.M{"user_id": viewer.ID})
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = []bson.M{
+			{"group_id": bson.M{"$exists": false}},
+			{"group_id": bson.M{"$in": groupIDs}},
+			{"organizer_id": viewer.ID},
+		}
+	}
+	if mine {
+		eventIDs, err := s.mongoClient.GetCollection("event_signups").Distinct(ctx, "event_id", bson.M{"user_id": viewer.ID})
+		if err != nil {
+			return nil, err
+		}
+		filter["$and"] = []bson.M{{"$or": []bson.M{
+			{"_id": bson.M{"$in": eventIDs}},
+			{"organizer_id": viewer.ID},
+		}}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "starts_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("events").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.Event{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Authorize checks that user may manage an event: its organizer, a site
+// admin, or an admin of the event's group
+func (s *EventService) Authorize(ctx context.Context, event *models.Event, user *models.User) error {
+	if event.OrganizerID == user.ID || user.Role == models.RoleAdmin {
+		return nil
+	}
+	if event.GroupID != nil {
+		err := s.groupService.Authorize(ctx, *event.GroupID, user, true)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrNotGroupAdmin) && !errors.Is(err, ErrNotGroupMember) && !errors.Is(err, ErrGroupNotFound) {
+			return err
+		}
+	}
+	return ErrNotEventOrganizer
+}
+
+// Cancel cancels a scheduled event and returns the IDs of the users signed
+// up for it, so they can be told
+func (s *EventService) Cancel(ctx context.Context, eventID primitive.ObjectID) ([]string, error) {
+	result, err := s.mongoClient.GetCollection("events").UpdateOne(ctx,
+		bson.M{"_id": eventID, "status": models.EventScheduled},
+		bson.M{"$set": bson.M{"status": models.EventCancelled, "updated_at": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrEventClosed
+	}
+
+	values, err := s.mongoClient.GetCollection("event_signups").Distinct(ctx, "user_id", bson.M{"event_id": eventID})
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			userIDs = append(userIDs, id.Hex())
+		}
+	}
+	return userIDs, nil
+}
+
+// Signup takes a place at an event, or at one of its shifts, for user
+func (s *EventService) Signup(ctx context.Context, event *models.Event, user *models.User, shiftID *primitive.ObjectID) (*models.EventSignup, error) {
+	now := time.Now()
+	if event.Status != models.EventScheduled || !event.EndsAt.After(now) {
+		return nil, ErrEventClosed
+	}
+	if err := CheckTaskEligibility(user, event.Category, now); err != nil {
+		return nil, err
+	}
+	if event.GroupID != nil && event.OrganizerID != user.ID {
+		if err := s.groupService.Authorize(ctx, *event.GroupID, user, false); err != nil {
+			return nil, err
+		}
+	}
+
+	signup := models.EventSignup{
+		ID:        primitive.NewObjectID(),
+		EventID:   event.ID,
+		UserID:    user.ID,
+		StartsAt:  event.StartsAt,
+		CreatedAt: now,
+	}
+	filter := bson.M{"_id": event.ID, "status": models.EventScheduled}
+	update := bson.M{"$inc": bson.M{"signed_up": 1}}
+	switch {
+	case len(event.Shifts) > 0 && shiftID == nil:
+		return nil, ErrShiftRequired
+	case len(event.Shifts) > 0:
+		shift := findShift(event, *shiftID)
+		if shift == nil {
+			return nil, ErrShiftNotFound
+		}
+		signup.ShiftID = shiftID
+		signup.StartsAt = shift.StartsAt
+		filter["shifts"] = bson.M{"$elemMatch": bson.M{"id": shift.ID, "signed_up": bson.M{"$lt": shift.Capacity}}}
+		update["$inc"] = bson.M{"signed_up": 1, "shifts.$.signed_up": 1}
+	default:
+		filter["signed_up"] = bson.M{"$lt": event.Capacity}
+	}
+
+	// Reserve the place first, so concurrent signups cannot overfill it
+	events := s.mongoClient.GetCollection("events")
+	result, err := events.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrEventFull
+	}
+
+	if _, err := s.mongoClient.GetCollection("event_signups").InsertOne(ctx, signup); err != nil {
+		if releaseErr := s.release(ctx, event.ID, signup.ShiftID); releaseErr != nil {
+			return nil, releaseErr
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadySignedUp
+		}
+		return nil, err
+	}
+	return &signup, nil
+}
+
+// CancelSignup removes a signup, freeing its place. Volunteers may cancel
+// their own signups and organizers anyone's.
+func (s *EventService) CancelSignup(ctx context.Context, event *models.Event, signupID primitive.ObjectID, user *models.User) error {
+	signup, err := s.findSignup(ctx, event.ID, signupID)
+	if err != nil {
+		return err
+	}
+	if signup.UserID != user.ID {
+		if err := s.Authorize(ctx, event, user); err != nil {
+			return err
+		}
+	}
+
+	result, err := s.mongoClient.GetCollection("event_signups").DeleteOne(ctx, bson.M{"_id": signup.ID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrSignupNotFound
+	}
+	return s.release(ctx, event.ID, signup.ShiftID)
+}
+
+// release gives back a place reserved at an event or shift
+func (s *EventService) release(ctx context.Context, eventID primitive.ObjectID, shiftID *primitive.ObjectID) error {
+	filter := bson.M{"_id": eventID}
+	inc := bson.M{"signed_up": -1}
+	if shiftID != nil {
+		filter["shifts.id"] = *shiftID
+		inc["shifts.$.signed_up"] = -1
+	}
+	_, err := s.mongoClient.GetCollection("events").UpdateOne(ctx, filter, bson.M{"$inc": inc})
+	return err
+}
+
+// Signups lists an event's signups, optionally only those of userID, by
+// start time
+func (s *EventService) Signups(ctx context.Context, eventID primitive.ObjectID, userID *primitive.ObjectID) ([]models.EventSignup, error) {
+	filter := bson.M{"event_id": eventID}
+	if userID != nil {
+		filter["user_id"] = *userID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := s.mongoClient.GetCollection("event_signups").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	signups := []models.EventSignup{}
+	if err := cursor.All(ctx, &signups); err != nil {
+		return nil, err
+	}
+	return signups, nil
+}
+
+// RecordAttendance marks whether a signed-up volunteer attended
+func (s *EventService) RecordAttendance(ctx context.Context, eventID, signupID primitive.ObjectID, attendance models.Attendance) (*models.EventSignup, error) {
+	set := bson.M{"attendance": attendance}
+	update := bson.M{"$set": set}
+	if attendance == models.AttendanceAttended {
+		set["checked_in_at"] = time.Now()
+	} else {
+		update["$unset"] = bson.M{"checked_in_at": ""}
+	}
+
+	var signup models.EventSignup
+	err := s.mongoClient.GetCollection("event_signups").FindOneAndUpdate(ctx,
+		bson.M{"_id": signupID, "event_id": eventID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&signup)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSignupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &signup, nil
+}
+
+// ClaimDueReminder marks one signup starting within lead as reminded and
+// returns it with its event, or nil if none is due. Claiming one at a time
+// lets several workers send reminders without sending any twice.
+func (s *EventService) ClaimDueReminder(ctx context.Context, lead time.Duration) (*models.EventSignup, *models.Event, error) {
+	now := time.Now()
+	filter := bson.M{
+		"reminded_at": bson.M{"$exists": false},
+		"starts_at":   bson.M{"$gt": now, "$lte": now.Add(lead)},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "starts_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var signup models.EventSignup
+	err := s.mongoClient.GetCollection("event_signups").FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"reminded_at": now}}, opts).Decode(&signup)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var event models.Event
+	if err := s.mongoClient.GetCollection("events").FindOne(ctx, bson.M{"_id": signup.EventID}).Decode(&event); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &signup, nil, nil
+		}
+		return nil, nil, err
+	}
+	return &signup, &event, nil
+}
+
+// Unavailable reports whether the user's volunteer profile has an exception
+// marking them unavailable at t, so reminders can flag the clash
+func (s *EventService) Unavailable(ctx context.Context, userID primitive.ObjectID, t time.Time) (bool, error) {
+	var volunteer models.Volunteer
+	opts := options.FindOne().SetProjection(bson.M{"availability_exceptions": 1, "timezone": 1})
+	err := s.mongoClient.GetCollection("volunteers").FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return volunteer.UnavailableAt(t), nil
+}
+
+// findSignup returns a signup of an event
+func (s *EventService) findSignup(ctx context.Context, eventID, signupID primitive.ObjectID) (*models.EventSignup, error) {
+	var signup models.EventSignup
+	err := s.mongoClient.GetCollection("event_signups").FindOne(ctx, bson.M{"_id": signupID, "event_id": eventID}).Decode(&signup)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSignupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &signup, nil
+}
+
+// findShift returns the event's shift with id, or nil
+func findShift(event *models.Event, id primitive.ObjectID) *models.EventShift {
+	for i := range event.Shifts {
+		if event.Shifts[i].ID == id {
+			return &event.Shifts[i]
+		}
+	}
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+const (
+	// needExtension is how much longer extending a need keeps it open
+	needExtension = 7 * 24 * time.Hour
+	// extensionWindow is how long before its expiry a need can be extended
+	extensionWindow = 2 * 24 * time.Hour
+)
+
+// ErrNeedNotExpiring is returned when extending a need that is not about to
+// expire
+var ErrNeedNotExpiring = errors.New("needs can only be extended in the 2 days before they expire")
+
+// ExpiryService closes needs nobody took on before they expired, and lets
+// their creators keep them open longer
+type ExpiryService struct {
+	mongoClient   *database.MongoClient
+	notifications *NotificationService
+	notify        Notifier
+}
+
+// NewExpiryService creates a new expiry service. Creators are told their
+// needs expired through notify and emailed by notificationService.
+func NewExpiryService(mongoClient *database.MongoClient, notificationService *NotificationService, notify Notifier) *ExpiryService {
+	return &ExpiryService{
+		mongoClient:   mongoClient,
+		notifications: notificationService,
+		notify:        notify,
+	}
+}
+
+// ExpireDue marks requested needs past their expiry as expired and tells
+// their creators, returning how many it expired. Needs a volunteer has
+// accepted stay open until their task closes.
+func (s *ExpiryService) ExpireDue(ctx context.Context) (int, error) {
+	collection := s.mongoClient.GetCollection("needs")
+	now := time.Now()
+	cursor, err := collection.Find(ctx, bson.M{
+		"status":     models.NeedStatusRequested,
+		"expires_at": bson.M{"$lte": now},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var due []models.Need
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, need := range due {
+		var updated models.Need
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{"_id": need.ID, "status": models.NeedStatusRequested, "expires_at": bson.M{"$lte": now}},
+			bson.M{
+				"$set":   bson.M{"status": models.NeedStatusExpired, "updated_at": now},
+				"$unset": bson.M{"waitlisted_at": ""},
+			},
+			options.FindOneAndUpdate().
+				SetProjection(bson.M{"user_id": 1, "title": 1, "status": 1, "expires_at": 1}).
+				SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err == mongo.ErrNoDocuments {
+			continue // accepted, cancelled, or extended in the meantime
+		}
+		if err != nil {
+			return expired, err
+		}
+		s.expired(ctx, &updated)
+		expired++
+	}
+	return expired, nil
+}
+
+// Extend keeps one of userID's needs open for another week from now. Needs
+// can only be extended once they are within extensionWindow of expiring, so
+// a need is never open for more than a week ahead. An expired need is
+// requested again, which Extend reports so its volunteers can be matched
+// afresh.
+func (s *ExpiryService) Extend(ctx context.Context, needID, userID primitive.ObjectID) (*models.Need, bool, error) {
+	collection := s.mongoClient.GetCollection("needs")
+	var need models.Need
+	err := collection.FindOne(ctx, bson.M{"_id": needID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1, "status": 1, "expires_at": 1})).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, ErrNeedNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case need.UserID != userID:
+		return nil, false, ErrNotNeedOwner
+	case need.Status != models.NeedStatusRequested && need.Status != models.NeedStatusMatched && need.Status != models.NeedStatusExpired:
+		return nil, false, ErrNeedClosed
+	}
+
+	now := time.Now()
+	if need.ExpiresAt == nil || need.ExpiresAt.After(now.Add(extensionWindow)) {
+		return nil, false, ErrNeedNotExpiring
+	}
+	set := bson.M{"expires_at": now.Add(needExtension), "updated_at": now}
+	reopened := need.Status == models.NeedStatusExpired
+	if reopened {
+		set["status"] = models.NeedStatusRequested
+	}
+
+	// Extending against the status and expiry read above keeps a sweep or a
+	// second extension from interleaving with this one
+	var updated models.Need
+	err = collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": need.ID, "status": need.Status, "expires_at": need.ExpiresAt},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetProjection(bson.M{"translations": 0}).SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, ErrNeedClosed
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &updated, reopened, nil
+}
+
+// expired tells a need's creator it expired, in the app and by email. A
+// failed notification is logged; the need stays expired either way.
+func (s *ExpiryService) expired(ctx context.Context, need *models.Need) {
+	message := models.WebSocketMessage{
+		Type: "need_expired",
+		Payload: map[string]interface{}{
+			"need_id":    need.ID.Hex(),
+			"status":     need.Status,
+			"expired_at": need.ExpiresAt,
+		},
+	}
+	if err := s.notify(ctx, []string{need.UserID.Hex()}, message); err != nil {
+		log.Printf("Failed to notify the creator of expired need %s: %v", need.ID.Hex(), err)
+	}
+	s.notifications.NeedExpired(ctx, *need)
+} 
\ No newline at end of file
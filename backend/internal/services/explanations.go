@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+)
+
+// ErrMatchUnexplained is returned for a match found before scores were
+// broken down, until matching scores it again
+var ErrMatchUnexplained = errors.New("this match has no breakdown yet; it gets one the next time matching runs")
+
+// Similarity bands for explanations. Embeddings of unrelated text seldom
+// score much below 0.7, so the bands sit high.
+const (
+	strongSimilarity = 0.85
+	goodSimilarity   = 0.8
+)
+
+// ExplainMatch breaks down the score of a user's match with a need, in
+// lang with distances in units
+func (m *MatchingService) ExplainMatch(ctx context.Context, userID, needID primitive.ObjectID, units models.Units, lang models.Language) (*models.MatchExplanation, error) {
+	var match models.Match
+	err := m.mongoClient.GetCollection("matches").FindOne(ctx,
+		bson.M{"user_id": userID, "need_id": needID},
+		options.FindOne().SetSort(bson.M{"updated_at": -1})).Decode(&match)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if match.Components == nil {
+		return nil, ErrMatchUnexplained
+	}
+
+	l := string(lang)
+	components := *match.Components
+	reasons := make([]models.MatchReason, 0, 5)
+
+	similarity := i18n.T(l, "Your skills and interests are a partial fit for this need")
+	switch {
+	case components.Similarity >= strongSimilarity:
+		similarity = i18n.T(l, "Your skills and interests are a strong fit for this need")
+	case components.Similarity >= goodSimilarity:
+		similarity = i18n.T(l, "Your skills and interests are a good fit for this need")
+	}
+	reasons = append(reasons, models.MatchReason{Component: "similarity", Score: componentScore(components.Similarity), Text: similarity})
+
+	reasons = append(reasons, models.MatchReason{
+		Component: "distance",
+		Score:     componentScore(components.DistanceScore),
+		Text:      i18n.Tf(l, "The need is %s away; closer needs score higher", LocalizeDistance(match.Distance, units, lang).Text),
+	})
+
+	availability := i18n.T(l, "You are free for as long as the need takes")
+	if components.Availability < 1 {
+		availability = i18n.T(l, "You have some time free, though less than the need takes or later than it is wanted")
+	}
+	reasons = append(reasons, models.MatchReason{Component: "availability", Score: componentScore(components.Availability), Text: availability})
+
+	category := i18n.T(l, "You help with needs in every category")
+	if components.CategoryMatch {
+		category = i18n.T(l, "The need is in a category you chose to help with")
+	}
+	reasons = append(reasons, models.MatchReason{Component: "category", Text: category})
+
+	if match.Priority {
+		reasons = append(reasons, models.MatchReason{Component: "priority", Text: i18n.T(l, "An active emergency puts this need first")})
+	}
+
+	return &models.MatchExplanation{
+		NeedID:     match.NeedID,
+		Score:      match.Score,
+		Components: components,
+		Reasons:    reasons,
+	}, nil
+}
+
+// componentScore rounds a component score for display
+func componentScore(score float64) *float64 {
+	score = math.Round(score*1000) / 1000
+	return &score
+} 
\ No newline at end of file
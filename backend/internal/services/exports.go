@@ -0,0 +1,375 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// Data export errors
+var (
+	ErrExportNotFound = errors.New("export not found")
+	ErrExportExpired  = errors.New("export has expired")
+)
+
+// ExportService assembles archives of everything stored about a user and
+// hands out signed, expiring links to download them
+type ExportService struct {
+	mongoClient *database.MongoClient
+	signingKey  []byte
+	retention   time.Duration
+	linkTTL     time.Duration
+}
+
+// NewExportService creates a new export service. Archives are kept for
+// retention; download links are valid for linkTTL.
+func NewExportService(mongoClient *database.MongoClient, signingKey string, retention, linkTTL time.Duration) *ExportService {
+	return &ExportService{
+		mongoClient: mongoClient,
+		signingKey:  []byte("data-export:" + signingKey),
+		retention:   retention,
+		linkTTL:     linkTTL,
+	}
+}
+
+// Request starts an export for a user. An export that is already pending or
+// processing is returned instead of starting another; created reports
+// whether a new export needs assembling.
+func (s *ExportService) Request(ctx context.Context, userID primitive.ObjectID) (export *models.DataExport, created bool, err error) {
+	collection := s.mongoClient.GetCollection("data_exports")
+
+	var existing models.DataExport
+	err = collection.FindOne(ctx, bson.M{
+		"user_id": userID,
+		"status":  bson.M{"$in": []models.ExportStatus{models.ExportPending, models.ExportProcessing}},
+	}).Decode(&existing)
+	if err == nil {
+		return &existing, false, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, false, err
+	}
+
+	// Only the newest archive is kept
+	if err := s.removeArchives(ctx, bson.M{"user_id": userID}); err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	export = &models.DataExport{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Status:    models.ExportPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := collection.InsertOne(ctx, export); err != nil {
+		return nil, false, err
+	}
+	return export, true, nil
+}
+
+// Latest returns the user's most recent export
+func (s *ExportService) Latest(ctx context.Context, userID primitive.ObjectID) (*models.DataExport, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	var export models.DataExport
+	err := s.mongoClient.GetCollection("data_exports").FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&export)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Build assembles the archive for a pending export. Failures are recorded on
+// the export so the user sees them when polling.
+func (s *ExportService) Build(ctx context.Context, id primitive.ObjectID) error {
+	collection := s.mongoClient.GetCollection("data_exports")
+
+	var export models.DataExport
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.ExportPending},
+		bson.M{"$set": bson.M{"status": models.ExportProcessing, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&export)
+	if err == mongo.ErrNoDocuments {
+		// Already picked up by another worker
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fileID, size, err := s.assemble(ctx, &export)
+	now := time.Now()
+	if err != nil {
+		_, updateErr := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+			"status":     models.ExportFailed,
+			"error":      "Failed to assemble export",
+			"updated_at": now,
+		}})
+		if updateErr != nil {
+			log.Printf("Failed to mark export %s failed: %v", id.Hex(), updateErr)
+		}
+		return err
+	}
+
+	expiresAt := now.Add(s.retention)
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":       models.ExportReady,
+		"file_id":      fileID,
+		"size":         size,
+		"completed_at": now,
+		"expires_at":   expiresAt,
+		"updated_at":   now,
+	}})
+	return err
+}
+
+// SignedPath appends a signature and expiry to a download path for export
+func (s *ExportService) SignedPath(path string, export *models.DataExport) string {
+	expires := time.Now().Add(s.linkTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expires) {
+		expires = *export.ExpiresAt
+	}
+	unix := strconv.FormatInt(expires.Unix(), 10)
+	return path + "?expires=" + unix + "&signature=" + s.sign(export.ID, unix)
+}
+
+// Open verifies a download signature and returns a reader over the archive,
+// which the caller must close
+func (s *ExportService) Open(ctx context.Context, id primitive.ObjectID, expires, signature string) (io.ReadCloser, *models.DataExport, error) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(id, expires))) {
+		return nil, nil, ErrExportNotFound
+	}
+	if time.Now().Unix() > unix {
+		return nil, nil, ErrExportExpired
+	}
+
+	var export models.DataExport
+	err = s.mongoClient.GetCollection("data_exports").FindOne(ctx, bson.M{"_id": id, "status": models.ExportReady}).Decode(&export)
+	if err == mongo.ErrNoDocuments || (err == nil && export.FileID == nil) {
+		return nil, nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return nil, nil, ErrExportExpired
+	}
+
+	bucket, err := s.bucket()
+	if err != nil {
+		return nil, nil, err
+	}
+	stream, err := bucket.OpenDownloadStream(*export.FileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stream, &export, nil
+}
+
+// PurgeExpired deletes archives past their retention, returning how many
+func (s *ExportService) PurgeExpired(ctx context.Context) (int, error) {
+	filter := bson.M{"expires_at": bson.M{"$lt": time.Now()}, "file_id": bson.M{"$exists": true}}
+	count, err := s.mongoClient.GetCollection("data_exports").CountDocuments(ctx, filter)
+	if err != nil || count == 0 {
+		return 0, err
+	}
+	return int(count), s.removeArchives(ctx, filter)
+}
+
+// DeleteForUser removes every export and archive belonging to a user,
+// returning how many exports were deleted
+func (s *ExportService) DeleteForUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	if err := s.removeArchives(ctx, bson.M{"user_id": userID}); err != nil {
+		return 0, err
+	}
+	result, err := s.mongoClient.GetCollection("data_exports").DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// removeArchives deletes the stored archives of the matching exports
+func (s *ExportService) removeArchives(ctx context.Context, filter bson.M) error {
+	collection := s.mongoClient.GetCollection("data_exports")
+	filter["file_id"] = bson.M{"$exists": true}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var exports []models.DataExport
+	if err := cursor.All(ctx, &exports); err != nil {
+		return err
+	}
+
+	bucket, err := s.bucket()
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err := bucket.DeleteContext(ctx, *export.FileID); err != nil && err != gridfs.ErrFileNotFound {
+			return err
+		}
+		_, err := collection.UpdateOne(ctx, bson.M{"_id": export.ID}, bson.M{"$unset": bson.M{"file_id": "", "size": ""}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assemble writes the user's data as JSON files in a zip archive and stores
+// it in GridFS
+func (s *ExportService) assemble(ctx context.Context, export *models.DataExport) (primitive.ObjectID, int64, error) {
+	userID := export.UserID
+	needIDs := []primitive.ObjectID{}
+
+	sections := []struct {
+		name string
+		load func() (interface{}, error)
+	}{
+		{"profile.json", func() (interface{}, error) {
+			var user models.User
+			err := s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+			return user, err
+		}},
+		{"volunteer_profile.json", func() (interface{}, error) {
+			var volunteers []models.Volunteer
+			return volunteers, s.findAll(ctx, "volunteers", bson.M{"user_id": userID}, &volunteers)
+		}},
+		{"offers.json", func() (interface{}, error) {
+			var offers []models.Offer
+			return offers, s.findAll(ctx, "offers", bson.M{"user_id": userID}, &offers)
+		}},
+		{"needs.json", func() (interface{}, error) {
+			var needs []models.Need
+			err := s.findAll(ctx, "needs", bson.M{"user_id": userID}, &needs)
+			for _, need := range needs {
+				needIDs = append(needIDs, need.ID)
+			}
+			return needs, err
+		}},
+		{"tasks.json", func() (interface{}, error) {
+			var tasks []models.Task
+			filter := bson.M{"$or": []bson.M{{"volunteer_id": userID}, {"need_id": bson.M{"$in": needIDs}}}}
+			return tasks, s.findAll(ctx, "tasks", filter, &tasks)
+		}},
+		{"feedback.json", func() (interface{}, error) {
+			var feedback []models.Feedback
+			// Feedback received is left out until it is revealed
+			filter := bson.M{"$or": []bson.M{
+				{"from_user_id": userID},
+				{"to_user_id": userID, "revealed_at": bson.M{"$exists": true}},
+			}}
+			if err := s.findAll(ctx, "feedback", filter, &feedback); err != nil {
+				return nil, err
+			}
+			// Incidents reported against the user stay with moderators
+			for i := range feedback {
+				if feedback[i].ToUserID == userID {
+					feedback[i].Incident = nil
+				}
+			}
+			return feedback, nil
+		}},
+		{"messages.json", func() (interface{}, error) {
+			var messages []bson.M
+			filter := bson.M{"$or": []bson.M{{"sender_id": userID}, {"recipient_id": userID}}}
+			return messages, s.findAll(ctx, "messages", filter, &messages)
+		}},
+		{"notifications.json", func() (interface{}, error) {
+			var notifications []bson.M
+			return notifications, s.findAll(ctx, "notifications", bson.M{"user_id": userID}, &notifications)
+		}},
+		{"kudos.json", func() (interface{}, error) {
+			var kudos []models.Kudos
+			filter := bson.M{"$or": []bson.M{{"from_user_id": userID}, {"to_user_id": userID}}}
+			return kudos, s.findAll(ctx, "kudos", filter, &kudos)
+		}},
+		{"points.json", func() (interface{}, error) {
+			var points []models.PointsEntry
+			return points, s.findAll(ctx, "points", bson.M{"user_id": userID}, &points)
+		}},
+		{"reports.json", func() (interface{}, error) {
+			var reports []models.AbuseReport
+			return reports, s.findAll(ctx, "reports", bson.M{"reporter_id": userID}, &reports)
+		}},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, section := range sections {
+		data, err := section.load()
+		if err != nil {
+			return primitive.NilObjectID, 0, err
+		}
+		file, err := archive.Create(section.name)
+		if err != nil {
+			return primitive.NilObjectID, 0, err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(data); err != nil {
+			return primitive.NilObjectID, 0, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return primitive.NilObjectID, 0, err
+	}
+
+	bucket, err := s.bucket()
+	if err != nil {
+		return primitive.NilObjectID, 0, err
+	}
+	size := int64(buf.Len())
+	fileID, err := bucket.UploadFromStream("export-"+export.ID.Hex()+".zip", &buf)
+	if err != nil {
+		return primitive.NilObjectID, 0, err
+	}
+	return fileID, size, nil
+}
+
+// findAll decodes every matching document into results
+func (s *ExportService) findAll(ctx context.Context, collectionName string, filter bson.M, results interface{}) error {
+	cursor, err := s.mongoClient.GetCollection(collectionName).Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
+
+// bucket returns the GridFS bucket archives are stored in
+func (s *ExportService) bucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(s.mongoClient.DB, options.GridFSBucket().SetName("exports"))
+}
+
+// sign returns the download signature for an export and expiry
+func (s *ExportService) sign(id primitive.ObjectID, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(id.Hex() + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+} 
\ No newline at end of file
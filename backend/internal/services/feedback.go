@@ -0,0 +1,803 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// lowRatingMax is the highest rating its recipient may respond to or appeal
+const lowRatingMax = 2
+
+// feedbackOverdueLookback bounds how far back missing feedback can count as
+// overdue, so tasks from long before the policy was turned on never block
+const feedbackOverdueLookback = 90 * 24 * time.Hour
+
+var (
+	// ErrTaskNotCompleted is returned for feedback on a task that has not
+	// been completed
+	ErrTaskNotCompleted = errors.New("feedback can only be left on completed tasks")
+	// ErrFeedbackExists is returned when a participant rates the same task
+	// twice
+	ErrFeedbackExists = errors.New("you have already left feedback on this task")
+	// ErrFeedbackClosed is returned when the other participant's feedback
+	// was already revealed, so new feedback could be a reaction to it
+	ErrFeedbackClosed = errors.New("the feedback window for this task has closed")
+	// ErrFeedbackNotFound is returned for feedback that does not exist, was
+	// not given to the user, or has not been revealed to them
+	ErrFeedbackNotFound = errors.New("feedback not found")
+	// ErrRatingNotLow is returned when responding to or appealing a rating
+	// above lowRatingMax
+	ErrRatingNotLow = errors.New("only ratings of 2 stars or fewer can be responded to or appealed")
+	// ErrResponseExists is returned when responding to feedback twice
+	ErrResponseExists = errors.New("you have already responded to this feedback")
+	// ErrAppealExists is returned when appealing feedback twice
+	ErrAppealExists = errors.New("this feedback has already been appealed")
+	// ErrFeedbackOverdue is returned when a user with overdue feedback tries
+	// to take on a new need
+	ErrFeedbackOverdue = errors.New("leave feedback on your completed tasks before accepting new needs")
+	// ErrNoComment is returned when sharing feedback without a comment as a
+	// testimonial
+	ErrNoComment = errors.New("only feedback with a comment can be shared")
+	// ErrNotShared is returned when approving feedback its author has not
+	// shared
+	ErrNotShared = errors.New("the author has not shared this feedback")
+	// ErrMatchingSuspended is returned when a user suspended pending review
+	// of a safety incident tries to take on a need
+	ErrMatchingSuspended = errors.New("your account is paused from matching while moderators review a safety report")
+)
+
+// FeedbackService records two-sided task feedback, reveals it once both
+// participants have rated each other or the reveal window passes, and queues
+// the recipients' reputations to be recomputed. Recipients of low ratings
+// can respond to them and appeal them to moderators. Feedback can also report
+// a safety incident, which moderators review whatever the rating.
+type FeedbackService struct {
+	mongoClient *database.MongoClient
+	moderation  *ModerationService
+	ratings     *RatingService
+	themes      *ThemeSummarizer
+	revealAfter time.Duration
+	dueAfter    time.Duration
+	suspendAt   models.IncidentSeverity
+}
+
+// NewFeedbackService creates a new feedback service. Feedback left
+// unanswered is revealed revealAfter it was given, appeals are queued with
+// moderationService, revealed ratings are rolled up by ratingService, and
+// volunteers' comments are summarized into themes with summarizer. Feedback
+// not given dueAfter a task was completed is overdue; zero never makes it
+// overdue. Users reported for an incident of suspendAt severity or worse
+// are suspended from matching until it is reviewed; an empty suspendAt
+// never suspends.
+func NewFeedbackService(mongoClient *database.MongoClient, moderationService *ModerationService, ratingService *RatingService, summarizer *ThemeSummarizer, revealAfter, dueAfter time.Duration, suspendAt models.IncidentSeverity) *FeedbackService {
+	return &FeedbackService{
+		mongoClient: mongoClient,
+		moderation:  moderationService,
+		ratings:     ratingService,
+		themes:      summarizer,
+		revealAfter: revealAfter,
+		dueAfter:    dueAfter,
+		suspendAt:   suspendAt,
+	}
+}
+
+// Submit records fromUserID's feedback on the other participant of a
+// completed task. When the other participant has already rated them, both
+// are revealed at once.
+func (s *FeedbackService) Submit(ctx context.Context, taskID, fromUserID primitive.ObjectID, req models.FeedbackRequest) (*models.Feedback, error) {
+	task, toUserID, err := s.taskPartner(ctx, taskID, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != models.TaskStatusCompleted {
+		return nil, ErrTaskNotCompleted
+	}
+
+	collection := s.mongoClient.GetCollection("feedback")
+	var counterpart models.Feedback
+	err = collection.FindOne(ctx, bson.M{"task_id": taskID, "from_user_id": toUserID}).Decode(&counterpart)
+	answered := err == nil
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if answered && counterpart.RevealedAt != nil {
+		return nil, ErrFeedbackClosed
+	}
+
+	now := time.Now()
+	feedback := models.Feedback{
+		ID:         primitive.NewObjectID(),
+		TaskID:     taskID,
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+		CreatedAt:  now,
+	}
+	if answered {
+		feedback.RevealedAt = &now
+	}
+	if req.Shareable && req.Comment != "" {
+		feedback.Testimonial = &models.Testimonial{SharedAt: now}
+	}
+	// The incident goes to moderators before the feedback is stored, so a
+	// failed write can lose the rating but never the safety report
+	if req.Incident != nil {
+		incident, err := s.reportIncident(ctx, feedback.ID, toUserID, req.Incident)
+		if err != nil {
+			return nil, err
+		}
+		feedback.Incident = incident
+	}
+	if _, err := collection.InsertOne(ctx, feedback); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrFeedbackExists
+		}
+		return nil, err
+	}
+	if !answered {
+		return &feedback, nil
+	}
+
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": counterpart.ID, "revealed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revealed_at": now}})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ratings.Changed(ctx, toUserID, fromUserID); err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// TaskFeedback returns the feedback on a task that viewerID may see: what
+// they gave, and what they received once it is revealed. Each carries its
+// author's summary, looked up in the same aggregation.
+func (s *FeedbackService) TaskFeedback(ctx context.Context, taskID, viewerID primitive.ObjectID) ([]models.Feedback, error) {
+	if _, _, err := s.taskPartner(ctx, taskID, viewerID); err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.mongoClient.GetCollection("feedback").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"task_id": taskID, "$or": []bson.M{
+			{"from_user_id": viewerID},
+			{"revealed_at": bson.M{"$exists": true}, "hidden": bson.M{"$ne": true}},
+		}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}}}},
+		lookupUserSummary("from_user_id", "from_user"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		models.Feedback `bson:",inline"`
+		From            []models.User `bson:"from_user"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	feedback := make([]models.Feedback, len(rows))
+	for i, row := range rows {
+		feedback[i] = row.Feedback
+		feedback[i].FromUser = firstSummary(row.From)
+		if feedback[i].FromUserID != viewerID {
+			feedback[i].Incident = nil
+		}
+	}
+	return feedback, nil
+}
+
+// Respond posts the recipient's public reply to a low rating they received
+func (s *FeedbackService) Respond(ctx context.Context, feedbackID, userID primitive.ObjectID, req models.FeedbackResponseRequest) (*models.Feedback, error) {
+	feedback, err := s.lowRating(ctx, feedbackID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := models.FeedbackResponse{Text: req.Text, CreatedAt: time.Now()}
+	result, err := s.mongoClient.GetCollection("feedback").UpdateOne(ctx,
+		bson.M{"_id": feedback.ID, "response": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"response": response}})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrResponseExists
+	}
+	feedback.Response = &response
+	return feedback, nil
+}
+
+// Appeal asks moderators to exclude a low rating from the recipient's
+// reputation. Hiding the feedback upholds the appeal; any other decision
+// denies it.
+func (s *FeedbackService) Appeal(ctx context.Context, feedbackID, userID primitive.ObjectID, req models.FeedbackAppealRequest) (*models.Feedback, error) {
+	feedback, err := s.lowRating(ctx, feedbackID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	appeal := models.FeedbackAppeal{Reason: req.Reason, Status: models.AppealPending, FiledAt: time.Now()}
+	result, err := s.mongoClient.GetCollection("feedback").UpdateOne(ctx,
+		bson.M{"_id": feedback.ID, "appeal": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"appeal": appeal}})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrAppealExists
+	}
+	feedback.Appeal = &appeal
+
+	if err := s.moderation.Appeal(ctx, models.ContentFeedback, feedback.ID, feedback.FromUserID, feedback.Comment); err != nil {
+		return nil, err
+	}
+	return feedback, nil
+}
+
+// ApplyDecision settles a moderator's decision on feedback: a pending
+// appeal is upheld when the feedback was hidden and denied otherwise, and
+// the recipient's reputation is queued to be recomputed either way. Any
+// decision on a safety incident ends the review, lifting the matching
+// suspension it caused.
+func (s *FeedbackService) ApplyDecision(ctx context.Context, item *models.ModerationItem) error {
+	if item.ContentType == models.ContentUser && contains(item.Sources, models.ModerationSourceIncident) {
+		return s.liftSuspension(ctx, item)
+	}
+	if item.ContentType != models.ContentFeedback {
+		return nil
+	}
+
+	collection := s.mongoClient.GetCollection("feedback")
+	var feedback models.Feedback
+	err := collection.FindOne(ctx, bson.M{"_id": item.ContentID}).Decode(&feedback)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	status := models.AppealDenied
+	if item.Status == models.ModerationHidden {
+		status = models.AppealUpheld
+	}
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": feedback.ID, "appeal.status": models.AppealPending},
+		bson.M{"$set": bson.M{"appeal.status": status, "appeal.decided_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	return s.ratings.Changed(ctx, feedback.ToUserID)
+}
+
+// ShareTestimonial offers a comment authorID wrote as a public testimonial.
+// Sharing again is a no-op.
+func (s *FeedbackService) ShareTestimonial(ctx context.Context, feedbackID, authorID primitive.ObjectID) (*models.Feedback, error) {
+	collection := s.mongoClient.GetCollection("feedback")
+	var feedback models.Feedback
+	err := collection.FindOne(ctx, bson.M{"_id": feedbackID, "from_user_id": authorID}).Decode(&feedback)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrFeedbackNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if feedback.Comment == "" {
+		return nil, ErrNoComment
+	}
+	if feedback.Testimonial != nil {
+		return &feedback, nil
+	}
+
+	testimonial := models.Testimonial{SharedAt: time.Now()}
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": feedback.ID, "testimonial": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"testimonial": testimonial}})
+	if err != nil {
+		return nil, err
+	}
+	feedback.Testimonial = &testimonial
+	return &feedback, nil
+}
+
+// ApproveTestimonial shows a shared comment recipientID received on their
+// public profile
+func (s *FeedbackService) ApproveTestimonial(ctx context.Context, feedbackID, recipientID primitive.ObjectID) (*models.Feedback, error) {
+	feedback, err := s.received(ctx, feedbackID, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	if feedback.Testimonial == nil {
+		return nil, ErrNotShared
+	}
+	if feedback.Testimonial.ApprovedAt != nil {
+		return feedback, nil
+	}
+
+	now := time.Now()
+	result, err := s.mongoClient.GetCollection("feedback").UpdateOne(ctx,
+		bson.M{"_id": feedback.ID, "testimonial": bson.M{"$exists": true}},
+		bson.M{"$set": bson.M{"testimonial.approved_at": now}})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrNotShared
+	}
+	feedback.Testimonial.ApprovedAt = &now
+	return feedback, nil
+}
+
+// WithdrawTestimonial takes a testimonial down. Its author withdraws their
+// consent to share it, and its recipient their approval, which they can
+// give again later.
+func (s *FeedbackService) WithdrawTestimonial(ctx context.Context, feedbackID, userID primitive.ObjectID) error {
+	collection := s.mongoClient.GetCollection("feedback")
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": feedbackID, "from_user_id": userID},
+		bson.M{"$unset": bson.M{"testimonial": ""}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	result, err = collection.UpdateOne(ctx,
+		bson.M{"_id": feedbackID, "to_user_id": userID, "revealed_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"testimonial.approved_at": ""}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrFeedbackNotFound
+	}
+	return nil
+}
+
+// Testimonials returns the approved testimonials userID received, newest
+// first
+func (s *FeedbackService) Testimonials(ctx context.Context, userID primitive.ObjectID, limit, offset int64) ([]models.PublicTestimonial, error) {
+	return s.publicTestimonials(ctx, bson.M{"to_user_id": userID}, limit, offset)
+}
+
+// Highlights returns the community's newest approved testimonials with a
+// rating of 4 or more
+func (s *FeedbackService) Highlights(ctx context.Context, limit int64) ([]models.PublicTestimonial, error) {
+	return s.publicTestimonials(ctx, bson.M{"rating": bson.M{"$gte": 4}}, limit, 0)
+}
+
+// publicTestimonials returns approved, visible testimonials matching
+// filter, newest first, leaving out those about users a moderator hid
+func (s *FeedbackService) publicTestimonials(ctx context.Context, filter bson.M, limit, offset int64) ([]models.PublicTestimonial, error) {
+	filter["testimonial.approved_at"] = bson.M{"$exists": true}
+	filter["revealed_at"] = bson.M{"$exists": true}
+	filter["hidden"] = bson.M{"$ne": true}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "testimonial.approved_at", Value: -1}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "users", "localField": "to_user_id", "foreignField": "_id", "as": "recipient"}}},
+		{{Key: "$unwind", Value: "$recipient"}},
+		{{Key: "$match", Value: bson.M{"recipient.hidden": bson.M{"$ne": true}}}},
+		{{Key: "$skip", Value: offset}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$lookup", Value: bson.M{"from": "users", "localField": "from_user_id", "foreignField": "_id", "as": "author"}}},
+	}
+	cursor, err := s.mongoClient.GetCollection("feedback").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		models.Feedback `bson:",inline"`
+		RecipientUser   models.User   `bson:"recipient"`
+		AuthorUsers     []models.User `bson:"author"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	testimonials := make([]models.PublicTestimonial, len(rows))
+	for i, row := range rows {
+		author := models.AnonymousName
+		if len(row.AuthorUsers) > 0 {
+			author = row.AuthorUsers[0].DisplayName()
+		}
+		testimonials[i] = models.PublicTestimonial{
+			ID:          row.ID,
+			Rating:      row.Rating,
+			Comment:     row.Comment,
+			Author:      author,
+			RecipientID: row.ToUserID,
+			Recipient:   row.RecipientUser.DisplayName(),
+			CreatedAt:   row.CreatedAt,
+		}
+	}
+	return testimonials, nil
+}
+
+// reportIncident queues a safety incident against userID for moderators,
+// suspends the user from matching when it is severe enough, and alerts the
+// moderators at once
+func (s *FeedbackService) reportIncident(ctx context.Context, feedbackID, userID primitive.ObjectID, req *models.IncidentRequest) (*models.FeedbackIncident, error) {
+	item, err := s.moderation.Incident(ctx, userID, req.Severity, req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	suspended := req.Severity.AtLeast(s.suspendAt)
+	if suspended {
+		suspension := models.MatchingSuspension{
+			FeedbackID:       feedbackID,
+			ModerationItemID: item.ID,
+			Severity:         req.Severity,
+			SuspendedAt:      now,
+		}
+		for collection, field := range map[string]string{"users": "_id", "volunteers": "user_id"} {
+			_, err := s.mongoClient.GetCollection(collection).UpdateOne(ctx,
+				bson.M{field: userID, "matching_suspension": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"matching_suspension": suspension}})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.moderation.AlertModerators(ctx, models.WebSocketMessage{
+		Type: "safety_incident",
+		Payload: map[string]interface{}{
+			"moderation_item_id": item.ID.Hex(),
+			"user_id":            userID.Hex(),
+			"severity":           req.Severity,
+			"suspended":          suspended,
+		},
+	})
+	return &models.FeedbackIncident{
+		Severity:         req.Severity,
+		Description:      req.Description,
+		ModerationItemID: item.ID,
+		ReportedAt:       now,
+	}, nil
+}
+
+// liftSuspension lets a user back into matching once moderators have
+// decided on the incident that suspended them. Users hidden by the decision
+// stay out of matching as hidden.
+func (s *FeedbackService) liftSuspension(ctx context.Context, item *models.ModerationItem) error {
+	for collection, field := range map[string]string{"users": "_id", "volunteers": "user_id"} {
+		_, err := s.mongoClient.GetCollection(collection).UpdateOne(ctx,
+			bson.M{field: item.ContentID, "matching_suspension.moderation_item_id": item.ID},
+			bson.M{"$unset": bson.M{"matching_suspension": ""}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// received returns revealed, visible feedback userID received, without any
+// incident its author reported
+func (s *FeedbackService) received(ctx context.Context, feedbackID, userID primitive.ObjectID) (*models.Feedback, error) {
+	var feedback models.Feedback
+	err := s.mongoClient.GetCollection("feedback").FindOne(ctx, bson.M{
+		"_id":         feedbackID,
+		"to_user_id":  userID,
+		"revealed_at": bson.M{"$exists": true},
+		"hidden":      bson.M{"$ne": true},
+	}).Decode(&feedback)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrFeedbackNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	feedback.Incident = nil
+	return &feedback, nil
+}
+
+// lowRating returns revealed, visible feedback userID received that is low
+// enough to respond to or appeal
+func (s *FeedbackService) lowRating(ctx context.Context, feedbackID, userID primitive.ObjectID) (*models.Feedback, error) {
+	feedback, err := s.received(ctx, feedbackID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if feedback.Rating > lowRatingMax {
+		return nil, ErrRatingNotLow
+	}
+	return feedback, nil
+}
+
+// Overdue returns the completed tasks on which userID still owes feedback
+// more than dueAfter after completion. Tasks whose feedback window has
+// closed, because the other participant's feedback was revealed, are not
+// owed. It returns nothing when the policy is off.
+func (s *FeedbackService) Overdue(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	if s.dueAfter <= 0 {
+		return nil, nil
+	}
+
+	needIDs, err := s.mongoClient.GetCollection("needs").Distinct(ctx, "_id", bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	completed := bson.M{"$lt": now.Add(-s.dueAfter), "$gte": now.Add(-s.dueAfter - feedbackOverdueLookback)}
+	cursor, err := s.mongoClient.GetCollection("tasks").Find(ctx, bson.M{
+		"status": models.TaskStatusCompleted,
+		"$and": []bson.M{
+			{"$or": []bson.M{{"volunteer_id": userID}, {"need_id": bson.M{"$in": needIDs}}}},
+			// Tasks completed before completed_at was recorded fall back to updated_at
+			{"$or": []bson.M{
+				{"completed_at": completed},
+				{"completed_at": bson.M{"$exists": false}, "updated_at": completed},
+			}},
+		},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	taskIDs := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	settled, err := s.mongoClient.GetCollection("feedback").Distinct(ctx, "task_id", bson.M{
+		"task_id": bson.M{"$in": taskIDs},
+		"$or": []bson.M{
+			{"from_user_id": userID},
+			{"revealed_at": bson.M{"$exists": true}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[primitive.ObjectID]bool, len(settled))
+	for _, id := range settled {
+		if taskID, ok := id.(primitive.ObjectID); ok {
+			done[taskID] = true
+		}
+	}
+
+	var overdue []primitive.ObjectID
+	for _, taskID := range taskIDs {
+		if !done[taskID] {
+			overdue = append(overdue, taskID)
+		}
+	}
+	return overdue, nil
+}
+
+// RevealDue reveals feedback the other participant did not answer within
+// the reveal window and queues the recipients' reputations to be
+// recomputed, returning how many were revealed
+func (s *FeedbackService) RevealDue(ctx context.Context) (int, error) {
+	collection := s.mongoClient.GetCollection("feedback")
+	cursor, err := collection.Find(ctx, bson.M{
+		"revealed_at": bson.M{"$exists": false},
+		"created_at":  bson.M{"$lte": time.Now().Add(-s.revealAfter)},
+	}, options.Find().SetProjection(bson.M{"to_user_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var due []models.Feedback
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, err
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(due))
+	recipients := make([]primitive.ObjectID, 0, len(due))
+	seen := make(map[primitive.ObjectID]bool)
+	for i, feedback := range due {
+		ids[i] = feedback.ID
+		if !seen[feedback.ToUserID] {
+			seen[feedback.ToUserID] = true
+			recipients = append(recipients, feedback.ToUserID)
+		}
+	}
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "revealed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revealed_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	if err := s.ratings.Changed(ctx, recipients...); err != nil {
+		return int(result.ModifiedCount), err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// Insights breaks down the revealed ratings userID received as a volunteer
+// over the last months calendar months, by month and by need category, and
+// summarizes their comments into themes. Themes are regenerated only once
+// new comments arrive; a failed summary falls back to the last one.
+func (s *FeedbackService) Insights(ctx context.Context, userID primitive.ObjectID, months int) (*models.FeedbackInsights, error) {
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+
+	pipeline := append(s.volunteerFeedback(userID, since),
+		bson.D{{Key: "$facet", Value: bson.M{
+			"monthly": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":     bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$created_at"}},
+					"average": bson.M{"$avg": "$rating"},
+					"count":   bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"categories": bson.A{
+				bson.M{"$lookup": bson.M{"from": "needs", "localField": "task.need_id", "foreignField": "_id", "as": "need"}},
+				bson.M{"$unwind": "$need"},
+				bson.M{"$group": bson.M{
+					"_id":     "$need.category",
+					"average": bson.M{"$avg": "$rating"},
+					"count":   bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			},
+		}}},
+	)
+	cursor, err := s.mongoClient.GetCollection("feedback").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var facets []models.FeedbackInsights
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	insights := &models.FeedbackInsights{Monthly: []models.RatingPeriod{}, Categories: []models.CategoryRating{}}
+	if len(facets) > 0 {
+		if facets[0].Monthly != nil {
+			insights.Monthly = facets[0].Monthly
+		}
+		if facets[0].Categories != nil {
+			insights.Categories = facets[0].Categories
+		}
+	}
+
+	insights.Themes, err = s.feedbackThemes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return insights, nil
+}
+
+// feedbackThemes returns the volunteer's cached comment themes, summarizing
+// again when comments have arrived since
+func (s *FeedbackService) feedbackThemes(ctx context.Context, userID primitive.ObjectID) (*models.FeedbackThemes, error) {
+	volunteers := s.mongoClient.GetCollection("volunteers")
+	var volunteer models.Volunteer
+	err := volunteers.FindOne(ctx, bson.M{"user_id": userID},
+		options.FindOne().SetProjection(bson.M{"feedback_themes": 1})).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !s.themes.Enabled() {
+		return volunteer.Themes, nil
+	}
+
+	pipeline := append(s.volunteerFeedback(userID, time.Time{}),
+		bson.D{{Key: "$match", Value: bson.M{"comment": bson.M{"$nin": bson.A{nil, ""}}}}},
+		bson.D{{Key: "$sort", Value: bson.M{"created_at": -1}}},
+		bson.D{{Key: "$project", Value: bson.M{"rating": 1, "comment": 1}}},
+	)
+	cursor, err := s.mongoClient.GetCollection("feedback").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var feedback []models.Feedback
+	if err := cursor.All(ctx, &feedback); err != nil {
+		return nil, err
+	}
+	if len(feedback) == 0 || (volunteer.Themes != nil && volunteer.Themes.Comments == len(feedback)) {
+		return volunteer.Themes, nil
+	}
+
+	comments := make([]string, len(feedback))
+	for i, item := range feedback {
+		comments[i] = fmt.Sprintf("%d stars: %s", item.Rating, strings.ReplaceAll(item.Comment, "\n", " "))
+	}
+	praise, criticism, err := s.themes.Summarize(ctx, comments)
+	if err != nil {
+		log.Printf("Feedback themes for %s failed, keeping the last summary: %v", userID.Hex(), err)
+		return volunteer.Themes, nil
+	}
+
+	themes := &models.FeedbackThemes{
+		Praise:      praise,
+		Criticism:   criticism,
+		Comments:    len(feedback),
+		GeneratedAt: time.Now(),
+	}
+	if _, err := volunteers.UpdateOne(ctx, bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"feedback_themes": themes}}); err != nil {
+		return nil, err
+	}
+	return themes, nil
+}
+
+// volunteerFeedback starts a pipeline over the revealed, visible feedback
+// userID received since since on tasks they volunteered for, with each
+// item's task joined as task
+func (s *FeedbackService) volunteerFeedback(userID primitive.ObjectID, since time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"to_user_id":  userID,
+			"revealed_at": bson.M{"$exists": true},
+			"hidden":      bson.M{"$ne": true},
+			"created_at":  bson.M{"$gte": since},
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "tasks", "localField": "task_id", "foreignField": "_id", "as": "task"}}},
+		{{Key: "$unwind", Value: "$task"}},
+		{{Key: "$match", Value: bson.M{"task.volunteer_id": userID}}},
+	}
+}
+
+// taskPartner returns a task and the other participant to viewerID, who
+// must be its volunteer or the need's creator
+func (s *FeedbackService) taskPartner(ctx context.Context, taskID, viewerID primitive.ObjectID) (*models.Task, primitive.ObjectID, error) {
+	var task models.Task
+	err := s.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{"_id": taskID}).Decode(&task)
+	if err == mongo.ErrNoDocuments {
+		return nil, primitive.NilObjectID, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+
+	var need models.Need
+	err = s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1})).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, primitive.NilObjectID, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+
+	switch viewerID {
+	case task.VolunteerID:
+		return &task, need.UserID, nil
+	case need.UserID:
+		return &task, task.VolunteerID, nil
+	}
+	return nil, primitive.NilObjectID, ErrTaskNotFound
+}
+
+// reputationWeight scales a match score by reputation, from 0.8 for the
+// worst to 1.2 for the best. Users never rated are left as they are.
+func reputationWeight(reputation *models.Reputation) float64 {
+	if reputation == nil {
+		return 1
+	}
+	return 0.8 + 0.4*reputation.Score
+} 
\ No newline at end of file
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// maxGroupCells caps a group's area, in block resolution cells. At the
+// default resolution 9 this is roughly 500 km², far more than any building
+// or block association needs.
+const maxGroupCells = 5000
+
+var (
+	// ErrGroupNotFound is returned for a group that does not exist
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrNotGroupMember is returned when the user is not a member of the group
+	ErrNotGroupMember = errors.New("not a member of this group")
+	// ErrNotGroupAdmin is returned when the user may not manage the group
+	ErrNotGroupAdmin = errors.New("only group admins can do this")
+	// ErrAlreadyGroupMember is returned when joining a group twice
+	ErrAlreadyGroupMember = errors.New("already a member of this group")
+	// ErrLastGroupAdmin is returned when a change would leave a group with
+	// members but no admin
+	ErrLastGroupAdmin = errors.New("a group must keep at least one admin; promote another member first")
+	// ErrInvalidGroupArea is returned for an area that is missing, given
+	// both ways, or made of invalid cells or coordinates
+	ErrInvalidGroupArea = errors.New("area must be a list of valid H3 cells or a boundary polygon, but not both")
+	// ErrGroupAreaTooLarge is returned for an area over maxGroupCells
+	ErrGroupAreaTooLarge = errors.New("group area is too large")
+)
+
+// GroupService manages neighborhood groups, their members, and the feed of
+// needs inside each group's area
+type GroupService struct {
+	mongoClient    *database.MongoClient
+	privacyService *PrivacyService
+}
+
+// NewGroupService creates a new group service
+func NewGroupService(mongoClient *database.MongoClient, privacyService *PrivacyService) *GroupService {
+	return &GroupService{
+		mongoClient:    mongoClient,
+		privacyService: privacyService,
+	}
+}
+
+// Create creates a group with its creator as the first admin
+func (s *GroupService) Create(ctx context.Context, createdBy primitive.ObjectID, req models.CreateGroupRequest) (*models.Group, error) {
+	area, err := s.normalizeArea(req.Area)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	group := models.Group{
+		ID:          primitive.NewObjectID(),
+		Name:        req.Name,
+		Description: req.Description,
+		Area:        area,
+		CreatedBy:   createdBy,
+		MemberCount: 1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := s.mongoClient.GetCollection("groups").InsertOne(ctx, group); err != nil {
+		return nil, err
+	}
+
+	member := models.GroupMember{
+		ID:       primitive.NewObjectID(),
+		GroupID:  group.ID,
+		UserID:   createdBy,
+		Role:     models.GroupRoleAdmin,
+		JoinedAt: now,
+	}
+	if _, err := s.mongoClient.GetCollection("group_members").InsertOne(ctx, member); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Get returns a group
+func (s *GroupService) Get(ctx context.Context, groupID primitive.ObjectID) (*models.Group, error) {
+	var group models.Group
+	err := s.mongoClient.GetCollection("groups").FindOne(ctx, bson.M{"_id": groupID}).Decode(&group)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// List returns groups, largest first. A non-nil memberID limits them to the
+// groups that user belongs to, and a non-empty query matches group names.
+func (s *GroupService) List(ctx context.Context, memberID *primitive.ObjectID, query string, limit, offset int64) ([]models.Group, error) {
+	filter := bson.M{}
+	if memberID != nil {
+		groupIDs, err := s.mongoClient.GetCollection("group_members").Distinct(ctx, "group_id", bson.M{"user_id": *memberID})
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$in": groupIDs}
+	}
+	if query != "" {
+		filter["name"] = primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "member_count", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit).
+		SetProjection(bson.M{"area.cells": 0})
+	cursor, err := s.mongoClient.GetCollection("groups").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	groups := []models.Group{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// Update changes a group's details or area
+func (s *GroupService) Update(ctx context.Context, groupID primitive.ObjectID, req models.UpdateGroupRequest) (*models.Group, error) {
+	set := bson.M{"updated_at": time.Now()}
+	if req.Name != nil {
+		set["name"] = *req.Name
+	}
+	if req.Description != nil {
+		set["description"] = *req.Description
+	}
+	if req.Area != nil {
+		area, err := s.normalizeArea(*req.Area)
+		if err != nil {
+			return nil, err
+		}
+		set["area"] = area
+	}
+
+	var group models.Group
+	err := s.mongoClient.GetCollection("groups").FindOneAndUpdate(ctx, bson.M{"_id": groupID}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&group)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Delete removes a group and its memberships
+func (s *GroupService) Delete(ctx context.Context, groupID primitive.ObjectID) error {
+	result, err := s.mongoClient.GetCollection("groups").DeleteOne(ctx, bson.M{"_id": groupID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrGroupNotFound
+	}
+	_, err = s.mongoClient.GetCollection("group_members").DeleteMany(ctx, bson.M{"group_id": groupID})
+	return err
+}
+
+// Membership returns a user's membership of a group, or nil if they are not
+// a member
+func (s *GroupService) Membership(ctx context.Context, groupID, userID primitive.ObjectID) (*models.GroupMember, error) {
+	var member models.GroupMember
+	err := s.mongoClient.GetCollection("group_members").FindOne(ctx, bson.M{"group_id": groupID, "user_id": userID}).Decode(&member)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Authorize checks that user may act on a group: as a member, or as a group
+// admin when admin is true. Site admins may act on any group.
+func (s *GroupService) Authorize(ctx context.Context, groupID primitive.ObjectID, user *models.User, admin bool) error {
+	if _, err := s.Get(ctx, groupID); err != nil {
+		return err
+	}
+	if user.Role == models.RoleAdmin {
+		return nil
+	}
+
+	member, err := s.Membership(ctx, groupID, user.ID)
+	switch {
+	case err != nil:
+		return err
+	case member == nil && admin:
+		return ErrNotGroupAdmin
+	case member == nil:
+		return ErrNotGroupMember
+	case admin && member.Role != models.GroupRoleAdmin:
+		return ErrNotGroupAdmin
+	}
+	return nil
+}
+
+// Join adds a user to a group as a member
+func (s *GroupService) Join(ctx context.Context, groupID, userID primitive.ObjectID) (*models.GroupMember, error) {
+	if _, err := s.Get(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	member := models.GroupMember{
+		ID:       primitive.NewObjectID(),
+		GroupID:  groupID,
+		UserID:   userID,
+		Role:     models.GroupRoleMember,
+		JoinedAt: time.Now(),
+	}
+	if _, err := s.mongoClient.GetCollection("group_members").InsertOne(ctx, member); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadyGroupMember
+		}
+		return nil, err
+	}
+	if err := s.adjustMemberCount(ctx, groupID, 1); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveMember removes a user from a group. The last admin cannot go while
+// other members remain, and a group left without members is deleted.
+func (s *GroupService) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) error {
+	member, err := s.Membership(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrNotGroupMember
+	}
+	if member.Role == models.GroupRoleAdmin {
+		if err := s.checkOtherAdmin(ctx, groupID, userID, true); err != nil {
+			return err
+		}
+	}
+
+	members := s.mongoClient.GetCollection("group_members")
+	result, err := members.DeleteOne(ctx, bson.M{"_id": member.ID})
+	if err != nil || result.DeletedCount == 0 {
+		return err
+	}
+	remaining, err := members.CountDocuments(ctx, bson.M{"group_id": groupID})
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return s.Delete(ctx, groupID)
+	}
+	return s.adjustMemberCount(ctx, groupID, -1)
+}
+
+// SetRole changes a member's role
+func (s *GroupService) SetRole(ctx context.Context, groupID, userID primitive.ObjectID, role models.GroupRole) (*models.GroupMember, error) {
+	member, err := s.Membership(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrNotGroupMember
+	}
+	if member.Role == models.GroupRoleAdmin && role != models.GroupRoleAdmin {
+		if err := s.checkOtherAdmin(ctx, groupID, userID, false); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.mongoClient.GetCollection("group_members").UpdateOne(ctx, bson.M{"_id": member.ID}, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return nil, err
+	}
+	member.Role = role
+	return member, nil
+}
+
+// checkOtherAdmin returns ErrLastGroupAdmin unless the group has an admin
+// besides userID. When leaving, a sole remaining member may go regardless.
+func (s *GroupService) checkOtherAdmin(ctx context.Context, groupID, userID primitive.ObjectID, leaving bool) error {
+	members := s.mongoClient.GetCollection("group_members")
+	admins, err := members.CountDocuments(ctx, bson.M{"group_id": groupID, "user_id": bson.M{"$ne": userID}, "role": models.GroupRoleAdmin})
+	if err != nil || admins > 0 {
+		return err
+	}
+	if leaving {
+		others, err := members.CountDocuments(ctx, bson.M{"group_id": groupID, "user_id": bson.M{"$ne": userID}})
+		if err != nil || others == 0 {
+			return err
+		}
+	}
+	return ErrLastGroupAdmin
+}
+
+// adjustMemberCount adds delta to a group's member count
+func (s *GroupService) adjustMemberCount(ctx context.Context, groupID primitive.ObjectID, delta int) error {
+	_, err := s.mongoClient.GetCollection("groups").UpdateOne(ctx, bson.M{"_id": groupID},
+		bson.M{"$inc": bson.M{"member_count": delta}, "$set": bson.M{"updated_at": time.Now()}})
+	return err
+}
+
+// Members lists a group's members, admins first
+func (s *GroupService) Members(ctx context.Context, groupID primitive.ObjectID, limit, offset int64) ([]models.GroupMember, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "role", Value: 1}, {Key: "joined_at", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("group_members").Find(ctx, bson.M{"group_id": groupID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	members := []models.GroupMember{}
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// FeedFilter returns the filter selecting needs inside a group's area. Need
+// locations are indexed at their owner's location precision, so needs at
+// neighborhood precision appear in the feeds of every group overlapping
+// their neighborhood cell.
+func (s *GroupService) FeedFilter(ctx context.Context, groupID primitive.ObjectID) (bson.M, error) {
+	var group models.Group
+	err := s.mongoClient.GetCollection("groups").FindOne(ctx, bson.M{"_id": groupID},
+		options.FindOne().SetProjection(bson.M{"area.cells": 1})).Decode(&group)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	cells := []string{}
+	for _, value := range group.Area.Cells {
+		cell := h3.Cell(h3.IndexFromString(value))
+		if !cell.IsValid() {
+			continue
+		}
+		for _, resolution := range s.privacyService.Resolutions() {
+			for _, indexed := range cellsAt(cell, resolution) {
+				if key := indexed.String(); !seen[key] {
+					seen[key] = true
+					cells = append(cells, key)
+				}
+			}
+		}
+	}
+	return bson.M{"location.h3_index": bson.M{"$in": cells}}, nil
+}
+
+// normalizeArea converts a requested area into block resolution cells
+func (s *GroupService) normalizeArea(req models.GroupAreaRequest) (models.GroupArea, error) {
+	resolution := s.privacyService.Resolutions()[0]
+
+	switch {
+	case len(req.Boundary) > 0 && len(req.H3Cells) == 0:
+		loop := make(h3.GeoLoop, len(req.Boundary))
+		for i, point := range req.Boundary {
+			loop[i] = h3.LatLng{Lat: point.Latitude, Lng: point.Longitude}
+		}
+		cells := h3.PolygonToCells(h3.GeoPolygon{GeoLoop: loop}, resolution)
+		if len(cells) == 0 {
+			// A boundary smaller than one cell still covers the cell around it
+			cells = []h3.Cell{h3.LatLngToCell(loop[0], resolution)}
+		}
+		if len(cells) > maxGroupCells {
+			return models.GroupArea{}, ErrGroupAreaTooLarge
+		}
+		return models.GroupArea{Cells: cellStrings(cells), Boundary: req.Boundary}, nil
+
+	case len(req.H3Cells) > 0 && len(req.Boundary) == 0:
+		var total float64
+		cells := make([]h3.Cell, 0, len(req.H3Cells))
+		for _, value := range req.H3Cells {
+			cell := h3.Cell(h3.IndexFromString(value))
+			if !cell.IsValid() {
+				return models.GroupArea{}, ErrInvalidGroupArea
+			}
+			if cell.Resolution() < resolution {
+				total += math.Pow(7, float64(resolution-cell.Resolution()))
+			} else {
+				total++
+			}
+			cells = append(cells, cell)
+		}
+		if total > maxGroupCells {
+			return models.GroupArea{}, ErrGroupAreaTooLarge
+		}
+
+		seen := make(map[h3.Cell]bool)
+		var normalized []h3.Cell
+		for _, cell := range cells {
+			for _, c := range cellsAt(cell, resolution) {
+				if !seen[c] {
+					seen[c] = true
+					normalized = append(normalized, c)
+				}
+			}
+		}
+		return models.GroupArea{Cells: cellStrings(normalized)}, nil
+	}
+	return models.GroupArea{}, ErrInvalidGroupArea
+}
+
+// cellsAt returns the cells at resolution covering cell: its children when
+// finer, or its parent when coarser
+func cellsAt(cell h3.Cell, resolution int) []h3.Cell {
+	switch {
+	case cell.Resolution() < resolution:
+		return cell.Children(resolution)
+	case cell.Resolution() > resolution:
+		return []h3.Cell{cell.Parent(resolution)}
+	}
+	return []h3.Cell{cell}
+}
+
+// cellStrings formats cells as H3 index strings
+func cellStrings(cells []h3.Cell) []string {
+	values := make([]string, len(cells))
+	for i, cell := range cells {
+		values[i] = cell.String()
+	}
+	return values
+} 
\ No newline at end of file
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// ErrNotificationNotFound is returned for a notification that does not
+// exist or belongs to another user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// inboxSkipped are the message types not kept in the inbox. Read receipts
+// only update a chat the user already has open.
+var inboxSkipped = map[string]bool{
+	"chat_read": true,
+}
+
+// NotificationQuery selects one user's notifications
+type NotificationQuery struct {
+	UserID     primitive.ObjectID
+	Before     primitive.ObjectID // only notifications with lower IDs, when set
+	UnreadOnly bool
+	Limit      int64
+}
+
+// NotificationStore keeps inbox notifications. MongoNotificationStore is the
+// store in production; tests can use the in-memory one in the fakes package.
+type NotificationStore interface {
+	// Insert adds notifications, skipping any with a key already stored
+	// for their user
+	Insert(ctx context.Context, notifications []models.Notification) error
+	// Find returns the notifications matching query, newest first
+	Find(ctx context.Context, query NotificationQuery) ([]models.Notification, error)
+	// CountUnread returns how many of userID's notifications are unread
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// MarkRead sets the read time of userID's notification id if it is
+	// unread, reporting whether userID has a notification id at all
+	MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) (bool, error)
+	// MarkAllRead sets the read time of userID's unread notifications,
+	// returning how many there were
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error)
+	// DeleteCreatedBefore deletes notifications created before cutoff,
+	// returning how many were deleted
+	DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// InboxService keeps the messages pushed to users over WebSocket, so users
+// who were offline can see what they missed
+type InboxService struct {
+	store     NotificationStore
+	retention time.Duration
+}
+
+// NewInboxService creates a new inbox service keeping notifications in
+// store. Notifications are kept for retention; zero keeps them forever.
+func NewInboxService(store NotificationStore, retention time.Duration) *InboxService {
+	return &InboxService{store: store, retention: retention}
+}
+
+// Store keeps a copy of message for each of userIDs. The payload is stored
+// as it is sent, in its JSON form. key identifies the delivery, such as a
+// queued job, so storing it again when the delivery is retried adds
+// nothing; deliveries that are never retried can leave it empty.
+func (s *InboxService) Store(ctx context.Context, key string, userIDs []string, message models.WebSocketMessage) error {
+	if s == nil || len(userIDs) == 0 || inboxSkipped[message.Type] {
+		return nil
+	}
+
+	var payload map[string]interface{}
+	if message.Payload != nil {
+		data, err := json.Marshal(message.Payload)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		objectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			ID:        primitive.NewObjectID(),
+			UserID:    objectID,
+			Type:      message.Type,
+			Payload:   payload,
+			CreatedAt: now,
+			Key:       key,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+	return s.store.Insert(ctx, notifications)
+}
+
+// List returns the notifications matching query, newest first
+func (s *InboxService) List(ctx context.Context, query NotificationQuery) ([]models.Notification, error) {
+	return s.store.Find(ctx, query)
+}
+
+// UnreadCount returns how many of userID's notifications are unread
+func (s *InboxService) UnreadCount(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.store.CountUnread(ctx, userID)
+}
+
+// MarkRead marks one of userID's notifications as read. Marking a read
+// notification again leaves it as it was.
+func (s *InboxService) MarkRead(ctx context.Context, id, userID primitive.ObjectID) error {
+	found, err := s.store.MarkRead(ctx, id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks all of userID's notifications as read, returning how
+// many were newly read
+func (s *InboxService) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.store.MarkAllRead(ctx, userID, time.Now())
+}
+
+// PurgeExpired deletes notifications older than the retention, returning
+// how many were deleted
+func (s *InboxService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteCreatedBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// MongoNotificationStore keeps notifications in the notifications collection
+type MongoNotificationStore struct {
+	mongoClient *database.MongoClient
+}
+
+// NewMongoNotificationStore creates a notification store over MongoDB
+func NewMongoNotificationStore(mongoClient *database.MongoClient) *MongoNotificationStore {
+	return &MongoNotificationStore{mongoClient: mongoClient}
+}
+
+// Insert adds notifications, continuing past any that fail. Those with a
+// key are upserted on it, so they are only inserted the first time.
+func (s *MongoNotificationStore) Insert(ctx context.Context, notifications []models.Notification) error {
+	writes := make([]mongo.WriteModel, len(notifications))
+	for i, notification := range notifications {
+		if notification.Key == "" {
+			writes[i] = mongo.NewInsertOneModel().SetDocument(notification)
+			continue
+		}
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": notification.UserID, "key": notification.Key}).
+			SetUpdate(bson.M{"$setOnInsert": notification}).
+			SetUpsert(true)
+	}
+	_, err := s.mongoClient.GetCollection("notifications").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Find returns the notifications matching query, newest first
+func (s *MongoNotificationStore) Find(ctx context.Context, query NotificationQuery) ([]models.Notification, error) {
+	filter := bson.M{"user_id": query.UserID}
+	if !query.Before.IsZero() {
+		filter["_id"] = bson.M{"$lt": query.Before}
+	}
+	if query.UnreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(query.Limit)
+	cursor, err := s.mongoClient.GetCollection("notifications").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// CountUnread returns how many of userID's notifications are unread
+func (s *MongoNotificationStore) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.mongoClient.GetCollection("notifications").CountDocuments(ctx,
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}})
+}
+
+// MarkRead sets the read time of userID's notification id if it is unread
+func (s *MongoNotificationStore) MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) (bool, error) {
+	collection := s.mongoClient.GetCollection("notifications")
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": at}})
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount > 0 {
+		return true, nil
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": id, "user_id": userID})
+	return count > 0, err
+}
+
+// MarkAllRead sets the read time of userID's unread notifications
+func (s *MongoNotificationStore) MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error) {
+	result, err := s.mongoClient.GetCollection("notifications").UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": at}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// DeleteCreatedBefore deletes notifications created before cutoff
+func (s *MongoNotificationStore) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := s.mongoClient.GetCollection("notifications").DeleteMany(ctx,
+		bson.M{"created_at": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+} 
\ No newline at end of file
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+var (
+	// ErrKudosNotAllowed is returned when someone other than the person
+	// helped sends kudos for a task
+	ErrKudosNotAllowed = errors.New("only the person helped can send kudos for a task")
+	// ErrKudosNotCompleted is returned when sending kudos for a task that is
+	// not completed
+	ErrKudosNotCompleted = errors.New("kudos can only be sent for completed tasks")
+	// ErrKudosExists is returned when sending kudos for a task twice
+	ErrKudosExists = errors.New("you have already sent kudos for this task")
+)
+
+// KudosService handles thank-you notes left for volunteers, keeping the
+// tally on their profile and awarding kudos badges
+type KudosService struct {
+	mongoClient     *database.MongoClient
+	moderation      *ModerationService
+	badgeThresholds []int
+}
+
+// NewKudosService creates a new kudos service. Messages are screened with
+// moderationService, and volunteers earn a kudos badge on reaching each of
+// badgeThresholds kudos.
+func NewKudosService(mongoClient *database.MongoClient, moderationService *ModerationService, badgeThresholds []int) *KudosService {
+	return &KudosService{
+		mongoClient:     mongoClient,
+		moderation:      moderationService,
+		badgeThresholds: badgeThresholds,
+	}
+}
+
+// Send records kudos from the person helped by a completed task to its
+// volunteer
+func (s *KudosService) Send(ctx context.Context, taskID, fromUserID primitive.ObjectID, req models.KudosRequest) (*models.Kudos, error) {
+	var task models.Task
+	err := s.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{"_id": taskID}).Decode(&task)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var need models.Need
+	err = s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1})).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case need.UserID != fromUserID && task.VolunteerID != fromUserID:
+		return nil, ErrTaskNotFound
+	case need.UserID != fromUserID:
+		return nil, ErrKudosNotAllowed
+	case task.Status != models.TaskStatusCompleted:
+		return nil, ErrKudosNotCompleted
+	}
+
+	kudos := models.Kudos{
+		ID:         primitive.NewObjectID(),
+		TaskID:     taskID,
+		FromUserID: fromUserID,
+		ToUserID:   task.VolunteerID,
+		Tags:       req.Tags,
+		Message:    req.Message,
+		CreatedAt:  time.Now(),
+	}
+	if _, err := s.mongoClient.GetCollection("kudos").InsertOne(ctx, kudos); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrKudosExists
+		}
+		return nil, err
+	}
+	if kudos.Message != "" {
+		s.moderation.Screen(ctx, models.ContentKudos, kudos.ID, fromUserID, kudos.Message)
+	}
+
+	if err := s.Refresh(ctx, kudos.ToUserID); err != nil {
+		return nil, err
+	}
+	return &kudos, nil
+}
+
+// List returns the visible kudos userID has received, newest first
+func (s *KudosService) List(ctx context.Context, userID primitive.ObjectID, limit, offset int64) ([]models.Kudos, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("kudos").Find(ctx,
+		bson.M{"to_user_id": userID, "hidden": bson.M{"$ne": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	kudos := []models.Kudos{}
+	if err := cursor.All(ctx, &kudos); err != nil {
+		return nil, err
+	}
+	return kudos, nil
+}
+
+// Refresh recounts the visible kudos userID has received onto their
+// volunteer profile and awards any kudos badge they have now earned
+func (s *KudosService) Refresh(ctx context.Context, userID primitive.ObjectID) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"to_user_id": userID, "hidden": bson.M{"$ne": true}}}},
+		{{Key: "$facet", Value: bson.M{
+			"count": bson.A{bson.M{"$count": "count"}},
+			"tags": bson.A{
+				bson.M{"$unwind": "$tags"},
+				bson.M{"$group": bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}},
+			},
+		}}},
+	}
+	cursor, err := s.mongoClient.GetCollection("kudos").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Count []struct {
+			Count int `bson:"count"`
+		} `bson:"count"`
+		Tags []struct {
+			Tag   models.KudosTag `bson:"_id"`
+			Count int             `bson:"count"`
+		} `bson:"tags"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return err
+	}
+
+	summary := models.KudosSummary{Tags: map[models.KudosTag]int{}}
+	if len(facets) > 0 {
+		if len(facets[0].Count) > 0 {
+			summary.Count = facets[0].Count[0].Count
+		}
+		for _, tag := range facets[0].Tags {
+			summary.Tags[tag.Tag] = tag.Count
+		}
+	}
+
+	_, err = s.mongoClient.GetCollection("volunteers").UpdateOne(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"kudos": summary, "updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	return awardBadges(ctx, s.mongoClient, userID, models.BadgeKudos, int64(summary.Count), s.badgeThresholds)
+}
+
+// ApplyDecision recounts the recipient's kudos after a moderator decides on
+// a kudos message, so hidden kudos drop out of their tally
+func (s *KudosService) ApplyDecision(ctx context.Context, item *models.ModerationItem) error {
+	if item.ContentType != models.ContentKudos {
+		return nil
+	}
+
+	var kudos models.Kudos
+	err := s.mongoClient.GetCollection("kudos").FindOne(ctx, bson.M{"_id": item.ContentID},
+		options.FindOne().SetProjection(bson.M{"to_user_id": 1})).Decode(&kudos)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.Refresh(ctx, kudos.ToUserID)
+} 
\ No newline at end of file
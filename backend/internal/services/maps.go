@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// mapPinsPerCluster caps the needs pinned in each map cluster
+const mapPinsPerCluster = 3
+
+// MapService aggregates needs for map views. Stored locations may be sealed
+// at rest, so needs are grouped by their stored H3 cell, which also keeps
+// every location on the map at or above its owner's chosen precision.
+type MapService struct {
+	mongoClient *database.MongoClient
+	privacy     *PrivacyService
+}
+
+// NewMapService creates a new map service
+func NewMapService(mongoClient *database.MongoClient, privacyService *PrivacyService) *MapService {
+	return &MapService{
+		mongoClient: mongoClient,
+		privacy:     privacyService,
+	}
+}
+
+// MapResolution picks the H3 resolution for clustering a map at a web map
+// zoom level, so a viewport holds a few dozen clusters. It is never finer
+// than stored locations, and cells stored coarser than it stay whole.
+func (s *MapService) MapResolution(zoom int) int {
+	resolution := int(math.Round(0.75 * float64(zoom-3)))
+	return max(min(resolution, s.privacy.Resolutions()[0]), 0)
+}
+
+// MaxCoverageResolution is the finest resolution coverage is reported at.
+// Volunteer counts are public, so cells are never smaller than a neighborhood.
+func (s *MapService) MaxCoverageResolution() int {
+	return s.privacy.Resolutions()[1]
+}
+
+// NeedMap clusters the open needs within bounds into H3 cells at resolution
+func (s *MapService) NeedMap(ctx context.Context, bounds models.MapBounds, resolution int) (*models.NeedMap, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: openNeedsFilter()}},
+		{{Key: "$sort", Value: bson.M{"created_at": -1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"cell": "$location.h3_index", "category": "$category"},
+			"count": bson.M{"$sum": 1},
+			"pins": bson.M{"$firstN": bson.M{"n": mapPinsPerCluster, "input": bson.M{
+				"id":         bson.M{"$toString": "$_id"},
+				"title":      "$title",
+				"category":   "$category",
+				"urgency":    "$urgency",
+				"created_at": "$created_at",
+			}}},
+		}}},
+	}
+	cursor, err := s.mongoClient.GetCollection("needs").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate needs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	result := &models.NeedMap{Resolution: resolution, Clusters: []models.NeedCluster{}}
+	clusters := make(map[string]*models.NeedCluster)
+	for cursor.Next(ctx) {
+		var group needCellGroup
+		if err := cursor.Decode(&group); err != nil {
+			return nil, fmt.Errorf("failed to decode need cluster: %w", err)
+		}
+
+		stored := h3.Cell(h3.IndexFromString(group.ID.Cell))
+		if group.ID.Cell == "" || !stored.IsValid() {
+			continue
+		}
+		cell := stored
+		if cell.Resolution() > resolution {
+			cell = cell.Parent(resolution)
+		}
+		center := cell.LatLng()
+		if !bounds.Contains(center.Lat, center.Lng) {
+			continue
+		}
+
+		cluster, ok := clusters[cell.String()]
+		if !ok {
+			cluster = &models.NeedCluster{
+				Cell:       cell.String(),
+				Location:   models.Location{Latitude: center.Lat, Longitude: center.Lng, H3Index: cell.String(), Approximate: true},
+				Categories: map[models.Category]int{},
+			}
+			clusters[cell.String()] = cluster
+		}
+		cluster.Count += group.Count
+		cluster.Categories[group.ID.Category] += group.Count
+		pinned := s.privacy.ApproximateLocation(models.Location{H3Index: group.ID.Cell})
+		for _, pin := range group.Pins {
+			pin.Title = sanitize.RedactContacts(pin.Title)
+			pin.Location = pinned
+			cluster.Pins = append(cluster.Pins, pin)
+		}
+		result.Total += group.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read need clusters: %w", err)
+	}
+
+	for _, cluster := range clusters {
+		sort.Slice(cluster.Pins, func(i, j int) bool { return cluster.Pins[i].CreatedAt.After(cluster.Pins[j].CreatedAt) })
+		if len(cluster.Pins) > mapPinsPerCluster {
+			cluster.Pins = cluster.Pins[:mapPinsPerCluster]
+		}
+		result.Clusters = append(result.Clusters, *cluster)
+	}
+	sort.Slice(result.Clusters, func(i, j int) bool {
+		if result.Clusters[i].Count != result.Clusters[j].Count {
+			return result.Clusters[i].Count > result.Clusters[j].Count
+		}
+		return result.Clusters[i].Cell < result.Clusters[j].Cell
+	})
+	return result, nil
+}
+
+// Coverage compares active volunteers with open needs, per H3 cell at
+// resolution, within bounds when given. Cells stored coarser than
+// resolution stay whole.
+func (s *MapService) Coverage(ctx context.Context, bounds *models.MapBounds, resolution int) ([]models.CellCoverage, error) {
+	cells := make(map[string]*models.CellCoverage)
+	cellFor := func(stored string) *models.CellCoverage {
+		cell := h3.Cell(h3.IndexFromString(stored))
+		if stored == "" || !cell.IsValid() {
+			return nil
+		}
+		if cell.Resolution() > resolution {
+			cell = cell.Parent(resolution)
+		}
+		coverage, ok := cells[cell.String()]
+		if !ok {
+			center := cell.LatLng()
+			if bounds != nil && !bounds.Contains(center.Lat, center.Lng) {
+				return nil
+			}
+			coverage = &models.CellCoverage{
+				Cell:       cell.String(),
+				Location:   models.Location{Latitude: center.Lat, Longitude: center.Lng, H3Index: cell.String(), Approximate: true},
+				Categories: map[models.Category]models.CategoryCoverage{},
+			}
+			cells[cell.String()] = coverage
+		}
+		return coverage
+	}
+
+	// Only the cell and the terms offered are read, so sealed coordinates
+	// are never decrypted
+	volunteers, err := s.mongoClient.GetCollection("volunteers").Find(ctx, bson.M{
+		"hidden":              bson.M{"$ne": true},
+		"matching_suspension": bson.M{"$exists": false},
+	}, options.Find().SetProjection(bson.M{"location.h3_index": 1, "skills": 1, "interests": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volunteers: %w", err)
+	}
+	defer volunteers.Close(ctx)
+	for volunteers.Next(ctx) {
+		var volunteer struct {
+			Location struct {
+				H3Index string `bson:"h3_index"`
+			} `bson:"location"`
+			Skills    []string `bson:"skills"`
+			Interests []string `bson:"interests"`
+		}
+		if err := volunteers.Decode(&volunteer); err != nil {
+			return nil, fmt.Errorf("failed to decode volunteer: %w", err)
+		}
+		coverage := cellFor(volunteer.Location.H3Index)
+		if coverage == nil {
+			continue
+		}
+		coverage.Volunteers++
+		offered := volunteerCategories(volunteer.Skills, volunteer.Interests)
+		for _, category := range models.DefaultCategories {
+			if offered[string(category)] {
+				entry := coverage.Categories[category]
+				entry.Volunteers++
+				coverage.Categories[category] = entry
+			}
+		}
+	}
+	if err := volunteers.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read volunteers: %w", err)
+	}
+
+	needs, err := s.mongoClient.GetCollection("needs").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: openNeedsFilter()}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"cell": "$location.h3_index", "category": "$category"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate needs: %w", err)
+	}
+	defer needs.Close(ctx)
+	for needs.Next(ctx) {
+		var group needCellGroup
+		if err := needs.Decode(&group); err != nil {
+			return nil, fmt.Errorf("failed to decode need counts: %w", err)
+		}
+		coverage := cellFor(group.ID.Cell)
+		if coverage == nil {
+			continue
+		}
+		coverage.OpenNeeds += group.Count
+		entry := coverage.Categories[group.ID.Category]
+		entry.OpenNeeds += group.Count
+		coverage.Categories[group.ID.Category] = entry
+	}
+	if err := needs.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read need counts: %w", err)
+	}
+
+	results := make([]models.CellCoverage, 0, len(cells))
+	for _, coverage := range cells {
+		coverage.Uncovered = []models.Category{}
+		for category, entry := range coverage.Categories {
+			if entry.OpenNeeds > 0 && entry.Volunteers == 0 {
+				coverage.Uncovered = append(coverage.Uncovered, category)
+			}
+		}
+		sort.Slice(coverage.Uncovered, func(i, j int) bool { return coverage.Uncovered[i] < coverage.Uncovered[j] })
+		results = append(results, *coverage)
+	}
+
+	// Cells where needs most outnumber volunteers come first
+	sort.Slice(results, func(i, j int) bool {
+		gapI, gapJ := results[i].OpenNeeds-results[i].Volunteers, results[j].OpenNeeds-results[j].Volunteers
+		if gapI != gapJ {
+			return gapI > gapJ
+		}
+		return results[i].Cell < results[j].Cell
+	})
+	return results, nil
+}
+
+// needCellGroup counts the open needs with a stored cell and category,
+// with the newest of them when pins are collected
+type needCellGroup struct {
+	ID struct {
+		Cell     string          `bson:"cell"`
+		Category models.Category `bson:"category"`
+	} `bson:"_id"`
+	Count int              `bson:"count"`
+	Pins  []models.NeedPin `bson:"pins"`
+}
+
+// openNeedsFilter matches needs still open, visible, and unexpired
+func openNeedsFilter() bson.M {
+	return bson.M{
+		"status":          bson.M{"$in": []models.NeedStatus{models.NeedStatusRequested, models.NeedStatusMatched}},
+		"hidden":          bson.M{"$ne": true},
+		"held_for_review": bson.M{"$ne": true},
+		"$or": []bson.M{
+			{"expires_at": bson.M{"$exists": false}},
+			{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+	}
+} 
\ No newline at end of file
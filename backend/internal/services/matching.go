@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"math"
 	"sort"
 	"time"
@@ -11,87 +12,238 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"neighborenexus/internal/database"
 	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+	"neighborenexus/internal/telemetry"
+	"neighborenexus/internal/vectors"
 )
 
 // MatchingService handles semantic matching between needs and volunteers
 type MatchingService struct {
 	embeddingService *EmbeddingService
 	mongoClient      *database.MongoClient
-	pineconeAPIKey   string
-	pineconeIndex    string
+	vectors          vectors.Store
+	settings         *settings.Store
+	emergencies      *EmergencyService
+	decisions        *MatchDecisionLog
+	dryRun           bool // match without saving matches or pruning vectors
 }
 
-// NewMatchingService creates a new matching service
-func NewMatchingService(embeddingService *EmbeddingService, mongoClient *database.MongoClient, pineconeAPIKey, pineconeIndex string) *MatchingService {
+// NewMatchingService creates a new matching service. Candidates come from
+// vector search when vectorIndex is not nil, and match sets are recorded to
+// decisions unless it is nil.
+func NewMatchingService(embeddingService *EmbeddingService, mongoClient *database.MongoClient, vectorIndex vectors.Store, settingsStore *settings.Store, emergencyService *EmergencyService, decisions *MatchDecisionLog) *MatchingService {
 	return &MatchingService{
 		embeddingService: embeddingService,
 		mongoClient:      mongoClient,
-		pineconeAPIKey:   pineconeAPIKey,
-		pineconeIndex:    pineconeIndex,
+		vectors:          vectorIndex,
+		settings:         settingsStore,
+		emergencies:      emergencyService,
+		decisions:        decisions,
 	}
 }
 
+// DryRun returns a copy of m that matches without recording decisions,
+// saving matches, or pruning vectors, for load tests against a live
+// database
+func (m *MatchingService) DryRun() *MatchingService {
+	dry := *m
+	dry.decisions = nil
+	dry.dryRun = true
+	return &dry
+}
+
 // FindMatchesForNeed finds matching volunteers for a specific need
 func (m *MatchingService) FindMatchesForNeed(ctx context.Context, need *models.Need, limit int) ([]models.Match, error) {
+	start := time.Now()
+	matches, err := m.findMatchesForNeed(ctx, need, limit, nil)
+	telemetry.ObserveMatch(time.Since(start))
+	return matches, err
+}
+
+// findMatchesForNeed finds matching volunteers for a need, letting adjust,
+// when non-nil, change the criteria they are scored by
+func (m *MatchingService) findMatchesForNeed(ctx context.Context, need *models.Need, limit int, adjust func(*needCriteria)) ([]models.Match, error) {
 	if limit <= 0 {
 		limit = 10
 	}
+	tunables := m.settings.Get(ctx)
+
+	// Requesters suspended pending a safety review are not matched
+	suspended, err := m.mongoClient.GetCollection("users").CountDocuments(ctx,
+		bson.M{"_id": need.UserID, "matching_suspension": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester account: %w", err)
+	}
+	if suspended > 0 {
+		return nil, nil
+	}
+
+	// Minor-safety rules keep some volunteers away from this need
+	ineligible, err := m.mongoClient.GetCollection("users").Distinct(ctx, "_id", ineligibleUsersFilter(need.Category, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ineligible volunteers: %w", err)
+	}
+	excluded := make(map[primitive.ObjectID]bool, len(ineligible))
+	for _, id := range ineligible {
+		if userID, ok := id.(primitive.ObjectID); ok {
+			excluded[userID] = true
+		}
+	}
+
+	// Volunteers who declined the need are not offered it again
+	declined, err := m.declinedVolunteers(ctx, need.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get declined matches: %w", err)
+	}
+	for userID := range declined {
+		excluded[userID] = true
+	}
+
+	// Emergencies covering the need, and escalation for missing an SLA, reach
+	// volunteers further away
+	emergencies, err := m.emergencies.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergencies: %w", err)
+	}
+	matching := tunables.ForCategory(need.Category)
+	radius := emergencies.RadiusMultiplier(need.Location) * need.EscalationRadius()
+	criteria := needCriteria{
+		decayKm:       matching.DistanceDecayKm * radius,
+		maxDistanceKm: matching.MaxDistanceKm * radius,
+		threshold:     matching.MatchThreshold,
+		minTrust:      RequiredTrust(need, tunables),
+		priority:      emergencies.Prioritizes(need.Location, need.Category),
+		excluded:      excluded,
+	}
+	if adjust != nil {
+		adjust(&criteria)
+	}
 
-	// Get all active volunteers
-	volunteers, err := m.getActiveVolunteers(ctx)
+	// Get the active volunteers worth scoring, only loading those in nearby
+	// cells when the criteria bound how far away a match can be
+	volunteers, err := m.candidateVolunteers(ctx, need, limit, criteria.nearby(need))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get volunteers: %w", err)
 	}
 
-	var matches []models.Match
+	// Score volunteers across the worker pool, keeping the best by score
+	matches, err := scoreTopK(ctx, len(volunteers), limit, byScore, func(i int) (models.Match, bool) {
+		return m.scoreVolunteer(need, &volunteers[i], criteria)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Calculate similarity scores for each volunteer
+	users := make(map[primitive.ObjectID]primitive.ObjectID, len(volunteers))
 	for _, volunteer := range volunteers {
-		// Skip if volunteer has no embedding
-		if len(volunteer.Embedding) == 0 {
-			continue
-		}
+		users[volunteer.ID] = volunteer.UserID
+	}
+	m.decisions.Record(ctx, models.MatchDecisionNeed, need.ID, matches, func(match models.Match) (primitive.ObjectID, models.Category) {
+		return users[match.VolunteerID], need.Category
+	})
+	m.saveMatches(ctx, matches)
+	return matches, nil
+}
 
-		// Calculate semantic similarity
-		similarity, err := m.embeddingService.CalculateSimilarity(need.Embedding, volunteer.Embedding)
-		if err != nil {
-			continue // Skip this volunteer if similarity calculation fails
-		}
+// needCriteria are what scoring volunteers for one need depends on beyond
+// the need itself
+type needCriteria struct {
+	decayKm       float64                     // distance at which the distance score falls to 1/e
+	maxDistanceKm float64                     // distance beyond which volunteers are not matched; 0 means no cutoff
+	threshold     float64                     // minimum combined score for a match
+	minTrust      models.TrustLevel           // lowest trust level that may take on the need
+	priority      bool                        // whether an emergency prioritizes the need
+	excluded      map[primitive.ObjectID]bool // users kept away from the need by minor-safety rules or who declined it
+}
 
-		// Calculate distance
-		distance := m.calculateDistance(need.Location, volunteer.Location)
+// RequiredTrust is the trust level a volunteer needs to take on need: the
+// stricter of the requester's choice and the need's category setting
+func RequiredTrust(need *models.Need, tunables settings.Tunables) models.TrustLevel {
+	required := tunables.ForCategory(need.Category).MinTrust
+	if required.AtLeast(need.MinTrust) {
+		return required
+	}
+	return need.MinTrust
+}
 
-		// Apply distance penalty (closer is better)
-		distanceScore := m.calculateDistanceScore(distance)
+// beyondCutoff reports whether distance, in meters, is past a cutoff in
+// kilometers, where 0 means no cutoff
+func beyondCutoff(distance, cutoffKm float64) bool {
+	return cutoffKm > 0 && distance > cutoffKm*1000
+}
 
-		// Combine similarity and distance scores
-		combinedScore := similarity * distanceScore
+// scoreVolunteer scores a volunteer against a need, reporting false when
+// they do not match
+func (m *MatchingService) scoreVolunteer(need *models.Need, volunteer *models.Volunteer, criteria needCriteria) (models.Match, bool) {
+	// Skip if volunteer has no embedding, may not take on the need, or
+	// filtered out its category
+	level := trustLevel(volunteer.Trust)
+	if len(volunteer.Embedding) == 0 || criteria.excluded[volunteer.UserID] || !level.AtLeast(criteria.minTrust) || !volunteer.Wants(need) {
+		return models.Match{}, false
+	}
 
-		// Only include matches above threshold
-		if combinedScore > 0.3 {
-			matches = append(matches, models.Match{
-				NeedID:      need.ID,
-				VolunteerID: volunteer.ID,
-				Score:       combinedScore,
-				Distance:    distance,
-				CreatedAt:   time.Now(),
-			})
-		}
+	// Skip volunteers with no time for the need, weighing how well the rest
+	// are available
+	availability, ok := availabilityWeight(volunteer, need, time.Now())
+	if !ok {
+		return models.Match{}, false
 	}
 
-	// Sort by score (highest first)
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Score > matches[j].Score
-	})
+	// Calculate semantic similarity
+	similarity, err := m.embeddingService.CalculateSimilarity(need.Embedding, volunteer.Embedding)
+	if err != nil {
+		return models.Match{}, false // Skip this volunteer if similarity calculation fails
+	}
 
-	// Return top matches
-	if len(matches) > limit {
-		matches = matches[:limit]
+	// Calculate distance, leaving out volunteers past the category's cutoff
+	distance := m.calculateDistance(need.Location, volunteer.Location)
+	if beyondCutoff(distance, criteria.maxDistanceKm) {
+		return models.Match{}, false
 	}
 
-	return matches, nil
+	// Apply distance penalty (closer is better)
+	distanceScore := m.calculateDistanceScore(distance, criteria.decayKm)
+
+	// Combine similarity, distance, and availability scores
+	combinedScore := similarity * distanceScore * availability
+
+	// Only include matches above threshold, ranking well-regarded
+	// volunteers higher among them
+	if combinedScore <= criteria.threshold {
+		return models.Match{}, false
+	}
+	weight := reputationWeight(volunteer.Reputation)
+	return models.Match{
+		NeedID:      need.ID,
+		VolunteerID: volunteer.ID,
+		UserID:      volunteer.UserID,
+		Score:       combinedScore * weight,
+		Distance:    distance,
+		Priority:    criteria.priority,
+		Trust:       trustScoreOf(volunteer.Trust),
+		TrustLevel:  level,
+		Features: &models.MatchFeatures{
+			Similarity:       similarity,
+			Distance:         distance,
+			DistanceScore:    distanceScore,
+			DecayKm:          criteria.decayKm,
+			Availability:     availability,
+			ReputationWeight: weight,
+			Trust:            trustScoreOf(volunteer.Trust),
+			Threshold:        criteria.threshold,
+			Priority:         criteria.priority,
+		},
+		Components: &models.MatchComponents{
+			Similarity:    similarity,
+			DistanceScore: distanceScore,
+			Availability:  availability,
+			CategoryMatch: len(volunteer.Categories) > 0,
+		},
+		CreatedAt: time.Now(),
+	}, true
 }
 
 // FindMatchesForVolunteer finds matching needs for a specific volunteer
@@ -100,66 +252,242 @@ func (m *MatchingService) FindMatchesForVolunteer(ctx context.Context, volunteer
 		limit = 10
 	}
 
-	// Get all active needs
-	needs, err := m.getActiveNeeds(ctx)
+	// Volunteers suspended pending a safety review are not matched
+	if volunteer.Suspension != nil {
+		return nil, nil
+	}
+
+	// Get the active needs worth scoring, only loading those in nearby cells
+	// when the tunables bound how far away a match can be
+	near, err := m.nearbyNeeds(ctx, volunteer.Location, m.settings.Get(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergencies: %w", err)
+	}
+	needs, err := m.candidateNeeds(ctx, volunteer, limit, near)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get needs: %w", err)
 	}
+	return m.matchNeeds(ctx, volunteer, needs, limit)
+}
 
-	var matches []models.Match
+// matchNeeds scores needs for a volunteer, keeping the best limit
+func (m *MatchingService) matchNeeds(ctx context.Context, volunteer *models.Volunteer, needs []models.Need, limit int) ([]models.Match, error) {
+	tunables := m.settings.Get(ctx)
 
-	// Calculate similarity scores for each need
-	for _, need := range needs {
-		// Skip if need has no embedding
-		if len(need.Embedding) == 0 {
-			continue
+	// Minor-safety rules depend on the volunteer's account
+	var user models.User
+	err := m.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": volunteer.UserID}).Decode(&user)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get volunteer account: %w", err)
+	}
+	now := time.Now()
+	level := trustLevel(volunteer.Trust)
+
+	// Needs the volunteer declined are not offered again
+	declined, err := m.declinedNeeds(ctx, volunteer.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get declined matches: %w", err)
+	}
+
+	emergencies, err := m.emergencies.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergencies: %w", err)
+	}
+
+	// Needs an emergency prioritizes rank first, then by score
+	ranks := func(a, b models.Match) bool {
+		if a.Priority != b.Priority {
+			return a.Priority
+		}
+		return a.Score > b.Score
+	}
+
+	// Score needs across the worker pool, keeping the best
+	matches, err := scoreTopK(ctx, len(needs), limit, ranks, func(i int) (models.Match, bool) {
+		need := &needs[i]
+
+		// Skip if need has no embedding, is outside the volunteer's categories,
+		// or the volunteer declined it or may not take it on
+		if len(need.Embedding) == 0 || !volunteer.Wants(need) || declined[need.ID] || CheckTaskEligibility(&user, need.Category, now) != nil || !level.AtLeast(RequiredTrust(need, tunables)) {
+			return models.Match{}, false
+		}
+		availability, ok := availabilityWeight(volunteer, need, now)
+		if !ok {
+			return models.Match{}, false
 		}
+		matching := tunables.ForCategory(need.Category)
+		radius := emergencies.RadiusMultiplier(need.Location) * need.EscalationRadius()
 
 		// Calculate semantic similarity
 		similarity, err := m.embeddingService.CalculateSimilarity(volunteer.Embedding, need.Embedding)
 		if err != nil {
-			continue // Skip this need if similarity calculation fails
+			return models.Match{}, false // Skip this need if similarity calculation fails
 		}
 
-		// Calculate distance
+		// Calculate distance, leaving out needs past the category's cutoff
+		// Needs inside an emergency or escalated reach volunteers further away
 		distance := m.calculateDistance(need.Location, volunteer.Location)
+		if beyondCutoff(distance, matching.MaxDistanceKm*radius) {
+			return models.Match{}, false
+		}
 
 		// Apply distance penalty (closer is better)
-		distanceScore := m.calculateDistanceScore(distance)
+		distanceScore := m.calculateDistanceScore(distance, matching.DistanceDecayKm*radius)
 
-		// Combine similarity and distance scores
-		combinedScore := similarity * distanceScore
+		// Combine similarity, distance, and availability scores
+		combinedScore := similarity * distanceScore * availability
 
 		// Only include matches above threshold
-		if combinedScore > 0.3 {
-			matches = append(matches, models.Match{
-				NeedID:      need.ID,
-				VolunteerID: volunteer.ID,
-				Score:       combinedScore,
-				Distance:    distance,
-				CreatedAt:   time.Now(),
-			})
+		if combinedScore <= matching.MatchThreshold {
+			return models.Match{}, false
 		}
+		priority := emergencies.Prioritizes(need.Location, need.Category)
+		return models.Match{
+			NeedID:      need.ID,
+			VolunteerID: volunteer.ID,
+			UserID:      volunteer.UserID,
+			Score:       combinedScore,
+			Distance:    distance,
+			Priority:    priority,
+			Trust:       trustScoreOf(volunteer.Trust),
+			TrustLevel:  level,
+			Features: &models.MatchFeatures{
+				Similarity:       similarity,
+				Distance:         distance,
+				DistanceScore:    distanceScore,
+				DecayKm:          matching.DistanceDecayKm * radius,
+				Availability:     availability,
+				ReputationWeight: 1,
+				Trust:            trustScoreOf(volunteer.Trust),
+				Threshold:        matching.MatchThreshold,
+				Priority:         priority,
+			},
+			Components: &models.MatchComponents{
+				Similarity:    similarity,
+				DistanceScore: distanceScore,
+				Availability:  availability,
+				CategoryMatch: len(volunteer.Categories) > 0,
+			},
+			CreatedAt: time.Now(),
+		}, true
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort by score (highest first)
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Score > matches[j].Score
+	categories := make(map[primitive.ObjectID]models.Category, len(needs))
+	for _, need := range needs {
+		categories[need.ID] = need.Category
+	}
+	m.decisions.Record(ctx, models.MatchDecisionVolunteer, volunteer.ID, matches, func(match models.Match) (primitive.ObjectID, models.Category) {
+		return volunteer.UserID, categories[match.NeedID]
 	})
+	m.saveMatches(ctx, matches)
+	return matches, nil
+}
 
-	// Return top matches
-	if len(matches) > limit {
-		matches = matches[:limit]
+// NeedRecipient is a volunteer to notify of a need they matched, with how
+// far away it is and how they read distances
+type NeedRecipient struct {
+	UserID   string
+	Distance float64 // meters
+	Units    models.Units
+	Language models.Language
+}
+
+// Recipients maps matches for a need to the users who own the matched
+// volunteer profiles
+func (m *MatchingService) Recipients(ctx context.Context, matches []models.Match) ([]NeedRecipient, error) {
+	if len(matches) == 0 {
+		return nil, nil
 	}
 
-	return matches, nil
+	volunteerIDs := make([]primitive.ObjectID, len(matches))
+	distances := make(map[primitive.ObjectID]float64, len(matches))
+	for i, match := range matches {
+		volunteerIDs[i] = match.VolunteerID
+		distances[match.VolunteerID] = match.Distance
+	}
+	cursor, err := m.mongoClient.GetCollection("volunteers").Find(ctx, bson.M{"_id": bson.M{"$in": volunteerIDs}},
+		options.Find().SetProjection(bson.M{"user_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var volunteers []models.Volunteer
+	if err := cursor.All(ctx, &volunteers); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, len(volunteers))
+	for i, volunteer := range volunteers {
+		userIDs[i] = volunteer.UserID
+	}
+	cursor, err = m.mongoClient.GetCollection("users").Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}},
+		options.Find().SetProjection(bson.M{"units": 1, "language": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	recipients := make([]NeedRecipient, len(volunteers))
+	for i, volunteer := range volunteers {
+		user := byID[volunteer.UserID]
+		recipients[i] = NeedRecipient{
+			UserID:   volunteer.UserID.Hex(),
+			Distance: distances[volunteer.ID],
+			Units:    user.Units,
+			Language: user.Language,
+		}
+	}
+	return recipients, nil
+}
+
+// MarkNotified records the users told about a need, so boosting it reaches
+// others. It is best-effort, so failures are logged.
+func (m *MatchingService) MarkNotified(ctx context.Context, needID primitive.ObjectID, recipients []NeedRecipient) {
+	if len(recipients) == 0 {
+		return
+	}
+	userIDs := make([]primitive.ObjectID, 0, len(recipients))
+	for _, recipient := range recipients {
+		if id, err := primitive.ObjectIDFromHex(recipient.UserID); err == nil {
+			userIDs = append(userIDs, id)
+		}
+	}
+	_, err := m.mongoClient.GetCollection("needs").UpdateOne(ctx, bson.M{"_id": needID},
+		bson.M{"$addToSet": bson.M{"notified": bson.M{"$each": userIDs}}})
+	if err != nil {
+		log.Printf("Failed to record volunteers notified of need %s: %v", needID.Hex(), err)
+	}
 }
 
 // getActiveVolunteers retrieves all active volunteers
 func (m *MatchingService) getActiveVolunteers(ctx context.Context) ([]models.Volunteer, error) {
+	return m.findActiveVolunteers(ctx, bson.M{})
+}
+
+// findActiveVolunteers retrieves the active volunteers that also match filter
+func (m *MatchingService) findActiveVolunteers(ctx context.Context, filter bson.M) ([]models.Volunteer, error) {
 	collection := m.mongoClient.GetCollection("volunteers")
-	
-	cursor, err := collection.Find(ctx, bson.M{})
+
+	// Volunteers hidden by moderators or suspended pending a safety review
+	// are not matched
+	active := bson.M{
+		"hidden":              bson.M{"$ne": true},
+		"matching_suspension": bson.M{"$exists": false},
+	}
+	if len(filter) > 0 {
+		active = bson.M{"$and": []bson.M{active, filter}}
+	}
+
+	cursor, err := collection.Find(ctx, active)
 	if err != nil {
 		return nil, err
 	}
@@ -175,18 +503,37 @@ func (m *MatchingService) getActiveVolunteers(ctx context.Context) ([]models.Vol
 
 // getActiveNeeds retrieves all active needs
 func (m *MatchingService) getActiveNeeds(ctx context.Context) ([]models.Need, error) {
+	return m.findActiveNeeds(ctx, bson.M{})
+}
+
+// findActiveNeeds retrieves the active needs that also match filter
+func (m *MatchingService) findActiveNeeds(ctx context.Context, filter bson.M) ([]models.Need, error) {
 	collection := m.mongoClient.GetCollection("needs")
-	
-	// Only get needs that are still open
-	filter := bson.M{
-		"status": bson.M{"$in": []string{"requested", "matched"}},
+
+	// Requesters suspended pending a safety review are not matched
+	suspended, err := m.mongoClient.GetCollection("users").Distinct(ctx, "_id", bson.M{"matching_suspension": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only get needs that are still open and not hidden or held by moderation
+	active := bson.M{
+		"status":          bson.M{"$in": []string{"requested", "matched"}},
+		"hidden":          bson.M{"$ne": true},
+		"held_for_review": bson.M{"$ne": true},
 		"$or": []bson.M{
 			{"expires_at": bson.M{"$exists": false}},
 			{"expires_at": bson.M{"$gt": time.Now()}},
 		},
 	}
+	if len(suspended) > 0 {
+		active["user_id"] = bson.M{"$nin": suspended}
+	}
+	if len(filter) > 0 {
+		active = bson.M{"$and": []bson.M{active, filter}}
+	}
 
-	cursor, err := collection.Find(ctx, filter)
+	cursor, err := collection.Find(ctx, active)
 	if err != nil {
 		return nil, err
 	}
@@ -221,13 +568,13 @@ func (m *MatchingService) calculateDistance(loc1, loc2 models.Location) float64
 }
 
 // calculateDistanceScore calculates a score based on distance (closer is better)
-func (m *MatchingService) calculateDistanceScore(distance float64) float64 {
+func (m *MatchingService) calculateDistanceScore(distance, decayKm float64) float64 {
 	// Convert distance to kilometers
 	distanceKm := distance / 1000
 
-	// Use exponential decay: score = e^(-distance/10)
-	// This gives a score of 1.0 for 0km, 0.37 for 10km, 0.14 for 20km, etc.
-	return math.Exp(-distanceKm / 10.0)
+	// Use exponential decay: score = e^(-distance/decay)
+	// With the default 10km decay this gives 1.0 for 0km, 0.37 for 10km, 0.14 for 20km, etc.
+	return math.Exp(-distanceKm / decayKm)
 }
 
 // GenerateH3Index generates an H3 index for privacy-preserving location matching
@@ -250,7 +597,7 @@ func (m *MatchingService) GetNearbyH3Indices(h3Index string, radiusKm float64) (
 
 	// Get indices within the specified radius
 	indices := h3.GridDisk(index, int(radiusKm))
-	
+
 	result := make([]string, len(indices))
 	for i, idx := range indices {
 		result[i] = idx.String()
@@ -269,7 +616,7 @@ func (m *MatchingService) UpdateNeedEmbedding(ctx context.Context, need *models.
 		ctx,
 		need.Title,
 		need.Description,
-		need.Category,
+		need.CategoryPath(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to generate need embedding: %w", err)
@@ -281,8 +628,8 @@ func (m *MatchingService) UpdateNeedEmbedding(ctx context.Context, need *models.
 		ctx,
 		bson.M{"_id": need.ID},
 		bson.M{"$set": bson.M{
-			"embedding":   embedding,
-			"updated_at":  time.Now(),
+			"embedding":  embedding,
+			"updated_at": time.Now(),
 		}},
 	)
 	if err != nil {
@@ -290,6 +637,7 @@ func (m *MatchingService) UpdateNeedEmbedding(ctx context.Context, need *models.
 	}
 
 	need.Embedding = embedding
+	m.indexVector(ctx, vectors.NamespaceNeeds, need.ID, embedding)
 	return nil
 }
 
@@ -315,8 +663,8 @@ func (m *MatchingService) UpdateVolunteerEmbedding(ctx context.Context, voluntee
 		ctx,
 		bson.M{"_id": volunteer.ID},
 		bson.M{"$set": bson.M{
-			"embedding":   embedding,
-			"updated_at":  time.Now(),
+			"embedding":  embedding,
+			"updated_at": time.Now(),
 		}},
 	)
 	if err != nil {
@@ -324,5 +672,201 @@ func (m *MatchingService) UpdateVolunteerEmbedding(ctx context.Context, voluntee
 	}
 
 	volunteer.Embedding = embedding
+	m.indexVector(ctx, vectors.NamespaceVolunteers, volunteer.ID, embedding)
+	return nil
+}
+
+// FindOffersForNeed finds standing offers that could meet a need
+func (m *MatchingService) FindOffersForNeed(ctx context.Context, need *models.Need, limit int) ([]models.OfferMatch, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(need.Embedding) == 0 {
+		return []models.OfferMatch{}, nil
+	}
+	tunables := m.settings.Get(ctx)
+
+	offers, err := m.getActiveOffers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offers: %w", err)
+	}
+
+	// Minor-safety rules keep some offerers away from this need
+	ineligible, err := m.mongoClient.GetCollection("users").Distinct(ctx, "_id", ineligibleUsersFilter(need.Category, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ineligible offerers: %w", err)
+	}
+	excluded := make(map[primitive.ObjectID]bool, len(ineligible)+1)
+	for _, id := range ineligible {
+		if userID, ok := id.(primitive.ObjectID); ok {
+			excluded[userID] = true
+		}
+	}
+	excluded[need.UserID] = true
+
+	// Emergencies covering the need, and escalation for missing an SLA, reach
+	// offers further away
+	emergencies, err := m.emergencies.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergencies: %w", err)
+	}
+	matching := tunables.ForCategory(need.Category)
+	radius := emergencies.RadiusMultiplier(need.Location) * need.EscalationRadius()
+	priority := emergencies.Prioritizes(need.Location, need.Category)
+
+	matches := []models.OfferMatch{}
+	for _, offer := range offers {
+		if len(offer.Embedding) == 0 || excluded[offer.UserID] {
+			continue
+		}
+		similarity, err := m.embeddingService.CalculateSimilarity(need.Embedding, offer.Embedding)
+		if err != nil {
+			continue
+		}
+		distance := m.calculateDistance(need.Location, offer.Location)
+		if beyondCutoff(distance, matching.MaxDistanceKm*radius) {
+			continue
+		}
+		score := similarity * m.calculateDistanceScore(distance, matching.DistanceDecayKm*radius)
+		if score > matching.MatchThreshold {
+			matches = append(matches, models.OfferMatch{
+				OfferID:  offer.ID,
+				NeedID:   need.ID,
+				Score:    score,
+				Distance: distance,
+				Priority: priority,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// FindNeedsForOffer finds open needs a standing offer could meet, those an
+// emergency prioritizes first
+func (m *MatchingService) FindNeedsForOffer(ctx context.Context, offer *models.Offer, limit int) ([]models.OfferMatch, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(offer.Embedding) == 0 {
+		return []models.OfferMatch{}, nil
+	}
+	tunables := m.settings.Get(ctx)
+
+	needs, err := m.getActiveNeeds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get needs: %w", err)
+	}
+
+	// Minor-safety rules depend on the offerer's account
+	var user models.User
+	err = m.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": offer.UserID}).Decode(&user)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get offerer account: %w", err)
+	}
+	now := time.Now()
+
+	emergencies, err := m.emergencies.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergencies: %w", err)
+	}
+
+	matches := []models.OfferMatch{}
+	for _, need := range needs {
+		// Only needs nobody has taken on yet can be met through the offer
+		if need.Status != models.NeedStatusRequested || need.UserID == offer.UserID || len(need.Embedding) == 0 {
+			continue
+		}
+		if CheckTaskEligibility(&user, need.Category, now) != nil {
+			continue
+		}
+		similarity, err := m.embeddingService.CalculateSimilarity(offer.Embedding, need.Embedding)
+		if err != nil {
+			continue
+		}
+		matching := tunables.ForCategory(need.Category)
+		radius := emergencies.RadiusMultiplier(need.Location) * need.EscalationRadius()
+		distance := m.calculateDistance(need.Location, offer.Location)
+		if beyondCutoff(distance, matching.MaxDistanceKm*radius) {
+			continue
+		}
+		score := similarity * m.calculateDistanceScore(distance, matching.DistanceDecayKm*radius)
+		if score > matching.MatchThreshold {
+			matches = append(matches, models.OfferMatch{
+				OfferID:  offer.ID,
+				NeedID:   need.ID,
+				Score:    score,
+				Distance: distance,
+				Priority: emergencies.Prioritizes(need.Location, need.Category),
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Priority != matches[j].Priority {
+			return matches[i].Priority
+		}
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// getActiveOffers retrieves the offers open to new needs
+func (m *MatchingService) getActiveOffers(ctx context.Context) ([]models.Offer, error) {
+	cursor, err := m.mongoClient.GetCollection("offers").Find(ctx, bson.M{
+		"status": models.OfferActive,
+		"hidden": bson.M{"$ne": true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var offers []models.Offer
+	if err = cursor.All(ctx, &offers); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// UpdateOfferEmbedding updates the embedding for a standing offer
+func (m *MatchingService) UpdateOfferEmbedding(ctx context.Context, offer *models.Offer) error {
+	if !m.embeddingService.IsAvailable() {
+		return fmt.Errorf("embedding service not available")
+	}
+
+	embedding, err := m.embeddingService.GenerateOfferEmbedding(
+		ctx,
+		offer.Title,
+		offer.Description,
+		string(offer.Category),
+		offer.Schedule,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to generate offer embedding: %w", err)
+	}
+
+	_, err = m.mongoClient.GetCollection("offers").UpdateOne(
+		ctx,
+		bson.M{"_id": offer.ID},
+		bson.M{"$set": bson.M{
+			"embedding":  embedding,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update offer embedding: %w", err)
+	}
+
+	offer.Embedding = embedding
 	return nil
 } 
\ No newline at end of file
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/models"
+)
+
+// Errors returned when changing a match's state
+var (
+	ErrMatchNotFound = errors.New("match not found")
+	ErrMatchAccepted = errors.New("match was already accepted")
+)
+
+// saveMatches stores computed matches, one per need and volunteer, keeping
+// the state of pairs matched before, and fills in each match's state. It is
+// best-effort, so failures are logged and leave the states empty.
+func (m *MatchingService) saveMatches(ctx context.Context, matches []models.Match) {
+	if m.dryRun || len(matches) == 0 {
+		return
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, len(matches))
+	pairs := make([]bson.M, len(matches))
+	for i, match := range matches {
+		pair := bson.M{"need_id": match.NeedID, "volunteer_id": match.VolunteerID}
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(pair).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"user_id":     match.UserID,
+					"score":       match.Score,
+					"distance":    match.Distance,
+					"priority":    match.Priority,
+					"trust":       match.Trust,
+					"trust_level": match.TrustLevel,
+					"components":  match.Components,
+					"updated_at":  now,
+				},
+				"$setOnInsert": bson.M{"state": models.MatchStateSuggested, "created_at": now},
+			}).
+			SetUpsert(true)
+		pairs[i] = pair
+	}
+	collection := m.mongoClient.GetCollection("matches")
+	if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		log.Printf("Failed to save %d matches: %v", len(matches), err)
+		return
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"$or": pairs},
+		options.Find().SetProjection(bson.M{"need_id": 1, "volunteer_id": 1, "state": 1}))
+	if err != nil {
+		log.Printf("Failed to read match states: %v", err)
+		return
+	}
+	var saved []models.Match
+	if err := cursor.All(ctx, &saved); err != nil {
+		log.Printf("Failed to read match states: %v", err)
+		return
+	}
+	states := make(map[[2]primitive.ObjectID]models.MatchState, len(saved))
+	for _, match := range saved {
+		states[[2]primitive.ObjectID{match.NeedID, match.VolunteerID}] = match.State
+	}
+	for i := range matches {
+		matches[i].State = states[[2]primitive.ObjectID{matches[i].NeedID, matches[i].VolunteerID}]
+	}
+}
+
+// declinedVolunteers returns the users who declined a need
+func (m *MatchingService) declinedVolunteers(ctx context.Context, needID primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return m.declined(ctx, "user_id", bson.M{"need_id": needID, "state": models.MatchStateDeclined})
+}
+
+// declinedNeeds returns the needs a user declined
+func (m *MatchingService) declinedNeeds(ctx context.Context, userID primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return m.declined(ctx, "need_id", bson.M{"user_id": userID, "state": models.MatchStateDeclined})
+}
+
+// declined collects field from the declined matches matching filter
+func (m *MatchingService) declined(ctx context.Context, field string, filter bson.M) (map[primitive.ObjectID]bool, error) {
+	ids, err := m.mongoClient.GetCollection("matches").Distinct(ctx, field, filter)
+	if err != nil {
+		return nil, err
+	}
+	declined := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			declined[oid] = true
+		}
+	}
+	return declined, nil
+}
+
+// MarkViewed records that a user was shown their matches with needs. It is
+// best-effort, so failures are logged.
+func (m *MatchingService) MarkViewed(ctx context.Context, userID primitive.ObjectID, needIDs []primitive.ObjectID) {
+	if len(needIDs) == 0 {
+		return
+	}
+	_, err := m.mongoClient.GetCollection("matches").UpdateMany(ctx,
+		bson.M{"user_id": userID, "need_id": bson.M{"$in": needIDs}, "state": models.MatchStateSuggested},
+		bson.M{"$set": bson.M{"state": models.MatchStateViewed, "updated_at": time.Now()}})
+	if err != nil {
+		log.Printf("Failed to mark matches viewed for user %s: %v", userID.Hex(), err)
+	}
+}
+
+// MarkAccepted records that a user took on a need they were matched with.
+// It is best-effort, so failures are logged.
+func (m *MatchingService) MarkAccepted(ctx context.Context, userID, needID primitive.ObjectID) {
+	_, err := m.mongoClient.GetCollection("matches").UpdateMany(ctx,
+		bson.M{"user_id": userID, "need_id": needID},
+		bson.M{"$set": bson.M{"state": models.MatchStateAccepted, "updated_at": time.Now()}})
+	if err != nil {
+		log.Printf("Failed to mark match of need %s accepted: %v", needID.Hex(), err)
+	}
+}
+
+// DeclineMatch stops matching a user with a need
+func (m *MatchingService) DeclineMatch(ctx context.Context, userID, needID primitive.ObjectID) (*models.Match, error) {
+	return m.setMatchState(ctx, userID, needID, models.MatchStateDeclined,
+		[]models.MatchState{models.MatchStateSuggested, models.MatchStateViewed, models.MatchStateDeclined})
+}
+
+// RestoreMatch undoes declining a match, so the need can be matched to the
+// user again
+func (m *MatchingService) RestoreMatch(ctx context.Context, userID, needID primitive.ObjectID) (*models.Match, error) {
+	return m.setMatchState(ctx, userID, needID, models.MatchStateViewed,
+		[]models.MatchState{models.MatchStateDeclined, models.MatchStateViewed})
+}
+
+// setMatchState moves the user's match with a need to state from one of the
+// states in from
+func (m *MatchingService) setMatchState(ctx context.Context, userID, needID primitive.ObjectID, state models.MatchState, from []models.MatchState) (*models.Match, error) {
+	collection := m.mongoClient.GetCollection("matches")
+	var match models.Match
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"user_id": userID, "need_id": needID, "state": bson.M{"$in": from}},
+		bson.M{"$set": bson.M{"state": state, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&match)
+	if err == nil {
+		return &match, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	// Tell a match that was accepted from one that doesn't exist
+	accepted, err := collection.CountDocuments(ctx, bson.M{"user_id": userID, "need_id": needID, "state": models.MatchStateAccepted})
+	if err != nil {
+		return nil, err
+	}
+	if accepted > 0 {
+		return nil, ErrMatchAccepted
+	}
+	return nil, ErrMatchNotFound
+} 
\ No newline at end of file
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// ErrMessagingClosed is returned when sending a message on a task that is
+// completed or cancelled
+var ErrMessagingClosed = errors.New("messages can only be sent while the task is active")
+
+// MessageService handles chat between a need's creator and the volunteer on
+// its task
+type MessageService struct {
+	mongoClient *database.MongoClient
+	moderation  *ModerationService
+	notify      Notifier
+}
+
+// NewMessageService creates a new message service. Messages are screened
+// with moderationService and delivered to both participants through notify.
+func NewMessageService(mongoClient *database.MongoClient, moderationService *ModerationService, notify Notifier) *MessageService {
+	return &MessageService{
+		mongoClient: mongoClient,
+		moderation:  moderationService,
+		notify:      notify,
+	}
+}
+
+// Send posts a message from userID to the other participant of a task and
+// delivers it to both of them
+func (s *MessageService) Send(ctx context.Context, taskID, userID primitive.ObjectID, body string) (*models.Message, error) {
+	task, otherID, err := s.participant(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch task.Status {
+	case models.TaskStatusAccepted, models.TaskStatusInProgress, models.TaskStatusAwaitingConfirmation:
+	default:
+		return nil, ErrMessagingClosed
+	}
+
+	message := models.Message{
+		ID:          primitive.NewObjectID(),
+		TaskID:      task.ID,
+		NeedID:      task.NeedID,
+		SenderID:    userID,
+		RecipientID: otherID,
+		Body:        body,
+		CreatedAt:   time.Now(),
+	}
+	if _, err := s.mongoClient.GetCollection("messages").InsertOne(ctx, message); err != nil {
+		return nil, err
+	}
+	s.moderation.Screen(ctx, models.ContentMessage, message.ID, userID, message.Body)
+
+	// The sender's other sessions get the message too, so every open
+	// conversation stays in step
+	notification := models.WebSocketMessage{Type: "chat_message", Payload: message}
+	if err := s.notify(ctx, []string{otherID.Hex(), userID.Hex()}, notification); err != nil {
+		log.Printf("Failed to deliver message %s: %v", message.ID.Hex(), err)
+	}
+	return &message, nil
+}
+
+// List returns the visible messages of a task userID takes part in, newest
+// first
+func (s *MessageService) List(ctx context.Context, taskID, userID primitive.ObjectID, limit, offset int64) ([]models.Message, error) {
+	if _, _, err := s.participant(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("messages").Find(ctx,
+		bson.M{"task_id": taskID, "hidden": bson.M{"$ne": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []models.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkRead marks the messages userID has received on a task as read and
+// tells the sender, returning how many were newly read
+func (s *MessageService) MarkRead(ctx context.Context, taskID, userID primitive.ObjectID) (int64, error) {
+	_, otherID, err := s.participant(ctx, taskID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	result, err := s.mongoClient.GetCollection("messages").UpdateMany(ctx,
+		bson.M{"task_id": taskID, "recipient_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": now}})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.ModifiedCount > 0 {
+		notification := models.WebSocketMessage{
+			Type: "chat_read",
+			Payload: map[string]interface{}{
+				"task_id": taskID.Hex(),
+				"read_at": now,
+			},
+		}
+		if err := s.notify(ctx, []string{otherID.Hex()}, notification); err != nil {
+			log.Printf("Failed to deliver read receipt for task %s: %v", taskID.Hex(), err)
+		}
+	}
+	return result.ModifiedCount, nil
+}
+
+// participant returns a task userID takes part in, as the need's creator or
+// its volunteer, and the other participant. Tasks of other users are
+// reported as not found.
+func (s *MessageService) participant(ctx context.Context, taskID, userID primitive.ObjectID) (*models.Task, primitive.ObjectID, error) {
+	var task models.Task
+	err := s.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{"_id": taskID}).Decode(&task)
+	if err == mongo.ErrNoDocuments {
+		return nil, primitive.NilObjectID, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+
+	var need models.Need
+	err = s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1})).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, primitive.NilObjectID, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+
+	switch userID {
+	case need.UserID:
+		return &task, task.VolunteerID, nil
+	case task.VolunteerID:
+		return &task, need.UserID, nil
+	}
+	return nil, primitive.NilObjectID, ErrTaskNotFound
+} 
\ No newline at end of file
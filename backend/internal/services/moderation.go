@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// Moderation errors
+var (
+	ErrContentNotFound    = errors.New("content not found")
+	ErrModerationNotFound = errors.New("moderation item not found")
+	ErrModerationResolved = errors.New("moderation item already resolved")
+	ErrFieldNotEditable   = errors.New("field cannot be edited")
+	ErrDuplicateReport    = errors.New("content already reported")
+)
+
+// Notifier delivers a message to users, wherever they are connected
+type Notifier func(ctx context.Context, userIDs []string, message models.WebSocketMessage) error
+
+// moderatedContent describes where each content type is stored
+type moderatedContent struct {
+	collection string
+	ownerField string
+	textFields []string // fields shown to moderators and editable by them
+}
+
+var moderatedContentTypes = map[models.ContentType]moderatedContent{
+	models.ContentNeed:     {collection: "needs", ownerField: "user_id", textFields: []string{"title", "description"}},
+	models.ContentProfile:  {collection: "volunteers", ownerField: "user_id", textFields: []string{"description"}},
+	models.ContentMessage:  {collection: "messages", ownerField: "sender_id", textFields: []string{"body"}},
+	models.ContentUser:     {collection: "users", ownerField: "_id", textFields: []string{"name"}},
+	models.ContentPost:     {collection: "posts", ownerField: "author_id", textFields: []string{"title", "body"}},
+	models.ContentOffer:    {collection: "offers", ownerField: "user_id", textFields: []string{"title", "description"}},
+	models.ContentFeedback: {collection: "feedback", ownerField: "from_user_id", textFields: []string{"comment"}},
+	models.ContentKudos:    {collection: "kudos", ownerField: "from_user_id", textFields: []string{"message"}},
+}
+
+// reportOutcomes maps a moderator decision to the outcome of its reports
+var reportOutcomes = map[models.ModerationStatus]models.ReportStatus{
+	models.ModerationApproved: models.ReportDismissed,
+	models.ModerationEdited:   models.ReportActioned,
+	models.ModerationHidden:   models.ReportActioned,
+}
+
+// ModerationService maintains the review queue for flagged content
+type ModerationService struct {
+	mongoClient   *database.MongoClient
+	sla           time.Duration
+	escalationSLA time.Duration
+	classifier    *AbuseClassifier
+	notify        Notifier
+}
+
+// NewModerationService creates a moderation service. New items are due within
+// sla and escalated items within escalationSLA; text the classifier flags is
+// queued automatically. Reporters hear about outcomes through notify.
+func NewModerationService(mongoClient *database.MongoClient, classifier *AbuseClassifier, notify Notifier, sla, escalationSLA time.Duration) *ModerationService {
+	return &ModerationService{
+		mongoClient:   mongoClient,
+		sla:           sla,
+		escalationSLA: escalationSLA,
+		classifier:    classifier,
+		notify:        notify,
+	}
+}
+
+// Report records a user's report and queues the content for review
+func (s *ModerationService) Report(ctx context.Context, reporterID primitive.ObjectID, req models.ReportContentRequest) (*models.AbuseReport, error) {
+	contentID, err := primitive.ObjectIDFromHex(req.ContentID)
+	if err != nil {
+		return nil, ErrContentNotFound
+	}
+
+	ownerID, snapshot, err := s.loadContent(ctx, req.ContentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := s.mongoClient.GetCollection("reports")
+	unresolved, err := reports.CountDocuments(ctx, bson.M{
+		"reporter_id":  reporterID,
+		"content_type": req.ContentType,
+		"content_id":   contentID,
+		"status":       bson.M{"$in": []models.ReportStatus{models.ReportOpen, models.ReportReviewing}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unresolved > 0 {
+		return nil, ErrDuplicateReport
+	}
+
+	item, err := s.flag(ctx, req.ContentType, contentID, ownerID, snapshot, models.ModerationSourceReport, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := models.AbuseReport{
+		ID:               primitive.NewObjectID(),
+		ReporterID:       reporterID,
+		ContentType:      req.ContentType,
+		ContentID:        contentID,
+		SubjectID:        ownerID,
+		ModerationItemID: item.ID,
+		Reason:           req.Reason,
+		Details:          req.Details,
+		Status:           models.ReportOpen,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if item.Status == models.ModerationEscalated {
+		report.Status = models.ReportReviewing
+	}
+	if _, err := reports.InsertOne(ctx, report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Reports lists reports matching filter, newest first; a zero limit returns all
+func (s *ModerationService) Reports(ctx context.Context, filter bson.M, limit int64) ([]models.AbuseReport, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("reports").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []models.AbuseReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Classify returns the reasons text looks abusive, or nil when it looks fine
+func (s *ModerationService) Classify(ctx context.Context, text string) []string {
+	if s == nil {
+		return nil
+	}
+	return s.classifier.Classify(ctx, text)
+}
+
+// Queue adds an automated finding to the review queue. Failures are logged
+// rather than blocking the write that triggered them.
+func (s *ModerationService) Queue(ctx context.Context, contentType models.ContentType, contentID, ownerID primitive.ObjectID, text string, reasons []string) {
+	if s == nil || len(reasons) == 0 {
+		return
+	}
+
+	if _, err := s.flag(ctx, contentType, contentID, ownerID, text, models.ModerationSourceAutomated, reasons, false); err != nil {
+		log.Printf("Failed to queue %s %s for moderation: %v", contentType, contentID.Hex(), err)
+	}
+}
+
+// Appeal queues content its subject disputes, such as a low rating, for a
+// moderator to decide on
+func (s *ModerationService) Appeal(ctx context.Context, contentType models.ContentType, contentID, ownerID primitive.ObjectID, text string) error {
+	_, err := s.flag(ctx, contentType, contentID, ownerID, text, models.ModerationSourceAppeal, []string{"appeal"}, false)
+	return err
+}
+
+// Incident queues a safety incident reported against userID, with the
+// reporter's description as the snapshot when the user is not already in
+// the queue
+func (s *ModerationService) Incident(ctx context.Context, userID primitive.ObjectID, severity models.IncidentSeverity, description string) (*models.ModerationItem, error) {
+	return s.flag(ctx, models.ContentUser, userID, userID, description, models.ModerationSourceIncident, []string{"incident_" + string(severity)}, false)
+}
+
+// AlertModerators sends message to every moderator. Failures are logged,
+// since the item is already in the queue.
+func (s *ModerationService) AlertModerators(ctx context.Context, message models.WebSocketMessage) {
+	if s.notify == nil {
+		return
+	}
+	values, err := s.mongoClient.GetCollection("users").Distinct(ctx, "_id", bson.M{"role": bson.M{"$in": []string{models.RoleModerator, models.RoleAdmin}}})
+	if err != nil {
+		log.Printf("Failed to look up moderators to alert: %v", err)
+		return
+	}
+	moderatorIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			moderatorIDs = append(moderatorIDs, id.Hex())
+		}
+	}
+	if err := s.notify(ctx, moderatorIDs, message); err != nil {
+		log.Printf("Failed to alert moderators: %v", err)
+	}
+}
+
+// Screen classifies newly written text and queues it when anything is found,
+// reporting whether it was flagged
+func (s *ModerationService) Screen(ctx context.Context, contentType models.ContentType, contentID, ownerID primitive.ObjectID, text string) bool {
+	reasons := s.Classify(ctx, text)
+	s.Queue(ctx, contentType, contentID, ownerID, text, reasons)
+	return len(reasons) > 0
+}
+
+// flag adds a report or automated finding to the open item for the content,
+// creating the item if the content is not already in the queue
+func (s *ModerationService) flag(ctx context.Context, contentType models.ContentType, contentID, ownerID primitive.ObjectID, snapshot, source string, reasons []string, reported bool) (*models.ModerationItem, error) {
+	now := time.Now()
+	filter := bson.M{
+		"content_type": contentType,
+		"content_id":   contentID,
+		"status":       bson.M{"$in": []models.ModerationStatus{models.ModerationPending, models.ModerationEscalated}},
+	}
+
+	update := bson.M{
+		"$set":      bson.M{"updated_at": now},
+		"$addToSet": bson.M{"sources": source},
+		"$setOnInsert": bson.M{
+			"owner_id":   ownerID,
+			"status":     models.ModerationPending,
+			"snapshot":   snapshot,
+			"created_at": now,
+			"due_at":     now.Add(s.sla),
+		},
+	}
+	if len(reasons) > 0 {
+		update["$addToSet"] = bson.M{"sources": source, "reasons": bson.M{"$each": reasons}}
+	}
+	if reported {
+		update["$inc"] = bson.M{"report_count": 1}
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var item models.ModerationItem
+	err := s.mongoClient.GetCollection("moderation_items").FindOneAndUpdate(ctx, filter, update, opts).Decode(&item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Get returns a queue item together with the current content and its
+// reports. Opening an item moves its open reports into review.
+func (s *ModerationService) Get(ctx context.Context, id primitive.ObjectID) (*models.ModerationItem, bson.M, []models.AbuseReport, error) {
+	item, err := s.find(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	spec := moderatedContentTypes[item.ContentType]
+	var content bson.M
+	err = s.mongoClient.GetCollection(spec.collection).FindOne(ctx, bson.M{"_id": item.ContentID}).Decode(&content)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, nil, nil, err
+	}
+	if err := s.mongoClient.Unseal(content); err != nil {
+		return nil, nil, nil, err
+	}
+	for _, field := range []string{"embedding", "password"} {
+		delete(content, field)
+	}
+
+	if item.Status.Open() {
+		if err := s.markReviewing(ctx, item.ID); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	reports, err := s.Reports(ctx, bson.M{"moderation_item_id": item.ID}, 100)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return item, content, reports, nil
+}
+
+// Approve leaves the content up, restoring it if it had been hidden
+func (s *ModerationService) Approve(ctx context.Context, id, moderatorID primitive.ObjectID, note string) (*models.ModerationItem, error) {
+	item, err := s.findOpen(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setHidden(ctx, item, false); err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, item, moderatorID, models.ModerationApproved, note)
+}
+
+// Hide takes the content down
+func (s *ModerationService) Hide(ctx context.Context, id, moderatorID primitive.ObjectID, note string) (*models.ModerationItem, error) {
+	item, err := s.findOpen(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setHidden(ctx, item, true); err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, item, moderatorID, models.ModerationHidden, note)
+}
+
+// Edit replaces text fields of the content and leaves it up
+func (s *ModerationService) Edit(ctx context.Context, id, moderatorID primitive.ObjectID, req models.ModerationEditRequest) (*models.ModerationItem, error) {
+	item, err := s.findOpen(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := moderatedContentTypes[item.ContentType]
+	updates := bson.M{"hidden": false, "updated_at": time.Now()}
+	for field, value := range req.Fields {
+		if !contains(spec.textFields, field) {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotEditable, field)
+		}
+		updates[field] = sanitize.Text(value)
+	}
+
+	result, err := s.mongoClient.GetCollection(spec.collection).UpdateOne(ctx, bson.M{"_id": item.ContentID}, bson.M{"$set": updates})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrContentNotFound
+	}
+	return s.resolve(ctx, item, moderatorID, models.ModerationEdited, req.Note)
+}
+
+// Escalate hands the item to senior moderators with a tighter deadline
+func (s *ModerationService) Escalate(ctx context.Context, id, moderatorID primitive.ObjectID, note string) (*models.ModerationItem, error) {
+	item, err := s.findOpen(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Status == models.ModerationEscalated {
+		return item, nil
+	}
+
+	now := time.Now()
+	dueAt := now.Add(s.escalationSLA)
+	if item.DueAt.Before(dueAt) {
+		dueAt = item.DueAt
+	}
+
+	_, err = s.mongoClient.GetCollection("moderation_items").UpdateOne(ctx, bson.M{"_id": item.ID}, bson.M{"$set": bson.M{
+		"status":       models.ModerationEscalated,
+		"reviewed_by":  moderatorID,
+		"note":         note,
+		"escalated_at": now,
+		"due_at":       dueAt,
+		"updated_at":   now,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status, item.ReviewedBy, item.Note = models.ModerationEscalated, &moderatorID, note
+	item.EscalatedAt, item.DueAt, item.UpdatedAt = &now, dueAt, now
+	if err := s.markReviewing(ctx, item.ID); err != nil {
+		log.Printf("Failed to mark reports for moderation item %s as reviewing: %v", item.ID.Hex(), err)
+	}
+	return item, nil
+}
+
+// markReviewing moves an item's open reports into review
+func (s *ModerationService) markReviewing(ctx context.Context, itemID primitive.ObjectID) error {
+	_, err := s.mongoClient.GetCollection("reports").UpdateMany(
+		ctx,
+		bson.M{"moderation_item_id": itemID, "status": models.ReportOpen},
+		bson.M{"$set": bson.M{"status": models.ReportReviewing, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// resolveReports closes an item's reports with the outcome of the decision
+// and lets each reporter know
+func (s *ModerationService) resolveReports(ctx context.Context, item *models.ModerationItem) error {
+	outcome := reportOutcomes[item.Status]
+	filter := bson.M{
+		"moderation_item_id": item.ID,
+		"status":             bson.M{"$in": []models.ReportStatus{models.ReportOpen, models.ReportReviewing}},
+	}
+
+	reports, err := s.Reports(ctx, filter, 0)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	_, err = s.mongoClient.GetCollection("reports").UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"status":      outcome,
+		"resolved_at": now,
+		"updated_at":  now,
+	}})
+	if err != nil {
+		return err
+	}
+
+	if s.notify == nil {
+		return nil
+	}
+	for _, report := range reports {
+		message := models.WebSocketMessage{
+			Type: "report_resolved",
+			Payload: map[string]interface{}{
+				"report_id":    report.ID.Hex(),
+				"content_type": report.ContentType,
+				"status":       outcome,
+			},
+		}
+		if err := s.notify(ctx, []string{report.ReporterID.Hex()}, message); err != nil {
+			log.Printf("Failed to notify reporter of report %s: %v", report.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// resolve records the moderator's final decision
+func (s *ModerationService) resolve(ctx context.Context, item *models.ModerationItem, moderatorID primitive.ObjectID, status models.ModerationStatus, note string) (*models.ModerationItem, error) {
+	now := time.Now()
+	_, err := s.mongoClient.GetCollection("moderation_items").UpdateOne(ctx, bson.M{"_id": item.ID}, bson.M{"$set": bson.M{
+		"status":      status,
+		"reviewed_by": moderatorID,
+		"note":        note,
+		"resolved_at": now,
+		"updated_at":  now,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status, item.ReviewedBy, item.Note = status, &moderatorID, note
+	item.ResolvedAt, item.UpdatedAt = &now, now
+	if err := s.resolveReports(ctx, item); err != nil {
+		log.Printf("Failed to resolve reports for moderation item %s: %v", item.ID.Hex(), err)
+	}
+	return item, nil
+}
+
+// setHidden shows or hides the moderated content
+func (s *ModerationService) setHidden(ctx context.Context, item *models.ModerationItem, hidden bool) error {
+	spec := moderatedContentTypes[item.ContentType]
+	_, err := s.mongoClient.GetCollection(spec.collection).UpdateOne(
+		ctx,
+		bson.M{"_id": item.ContentID},
+		bson.M{"$set": bson.M{"hidden": hidden, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// loadContent returns the owner and moderator-facing text of a piece of content
+func (s *ModerationService) loadContent(ctx context.Context, contentType models.ContentType, contentID primitive.ObjectID) (primitive.ObjectID, string, error) {
+	spec, ok := moderatedContentTypes[contentType]
+	if !ok {
+		return primitive.NilObjectID, "", ErrContentNotFound
+	}
+
+	var content bson.M
+	err := s.mongoClient.GetCollection(spec.collection).FindOne(ctx, bson.M{"_id": contentID}).Decode(&content)
+	if err == mongo.ErrNoDocuments {
+		return primitive.NilObjectID, "", ErrContentNotFound
+	}
+	if err != nil {
+		return primitive.NilObjectID, "", err
+	}
+
+	ownerID, _ := content[spec.ownerField].(primitive.ObjectID)
+	var parts []string
+	for _, field := range spec.textFields {
+		if text, ok := content[field].(string); ok && text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return ownerID, strings.Join(parts, "\n\n"), nil
+}
+
+func (s *ModerationService) find(ctx context.Context, id primitive.ObjectID) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+	err := s.mongoClient.GetCollection("moderation_items").FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrModerationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// findOpen returns an item that still awaits a decision
+func (s *ModerationService) findOpen(ctx context.Context, id primitive.ObjectID) (*models.ModerationItem, error) {
+	item, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !item.Status.Open() {
+		return nil, ErrModerationResolved
+	}
+	return item, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
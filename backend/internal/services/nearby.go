@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"math"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+)
+
+// maxNearbyRings bounds how many rings of match cells around a location are
+// loaded. Reaches spanning more, about 125km at MatchCellResolution, scan
+// every candidate instead.
+const maxNearbyRings = 25
+
+// matchReachKm is the furthest a volunteer can be from a need and still
+// match it: the cutoff, or where the distance score alone falls to the
+// threshold, as similarity and availability are at most 1. It reports false
+// when neither bounds the distance.
+func matchReachKm(decayKm, maxDistanceKm, threshold float64) (float64, bool) {
+	reach, bounded := maxDistanceKm, maxDistanceKm > 0
+	if threshold > 0 && decayKm > 0 {
+		if scoreReach := decayKm * math.Log(1/threshold); !bounded || scoreReach < reach {
+			reach, bounded = scoreReach, true
+		}
+	}
+	return reach, bounded
+}
+
+// nearbyCells returns the match cells holding every point within reachKm of
+// location, or nil when location has no coordinates or the reach spans more
+// than maxNearbyRings
+func nearbyCells(location models.Location, reachKm float64) []string {
+	if location.MatchCell() == "" {
+		return nil
+	}
+	origin := h3.LatLngToCell(h3.LatLng{Lat: location.Latitude, Lng: location.Longitude}, models.MatchCellResolution)
+
+	// A point within reach lies in a cell whose center is at most reach plus
+	// two edges from the origin's center, and ring k of the grid is at least
+	// 1.5k edges out. Measuring by the origin's shortest edge and adding a
+	// ring allows for cells shrinking across the disk.
+	edgeKm := math.Inf(1)
+	for _, edge := range origin.DirectedEdges() {
+		edgeKm = math.Min(edgeKm, h3.EdgeLengthKm(edge))
+	}
+	rings := int(math.Ceil((reachKm+2*edgeKm)/(1.5*edgeKm))) + 1
+	if rings > maxNearbyRings {
+		return nil
+	}
+
+	disk := origin.GridDisk(rings)
+	cells := make([]string, len(disk))
+	for i, cell := range disk {
+		cells[i] = cell.String()
+	}
+	return cells
+}
+
+// nearby returns the filter for volunteers close enough to need to match it
+// under criteria, or nil when they may be anywhere
+func (c needCriteria) nearby(need *models.Need) bson.M {
+	reach, ok := matchReachKm(c.decayKm, c.maxDistanceKm, c.threshold)
+	if !ok {
+		return nil
+	}
+	cells := nearbyCells(need.Location, reach)
+	if cells == nil {
+		return nil
+	}
+	return bson.M{"match_cell": bson.M{"$in": cells}}
+}
+
+// nearbyNeeds returns the filter for needs close enough to a volunteer at
+// location to match them under any category's tunables, stretched by the
+// widest active emergency, or nil when they may be anywhere. Escalated
+// needs reach further still, so they are always included.
+func (m *MatchingService) nearbyNeeds(ctx context.Context, location models.Location, tunables settings.Tunables) (bson.M, error) {
+	emergencies, err := m.emergencies.Active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	multiplier := 1.0
+	for _, emergency := range emergencies {
+		multiplier = math.Max(multiplier, emergency.RadiusMultiplier)
+	}
+
+	// Categories without an override match on the defaults, which the
+	// empty category stands for
+	categories := []models.Category{""}
+	for category := range tunables.Categories {
+		categories = append(categories, category)
+	}
+	var reach float64
+	for _, category := range categories {
+		matching := tunables.ForCategory(category)
+		categoryReach, ok := matchReachKm(matching.DistanceDecayKm, matching.MaxDistanceKm, matching.MatchThreshold)
+		if !ok {
+			return nil, nil
+		}
+		reach = math.Max(reach, categoryReach)
+	}
+
+	cells := nearbyCells(location, reach*multiplier)
+	if cells == nil {
+		return nil, nil
+	}
+	return bson.M{"$or": []bson.M{
+		{"match_cell": bson.M{"$in": cells}},
+		{"sla.radius_multiplier": bson.M{"$gt": 1}},
+	}}, nil
+} 
\ No newline at end of file
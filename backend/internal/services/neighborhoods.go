@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"neighborenexus/internal/models"
+)
+
+// NeighborhoodHealth groups open needs and volunteers into H3 regions at the
+// given resolution and reports where demand outstrips supply, most
+// under-served regions first
+func (m *MatchingService) NeighborhoodHealth(ctx context.Context, resolution int) ([]models.NeighborhoodHealth, error) {
+	needs, err := m.getActiveNeeds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get needs: %w", err)
+	}
+	volunteers, err := m.getActiveVolunteers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volunteers: %w", err)
+	}
+
+	regions := make(map[string]*models.NeighborhoodHealth)
+	region := func(location models.Location) *models.NeighborhoodHealth {
+		cell := m.GenerateH3Index(location.Latitude, location.Longitude, resolution)
+		health, ok := regions[cell]
+		if !ok {
+			health = &models.NeighborhoodHealth{Region: cell, NeedsByCategory: map[string]int{}}
+			regions[cell] = health
+		}
+		return health
+	}
+
+	// Categories volunteers mention in their skills or interests, per region
+	offered := make(map[string]map[string]bool)
+	for _, volunteer := range volunteers {
+		health := region(volunteer.Location)
+		health.Volunteers++
+		if offered[health.Region] == nil {
+			offered[health.Region] = map[string]bool{}
+		}
+		for category := range volunteerCategories(volunteer.Skills, volunteer.Interests) {
+			offered[health.Region][category] = true
+		}
+	}
+
+	unmatched := make(map[string]map[string]bool)
+	for _, need := range needs {
+		health := region(need.Location)
+		health.OpenNeeds++
+		health.NeedsByCategory[string(need.Category)]++
+		if need.Status == models.NeedStatusRequested {
+			health.UnmatchedNeeds++
+			if unmatched[health.Region] == nil {
+				unmatched[health.Region] = map[string]bool{}
+			}
+			unmatched[health.Region][string(need.Category)] = true
+		}
+	}
+
+	results := make([]models.NeighborhoodHealth, 0, len(regions))
+	for cell, health := range regions {
+		health.Gap = health.UnmatchedNeeds - health.Volunteers
+		health.UnmetCategories = []string{}
+		for category := range unmatched[cell] {
+			if !offered[cell][category] {
+				health.UnmetCategories = append(health.UnmetCategories, category)
+			}
+		}
+		sort.Strings(health.UnmetCategories)
+		results = append(results, *health)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Gap != results[j].Gap {
+			return results[i].Gap > results[j].Gap
+		}
+		return results[i].Region < results[j].Region
+	})
+	return results, nil
+}
+
+// volunteerCategories returns the terms in a volunteer's skills and
+// interests, normalized so those naming a category match it
+func volunteerCategories(skills, interests []string) map[string]bool {
+	terms := make(map[string]bool, len(skills)+len(interests))
+	for _, term := range append(append([]string(nil), skills...), interests...) {
+		terms[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(term), " ", "_"))] = true
+	}
+	return terms
+} 
\ No newline at end of file
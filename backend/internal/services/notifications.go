@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/email"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// NotificationService emails users about events on their needs and tasks,
+// skipping the events they have muted. Notifications are best effort, so
+// failures are logged rather than returned.
+type NotificationService struct {
+	mongoClient *database.MongoClient
+	mailer      email.Sender
+	appURL      string
+}
+
+// NewNotificationService creates a notification service. Emails link to
+// pages under appURL, or carry no links when it is empty.
+func NewNotificationService(mongoClient *database.MongoClient, mailer email.Sender, appURL string) *NotificationService {
+	return &NotificationService{
+		mongoClient: mongoClient,
+		mailer:      mailer,
+		appURL:      strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+// UpdatePreferences applies a partial update to a user's notification
+// preferences and returns the result
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID primitive.ObjectID, req models.UpdateNotificationPreferencesRequest) (models.NotificationPreferences, error) {
+	set := bson.M{}
+	if req.MuteNeedAccepted != nil {
+		set["notifications.mute_need_accepted"] = *req.MuteNeedAccepted
+	}
+	if req.MuteTaskCompleted != nil {
+		set["notifications.mute_task_completed"] = *req.MuteTaskCompleted
+	}
+	if req.MuteNewMatches != nil {
+		set["notifications.mute_new_matches"] = *req.MuteNewMatches
+	}
+	if req.MuteNeedExpired != nil {
+		set["notifications.mute_need_expired"] = *req.MuteNeedExpired
+	}
+
+	var user models.User
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.M{"notifications": 1}).
+		SetReturnDocument(options.After)
+	err := s.mongoClient.GetCollection("users").FindOneAndUpdate(ctx, bson.M{"_id": userID}, bson.M{"$set": set}, opts).Decode(&user)
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	return user.Notifications, nil
+}
+
+// NeedAccepted tells a need's creator that a volunteer accepted it
+func (s *NotificationService) NeedAccepted(ctx context.Context, need models.Need, task models.Task, volunteerName string) {
+	users, err := s.recipients(ctx, []primitive.ObjectID{need.UserID})
+	if err != nil {
+		log.Printf("Failed to look up the creator of need %s to notify: %v", need.ID.Hex(), err)
+		return
+	}
+
+	for _, user := range users {
+		if user.Notifications.MuteNeedAccepted {
+			continue
+		}
+		lang := userLanguage(user)
+		s.send(ctx, user, lang,
+			i18n.T(lang, "A volunteer accepted your need"),
+			i18n.Tf(lang, "Hi %s,\n\n%s offered to help with \"%s\". You can coordinate with them in the task chat.",
+				user.Name, volunteerName, sanitize.RedactContacts(need.Title)),
+			"/tasks/"+task.ID.Hex())
+	}
+}
+
+// TaskCompleted tells the need's creator and the volunteer that a task was
+// completed, except for completedBy, who marked it
+func (s *NotificationService) TaskCompleted(ctx context.Context, task models.Task, completedBy primitive.ObjectID) {
+	var need models.Need
+	err := s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1, "title": 1})).Decode(&need)
+	if err != nil {
+		log.Printf("Failed to look up need %s to notify of its completed task: %v", task.NeedID.Hex(), err)
+		return
+	}
+
+	var userIDs []primitive.ObjectID
+	for _, id := range []primitive.ObjectID{need.UserID, task.VolunteerID} {
+		if id != completedBy {
+			userIDs = append(userIDs, id)
+		}
+	}
+	users, err := s.recipients(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to look up users to notify of completed task %s: %v", task.ID.Hex(), err)
+		return
+	}
+
+	for _, user := range users {
+		if user.Notifications.MuteTaskCompleted {
+			continue
+		}
+		lang := userLanguage(user)
+		s.send(ctx, user, lang,
+			i18n.T(lang, "A task was completed"),
+			i18n.Tf(lang, "Hi %s,\n\nThe task for \"%s\" was marked as completed. Thank you for helping your neighborhood!",
+				user.Name, sanitize.RedactContacts(need.Title)),
+			"/tasks/"+task.ID.Hex())
+	}
+}
+
+// NeedExpired tells a need's creator that it expired before anyone took it
+// on, and that they can extend it
+func (s *NotificationService) NeedExpired(ctx context.Context, need models.Need) {
+	users, err := s.recipients(ctx, []primitive.ObjectID{need.UserID})
+	if err != nil {
+		log.Printf("Failed to look up the creator of need %s to notify: %v", need.ID.Hex(), err)
+		return
+	}
+
+	for _, user := range users {
+		if user.Notifications.MuteNeedExpired {
+			continue
+		}
+		lang := userLanguage(user)
+		s.send(ctx, user, lang,
+			i18n.T(lang, "Your need expired"),
+			i18n.Tf(lang, "Hi %s,\n\nNobody took on \"%s\" before it expired, so volunteers no longer see it. If you still need help, you can extend it by a week from the need's page.",
+				user.Name, sanitize.RedactContacts(need.Title)),
+			"/needs/"+need.ID.Hex())
+	}
+}
+
+// NewMatches tells the volunteers matched to a need about it, with its
+// distance in their preferred units
+func (s *NotificationService) NewMatches(ctx context.Context, need models.Need, recipients []NeedRecipient) {
+	byUser := make(map[primitive.ObjectID]NeedRecipient, len(recipients))
+	userIDs := make([]primitive.ObjectID, 0, len(recipients))
+	for _, recipient := range recipients {
+		id, err := primitive.ObjectIDFromHex(recipient.UserID)
+		if err != nil {
+			continue
+		}
+		byUser[id] = recipient
+		userIDs = append(userIDs, id)
+	}
+
+	users, err := s.recipients(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to look up volunteers to email about need %s: %v", need.ID.Hex(), err)
+		return
+	}
+
+	for _, user := range users {
+		if user.Notifications.MuteNewMatches {
+			continue
+		}
+		recipient := byUser[user.ID]
+		lang := userLanguage(user)
+		distance := LocalizeDistance(recipient.Distance, recipient.Units, models.Language(lang))
+		s.send(ctx, user, lang,
+			i18n.T(lang, "A neighbor near you needs help"),
+			i18n.Tf(lang, "Hi %s,\n\nA neighbor %s away needs help with \"%s\", and your volunteer profile is a good match.",
+				user.Name, distance.Text, sanitize.RedactContacts(need.Title)),
+			"/needs/"+need.ID.Hex())
+	}
+}
+
+// recipients loads the users to email, skipping hidden accounts
+func (s *NotificationService) recipients(ctx context.Context, userIDs []primitive.ObjectID) ([]models.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := s.mongoClient.GetCollection("users").Find(ctx,
+		bson.M{"_id": bson.M{"$in": userIDs}, "hidden": bson.M{"$ne": true}},
+		options.Find().SetProjection(bson.M{"email": 1, "name": 1, "language": 1, "notifications": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// send emails user, appending a link to path in the app when there is one
+// and a pointer to the preferences that control these emails
+func (s *NotificationService) send(ctx context.Context, user models.User, lang, subject, body, path string) {
+	if user.Email == "" {
+		return
+	}
+	if s.appURL != "" {
+		body += "\n\n" + s.appURL + path
+	}
+	body += "\n\n" + i18n.T(lang, "You can choose which emails you get in your notification settings.")
+
+	if err := s.mailer.Send(ctx, email.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+		log.Printf("Failed to email user %s: %v", user.ID.Hex(), err)
+	}
+}
+
+// userLanguage is the language to write to user in
+func userLanguage(user models.User) string {
+	if user.Language == "" {
+		return i18n.Default
+	}
+	return string(user.Language)
+} 
\ No newline at end of file
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/email"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+)
+
+// oauthStateLifetime is how long a user has to sign in with a provider
+// after starting
+const oauthStateLifetime = 10 * time.Minute
+
+var (
+	// ErrOAuthProviderUnavailable is returned for a provider that is unknown
+	// or not configured
+	ErrOAuthProviderUnavailable = errors.New("sign-in provider is not available")
+	// ErrInvalidOAuthState is returned for a callback that was not started
+	// here, has expired, or was started for another provider
+	ErrInvalidOAuthState = errors.New("sign-in link is invalid or has expired; start again")
+	// ErrOAuthEmailUnverified is returned when the provider has not verified
+	// the account's email address, which accounts are linked and created by
+	ErrOAuthEmailUnverified = errors.New("the provider has not verified this account's email address")
+	// ErrOAuthSignupIncomplete is returned when signing in would create an
+	// account but no date of birth was given when starting
+	ErrOAuthSignupIncomplete = errors.New("date_of_birth is required to create an account; start again with it")
+)
+
+// oauthState is what the state parameter carries through a provider's
+// sign-in page. It is signed, so the callback can trust it.
+type oauthState struct {
+	Provider    models.OAuthProvider
+	Nonce       string
+	DateOfBirth *time.Time
+	Language    models.Language
+}
+
+// StartOAuth returns the provider's sign-in page to send the user to, and
+// the state the callback must return. A date of birth (YYYY-MM-DD) lets the
+// callback create an account when none exists for the user.
+func (a *AuthService) StartOAuth(providerName models.OAuthProvider, dateOfBirth string, language models.Language) (string, string, error) {
+	provider, ok := a.providers[providerName]
+	if !ok {
+		return "", "", ErrOAuthProviderUnavailable
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(raw)
+
+	claims := jwt.MapClaims{
+		"type":     "oauth_state",
+		"provider": string(providerName),
+		"nonce":    nonce,
+		"language": string(language),
+		"exp":      time.Now().Add(oauthStateLifetime).Unix(),
+	}
+	if dateOfBirth != "" {
+		if _, err := ParseDateOfBirth(dateOfBirth, time.Now()); err != nil {
+			return "", "", err
+		}
+		claims["date_of_birth"] = dateOfBirth
+	}
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(a.jwtSecret))
+	if err != nil {
+		return "", "", err
+	}
+	return provider.AuthCodeURL(state, nonce), state, nil
+}
+
+// OAuthLogin completes signing in with a provider: it redeems the code,
+// finds the user by the provider account, or else by its verified email,
+// matched case-insensitively, to link it, or else creates an account, and
+// starts a session. It reports
+// whether the account was created. name is used for new accounts when the
+// provider sends it outside the ID token, as Apple does.
+func (a *AuthService) OAuthLogin(ctx context.Context, providerName models.OAuthProvider, code, state, name string, client models.SessionClient) (*models.AuthResponse, bool, error) {
+	provider, ok := a.providers[providerName]
+	if !ok {
+		return nil, false, ErrOAuthProviderUnavailable
+	}
+	started, err := a.parseOAuthState(state)
+	if err != nil || started.Provider != providerName {
+		return nil, false, ErrInvalidOAuthState
+	}
+
+	identity, err := provider.Exchange(ctx, code, started.Nonce)
+	if err != nil {
+		return nil, false, err
+	}
+
+	collection := a.mongoClient.GetCollection("users")
+	var user models.User
+	created := false
+	err = collection.FindOne(ctx, bson.M{"linked_accounts": bson.M{"$elemMatch": bson.M{"provider": providerName, "subject": identity.Subject}}}).Decode(&user)
+	switch {
+	case err == nil:
+	case err != mongo.ErrNoDocuments:
+		return nil, false, err
+	case identity.Email == "" || !identity.EmailVerified:
+		return nil, false, ErrOAuthEmailUnverified
+	default:
+		address := normalizeEmail(identity.Email)
+		linked := models.LinkedAccount{Provider: providerName, Subject: identity.Subject, LinkedAt: time.Now()}
+		user, err = a.linkOAuthAccount(ctx, address, linked)
+		if err == mongo.ErrNoDocuments {
+			if started.DateOfBirth == nil {
+				return nil, false, ErrOAuthSignupIncomplete
+			}
+			if identity.Name != "" {
+				name = identity.Name
+			}
+			user, err = a.createOAuthUser(ctx, address, name, started, linked)
+			created = true
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if user.AccountSuspended(time.Now()) {
+		return nil, false, ErrAccountSuspended
+	}
+	response, err := a.startSession(ctx, user, client)
+	return response, created, err
+}
+
+// linkOAuthAccount links a provider account to the user registered with
+// its normalized email address. An account still waiting to verify its
+// address may have been registered by someone else to wait for the owner,
+// so linking hands it to the provider account: its password is cleared and
+// its sessions ended, the owner is emailed, and they set a new password by
+// resetting it.
+func (a *AuthService) linkOAuthAccount(ctx context.Context, address string, linked models.LinkedAccount) (models.User, error) {
+	collection := a.mongoClient.GetCollection("users")
+	var user models.User
+	if err := collection.FindOne(ctx, bson.M{"email": address}).Decode(&user); err != nil {
+		return models.User{}, err
+	}
+
+	now := time.Now()
+	update := bson.M{"$push": bson.M{"linked_accounts": linked}}
+	set := bson.M{"updated_at": now}
+	if user.EmailVerifiedAt == nil {
+		set["email_verified_at"] = now
+	}
+	takeover := user.EmailPending
+	if takeover {
+		set["password"] = ""
+		update["$unset"] = bson.M{"email_pending": ""}
+	}
+	update["$set"] = set
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": user.ID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&user)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if takeover {
+		if _, err := a.sessions.RevokeAll(ctx, user.ID.Hex(), ""); err != nil {
+			return models.User{}, err
+		}
+		if err := a.mailer.Send(ctx, takeoverEmail(user, linked.Provider)); err != nil {
+			log.Printf("Failed to email %s about linking %s: %v", user.ID.Hex(), linked.Provider, err)
+		}
+	}
+	return user, nil
+}
+
+// takeoverEmail tells the owner of an address, in their language, that
+// signing in with provider took over the account registered with it
+func takeoverEmail(user models.User, provider models.OAuthProvider) email.Message {
+	lang := userLanguage(user)
+	return email.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "Your NeighborNexus account is now linked"),
+		Body: i18n.Tf(lang, "Hi %s,\n\nYou signed in to NeighborNexus with %s, so the account registered with this email address is now yours. It had not verified the address, so its password was removed and everyone signed in to it was signed out.\n\nIf you did not register this account, someone else did; check its profile and posts. To sign in with a password as well, reset it from the sign-in page.",
+			user.Name, provider.DisplayName()),
+	}
+}
+
+// createOAuthUser creates an account signed into with a provider account.
+// It has no password until the user sets one by resetting it.
+func (a *AuthService) createOAuthUser(ctx context.Context, email, name string, started oauthState, linked models.LinkedAccount) (models.User, error) {
+	now := time.Now()
+	if name = strings.TrimSpace(name); name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	user := models.User{
+		ID:              primitive.NewObjectID(),
+		Email:           email,
+		Name:            name,
+		Role:            models.RoleUser,
+		DateOfBirth:     started.DateOfBirth,
+		Language:        started.Language,
+		EmailVerifiedAt: &now,
+		LinkedAccounts:  []models.LinkedAccount{linked},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if _, err := a.mongoClient.GetCollection("users").InsertOne(ctx, user); err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// parseOAuthState validates a state token and returns what it carries
+func (a *AuthService) parseOAuthState(state string) (oauthState, error) {
+	token, err := jwt.Parse(state, func(token *jwt.Token) (interface{}, error) {
+		return []byte(a.jwtSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+	if err != nil || !token.Valid {
+		return oauthState{}, ErrInvalidOAuthState
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return oauthState{}, ErrInvalidOAuthState
+	}
+
+	tokenType, _ := claims["type"].(string)
+	provider, _ := claims["provider"].(string)
+	nonce, _ := claims["nonce"].(string)
+	language, _ := claims["language"].(string)
+	if tokenType != "oauth_state" || nonce == "" {
+		return oauthState{}, ErrInvalidOAuthState
+	}
+	started := oauthState{Provider: models.OAuthProvider(provider), Nonce: nonce, Language: models.Language(language)}
+	if value, _ := claims["date_of_birth"].(string); value != "" {
+		dateOfBirth, err := time.Parse(models.DateOfBirthLayout, value)
+		if err != nil {
+			return oauthState{}, ErrInvalidOAuthState
+		}
+		started.DateOfBirth = &dateOfBirth
+	}
+	return started, nil
+} 
\ No newline at end of file
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+var (
+	// ErrOfferNotFound is returned for an offer that does not exist, is
+	// hidden or closed, or belongs to someone else when the owner is required
+	ErrOfferNotFound = errors.New("offer not found")
+	// ErrOfferNotActive is returned when accepting a paused offer
+	ErrOfferNotActive = errors.New("offer is not taking new needs")
+	// ErrOwnOffer is returned when someone accepts their own offer
+	ErrOwnOffer = errors.New("you cannot accept your own offer")
+	// ErrOffererIneligible is returned when minor-safety rules keep the
+	// offerer away from the need's category
+	ErrOffererIneligible = errors.New("this offer cannot take on needs in that category")
+)
+
+// OfferService manages volunteers' standing offers and their acceptance
+type OfferService struct {
+	mongoClient    *database.MongoClient
+	privacyService *PrivacyService
+}
+
+// NewOfferService creates a new offer service
+func NewOfferService(mongoClient *database.MongoClient, privacyService *PrivacyService) *OfferService {
+	return &OfferService{
+		mongoClient:    mongoClient,
+		privacyService: privacyService,
+	}
+}
+
+// Create posts a standing offer at location, which the caller has already
+// indexed for the offerer's privacy settings
+func (s *OfferService) Create(ctx context.Context, offerer *models.User, req models.CreateOfferRequest, location models.Location) (*models.Offer, error) {
+	now := time.Now()
+	offer := models.Offer{
+		ID:           primitive.NewObjectID(),
+		UserID:       offerer.ID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Category:     req.Category,
+		Schedule:     req.Schedule,
+		Location:     location,
+		Neighborhood: s.privacyService.NeighborhoodCell(location),
+		Status:       models.OfferActive,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if _, err := s.mongoClient.GetCollection("offers").InsertOne(ctx, offer); err != nil {
+		return nil, err
+	}
+	offer.OffererName = offerer.DisplayName()
+	return &offer, nil
+}
+
+// Browse lists active offers, newest first, optionally limited to a
+// category and a neighborhood cell
+func (s *OfferService) Browse(ctx context.Context, viewerID primitive.ObjectID, category models.Category, neighborhood string, limit, offset int64) ([]models.Offer, error) {
+	filter := bson.M{"status": models.OfferActive, "hidden": bson.M{"$ne": true}}
+	if category != "" {
+		filter["category"] = category
+	}
+	if neighborhood != "" {
+		filter["neighborhood"] = neighborhood
+	}
+	return s.find(ctx, viewerID, filter, limit, offset)
+}
+
+// Mine lists a user's own offers, including paused and closed ones, newest
+// first
+func (s *OfferService) Mine(ctx context.Context, userID primitive.ObjectID, limit, offset int64) ([]models.Offer, error) {
+	return s.find(ctx, userID, bson.M{"user_id": userID}, limit, offset)
+}
+
+// Get returns an offer visible to viewerID: any of their own, or someone
+// else's that is neither hidden nor closed
+func (s *OfferService) Get(ctx context.Context, offerID, viewerID primitive.ObjectID) (*models.Offer, error) {
+	var offer models.Offer
+	err := s.mongoClient.GetCollection("offers").FindOne(ctx, bson.M{
+		"_id": offerID,
+		"$or": []bson.M{
+			{"user_id": viewerID},
+			{"hidden": bson.M{"$ne": true}, "status": bson.M{"$ne": models.OfferClosed}},
+		},
+	}).Decode(&offer)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrOfferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	offers := []models.Offer{offer}
+	if err := s.shape(ctx, viewerID, offers); err != nil {
+		return nil, err
+	}
+	return &offers[0], nil
+}
+
+// Update changes one of the user's offers. A new location must already be
+// indexed for the offerer's privacy settings.
+func (s *OfferService) Update(ctx context.Context, offerID, userID primitive.ObjectID, req models.UpdateOfferRequest) (*models.Offer, error) {
+	set := bson.M{"updated_at": time.Now()}
+	if req.Title != nil {
+		set["title"] = *req.Title
+	}
+	if req.Description != nil {
+		set["description"] = *req.Description
+	}
+	if req.Category != "" {
+		set["category"] = req.Category
+	}
+	if req.Schedule != nil {
+		set["schedule"] = *req.Schedule
+	}
+	if req.Location != nil {
+		set["location"] = *req.Location
+		set["neighborhood"] = s.privacyService.NeighborhoodCell(*req.Location)
+	}
+	if req.Status != "" {
+		set["status"] = req.Status
+	}
+
+	var offer models.Offer
+	err := s.mongoClient.GetCollection("offers").FindOneAndUpdate(ctx,
+		bson.M{"_id": offerID, "user_id": userID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&offer)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrOfferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// Close withdraws one of the user's offers. Closed offers are kept so the
+// tasks created from them can still be traced back.
+func (s *OfferService) Close(ctx context.Context, offerID, userID primitive.ObjectID) error {
+	result, err := s.mongoClient.GetCollection("offers").UpdateOne(ctx,
+		bson.M{"_id": offerID, "user_id": userID, "status": bson.M{"$ne": models.OfferClosed}},
+		bson.M{"$set": bson.M{"status": models.OfferClosed, "updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrOfferNotFound
+	}
+	return nil
+}
+
+// Accept takes up an active offer for one of requester's open needs,
+// creating a task with the offerer as volunteer and marking the need
+// matched
+func (s *OfferService) Accept(ctx context.Context, offerID primitive.ObjectID, requester *models.User, needID primitive.ObjectID) (*models.Offer, *models.Need, *models.Task, error) {
+	offer, err := s.Get(ctx, offerID, requester.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	switch {
+	case offer.UserID == requester.ID:
+		return nil, nil, nil, ErrOwnOffer
+	case offer.Status != models.OfferActive:
+		return nil, nil, nil, ErrOfferNotActive
+	}
+
+	needs := s.mongoClient.GetCollection("needs")
+	var need models.Need
+	if err := needs.FindOne(ctx, bson.M{"_id": needID}).Decode(&need); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, nil, ErrNeedNotFound
+		}
+		return nil, nil, nil, err
+	}
+	switch {
+	case need.UserID != requester.ID:
+		return nil, nil, nil, ErrNotNeedOwner
+	case need.Status != models.NeedStatusRequested || need.Hidden || need.HeldForReview:
+		return nil, nil, nil, ErrNeedClosed
+	}
+
+	// Minor-safety rules apply to the offerer, who takes on the task
+	var offerer models.User
+	if err := s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": offer.UserID}).Decode(&offerer); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, nil, ErrOfferNotFound
+		}
+		return nil, nil, nil, err
+	}
+	now := time.Now()
+	if CheckTaskEligibility(&offerer, need.Category, now) != nil {
+		return nil, nil, nil, ErrOffererIneligible
+	}
+
+	// Claim the need first so a volunteer accepting it at the same time
+	// cannot also take it on
+	result, err := needs.UpdateOne(ctx,
+		bson.M{"_id": need.ID, "status": models.NeedStatusRequested},
+		bson.M{"$set": bson.M{"status": models.NeedStatusMatched, "updated_at": now}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil, nil, ErrNeedClosed
+	}
+	need.Status = models.NeedStatusMatched
+
+	task := models.Task{
+		ID:          primitive.NewObjectID(),
+		NeedID:      need.ID,
+		VolunteerID: offer.UserID,
+		OfferID:     &offer.ID,
+		Status:      models.TaskStatusAccepted,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := s.mongoClient.GetCollection("tasks").InsertOne(ctx, task); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if _, err := s.mongoClient.GetCollection("offers").UpdateOne(ctx, bson.M{"_id": offer.ID}, bson.M{"$inc": bson.M{"accepted": 1}}); err != nil {
+		return nil, nil, nil, err
+	}
+	offer.Accepted++
+	return offer, &need, &task, nil
+}
+
+// find returns the offers matching filter, newest first, shaped for viewerID
+func (s *OfferService) find(ctx context.Context, viewerID primitive.ObjectID, filter bson.M, limit, offset int64) ([]models.Offer, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit).
+		SetProjection(bson.M{"embedding": 0})
+	cursor, err := s.mongoClient.GetCollection("offers").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	offers := []models.Offer{}
+	if err := cursor.All(ctx, &offers); err != nil {
+		return nil, err
+	}
+	if err := s.shape(ctx, viewerID, offers); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// shape fills in the offerers' display names and coarsens the locations of
+// offers that are not the viewer's own
+func (s *OfferService) shape(ctx context.Context, viewerID primitive.ObjectID, offers []models.Offer) error {
+	if len(offers) == 0 {
+		return nil
+	}
+	offererIDs := make([]primitive.ObjectID, len(offers))
+	for i, offer := range offers {
+		offererIDs[i] = offer.UserID
+	}
+
+	cursor, err := s.mongoClient.GetCollection("users").Find(ctx, bson.M{"_id": bson.M{"$in": offererIDs}},
+		options.Find().SetProjection(bson.M{"name": 1, "privacy": 1}))
+	if err != nil {
+		return err
+	}
+	var offerers []models.User
+	if err := cursor.All(ctx, &offerers); err != nil {
+		return err
+	}
+	names := make(map[primitive.ObjectID]string, len(offerers))
+	for i := range offerers {
+		names[offerers[i].ID] = offerers[i].DisplayName()
+	}
+
+	for i := range offers {
+		offers[i].OffererName = names[offers[i].UserID]
+		if offers[i].UserID != viewerID {
+			offers[i].Location = s.privacyService.ApproximateLocation(offers[i].Location)
+		}
+	}
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"github.com/sashabaranov/go-openai"
+	"neighborenexus/internal/telemetry"
+)
+
+// newOpenAIClient returns an OpenAI client whose requests are traced
+func newOpenAIClient(apiKey string) *openai.Client {
+	config := openai.DefaultConfig(apiKey)
+	config.HTTPClient = telemetry.HTTPClient()
+	return openai.NewClientWithConfig(config)
+} 
\ No newline at end of file
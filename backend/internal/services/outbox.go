@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// outboxLease is how long a claimed event is left to its worker before
+// another worker may retry it
+const outboxLease = 5 * time.Minute
+
+// outboxMaxAttempts is how many times an event is tried before it is left
+// in the outbox, with its last error, for an operator to look at
+const outboxMaxAttempts = 5
+
+// Outbox queues work that must follow a change to the database. Events are
+// stored alongside the data rather than in Redis, so a change is never lost
+// between writing it and queueing its follow-up work.
+type Outbox struct {
+	mongoClient *database.MongoClient
+}
+
+// NewOutbox creates a new outbox
+func NewOutbox(mongoClient *database.MongoClient) *Outbox {
+	return &Outbox{mongoClient: mongoClient}
+}
+
+// Publish records an event on topic for each subject, coalescing it with
+// any event for the same subject not yet claimed
+func (o *Outbox) Publish(ctx context.Context, topic models.OutboxTopic, subjectIDs ...primitive.ObjectID) error {
+	collection := o.mongoClient.GetCollection("outbox")
+	for _, subjectID := range subjectIDs {
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"topic": topic, "subject_id": subjectID, "claimed_at": bson.M{"$exists": false}},
+			bson.M{"$setOnInsert": bson.M{"attempts": 0, "created_at": time.Now()}},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Process claims topic's events one at a time, oldest first, and hands each
+// subject to handle, returning how many were handled. Handled events are
+// removed. A failed event keeps its error and is retried once its lease
+// runs out; the failure also ends the pass.
+func (o *Outbox) Process(ctx context.Context, topic models.OutboxTopic, handle func(ctx context.Context, subjectID primitive.ObjectID) error) (int, error) {
+	collection := o.mongoClient.GetCollection("outbox")
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	handled := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+		var event models.OutboxEvent
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"topic":    topic,
+				"attempts": bson.M{"$lt": outboxMaxAttempts},
+				"$or": []bson.M{
+					{"claimed_at": bson.M{"$exists": false}},
+					{"claimed_at": bson.M{"$lt": now.Add(-outboxLease)}},
+				},
+			},
+			bson.M{"$set": bson.M{"claimed_at": now}, "$inc": bson.M{"attempts": 1}},
+			opts).Decode(&event)
+		if err == mongo.ErrNoDocuments {
+			return handled, nil
+		}
+		if err != nil {
+			return handled, err
+		}
+
+		if err := handle(ctx, event.SubjectID); err != nil {
+			if _, updateErr := collection.UpdateOne(ctx, bson.M{"_id": event.ID},
+				bson.M{"$set": bson.M{"last_error": err.Error()}}); updateErr != nil {
+				return handled, updateErr
+			}
+			return handled, err
+		}
+		if _, err := collection.DeleteOne(ctx, bson.M{"_id": event.ID}); err != nil {
+			return handled, err
+		}
+		handled++
+	}
+	return handled, ctx.Err()
+} 
\ No newline at end of file
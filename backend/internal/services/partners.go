@@ -0,0 +1,495 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// partnerKeyPrefix starts every partner API key, so a leaked key is easy to
+// recognize in logs and by secret scanners
+const partnerKeyPrefix = "nnp_"
+
+// partnerKeyShown is how much of an API key, prefix included, is kept in
+// the clear to tell an organization's keys apart
+const partnerKeyShown = 12
+
+// referralLifetime is how long a referred need stays open, the same as a
+// need posted in the app
+const referralLifetime = 7 * 24 * time.Hour
+
+var (
+	// ErrPartnerNotFound is returned for a partner organization that does
+	// not exist or has been revoked
+	ErrPartnerNotFound = errors.New("partner organization not found")
+	// ErrInvalidPartnerKey is returned for an API key that is unknown or
+	// belongs to a revoked organization
+	ErrInvalidPartnerKey = errors.New("invalid or revoked API key")
+	// ErrInvalidWebhookURL is returned for a webhook URL that is not an
+	// absolute http or https URL
+	ErrInvalidWebhookURL = errors.New("webhook URL must be an absolute http or https URL")
+	// ErrReferralNotFound is returned for an external ID the organization
+	// has not referred
+	ErrReferralNotFound = errors.New("referral not found")
+	// ErrReferralClosed is returned when cancelling a referral that has
+	// already been completed, cancelled, or has expired
+	ErrReferralClosed = errors.New("referral is no longer open")
+)
+
+// PartnerService manages partner organizations and the needs they refer on
+// behalf of clients without accounts
+type PartnerService struct {
+	mongoClient    *database.MongoClient
+	privacyService *PrivacyService
+	tasks          *TaskService
+	categories     *CategoryService
+}
+
+// NewPartnerService creates a new partner service. Tasks on cancelled
+// referrals are cancelled through taskService, and their categories are
+// checked against categoryService's taxonomy.
+func NewPartnerService(mongoClient *database.MongoClient, privacyService *PrivacyService, taskService *TaskService, categoryService *CategoryService) *PartnerService {
+	return &PartnerService{
+		mongoClient:    mongoClient,
+		privacyService: privacyService,
+		tasks:          taskService,
+		categories:     categoryService,
+	}
+}
+
+// CreateOrganization registers a partner organization, returning its API
+// key and webhook secret, which are not shown again
+func (s *PartnerService) CreateOrganization(ctx context.Context, createdBy primitive.ObjectID, req models.CreatePartnerRequest) (*models.PartnerCredentials, error) {
+	if err := checkWebhookURL(req.WebhookURL); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	org := models.PartnerOrganization{
+		ID:           primitive.NewObjectID(),
+		Name:         req.Name,
+		ContactEmail: req.ContactEmail,
+		ContactPhone: models.EncryptedString(req.ContactPhone),
+		WebhookURL:   req.WebhookURL,
+		CreatedBy:    createdBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	apiKey, webhookSecret, err := newPartnerCredentials(&org)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.mongoClient.GetCollection("partner_organizations").InsertOne(ctx, org); err != nil {
+		return nil, err
+	}
+	return &models.PartnerCredentials{Organization: &org, APIKey: apiKey, WebhookSecret: webhookSecret}, nil
+}
+
+// ListOrganizations returns partner organizations by name, including
+// revoked ones when includeRevoked is set
+func (s *PartnerService) ListOrganizations(ctx context.Context, includeRevoked bool, limit, offset int64) ([]models.PartnerOrganization, error) {
+	filter := bson.M{}
+	if !includeRevoked {
+		filter["revoked_at"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("partner_organizations").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	orgs := []models.PartnerOrganization{}
+	if err := cursor.All(ctx, &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// Organization returns an active partner organization
+func (s *PartnerService) Organization(ctx context.Context, orgID primitive.ObjectID) (*models.PartnerOrganization, error) {
+	var org models.PartnerOrganization
+	err := s.mongoClient.GetCollection("partner_organizations").FindOne(ctx,
+		bson.M{"_id": orgID, "revoked_at": bson.M{"$exists": false}}).Decode(&org)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPartnerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// UpdateOrganization changes an active partner organization's details
+func (s *PartnerService) UpdateOrganization(ctx context.Context, orgID primitive.ObjectID, req models.UpdatePartnerRequest) (*models.PartnerOrganization, error) {
+	set := bson.M{"updated_at": time.Now()}
+	if req.Name != nil {
+		set["name"] = *req.Name
+	}
+	if req.ContactEmail != nil {
+		set["contact_email"] = *req.ContactEmail
+	}
+	if req.ContactPhone != nil {
+		set["contact_phone"] = models.EncryptedString(*req.ContactPhone)
+	}
+	if req.WebhookURL != nil {
+		if err := checkWebhookURL(*req.WebhookURL); err != nil {
+			return nil, err
+		}
+		set["webhook_url"] = *req.WebhookURL
+	}
+	return s.updateOrganization(ctx, orgID, set)
+}
+
+// RotateCredentials issues an organization a new API key and webhook
+// secret, which take effect at once
+func (s *PartnerService) RotateCredentials(ctx context.Context, orgID primitive.ObjectID) (*models.PartnerCredentials, error) {
+	var org models.PartnerOrganization
+	apiKey, webhookSecret, err := newPartnerCredentials(&org)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.updateOrganization(ctx, orgID, bson.M{
+		"key_prefix":     org.KeyPrefix,
+		"key_hash":       org.KeyHash,
+		"webhook_secret": org.WebhookSecret,
+		"updated_at":     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.PartnerCredentials{Organization: updated, APIKey: apiKey, WebhookSecret: webhookSecret}, nil
+}
+
+// RevokeOrganization turns off an organization's API key and status
+// events. Needs it already referred stay open.
+func (s *PartnerService) RevokeOrganization(ctx context.Context, orgID, revokedBy primitive.ObjectID) error {
+	now := time.Now()
+	_, err := s.updateOrganization(ctx, orgID, bson.M{"revoked_at": now, "revoked_by": revokedBy, "updated_at": now})
+	return err
+}
+
+// updateOrganization applies set to an active organization
+func (s *PartnerService) updateOrganization(ctx context.Context, orgID primitive.ObjectID, set bson.M) (*models.PartnerOrganization, error) {
+	var org models.PartnerOrganization
+	err := s.mongoClient.GetCollection("partner_organizations").FindOneAndUpdate(ctx,
+		bson.M{"_id": orgID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&org)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPartnerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// Authenticate returns the active organization holding apiKey
+func (s *PartnerService) Authenticate(ctx context.Context, apiKey string) (*models.PartnerOrganization, error) {
+	var org models.PartnerOrganization
+	err := s.mongoClient.GetCollection("partner_organizations").FindOne(ctx, bson.M{
+		"key_hash":   hashPartnerKey(apiKey),
+		"revoked_at": bson.M{"$exists": false},
+	}).Decode(&org)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidPartnerKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// Refer creates a need on behalf of an organization's client, held for
+// review when hold is set because the text was flagged. A referral whose
+// external ID the organization already used is returned as it stands,
+// with created false.
+func (s *PartnerService) Refer(ctx context.Context, org *models.PartnerOrganization, req models.CreateReferralRequest, hold bool) (*models.Need, bool, error) {
+	if existing, err := s.referredNeed(ctx, org.ID, req.ExternalID); !errors.Is(err, ErrReferralNotFound) {
+		return existing, false, err
+	}
+	if err := s.categories.CheckNeed(ctx, req.Category, ""); err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(referralLifetime)
+	need := models.Need{
+		ID:          primitive.NewObjectID(),
+		UserID:      org.ID,
+		Title:       req.Title,
+		Description: req.Description,
+		Category:    req.Category,
+		Urgency:     req.Urgency,
+		Duration:    req.Duration,
+		// Clients have no privacy settings of their own, so the defaults apply
+		Location:      s.privacyService.IndexLocation(req.Location, models.PrivacySettings{}),
+		Status:        models.NeedStatusRequested,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ExpiresAt:     &expiresAt,
+		HeldForReview: hold,
+		Partner: &models.PartnerReferral{
+			OrganizationID: org.ID,
+			ExternalID:     req.ExternalID,
+			ClientName:     models.EncryptedString(req.ClientName),
+			ClientPhone:    models.EncryptedString(req.ClientPhone),
+		},
+	}
+	need.Point = s.privacyService.LocationPoint(need.Location)
+	need.MatchCell = need.Location.MatchCell()
+	_, err := s.mongoClient.GetCollection("needs").InsertOne(ctx, need)
+	if mongo.IsDuplicateKeyError(err) {
+		// The same referral was sent twice at once
+		existing, err := s.referredNeed(ctx, org.ID, req.ExternalID)
+		return existing, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &need, true, nil
+}
+
+// Referral returns the state of one of an organization's referrals
+func (s *PartnerService) Referral(ctx context.Context, orgID primitive.ObjectID, externalID string) (*models.ReferralState, error) {
+	need, err := s.referredNeed(ctx, orgID, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.State(ctx, need)
+}
+
+// CancelReferral withdraws one of an organization's open referrals and any
+// tasks still working on it
+func (s *PartnerService) CancelReferral(ctx context.Context, orgID primitive.ObjectID, externalID string) (*models.ReferralState, error) {
+	now := time.Now()
+	var need models.Need
+	err := s.mongoClient.GetCollection("needs").FindOneAndUpdate(ctx,
+		bson.M{
+			"partner.organization_id": orgID,
+			"partner.external_id":     externalID,
+			"status":                  bson.M{"$in": []models.NeedStatus{models.NeedStatusRequested, models.NeedStatusMatched}},
+		},
+		bson.M{"$set": bson.M{"status": models.NeedStatusCancelled, "updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		if _, err := s.referredNeed(ctx, orgID, externalID); err != nil {
+			return nil, err
+		}
+		return nil, ErrReferralClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tasks.CancelForNeed(ctx, need.ID); err != nil {
+		return nil, err
+	}
+	return s.State(ctx, &need)
+}
+
+// State works out a referred need's status from the need and its most
+// recent task that was not cancelled
+func (s *PartnerService) State(ctx context.Context, need *models.Need) (*models.ReferralState, error) {
+	tasks, err := s.liveTasks(ctx, []primitive.ObjectID{need.ID})
+	if err != nil {
+		return nil, err
+	}
+	state := referralState(need, tasks[need.ID], time.Now())
+	return &state, nil
+}
+
+// ReferralReport is a referral whose status has changed since it was last
+// reported to its organization
+type ReferralReport struct {
+	Need  models.Need
+	State models.ReferralState
+}
+
+// DueReports returns the referrals whose status has changed since it was
+// last reported, skipping those already reported in a final status
+func (s *PartnerService) DueReports(ctx context.Context) ([]ReferralReport, error) {
+	cursor, err := s.mongoClient.GetCollection("needs").Find(ctx, bson.M{
+		"partner.organization_id": bson.M{"$exists": true},
+		"partner.final":           bson.M{"$ne": true},
+	}, options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var needs []models.Need
+	if err := cursor.All(ctx, &needs); err != nil {
+		return nil, err
+	}
+	if len(needs) == 0 {
+		return nil, nil
+	}
+
+	needIDs := make([]primitive.ObjectID, len(needs))
+	for i, need := range needs {
+		needIDs[i] = need.ID
+	}
+	tasks, err := s.liveTasks(ctx, needIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var due []ReferralReport
+	for i := range needs {
+		state := referralState(&needs[i], tasks[needs[i].ID], now)
+		if state.Status != needs[i].Partner.ReportedStatus {
+			due = append(due, ReferralReport{Need: needs[i], State: state})
+		}
+	}
+	return due, nil
+}
+
+// ClaimReport records that report is being sent, returning false when
+// another worker already claimed it. Referrals whose task was completed are
+// completed too, since there is no account holder to close them.
+func (s *PartnerService) ClaimReport(ctx context.Context, report ReferralReport) (bool, error) {
+	filter := bson.M{"_id": report.Need.ID}
+	if report.Need.Partner.ReportedStatus == "" {
+		filter["partner.reported_status"] = bson.M{"$exists": false}
+	} else {
+		filter["partner.reported_status"] = report.Need.Partner.ReportedStatus
+	}
+	set := bson.M{"partner.reported_status": report.State.Status}
+	if report.State.Status.Final() {
+		set["partner.final"] = true
+	}
+	if report.State.Status == models.ReferralCompleted && report.Need.Status != models.NeedStatusCompleted {
+		set["status"] = models.NeedStatusCompleted
+		set["updated_at"] = time.Now()
+	}
+
+	result, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// referredNeed returns the need an organization referred with externalID
+func (s *PartnerService) referredNeed(ctx context.Context, orgID primitive.ObjectID, externalID string) (*models.Need, error) {
+	var need models.Need
+	err := s.mongoClient.GetCollection("needs").FindOne(ctx,
+		bson.M{"partner.organization_id": orgID, "partner.external_id": externalID},
+		options.FindOne().SetProjection(bson.M{"embedding": 0}),
+	).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrReferralNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &need, nil
+}
+
+// liveTasks returns the most recently updated task that was not cancelled
+// for each of needIDs that has one
+func (s *PartnerService) liveTasks(ctx context.Context, needIDs []primitive.ObjectID) (map[primitive.ObjectID]*models.Task, error) {
+	cursor, err := s.mongoClient.GetCollection("tasks").Find(ctx,
+		bson.M{"need_id": bson.M{"$in": needIDs}, "status": bson.M{"$ne": models.TaskStatusCancelled}},
+		options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	// Later tasks overwrite earlier ones
+	latest := make(map[primitive.ObjectID]*models.Task, len(tasks))
+	for i := range tasks {
+		latest[tasks[i].NeedID] = &tasks[i]
+	}
+	return latest, nil
+}
+
+// referralState works out a referred need's status from the need and its
+// live task, if any
+func referralState(need *models.Need, task *models.Task, now time.Time) models.ReferralState {
+	state := models.ReferralState{
+		ExternalID: need.Partner.ExternalID,
+		NeedID:     need.ID,
+		UpdatedAt:  need.UpdatedAt,
+	}
+	if task != nil {
+		state.AcceptedAt = &task.CreatedAt
+		if task.UpdatedAt.After(state.UpdatedAt) {
+			state.UpdatedAt = task.UpdatedAt
+		}
+	}
+
+	switch {
+	case need.Status == models.NeedStatusCancelled:
+		state.Status = models.ReferralCancelled
+	case need.Status == models.NeedStatusCompleted || task != nil && task.Status == models.TaskStatusCompleted:
+		state.Status = models.ReferralCompleted
+		state.CompletedAt = &state.UpdatedAt
+		if task != nil && task.CompletedAt != nil {
+			state.CompletedAt = task.CompletedAt
+		}
+	case task != nil && (task.Status == models.TaskStatusInProgress || task.Status == models.TaskStatusAwaitingConfirmation):
+		state.Status = models.ReferralInProgress
+	case task != nil:
+		state.Status = models.ReferralMatched
+	case need.Status == models.NeedStatusExpired || need.ExpiresAt != nil && need.ExpiresAt.Before(now):
+		state.Status = models.ReferralExpired
+	case need.HeldForReview || need.Hidden:
+		state.Status = models.ReferralPendingReview
+	default:
+		state.Status = models.ReferralOpen
+	}
+	return state
+}
+
+// newPartnerCredentials generates an API key and webhook secret, storing
+// the key's prefix and hash and the secret on org
+func newPartnerCredentials(org *models.PartnerOrganization) (apiKey, webhookSecret string, err error) {
+	raw := make([]byte, 64)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	apiKey = partnerKeyPrefix + hex.EncodeToString(raw[:32])
+	webhookSecret = hex.EncodeToString(raw[32:])
+
+	org.KeyPrefix = apiKey[:partnerKeyShown]
+	org.KeyHash = hashPartnerKey(apiKey)
+	org.WebhookSecret = webhookSecret
+	return apiKey, webhookSecret, nil
+}
+
+// hashPartnerKey returns the stored form of an API key. Keys are random
+// enough that an unsalted hash is safe, and it lets keys be looked up.
+func hashPartnerKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkWebhookURL accepts an empty URL or an absolute http or https one
+func checkWebhookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/email"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+)
+
+// passwordResetInterval is how long an address waits between reset emails,
+// so the endpoint cannot be used to flood someone's inbox
+const passwordResetInterval = time.Minute
+
+// ErrInvalidResetToken is returned for reset tokens that are unknown,
+// expired, or already used
+var ErrInvalidResetToken = errors.New("reset token is invalid or has expired")
+
+// PasswordResetService issues emailed, single-use password reset tokens.
+// Only a hash of each token is kept, in Redis, until it expires.
+type PasswordResetService struct {
+	mongoClient *database.MongoClient
+	redisClient *database.RedisClient
+	sessions    *SessionStore
+	mailer      email.Sender
+	ttl         time.Duration
+	resetURL    string
+}
+
+// NewPasswordResetService creates a password reset service. Tokens are
+// valid for ttl; when resetURL is set the email links to it with the token
+// as a query parameter, otherwise it contains the bare token.
+func NewPasswordResetService(mongoClient *database.MongoClient, redisClient *database.RedisClient, sessionStore *SessionStore, mailer email.Sender, ttl time.Duration, resetURL string) *PasswordResetService {
+	return &PasswordResetService{
+		mongoClient: mongoClient,
+		redisClient: redisClient,
+		sessions:    sessionStore,
+		mailer:      mailer,
+		ttl:         ttl,
+		resetURL:    resetURL,
+	}
+}
+
+// RequestReset emails a reset token to the account registered with address.
+// Unknown addresses, and repeat requests within passwordResetInterval, are
+// ignored without error so callers cannot learn which addresses have
+// accounts. Issuing a token invalidates the user's previous one.
+func (s *PasswordResetService) RequestReset(ctx context.Context, address string) error {
+	address = normalizeEmail(address)
+	var user models.User
+	err := s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"email": address}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	userID := user.ID.Hex()
+	allowed, err := s.redisClient.SetNX(ctx, "password_reset_throttle:"+userID, 1, passwordResetInterval)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	hash := hashResetToken(token)
+
+	userKey := "password_reset_user:" + userID
+	if previous, err := s.redisClient.Get(ctx, userKey); err == nil {
+		s.redisClient.Del(ctx, "password_reset:"+previous)
+	}
+	if err := s.redisClient.Set(ctx, "password_reset:"+hash, userID, s.ttl); err != nil {
+		return err
+	}
+	if err := s.redisClient.Set(ctx, userKey, hash, s.ttl); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, s.resetEmail(user, token))
+}
+
+// resetEmail writes the reset email in the user's language
+func (s *PasswordResetService) resetEmail(user models.User, token string) email.Message {
+	lang := userLanguage(user)
+
+	link := token
+	if s.resetURL != "" {
+		if u, err := url.Parse(s.resetURL); err == nil {
+			query := u.Query()
+			query.Set("token", token)
+			u.RawQuery = query.Encode()
+			link = u.String()
+		}
+	}
+
+	return email.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "Reset your NeighborNexus password"),
+		Body: i18n.Tf(lang, "Hi %s,\n\nSomeone asked to reset the password for your NeighborNexus account. Use this to choose a new one within %d minutes:\n\n%s\n\nIf you did not ask for this, you can ignore this email; your password has not changed.",
+			user.Name, int(s.ttl.Minutes()), link),
+	}
+}
+
+// Reset consumes token and sets the password of the user it was issued to,
+// signing them out everywhere, and returns that user's ID. A token can only
+// be used once.
+func (s *PasswordResetService) Reset(ctx context.Context, token, password string) (primitive.ObjectID, error) {
+	hash := hashResetToken(token)
+	userID, err := s.redisClient.GetDel(ctx, "password_reset:"+hash)
+	if err == redis.Nil {
+		return primitive.NilObjectID, ErrInvalidResetToken
+	}
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	s.redisClient.Del(ctx, "password_reset_user:"+userID)
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidResetToken
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	result, err := s.mongoClient.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": objectID},
+		// The reset link was mailed to the account's address, which proves it
+		bson.M{
+			"$set":   bson.M{"password": string(hashed), "email_verified_at": time.Now(), "updated_at": time.Now()},
+			"$unset": bson.M{"email_pending": ""},
+		},
+	)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("failed to update password: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return primitive.NilObjectID, ErrInvalidResetToken
+	}
+
+	// Whoever prompted the reset may be signed in with the old password
+	if _, err := s.sessions.RevokeAll(ctx, userID, ""); err != nil {
+		log.Printf("Failed to end sessions of %s after password reset: %v", userID, err)
+	}
+	return objectID, nil
+}
+
+// hashResetToken is the form a reset token is stored under, so tokens
+// cannot be read back out of Redis
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+} 
\ No newline at end of file
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// Points rules: a completed task earns pointsBase plus one point per
+// pointsDurationStep minutes of estimated duration, up to
+// pointsDurationMax, scaled by the need's urgency
+const (
+	pointsBase         = 10
+	pointsDurationStep = 15
+	pointsDurationMax  = 20
+)
+
+// pointsUrgencyWeight scales a task's points by its need's urgency
+var pointsUrgencyWeight = map[models.Urgency]float64{
+	models.UrgencyLow:    1,
+	models.UrgencyMedium: 1.5,
+	models.UrgencyHigh:   2,
+}
+
+// pointsBatchSize caps how many completed tasks one AwardDue call scores
+const pointsBatchSize = 200
+
+// ErrInvalidPeriod is returned for a leaderboard period other than week,
+// month, or all
+var ErrInvalidPeriod = errors.New("period must be week, month, or all")
+
+// PointsService awards volunteers points and weekly streaks for completed
+// tasks and ranks them on leaderboards
+type PointsService struct {
+	mongoClient *database.MongoClient
+	dailyCap    int
+}
+
+// NewPointsService creates a new points service. A volunteer earns at most
+// dailyCap points per UTC day; zero leaves points uncapped.
+func NewPointsService(mongoClient *database.MongoClient, dailyCap int) *PointsService {
+	return &PointsService{
+		mongoClient: mongoClient,
+		dailyCap:    dailyCap,
+	}
+}
+
+// AwardDue scores completed tasks not yet scored, returning how many it
+// scored. Each task is claimed before it is scored, so concurrent workers
+// score it once.
+func (s *PointsService) AwardDue(ctx context.Context) (int, error) {
+	tasks := s.mongoClient.GetCollection("tasks")
+	cursor, err := tasks.Find(ctx,
+		bson.M{"status": models.TaskStatusCompleted, "scored": bson.M{"$ne": true}},
+		options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}).SetLimit(pointsBatchSize))
+	if err != nil {
+		return 0, err
+	}
+	var due []models.Task
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, err
+	}
+
+	scored := 0
+	for _, task := range due {
+		result, err := tasks.UpdateOne(ctx,
+			bson.M{"_id": task.ID, "scored": bson.M{"$ne": true}},
+			bson.M{"$set": bson.M{"scored": true}})
+		if err != nil {
+			return scored, err
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+		if err := s.award(ctx, &task); err != nil {
+			return scored, err
+		}
+		scored++
+	}
+	return scored, nil
+}
+
+// award writes the ledger line for a completed task and credits the
+// volunteer's points and streak. Volunteers earn nothing, and keep no
+// streak, for needs they created themselves.
+func (s *PointsService) award(ctx context.Context, task *models.Task) error {
+	var need models.Need
+	err := s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1, "urgency": 1, "duration": 1})).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	completedAt := task.UpdatedAt
+	if task.CompletedAt != nil {
+		completedAt = *task.CompletedAt
+	}
+	entry := models.PointsEntry{
+		ID:        primitive.NewObjectID(),
+		UserID:    task.VolunteerID,
+		TaskID:    task.ID,
+		CreatedAt: completedAt,
+	}
+
+	if need.UserID == task.VolunteerID {
+		entry.Reason = models.PointsSelfCreated
+	} else {
+		entry.Points = taskPoints(&need)
+		if s.dailyCap > 0 {
+			earned, err := s.earnedOn(ctx, task.VolunteerID, completedAt)
+			if err != nil {
+				return err
+			}
+			if left := s.dailyCap - earned; entry.Points > left {
+				entry.Points = max(left, 0)
+				entry.Reason = models.PointsDailyCap
+			}
+		}
+	}
+
+	if _, err := s.mongoClient.GetCollection("points").InsertOne(ctx, entry); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	}
+	if entry.Reason == models.PointsSelfCreated {
+		return nil
+	}
+	return s.credit(ctx, task.VolunteerID, entry.Points, completedAt)
+}
+
+// credit adds points to the volunteer's profile and extends their streak
+// to the week of completedAt
+func (s *PointsService) credit(ctx context.Context, userID primitive.ObjectID, points int, completedAt time.Time) error {
+	volunteers := s.mongoClient.GetCollection("volunteers")
+	var volunteer models.Volunteer
+	err := volunteers.FindOne(ctx, bson.M{"user_id": userID},
+		options.FindOne().SetProjection(bson.M{"streak": 1})).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	if streak := extendStreak(volunteer.Streak, weekOf(completedAt)); streak != nil {
+		set["streak"] = streak
+	}
+	_, err = volunteers.UpdateOne(ctx, bson.M{"user_id": userID},
+		bson.M{"$inc": bson.M{"points": points}, "$set": set})
+	return err
+}
+
+// earnedOn sums the points userID earned on the UTC day of t
+func (s *PointsService) earnedOn(ctx context.Context, userID primitive.ObjectID, t time.Time) (int, error) {
+	day := t.UTC().Truncate(24 * time.Hour)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":    userID,
+			"created_at": bson.M{"$gte": day, "$lt": day.Add(24 * time.Hour)},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "points": bson.M{"$sum": "$points"}}}},
+	}
+	cursor, err := s.mongoClient.GetCollection("points").Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	var totals []struct {
+		Points int `bson:"points"`
+	}
+	if err := cursor.All(ctx, &totals); err != nil {
+		return 0, err
+	}
+	if len(totals) == 0 {
+		return 0, nil
+	}
+	return totals[0].Points, nil
+}
+
+// Summary returns a volunteer's points, streak, and latest ledger lines
+func (s *PointsService) Summary(ctx context.Context, userID primitive.ObjectID) (*models.PointsSummary, error) {
+	var volunteer models.Volunteer
+	err := s.mongoClient.GetCollection("volunteers").FindOne(ctx, bson.M{"user_id": userID},
+		options.FindOne().SetProjection(bson.M{"points": 1, "streak": 1})).Decode(&volunteer)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	cursor, err := s.mongoClient.GetCollection("points").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(20))
+	if err != nil {
+		return nil, err
+	}
+	summary := &models.PointsSummary{
+		Points: volunteer.Points,
+		Streak: currentStreak(volunteer.Streak, time.Now()),
+		Recent: []models.PointsEntry{},
+	}
+	if err := cursor.All(ctx, &summary.Recent); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Leaderboard ranks volunteers by points earned in period, one of week,
+// month, or all. Users who hide from leaderboards, or whose profile a
+// moderator hid, are left out.
+func (s *PointsService) Leaderboard(ctx context.Context, period string, limit int64) ([]models.LeaderboardEntry, error) {
+	now := time.Now().UTC()
+	match := bson.M{"points": bson.M{"$gt": 0}}
+	switch period {
+	case "week":
+		match["created_at"] = bson.M{"$gte": weekStart(weekOf(now))}
+	case "month":
+		match["created_at"] = bson.M{"$gte": time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)}
+	case "all", "":
+	default:
+		return nil, ErrInvalidPeriod
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "points": bson.M{"$sum": "$points"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "users", "localField": "_id", "foreignField": "_id", "as": "user"}}},
+		{{Key: "$unwind", Value: "$user"}},
+		{{Key: "$match", Value: bson.M{
+			"user.privacy.hide_from_leaderboards": bson.M{"$ne": true},
+			"user.hidden":                         bson.M{"$ne": true},
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "volunteers", "localField": "_id", "foreignField": "user_id", "as": "volunteer"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "points", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := s.mongoClient.GetCollection("points").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Points    int                `bson:"points"`
+		User      models.User        `bson:"user"`
+		Volunteer []models.Volunteer `bson:"volunteer"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.LeaderboardEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = models.LeaderboardEntry{
+			Rank:   i + 1,
+			UserID: row.User.ID,
+			Name:   row.User.DisplayName(),
+			Points: row.Points,
+		}
+		if len(row.Volunteer) > 0 {
+			entries[i].Streak = currentStreak(row.Volunteer[0].Streak, now).Current
+		}
+	}
+	return entries, nil
+}
+
+// taskPoints applies the points rules to a need
+func taskPoints(need *models.Need) int {
+	weight, ok := pointsUrgencyWeight[need.Urgency]
+	if !ok {
+		weight = 1
+	}
+	bonus := min(max(need.Duration, 0)/pointsDurationStep, pointsDurationMax)
+	return int(math.Round(float64(pointsBase+bonus) * weight))
+}
+
+// weekOf numbers the Monday-to-Sunday UTC week t falls in. The Unix epoch
+// was a Thursday, so shifting by three days puts week boundaries on Mondays.
+func weekOf(t time.Time) int {
+	return int((t.Unix()/86400 + 3) / 7)
+}
+
+// weekStart returns the Monday midnight UTC that starts week
+func weekStart(week int) time.Time {
+	return time.Unix((int64(week)*7-3)*86400, 0).UTC()
+}
+
+// extendStreak returns streak carried to week, or nil when week is already
+// counted or earlier than the last counted week
+func extendStreak(streak *models.Streak, week int) *models.Streak {
+	next := models.Streak{Current: 1, LastWeek: week}
+	if streak != nil {
+		if week <= streak.LastWeek {
+			return nil
+		}
+		if week == streak.LastWeek+1 {
+			next.Current = streak.Current + 1
+		}
+		next.Longest = streak.Longest
+	}
+	next.Longest = max(next.Longest, next.Current)
+	return &next
+}
+
+// currentStreak returns streak as of now: a streak whose last week is
+// neither this week nor last has been broken
+func currentStreak(streak *models.Streak, now time.Time) models.Streak {
+	if streak == nil {
+		return models.Streak{}
+	}
+	current := *streak
+	if current.LastWeek < weekOf(now)-1 {
+		current.Current = 0
+	}
+	return current
+} 
\ No newline at end of file
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+var (
+	// ErrPostNotFound is returned for a post that does not exist or is hidden
+	ErrPostNotFound = errors.New("post not found")
+	// ErrNotOrganizer is returned when someone other than a verified
+	// organizer tries to post
+	ErrNotOrganizer = errors.New("only verified organizers can post to neighborhood feeds")
+	// ErrNotPostAuthor is returned when someone other than the author or an
+	// admin tries to delete a post
+	ErrNotPostAuthor = errors.New("only the author can delete this post")
+	// ErrInvalidNeighborhood is returned for a neighborhood that is not a
+	// valid H3 cell at neighborhood resolution or finer, or for a user with
+	// no location to default to
+	ErrInvalidNeighborhood = errors.New("neighborhood must be a valid H3 cell at neighborhood resolution or finer")
+)
+
+// PostService manages organizers' posts to neighborhood feeds and readers'
+// reactions to them
+type PostService struct {
+	mongoClient    *database.MongoClient
+	privacyService *PrivacyService
+}
+
+// NewPostService creates a new post service
+func NewPostService(mongoClient *database.MongoClient, privacyService *PrivacyService) *PostService {
+	return &PostService{
+		mongoClient:    mongoClient,
+		privacyService: privacyService,
+	}
+}
+
+// Create posts to a neighborhood feed, hidden until reviewed when hold is
+// set because the text was flagged
+func (s *PostService) Create(ctx context.Context, author *models.User, req models.CreatePostRequest, hold bool) (*models.Post, error) {
+	if !author.CanPost() {
+		return nil, ErrNotOrganizer
+	}
+	neighborhood, err := s.Neighborhood(author, req.Neighborhood)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	post := models.Post{
+		ID:           primitive.NewObjectID(),
+		AuthorID:     author.ID,
+		Neighborhood: neighborhood,
+		Title:        req.Title,
+		Body:         req.Body,
+		Reactions:    map[models.PostReaction]int{},
+		Hidden:       hold,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if _, err := s.mongoClient.GetCollection("posts").InsertOne(ctx, post); err != nil {
+		return nil, err
+	}
+	post.AuthorName = author.DisplayName()
+	return &post, nil
+}
+
+// Neighborhood resolves a requested neighborhood to its cell at
+// neighborhood resolution, defaulting to the user's own
+func (s *PostService) Neighborhood(user *models.User, requested string) (string, error) {
+	var neighborhood string
+	if requested == "" {
+		neighborhood = s.privacyService.NeighborhoodCell(user.Location)
+	} else {
+		neighborhood = s.privacyService.NeighborhoodCell(models.Location{H3Index: requested})
+	}
+	resolution := s.privacyService.Resolutions()[1]
+	if neighborhood == "" || h3.Cell(h3.IndexFromString(neighborhood)).Resolution() != resolution {
+		return "", ErrInvalidNeighborhood
+	}
+	return neighborhood, nil
+}
+
+// Feed returns the visible posts in a neighborhood, newest first, with the
+// authors' display names and the viewer's reactions
+func (s *PostService) Feed(ctx context.Context, neighborhood string, viewerID primitive.ObjectID, limit, offset int64) ([]models.Post, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("posts").Find(ctx, bson.M{
+		"neighborhood": neighborhood,
+		"hidden":       bson.M{"$ne": true},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	posts := []models.Post{}
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	if err := s.shape(ctx, viewerID, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// Get returns a visible post for viewerID
+func (s *PostService) Get(ctx context.Context, postID, viewerID primitive.ObjectID) (*models.Post, error) {
+	var post models.Post
+	err := s.mongoClient.GetCollection("posts").FindOne(ctx, bson.M{"_id": postID, "hidden": bson.M{"$ne": true}}).Decode(&post)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	posts := []models.Post{post}
+	if err := s.shape(ctx, viewerID, posts); err != nil {
+		return nil, err
+	}
+	return &posts[0], nil
+}
+
+// Delete removes a post and its reactions. Authors can delete their own
+// posts and admins any post.
+func (s *PostService) Delete(ctx context.Context, postID primitive.ObjectID, user *models.User) error {
+	filter := bson.M{"_id": postID}
+	var post models.Post
+	if err := s.mongoClient.GetCollection("posts").FindOne(ctx, filter).Decode(&post); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrPostNotFound
+		}
+		return err
+	}
+	if post.AuthorID != user.ID && !user.HasRole(models.RoleAdmin) {
+		return ErrNotPostAuthor
+	}
+
+	if _, err := s.mongoClient.GetCollection("posts").DeleteOne(ctx, filter); err != nil {
+		return err
+	}
+	_, err := s.mongoClient.GetCollection("post_reactions").DeleteMany(ctx, bson.M{"post_id": postID})
+	return err
+}
+
+// React sets a user's reaction to a visible post, replacing any earlier one
+func (s *PostService) React(ctx context.Context, postID, userID primitive.ObjectID, reaction models.PostReaction) (*models.Post, error) {
+	if _, err := s.Get(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	var previous models.PostReactionRecord
+	err := s.mongoClient.GetCollection("post_reactions").FindOneAndUpdate(ctx,
+		bson.M{"post_id": postID, "user_id": userID},
+		bson.M{
+			"$set":         bson.M{"reaction": reaction, "created_at": time.Now()},
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&previous)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		err = s.countReaction(ctx, postID, bson.M{"reactions." + string(reaction): 1})
+	case err == nil && previous.Reaction != reaction:
+		err = s.countReaction(ctx, postID, bson.M{"reactions." + string(reaction): 1, "reactions." + string(previous.Reaction): -1})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, postID, userID)
+}
+
+// Unreact removes a user's reaction to a post
+func (s *PostService) Unreact(ctx context.Context, postID, userID primitive.ObjectID) error {
+	var previous models.PostReactionRecord
+	err := s.mongoClient.GetCollection("post_reactions").FindOneAndDelete(ctx, bson.M{"post_id": postID, "user_id": userID}).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.countReaction(ctx, postID, bson.M{"reactions." + string(previous.Reaction): -1})
+}
+
+// countReaction applies inc to a post's reaction counts
+func (s *PostService) countReaction(ctx context.Context, postID primitive.ObjectID, inc bson.M) error {
+	_, err := s.mongoClient.GetCollection("posts").UpdateOne(ctx, bson.M{"_id": postID}, bson.M{"$inc": inc})
+	return err
+}
+
+// shape fills in the authors' display names and the viewer's reactions
+func (s *PostService) shape(ctx context.Context, viewerID primitive.ObjectID, posts []models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	postIDs := make([]primitive.ObjectID, len(posts))
+	authorIDs := make([]primitive.ObjectID, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+		authorIDs[i] = post.AuthorID
+	}
+
+	cursor, err := s.mongoClient.GetCollection("users").Find(ctx, bson.M{"_id": bson.M{"$in": authorIDs}},
+		options.Find().SetProjection(bson.M{"name": 1, "privacy": 1}))
+	if err != nil {
+		return err
+	}
+	var authors []models.User
+	if err := cursor.All(ctx, &authors); err != nil {
+		return err
+	}
+	names := make(map[primitive.ObjectID]string, len(authors))
+	for i := range authors {
+		names[authors[i].ID] = authors[i].DisplayName()
+	}
+
+	cursor, err = s.mongoClient.GetCollection("post_reactions").Find(ctx, bson.M{"post_id": bson.M{"$in": postIDs}, "user_id": viewerID})
+	if err != nil {
+		return err
+	}
+	var reactions []models.PostReactionRecord
+	if err := cursor.All(ctx, &reactions); err != nil {
+		return err
+	}
+	mine := make(map[primitive.ObjectID]models.PostReaction, len(reactions))
+	for _, reaction := range reactions {
+		mine[reaction.PostID] = reaction.Reaction
+	}
+
+	for i := range posts {
+		posts[i].AuthorName = names[posts[i].AuthorID]
+		posts[i].MyReaction = mine[posts[i].ID]
+		if posts[i].Reactions == nil {
+			posts[i].Reactions = map[models.PostReaction]int{}
+		}
+	}
+	return nil
+} 
\ No newline at end of file
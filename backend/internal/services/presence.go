@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"neighborenexus/internal/database"
+)
+
+// presenceTTL is how long a WebSocket connection counts as present after
+// its instance last refreshed it, so a crashed instance's users are soon
+// treated as offline
+const presenceTTL = 90 * time.Second
+
+// presenceHeartbeat is how often instances refresh their users' presence
+const presenceHeartbeat = 30 * time.Second
+
+// Presence tracks which users hold a WebSocket connection on any API
+// instance, so notifications can fall back to push for everyone else
+type Presence struct {
+	redisClient *database.RedisClient
+	instanceID  string
+}
+
+// NewPresence creates a presence tracker for this instance
+func NewPresence(redisClient *database.RedisClient) *Presence {
+	return &Presence{
+		redisClient: redisClient,
+		instanceID:  uuid.New().String(),
+	}
+}
+
+// Touch marks users as connected to this instance
+func (p *Presence) Touch(ctx context.Context, userIDs []string) {
+	now := time.Now()
+	for _, userID := range userIDs {
+		if err := p.redisClient.TouchPresence(ctx, "presence:"+userID, p.instanceID, now, presenceTTL); err != nil {
+			log.Printf("Failed to record presence of user %s: %v", userID, err)
+		}
+	}
+}
+
+// Leave marks a user as no longer connected to this instance
+func (p *Presence) Leave(ctx context.Context, userID string) {
+	if err := p.redisClient.RemovePresence(ctx, "presence:"+userID, p.instanceID); err != nil {
+		log.Printf("Failed to clear presence of user %s: %v", userID, err)
+	}
+}
+
+// Offline returns the users not connected to any instance
+func (p *Presence) Offline(ctx context.Context, userIDs []string) ([]string, error) {
+	since := time.Now().Add(-presenceTTL)
+	var offline []string
+	for _, userID := range userIDs {
+		present, err := p.redisClient.PresentSince(ctx, "presence:"+userID, since)
+		if err != nil {
+			return nil, err
+		}
+		if !present {
+			offline = append(offline, userID)
+		}
+	}
+	return offline, nil
+} 
\ No newline at end of file
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// locationRevealStatuses are the task statuses at which a need's creator and
+// its volunteer see each other's exact location
+var locationRevealStatuses = []models.TaskStatus{
+	models.TaskStatusAccepted,
+	models.TaskStatusInProgress,
+	models.TaskStatusAwaitingConfirmation,
+	models.TaskStatusCompleted,
+}
+
+// contactStatuses are the task statuses at which a need's creator and its
+// volunteer may see each other's contact details
+var contactStatuses = []models.TaskStatus{
+	models.TaskStatusAccepted,
+	models.TaskStatusInProgress,
+	models.TaskStatusAwaitingConfirmation,
+}
+
+var (
+	// ErrTaskNotFound is returned for a task that does not exist or that the
+	// viewer does not take part in
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrContactNotShared is returned when a task is not in a status that
+	// shares contact details
+	ErrContactNotShared = errors.New("contact details are only shared while a task is accepted or in progress")
+)
+
+// PrivacyService decides what each viewer may see of other users' data and
+// stores each user's privacy settings. It also assigns stored locations
+// their H3 cell, at the resolution of the owner's location precision.
+type PrivacyService struct {
+	mongoClient      *database.MongoClient
+	defaultPrecision models.LocationPrecision
+	resolutions      map[models.LocationPrecision]int
+}
+
+// NewPrivacyService creates a new privacy service. defaultPrecision applies
+// to users who have not chosen one; block resolution 9 cells average about
+// 0.1 km² and neighborhood resolution 7 cells about 5 km².
+func NewPrivacyService(mongoClient *database.MongoClient, defaultPrecision models.LocationPrecision, blockResolution, neighborhoodResolution int) *PrivacyService {
+	return &PrivacyService{
+		mongoClient:      mongoClient,
+		defaultPrecision: defaultPrecision,
+		resolutions: map[models.LocationPrecision]int{
+			models.LocationPrecisionBlock:        blockResolution,
+			models.LocationPrecisionNeighborhood: neighborhoodResolution,
+		},
+	}
+}
+
+// LocationResolution returns the H3 resolution of the locations of a user
+// with settings
+func (s *PrivacyService) LocationResolution(settings models.PrivacySettings) int {
+	if resolution, ok := s.resolutions[settings.LocationPrecision]; ok {
+		return resolution
+	}
+	return s.resolutions[s.defaultPrecision]
+}
+
+// Resolutions returns the H3 resolutions stored locations may be indexed
+// at, finest first
+func (s *PrivacyService) Resolutions() []int {
+	return []int{s.resolutions[models.LocationPrecisionBlock], s.resolutions[models.LocationPrecisionNeighborhood]}
+}
+
+// NeighborhoodCell returns the H3 cell at neighborhood resolution that
+// location falls in, or "" for a location without coordinates or a cell
+func (s *PrivacyService) NeighborhoodCell(location models.Location) string {
+	resolution := s.resolutions[models.LocationPrecisionNeighborhood]
+	if location.Latitude != 0 || location.Longitude != 0 {
+		return h3.LatLngToCell(h3.LatLng{Lat: location.Latitude, Lng: location.Longitude}, resolution).String()
+	}
+	cell := h3.Cell(h3.IndexFromString(location.H3Index))
+	if location.H3Index == "" || !cell.IsValid() {
+		return ""
+	}
+	if cell.Resolution() > resolution {
+		cell = cell.Parent(resolution)
+	}
+	return cell.String()
+}
+
+// IndexLocation sets location's H3 cell from its coordinates, at the
+// resolution of the owner's settings. Locations without coordinates are
+// returned unchanged.
+func (s *PrivacyService) IndexLocation(location models.Location, settings models.PrivacySettings) models.Location {
+	if location.Latitude == 0 && location.Longitude == 0 {
+		return location
+	}
+	cell := h3.LatLngToCell(h3.LatLng{Lat: location.Latitude, Lng: location.Longitude}, s.LocationResolution(settings))
+	location.H3Index = cell.String()
+	return location
+}
+
+// LocationPoint returns the GeoJSON point needs near a location are
+// searched by: the center of its H3 cell, so radius queries never see more
+// than the owner's chosen precision. It returns nil for a location without
+// coordinates or a cell.
+func (s *PrivacyService) LocationPoint(location models.Location) *models.GeoPoint {
+	approximate := s.ApproximateLocation(location)
+	if approximate.H3Index == "" {
+		return nil
+	}
+	return models.NewGeoPoint(approximate.Latitude, approximate.Longitude)
+}
+
+// ReindexLocations recomputes the H3 cells of a user's profile, volunteer
+// profile, and needs, and the points of their needs, after their location
+// precision changes
+func (s *PrivacyService) ReindexLocations(ctx context.Context, userID primitive.ObjectID, settings models.PrivacySettings) error {
+	for collectionName, field := range map[string]string{"users": "_id", "volunteers": "user_id", "needs": "user_id"} {
+		collection := s.mongoClient.GetCollection(collectionName)
+		cursor, err := collection.Find(ctx, bson.M{field: userID}, options.Find().SetProjection(bson.M{"location": 1}))
+		if err != nil {
+			return err
+		}
+
+		var docs []struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Location models.Location    `bson:"location"`
+		}
+		err = cursor.All(ctx, &docs)
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range docs {
+			location := s.IndexLocation(doc.Location, settings)
+			if location.H3Index == doc.Location.H3Index {
+				continue
+			}
+			set := bson.M{"location.h3_index": location.H3Index}
+			if collectionName == "needs" {
+				set["point"] = s.LocationPoint(location)
+			}
+			_, err = collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": set})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateSettings applies a partial update to a user's privacy settings and
+// returns the result. Changing the location precision re-cells the user's
+// stored locations.
+func (s *PrivacyService) UpdateSettings(ctx context.Context, userID primitive.ObjectID, req models.UpdatePrivacyRequest) (models.PrivacySettings, error) {
+	set := bson.M{}
+	if req.NameDisplay != nil {
+		set["privacy.name_display"] = *req.NameDisplay
+	}
+	if req.HideRating != nil {
+		set["privacy.hide_rating"] = *req.HideRating
+	}
+	if req.HideFromSearch != nil {
+		set["privacy.hide_from_search"] = *req.HideFromSearch
+	}
+	if req.HideFromLeaderboards != nil {
+		set["privacy.hide_from_leaderboards"] = *req.HideFromLeaderboards
+	}
+	if req.LocationPrecision != nil {
+		set["privacy.location_precision"] = *req.LocationPrecision
+	}
+	if req.HideContact != nil {
+		set["privacy.hide_contact"] = *req.HideContact
+	}
+
+	var user models.User
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.M{"privacy": 1}).
+		SetReturnDocument(options.After)
+	err := s.mongoClient.GetCollection("users").FindOneAndUpdate(ctx, bson.M{"_id": userID}, bson.M{"$set": set}, opts).Decode(&user)
+	if err != nil {
+		return user.Privacy, err
+	}
+
+	if req.LocationPrecision != nil {
+		err = s.ReindexLocations(ctx, userID, user.Privacy)
+	}
+	return user.Privacy, err
+}
+
+// RevealedNeeds returns which of needIDs the viewer may see the exact
+// location of: needs they created and needs they hold an accepted task for
+func (s *PrivacyService) RevealedNeeds(ctx context.Context, viewerID primitive.ObjectID, needIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	revealed := map[primitive.ObjectID]bool{}
+	if len(needIDs) == 0 {
+		return revealed, nil
+	}
+
+	own, err := s.mongoClient.GetCollection("needs").Distinct(ctx, "_id", bson.M{"_id": bson.M{"$in": needIDs}, "user_id": viewerID})
+	if err != nil {
+		return nil, err
+	}
+	volunteered, err := s.mongoClient.GetCollection("tasks").Distinct(ctx, "need_id", bson.M{
+		"need_id":      bson.M{"$in": needIDs},
+		"volunteer_id": viewerID,
+		"status":       bson.M{"$in": locationRevealStatuses},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range append(own, volunteered...) {
+		if objectID, ok := id.(primitive.ObjectID); ok {
+			revealed[objectID] = true
+		}
+	}
+	return revealed, nil
+}
+
+// SharesTask reports whether the viewer and another user are the creator and
+// volunteer of a need with an accepted task, in either direction
+func (s *PrivacyService) SharesTask(ctx context.Context, viewerID, otherID primitive.ObjectID) (bool, error) {
+	if viewerID == otherID {
+		return true, nil
+	}
+
+	for _, pair := range [][2]primitive.ObjectID{{viewerID, otherID}, {otherID, viewerID}} {
+		creatorID, volunteerID := pair[0], pair[1]
+		needIDs, err := s.mongoClient.GetCollection("needs").Distinct(ctx, "_id", bson.M{"user_id": creatorID})
+		if err != nil {
+			return false, err
+		}
+		if len(needIDs) == 0 {
+			continue
+		}
+		count, err := s.mongoClient.GetCollection("tasks").CountDocuments(ctx, bson.M{
+			"need_id":      bson.M{"$in": needIDs},
+			"volunteer_id": volunteerID,
+			"status":       bson.M{"$in": locationRevealStatuses},
+		})
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TaskContact returns the contact details of the viewer's partner on a task:
+// the volunteer if the viewer created the need, otherwise the need's creator
+func (s *PrivacyService) TaskContact(ctx context.Context, viewerID, taskID primitive.ObjectID) (*models.ContactDetails, error) {
+	var task models.Task
+	err := s.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{"_id": taskID}).Decode(&task)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var need models.Need
+	opts := options.FindOne().SetProjection(bson.M{"user_id": 1, "partner": 1})
+	err = s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID}, opts).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var partnerID primitive.ObjectID
+	switch viewerID {
+	case need.UserID:
+		partnerID = task.VolunteerID
+	case task.VolunteerID:
+		partnerID = need.UserID
+	default:
+		return nil, ErrTaskNotFound
+	}
+	if !hasStatus(contactStatuses, task.Status) {
+		return nil, ErrContactNotShared
+	}
+	if need.Partner != nil {
+		return s.referralContact(ctx, need.Partner)
+	}
+
+	var partner models.User
+	err = s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": partnerID}).Decode(&partner)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contact := &models.ContactDetails{
+		UserID:    partner.ID,
+		Name:      partner.DisplayName(),
+		InAppOnly: partner.Privacy.HideContact,
+	}
+	if !contact.InAppOnly {
+		contact.Phone = string(partner.Phone)
+		contact.Email = partner.Email
+	}
+	return contact, nil
+}
+
+// referralContact returns the contact details for a need a partner
+// organization referred: the client's where given, otherwise the
+// organization's
+func (s *PrivacyService) referralContact(ctx context.Context, referral *models.PartnerReferral) (*models.ContactDetails, error) {
+	var org models.PartnerOrganization
+	err := s.mongoClient.GetCollection("partner_organizations").FindOne(ctx, bson.M{"_id": referral.OrganizationID}).Decode(&org)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contact := &models.ContactDetails{
+		UserID:       org.ID,
+		Name:         string(referral.ClientName),
+		Phone:        string(referral.ClientPhone),
+		Email:        org.ContactEmail,
+		Organization: org.Name,
+	}
+	if contact.Name == "" {
+		contact.Name = org.Name
+	}
+	if contact.Phone == "" {
+		contact.Phone = string(org.ContactPhone)
+	}
+	return contact, nil
+}
+
+// RevealsLocation reports whether a task in status lets its participants see
+// each other's exact location
+func RevealsLocation(status models.TaskStatus) bool {
+	return hasStatus(locationRevealStatuses, status)
+}
+
+// hasStatus reports whether status is one of statuses
+func hasStatus(statuses []models.TaskStatus, status models.TaskStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ApproximateLocation coarsens a location to the center of its stored H3
+// cell, which is at the owner's chosen precision, dropping the street
+// address. Locations without a stored cell fall back to the neighborhood
+// resolution, the coarser of the two.
+func (s *PrivacyService) ApproximateLocation(location models.Location) models.Location {
+	blockResolution := s.resolutions[models.LocationPrecisionBlock]
+	neighborhoodResolution := s.resolutions[models.LocationPrecisionNeighborhood]
+
+	cell := h3.Cell(h3.IndexFromString(location.H3Index))
+	switch {
+	case location.H3Index != "" && cell.IsValid():
+		// Cells stored before the deployment's resolutions were lowered are
+		// coarsened to match
+		if cell.Resolution() > blockResolution {
+			cell = cell.Parent(blockResolution)
+		}
+	case location.Latitude != 0 || location.Longitude != 0:
+		cell = h3.LatLngToCell(h3.LatLng{Lat: location.Latitude, Lng: location.Longitude}, neighborhoodResolution)
+	default:
+		return models.Location{Approximate: true}
+	}
+
+	center := cell.LatLng()
+	return models.Location{
+		Latitude:    center.Lat,
+		Longitude:   center.Lng,
+		H3Index:     cell.String(),
+		Approximate: true,
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/push"
+)
+
+// ErrDeviceNotFound is returned when the user has no device with the token
+var ErrDeviceNotFound = errors.New("device not found")
+
+// pushTitles are the notification titles for the WebSocket messages worth
+// a push. Other messages, like read receipts, only matter to an open app.
+var pushTitles = map[string]string{
+	"new_need":           "A neighbor near you needs help",
+	"need_accepted":      "A volunteer accepted your need",
+	"offer_accepted":     "A neighbor took up your offer",
+	"task_status_update": "A task was updated",
+	"new_match":          "You have a new match",
+	"chat_message":       "You have a new message",
+	"announcement":       "New announcement",
+	"digest":             "Your digest of needs near you",
+	"event_reminder":     "Reminder: your volunteer shift is coming up",
+	"event_cancelled":    "An event you signed up for was cancelled",
+	"need_sla_breached":  "A need is overdue",
+	"waitlist_matched":   "Volunteers are now available for your need",
+	"report_resolved":    "Your report was reviewed",
+	"safety_incident":    "A safety incident was reported",
+}
+
+// PushService stores users' device tokens and pushes notifications to them
+// through FCM and APNs when users aren't connected over WebSocket
+type PushService struct {
+	mongoClient *database.MongoClient
+	presence    *Presence
+	senders     map[models.DevicePlatform]push.Sender
+}
+
+// NewPushService creates a push service. FCM delivers to Android and web
+// devices and APNs to iOS ones; a nil sender leaves its platforms without
+// push.
+func NewPushService(mongoClient *database.MongoClient, presence *Presence, fcm, apns push.Sender) *PushService {
+	senders := make(map[models.DevicePlatform]push.Sender)
+	if fcm != nil {
+		senders[models.DevicePlatformAndroid] = fcm
+		senders[models.DevicePlatformWeb] = fcm
+	}
+	if apns != nil {
+		senders[models.DevicePlatformIOS] = apns
+	}
+	return &PushService{
+		mongoClient: mongoClient,
+		presence:    presence,
+		senders:     senders,
+	}
+}
+
+// Enabled reports whether any push service is configured
+func (s *PushService) Enabled() bool {
+	return len(s.senders) > 0
+}
+
+// RegisterDevice stores a push token for the user. A token already
+// registered by someone else, e.g. after signing in as another user on a
+// shared device, moves to this user.
+func (s *PushService) RegisterDevice(ctx context.Context, userID primitive.ObjectID, req models.RegisterDeviceRequest) (*models.Device, error) {
+	now := time.Now()
+	var device models.Device
+	err := s.mongoClient.GetCollection("devices").FindOneAndUpdate(ctx,
+		bson.M{"token": req.Token},
+		bson.M{
+			"$set": bson.M{
+				"user_id":    userID,
+				"platform":   req.Platform,
+				"updated_at": now,
+			},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&device)
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// UnregisterDevice removes one of the user's push tokens
+func (s *PushService) UnregisterDevice(ctx context.Context, userID primitive.ObjectID, token string) error {
+	result, err := s.mongoClient.GetCollection("devices").DeleteOne(ctx, bson.M{"token": token, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// SendOffline pushes a message to those of the users not connected over
+// WebSocket to any instance
+func (s *PushService) SendOffline(ctx context.Context, userIDs []string, message models.WebSocketMessage) error {
+	offline, err := s.presence.Offline(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	return s.Send(ctx, offline, message)
+}
+
+// Send pushes a message to every device of the users. Failures on single
+// devices are logged rather than returned, so a retry doesn't notify the
+// devices that got it twice, and tokens the push services reject are
+// forgotten.
+func (s *PushService) Send(ctx context.Context, userIDs []string, message models.WebSocketMessage) error {
+	title, ok := pushTitles[message.Type]
+	if !ok || !s.Enabled() || len(userIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if id, err := primitive.ObjectIDFromHex(userID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	cursor, err := s.mongoClient.GetCollection("devices").Find(ctx, bson.M{"user_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	var devices []models.Device
+	if err := cursor.All(ctx, &devices); err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	languages, err := s.languages(ctx, devices)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{"type": message.Type}
+	if message.Payload != nil {
+		payload, err := json.Marshal(message.Payload)
+		if err != nil {
+			return err
+		}
+		data["payload"] = string(payload)
+	}
+	body := pushBody(message)
+
+	for _, device := range devices {
+		sender, ok := s.senders[device.Platform]
+		if !ok {
+			continue
+		}
+		msg := push.Message{
+			Title: i18n.T(languages[device.UserID], title),
+			Body:  body,
+			Data:  data,
+		}
+		err := sender.Send(ctx, device.Token, msg)
+		switch {
+		case errors.Is(err, push.ErrInvalidToken):
+			if _, err := s.mongoClient.GetCollection("devices").DeleteOne(ctx, bson.M{"_id": device.ID}); err != nil {
+				log.Printf("Failed to forget invalid device %s: %v", device.ID.Hex(), err)
+			}
+		case err != nil:
+			log.Printf("Failed to push %s to device %s: %v", message.Type, device.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// languages looks up the language to write to each device's user in
+func (s *PushService) languages(ctx context.Context, devices []models.Device) (map[primitive.ObjectID]string, error) {
+	ids := make([]primitive.ObjectID, 0, len(devices))
+	for _, device := range devices {
+		ids = append(ids, device.UserID)
+	}
+
+	cursor, err := s.mongoClient.GetCollection("users").Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"language": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	languages := make(map[primitive.ObjectID]string, len(users))
+	for _, user := range users {
+		languages[user.ID] = userLanguage(user)
+	}
+	for _, id := range ids {
+		if _, ok := languages[id]; !ok {
+			languages[id] = i18n.Default
+		}
+	}
+	return languages, nil
+}
+
+// pushBody is the notification text under the title: the title of the
+// need, announcement or event the message is about, when it carries one
+func pushBody(message models.WebSocketMessage) string {
+	payload, ok := message.Payload.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	title, _ := payload["title"].(string)
+	return title
+} 
\ No newline at end of file
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// Reputation scores are a Bayesian average: reputationPriorWeight neutral
+// ratings of reputationPrior stand in until real ratings outweigh them, so
+// one early rating cannot make or break a newcomer
+const (
+	reputationPrior       = 3.0
+	reputationPriorWeight = 5.0
+)
+
+// RatingService keeps users' reputations, ratings, and task counts in step
+// with their feedback and tasks. Writers queue the users affected by a
+// change on the outbox and a worker recomputes each from scratch, so
+// replaying an event, or a full recompute, cannot make the totals drift.
+type RatingService struct {
+	mongoClient *database.MongoClient
+	outbox      *Outbox
+}
+
+// NewRatingService creates a new rating service
+func NewRatingService(mongoClient *database.MongoClient, outbox *Outbox) *RatingService {
+	return &RatingService{
+		mongoClient: mongoClient,
+		outbox:      outbox,
+	}
+}
+
+// Changed queues a recompute for users whose received feedback or tasks
+// changed
+func (s *RatingService) Changed(ctx context.Context, userIDs ...primitive.ObjectID) error {
+	return s.outbox.Publish(ctx, models.OutboxRatings, userIDs...)
+}
+
+// ProcessPending recomputes the users queued since the last pass, returning
+// how many it recomputed
+func (s *RatingService) ProcessPending(ctx context.Context) (int, error) {
+	return s.outbox.Process(ctx, models.OutboxRatings, s.Recompute)
+}
+
+// Recompute rebuilds a user's reputation from the revealed feedback they
+// have received, leaving out any a moderator hid, and their task count and
+// completion and cancellation rates from the tasks they closed as volunteer,
+// on the user and their volunteer profile
+func (s *RatingService) Recompute(ctx context.Context, userID primitive.ObjectID) error {
+	cursor, err := s.mongoClient.GetCollection("feedback").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"to_user_id": userID, "revealed_at": bson.M{"$exists": true}, "hidden": bson.M{"$ne": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "sum": bson.M{"$sum": "$rating"}, "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var totals []struct {
+		Sum   int `bson:"sum"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &totals); err != nil {
+		return err
+	}
+
+	cursor, err = s.mongoClient.GetCollection("tasks").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"volunteer_id": userID,
+			"status":       bson.M{"$in": bson.A{models.TaskStatusCompleted, models.TaskStatusCancelled}},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var counts []struct {
+		Status models.TaskStatus `bson:"_id"`
+		Count  int               `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return err
+	}
+	var completed, cancelled int
+	for _, count := range counts {
+		if count.Status == models.TaskStatusCompleted {
+			completed = count.Count
+		} else {
+			cancelled = count.Count
+		}
+	}
+
+	now := time.Now()
+	reputation := models.Reputation{UpdatedAt: now}
+	var sum float64
+	if len(totals) > 0 && totals[0].Count > 0 {
+		sum = float64(totals[0].Sum)
+		reputation.Count = totals[0].Count
+		reputation.Average = sum / float64(reputation.Count)
+	}
+	// Map the 1-5 weighted average onto 0-1
+	weighted := (reputationPrior*reputationPriorWeight + sum) / (reputationPriorWeight + float64(reputation.Count))
+	reputation.Score = (weighted - 1) / 4
+	reputation.ClosedTasks = completed + cancelled
+	if reputation.ClosedTasks > 0 {
+		reputation.CompletionRate = float64(completed) / float64(reputation.ClosedTasks)
+		reputation.CancellationRate = float64(cancelled) / float64(reputation.ClosedTasks)
+	}
+
+	if _, err := s.mongoClient.GetCollection("users").UpdateOne(ctx, bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"reputation": reputation}}); err != nil {
+		return err
+	}
+	_, err = s.mongoClient.GetCollection("volunteers").UpdateOne(ctx, bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{
+			"reputation": reputation,
+			"rating":     reputation.Average,
+			"task_count": completed,
+			"updated_at": now,
+		}})
+	return err
+}
+
+// RecomputeAll recomputes every user, repairing any drift, and returns how
+// many it recomputed
+func (s *RatingService) RecomputeAll(ctx context.Context) (int, error) {
+	cursor, err := s.mongoClient.GetCollection("users").Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	recomputed := 0
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			return recomputed, err
+		}
+		if err := s.Recompute(ctx, user.ID); err != nil {
+			return recomputed, err
+		}
+		recomputed++
+	}
+	return recomputed, cursor.Err()
+} 
\ No newline at end of file
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// recordBatchSize is how many documents each cursor round trip fetches, so
+// exports hold one batch in memory however large they are
+const recordBatchSize = 500
+
+// activityMinGroup is the least activity a day and category must have to
+// appear in the aggregate export, so rare events can't single anyone out
+const activityMinGroup = 5
+
+// RecordService streams users' records, and anonymized community activity,
+// for export
+type RecordService struct {
+	mongoClient *database.MongoClient
+}
+
+// NewRecordService creates a new record service
+func NewRecordService(mongoClient *database.MongoClient) *RecordService {
+	return &RecordService{mongoClient: mongoClient}
+}
+
+// Needs streams the needs userID created, oldest first
+func (s *RecordService) Needs(ctx context.Context, userID primitive.ObjectID, fn func(models.Need) error) error {
+	cursor, err := s.mongoClient.GetCollection("needs").Find(ctx, bson.M{"user_id": userID},
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetProjection(bson.M{"embedding": 0, "translations": 0}).
+			SetBatchSize(recordBatchSize))
+	if err != nil {
+		return err
+	}
+	return streamCursor(ctx, cursor, fn)
+}
+
+// Tasks streams the tasks userID volunteered for or asked for, oldest first
+func (s *RecordService) Tasks(ctx context.Context, userID primitive.ObjectID, fn func(models.Task) error) error {
+	needIDs, err := s.mongoClient.GetCollection("needs").Distinct(ctx, "_id", bson.M{"user_id": userID})
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"volunteer_id": userID}
+	if len(needIDs) > 0 {
+		filter = bson.M{"$or": []bson.M{filter, {"need_id": bson.M{"$in": needIDs}}}}
+	}
+
+	cursor, err := s.mongoClient.GetCollection("tasks").Find(ctx, filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetBatchSize(recordBatchSize))
+	if err != nil {
+		return err
+	}
+	return streamCursor(ctx, cursor, fn)
+}
+
+// Hours streams the tasks userID completed as a volunteer, oldest first,
+// with each need's estimated duration as the time given
+func (s *RecordService) Hours(ctx context.Context, userID primitive.ObjectID, fn func(models.VolunteerHours) error) error {
+	cursor, err := s.mongoClient.GetCollection("tasks").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"volunteer_id": userID, "status": models.TaskStatusCompleted}}},
+		{{Key: "$sort", Value: bson.D{{Key: "completed_at", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "needs", "localField": "need_id", "foreignField": "_id", "as": "need"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$need", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$project", Value: bson.M{
+			"need_id":      1,
+			"completed_at": 1,
+			"category":     "$need.category",
+			"minutes":      bson.M{"$ifNull": bson.A{"$need.duration", 0}},
+		}}},
+	}, options.Aggregate().SetBatchSize(recordBatchSize))
+	if err != nil {
+		return err
+	}
+	return streamCursor(ctx, cursor, fn)
+}
+
+// Activity streams needs created, tasks completed, and minutes volunteered
+// per day and category between from and to. Days and categories with
+// fewer than activityMinGroup needs and tasks together are left out.
+func (s *RecordService) Activity(ctx context.Context, from, to time.Time, fn func(models.ActivityAggregate) error) error {
+	day := func(field string) bson.M {
+		return bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": field}}
+	}
+	cursor, err := s.mongoClient.GetCollection("needs").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$project", Value: bson.M{
+			"day": day("$created_at"), "category": 1,
+			"needs": bson.M{"$literal": 1}, "tasks": bson.M{"$literal": 0}, "minutes": bson.M{"$literal": 0},
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": "tasks",
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"status": models.TaskStatusCompleted, "completed_at": bson.M{"$gte": from, "$lt": to}}}},
+				{{Key: "$lookup", Value: bson.M{"from": "needs", "localField": "need_id", "foreignField": "_id", "as": "need"}}},
+				{{Key: "$unwind", Value: "$need"}},
+				{{Key: "$project", Value: bson.M{
+					"day": day("$completed_at"), "category": "$need.category",
+					"needs": bson.M{"$literal": 0}, "tasks": bson.M{"$literal": 1}, "minutes": "$need.duration",
+				}}},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               bson.M{"day": "$day", "category": "$category"},
+			"needs_created":     bson.M{"$sum": "$needs"},
+			"tasks_completed":   bson.M{"$sum": "$tasks"},
+			"volunteer_minutes": bson.M{"$sum": "$minutes"},
+		}}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$gte": bson.A{bson.M{"$add": bson.A{"$needs_created", "$tasks_completed"}}, activityMinGroup}}}}},
+		{{Key: "$project", Value: bson.M{
+			"_id": 0, "day": "$_id.day", "category": "$_id.category",
+			"needs_created": 1, "tasks_completed": 1, "volunteer_minutes": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "day", Value: 1}, {Key: "category", Value: 1}}}},
+	}, options.Aggregate().SetBatchSize(recordBatchSize).SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	return streamCursor(ctx, cursor, fn)
+}
+
+// streamCursor decodes each document of cursor and hands it to fn, stopping
+// at the first error
+func streamCursor[T any](ctx context.Context, cursor *mongo.Cursor, fn func(T) error) error {
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var record T
+		if err := cursor.Decode(&record); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+} 
\ No newline at end of file
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// maxActiveInvites caps how many usable invites a user can hold at once
+const maxActiveInvites = 20
+
+// inviteCodeAlphabet leaves out characters that are easy to misread when an
+// invite code is copied by hand
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// inviteCodeLength gives 32^8, about 10^12, possible codes
+const inviteCodeLength = 8
+
+var (
+	// ErrInviteNotFound is returned for an invite that does not exist or
+	// belongs to someone else
+	ErrInviteNotFound = errors.New("invite not found")
+	// ErrInvalidInvite is returned at sign-up for a code that is unknown,
+	// revoked, expired, or used up
+	ErrInvalidInvite = errors.New("invite code is invalid or has expired")
+	// ErrTooManyInvites is returned when a user already holds
+	// maxActiveInvites usable invites
+	ErrTooManyInvites = errors.New("too many active invites; revoke one first")
+)
+
+// ReferralService issues invite codes, attributes sign-ups to the users who
+// invited them, and awards referrer badges
+type ReferralService struct {
+	mongoClient     *database.MongoClient
+	privacyService  *PrivacyService
+	linkBase        string
+	badgeThresholds []int
+}
+
+// NewReferralService creates a new referral service. Invites carry a link
+// when linkBase is set, and inviters earn a referrer badge on reaching each
+// of badgeThresholds sign-ups.
+func NewReferralService(mongoClient *database.MongoClient, privacyService *PrivacyService, linkBase string, badgeThresholds []int) *ReferralService {
+	return &ReferralService{
+		mongoClient:     mongoClient,
+		privacyService:  privacyService,
+		linkBase:        linkBase,
+		badgeThresholds: badgeThresholds,
+	}
+}
+
+// CreateInvite issues a new invite for inviter, tied to their neighborhood
+func (s *ReferralService) CreateInvite(ctx context.Context, inviter *models.User, req models.CreateInviteRequest) (*models.Invite, error) {
+	invites := s.mongoClient.GetCollection("invites")
+	now := time.Now()
+	active, err := invites.CountDocuments(ctx, usableInvites(bson.M{"inviter_id": inviter.ID}, now))
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveInvites {
+		return nil, ErrTooManyInvites
+	}
+
+	invite := models.Invite{
+		InviterID:    inviter.ID,
+		Neighborhood: s.privacyService.NeighborhoodCell(inviter.Location),
+		MaxUses:      req.MaxUses,
+		CreatedAt:    now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	// Retry the rare clash with an existing code
+	for attempt := 0; ; attempt++ {
+		invite.ID = primitive.NewObjectID()
+		if invite.Code, err = newInviteCode(); err != nil {
+			return nil, err
+		}
+		_, err = invites.InsertOne(ctx, invite)
+		if err == nil || !mongo.IsDuplicateKeyError(err) || attempt == 2 {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	invite.Link = s.link(invite.Code)
+	return &invite, nil
+}
+
+// ListInvites returns a user's invites, newest first
+func (s *ReferralService) ListInvites(ctx context.Context, inviterID primitive.ObjectID, limit, offset int64) ([]models.Invite, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := s.mongoClient.GetCollection("invites").Find(ctx, bson.M{"inviter_id": inviterID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	invites := []models.Invite{}
+	if err := cursor.All(ctx, &invites); err != nil {
+		return nil, err
+	}
+	for i := range invites {
+		invites[i].Link = s.link(invites[i].Code)
+	}
+	return invites, nil
+}
+
+// RevokeInvite stops one of the inviter's invites from being used again
+func (s *ReferralService) RevokeInvite(ctx context.Context, inviteID, inviterID primitive.ObjectID) error {
+	result, err := s.mongoClient.GetCollection("invites").UpdateOne(ctx,
+		bson.M{"_id": inviteID, "inviter_id": inviterID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInviteNotFound
+	}
+	return nil
+}
+
+// LookupInvite returns the usable invite with code, so sign-ups with a bad
+// code can be turned away before the account is created
+func (s *ReferralService) LookupInvite(ctx context.Context, code string) (*models.Invite, error) {
+	var invite models.Invite
+	err := s.mongoClient.GetCollection("invites").FindOne(ctx,
+		usableInvites(bson.M{"code": normalizeInviteCode(code)}, time.Now())).Decode(&invite)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// Attribute records that user signed up through invite, counting the use
+// and awarding the inviter any badge they have now earned. An invite used
+// up since it was looked up is not counted.
+func (s *ReferralService) Attribute(ctx context.Context, invite *models.Invite, user *models.User) error {
+	now := time.Now()
+	filter := usableInvites(bson.M{"_id": invite.ID}, now)
+	result, err := s.mongoClient.GetCollection("invites").UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"uses": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInvalidInvite
+	}
+
+	referral := models.Referral{
+		ID:           primitive.NewObjectID(),
+		InviteID:     invite.ID,
+		InviterID:    invite.InviterID,
+		ReferredID:   user.ID,
+		Neighborhood: invite.Neighborhood,
+		CreatedAt:    now,
+	}
+	if _, err := s.mongoClient.GetCollection("referrals").InsertOne(ctx, referral); err != nil {
+		return err
+	}
+
+	return s.awardBadges(ctx, invite.InviterID)
+}
+
+// awardBadges gives the inviter a referrer badge for each threshold their
+// sign-ups have reached that they do not already hold
+func (s *ReferralService) awardBadges(ctx context.Context, inviterID primitive.ObjectID) error {
+	if len(s.badgeThresholds) == 0 {
+		return nil
+	}
+	signUps, err := s.mongoClient.GetCollection("referrals").CountDocuments(ctx, bson.M{"inviter_id": inviterID})
+	if err != nil {
+		return err
+	}
+
+	return awardBadges(ctx, s.mongoClient, inviterID, models.BadgeReferrer, signUps, s.badgeThresholds)
+}
+
+// Stats returns a user's active invites, referred sign-ups, and badges
+func (s *ReferralService) Stats(ctx context.Context, user *models.User) (*models.ReferralStats, error) {
+	active, err := s.mongoClient.GetCollection("invites").CountDocuments(ctx, usableInvites(bson.M{"inviter_id": user.ID}, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	signUps, err := s.mongoClient.GetCollection("referrals").CountDocuments(ctx, bson.M{"inviter_id": user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ReferralStats{
+		ActiveInvites: int(active),
+		SignUps:       signUps,
+		NextBadgeAt:   nextBadgeAt(signUps, s.badgeThresholds),
+		Badges:        []models.Badge{},
+	}
+	for _, badge := range user.Badges {
+		if badge.Kind == models.BadgeReferrer {
+			stats.Badges = append(stats.Badges, badge)
+		}
+	}
+	return stats, nil
+}
+
+// NeighborhoodReferrals counts referred sign-ups and distinct referrers per
+// neighborhood, most sign-ups first
+func (s *ReferralService) NeighborhoodReferrals(ctx context.Context, since time.Time, limit int64) ([]models.NeighborhoodReferrals, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": since}, "neighborhood": bson.M{"$ne": ""}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$neighborhood",
+			"sign_ups":  bson.M{"$sum": 1},
+			"referrers": bson.M{"$addToSet": "$inviter_id"},
+		}}},
+		{{Key: "$set", Value: bson.M{"referrers": bson.M{"$size": "$referrers"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "sign_ups", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := s.mongoClient.GetCollection("referrals").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	results := []models.NeighborhoodReferrals{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// link builds the sign-up link for an invite code
+func (s *ReferralService) link(code string) string {
+	if s.linkBase == "" {
+		return ""
+	}
+	separator := "?"
+	if strings.Contains(s.linkBase, "?") {
+		separator = "&"
+	}
+	return s.linkBase + separator + url.Values{"invite": {code}}.Encode()
+}
+
+// usableInvites adds the conditions for an invite that can still be used to
+// filter
+func usableInvites(filter bson.M, now time.Time) bson.M {
+	filter["revoked_at"] = bson.M{"$exists": false}
+	filter["$and"] = []bson.M{
+		{"$or": []bson.M{{"expires_at": bson.M{"$exists": false}}, {"expires_at": bson.M{"$gt": now}}}},
+		{"$or": []bson.M{{"max_uses": bson.M{"$exists": false}}, {"$expr": bson.M{"$lt": bson.A{"$uses", "$max_uses"}}}}},
+	}
+	return filter
+}
+
+// newInviteCode returns a random invite code
+func newInviteCode() (string, error) {
+	raw := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := make([]byte, inviteCodeLength)
+	for i, b := range raw {
+		// 256 is a multiple of the 32-character alphabet, so this is unbiased
+		code[i] = inviteCodeAlphabet[int(b)%len(inviteCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// normalizeInviteCode makes codes typed by hand match regardless of case
+// and surrounding space
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+} 
\ No newline at end of file
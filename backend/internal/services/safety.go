@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"neighborenexus/internal/models"
+)
+
+// Errors returned when minor-safety rules block an action
+var (
+	ErrSensitiveCategoryForMinor = fmt.Errorf("users under %d cannot take part in %s tasks", models.AdultAge, strings.Join(models.SensitiveCategories(), ", "))
+	ErrSupervisionUnverified     = errors.New("youth group accounts need a verified supervising adult before posting or accepting needs")
+)
+
+// CheckTaskEligibility reports whether user may post or take on a need in
+// category. Minors are kept out of one-to-one tasks in sensitive categories,
+// and youth groups out of every task until their supervisor is verified.
+func CheckTaskEligibility(user *models.User, category models.Category, now time.Time) error {
+	if user.AccountType == models.AccountYouthGroup && !user.Supervision.Verified() {
+		return ErrSupervisionUnverified
+	}
+	if category.Sensitive() && user.IsMinor(now) {
+		return ErrSensitiveCategoryForMinor
+	}
+	return nil
+}
+
+// ineligibleUsersFilter matches the users CheckTaskEligibility rejects for
+// category
+func ineligibleUsersFilter(category models.Category, now time.Time) bson.M {
+	rules := []bson.M{{
+		"account_type":            models.AccountYouthGroup,
+		"supervision.verified_at": bson.M{"$exists": false},
+	}}
+	if category.Sensitive() {
+		rules = append(rules,
+			bson.M{"account_type": models.AccountYouthGroup},
+			bson.M{"date_of_birth": bson.M{"$gt": now.AddDate(-models.AdultAge, 0, 0)}},
+		)
+	}
+	return bson.M{"$or": rules}
+}
+
+// registrationSafety validates the age and supervision details of a
+// registration and returns the date of birth and supervision to store
+func registrationSafety(req models.RegisterRequest, now time.Time) (*time.Time, *models.AdultSupervision, error) {
+	if req.AccountType == models.AccountYouthGroup {
+		if req.SupervisorName == "" || req.SupervisorEmail == "" {
+			return nil, nil, errors.New("youth group accounts must name a supervising adult with supervisor_name and supervisor_email")
+		}
+		return nil, &models.AdultSupervision{
+			SupervisorName:  req.SupervisorName,
+			SupervisorEmail: req.SupervisorEmail,
+		}, nil
+	}
+
+	if req.DateOfBirth == "" {
+		return nil, nil, errors.New("date_of_birth is required")
+	}
+	dateOfBirth, err := ParseDateOfBirth(req.DateOfBirth, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &dateOfBirth, nil, nil
+}
+
+// ParseDateOfBirth parses a YYYY-MM-DD date of birth and checks that the user
+// is old enough to register
+func ParseDateOfBirth(value string, now time.Time) (time.Time, error) {
+	dateOfBirth, err := time.Parse(models.DateOfBirthLayout, value)
+	if err != nil {
+		return time.Time{}, errors.New("date_of_birth must be a date in YYYY-MM-DD format")
+	}
+	user := models.User{DateOfBirth: &dateOfBirth}
+	if age, _ := user.Age(now); age < models.MinimumAge {
+		return time.Time{}, fmt.Errorf("users must be at least %d years old to register", models.MinimumAge)
+	}
+	return dateOfBirth, nil
+} 
\ No newline at end of file
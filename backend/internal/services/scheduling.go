@@ -0,0 +1,73 @@
+package services
+
+import (
+	"time"
+
+	"neighborenexus/internal/models"
+)
+
+// schedulingHorizons are how soon needs without a time window should be
+// done, by urgency
+var schedulingHorizons = map[models.Urgency]time.Duration{
+	models.UrgencyHigh:   24 * time.Hour,
+	models.UrgencyMedium: 3 * 24 * time.Hour,
+	models.UrgencyLow:    7 * 24 * time.Hour,
+}
+
+// defaultNeedDuration is assumed for needs without a duration estimate
+const defaultNeedDuration = time.Hour
+
+// availabilityFloor is the weight of a volunteer with some time in a need's
+// horizon that suits it as poorly as possible
+const availabilityFloor = 0.5
+
+// needHorizon returns when a need should be done: its time window, or from
+// now until its urgency's horizon
+func needHorizon(need *models.Need, now time.Time) (time.Time, time.Time) {
+	if need.Window != nil {
+		return need.Window.Start, need.Window.End
+	}
+	horizon, ok := schedulingHorizons[need.Urgency]
+	if !ok {
+		horizon = schedulingHorizons[models.UrgencyMedium]
+	}
+	return now, now.Add(horizon)
+}
+
+// availabilityWeight scores how well a volunteer's availability suits a
+// need, from availabilityFloor to 1. A free block as long as the need's
+// duration gets full weight, shorter blocks less; for needs without a time
+// window, blocks opening later in the horizon get less too. It reports
+// false when the volunteer has no time in the horizon at all.
+func availabilityWeight(volunteer *models.Volunteer, need *models.Need, now time.Time) (float64, bool) {
+	start, end := needHorizon(need, now)
+	windows := volunteer.AvailableWindows(start, end)
+	if len(windows) == 0 {
+		return 0, false
+	}
+
+	duration := time.Duration(need.Duration) * time.Minute
+	if duration <= 0 {
+		duration = defaultNeedDuration
+	}
+
+	// The earliest block that fits the need, or failing that the longest
+	best, fit := windows[0], 0.0
+	for _, window := range windows {
+		length := window.End.Sub(window.Start)
+		if length >= duration {
+			best, fit = window, 1
+			break
+		}
+		if f := float64(length) / float64(duration); f > fit {
+			best, fit = window, f
+		}
+	}
+
+	// Urgent needs are better served by volunteers free sooner
+	soon := 1.0
+	if need.Window == nil {
+		soon = 1 - float64(best.Start.Sub(start))/float64(end.Sub(start))
+	}
+	return availabilityFloor + (1-availabilityFloor)*fit*soon, true
+} 
\ No newline at end of file
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"container/heap"
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	"neighborenexus/internal/models"
+)
+
+// minScoringChunk is the fewest candidates worth handing a worker of their
+// own; below it goroutine overhead outweighs the scoring
+const minScoringChunk = 256
+
+// scoringCheckEvery is how many candidates a worker scores between checks
+// for a cancelled request
+const scoringCheckEvery = 64
+
+// scoringWorkers bounds how many goroutines one matching call scores with
+var scoringWorkers = runtime.GOMAXPROCS(0)
+
+// matchRanking reports whether match a ranks above match b
+type matchRanking func(a, b models.Match) bool
+
+// byScore ranks matches by score alone
+func byScore(a, b models.Match) bool { return a.Score > b.Score }
+
+// matchHeap is a min-heap of matches by rank, so the worst of the best k
+// seen so far is the one evicted
+type matchHeap struct {
+	matches []models.Match
+	ranks   matchRanking
+}
+
+func (h *matchHeap) Len() int           { return len(h.matches) }
+func (h *matchHeap) Less(i, j int) bool { return h.ranks(h.matches[j], h.matches[i]) }
+func (h *matchHeap) Swap(i, j int)      { h.matches[i], h.matches[j] = h.matches[j], h.matches[i] }
+func (h *matchHeap) Push(x interface{}) { h.matches = append(h.matches, x.(models.Match)) }
+func (h *matchHeap) Pop() interface{} {
+	last := h.matches[len(h.matches)-1]
+	h.matches = h.matches[:len(h.matches)-1]
+	return last
+}
+
+// offer keeps match if it is among the best k seen so far
+func (h *matchHeap) offer(match models.Match, k int) {
+	if h.Len() < k {
+		heap.Push(h, match)
+		return
+	}
+	if h.ranks(match, h.matches[0]) {
+		h.matches[0] = match
+		heap.Fix(h, 0)
+	}
+}
+
+// scoreTopK scores n candidates across a bounded pool of workers and
+// returns the best k matches, best first. score is called concurrently and
+// returns false for candidates that do not match. Each worker keeps only
+// its own best k, so memory stays bounded however many candidates match.
+func scoreTopK(ctx context.Context, n, k int, ranks matchRanking, score func(i int) (models.Match, bool)) ([]models.Match, error) {
+	workers := max(min(scoringWorkers, (n+minScoringChunk-1)/minScoringChunk), 1)
+	chunk := (n + workers - 1) / workers
+
+	heaps := make([]*matchHeap, workers)
+	var wg sync.WaitGroup
+	for w := range heaps {
+		heaps[w] = &matchHeap{ranks: ranks}
+		start, end := w*chunk, min((w+1)*chunk, n)
+		wg.Add(1)
+		go func(h *matchHeap) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if (i-start)%scoringCheckEvery == 0 && ctx.Err() != nil {
+					return
+				}
+				if match, ok := score(i); ok {
+					h.offer(match, k)
+				}
+			}
+		}(heaps[w])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var matches []models.Match
+	for _, h := range heaps {
+		matches = append(matches, h.matches...)
+	}
+	sort.Slice(matches, func(i, j int) bool { return ranks(matches[i], matches[j]) })
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+} 
\ No newline at end of file
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// refreshTokenLifetime is how long a session lasts without its refresh
+// token being used
+const refreshTokenLifetime = 7 * 24 * time.Hour
+
+// maxUserAgentLength bounds the user agent kept with a session
+const maxUserAgentLength = 256
+
+// ErrSessionNotFound is returned for sessions that have ended or belong to
+// someone else
+var ErrSessionNotFound = errors.New("session not found")
+
+// errRefreshTokenReused is returned when a rotated-out refresh token is
+// presented again, which ends the session since the token may have leaked
+var errRefreshTokenReused = errors.New("refresh token reused")
+
+// sessionRecord is a session as stored in Redis, with the ID of the only
+// refresh token that may continue it
+type sessionRecord struct {
+	models.Session
+	UserID  string `json:"user_id"`
+	TokenID string `json:"token_id"`
+}
+
+// SessionStore tracks signed-in sessions in Redis so refresh tokens can be
+// rotated and revoked. Each session expires with its refresh token.
+type SessionStore struct {
+	redisClient *database.RedisClient
+}
+
+// NewSessionStore creates a session store
+func NewSessionStore(redisClient *database.RedisClient) *SessionStore {
+	return &SessionStore{redisClient: redisClient}
+}
+
+func sessionKey(sessionID string) string { return "session:" + sessionID }
+
+func userSessionsKey(userID string) string { return "sessions:" + userID }
+
+// Start begins a session for userID and returns it with the ID of its first
+// refresh token
+func (s *SessionStore) Start(ctx context.Context, userID string, client models.SessionClient) (models.Session, string, error) {
+	now := time.Now()
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	record := &sessionRecord{
+		Session: models.Session{
+			ID:         uuid.New().String(),
+			UserAgent:  userAgent,
+			IP:         client.IP,
+			CreatedAt:  now,
+			LastUsedAt: now,
+			ExpiresAt:  now.Add(refreshTokenLifetime),
+		},
+		UserID:  userID,
+		TokenID: uuid.New().String(),
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return models.Session{}, "", err
+	}
+	if err := s.redisClient.Set(ctx, sessionKey(record.ID), data, refreshTokenLifetime); err != nil {
+		return models.Session{}, "", err
+	}
+	if _, err := s.redisClient.AddToSet(ctx, userSessionsKey(userID), record.ID, refreshTokenLifetime); err != nil {
+		return models.Session{}, "", err
+	}
+	return record.Session, record.TokenID, nil
+}
+
+// Rotate moves a session on to a new refresh token, extending it, and
+// returns the new token's ID. tokenID must be the session's current token:
+// presenting an earlier one, or racing another rotation of the same token,
+// revokes the session.
+func (s *SessionStore) Rotate(ctx context.Context, userID, sessionID, tokenID string, client models.SessionClient) (string, error) {
+	raw, record, err := s.load(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+	if record.UserID != userID {
+		return "", ErrSessionNotFound
+	}
+	if record.TokenID != tokenID {
+		s.Revoke(ctx, userID, sessionID)
+		return "", errRefreshTokenReused
+	}
+
+	now := time.Now()
+	record.TokenID = uuid.New().String()
+	record.LastUsedAt = now
+	record.ExpiresAt = now.Add(refreshTokenLifetime)
+	if client.IP != "" {
+		record.IP = client.IP
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	rotated, err := s.redisClient.CompareAndSet(ctx, sessionKey(sessionID), raw, string(data), refreshTokenLifetime)
+	if err != nil {
+		return "", err
+	}
+	if !rotated {
+		s.Revoke(ctx, userID, sessionID)
+		return "", errRefreshTokenReused
+	}
+	if _, err := s.redisClient.AddToSet(ctx, userSessionsKey(userID), sessionID, refreshTokenLifetime); err != nil {
+		return "", err
+	}
+	return record.TokenID, nil
+}
+
+// Active reports whether a session has not ended
+func (s *SessionStore) Active(ctx context.Context, sessionID string) (bool, error) {
+	return s.redisClient.Exists(ctx, sessionKey(sessionID))
+}
+
+// List returns a user's active sessions, most recently used first
+func (s *SessionStore) List(ctx context.Context, userID string) ([]models.Session, error) {
+	sessionIDs, err := s.redisClient.SetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []models.Session{}
+	var ended []string
+	for _, sessionID := range sessionIDs {
+		_, record, err := s.load(ctx, sessionID)
+		if errors.Is(err, ErrSessionNotFound) {
+			ended = append(ended, sessionID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, record.Session)
+	}
+	if len(ended) > 0 {
+		s.redisClient.RemoveFromSet(ctx, userSessionsKey(userID), ended...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt) })
+	return sessions, nil
+}
+
+// Revoke ends one of a user's sessions
+func (s *SessionStore) Revoke(ctx context.Context, userID, sessionID string) error {
+	_, record, err := s.load(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if record.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if err := s.redisClient.Del(ctx, sessionKey(sessionID)); err != nil {
+		return err
+	}
+	return s.redisClient.RemoveFromSet(ctx, userSessionsKey(userID), sessionID)
+}
+
+// RevokeAll ends every session of a user except keep, which may be empty,
+// returning how many were ended
+func (s *SessionStore) RevokeAll(ctx context.Context, userID, keep string) (int, error) {
+	sessionIDs, err := s.redisClient.SetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, sessionID := range sessionIDs {
+		if sessionID == keep {
+			continue
+		}
+		err := s.Revoke(ctx, userID, sessionID)
+		if errors.Is(err, ErrSessionNotFound) {
+			continue
+		}
+		if err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	if keep == "" {
+		s.redisClient.Del(ctx, userSessionsKey(userID))
+	}
+	return revoked, nil
+}
+
+// load reads a session, returning its stored form for CompareAndSet
+func (s *SessionStore) load(ctx context.Context, sessionID string) (string, *sessionRecord, error) {
+	raw, err := s.redisClient.Get(ctx, sessionKey(sessionID))
+	if err == redis.Nil {
+		return "", nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	var record sessionRecord
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return "", nil, err
+	}
+	return raw, &record, nil
+} 
\ No newline at end of file
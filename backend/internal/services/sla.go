@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+)
+
+// slaBatchSize bounds the needs checked against each target per pass
+const slaBatchSize = 200
+
+// SLATargets are how long after being posted urgent needs should reach each
+// milestone. A zero target is not tracked.
+type SLATargets struct {
+	FirstMatch       time.Duration
+	Accepted         time.Duration
+	Completed        time.Duration
+	EscalationRadius float64 // stretches the matching distance of needs that miss a target
+}
+
+// For returns the target for milestone
+func (t SLATargets) For(milestone models.SLAMilestone) time.Duration {
+	switch milestone {
+	case models.MilestoneFirstMatch:
+		return t.FirstMatch
+	case models.MilestoneAccepted:
+		return t.Accepted
+	case models.MilestoneCompleted:
+		return t.Completed
+	}
+	return 0
+}
+
+// SLAService records when needs reach each milestone and escalates urgent
+// needs that miss their targets: matching reaches further, the volunteers
+// it finds are notified, and coordinators are alerted
+type SLAService struct {
+	mongoClient *database.MongoClient
+	matching    *MatchingService
+	privacy     *PrivacyService
+	analytics   *AnalyticsService
+	settings    *settings.Store
+	notify      Notifier
+	targets     SLATargets
+}
+
+// NewSLAService creates an SLA service holding urgent needs to targets
+func NewSLAService(mongoClient *database.MongoClient, matchingService *MatchingService, privacyService *PrivacyService, analyticsService *AnalyticsService, settingsStore *settings.Store, notify Notifier, targets SLATargets) *SLAService {
+	return &SLAService{
+		mongoClient: mongoClient,
+		matching:    matchingService,
+		privacy:     privacyService,
+		analytics:   analyticsService,
+		settings:    settingsStore,
+		notify:      notify,
+		targets:     targets,
+	}
+}
+
+// Record records that a need reached milestone at, keeping the first time
+// it did. SLA tracking is best-effort, so failures are logged.
+func (s *SLAService) Record(ctx context.Context, needID primitive.ObjectID, milestone models.SLAMilestone, at time.Time) {
+	_, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx,
+		bson.M{"_id": needID, milestone.Field(): bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{milestone.Field(): at}})
+	if err != nil {
+		log.Printf("Failed to record %s for need %s: %v", milestone, needID.Hex(), err)
+	}
+}
+
+// CheckBreaches finds urgent needs that have missed a target since the last
+// check and escalates them, returning how many breaches it found
+func (s *SLAService) CheckBreaches(ctx context.Context) (int, error) {
+	now := time.Now()
+	breaches := 0
+	for _, milestone := range models.SLAMilestones {
+		target := s.targets.For(milestone)
+		if target <= 0 {
+			continue
+		}
+
+		filter := bson.M{
+			"urgency":         models.UrgencyHigh,
+			"status":          bson.M{"$nin": []models.NeedStatus{models.NeedStatusCompleted, models.NeedStatusCancelled, models.NeedStatusExpired}},
+			"hidden":          bson.M{"$ne": true},
+			"held_for_review": bson.M{"$ne": true},
+			"created_at":      bson.M{"$lte": now.Add(-target)},
+			milestone.Field(): bson.M{"$exists": false},
+			"sla.breached":    bson.M{"$ne": milestone},
+			"$or":             []bson.M{{"expires_at": bson.M{"$exists": false}}, {"expires_at": bson.M{"$gt": now}}},
+		}
+		cursor, err := s.mongoClient.GetCollection("needs").Find(ctx, filter,
+			options.Find().SetSort(bson.M{"created_at": 1}).SetLimit(slaBatchSize).SetProjection(bson.M{"translations": 0}))
+		if err != nil {
+			return breaches, fmt.Errorf("failed to find needs past their %s target: %w", milestone, err)
+		}
+		var needs []models.Need
+		if err := cursor.All(ctx, &needs); err != nil {
+			return breaches, fmt.Errorf("failed to read needs past their %s target: %w", milestone, err)
+		}
+
+		for i := range needs {
+			escalated, err := s.breach(ctx, &needs[i], milestone, now)
+			if err != nil {
+				return breaches, err
+			}
+			if escalated {
+				breaches++
+			}
+		}
+	}
+	return breaches, nil
+}
+
+// breach marks a need as having missed milestone and escalates it,
+// reporting false when another worker got there first
+func (s *SLAService) breach(ctx context.Context, need *models.Need, milestone models.SLAMilestone, now time.Time) (bool, error) {
+	result, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx,
+		bson.M{"_id": need.ID, "sla.breached": bson.M{"$ne": milestone}},
+		bson.M{"$addToSet": bson.M{"sla.breached": milestone}})
+	if err != nil {
+		return false, fmt.Errorf("failed to record %s breach of need %s: %w", milestone, need.ID.Hex(), err)
+	}
+	if result.ModifiedCount == 0 {
+		return false, nil
+	}
+
+	overdue := now.Sub(need.CreatedAt.Add(s.targets.For(milestone)))
+	s.analytics.SLABreached(ctx, need, milestone, overdue)
+
+	// A need nobody has taken on yet is offered to volunteers further away,
+	// once; one already taken on only needs a coordinator's attention
+	if milestone != models.MilestoneCompleted {
+		s.widen(ctx, need, now)
+	}
+	s.alertCoordinators(ctx, need, milestone, overdue)
+	return true, nil
+}
+
+// widen stretches a need's matching distance the first time it is escalated
+// and notifies the volunteers that now match. Failures are logged, since the
+// breach is already recorded.
+func (s *SLAService) widen(ctx context.Context, need *models.Need, now time.Time) {
+	result, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx,
+		bson.M{"_id": need.ID, "sla.escalated_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"sla.escalated_at": now, "sla.radius_multiplier": s.targets.EscalationRadius}})
+	if err != nil {
+		log.Printf("Failed to escalate need %s: %v", need.ID.Hex(), err)
+		return
+	}
+	if result.ModifiedCount == 0 || need.Status != models.NeedStatusRequested {
+		return
+	}
+	if len(need.Embedding) == 0 {
+		if err := s.matching.UpdateNeedEmbedding(ctx, need); err != nil {
+			log.Printf("Failed to embed escalated need %s: %v", need.ID.Hex(), err)
+			return
+		}
+	}
+	if need.SLA == nil {
+		need.SLA = &models.NeedSLA{}
+	}
+	need.SLA.EscalatedAt = &now
+	need.SLA.RadiusMultiplier = s.targets.EscalationRadius
+
+	matches, err := s.matching.FindMatchesForNeed(ctx, need, s.settings.Get(ctx).NotificationFanout)
+	if err != nil {
+		log.Printf("Failed to rematch escalated need %s: %v", need.ID.Hex(), err)
+		return
+	}
+	recipients, err := s.matching.Recipients(ctx, matches)
+	if err != nil {
+		log.Printf("Failed to look up volunteers for escalated need %s: %v", need.ID.Hex(), err)
+		return
+	}
+	s.analytics.MatchesShown(ctx, need, matches, MatchSurfaceEscalation)
+	for _, recipient := range recipients {
+		if err := s.notify(ctx, []string{recipient.UserID}, NewNeedMessage(*need, recipient)); err != nil {
+			log.Printf("Failed to notify volunteer of escalated need %s: %v", need.ID.Hex(), err)
+		}
+	}
+	s.matching.MarkNotified(ctx, need.ID, recipients)
+	if len(matches) > 0 {
+		s.Record(ctx, need.ID, models.MilestoneFirstMatch, now)
+	}
+}
+
+// alertCoordinators tells the verified organizers of the need's
+// neighborhood, and every admin, that it missed a target
+func (s *SLAService) alertCoordinators(ctx context.Context, need *models.Need, milestone models.SLAMilestone, overdue time.Duration) {
+	cursor, err := s.mongoClient.GetCollection("users").Find(ctx,
+		bson.M{"$or": []bson.M{{"organizer": bson.M{"$exists": true}}, {"role": models.RoleAdmin}}},
+		options.Find().SetProjection(bson.M{"location": 1, "role": 1, "organizer": 1}))
+	if err != nil {
+		log.Printf("Failed to look up coordinators for need %s: %v", need.ID.Hex(), err)
+		return
+	}
+	var coordinators []models.User
+	if err := cursor.All(ctx, &coordinators); err != nil {
+		log.Printf("Failed to look up coordinators for need %s: %v", need.ID.Hex(), err)
+		return
+	}
+
+	neighborhood := s.privacy.NeighborhoodCell(need.Location)
+	var userIDs []string
+	for _, coordinator := range coordinators {
+		if coordinator.HasRole(models.RoleAdmin) || neighborhood != "" && s.privacy.NeighborhoodCell(coordinator.Location) == neighborhood {
+			userIDs = append(userIDs, coordinator.ID.Hex())
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	message := models.WebSocketMessage{
+		Type: "need_sla_breached",
+		Payload: map[string]interface{}{
+			"need_id":         need.ID.Hex(),
+			"category":        need.Category,
+			"urgency":         need.Urgency,
+			"milestone":       milestone,
+			"minutes_overdue": int(overdue.Minutes()),
+			"neighborhood":    neighborhood,
+		},
+	}
+	if err := s.notify(ctx, userIDs, message); err != nil {
+		log.Printf("Failed to alert coordinators of need %s: %v", need.ID.Hex(), err)
+	}
+}
+
+// Metrics summarizes how quickly needs created between from and to reached
+// each milestone, by urgency. Only urgent needs have targets and breaches.
+func (s *SLAService) Metrics(ctx context.Context, from, to time.Time) (*models.SLAMetrics, error) {
+	cursor, err := s.mongoClient.GetCollection("needs").Find(ctx,
+		bson.M{"created_at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetProjection(bson.M{"urgency": 1, "created_at": 1, "sla": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type urgencyTotals struct {
+		needs     int
+		escalated int
+		minutes   map[models.SLAMilestone][]float64
+		breached  map[models.SLAMilestone]int
+	}
+	totals := map[models.Urgency]*urgencyTotals{}
+	for cursor.Next(ctx) {
+		var need models.Need
+		if err := cursor.Decode(&need); err != nil {
+			return nil, err
+		}
+		t, ok := totals[need.Urgency]
+		if !ok {
+			t = &urgencyTotals{minutes: map[models.SLAMilestone][]float64{}, breached: map[models.SLAMilestone]int{}}
+			totals[need.Urgency] = t
+		}
+		t.needs++
+		if need.SLA == nil {
+			continue
+		}
+		if need.SLA.EscalatedAt != nil {
+			t.escalated++
+		}
+		for _, milestone := range models.SLAMilestones {
+			if at := need.SLA.Reached(milestone); at != nil {
+				t.minutes[milestone] = append(t.minutes[milestone], at.Sub(need.CreatedAt).Minutes())
+			}
+		}
+		for _, milestone := range need.SLA.Breached {
+			t.breached[milestone]++
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	metrics := &models.SLAMetrics{From: from, To: to, Urgencies: []models.UrgencySLAMetrics{}}
+	for _, urgency := range []models.Urgency{models.UrgencyHigh, models.UrgencyMedium, models.UrgencyLow} {
+		t, ok := totals[urgency]
+		if !ok {
+			continue
+		}
+		summary := models.UrgencySLAMetrics{Urgency: urgency, Needs: t.needs, Escalated: t.escalated}
+		for _, milestone := range models.SLAMilestones {
+			minutes := t.minutes[milestone]
+			sort.Float64s(minutes)
+			milestoneMetrics := models.MilestoneMetrics{
+				Milestone:     milestone,
+				Reached:       len(minutes),
+				MedianMinutes: percentile(minutes, 0.5),
+				P90Minutes:    percentile(minutes, 0.9),
+				Breached:      t.breached[milestone],
+			}
+			if urgency == models.UrgencyHigh {
+				milestoneMetrics.TargetMinutes = s.targets.For(milestone).Minutes()
+			}
+			summary.Milestones = append(summary.Milestones, milestoneMetrics)
+		}
+		metrics.Urgencies = append(metrics.Urgencies, summary)
+	}
+	return metrics, nil
+}
+
+// percentile returns the p-th percentile of sorted values, rounded to a
+// tenth, or 0 when there are none
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return math.Round(sorted[max(index, 0)]*10) / 10
+} 
\ No newline at end of file
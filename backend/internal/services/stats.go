@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// timeToMatchBounds are the boundaries, in minutes, of the time-to-match
+// buckets: 15 minutes, an hour, four hours, a day, and three days. Needs
+// matched later share a last, open bucket.
+var timeToMatchBounds = []float64{0, 15, 60, 240, 1440, 4320}
+
+// StatsService aggregates platform-wide statistics for the admin dashboard
+type StatsService struct {
+	mongoClient *database.MongoClient
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(mongoClient *database.MongoClient) *StatsService {
+	return &StatsService{mongoClient: mongoClient}
+}
+
+// Overview counts open needs now, and the volunteers active, tasks
+// completed, and matches suggested between from and to
+func (s *StatsService) Overview(ctx context.Context, from, to time.Time) (*models.PlatformStats, error) {
+	stats := &models.PlatformStats{From: from, To: to, OpenNeedsByUrgency: map[models.Urgency]int{}}
+	period := bson.M{"$gte": from, "$lt": to}
+
+	var urgencies []struct {
+		Urgency models.Urgency `bson:"_id"`
+		Count   int            `bson:"count"`
+	}
+	err := s.aggregate(ctx, "needs", mongo.Pipeline{
+		{{Key: "$match", Value: openNeedsFilter()}},
+		{{Key: "$group", Value: bson.M{"_id": "$urgency", "count": bson.M{"$sum": 1}}}},
+	}, &urgencies)
+	if err != nil {
+		return nil, err
+	}
+	for _, urgency := range urgencies {
+		stats.OpenNeeds += urgency.Count
+		stats.OpenNeedsByUrgency[urgency.Urgency] = urgency.Count
+	}
+
+	if stats.ActiveVolunteers, err = s.count(ctx, "tasks", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": []bson.M{{"created_at": period}, {"completed_at": period}}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$volunteer_id"}}},
+	}); err != nil {
+		return nil, err
+	}
+	if stats.CompletedTasks, err = s.count(ctx, "tasks", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": models.TaskStatusCompleted, "completed_at": period}}},
+	}); err != nil {
+		return nil, err
+	}
+
+	var scores []struct {
+		Count    int     `bson:"count"`
+		Average  float64 `bson:"average"`
+		Accepted int     `bson:"accepted"`
+	}
+	err = s.aggregate(ctx, "matches", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": period}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"count":   bson.M{"$sum": 1},
+			"average": bson.M{"$avg": "$score"},
+			"accepted": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$state", models.MatchStateAccepted}}, 1, 0,
+			}}},
+		}}},
+	}, &scores)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) > 0 && scores[0].Count > 0 {
+		stats.Matches = models.MatchScoreSummary{
+			Count:        scores[0].Count,
+			AverageScore: math.Round(scores[0].Average*1000) / 1000,
+			AcceptedRate: math.Round(float64(scores[0].Accepted)/float64(scores[0].Count)*1000) / 1000,
+		}
+	}
+	return stats, nil
+}
+
+// TimeToMatch buckets the needs created between from and to by the minutes
+// from posting to their first match, optionally for one urgency
+func (s *StatsService) TimeToMatch(ctx context.Context, from, to time.Time, urgency models.Urgency) (*models.TimeToMatchStats, error) {
+	filter := bson.M{"created_at": bson.M{"$gte": from, "$lt": to}}
+	if urgency != "" {
+		filter["urgency"] = urgency
+	}
+	var results []struct {
+		Matched []struct {
+			Bucket interface{} `bson:"_id"`
+			Count  int         `bson:"count"`
+		} `bson:"matched"`
+		Unmatched []struct {
+			Count int `bson:"count"`
+		} `bson:"unmatched"`
+	}
+	err := s.aggregate(ctx, "needs", mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.M{
+			"matched": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"sla.first_match_at": bson.M{"$type": "date"}}}},
+				{{Key: "$project", Value: bson.M{"minutes": bson.M{"$divide": bson.A{
+					bson.M{"$subtract": bson.A{"$sla.first_match_at", "$created_at"}}, 60000,
+				}}}}},
+				{{Key: "$bucket", Value: bson.M{
+					"groupBy":    bson.M{"$max": bson.A{"$minutes", 0.0}},
+					"boundaries": timeToMatchBounds,
+					"default":    "later",
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}}},
+			},
+			"unmatched": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"sla.first_match_at": bson.M{"$not": bson.M{"$type": "date"}}}}},
+				{{Key: "$count", Value: "count"}},
+			},
+		}}},
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.TimeToMatchStats{From: from, To: to, Urgency: urgency}
+	for i, lower := range timeToMatchBounds {
+		bucket := models.TimeToMatchBucket{MinMinutes: lower}
+		if i+1 < len(timeToMatchBounds) {
+			upper := timeToMatchBounds[i+1]
+			bucket.MaxMinutes = &upper
+		}
+		stats.Buckets = append(stats.Buckets, bucket)
+	}
+	if len(results) > 0 {
+		// $bucket names each bucket by its lower boundary, and the open one
+		// by its default
+		for _, bucket := range results[0].Matched {
+			index := len(timeToMatchBounds) - 1
+			if lower, ok := bucket.Bucket.(float64); ok {
+				index = sort.SearchFloat64s(timeToMatchBounds, lower)
+			}
+			stats.Buckets[index].Needs += bucket.Count
+			stats.Matched += bucket.Count
+		}
+		if len(results[0].Unmatched) > 0 {
+			stats.Unmatched = results[0].Unmatched[0].Count
+		}
+	}
+	return stats, nil
+}
+
+// count returns the number of documents a pipeline yields
+func (s *StatsService) count(ctx context.Context, collectionName string, pipeline mongo.Pipeline) (int, error) {
+	var results []struct {
+		Count int `bson:"count"`
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$count", Value: "count"}})
+	if err := s.aggregate(ctx, collectionName, pipeline, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Count, nil
+}
+
+// aggregate runs a pipeline and decodes all of its results
+func (s *StatsService) aggregate(ctx context.Context, collectionName string, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := s.mongoClient.GetCollection(collectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+} 
\ No newline at end of file
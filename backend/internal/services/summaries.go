@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// lookupUserSummary embeds the user whose ID is in localField as a
+// one-element array under as, with only the fields a summary needs
+func lookupUserSummary(localField, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from": "users",
+		"let":  bson.M{"id": "$" + localField},
+		"pipeline": mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$id"}}}}},
+			{{Key: "$project", Value: bson.M{"name": 1, "privacy": 1}}},
+		},
+		"as": as,
+	}}}
+}
+
+// firstSummary summarizes the user a lookup found, if any
+func firstSummary(users []models.User) *models.UserSummary {
+	if len(users) == 0 {
+		return nil
+	}
+	return users[0].Summary()
+}
+
+// SummarizeNeeds embeds summaries of matched needs and their requesters in
+// matches, fetched in one aggregation
+func (m *MatchingService) SummarizeNeeds(ctx context.Context, matches []models.Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	needIDs := make([]primitive.ObjectID, len(matches))
+	for i, match := range matches {
+		needIDs[i] = match.NeedID
+	}
+
+	cursor, err := m.mongoClient.GetCollection("needs").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": needIDs}}}},
+		{{Key: "$project", Value: bson.M{
+			"user_id": 1, "title": 1, "category": 1, "urgency": 1, "duration": 1,
+			"status": 1, "created_at": 1, "expires_at": 1,
+		}}},
+		lookupUserSummary("user_id", "requester"),
+	})
+	if err != nil {
+		return err
+	}
+	var rows []struct {
+		models.Need `bson:",inline"`
+		Requester   []models.User `bson:"requester"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return err
+	}
+
+	summaries := make(map[primitive.ObjectID]*models.NeedSummary, len(rows))
+	for _, row := range rows {
+		summaries[row.ID] = &models.NeedSummary{
+			ID:        row.ID,
+			Title:     sanitize.RedactContacts(row.Title),
+			Category:  row.Category,
+			Urgency:   row.Urgency,
+			Duration:  row.Duration,
+			Status:    row.Status,
+			Requester: firstSummary(row.Requester),
+			CreatedAt: row.CreatedAt,
+			ExpiresAt: row.ExpiresAt,
+		}
+	}
+	for i := range matches {
+		matches[i].Need = summaries[matches[i].NeedID]
+	}
+	return nil
+}
+
+// SummarizeVolunteers embeds the display details of matched volunteers in
+// matches, fetched in one aggregation. Ratings are left out for volunteers
+// who hide them.
+func (m *MatchingService) SummarizeVolunteers(ctx context.Context, matches []models.Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	volunteerIDs := make([]primitive.ObjectID, len(matches))
+	for i, match := range matches {
+		volunteerIDs[i] = match.VolunteerID
+	}
+
+	cursor, err := m.mongoClient.GetCollection("volunteers").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": volunteerIDs}}}},
+		{{Key: "$project", Value: bson.M{"user_id": 1, "rating": 1, "task_count": 1, "trust": 1}}},
+		lookupUserSummary("user_id", "user"),
+	})
+	if err != nil {
+		return err
+	}
+	var rows []struct {
+		models.Volunteer `bson:",inline"`
+		User             []models.User `bson:"user"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return err
+	}
+
+	summaries := make(map[primitive.ObjectID]*models.VolunteerSummary, len(rows))
+	for _, row := range rows {
+		summary := &models.VolunteerSummary{
+			ID:         row.ID,
+			User:       firstSummary(row.User),
+			TaskCount:  row.TaskCount,
+			TrustLevel: trustLevel(row.Trust),
+		}
+		if len(row.User) > 0 && !row.User[0].Privacy.HideRating {
+			rating := row.Rating
+			summary.Rating = &rating
+		}
+		summaries[row.ID] = summary
+	}
+	for i := range matches {
+		matches[i].Volunteer = summaries[matches[i].VolunteerID]
+	}
+	return nil
+} 
\ No newline at end of file
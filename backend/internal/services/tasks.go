@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// taskUpdateAttempts is how many times a status change is retried when the
+// other participant changes the task at the same moment
+const taskUpdateAttempts = 3
+
+// openTaskStatuses are the statuses of tasks still being worked on
+var openTaskStatuses = []models.TaskStatus{
+	models.TaskStatusAccepted,
+	models.TaskStatusInProgress,
+	models.TaskStatusAwaitingConfirmation,
+}
+
+// Errors returned when changing a task's status
+var (
+	ErrTaskClosed            = errors.New("task is already completed or cancelled")
+	ErrInvalidTaskTransition = errors.New("tasks cannot go back to accepted, and are completed by both participants marking them completed")
+	ErrTaskStatusConflict    = errors.New("task was changed at the same time; try again")
+)
+
+// TaskService moves tasks through their statuses. Completion takes both
+// participants: the first to mark a task completed leaves it awaiting the
+// other's confirmation, and it completes once they confirm too or
+// confirmWithin passes without them answering. Marking an awaiting task in
+// progress again disputes the completion.
+type TaskService struct {
+	mongoClient   *database.MongoClient
+	ratings       *RatingService
+	sla           *SLAService
+	notifications *NotificationService
+	notify        Notifier
+	audit         *AuditService
+	confirmWithin time.Duration
+}
+
+// NewTaskService creates a new task service. Closed tasks are queued on
+// ratingService to update volunteers' reputations, and completed ones are
+// recorded on slaService and emailed about by notificationService; notify tells a participant the other is
+// waiting on their confirmation. Status changes are recorded on
+// auditService.
+func NewTaskService(mongoClient *database.MongoClient, ratingService *RatingService, slaService *SLAService, notificationService *NotificationService, notify Notifier, auditService *AuditService, confirmWithin time.Duration) *TaskService {
+	return &TaskService{
+		mongoClient:   mongoClient,
+		ratings:       ratingService,
+		sla:           slaService,
+		notifications: notificationService,
+		notify:        notify,
+		audit:         auditService,
+		confirmWithin: confirmWithin,
+	}
+}
+
+// UpdateStatus applies a status change by userID, who must be the task's
+// volunteer or the creator of its need, and returns the updated task
+func (s *TaskService) UpdateStatus(ctx context.Context, userID, taskID primitive.ObjectID, req models.UpdateTaskStatusRequest) (*models.Task, error) {
+	for attempt := 1; ; attempt++ {
+		task, requesterID, err := s.participant(ctx, taskID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		set, err := s.transition(task, userID == task.VolunteerID, req.Status, now)
+		if err != nil {
+			return nil, err
+		}
+		set["updated_at"] = now
+		if req.ScheduledAt != nil {
+			set["scheduled_at"] = req.ScheduledAt
+		}
+		if req.Notes != "" {
+			set["notes"] = req.Notes
+		}
+		update := bson.M{"$set": set}
+		if req.Status != models.TaskStatusCompleted && task.Confirmation != nil {
+			update["$unset"] = bson.M{"confirmation": ""}
+		}
+
+		// Only apply the change to the task as it was read, so both
+		// participants confirming at once cannot overwrite each other
+		var updated models.Task
+		err = s.mongoClient.GetCollection("tasks").FindOneAndUpdate(ctx,
+			bson.M{"_id": taskID, "status": task.Status},
+			update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err == mongo.ErrNoDocuments {
+			if attempt < taskUpdateAttempts {
+				continue
+			}
+			return nil, ErrTaskStatusConflict
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.recordChange(ctx, &userID, task.Status, &updated, nil)
+
+		switch {
+		case updated.Status == models.TaskStatusCompleted:
+			s.completed(ctx, &updated, userID)
+		case updated.Status == models.TaskStatusCancelled:
+			s.closed(ctx, updated.VolunteerID)
+		case updated.Status == models.TaskStatusAwaitingConfirmation && task.Status != models.TaskStatusAwaitingConfirmation:
+			other := requesterID
+			if userID == requesterID {
+				other = task.VolunteerID
+			}
+			s.awaiting(ctx, &updated, other)
+		}
+		return &updated, nil
+	}
+}
+
+// ConfirmDue completes tasks whose confirmation window has passed without
+// the other participant answering, returning how many were completed
+func (s *TaskService) ConfirmDue(ctx context.Context) (int, error) {
+	collection := s.mongoClient.GetCollection("tasks")
+	now := time.Now()
+	cursor, err := collection.Find(ctx, bson.M{
+		"status":              models.TaskStatusAwaitingConfirmation,
+		"confirmation.due_at": bson.M{"$lte": now},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var due []models.Task
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, task := range due {
+		var updated models.Task
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{"_id": task.ID, "status": models.TaskStatusAwaitingConfirmation},
+			bson.M{"$set": bson.M{
+				"status":              models.TaskStatusCompleted,
+				"completed_at":        now,
+				"confirmation.lapsed": true,
+				"updated_at":          now,
+			}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err == mongo.ErrNoDocuments {
+			continue // confirmed or disputed in the meantime
+		}
+		if err != nil {
+			return completed, err
+		}
+		s.recordChange(ctx, nil, models.TaskStatusAwaitingConfirmation, &updated, map[string]interface{}{"confirmation_lapsed": true})
+		s.completed(ctx, &updated, primitive.NilObjectID)
+		completed++
+	}
+	return completed, nil
+}
+
+// CancelForNeed cancels the tasks still working on a need, as when the need
+// itself is cancelled
+func (s *TaskService) CancelForNeed(ctx context.Context, needID primitive.ObjectID) error {
+	collection := s.mongoClient.GetCollection("tasks")
+	open := bson.M{"need_id": needID, "status": bson.M{"$in": openTaskStatuses}}
+	volunteerIDs, err := collection.Distinct(ctx, "volunteer_id", open)
+	if err != nil {
+		return err
+	}
+	if len(volunteerIDs) == 0 {
+		return nil
+	}
+
+	if _, err := collection.UpdateMany(ctx, open,
+		bson.M{"$set": bson.M{"status": models.TaskStatusCancelled, "updated_at": time.Now()}}); err != nil {
+		return err
+	}
+	for _, id := range volunteerIDs {
+		if volunteerID, ok := id.(primitive.ObjectID); ok {
+			s.closed(ctx, volunteerID)
+		}
+	}
+	return nil
+}
+
+// participant loads a task userID takes part in, along with the creator of
+// its need. Tasks userID is not part of are reported as not found.
+func (s *TaskService) participant(ctx context.Context, taskID, userID primitive.ObjectID) (*models.Task, primitive.ObjectID, error) {
+	var task models.Task
+	if err := s.mongoClient.GetCollection("tasks").FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, primitive.NilObjectID, ErrTaskNotFound
+		}
+		return nil, primitive.NilObjectID, err
+	}
+
+	var need models.Need
+	err := s.mongoClient.GetCollection("needs").FindOne(ctx, bson.M{"_id": task.NeedID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1})).Decode(&need)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, primitive.NilObjectID, err
+	}
+	if userID != task.VolunteerID && userID != need.UserID {
+		return nil, primitive.NilObjectID, ErrTaskNotFound
+	}
+	return &task, need.UserID, nil
+}
+
+// transition works out the fields a participant's status change sets.
+// Marking a task completed records that side's confirmation, and only
+// completes the task once the other side has confirmed as well.
+func (s *TaskService) transition(task *models.Task, volunteer bool, status models.TaskStatus, now time.Time) (bson.M, error) {
+	switch {
+	case task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusCancelled:
+		return nil, ErrTaskClosed
+	case status == models.TaskStatusAwaitingConfirmation,
+		status == models.TaskStatusAccepted && task.Status != models.TaskStatusAccepted:
+		return nil, ErrInvalidTaskTransition
+	case status != models.TaskStatusCompleted:
+		return bson.M{"status": status}, nil
+	}
+
+	confirmation := models.TaskConfirmation{DueAt: now.Add(s.confirmWithin)}
+	if task.Confirmation != nil {
+		confirmation = *task.Confirmation
+	}
+	if volunteer && confirmation.VolunteerAt == nil {
+		confirmation.VolunteerAt = &now
+	}
+	if !volunteer && confirmation.RequesterAt == nil {
+		confirmation.RequesterAt = &now
+	}
+	if confirmation.VolunteerAt == nil || confirmation.RequesterAt == nil {
+		return bson.M{"status": models.TaskStatusAwaitingConfirmation, "confirmation": confirmation}, nil
+	}
+	return bson.M{"status": models.TaskStatusCompleted, "completed_at": now, "confirmation": confirmation}, nil
+}
+
+// recordChange audits a task's status changing from previous to the
+// status of updated. actorID is nil when the change was made by the system
+// rather than a participant.
+func (s *TaskService) recordChange(ctx context.Context, actorID *primitive.ObjectID, previous models.TaskStatus, updated *models.Task, details map[string]interface{}) {
+	s.audit.Record(ctx, models.AuditEntry{
+		ActorID:    actorID,
+		Action:     models.AuditTaskStatusChanged,
+		TargetType: models.AuditTargetTask,
+		TargetID:   &updated.ID,
+		Details:    details,
+		Before:     map[string]interface{}{"status": previous},
+		After:      map[string]interface{}{"status": updated.Status, "need_id": updated.NeedID, "volunteer_id": updated.VolunteerID},
+	})
+}
+
+// awaiting tells the other participant a task is waiting on their
+// confirmation. A failed notification is logged; the task still completes
+// when the window passes.
+func (s *TaskService) awaiting(ctx context.Context, task *models.Task, other primitive.ObjectID) {
+	message := models.WebSocketMessage{
+		Type: "task_status_update",
+		Payload: map[string]interface{}{
+			"task_id":    task.ID.Hex(),
+			"need_id":    task.NeedID.Hex(),
+			"status":     task.Status,
+			"confirm_by": task.Confirmation.DueAt,
+		},
+	}
+	if err := s.notify(ctx, []string{other.Hex()}, message); err != nil {
+		log.Printf("Failed to ask for confirmation of task %s: %v", task.ID.Hex(), err)
+	}
+}
+
+// completed updates everything that follows a task completing. completedBy
+// is the participant who confirmed last, or nil when the window lapsed, in
+// which case both participants are notified.
+func (s *TaskService) completed(ctx context.Context, task *models.Task, completedBy primitive.ObjectID) {
+	s.closed(ctx, task.VolunteerID)
+	s.sla.Record(ctx, task.NeedID, models.MilestoneCompleted, *task.CompletedAt)
+	s.notifications.TaskCompleted(ctx, *task, completedBy)
+}
+
+// closed queues a recompute of the reputation of a volunteer whose task was
+// completed or cancelled. A failure only delays the recompute until the
+// next full one, so it is logged.
+func (s *TaskService) closed(ctx context.Context, volunteerID primitive.ObjectID) {
+	if err := s.ratings.Changed(ctx, volunteerID); err != nil {
+		log.Printf("Failed to queue reputation for %s: %v", volunteerID.Hex(), err)
+	}
+} 
\ No newline at end of file
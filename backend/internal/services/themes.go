@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxThemeComments caps how many comments are sent to be summarized
+const maxThemeComments = 50
+
+// themePrompt asks for praise and criticism themes as JSON. Comments are
+// about one person and written by others, so quotes and names are ruled out.
+const themePrompt = `You summarize feedback a community volunteer received after helping neighbors.
+Each line below is one rating (1-5 stars) and comment. Identify up to 3 recurring themes of praise and up to 3 of constructive criticism.
+Write each theme as a short phrase addressed to the volunteer, such as "Arrives on time" or "Could confirm plans sooner".
+Never quote comments or mention anyone's name. Leave a list empty when there is no clear theme.
+Reply with only JSON of the form {"praise": ["..."], "criticism": ["..."]}.`
+
+// ThemeSummarizer condenses feedback comments into praise and criticism
+// themes with an OpenAI chat model
+type ThemeSummarizer struct {
+	client *openai.Client
+}
+
+// NewThemeSummarizer creates a summarizer. Without an API key it is
+// disabled and Summarize returns no themes.
+func NewThemeSummarizer(apiKey string) *ThemeSummarizer {
+	summarizer := &ThemeSummarizer{}
+	if apiKey != "" {
+		summarizer.client = newOpenAIClient(apiKey)
+	}
+	return summarizer
+}
+
+// Enabled reports whether the summarizer can produce themes
+func (t *ThemeSummarizer) Enabled() bool {
+	return t != nil && t.client != nil
+}
+
+// Summarize returns the praise and criticism themes in comments, each
+// prefixed with its rating. It returns nil lists when disabled.
+func (t *ThemeSummarizer) Summarize(ctx context.Context, comments []string) ([]string, []string, error) {
+	if !t.Enabled() || len(comments) == 0 {
+		return nil, nil, nil
+	}
+	if len(comments) > maxThemeComments {
+		comments = comments[:maxThemeComments]
+	}
+
+	resp, err := t.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       openai.GPT3Dot5Turbo,
+		Temperature: 0.2,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: themePrompt},
+			{Role: openai.ChatMessageRoleUser, Content: strings.Join(comments, "\n")},
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to summarize feedback: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, nil, fmt.Errorf("no summary returned")
+	}
+
+	var themes struct {
+		Praise    []string `json:"praise"`
+		Criticism []string `json:"criticism"`
+	}
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	content = strings.TrimSuffix(strings.TrimPrefix(content, "```json"), "```")
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &themes); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse feedback summary: %w", err)
+	}
+	return limitThemes(themes.Praise), limitThemes(themes.Criticism), nil
+}
+
+// limitThemes keeps the first three non-empty themes
+func limitThemes(themes []string) []string {
+	kept := []string{}
+	for _, theme := range themes {
+		if theme = strings.TrimSpace(theme); theme != "" && len(kept) < 3 {
+			kept = append(kept, theme)
+		}
+	}
+	return kept
+} 
\ No newline at end of file
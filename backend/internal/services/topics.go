@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/uber/h3-go/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// Topic prefixes. Clients follow new needs in a category, named by its
+// category or subcategory path as in "needs:category:errands", or
+// in a neighborhood, named by its H3 cell at neighborhood resolution as in
+// "area:h3:872830828ffffff".
+const (
+	TopicNeedsCategory = "needs:category:"
+	TopicArea          = "area:h3:"
+)
+
+// areaTopicRings is how many rings of neighborhoods around their own a user
+// may follow, so nobody can watch needs anywhere in the city
+const areaTopicRings = 2
+
+// Errors returned when subscribing to a topic
+var (
+	ErrUnknownTopic    = errors.New("unknown topic")
+	ErrTopicNotAllowed = errors.New("topic is not allowed")
+	ErrTooManyTopics   = errors.New("too many topic subscriptions")
+)
+
+// TopicNotifier delivers a message to the clients subscribed to any of
+// topics, on every instance
+type TopicNotifier func(ctx context.Context, topics []string, message models.WebSocketMessage) error
+
+// TopicService decides which WebSocket topics a user may subscribe to, and
+// publishes new needs on theirs
+type TopicService struct {
+	mongoClient *database.MongoClient
+	privacy     *PrivacyService
+	categories  *CategoryService
+	publish     TopicNotifier
+}
+
+// NewTopicService creates a new topic service. Category topics must name a
+// category known to categoryService, and area topics a neighborhood near the
+// user's own by privacyService's neighborhood resolution.
+func NewTopicService(mongoClient *database.MongoClient, privacyService *PrivacyService, categoryService *CategoryService, publish TopicNotifier) *TopicService {
+	return &TopicService{
+		mongoClient: mongoClient,
+		privacy:     privacyService,
+		categories:  categoryService,
+		publish:     publish,
+	}
+}
+
+// Authorize returns nil if userID may subscribe to topic
+func (s *TopicService) Authorize(ctx context.Context, userID, topic string) error {
+	switch {
+	case strings.HasPrefix(topic, TopicNeedsCategory):
+		err := s.categories.Check(ctx, strings.TrimPrefix(topic, TopicNeedsCategory))
+		if errors.Is(err, ErrUnknownCategory) {
+			return ErrUnknownTopic
+		}
+		return err
+	case strings.HasPrefix(topic, TopicArea):
+		return s.authorizeArea(ctx, userID, strings.TrimPrefix(topic, TopicArea))
+	}
+	return ErrUnknownTopic
+}
+
+// authorizeArea allows neighborhoods within areaTopicRings of the user's own
+func (s *TopicService) authorizeArea(ctx context.Context, userID, neighborhood string) error {
+	cell := h3.Cell(h3.IndexFromString(neighborhood))
+	if !cell.IsValid() || cell.Resolution() != s.privacy.Resolutions()[1] {
+		return ErrUnknownTopic
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrTopicNotAllowed
+	}
+	var user models.User
+	err = s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID},
+		options.FindOne().SetProjection(bson.M{"location": 1})).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return ErrTopicNotAllowed
+	}
+	if err != nil {
+		return err
+	}
+
+	own := s.privacy.NeighborhoodCell(user.Location)
+	if own == "" {
+		return ErrTopicNotAllowed
+	}
+	for _, nearby := range h3.GridDisk(h3.Cell(h3.IndexFromString(own)), areaTopicRings) {
+		if nearby == cell {
+			return nil
+		}
+	}
+	return ErrTopicNotAllowed
+}
+
+// NeedTopics returns the topics a need is published on: its category and
+// each subcategory above it, and its neighborhood
+func (s *TopicService) NeedTopics(need *models.Need) []string {
+	var topics []string
+	for _, path := range models.CategoryAncestors(need.CategoryPath()) {
+		topics = append(topics, TopicNeedsCategory+path)
+	}
+	if neighborhood := s.privacy.NeighborhoodCell(need.Location); neighborhood != "" {
+		topics = append(topics, TopicArea+neighborhood)
+	}
+	return topics
+}
+
+// PublishNeed announces a new need to the clients following its topics.
+// Followers are not bound to the need, so it carries only its neighborhood
+// and has contact details redacted from its title.
+func (s *TopicService) PublishNeed(ctx context.Context, need *models.Need) error {
+	message := models.WebSocketMessage{
+		Type: "need_posted",
+		Payload: map[string]interface{}{
+			"need_id":      need.ID.Hex(),
+			"title":        sanitize.RedactContacts(need.Title),
+			"category":     need.Category,
+			"subcategory":  need.Subcategory,
+			"urgency":      need.Urgency,
+			"neighborhood": s.privacy.NeighborhoodCell(need.Location),
+		},
+	}
+	return s.publish(ctx, s.NeedTopics(need), message)
+} 
\ No newline at end of file
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+)
+
+// AudioFormats are the file extensions voice notes can be uploaded in
+var AudioFormats = []string{".flac", ".m4a", ".mp3", ".mp4", ".mpeg", ".mpga", ".oga", ".ogg", ".wav", ".webm"}
+
+// Transcriber turns a recorded voice note into text. Whisper is built in;
+// other speech-to-text providers can be plugged in by implementing it.
+type Transcriber interface {
+	// Transcribe returns what is said in audio, a file named filename.
+	// language, when known, hints at the language spoken.
+	Transcribe(ctx context.Context, filename string, audio io.Reader, language models.Language) (string, error)
+}
+
+// WhisperTranscriber transcribes voice notes with OpenAI's Whisper
+type WhisperTranscriber struct {
+	client *openai.Client
+}
+
+// NewWhisperTranscriber creates a Whisper transcriber
+func NewWhisperTranscriber(apiKey string) *WhisperTranscriber {
+	return &WhisperTranscriber{client: newOpenAIClient(apiKey)}
+}
+
+// Transcribe sends the voice note to Whisper
+func (w *WhisperTranscriber) Transcribe(ctx context.Context, filename string, audio io.Reader, language models.Language) (string, error) {
+	resp, err := w.client.CreateTranscription(ctx, openai.AudioRequest{
+		Model:    openai.Whisper1,
+		FilePath: filename,
+		Reader:   audio,
+		Language: string(language),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe voice note: %w", err)
+	}
+	return sanitize.Text(resp.Text), nil
+}
+
+// IsAudioFormat reports whether filename has an extension voice notes can
+// be uploaded in
+func IsAudioFormat(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, format := range AudioFormats {
+		if ext == format {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
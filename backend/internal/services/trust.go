@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// Trust score weights; they sum to 1
+const (
+	trustWeightVerified   = 0.25
+	trustWeightAge        = 0.15
+	trustWeightCompletion = 0.25
+	trustWeightReputation = 0.25
+	trustWeightDisputes   = 0.10
+)
+
+// trustMatureAge is the account age that earns full credit for age
+const trustMatureAge = 180 * 24 * time.Hour
+
+// trustMaxUpheldReports is how many upheld reports take away all credit for
+// dispute history
+const trustMaxUpheldReports = 3
+
+// trustRefreshAfter is how old a trust score gets before it is recomputed,
+// so account age and reports are reflected without every change hooking in
+const trustRefreshAfter = 24 * time.Hour
+
+// trustBatchSize caps how many users one pass of RefreshStale loads
+const trustBatchSize = 500
+
+// ErrTrustTooLow is returned when a volunteer below a need's minimum trust
+// level tries to accept it
+var ErrTrustTooLow = errors.New("this need is limited to volunteers with a higher trust level")
+
+// TrustService computes users' trust scores
+type TrustService struct {
+	mongoClient *database.MongoClient
+}
+
+// NewTrustService creates a new trust service
+func NewTrustService(mongoClient *database.MongoClient) *TrustService {
+	return &TrustService{mongoClient: mongoClient}
+}
+
+// Refresh recomputes a user's trust score on the user and their volunteer
+// profile
+func (s *TrustService) Refresh(ctx context.Context, userID primitive.ObjectID) (*models.TrustScore, error) {
+	var user models.User
+	err := s.mongoClient.GetCollection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	factors := models.TrustFactors{
+		Verified:       user.Identity != nil || user.Organizer != nil || user.Supervision.Verified(),
+		AccountAgeDays: int(now.Sub(user.CreatedAt) / (24 * time.Hour)),
+		Reputation:     0.5,
+	}
+	if user.Reputation != nil {
+		factors.Reputation = user.Reputation.Score
+	}
+
+	cursor, err := s.mongoClient.GetCollection("tasks").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"volunteer_id": userID,
+			"status":       bson.M{"$in": bson.A{models.TaskStatusCompleted, models.TaskStatusCancelled}},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		Status models.TaskStatus `bson:"_id"`
+		Count  int               `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		if count.Status == models.TaskStatusCompleted {
+			factors.Completed = count.Count
+		} else {
+			factors.Cancelled = count.Count
+		}
+	}
+
+	upheld, err := s.mongoClient.GetCollection("reports").CountDocuments(ctx,
+		bson.M{"subject_id": userID, "status": models.ReportActioned})
+	if err != nil {
+		return nil, err
+	}
+	factors.UpheldReports = int(upheld)
+
+	score := trustScore(factors)
+	trust := &models.TrustScore{
+		Score:     score,
+		Level:     models.TrustLevelFor(score),
+		Factors:   factors,
+		UpdatedAt: now,
+	}
+	if _, err := s.mongoClient.GetCollection("users").UpdateOne(ctx, bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"trust": trust}}); err != nil {
+		return nil, err
+	}
+	if _, err := s.mongoClient.GetCollection("volunteers").UpdateOne(ctx, bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"trust": trust}}); err != nil {
+		return nil, err
+	}
+	return trust, nil
+}
+
+// RefreshStale recomputes every trust score older than trustRefreshAfter,
+// and those never computed, returning how many it refreshed
+func (s *TrustService) RefreshStale(ctx context.Context) (int, error) {
+	refreshed := 0
+	for {
+		cutoff := time.Now().Add(-trustRefreshAfter)
+		cursor, err := s.mongoClient.GetCollection("users").Find(ctx,
+			bson.M{"$or": []bson.M{
+				{"trust.updated_at": bson.M{"$lt": cutoff}},
+				{"trust": bson.M{"$exists": false}},
+			}},
+			options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(trustBatchSize))
+		if err != nil {
+			return refreshed, err
+		}
+		var users []models.User
+		if err := cursor.All(ctx, &users); err != nil {
+			return refreshed, err
+		}
+
+		for _, user := range users {
+			if _, err := s.Refresh(ctx, user.ID); err != nil && !errors.Is(err, ErrUserNotFound) {
+				return refreshed, err
+			}
+			refreshed++
+		}
+		if len(users) < trustBatchSize || ctx.Err() != nil {
+			return refreshed, ctx.Err()
+		}
+	}
+}
+
+// trustScore weighs the trust factors into a 0-1 score. Completion rate is
+// smoothed so a volunteer with no finished tasks sits at the midpoint.
+func trustScore(f models.TrustFactors) float64 {
+	var verified float64
+	if f.Verified {
+		verified = 1
+	}
+	age := min(float64(f.AccountAgeDays)/(trustMatureAge.Hours()/24), 1)
+	completion := float64(f.Completed+1) / float64(f.Completed+f.Cancelled+2)
+	disputes := 1 - min(float64(f.UpheldReports)/trustMaxUpheldReports, 1)
+
+	return trustWeightVerified*verified +
+		trustWeightAge*age +
+		trustWeightCompletion*completion +
+		trustWeightReputation*f.Reputation +
+		trustWeightDisputes*disputes
+}
+
+// trustLevel returns the level of a trust score that may not have been
+// computed yet
+func trustLevel(trust *models.TrustScore) models.TrustLevel {
+	if trust == nil {
+		return ""
+	}
+	return trust.Level
+}
+
+// trustScoreOf returns a trust score that may not have been computed yet,
+// or zero
+func trustScoreOf(trust *models.TrustScore) float64 {
+	if trust == nil {
+		return 0
+	}
+	return trust.Score
+} 
\ No newline at end of file
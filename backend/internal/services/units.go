@@ -0,0 +1,40 @@
+package services
+
+import (
+	"math"
+
+	"neighborenexus/internal/i18n"
+	"neighborenexus/internal/models"
+)
+
+// metersPerMile converts meters to miles
+const metersPerMile = 1609.344
+
+// LocalizeDistance renders a distance in meters in the given units,
+// formatted for lang. Users who never chose units read kilometers.
+func LocalizeDistance(meters float64, units models.Units, lang models.Language) *models.Distance {
+	value, unit := meters/1000, "km"
+	if units == models.UnitsImperial {
+		value, unit = meters/metersPerMile, "mi"
+	}
+	value = math.Round(value*10) / 10
+	return &models.Distance{
+		Value: value,
+		Unit:  unit,
+		Text:  i18n.FormatDecimal(string(lang), value) + " " + unit,
+	}
+}
+
+// LocalizeMatches renders matches' distances for a reader
+func LocalizeMatches(matches []models.Match, units models.Units, lang models.Language) {
+	for i := range matches {
+		matches[i].DistanceDisplay = LocalizeDistance(matches[i].Distance, units, lang)
+	}
+}
+
+// LocalizeOfferMatches renders offer matches' distances for a reader
+func LocalizeOfferMatches(matches []models.OfferMatch, units models.Units, lang models.Language) {
+	for i := range matches {
+		matches[i].DistanceDisplay = LocalizeDistance(matches[i].Distance, units, lang)
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/storage"
+)
+
+const (
+	// uploadURLExpiry is how long a presigned upload URL can be used
+	uploadURLExpiry = 15 * time.Minute
+	// downloadURLExpiry is how long the URL an upload is served from lasts
+	downloadURLExpiry = 10 * time.Minute
+	// abandonedUploadAge is how long an upload may stay unattached before
+	// it is deleted
+	abandonedUploadAge = 24 * time.Hour
+	// sniffLen is how much of an upload is read to check it is an image
+	sniffLen = 512
+)
+
+// ImageTypes are the content types images can be uploaded as, with the
+// extension their objects are stored under
+var ImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// Errors returned when uploading and attaching files
+var (
+	ErrUploadsDisabled      = errors.New("uploads are not enabled on this deployment")
+	ErrUnsupportedImageType = errors.New("images must be JPEG, PNG, WebP, or GIF")
+	ErrImageTooLarge        = errors.New("image is larger than the upload limit")
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadIncomplete     = errors.New("upload has not finished; send the file to the upload URL first")
+	ErrUploadMismatch       = errors.New("uploaded file does not match the declared content type and size")
+	ErrWrongUploadPurpose   = errors.New("upload was made for a different purpose")
+	ErrUploadInUse          = errors.New("upload is already attached")
+	ErrTooManyImages        = errors.New("needs can have at most 5 images")
+)
+
+// UploadService hands out presigned URLs for uploading profile photos and
+// need images straight to object storage, checks uploads are the images
+// they were declared as before attaching them, and deletes uploads nothing
+// uses. It is switched off on deployments without a bucket.
+type UploadService struct {
+	mongoClient *database.MongoClient
+	store       *storage.S3Client
+	maxBytes    int64
+}
+
+// NewUploadService creates a new upload service. Images larger than
+// maxBytes are refused.
+func NewUploadService(mongoClient *database.MongoClient, store *storage.S3Client, maxBytes int64) *UploadService {
+	return &UploadService{
+		mongoClient: mongoClient,
+		store:       store,
+		maxBytes:    maxBytes,
+	}
+}
+
+// Enabled reports whether uploads are switched on
+func (s *UploadService) Enabled() bool {
+	return s.store != nil
+}
+
+// MaxBytes is the largest image that can be uploaded
+func (s *UploadService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Create records a pending upload by userID and returns the URL to upload
+// it to
+func (s *UploadService) Create(ctx context.Context, userID primitive.ObjectID, req models.CreateUploadRequest) (*models.PresignedUpload, error) {
+	if !s.Enabled() {
+		return nil, ErrUploadsDisabled
+	}
+	ext, ok := ImageTypes[req.ContentType]
+	if !ok {
+		return nil, ErrUnsupportedImageType
+	}
+	if req.Size > s.maxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	id := primitive.NewObjectID()
+	upload := &models.Upload{
+		ID:          id,
+		UserID:      userID,
+		Purpose:     req.Purpose,
+		Key:         "uploads/" + userID.Hex() + "/" + id.Hex() + ext,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		CreatedAt:   time.Now(),
+	}
+	if _, err := s.mongoClient.GetCollection("uploads").InsertOne(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return &models.PresignedUpload{
+		Upload:    upload,
+		Method:    http.MethodPut,
+		URL:       s.store.PresignPut(upload.Key, upload.ContentType, upload.Size, uploadURLExpiry),
+		Headers:   map[string]string{"Content-Type": upload.ContentType},
+		ExpiresAt: upload.CreatedAt.Add(uploadURLExpiry),
+	}, nil
+}
+
+// URL returns a short-lived URL an upload can be downloaded from. Attached
+// uploads are shown to anyone who can see the profile or need they are on;
+// pending ones, and those on needs or profiles moderation hid or holds for
+// review, only to their uploader.
+func (s *UploadService) URL(ctx context.Context, viewerID, uploadID primitive.ObjectID) (string, error) {
+	if !s.Enabled() {
+		return "", ErrUploadsDisabled
+	}
+	var upload models.Upload
+	if err := s.mongoClient.GetCollection("uploads").FindOne(ctx, bson.M{"_id": uploadID}).Decode(&upload); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", ErrUploadNotFound
+		}
+		return "", err
+	}
+	if upload.UserID != viewerID {
+		visible, err := s.visible(ctx, &upload)
+		if err != nil {
+			return "", err
+		}
+		if !visible {
+			return "", ErrUploadNotFound
+		}
+	}
+	return s.store.PresignGet(upload.Key, downloadURLExpiry), nil
+}
+
+// visible reports whether an upload can be seen by users other than its
+// uploader, who owns the need or profile it is attached to
+func (s *UploadService) visible(ctx context.Context, upload *models.Upload) (bool, error) {
+	if !upload.Attached {
+		return false, nil
+	}
+	switch {
+	case upload.Purpose == models.UploadPurposeNeedImage && upload.NeedID != nil:
+		count, err := s.mongoClient.GetCollection("needs").CountDocuments(ctx, bson.M{
+			"_id":             *upload.NeedID,
+			"hidden":          bson.M{"$ne": true},
+			"held_for_review": bson.M{"$ne": true},
+		})
+		return count > 0, err
+	case upload.Purpose == models.UploadPurposeProfilePhoto:
+		// The photo is on both the account and the volunteer profile, so
+		// hiding either hides it
+		count, err := s.mongoClient.GetCollection("users").CountDocuments(ctx, bson.M{
+			"_id":    upload.UserID,
+			"hidden": bson.M{"$ne": true},
+		})
+		if err != nil || count == 0 {
+			return false, err
+		}
+		hidden, err := s.mongoClient.GetCollection("volunteers").CountDocuments(ctx, bson.M{
+			"user_id": upload.UserID,
+			"hidden":  true,
+		})
+		return hidden == 0, err
+	}
+	return false, nil
+}
+
+// SetPhoto makes an upload the user's profile photo, on their volunteer
+// profile too, replacing any photo they had
+func (s *UploadService) SetPhoto(ctx context.Context, userID, uploadID primitive.ObjectID) (*models.Attachment, error) {
+	upload, err := s.verify(ctx, userID, uploadID, models.UploadPurposeProfilePhoto)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markAttached(ctx, []*models.Upload{upload}, nil); err != nil {
+		return nil, err
+	}
+
+	photo := attachmentFor(upload)
+	previous, err := s.replacePhoto(ctx, userID, bson.M{"$set": bson.M{"photo": photo, "updated_at": photo.AttachedAt}})
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		s.release(ctx, previous.UploadID)
+	}
+	return &photo, nil
+}
+
+// RemovePhoto removes the user's profile photo
+func (s *UploadService) RemovePhoto(ctx context.Context, userID primitive.ObjectID) error {
+	previous, err := s.replacePhoto(ctx, userID, bson.M{"$unset": bson.M{"photo": ""}, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		s.release(ctx, previous.UploadID)
+	}
+	return nil
+}
+
+// SetNeedImages replaces the images on one of userID's needs with
+// uploadIDs, in order. Images already on the need can be kept by listing
+// them again.
+func (s *UploadService) SetNeedImages(ctx context.Context, userID, needID primitive.ObjectID, uploadIDs []primitive.ObjectID) ([]models.Attachment, error) {
+	if len(uploadIDs) > models.MaxNeedImages {
+		return nil, ErrTooManyImages
+	}
+	needs := s.mongoClient.GetCollection("needs")
+	var need models.Need
+	err := needs.FindOne(ctx, bson.M{"_id": needID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1, "status": 1, "images": 1})).Decode(&need)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case need.UserID != userID:
+		return nil, ErrNotNeedOwner
+	case need.Status == models.NeedStatusCompleted || need.Status == models.NeedStatusCancelled || need.Status == models.NeedStatusExpired:
+		return nil, ErrNeedClosed
+	}
+
+	current := make(map[primitive.ObjectID]models.Attachment, len(need.Images))
+	for _, image := range need.Images {
+		current[image.UploadID] = image
+	}
+
+	// Check every new upload before attaching any, so a bad one leaves the
+	// need as it was
+	images := make([]models.Attachment, 0, len(uploadIDs))
+	var added []*models.Upload
+	seen := make(map[primitive.ObjectID]bool, len(uploadIDs))
+	for _, id := range uploadIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if image, ok := current[id]; ok {
+			images = append(images, image)
+			continue
+		}
+		upload, err := s.verify(ctx, userID, id, models.UploadPurposeNeedImage)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, upload)
+		images = append(images, attachmentFor(upload))
+	}
+	if err := s.markAttached(ctx, added, &needID); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"$set": bson.M{"images": images, "updated_at": time.Now()}}
+	if len(images) == 0 {
+		set = bson.M{"$unset": bson.M{"images": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	if _, err := needs.UpdateOne(ctx, bson.M{"_id": needID}, set); err != nil {
+		return nil, err
+	}
+
+	var removed []primitive.ObjectID
+	for id := range current {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+	s.release(ctx, removed...)
+	return images, nil
+}
+
+// PurgeAbandoned deletes uploads never attached to anything, and need
+// images whose need has been deleted, returning how many were deleted
+func (s *UploadService) PurgeAbandoned(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+	uploads := s.mongoClient.GetCollection("uploads")
+	cursor, err := uploads.Find(ctx, bson.M{
+		"attached":   bson.M{"$ne": true},
+		"created_at": bson.M{"$lt": time.Now().Add(-abandonedUploadAge)},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var abandoned []models.Upload
+	if err := cursor.All(ctx, &abandoned); err != nil {
+		return 0, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(abandoned))
+	for _, upload := range abandoned {
+		ids = append(ids, upload.ID)
+	}
+
+	needIDs, err := uploads.Distinct(ctx, "need_id", bson.M{"purpose": models.UploadPurposeNeedImage, "attached": true})
+	if err != nil {
+		return 0, err
+	}
+	if len(needIDs) > 0 {
+		existing, err := s.mongoClient.GetCollection("needs").Distinct(ctx, "_id", bson.M{"_id": bson.M{"$in": needIDs}})
+		if err != nil {
+			return 0, err
+		}
+		gone := make([]interface{}, 0, len(needIDs))
+		kept := make(map[interface{}]bool, len(existing))
+		for _, id := range existing {
+			kept[id] = true
+		}
+		for _, id := range needIDs {
+			if !kept[id] {
+				gone = append(gone, id)
+			}
+		}
+		if len(gone) > 0 {
+			ids, err = s.appendIDs(ctx, ids, bson.M{"need_id": bson.M{"$in": gone}})
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return s.release(ctx, ids...), nil
+}
+
+// DeleteOwned deletes every upload by userID, as when they are erased,
+// returning how many were deleted. Uploads that could not be deleted are
+// reported as an error, so the erasure is run again.
+func (s *UploadService) DeleteOwned(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	ids, err := s.appendIDs(ctx, nil, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	if s.Enabled() {
+		deleted := s.release(ctx, ids...)
+		if deleted < len(ids) {
+			return int64(deleted), fmt.Errorf("failed to delete %d of %d uploads", len(ids)-deleted, len(ids))
+		}
+		return int64(deleted), nil
+	}
+	result, err := s.mongoClient.GetCollection("uploads").DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// verify checks a pending upload by userID was made for purpose and that
+// the file in the bucket is the image it was declared as
+func (s *UploadService) verify(ctx context.Context, userID, uploadID primitive.ObjectID, purpose models.UploadPurpose) (*models.Upload, error) {
+	if !s.Enabled() {
+		return nil, ErrUploadsDisabled
+	}
+	var upload models.Upload
+	err := s.mongoClient.GetCollection("uploads").FindOne(ctx, bson.M{"_id": uploadID, "user_id": userID}).Decode(&upload)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case upload.Purpose != purpose:
+		return nil, ErrWrongUploadPurpose
+	case upload.Attached:
+		return nil, ErrUploadInUse
+	}
+
+	info, err := s.store.Stat(ctx, upload.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrUploadIncomplete
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Size != upload.Size {
+		return nil, ErrUploadMismatch
+	}
+	// The content type was signed into the upload URL, but the bytes are
+	// whatever the client sent
+	head, err := s.store.ReadPrefix(ctx, upload.Key, sniffLen)
+	if err != nil {
+		return nil, err
+	}
+	if http.DetectContentType(head) != upload.ContentType {
+		return nil, ErrUploadMismatch
+	}
+	return &upload, nil
+}
+
+// markAttached records uploads as attached, to needID for need images.
+// Uploads attached elsewhere in the meantime fail with ErrUploadInUse.
+func (s *UploadService) markAttached(ctx context.Context, uploads []*models.Upload, needID *primitive.ObjectID) error {
+	if len(uploads) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(uploads))
+	for _, upload := range uploads {
+		ids = append(ids, upload.ID)
+	}
+	set := bson.M{"attached": true}
+	if needID != nil {
+		set["need_id"] = *needID
+	}
+	result, err := s.mongoClient.GetCollection("uploads").UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "attached": bson.M{"$ne": true}},
+		bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount != int64(len(ids)) {
+		return ErrUploadInUse
+	}
+	return nil
+}
+
+// replacePhoto applies update to the user and their volunteer profile and
+// returns the photo the user had before
+func (s *UploadService) replacePhoto(ctx context.Context, userID primitive.ObjectID, update bson.M) (*models.Attachment, error) {
+	var before models.User
+	err := s.mongoClient.GetCollection("users").FindOneAndUpdate(ctx, bson.M{"_id": userID}, update,
+		options.FindOneAndUpdate().SetProjection(bson.M{"photo": 1})).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.mongoClient.GetCollection("volunteers").UpdateOne(ctx, bson.M{"user_id": userID}, update); err != nil {
+		return nil, err
+	}
+	return before.Photo, nil
+}
+
+// appendIDs adds the IDs of uploads matching filter to ids
+func (s *UploadService) appendIDs(ctx context.Context, ids []primitive.ObjectID, filter bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := s.mongoClient.GetCollection("uploads").Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var uploads []models.Upload
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, err
+	}
+	for _, upload := range uploads {
+		ids = append(ids, upload.ID)
+	}
+	return ids, nil
+}
+
+// release deletes uploads and their files, returning how many were
+// deleted. Failures are logged and the upload kept, so a later purge
+// retries it.
+func (s *UploadService) release(ctx context.Context, uploadIDs ...primitive.ObjectID) int {
+	if !s.Enabled() {
+		return 0
+	}
+	uploads := s.mongoClient.GetCollection("uploads")
+	deleted := 0
+	for _, id := range uploadIDs {
+		var upload models.Upload
+		if err := uploads.FindOne(ctx, bson.M{"_id": id}).Decode(&upload); err != nil {
+			if err != mongo.ErrNoDocuments {
+				log.Printf("Failed to load upload %s: %v", id.Hex(), err)
+			}
+			continue
+		}
+		if err := s.store.Delete(ctx, upload.Key); err != nil {
+			log.Printf("Failed to delete upload %s from storage: %v", id.Hex(), err)
+			continue
+		}
+		if _, err := uploads.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+			log.Printf("Failed to delete upload %s: %v", id.Hex(), err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
+
+// attachmentFor is the reference to a verified upload stored on the profile
+// or need it is attached to
+func attachmentFor(upload *models.Upload) models.Attachment {
+	return models.Attachment{
+		UploadID:    upload.ID,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		AttachedAt:  time.Now(),
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/vectors"
+)
+
+// Matching scores an embedding's nearest neighbors rather than every
+// profile when vector search is configured. It oversamples so the distance,
+// trust, eligibility, and availability rules applied afterwards still leave
+// enough matches.
+const (
+	vectorOversample    = 25
+	minVectorCandidates = 250
+)
+
+// vectorSyncBatch is how many documents are read per batch when syncing
+const vectorSyncBatch = 100
+
+// ErrVectorSearchDisabled is returned when vector search is not configured
+var ErrVectorSearchDisabled = errors.New("vector search is not configured")
+
+// openNeedStatuses are the statuses a need can be matched in, or return to
+var openNeedStatuses = []models.NeedStatus{models.NeedStatusRequested, models.NeedStatusMatched}
+
+// vectorCandidates is how many nearest neighbors to score for limit matches
+func vectorCandidates(limit int) int {
+	return min(max(limit*vectorOversample, minVectorCandidates), vectors.MaxTopK)
+}
+
+// candidateVolunteers returns the active volunteers to score for need: those
+// matching near when it is set, or else its nearest neighbors when vector
+// search is configured, or else all of them
+func (m *MatchingService) candidateVolunteers(ctx context.Context, need *models.Need, limit int, near bson.M) ([]models.Volunteer, error) {
+	if near != nil {
+		return m.findActiveVolunteers(ctx, near)
+	}
+	if m.vectors == nil || len(need.Embedding) == 0 {
+		return m.getActiveVolunteers(ctx)
+	}
+	ids, err := m.nearest(ctx, vectors.NamespaceVolunteers, need.Embedding, limit)
+	if err != nil {
+		log.Printf("Vector search for need %s failed, scanning all volunteers: %v", need.ID.Hex(), err)
+		return m.getActiveVolunteers(ctx)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	volunteers, err := m.findActiveVolunteers(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[primitive.ObjectID]bool, len(volunteers))
+	for _, volunteer := range volunteers {
+		found[volunteer.ID] = true
+	}
+	m.pruneVectors(ctx, vectors.NamespaceVolunteers, "volunteers", ids, found, bson.M{})
+	return volunteers, nil
+}
+
+// candidateNeeds returns the active needs to score for volunteer: those
+// matching near when it is set, or else their nearest neighbors when vector
+// search is configured, or else all of them
+func (m *MatchingService) candidateNeeds(ctx context.Context, volunteer *models.Volunteer, limit int, near bson.M) ([]models.Need, error) {
+	if near != nil {
+		return m.findActiveNeeds(ctx, near)
+	}
+	if m.vectors == nil || len(volunteer.Embedding) == 0 {
+		return m.getActiveNeeds(ctx)
+	}
+	ids, err := m.nearest(ctx, vectors.NamespaceNeeds, volunteer.Embedding, limit)
+	if err != nil {
+		log.Printf("Vector search for volunteer %s failed, scanning all needs: %v", volunteer.ID.Hex(), err)
+		return m.getActiveNeeds(ctx)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	needs, err := m.findActiveNeeds(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[primitive.ObjectID]bool, len(needs))
+	for _, need := range needs {
+		found[need.ID] = true
+	}
+	m.pruneVectors(ctx, vectors.NamespaceNeeds, "needs", ids, found, bson.M{"status": bson.M{"$in": openNeedStatuses}})
+	return needs, nil
+}
+
+// nearest returns the IDs of the documents in namespace whose embeddings are
+// closest to embedding, enough to find limit matches among
+func (m *MatchingService) nearest(ctx context.Context, namespace string, embedding []float32, limit int) ([]primitive.ObjectID, error) {
+	results, err := m.vectors.Query(ctx, namespace, embedding, vectorCandidates(limit))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(results))
+	for _, result := range results {
+		if id, err := primitive.ObjectIDFromHex(result.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// pruneVectors deletes the vectors of documents a search returned that are
+// gone for good: deleted, or no longer matching keep. Documents only
+// temporarily out of matching, such as hidden ones, keep their vectors. It
+// is best-effort, so failures are logged.
+func (m *MatchingService) pruneVectors(ctx context.Context, namespace, collection string, ids []primitive.ObjectID, found map[primitive.ObjectID]bool, keep bson.M) {
+	if m.dryRun {
+		return
+	}
+	var missing []primitive.ObjectID
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": missing}}
+	for key, value := range keep {
+		filter[key] = value
+	}
+	kept, err := m.mongoClient.GetCollection(collection).Distinct(ctx, "_id", filter)
+	if err != nil {
+		log.Printf("Failed to check stale %s vectors: %v", namespace, err)
+		return
+	}
+	keeping := make(map[primitive.ObjectID]bool, len(kept))
+	for _, id := range kept {
+		if objectID, ok := id.(primitive.ObjectID); ok {
+			keeping[objectID] = true
+		}
+	}
+
+	var stale []string
+	for _, id := range missing {
+		if !keeping[id] {
+			stale = append(stale, id.Hex())
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+	if err := m.vectors.Delete(ctx, namespace, stale); err != nil {
+		log.Printf("Failed to delete %d stale %s vectors: %v", len(stale), namespace, err)
+	}
+}
+
+// indexVector stores a document's embedding for vector search. Mongo holds
+// the embedding of record, so failures are logged and repaired by syncing.
+func (m *MatchingService) indexVector(ctx context.Context, namespace string, id primitive.ObjectID, embedding []float32) {
+	if m.vectors == nil || len(embedding) == 0 {
+		return
+	}
+	if err := m.vectors.Upsert(ctx, namespace, []vectors.Vector{{ID: id.Hex(), Values: embedding}}); err != nil {
+		log.Printf("Failed to index %s vector %s: %v", namespace, id.Hex(), err)
+	}
+}
+
+// SyncVectors stores every open need's and every volunteer's embedding for
+// vector search, e.g. after enabling it or losing the index. It returns how
+// many vectors of each kind were stored.
+func (m *MatchingService) SyncVectors(ctx context.Context) (needs, volunteers int, err error) {
+	if m.vectors == nil {
+		return 0, 0, ErrVectorSearchDisabled
+	}
+	withEmbedding := bson.M{"embedding.0": bson.M{"$exists": true}}
+
+	needs, err = m.syncVectors(ctx, vectors.NamespaceNeeds, "needs",
+		bson.M{"$and": []bson.M{withEmbedding, {"status": bson.M{"$in": openNeedStatuses}}}})
+	if err != nil {
+		return needs, 0, err
+	}
+	volunteers, err = m.syncVectors(ctx, vectors.NamespaceVolunteers, "volunteers", withEmbedding)
+	return needs, volunteers, err
+}
+
+// syncVectors stores the embeddings of the documents in collection
+// matching filter
+func (m *MatchingService) syncVectors(ctx context.Context, namespace, collection string, filter bson.M) (int, error) {
+	cursor, err := m.mongoClient.GetCollection(collection).Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"embedding": 1}).SetBatchSize(vectorSyncBatch))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	synced := 0
+	batch := make([]vectors.Vector, 0, vectorSyncBatch)
+	flush := func() error {
+		if err := m.vectors.Upsert(ctx, namespace, batch); err != nil {
+			return fmt.Errorf("failed to upsert %s vectors: %w", namespace, err)
+		}
+		synced += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Embedding []float32          `bson:"embedding"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return synced, err
+		}
+		batch = append(batch, vectors.Vector{ID: doc.ID.Hex(), Values: doc.Embedding})
+		if len(batch) == vectorSyncBatch {
+			if err := flush(); err != nil {
+				return synced, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return synced, err
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return synced, err
+		}
+	}
+	return synced, nil
+}
+
+// IndexVolunteers stores volunteers' embeddings for vector search, for
+// volunteers written to the database without going through the embedding
+// jobs. It does nothing when vector search is not configured.
+func (m *MatchingService) IndexVolunteers(ctx context.Context, volunteers []models.Volunteer) error {
+	if m.vectors == nil {
+		return nil
+	}
+	for start := 0; start < len(volunteers); start += vectorSyncBatch {
+		batch := make([]vectors.Vector, 0, vectorSyncBatch)
+		for _, volunteer := range volunteers[start:min(start+vectorSyncBatch, len(volunteers))] {
+			if len(volunteer.Embedding) > 0 {
+				batch = append(batch, vectors.Vector{ID: volunteer.ID.Hex(), Values: volunteer.Embedding})
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		if err := m.vectors.Upsert(ctx, vectors.NamespaceVolunteers, batch); err != nil {
+			return fmt.Errorf("failed to upsert %s vectors: %w", vectors.NamespaceVolunteers, err)
+		}
+	}
+	return nil
+}
+
+// UnindexVolunteers removes the vectors IndexVolunteers stored for the
+// volunteers with ids
+func (m *MatchingService) UnindexVolunteers(ctx context.Context, ids []primitive.ObjectID) error {
+	if m.vectors == nil {
+		return nil
+	}
+	for start := 0; start < len(ids); start += vectorSyncBatch {
+		batch := make([]string, 0, vectorSyncBatch)
+		for _, id := range ids[start:min(start+vectorSyncBatch, len(ids))] {
+			batch = append(batch, id.Hex())
+		}
+		if err := m.vectors.Delete(ctx, vectors.NamespaceVolunteers, batch); err != nil {
+			return fmt.Errorf("failed to delete %s vectors: %w", vectors.NamespaceVolunteers, err)
+		}
+	}
+	return nil
+}
+
+// DeleteVectors removes the vectors of a user's needs and volunteer profile,
+// e.g. when their account is erased
+func (m *MatchingService) DeleteVectors(ctx context.Context, userID primitive.ObjectID) error {
+	if m.vectors == nil {
+		return nil
+	}
+	for _, target := range []struct{ namespace, collection string }{
+		{vectors.NamespaceNeeds, "needs"},
+		{vectors.NamespaceVolunteers, "volunteers"},
+	} {
+		ids, err := m.mongoClient.GetCollection(target.collection).Distinct(ctx, "_id", bson.M{"user_id": userID})
+		if err != nil {
+			return err
+		}
+		hexIDs := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if objectID, ok := id.(primitive.ObjectID); ok {
+				hexIDs = append(hexIDs, objectID.Hex())
+			}
+		}
+		if len(hexIDs) == 0 {
+			continue
+		}
+		if err := m.vectors.Delete(ctx, target.namespace, hexIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// Velocity findings, recorded as moderation reasons
+const (
+	VelocityPostingBurst  = "posting_burst"
+	VelocityDuplicateText = "duplicate_text"
+	VelocitySharedIP      = "shared_ip"
+)
+
+// VelocityLimits configures the velocity checks; a zero limit disables a check
+type VelocityLimits struct {
+	BurstLimit        int // posts per BurstWindow before the poster is throttled
+	BurstWindow       time.Duration
+	Throttle          time.Duration // how long a bursting poster is blocked
+	DuplicateAccounts int           // distinct accounts posting the same text
+	AccountsPerIP     int           // accounts registered from one IP
+	TrackingWindow    time.Duration // how long texts and IPs are remembered
+}
+
+// fingerprintNoise is stripped before hashing so that texts differing only in
+// links, numbers, punctuation, or spacing are treated as duplicates
+var fingerprintNoise = regexp.MustCompile(`https?://\S+|[^\pL]+`)
+
+// VelocityDetector spots spam by how content is posted rather than what it
+// says: posting bursts, the same text across accounts, and many accounts from
+// one IP. Counters live in Redis so every instance sees the same activity.
+type VelocityDetector struct {
+	redisClient *database.RedisClient
+	moderation  *ModerationService
+	limits      VelocityLimits
+}
+
+// NewVelocityDetector creates a detector. Accounts registered from a busy IP
+// are queued for review through moderationService.
+func NewVelocityDetector(redisClient *database.RedisClient, moderationService *ModerationService, limits VelocityLimits) *VelocityDetector {
+	return &VelocityDetector{
+		redisClient: redisClient,
+		moderation:  moderationService,
+		limits:      limits,
+	}
+}
+
+// Throttled reports how much longer a user is blocked from posting, or zero
+// when they may post. Redis errors fail open.
+func (v *VelocityDetector) Throttled(ctx context.Context, userID primitive.ObjectID) time.Duration {
+	if v == nil {
+		return 0
+	}
+
+	ttl, err := v.redisClient.TTL(ctx, "velocity:throttle:"+userID.Hex())
+	if err != nil {
+		log.Printf("Throttle check failed for user %s: %v", userID.Hex(), err)
+		return 0
+	}
+	if ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// CheckPost counts a new post and returns the velocity findings against it.
+// A poster who exceeds the burst limit is also throttled. Redis errors are
+// logged and skip the affected check.
+func (v *VelocityDetector) CheckPost(ctx context.Context, userID primitive.ObjectID, ip, text string) []string {
+	if v == nil {
+		return nil
+	}
+
+	var reasons []string
+	if v.limits.BurstLimit > 0 {
+		window := int64(v.limits.BurstWindow.Seconds())
+		key := "velocity:posts:" + userID.Hex() + ":" + strconv.FormatInt(time.Now().Unix()/window, 10)
+		burst, err := v.redisClient.IsRateLimited(ctx, key, v.limits.BurstLimit, v.limits.BurstWindow)
+		if err != nil {
+			log.Printf("Posting burst check failed for user %s: %v", userID.Hex(), err)
+		} else if burst {
+			reasons = append(reasons, VelocityPostingBurst)
+			if err := v.redisClient.Set(ctx, "velocity:throttle:"+userID.Hex(), time.Now().Unix(), v.limits.Throttle); err != nil {
+				log.Printf("Failed to throttle user %s: %v", userID.Hex(), err)
+			}
+		}
+	}
+
+	if fingerprint := textFingerprint(text); v.limits.DuplicateAccounts > 0 && fingerprint != "" {
+		accounts, err := v.redisClient.AddToSet(ctx, "velocity:text:"+fingerprint, userID.Hex(), v.limits.TrackingWindow)
+		if err != nil {
+			log.Printf("Duplicate text check failed for user %s: %v", userID.Hex(), err)
+		} else if accounts >= int64(v.limits.DuplicateAccounts) {
+			reasons = append(reasons, VelocityDuplicateText)
+		}
+	}
+
+	if v.limits.AccountsPerIP > 0 && ip != "" {
+		accounts, err := v.redisClient.SetSize(ctx, "velocity:ip:"+ip)
+		if err != nil {
+			log.Printf("Shared IP check failed for %s: %v", ip, err)
+		} else if accounts >= int64(v.limits.AccountsPerIP) {
+			reasons = append(reasons, VelocitySharedIP)
+		}
+	}
+
+	return reasons
+}
+
+// RecordAccount remembers the IP a new account registered from, queueing the
+// account for review once too many accounts share that IP
+func (v *VelocityDetector) RecordAccount(ctx context.Context, ip string, user *models.User) {
+	if v == nil || v.limits.AccountsPerIP <= 0 || ip == "" {
+		return
+	}
+
+	accounts, err := v.redisClient.AddToSet(ctx, "velocity:ip:"+ip, user.ID.Hex(), v.limits.TrackingWindow)
+	if err != nil {
+		log.Printf("Failed to record registration IP for user %s: %v", user.ID.Hex(), err)
+		return
+	}
+	if accounts >= int64(v.limits.AccountsPerIP) {
+		v.moderation.Queue(ctx, models.ContentUser, user.ID, user.ID, user.Name, []string{VelocitySharedIP})
+	}
+}
+
+// textFingerprint hashes the letters of text, or returns "" when too little
+// remains to compare meaningfully
+func textFingerprint(text string) string {
+	normalized := strings.Join(strings.Fields(fingerprintNoise.ReplaceAllString(strings.ToLower(text), " ")), " ")
+	if len(normalized) < 20 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+} 
\ No newline at end of file
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/settings"
+)
+
+// WaitlistService keeps needs matching found nobody for, and matches them
+// against volunteers as they join or change their profiles
+type WaitlistService struct {
+	mongoClient *database.MongoClient
+	matching    *MatchingService
+	sla         *SLAService
+	settings    *settings.Store
+	notify      Notifier
+}
+
+// NewWaitlistService creates a waitlist service. Both sides of each match
+// are told through notify, and first matches are recorded on slaService.
+func NewWaitlistService(mongoClient *database.MongoClient, matchingService *MatchingService, slaService *SLAService, settingsStore *settings.Store, notify Notifier) *WaitlistService {
+	return &WaitlistService{
+		mongoClient: mongoClient,
+		matching:    matchingService,
+		sla:         slaService,
+		settings:    settingsStore,
+		notify:      notify,
+	}
+}
+
+// Add puts a need nobody has taken on onto the waitlist, setting its
+// WaitlistedAt
+func (s *WaitlistService) Add(ctx context.Context, need *models.Need) error {
+	now := time.Now()
+	_, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx,
+		bson.M{"_id": need.ID, "status": models.NeedStatusRequested, "waitlisted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"waitlisted_at": now}})
+	if err != nil {
+		return fmt.Errorf("failed to waitlist need %s: %w", need.ID.Hex(), err)
+	}
+	if need.WaitlistedAt == nil {
+		need.WaitlistedAt = &now
+	}
+	return nil
+}
+
+// MatchVolunteer matches a volunteer against the waitlisted needs, taking
+// each need it matches off the waitlist and notifying the volunteer and the
+// requester. It returns how many needs were matched.
+func (s *WaitlistService) MatchVolunteer(ctx context.Context, volunteerID primitive.ObjectID) (int, error) {
+	var volunteer models.Volunteer
+	err := s.mongoClient.GetCollection("volunteers").FindOne(ctx, bson.M{"_id": volunteerID}).Decode(&volunteer)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load volunteer %s: %w", volunteerID.Hex(), err)
+	}
+	if len(volunteer.Embedding) == 0 || volunteer.Suspension != nil {
+		return 0, nil
+	}
+
+	needs, err := s.matching.findActiveNeeds(ctx, bson.M{
+		"status":        models.NeedStatusRequested,
+		"waitlisted_at": bson.M{"$exists": true},
+		"user_id":       bson.M{"$ne": volunteer.UserID},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get waitlisted needs: %w", err)
+	}
+	if len(needs) == 0 {
+		return 0, nil
+	}
+	matches, err := s.matching.matchNeeds(ctx, &volunteer, needs, s.settings.Get(ctx).NotificationFanout)
+	if err != nil {
+		return 0, err
+	}
+
+	byID := make(map[primitive.ObjectID]*models.Need, len(needs))
+	for i := range needs {
+		byID[needs[i].ID] = &needs[i]
+	}
+	matched := 0
+	for _, match := range matches {
+		need := byID[match.NeedID]
+		claimed, err := s.take(ctx, need.ID)
+		if err != nil {
+			return matched, err
+		}
+		if !claimed {
+			continue // another volunteer was matched first
+		}
+		matched++
+		s.sla.Record(ctx, need.ID, models.MilestoneFirstMatch, time.Now())
+		s.announce(ctx, need, match)
+	}
+	return matched, nil
+}
+
+// take removes a need from the waitlist, reporting false when it had
+// already left
+func (s *WaitlistService) take(ctx context.Context, needID primitive.ObjectID) (bool, error) {
+	result, err := s.mongoClient.GetCollection("needs").UpdateOne(ctx,
+		bson.M{"_id": needID, "waitlisted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"waitlisted_at": ""}})
+	if err != nil {
+		return false, fmt.Errorf("failed to take need %s off the waitlist: %w", needID.Hex(), err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// announce tells the volunteer about the need, in their own units, and the
+// requester that someone who can help has appeared. Failures are logged,
+// since the need has already left the waitlist.
+func (s *WaitlistService) announce(ctx context.Context, need *models.Need, match models.Match) {
+	recipients, err := s.matching.Recipients(ctx, []models.Match{match})
+	if err != nil {
+		log.Printf("Failed to look up volunteer for waitlisted need %s: %v", need.ID.Hex(), err)
+	}
+	for _, recipient := range recipients {
+		if err := s.notify(ctx, []string{recipient.UserID}, NewNeedMessage(*need, recipient)); err != nil {
+			log.Printf("Failed to notify volunteer of waitlisted need %s: %v", need.ID.Hex(), err)
+		}
+	}
+	s.matching.MarkNotified(ctx, need.ID, recipients)
+
+	message := models.WebSocketMessage{
+		Type: "waitlist_matched",
+		Payload: map[string]interface{}{
+			"need_id": need.ID.Hex(),
+		},
+	}
+	if err := s.notify(ctx, []string{need.UserID.Hex()}, message); err != nil {
+		log.Printf("Failed to notify requester of waitlisted need %s: %v", need.ID.Hex(), err)
+	}
+} 
\ No newline at end of file
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"sync"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/gorilla/websocket"
 	"neighborenexus/internal/models"
+	"neighborenexus/internal/sanitize"
+	"neighborenexus/internal/telemetry"
 )
 
 // WebSocketService handles real-time WebSocket connections
@@ -19,35 +22,78 @@ type WebSocketService struct {
 	register   chan *WebSocketClient
 	unregister chan *WebSocketClient
 	mutex      sync.RWMutex
+
+	// presence shares which users are connected with the other instances
+	presence *Presence
+	// offline receives messages for users not connected to this instance
+	offline func(ctx context.Context, userIDs []string, message models.WebSocketMessage) error
+	// inbox keeps what is sent to users for when they were offline
+	inbox *InboxService
+	// topics authorizes topic subscriptions; nil turns them off
+	topics *TopicService
+	// pumps counts connections still open, for draining on shutdown
+	pumps sync.WaitGroup
 }
 
+// maxTopicsPerClient caps the topics one connection may subscribe to
+const maxTopicsPerClient = 20
+
 // WebSocketClient represents a connected WebSocket client
 type WebSocketClient struct {
-	ID       string
-	UserID   string
-	Conn     *websocket.Conn
-	Send     chan []byte
-	Service  *WebSocketService
+	ID      string
+	UserID  string
+	Conn    *websocket.Conn
+	Send    chan []byte
+	Service *WebSocketService
+
+	// subscriptions are the topics the client follows, guarded by the
+	// service's mutex
+	subscriptions map[string]bool
 }
 
-// NewWebSocketService creates a new WebSocket service
-func NewWebSocketService() *WebSocketService {
-	return &WebSocketService{
+// NewWebSocketService creates a new WebSocket service. Presence and the
+// offline fallback are optional; without them messages for users not
+// connected to this instance are dropped. Messages sent to users are kept in
+// inboxService, when set. Clients subscribe to the topics topicService
+// allows them.
+func NewWebSocketService(presence *Presence, offline func(ctx context.Context, userIDs []string, message models.WebSocketMessage) error, inboxService *InboxService, topicService *TopicService) *WebSocketService {
+	ws := &WebSocketService{
 		clients:    make(map[string]*WebSocketClient),
 		broadcast:  make(chan models.WebSocketMessage),
 		register:   make(chan *WebSocketClient),
 		unregister: make(chan *WebSocketClient),
+		presence:   presence,
+		offline:    offline,
+		inbox:      inboxService,
+		topics:     topicService,
 	}
+	telemetry.CountWebSocketConnections(ws.ConnectionCount)
+	return ws
 }
 
-// Start starts the WebSocket service
-func (ws *WebSocketService) Start() {
+// Start runs the WebSocket service until ctx is cancelled, then closes all client connections
+func (ws *WebSocketService) Start(ctx context.Context) {
+	// A nil channel never fires, so the heartbeat is off without presence
+	var heartbeat <-chan time.Time
+	if ws.presence != nil {
+		ticker := time.NewTicker(presenceHeartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
 	for {
 		select {
+		case <-ctx.Done():
+			ws.closeAll()
+			return
+
 		case client := <-ws.register:
 			ws.mutex.Lock()
 			ws.clients[client.ID] = client
 			ws.mutex.Unlock()
+			if ws.presence != nil {
+				ws.presence.Touch(ctx, []string{client.UserID})
+			}
 			log.Printf("WebSocket client registered: %s (User: %s)", client.ID, client.UserID)
 
 		case client := <-ws.unregister:
@@ -57,10 +103,53 @@ func (ws *WebSocketService) Start() {
 				close(client.Send)
 			}
 			ws.mutex.Unlock()
+			if ws.presence != nil && !ws.IsUserConnected(client.UserID) {
+				ws.presence.Leave(ctx, client.UserID)
+			}
 			log.Printf("WebSocket client unregistered: %s (User: %s)", client.ID, client.UserID)
 
 		case message := <-ws.broadcast:
 			ws.broadcastMessage(message)
+
+		case <-heartbeat:
+			ws.presence.Touch(ctx, ws.GetConnectedUsers())
+		}
+	}
+}
+
+// Drain disconnects every client, telling them the server is going away so
+// they reconnect to another instance, and waits until their connections
+// are closed or ctx ends. WebSocket connections are hijacked from the HTTP
+// server, so shutting it down leaves them open.
+func (ws *WebSocketService) Drain(ctx context.Context) {
+	ws.closeAll()
+
+	closed := make(chan struct{})
+	go func() {
+		ws.pumps.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+	}
+}
+
+// closeAll disconnects every client; closing Send makes writePump send a close frame
+func (ws *WebSocketService) closeAll() {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	// The hub's context is already cancelled, so presence is cleared with a
+	// fresh one rather than leaving users online until it expires
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for id, client := range ws.clients {
+		close(client.Send)
+		delete(ws.clients, id)
+		if ws.presence != nil {
+			ws.presence.Leave(ctx, client.UserID)
 		}
 	}
 }
@@ -86,69 +175,151 @@ func (ws *WebSocketService) broadcastMessage(message models.WebSocketMessage) {
 	}
 }
 
-// SendToUser sends a message to a specific user
+// SendToUser sends a message to a specific user, falling back to the
+// offline handler when they aren't connected to this instance
 func (ws *WebSocketService) SendToUser(userID string, message models.WebSocketMessage) {
+	ws.SendToMultipleUsers([]string{userID}, message)
+}
+
+// SendToMultipleUsers sends a message to multiple users and keeps it in their
+// inboxes, falling back to the offline handler for those not connected to
+// this instance
+func (ws *WebSocketService) SendToMultipleUsers(userIDs []string, message models.WebSocketMessage) {
+	if err := ws.inbox.Store(context.Background(), "", userIDs, message); err != nil {
+		log.Printf("Failed to keep %s message in %d inboxes: %v", message.Type, len(userIDs), err)
+	}
+	missed := ws.SendToConnected(userIDs, message)
+	if ws.offline == nil || len(missed) == 0 {
+		return
+	}
+	if err := ws.offline(context.Background(), missed, message); err != nil {
+		log.Printf("Failed to hand off message for %d offline users: %v", len(missed), err)
+	}
+}
+
+// SendToConnected sends a message to those of the users connected to this
+// instance, without any fallback, and returns the users it didn't reach
+func (ws *WebSocketService) SendToConnected(userIDs []string, message models.WebSocketMessage) []string {
 	data, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("Error marshaling WebSocket message: %v", err)
-		return
+		return nil
 	}
 
-	ws.mutex.RLock()
-	defer ws.mutex.RUnlock()
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	userIDSet := make(map[string]bool)
+	for _, id := range userIDs {
+		userIDSet[id] = true
+	}
 
+	reached := make(map[string]bool)
 	for _, client := range ws.clients {
-		if client.UserID == userID {
+		if userIDSet[client.UserID] {
 			select {
 			case client.Send <- data:
+				reached[client.UserID] = true
 			default:
 				close(client.Send)
 				delete(ws.clients, client.ID)
 			}
 		}
 	}
+
+	var missed []string
+	for id := range userIDSet {
+		if !reached[id] {
+			missed = append(missed, id)
+		}
+	}
+	return missed
 }
 
-// SendToMultipleUsers sends a message to multiple users
-func (ws *WebSocketService) SendToMultipleUsers(userIDs []string, message models.WebSocketMessage) {
+// SendToTopics sends a message to the clients connected to this instance
+// that follow any of topics, once each
+func (ws *WebSocketService) SendToTopics(topics []string, message models.WebSocketMessage) {
 	data, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("Error marshaling WebSocket message: %v", err)
 		return
 	}
 
-	ws.mutex.RLock()
-	defer ws.mutex.RUnlock()
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
 
-	userIDSet := make(map[string]bool)
-	for _, id := range userIDs {
-		userIDSet[id] = true
+	for _, client := range ws.clients {
+		if !client.follows(topics) {
+			continue
+		}
+		select {
+		case client.Send <- data:
+		default:
+			close(client.Send)
+			delete(ws.clients, client.ID)
+		}
 	}
+}
 
-	for _, client := range ws.clients {
-		if userIDSet[client.UserID] {
-			select {
-			case client.Send <- data:
-			default:
-				close(client.Send)
-				delete(ws.clients, client.ID)
-			}
+// Subscribe adds topic to the client's subscriptions if its user may follow it
+func (ws *WebSocketService) Subscribe(ctx context.Context, client *WebSocketClient, topic string) error {
+	if ws.topics == nil {
+		return ErrUnknownTopic
+	}
+	if err := ws.topics.Authorize(ctx, client.UserID, topic); err != nil {
+		return err
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	if client.subscriptions == nil {
+		client.subscriptions = make(map[string]bool)
+	}
+	if !client.subscriptions[topic] && len(client.subscriptions) >= maxTopicsPerClient {
+		return ErrTooManyTopics
+	}
+	client.subscriptions[topic] = true
+	return nil
+}
+
+// Unsubscribe removes topic from the client's subscriptions
+func (ws *WebSocketService) Unsubscribe(client *WebSocketClient, topic string) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	delete(client.subscriptions, topic)
+}
+
+// follows reports whether the client subscribes to any of topics. The
+// caller holds the service's mutex.
+func (c *WebSocketClient) follows(topics []string) bool {
+	for _, topic := range topics {
+		if c.subscriptions[topic] {
+			return true
 		}
 	}
+	return false
 }
 
-// NotifyNewNeed notifies relevant volunteers about a new need
-func (ws *WebSocketService) NotifyNewNeed(need models.Need, volunteerIDs []string) {
-	message := models.WebSocketMessage{
+// NewNeedMessage builds the notification of a new need for one matched
+// volunteer, with its distance in their preferred units
+func NewNeedMessage(need models.Need, recipient NeedRecipient) models.WebSocketMessage {
+	return models.WebSocketMessage{
 		Type: "new_need",
 		Payload: map[string]interface{}{
-			"need_id": need.ID.Hex(),
-			"title":   need.Title,
-			"urgency": need.Urgency,
+			"need_id":          need.ID.Hex(),
+			"title":            sanitize.RedactContacts(need.Title),
+			"urgency":          need.Urgency,
+			"distance":         recipient.Distance,
+			"distance_display": LocalizeDistance(recipient.Distance, recipient.Units, recipient.Language),
 		},
 	}
+}
 
-	ws.SendToMultipleUsers(volunteerIDs, message)
+// NotifyNewNeed notifies matched volunteers about a new need
+func (ws *WebSocketService) NotifyNewNeed(need models.Need, recipients []NeedRecipient) {
+	for _, recipient := range recipients {
+		ws.SendToUser(recipient.UserID, NewNeedMessage(need, recipient))
+	}
 }
 
 // NotifyNeedAccepted notifies the need creator that their need was accepted
@@ -156,8 +327,8 @@ func (ws *WebSocketService) NotifyNeedAccepted(needID, volunteerID string, volun
 	message := models.WebSocketMessage{
 		Type: "need_accepted",
 		Payload: map[string]interface{}{
-			"need_id":       needID,
-			"volunteer_id":  volunteerID,
+			"need_id":        needID,
+			"volunteer_id":   volunteerID,
 			"volunteer_name": volunteerName,
 		},
 	}
@@ -166,6 +337,22 @@ func (ws *WebSocketService) NotifyNeedAccepted(needID, volunteerID string, volun
 	ws.SendToUser(needID, message)
 }
 
+// NotifyOfferAccepted notifies an offerer that a neighbor took up their
+// offer for a need
+func (ws *WebSocketService) NotifyOfferAccepted(offer models.Offer, task models.Task, requesterName string) {
+	message := models.WebSocketMessage{
+		Type: "offer_accepted",
+		Payload: map[string]interface{}{
+			"offer_id":       offer.ID.Hex(),
+			"need_id":        task.NeedID.Hex(),
+			"task_id":        task.ID.Hex(),
+			"requester_name": requesterName,
+		},
+	}
+
+	ws.SendToUser(offer.UserID.Hex(), message)
+}
+
 // NotifyTaskStatusUpdate notifies users about task status changes
 func (ws *WebSocketService) NotifyTaskStatusUpdate(task models.Task, userIDs []string) {
 	message := models.WebSocketMessage{
@@ -206,6 +393,13 @@ func (ws *WebSocketService) GetConnectedUsers() []string {
 	return userIDs
 }
 
+// ConnectionCount returns how many clients are connected to this instance
+func (ws *WebSocketService) ConnectionCount() int {
+	ws.mutex.RLock()
+	defer ws.mutex.RUnlock()
+	return len(ws.clients)
+}
+
 // IsUserConnected checks if a user is currently connected
 func (ws *WebSocketService) IsUserConnected(userID string) bool {
 	ws.mutex.RLock()
@@ -243,17 +437,67 @@ func (c *WebSocketClient) readPump() {
 			break
 		}
 
-		// Handle incoming messages if needed
-		log.Printf("Received message from client %s: %s", c.ID, string(message))
+		c.handleCommand(message)
+	}
+}
+
+// handleCommand applies a subscribe or unsubscribe command from the client,
+// answering with the outcome. Other messages are ignored.
+func (c *WebSocketClient) handleCommand(data []byte) {
+	var command models.WebSocketCommand
+	if err := json.Unmarshal(data, &command); err != nil {
+		return
+	}
+	if (command.Type == "subscribe" || command.Type == "unsubscribe") && command.Topic == "" {
+		c.reply(models.WebSocketMessage{Type: "subscribe_failed", Payload: map[string]interface{}{"error": "topic required"}})
+		return
+	}
+
+	switch command.Type {
+	case "subscribe":
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := c.Service.Subscribe(ctx, c, command.Topic); err != nil {
+			if !errors.Is(err, ErrUnknownTopic) && !errors.Is(err, ErrTopicNotAllowed) && !errors.Is(err, ErrTooManyTopics) {
+				log.Printf("Failed to subscribe client %s to %s: %v", c.ID, command.Topic, err)
+				err = errors.New("failed to subscribe")
+			}
+			c.reply(models.WebSocketMessage{Type: "subscribe_failed", Payload: map[string]interface{}{"topic": command.Topic, "error": err.Error()}})
+			return
+		}
+		c.reply(models.WebSocketMessage{Type: "subscribed", Payload: map[string]interface{}{"topic": command.Topic}})
+	case "unsubscribe":
+		c.Service.Unsubscribe(c, command.Topic)
+		c.reply(models.WebSocketMessage{Type: "unsubscribed", Payload: map[string]interface{}{"topic": command.Topic}})
+	}
+}
+
+// reply queues a message to the client, dropping it if the client is not
+// keeping up
+func (c *WebSocketClient) reply(message models.WebSocketMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	c.Service.mutex.RLock()
+	defer c.Service.mutex.RUnlock()
+	if _, ok := c.Service.clients[c.ID]; !ok {
+		return
+	}
+	select {
+	case c.Send <- data:
+	default:
 	}
 }
 
 // writePump writes messages to the WebSocket connection
 func (c *WebSocketClient) writePump() {
+	c.Service.pumps.Add(1)
 	ticker := time.NewTicker(54 * time.Second)
 	defer func() {
 		ticker.Stop()
 		c.Conn.Close()
+		c.Service.pumps.Done()
 	}()
 
 	for {
@@ -261,7 +505,7 @@ func (c *WebSocketClient) writePump() {
 		case message, ok := <-c.Send:
 			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if !ok {
-				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
 				return
 			}
 
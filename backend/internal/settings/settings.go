@@ -0,0 +1,266 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/models"
+)
+
+// redisKey holds the JSON-encoded tunables shared by every instance
+const redisKey = "settings:tunables"
+
+// Tunables are operational parameters that can be changed at runtime
+type Tunables struct {
+	MatchThreshold         float64 `json:"match_threshold"`            // minimum combined score for a match
+	DistanceDecayKm        float64 `json:"distance_decay_km"`          // distance at which the distance score falls to 1/e
+	NotificationFanout     int     `json:"notification_fanout"`        // volunteers notified about a new need
+	RateLimitAuthPerMinute int     `json:"rate_limit_auth_per_minute"` // per client IP on auth routes; 0 disables
+	RateLimitAPIPerMinute  int     `json:"rate_limit_api_per_minute"`  // per user on authenticated routes; 0 disables
+	// Categories override the matching tunables for some categories, such
+	// as stricter thresholds and trust for childcare than snow shoveling
+	Categories map[models.Category]CategoryTunables `json:"categories,omitempty"`
+}
+
+// CategoryTunables override matching for one category. Unset fields fall
+// back to the global tunables.
+type CategoryTunables struct {
+	MatchThreshold  *float64          `json:"match_threshold,omitempty"`
+	DistanceDecayKm *float64          `json:"distance_decay_km,omitempty"`
+	MaxDistanceKm   float64           `json:"max_distance_km,omitempty"` // volunteers further away are never matched; 0 means no cutoff
+	MinTrust        models.TrustLevel `json:"min_trust,omitempty"`       // lowest trust level that may be matched with or accept the category's needs
+}
+
+// CategoryMatching is the matching tunables in effect for one category
+type CategoryMatching struct {
+	MatchThreshold  float64
+	DistanceDecayKm float64
+	MaxDistanceKm   float64 // 0 means no cutoff
+	MinTrust        models.TrustLevel
+}
+
+// ForCategory returns the matching tunables for needs in category
+func (t Tunables) ForCategory(category models.Category) CategoryMatching {
+	matching := CategoryMatching{MatchThreshold: t.MatchThreshold, DistanceDecayKm: t.DistanceDecayKm}
+	override, ok := t.Categories[category]
+	if !ok {
+		return matching
+	}
+	if override.MatchThreshold != nil {
+		matching.MatchThreshold = *override.MatchThreshold
+	}
+	if override.DistanceDecayKm != nil {
+		matching.DistanceDecayKm = *override.DistanceDecayKm
+	}
+	matching.MaxDistanceKm = override.MaxDistanceKm
+	matching.MinTrust = override.MinTrust
+	return matching
+}
+
+// Defaults returns the tunables used when nothing has been stored
+func Defaults() Tunables {
+	return Tunables{
+		MatchThreshold:         0.3,
+		DistanceDecayKm:        10,
+		NotificationFanout:     5,
+		RateLimitAuthPerMinute: 20,
+		RateLimitAPIPerMinute:  300,
+	}
+}
+
+// Validate checks that every tunable is within a usable range
+func (t Tunables) Validate() error {
+	switch {
+	case t.MatchThreshold < 0 || t.MatchThreshold > 1:
+		return errors.New("match_threshold must be between 0 and 1")
+	case t.DistanceDecayKm <= 0 || t.DistanceDecayKm > 1000:
+		return errors.New("distance_decay_km must be greater than 0 and at most 1000")
+	case t.NotificationFanout < 1 || t.NotificationFanout > 100:
+		return errors.New("notification_fanout must be between 1 and 100")
+	case t.RateLimitAuthPerMinute < 0 || t.RateLimitAPIPerMinute < 0:
+		return errors.New("rate limits cannot be negative")
+	}
+	for category, override := range t.Categories {
+		switch {
+		case !category.WellFormed():
+			return fmt.Errorf("categories: %q is not a category", category)
+		case override.MatchThreshold != nil && (*override.MatchThreshold < 0 || *override.MatchThreshold > 1):
+			return fmt.Errorf("categories.%s.match_threshold must be between 0 and 1", category)
+		case override.DistanceDecayKm != nil && (*override.DistanceDecayKm <= 0 || *override.DistanceDecayKm > 1000):
+			return fmt.Errorf("categories.%s.distance_decay_km must be greater than 0 and at most 1000", category)
+		case override.MaxDistanceKm < 0 || override.MaxDistanceKm > 1000:
+			return fmt.Errorf("categories.%s.max_distance_km must be between 0 and 1000", category)
+		case override.MinTrust != "" && !override.MinTrust.Valid():
+			return fmt.Errorf("categories.%s.min_trust must be one of: %v", category, override.MinTrust.Values())
+		}
+	}
+	return nil
+}
+
+// Patch is a partial update to the tunables; nil fields are left unchanged
+type Patch struct {
+	MatchThreshold         *float64 `json:"match_threshold,omitempty"`
+	DistanceDecayKm        *float64 `json:"distance_decay_km,omitempty"`
+	NotificationFanout     *int     `json:"notification_fanout,omitempty"`
+	RateLimitAuthPerMinute *int     `json:"rate_limit_auth_per_minute,omitempty"`
+	RateLimitAPIPerMinute  *int     `json:"rate_limit_api_per_minute,omitempty"`
+	// Categories replaces the overrides of the categories given; null
+	// removes a category's override
+	Categories map[models.Category]*CategoryTunables `json:"categories,omitempty"`
+}
+
+// Apply returns t with the patch's non-nil fields applied
+func (p Patch) Apply(t Tunables) Tunables {
+	if p.MatchThreshold != nil {
+		t.MatchThreshold = *p.MatchThreshold
+	}
+	if p.DistanceDecayKm != nil {
+		t.DistanceDecayKm = *p.DistanceDecayKm
+	}
+	if p.NotificationFanout != nil {
+		t.NotificationFanout = *p.NotificationFanout
+	}
+	if p.RateLimitAuthPerMinute != nil {
+		t.RateLimitAuthPerMinute = *p.RateLimitAuthPerMinute
+	}
+	if p.RateLimitAPIPerMinute != nil {
+		t.RateLimitAPIPerMinute = *p.RateLimitAPIPerMinute
+	}
+	if len(p.Categories) > 0 {
+		// Copy so the cached tunables are never modified
+		categories := make(map[models.Category]CategoryTunables, len(t.Categories)+len(p.Categories))
+		for category, override := range t.Categories {
+			categories[category] = override
+		}
+		for category, override := range p.Categories {
+			if override == nil {
+				delete(categories, category)
+			} else {
+				categories[category] = *override
+			}
+		}
+		t.Categories = categories
+	}
+	return t
+}
+
+// Store reads tunables from Redis through a short-lived in-process cache, so
+// changes made on one instance reach the others within the cache TTL
+type Store struct {
+	redisClient *database.RedisClient
+	ttl         time.Duration
+	defaults    Tunables
+
+	mutex    sync.RWMutex
+	cached   Tunables
+	loadedAt time.Time
+}
+
+// NewStore creates a settings store that re-reads Redis at most once per ttl
+// and falls back to defaults for anything that has not been stored
+func NewStore(redisClient *database.RedisClient, ttl time.Duration, defaults Tunables) *Store {
+	return &Store{
+		redisClient: redisClient,
+		ttl:         ttl,
+		defaults:    defaults,
+		cached:      defaults,
+	}
+}
+
+// Defaults returns the tunables this store falls back to
+func (s *Store) Defaults() Tunables {
+	if s == nil {
+		return Defaults()
+	}
+	return s.defaults
+}
+
+// Get returns the current tunables. A nil store returns the defaults, and a
+// Redis failure keeps serving the last known values.
+func (s *Store) Get(ctx context.Context) Tunables {
+	if s == nil {
+		return Defaults()
+	}
+
+	s.mutex.RLock()
+	cached, fresh := s.cached, time.Since(s.loadedAt) < s.ttl
+	s.mutex.RUnlock()
+	if fresh {
+		return cached
+	}
+
+	tunables, err := s.load(ctx)
+	if err != nil {
+		log.Printf("Failed to load settings, using cached values: %v", err)
+		tunables = cached
+	}
+
+	s.mutex.Lock()
+	s.cached, s.loadedAt = tunables, time.Now()
+	s.mutex.Unlock()
+	return tunables
+}
+
+// Update applies a patch, validates the result, and stores it for all instances
+func (s *Store) Update(ctx context.Context, patch Patch) (Tunables, error) {
+	current, err := s.load(ctx)
+	if err != nil {
+		return Tunables{}, err
+	}
+
+	updated := patch.Apply(current)
+	if err := updated.Validate(); err != nil {
+		return Tunables{}, err
+	}
+	return updated, s.save(ctx, updated)
+}
+
+// Reset restores the defaults for all instances
+func (s *Store) Reset(ctx context.Context) (Tunables, error) {
+	if err := s.redisClient.Del(ctx, redisKey); err != nil {
+		return Tunables{}, err
+	}
+	s.remember(s.defaults)
+	return s.defaults, nil
+}
+
+// load reads the stored tunables, filling unset fields with defaults
+func (s *Store) load(ctx context.Context) (Tunables, error) {
+	tunables := s.defaults
+	data, err := s.redisClient.Get(ctx, redisKey)
+	if err == redis.Nil {
+		return tunables, nil
+	}
+	if err != nil {
+		return tunables, err
+	}
+	if err := json.Unmarshal([]byte(data), &tunables); err != nil {
+		return s.defaults, err
+	}
+	return tunables, nil
+}
+
+func (s *Store) save(ctx context.Context, tunables Tunables) error {
+	data, err := json.Marshal(tunables)
+	if err != nil {
+		return err
+	}
+	if err := s.redisClient.Set(ctx, redisKey, data, 0); err != nil {
+		return err
+	}
+	s.remember(tunables)
+	return nil
+}
+
+func (s *Store) remember(tunables Tunables) {
+	s.mutex.Lock()
+	s.cached, s.loadedAt = tunables, time.Now()
+	s.mutex.Unlock()
+} 
\ No newline at end of file
@@ -0,0 +1,238 @@
+// Package storage keeps user uploads in an S3-compatible object store such
+// as AWS S3 or MinIO. Requests are signed with AWS Signature Version 4.
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for an object that is not in the bucket
+var ErrNotFound = errors.New("storage: object not found")
+
+const (
+	// amzDateFormat is the timestamp format Signature Version 4 uses
+	amzDateFormat = "20060102T150405Z"
+	// unsignedPayload skips hashing request bodies; S3 accepts it for
+	// presigned URLs and over TLS
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// emptyPayloadHash is the SHA-256 of an empty body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// S3Client reads, deletes, and presigns uploads of objects in one bucket.
+// Objects are addressed path-style, endpoint/bucket/key, which both AWS S3
+// and MinIO accept.
+type S3Client struct {
+	client    *http.Client
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+}
+
+// NewS3Client creates a client for bucket. An empty endpoint is AWS S3 in
+// region.
+func NewS3Client(endpoint, region, bucket, accessKey, secretKey string) (*S3Client, error) {
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return &S3Client{
+		client:    &http.Client{Timeout: 15 * time.Second},
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}, nil
+}
+
+// PresignPut returns a URL the holder can PUT one object to until it
+// expires. The content type and length are signed, so the store rejects
+// uploads of any other type or size.
+func (c *S3Client) PresignPut(key, contentType string, size int64, expires time.Duration) string {
+	return c.presign(http.MethodPut, key, map[string]string{
+		"content-length": strconv.FormatInt(size, 10),
+		"content-type":   contentType,
+	}, expires)
+}
+
+// PresignGet returns a URL the holder can download an object from until it
+// expires
+func (c *S3Client) PresignGet(key string, expires time.Duration) string {
+	return c.presign(http.MethodGet, key, nil, expires)
+}
+
+// Stat returns the size and content type of an object
+func (c *S3Client) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+	return ObjectInfo{Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// ReadPrefix returns up to the first n bytes of an object
+func (c *S3Client) ReadPrefix(ctx context.Context, key string, n int) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, map[string]string{"range": fmt.Sprintf("bytes=0-%d", n-1)})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, int64(n)))
+}
+
+// Delete removes an object. Deleting an object that is already gone
+// succeeds.
+func (c *S3Client) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a request for an object signed in its Authorization header
+func (c *S3Client) do(ctx context.Context, method, key string, headers map[string]string) (*http.Response, error) {
+	u := c.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	signed := map[string]string{
+		"host":                 u.Host,
+		"x-amz-content-sha256": emptyPayloadHash,
+		"x-amz-date":           now.Format(amzDateFormat),
+	}
+	for name, value := range headers {
+		signed[name] = value
+	}
+	for name, value := range signed {
+		if name != "host" {
+			req.Header.Set(name, value)
+		}
+	}
+	names, canonicalHeaders := canonicalize(signed)
+	canonicalRequest := strings.Join([]string{method, u.EscapedPath(), "", canonicalHeaders, names, emptyPayloadHash}, "\n")
+	scope := c.scope(now)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, names, c.signature(now, scope, canonicalRequest)))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case resp.StatusCode >= 300:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("storage: %s %s returned %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// presign returns a URL for method on an object with the signature in its
+// query, valid for expires. headers must be sent as given.
+func (c *S3Client) presign(method, key string, headers map[string]string, expires time.Duration) string {
+	u := c.objectURL(key)
+	now := time.Now().UTC()
+	scope := c.scope(now)
+
+	signed := map[string]string{"host": u.Host}
+	for name, value := range headers {
+		signed[name] = value
+	}
+	names, canonicalHeaders := canonicalize(signed)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", c.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", names)
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{method, u.EscapedPath(), canonicalQuery, canonicalHeaders, names, unsignedPayload}, "\n")
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + c.signature(now, scope, canonicalRequest)
+	return u.String()
+}
+
+// objectURL is the path-style URL of an object
+func (c *S3Client) objectURL(key string) *url.URL {
+	u := *c.endpoint
+	u.Path = c.endpoint.Path + "/" + c.bucket + "/" + key
+	return &u
+}
+
+// scope is the credential scope for requests signed at now
+func (c *S3Client) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + c.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request with a key derived for its scope
+func (c *S3Client) signature(now time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format(amzDateFormat) + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalize returns the signed header names and canonical header block
+// for lowercase header names and their values
+func canonicalize(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return strings.Join(names, ";"), block.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+} 
\ No newline at end of file
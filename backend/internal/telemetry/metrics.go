@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// registry holds the metrics /metrics exports, along with the Go runtime
+// and process collectors
+var registry = prometheus.NewRegistry()
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "neighborenexus",
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	matchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "neighborenexus",
+		Name:      "match_duration_seconds",
+		Help:      "Time taken to find the volunteers matching a need.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	websocketConnections = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "neighborenexus",
+		Name:      "websocket_connections",
+		Help:      "WebSocket clients connected to this instance.",
+	}, func() float64 {
+		if count, ok := websocketCount.Load().(func() int); ok {
+			return float64(count())
+		}
+		return 0
+	})
+
+	// websocketCount holds the function counting this instance's WebSocket
+	// clients
+	websocketCount atomic.Value
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestDuration,
+		matchDuration,
+		websocketConnections,
+	)
+}
+
+// ObserveMatch records how long matching a need took
+func ObserveMatch(duration time.Duration) {
+	matchDuration.Observe(duration.Seconds())
+}
+
+// CountWebSocketConnections sets the function the WebSocket connection
+// gauge reads when scraped
+func CountWebSocketConnections(count func() int) {
+	websocketCount.Store(count)
+}
+
+// MetricsHandler serves the metrics in the Prometheus text format. When
+// token is set, scrapers must send it as a bearer token.
+func MetricsHandler(token string) gin.HandlerFunc {
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MongoMonitor returns a command monitor that records a span for each
+// MongoDB command, under the span of the request or job that ran it.
+// Command documents are left out, since they hold user data.
+func MongoMonitor() *event.CommandMonitor {
+	var spans sync.Map // request ID to its command's span
+
+	finish := func(requestID int64, failure string) {
+		value, ok := spans.LoadAndDelete(requestID)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		if failure != "" {
+			span.SetStatus(codes.Error, failure)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			attributes := []attribute.KeyValue{semconv.DBSystemMongoDB, semconv.DBName(evt.DatabaseName), semconv.DBOperation(evt.CommandName)}
+			if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+				attributes = append(attributes, semconv.DBMongoDBCollection(collection))
+			}
+			_, span := tracer().Start(ctx, "mongodb."+evt.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attributes...))
+			spans.Store(evt.RequestID, span)
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			finish(evt.RequestID, "")
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			finish(evt.RequestID, evt.Failure)
+		},
+	}
+} 
\ No newline at end of file
@@ -0,0 +1,62 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook records a span for each Redis command and pipeline, under the
+// span of the request or job that ran it. Arguments are left out, since
+// they hold user data.
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+// BeforeProcess starts a command's span
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = tracer().Start(ctx, "redis."+cmd.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperation(cmd.Name())))
+	return ctx, nil
+}
+
+// AfterProcess ends a command's span
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd)
+	return nil
+}
+
+// BeforeProcessPipeline starts a pipeline's span
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx, _ = tracer().Start(ctx, "redis.pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperation("pipeline")))
+	return ctx, nil
+}
+
+// AfterProcessPipeline ends a pipeline's span, marking it failed if any of
+// its commands failed
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			endRedisSpan(ctx, cmd)
+			return nil
+		}
+	}
+	trace.SpanFromContext(ctx).End()
+	return nil
+}
+
+// endRedisSpan ends the span in ctx, marking it failed if cmd failed. A
+// missing key is an answer, not a failure.
+func endRedisSpan(ctx context.Context, cmd redis.Cmder) {
+	span := trace.SpanFromContext(ctx)
+	if err := cmd.Err(); err != nil && err != redis.Nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+} 
\ No newline at end of file
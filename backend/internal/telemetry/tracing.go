@@ -0,0 +1,49 @@
+// Package telemetry traces requests and the calls they make to MongoDB,
+// Redis, and OpenAI with OpenTelemetry, and exports Prometheus metrics.
+// Until Setup turns tracing on, spans are recorded by OpenTelemetry's no-op
+// provider and cost next to nothing.
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"neighborenexus/internal/buildinfo"
+)
+
+// instrumentationName names the tracer spans are recorded with
+const instrumentationName = "neighborenexus/internal/telemetry"
+
+// Setup starts exporting spans over OTLP/HTTP to the collector named by the
+// standard OTEL_EXPORTER_OTLP_* environment variables, sampling sampleRatio
+// of new traces and following the caller's decision for propagated ones. The
+// returned function flushes buffered spans and stops the exporter.
+func Setup(ctx context.Context, serviceName string, sampleRatio float64) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(buildinfo.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// tracer returns the tracer of the current provider, so spans started
+// before Setup are no-ops rather than lost to a stale provider
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+} 
\ No newline at end of file
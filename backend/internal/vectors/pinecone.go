@@ -0,0 +1,209 @@
+// Package vectors keeps need and volunteer embeddings in a vector store, a
+// Pinecone index in production, so matching can find nearest neighbors
+// without scanning every profile.
+package vectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pineconeControlBase is the Pinecone control plane, which names each
+// index's data plane host
+const pineconeControlBase = "https://api.pinecone.io"
+
+// pineconeAPIVersion pins the Pinecone REST API version
+const pineconeAPIVersion = "2024-07"
+
+// Request size limits of the Pinecone data plane
+const (
+	maxUpsertBatch = 100
+	maxDeleteBatch = 1000
+	MaxTopK        = 10000
+)
+
+// Namespaces of the index, one per kind of document
+const (
+	NamespaceNeeds      = "needs"
+	NamespaceVolunteers = "volunteers"
+)
+
+// Vector is an embedding stored under a document ID
+type Vector struct {
+	ID     string    `json:"id"`
+	Values []float32 `json:"values"`
+}
+
+// ScoredVector is a query result: a document ID and its similarity
+type ScoredVector struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// Store keeps embeddings under document IDs, in namespaces, and finds the
+// ones nearest a query vector
+type Store interface {
+	// Upsert stores vectors in namespace, replacing any with the same IDs
+	Upsert(ctx context.Context, namespace string, vectors []Vector) error
+	// Query returns the topK vectors in namespace most similar to values,
+	// most similar first
+	Query(ctx context.Context, namespace string, values []float32, topK int) ([]ScoredVector, error)
+	// Delete removes the vectors with ids from namespace
+	Delete(ctx context.Context, namespace string, ids []string) error
+}
+
+// APIError is an error response from Pinecone
+type APIError struct {
+	Status  int
+	Message string
+}
+
+// Error formats the Pinecone error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("pinecone: %d: %s", e.Status, e.Message)
+}
+
+// PineconeClient upserts, queries, and deletes embeddings through the
+// Pinecone REST API
+type PineconeClient struct {
+	client *http.Client
+	apiKey string
+	index  string
+
+	mu   sync.Mutex
+	host string // data plane host, looked up on first use when not configured
+}
+
+// NewPineconeClient creates a Pinecone client for index, or returns nil when
+// apiKey is empty so deployments without Pinecone match by scanning. host is
+// the index's data plane host; when empty it is looked up from the index name.
+func NewPineconeClient(apiKey, index, host string) *PineconeClient {
+	if apiKey == "" {
+		return nil
+	}
+	return &PineconeClient{
+		client: &http.Client{Timeout: 10 * time.Second},
+		apiKey: apiKey,
+		index:  index,
+		host:   strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://"),
+	}
+}
+
+// Upsert stores vectors in namespace, replacing any with the same IDs
+func (c *PineconeClient) Upsert(ctx context.Context, namespace string, vectors []Vector) error {
+	for start := 0; start < len(vectors); start += maxUpsertBatch {
+		batch := vectors[start:min(start+maxUpsertBatch, len(vectors))]
+		body := map[string]interface{}{"namespace": namespace, "vectors": batch}
+		if err := c.post(ctx, "/vectors/upsert", body, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns the IDs of the topK vectors in namespace most similar to
+// values, most similar first
+func (c *PineconeClient) Query(ctx context.Context, namespace string, values []float32, topK int) ([]ScoredVector, error) {
+	body := map[string]interface{}{
+		"namespace":       namespace,
+		"vector":          values,
+		"topK":            min(topK, MaxTopK),
+		"includeValues":   false,
+		"includeMetadata": false,
+	}
+	var result struct {
+		Matches []ScoredVector `json:"matches"`
+	}
+	if err := c.post(ctx, "/query", body, &result); err != nil {
+		return nil, err
+	}
+	return result.Matches, nil
+}
+
+// Delete removes the vectors with the given IDs from namespace
+func (c *PineconeClient) Delete(ctx context.Context, namespace string, ids []string) error {
+	for start := 0; start < len(ids); start += maxDeleteBatch {
+		batch := ids[start:min(start+maxDeleteBatch, len(ids))]
+		body := map[string]interface{}{"namespace": namespace, "ids": batch}
+		if err := c.post(ctx, "/vectors/delete", body, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataHost returns the index's data plane host, asking the control plane
+// for it the first time
+func (c *PineconeClient) dataHost(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.host != "" {
+		return c.host, nil
+	}
+
+	var index struct {
+		Host string `json:"host"`
+	}
+	if err := c.do(ctx, http.MethodGet, pineconeControlBase+"/indexes/"+url.PathEscape(c.index), nil, &index); err != nil {
+		return "", fmt.Errorf("failed to look up index %q: %w", c.index, err)
+	}
+	if index.Host == "" {
+		return "", fmt.Errorf("index %q has no host yet", c.index)
+	}
+	c.host = index.Host
+	return c.host, nil
+}
+
+// post sends a JSON request to the data plane and decodes the JSON response
+// into out, if it is not nil
+func (c *PineconeClient) post(ctx context.Context, path string, body, out interface{}) error {
+	host, err := c.dataHost(ctx)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, "https://"+host+path, body, out)
+}
+
+// do sends a request and decodes the JSON response into out, if it is not nil
+func (c *PineconeClient) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Api-Key", c.apiKey)
+	req.Header.Set("X-Pinecone-API-Version", pineconeAPIVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{Status: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+} 
\ No newline at end of file
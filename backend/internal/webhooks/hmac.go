@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+)
+
+// SignSHA256 computes an HMAC-SHA256 of payload with secret
+func SignSHA256(secret, payload []byte) []byte {
+	return sign(sha256.New, secret, payload)
+}
+
+// SignSHA1 computes an HMAC-SHA1 of payload with secret
+func SignSHA1(secret, payload []byte) []byte {
+	return sign(sha1.New, secret, payload)
+}
+
+// VerifyHex checks a hex-encoded signature in constant time
+func VerifyHex(expected []byte, signature string) bool {
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, decoded)
+}
+
+// VerifyBase64 checks a base64-encoded signature in constant time
+func VerifyBase64(expected []byte, signature string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, decoded)
+}
+
+func sign(h func() hash.Hash, secret, payload []byte) []byte {
+	mac := hmac.New(h, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+} 
\ No newline at end of file
@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// OutboundEvent is a notification sent to partner systems. Partners should
+// deduplicate on ID, since an event may be delivered more than once.
+type OutboundEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Sender delivers signed events to partner endpoints. Each request carries a
+// NeighborNexus-Signature header of the form t=<unix>,v1=<hex>, where v1 is
+// the HMAC-SHA256 of "<unix>.<body>" with the shared secret.
+type Sender struct {
+	client *http.Client
+	urls   []string
+	secret []byte
+}
+
+// NewSender creates a sender for the given partner endpoints
+func NewSender(urls []string, secret string) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: 10 * time.Second},
+		urls:   urls,
+		secret: []byte(secret),
+	}
+}
+
+// Enabled reports whether any partner endpoints are configured
+func (s *Sender) Enabled() bool {
+	return s != nil && len(s.urls) > 0
+}
+
+// Send posts event to every partner endpoint, returning the combined errors
+// of the deliveries that failed
+func (s *Sender) Send(ctx context.Context, event OutboundEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := hex.EncodeToString(SignSHA256(s.secret, []byte(timestamp+"."+string(body))))
+
+	var errs []error
+	for _, url := range s.urls {
+		if err := s.post(ctx, url, event.Type, body, "t="+timestamp+",v1="+signature); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post delivers one signed request, treating any non-2xx response as failure
+func (s *Sender) post(ctx context.Context, url, eventType string, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("NeighborNexus-Event", eventType)
+	req.Header.Set("NeighborNexus-Signature", signature)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+} 
\ No newline at end of file
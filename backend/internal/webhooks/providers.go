@@ -0,0 +1,205 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature verification errors
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrStaleTimestamp   = errors.New("webhook timestamp outside tolerance")
+)
+
+// Provider verifies and identifies inbound webhooks from one external service
+type Provider interface {
+	// Name is the path segment the provider's webhooks are received on
+	Name() string
+	// Verify checks the request signature against the raw body
+	Verify(r *http.Request, body []byte) error
+	// EventID returns the provider's unique event identifier, if any
+	EventID(r *http.Request, body []byte) string
+}
+
+// StripeProvider verifies Stripe-Signature headers
+type StripeProvider struct {
+	secret    []byte
+	tolerance time.Duration
+}
+
+// NewStripeProvider creates a Stripe webhook provider
+func NewStripeProvider(secret string, tolerance time.Duration) *StripeProvider {
+	return &StripeProvider{secret: []byte(secret), tolerance: tolerance}
+}
+
+// Name returns the provider name
+func (p *StripeProvider) Name() string { return "stripe" }
+
+// Verify checks the "t=<timestamp>,v1=<signature>" header over "<timestamp>.<body>"
+func (p *StripeProvider) Verify(r *http.Request, body []byte) error {
+	header := r.Header.Get("Stripe-Signature")
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+
+	if err := checkTimestamp(timestamp, p.tolerance); err != nil {
+		return err
+	}
+
+	expected := SignSHA256(p.secret, append([]byte(timestamp+"."), body...))
+	for _, signature := range signatures {
+		if VerifyHex(expected, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// EventID returns the Stripe event ID
+func (p *StripeProvider) EventID(r *http.Request, body []byte) string {
+	return jsonID(body)
+}
+
+// CheckrProvider verifies X-Checkr-Signature headers
+type CheckrProvider struct {
+	apiKey []byte
+}
+
+// NewCheckrProvider creates a Checkr webhook provider
+func NewCheckrProvider(apiKey string) *CheckrProvider {
+	return &CheckrProvider{apiKey: []byte(apiKey)}
+}
+
+// Name returns the provider name
+func (p *CheckrProvider) Name() string { return "checkr" }
+
+// Verify checks the hex HMAC-SHA256 of the body keyed with the API key
+func (p *CheckrProvider) Verify(r *http.Request, body []byte) error {
+	signature := r.Header.Get("X-Checkr-Signature")
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if !VerifyHex(SignSHA256(p.apiKey, body), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// EventID returns the Checkr event ID
+func (p *CheckrProvider) EventID(r *http.Request, body []byte) string {
+	return jsonID(body)
+}
+
+// TwilioProvider verifies X-Twilio-Signature headers
+type TwilioProvider struct {
+	authToken []byte
+	baseURL   string
+}
+
+// NewTwilioProvider creates a Twilio webhook provider. Twilio signs the full
+// public URL, so baseURL must be the externally visible scheme and host.
+func NewTwilioProvider(authToken, baseURL string) *TwilioProvider {
+	return &TwilioProvider{authToken: []byte(authToken), baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Name returns the provider name
+func (p *TwilioProvider) Name() string { return "twilio" }
+
+// Verify checks the base64 HMAC-SHA1 of the URL followed by the sorted form parameters
+func (p *TwilioProvider) Verify(r *http.Request, body []byte) error {
+	signature := r.Header.Get("X-Twilio-Signature")
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var payload strings.Builder
+	payload.WriteString(p.baseURL + r.URL.RequestURI())
+	for _, key := range keys {
+		for _, value := range params[key] {
+			payload.WriteString(key + value)
+		}
+	}
+
+	if !VerifyBase64(SignSHA1(p.authToken, []byte(payload.String())), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// EventID returns the message or call SID
+func (p *TwilioProvider) EventID(r *http.Request, body []byte) string {
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	for _, key := range []string{"MessageSid", "CallSid", "SmsSid"} {
+		if sid := params.Get(key); sid != "" {
+			return sid
+		}
+	}
+	return ""
+}
+
+// checkTimestamp rejects Unix timestamps further than tolerance from now
+func checkTimestamp(value string, tolerance time.Duration) error {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	age := time.Since(time.Unix(seconds, 0))
+	if age < 0 {
+		age = -age
+	}
+	if tolerance > 0 && age > tolerance {
+		return ErrStaleTimestamp
+	}
+	return nil
+}
+
+// jsonID extracts the top-level "id" of a JSON payload
+func jsonID(body []byte) string {
+	var payload struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.ID
+} 
\ No newline at end of file
@@ -0,0 +1,127 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"neighborenexus/internal/database"
+)
+
+// Receiver errors
+var (
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	ErrDuplicateEvent  = errors.New("duplicate webhook event")
+)
+
+// Event is a verified inbound webhook
+type Event struct {
+	Provider   string
+	ID         string
+	Header     http.Header
+	Body       []byte
+	ReceivedAt time.Time
+}
+
+// EventHandler processes a verified webhook event
+type EventHandler func(ctx context.Context, event Event) error
+
+// registration pairs a provider with its event handler
+type registration struct {
+	provider Provider
+	handler  EventHandler
+}
+
+// Receiver verifies, deduplicates, and dispatches inbound webhooks
+type Receiver struct {
+	redisClient  *database.RedisClient
+	replayWindow time.Duration
+
+	mutex     sync.RWMutex
+	providers map[string]registration
+}
+
+// NewReceiver creates a webhook receiver. Event IDs are remembered in Redis for
+// replayWindow so redelivered or replayed events are only processed once.
+func NewReceiver(redisClient *database.RedisClient, replayWindow time.Duration) *Receiver {
+	return &Receiver{
+		redisClient:  redisClient,
+		replayWindow: replayWindow,
+		providers:    make(map[string]registration),
+	}
+}
+
+// Register adds a provider and the handler for its events
+func (r *Receiver) Register(provider Provider, handler EventHandler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.providers[provider.Name()] = registration{provider: provider, handler: handler}
+}
+
+// Receive verifies the request signature, rejects replays, and dispatches the event
+func (r *Receiver) Receive(ctx context.Context, providerName string, req *http.Request, body []byte) error {
+	r.mutex.RLock()
+	reg, ok := r.providers[providerName]
+	r.mutex.RUnlock()
+	if !ok {
+		return ErrUnknownProvider
+	}
+
+	if err := reg.provider.Verify(req, body); err != nil {
+		return err
+	}
+
+	event := Event{
+		Provider:   providerName,
+		ID:         reg.provider.EventID(req, body),
+		Header:     req.Header,
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+
+	replayKey := r.replayKey(event)
+	if r.redisClient != nil {
+		first, err := r.redisClient.SetNX(ctx, replayKey, event.ReceivedAt.Unix(), r.replayWindow)
+		if err != nil {
+			return err
+		}
+		if !first {
+			return ErrDuplicateEvent
+		}
+	}
+
+	if reg.handler == nil {
+		return nil
+	}
+
+	if err := reg.handler(ctx, event); err != nil {
+		// Forget the event so the provider's retry is processed
+		if r.redisClient != nil {
+			r.redisClient.Del(ctx, replayKey)
+		}
+		return err
+	}
+	return nil
+}
+
+// replayKey identifies an event for deduplication, falling back to a body hash
+// for providers that don't send event IDs
+func (r *Receiver) replayKey(event Event) string {
+	id := event.ID
+	if id == "" {
+		sum := sha256.Sum256(event.Body)
+		id = hex.EncodeToString(sum[:])
+	}
+	return "webhook:" + event.Provider + ":" + id
+}
+
+// LogEvent is an EventHandler that only records receipt of an event
+func LogEvent(ctx context.Context, event Event) error {
+	log.Printf("Received %s webhook %s (%d bytes)", event.Provider, event.ID, len(event.Body))
+	return nil
+} 
\ No newline at end of file
@@ -1,20 +1,37 @@
 package main
 
 import (
+	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"sort"
+	"strings"
 
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"neighborenexus/internal/config"
-	"neighborenexus/internal/database"
-	"neighborenexus/internal/handlers"
-	"neighborenexus/internal/middleware"
-	"neighborenexus/internal/services"
 )
 
+// command is a subcommand of the backend binary
+type command struct {
+	summary string
+	run     func(cfg *config.Config, args []string) error
+}
+
+// commands lists the subcommands; RUN_MODE picks one when none is given
+var commands = map[string]command{
+	"serve":                  {summary: "run the HTTP API server", run: runServe},
+	"worker":                 {summary: "run background job consumers", run: runWorker},
+	"migrate":                {summary: "create indexes and migrate legacy data", run: runMigrate},
+	"seed":                   {summary: "insert sample users, volunteers, and needs", run: runSeed},
+	"backfill-embeddings":    {summary: "generate embeddings for needs, volunteers, and offers missing them", run: runBackfillEmbeddings},
+	"reindex-vectors":        {summary: "regenerate every need and volunteer embedding", run: runReindexVectors},
+	"sync-vectors":           {summary: "store existing need and volunteer embeddings in the vector index", run: runSyncVectors},
+	"recompute-ratings":      {summary: "recompute reputations, ratings, and task counts from scratch", run: runRecomputeRatings},
+	"bench-matching":         {summary: "benchmark matching latency and recall on synthetic data", run: runBenchMatching},
+	"export-match-decisions": {summary: "export recorded match sets with their outcomes for offline evaluation", run: runExportMatchDecisions},
+	"dead-jobs":              {summary: "list or requeue background jobs that failed on every attempt", run: runDeadJobs},
+}
+
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
@@ -23,106 +40,37 @@ func main() {
 
 	// Initialize configuration
 	cfg := config.Load()
-
-	// Initialize database connections
-	mongoClient, err := database.NewMongoClient(cfg.MongoURI)
-	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
 	}
-	defer mongoClient.Disconnect(nil)
-
-	redisClient := database.NewRedisClient(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
-	defer redisClient.Close()
-
-	// Initialize services
-	authService := services.NewAuthService(mongoClient, cfg.JWTSecret)
-	embeddingService := services.NewEmbeddingService(cfg.OpenAIKey)
-	matchingService := services.NewMatchingService(embeddingService, mongoClient, cfg.PineconeAPIKey, cfg.PineconeIndex)
-	websocketService := services.NewWebSocketService()
-
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	needHandler := handlers.NewNeedHandler(matchingService, websocketService)
-	volunteerHandler := handlers.NewVolunteerHandler(matchingService, websocketService)
-	websocketHandler := handlers.NewWebSocketHandler(websocketService)
-
-	// Setup Gin router
-	router := gin.Default()
-
-	// CORS configuration
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		AllowCredentials: true,
-	}))
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy", "service": "neighborenexus"})
-	})
-
-	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Auth routes
-		auth := api.Group("/auth")
-		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-		}
-
-		// Protected routes
-		protected := api.Group("/")
-		protected.Use(middleware.AuthMiddleware(authService))
-		{
-			// User profile
-			protected.GET("/profile", authHandler.GetProfile)
-			protected.PUT("/profile", authHandler.UpdateProfile)
-
-			// Needs
-			needs := protected.Group("/needs")
-			{
-				needs.POST("/", needHandler.CreateNeed)
-				needs.GET("/", needHandler.GetNeeds)
-				needs.GET("/:id", needHandler.GetNeed)
-				needs.PUT("/:id", needHandler.UpdateNeed)
-				needs.DELETE("/:id", needHandler.DeleteNeed)
-				needs.POST("/:id/accept", needHandler.AcceptNeed)
-			}
-
-			// Volunteers
-			volunteers := protected.Group("/volunteers")
-			{
-				volunteers.POST("/profile", volunteerHandler.CreateProfile)
-				volunteers.GET("/profile", volunteerHandler.GetProfile)
-				volunteers.PUT("/profile", volunteerHandler.UpdateProfile)
-				volunteers.GET("/matches", volunteerHandler.GetMatches)
-			}
+	name, args := cfg.RunMode, os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-			// Tasks
-			tasks := protected.Group("/tasks")
-			{
-				tasks.GET("/", needHandler.GetTasks)
-				tasks.GET("/:id", needHandler.GetTask)
-				tasks.PUT("/:id/status", needHandler.UpdateTaskStatus)
-				tasks.POST("/:id/feedback", needHandler.SubmitFeedback)
-			}
-		}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
 
-		// WebSocket endpoint
-		api.GET("/ws", middleware.AuthMiddleware(authService), websocketHandler.HandleWebSocket)
+	if err := cmd.run(cfg, args); err != nil {
+		log.Fatalf("%s: %v", name, err)
 	}
+}
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// usage prints the available subcommands
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	log.Printf("Starting NeighborNexus server on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", name, commands[name].summary)
 	}
 } 
\ No newline at end of file
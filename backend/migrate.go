@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"neighborenexus/internal/config"
+)
+
+// runMigrate creates indexes and migrates legacy data, failing on any error
+func runMigrate(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flags.Parse(args)
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	if err := a.mongoClient.Migrate(context.Background()); err != nil {
+		return err
+	}
+
+	log.Println("Migrations complete")
+	return nil
+} 
\ No newline at end of file
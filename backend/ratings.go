@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/config"
+)
+
+// runRecomputeRatings rebuilds reputations, ratings, and task counts from
+// feedback and tasks, repairing any drift from missed or failed events
+func runRecomputeRatings(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("recompute-ratings", flag.ExitOnError)
+	user := flags.String("user", "", "recompute only this user ID (default all users)")
+	flags.Parse(args)
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	if *user != "" {
+		userID, err := primitive.ObjectIDFromHex(*user)
+		if err != nil {
+			return fmt.Errorf("invalid --user %q", *user)
+		}
+		if err := a.ratingService.Recompute(ctx, userID); err != nil {
+			return err
+		}
+		log.Printf("Recomputed ratings for %s", userID.Hex())
+		return nil
+	}
+
+	recomputed, err := a.ratingService.RecomputeAll(ctx)
+	if err != nil {
+		return fmt.Errorf("after %d users: %w", recomputed, err)
+	}
+	log.Printf("Recomputed ratings for %d users", recomputed)
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/models"
+)
+
+// seedDateOfBirth is the date of birth given to every seeded user
+const seedDateOfBirth = "1985-06-15"
+
+// seedUser is a sample account; volunteers also get a volunteer profile
+type seedUser struct {
+	email     string
+	name      string
+	role      string
+	lat, lng  float64
+	volunteer *models.CreateVolunteerRequest
+	needs     []models.CreateNeedRequest
+}
+
+var seedUsers = []seedUser{
+	{email: "admin@example.org", name: "Avery Admin", role: models.RoleAdmin, lat: 47.6062, lng: -122.3321},
+	{email: "morgan@example.org", name: "Morgan Moderator", role: models.RoleModerator, lat: 47.6101, lng: -122.3420},
+	{
+		email: "rosa@example.org", name: "Rosa Neighbor", lat: 47.6097, lng: -122.3331,
+		needs: []models.CreateNeedRequest{
+			{Title: "Weekly grocery pickup", Description: "I recently had knee surgery and need someone to pick up groceries on Saturdays.", Category: models.CategoryGroceries, Urgency: models.UrgencyMedium, Duration: 60},
+			{Title: "Ride to physical therapy", Description: "Looking for a ride to and from my PT appointment on Tuesday morning.", Category: models.CategoryTransportation, Urgency: models.UrgencyHigh, Duration: 90},
+		},
+	},
+	{
+		email: "sam@example.org", name: "Sam Neighbor", lat: 47.6150, lng: -122.3200,
+		needs: []models.CreateNeedRequest{
+			{Title: "Help setting up a new laptop", Description: "I need help moving files and setting up video calls with my grandkids.", Category: models.CategoryTechnology, Urgency: models.UrgencyLow, Duration: 45},
+		},
+	},
+	{
+		email: "jordan@example.org", name: "Jordan Volunteer", lat: 47.6080, lng: -122.3350,
+		volunteer: &models.CreateVolunteerRequest{
+			Skills:      []string{"driving", "grocery shopping", "errands"},
+			Interests:   []string{"seniors", "accessibility"},
+			Description: "I have a car and free weekends, happy to drive or run errands.",
+			Availability: []models.Availability{
+				{DayOfWeek: 6, StartTime: "09:00", EndTime: "17:00"},
+				{DayOfWeek: 0, StartTime: "10:00", EndTime: "14:00"},
+			},
+		},
+	},
+	{
+		email: "kai@example.org", name: "Kai Volunteer", lat: 47.6120, lng: -122.3250,
+		volunteer: &models.CreateVolunteerRequest{
+			Skills:      []string{"computers", "tutoring", "phone setup"},
+			Interests:   []string{"technology", "education"},
+			Description: "Software developer who enjoys helping neighbors get comfortable with technology.",
+			Availability: []models.Availability{
+				{DayOfWeek: 2, StartTime: "18:00", EndTime: "21:00"},
+				{DayOfWeek: 4, StartTime: "18:00", EndTime: "21:00"},
+			},
+		},
+	},
+}
+
+// runSeed inserts sample users, volunteer profiles, and needs for local development
+func runSeed(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	password := flags.String("password", "neighbor123", "password for every seeded account")
+	force := flags.Bool("force", false, "allow seeding a production database")
+	flags.Parse(args)
+
+	if cfg.Environment == config.EnvProduction && !*force {
+		return errors.New("refusing to seed a production database without --force")
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	for _, su := range seedUsers {
+		location := a.privacyService.IndexLocation(models.Location{
+			Latitude:  su.lat,
+			Longitude: su.lng,
+		}, models.PrivacySettings{})
+
+		user, err := a.authService.Register(ctx, models.RegisterRequest{
+			Email:    su.email,
+			Password: *password,
+			Name:     su.name,
+			Location: location,
+			// Seeded users are adults so every category can be exercised
+			DateOfBirth: seedDateOfBirth,
+		})
+		if err != nil {
+			log.Printf("Skipping %s: %v", su.email, err)
+			continue
+		}
+
+		if su.role != "" {
+			if _, err := a.mongoClient.GetCollection("users").UpdateOne(ctx,
+				bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"role": su.role}}); err != nil {
+				return fmt.Errorf("failed to set role for %s: %w", su.email, err)
+			}
+		}
+
+		if su.volunteer != nil {
+			if err := seedVolunteer(ctx, a, user.ID, location, su.volunteer); err != nil {
+				return fmt.Errorf("failed to seed volunteer %s: %w", su.email, err)
+			}
+		}
+
+		for _, req := range su.needs {
+			if err := seedNeed(ctx, a, user.ID, location, req); err != nil {
+				return fmt.Errorf("failed to seed need for %s: %w", su.email, err)
+			}
+		}
+
+		log.Printf("Seeded %s", su.email)
+	}
+
+	if !a.embeddingService.IsAvailable() {
+		log.Println("Embeddings were not generated; run backfill-embeddings once OPENAI_API_KEY is set")
+	}
+	return nil
+}
+
+func seedVolunteer(ctx context.Context, a *app, userID primitive.ObjectID, location models.Location, req *models.CreateVolunteerRequest) error {
+	now := time.Now()
+	volunteer := models.Volunteer{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		Skills:       req.Skills,
+		Interests:    req.Interests,
+		Description:  req.Description,
+		Availability: req.Availability,
+		Location:     location,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if _, err := a.mongoClient.GetCollection("volunteers").InsertOne(ctx, volunteer); err != nil {
+		return err
+	}
+
+	if a.embeddingService.IsAvailable() {
+		return a.matchingService.UpdateVolunteerEmbedding(ctx, &volunteer)
+	}
+	return nil
+}
+
+func seedNeed(ctx context.Context, a *app, userID primitive.ObjectID, location models.Location, req models.CreateNeedRequest) error {
+	now := time.Now()
+	expiresAt := now.Add(7 * 24 * time.Hour)
+	need := models.Need{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		Category:    req.Category,
+		Urgency:     req.Urgency,
+		Duration:    req.Duration,
+		Location:    location,
+		Point:       a.privacyService.LocationPoint(location),
+		Status:      models.NeedStatusRequested,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ExpiresAt:   &expiresAt,
+	}
+	if _, err := a.mongoClient.GetCollection("needs").InsertOne(ctx, need); err != nil {
+		return err
+	}
+
+	if a.embeddingService.IsAvailable() {
+		return a.matchingService.UpdateNeedEmbedding(ctx, &need)
+	}
+	return nil
+} 
\ No newline at end of file
@@ -0,0 +1,706 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"neighborenexus/internal/background"
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/database"
+	"neighborenexus/internal/graph"
+	"neighborenexus/internal/handlers"
+	"neighborenexus/internal/jobs"
+	"neighborenexus/internal/middleware"
+	"neighborenexus/internal/models"
+	"neighborenexus/internal/services"
+	"neighborenexus/internal/telemetry"
+	"neighborenexus/internal/webhooks"
+)
+
+// runServe runs the HTTP API server until SIGINT or SIGTERM
+func runServe(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	skipMigrate := flags.Bool("skip-migrate", false, "don't create indexes or migrate data at startup")
+	withWorkers := flags.Bool("with-workers", false, "also run the background job consumers in this process")
+	flags.Parse(args)
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	if !*skipMigrate {
+		if err := a.mongoClient.Migrate(context.Background()); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	// Start background jobs
+	group := background.NewGroup()
+	group.Go("websocket-hub", a.websocketService.Start)
+	group.Go("notification-delivery", jobs.DeliverNotifications(a.redisClient, a.websocketService))
+	if *withWorkers {
+		startWorkers(a, group, nil)
+	}
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(a.authService, a.auditService, a.velocityDetector, a.consentService, a.privacyService, a.referralService, a.passwordResets, cfg.OAuthCompleteURL)
+	var matchingQueue *database.RedisClient
+	if cfg.AsyncMatching {
+		matchingQueue = a.redisClient
+	}
+	needHandler := handlers.NewNeedHandler(a.matchingService, a.websocketService, a.mongoClient, a.settings, matchingQueue, a.moderationService, a.velocityDetector, a.privacyService, a.analyticsService, a.feedbackService, a.taskService, a.translationService, a.needComposer, a.transcriber, a.slaService, a.waitlistService, a.notificationService, a.categoryService, a.topicService, a.expiryService, a.auditService)
+	volunteerHandler := handlers.NewVolunteerHandler(a.matchingService, a.websocketService, a.mongoClient, a.moderationService, a.privacyService, a.translationService, a.waitlistService, matchingQueue, a.categoryService)
+	neighborhoodHandler := handlers.NewNeighborhoodHandler(a.matchingService)
+	announcementHandler := handlers.NewAnnouncementHandler(a.announcementService, a.auditService)
+	moderationHandler := handlers.NewModerationHandler(a.moderationService, a.mongoClient, needHandler, a.feedbackService, a.kudosService, a.auditService)
+	var websocketOrigins []string
+	if cfg.WebSocketCheckOrigin {
+		websocketOrigins = cfg.CORSAllowedOrigins
+	}
+	websocketHandler := handlers.NewWebSocketHandler(a.websocketService, websocketOrigins)
+	adminHandler := handlers.NewAdminHandler(a.mongoClient, a.auditService, a.trustService)
+	auditHandler := handlers.NewAuditHandler(a.auditService)
+	exportHandler := handlers.NewDataExportHandler(a.exportService, a.redisClient)
+	var partnerEvents *database.RedisClient
+	if a.partnerSender.Enabled() {
+		partnerEvents = a.redisClient
+	}
+	erasureHandler := handlers.NewErasureHandler(a.erasureService, a.authService, a.auditService, partnerEvents)
+	settingsHandler := handlers.NewSettingsHandler(a.settings, a.categoryService, a.auditService)
+	consentHandler := handlers.NewConsentHandler(a.consentService, a.auditService)
+	privacyHandler := handlers.NewPrivacyHandler(a.privacyService, a.auditService)
+	notificationHandler := handlers.NewNotificationHandler(a.notificationService, a.inboxService)
+	deviceHandler := handlers.NewDeviceHandler(a.pushService)
+	groupHandler := handlers.NewGroupHandler(a.groupService, a.privacyService, a.auditService, a.mongoClient)
+	eventHandler := handlers.NewEventHandler(a.eventService, a.privacyService, a.auditService, a.redisClient)
+	contributionHandler := handlers.NewContributionHandler(a.contributionService, a.auditService)
+	referralHandler := handlers.NewReferralHandler(a.referralService)
+	postHandler := handlers.NewPostHandler(a.postService, a.moderationService, a.auditService)
+	offerHandler := handlers.NewOfferHandler(a.offerService, a.matchingService, a.websocketService, a.mongoClient, a.moderationService, a.privacyService, a.analyticsService, a.slaService, matchingQueue)
+	feedbackHandler := handlers.NewFeedbackHandler(a.feedbackService)
+	recordHandler := handlers.NewRecordHandler(a.recordService)
+	mapHandler := handlers.NewMapHandler(a.mapService)
+	slaHandler := handlers.NewSLAHandler(a.slaService)
+	statsHandler := handlers.NewStatsHandler(a.statsService)
+	categoryHandler := handlers.NewCategoryHandler(a.categoryService, a.auditService)
+	boostHandler := handlers.NewBoostHandler(a.boostService)
+	uploadHandler := handlers.NewUploadHandler(a.uploadService)
+
+	// Readiness needs the datastores, and OpenAI when a key is set
+	healthChecks := []handlers.HealthCheck{
+		{Name: "mongo", Check: a.mongoClient.Ping},
+		{Name: "redis", Check: a.redisClient.Ping},
+	}
+	if cfg.OpenAIKey != "" {
+		healthChecks = append(healthChecks, handlers.HealthCheck{Name: "openai", Check: a.embeddingService.Ping, Cached: true})
+	}
+	healthHandler := handlers.NewHealthHandler(healthChecks...)
+	kudosHandler := handlers.NewKudosHandler(a.kudosService)
+	messageHandler := handlers.NewMessageHandler(a.messageService)
+	pointsHandler := handlers.NewPointsHandler(a.pointsService)
+	partnerHandler := handlers.NewPartnerHandler(a.partnerService, needHandler, a.moderationService, a.auditService)
+	emergencyHandler := handlers.NewEmergencyHandler(a.emergencyService, a.announcementService, a.privacyService, a.auditService)
+
+	// Inbound webhooks from external providers
+	webhookReceiver := webhooks.NewReceiver(a.redisClient, cfg.WebhookReplayWindow)
+	if cfg.StripeWebhookSecret != "" {
+		stripeEvents := webhooks.LogEvent
+		if a.contributionService.Enabled() {
+			stripeEvents = func(ctx context.Context, event webhooks.Event) error {
+				return a.contributionService.HandleStripeEvent(ctx, event.Body)
+			}
+		}
+		webhookReceiver.Register(webhooks.NewStripeProvider(cfg.StripeWebhookSecret, cfg.StripeWebhookTolerance), stripeEvents)
+	}
+	if cfg.CheckrAPIKey != "" {
+		webhookReceiver.Register(webhooks.NewCheckrProvider(cfg.CheckrAPIKey), webhooks.LogEvent)
+	}
+	if cfg.TwilioAuthToken != "" {
+		webhookReceiver.Register(webhooks.NewTwilioProvider(cfg.TwilioAuthToken, cfg.WebhookBaseURL), webhooks.LogEvent)
+	}
+	webhookHandler := handlers.NewWebhookHandler(webhookReceiver)
+
+	graphqlSchema, err := graph.NewSchema(a.matchingService, a.privacyService, a.analyticsService, a.mongoClient)
+	if err != nil {
+		return fmt.Errorf("failed to parse GraphQL schema: %w", err)
+	}
+	graphqlHandler := handlers.NewGraphQLHandler(graphqlSchema, a.mongoClient)
+
+	// Register request validators
+	if err := handlers.RegisterValidators(); err != nil {
+		return fmt.Errorf("failed to register validators: %w", err)
+	}
+
+	// Setup Gin router; request logging is only on at debug and info levels
+	gin.SetMode(cfg.GinMode)
+	router := gin.New()
+	if cfg.LogLevel == "debug" || cfg.LogLevel == "info" {
+		router.Use(gin.Logger())
+	}
+	// Tracing and request metrics wrap recovery so panics count as 500s
+	router.Use(telemetry.Middleware())
+	router.Use(gin.Recovery())
+
+	// Browser security headers
+	if cfg.SecurityHeaders {
+		router.Use(middleware.SecurityHeaders(cfg.HSTSMaxAge))
+	}
+
+	// CORS configuration
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     cfg.CORSAllowedOrigins,
+		AllowMethods:     cfg.CORSAllowedMethods,
+		AllowHeaders:     cfg.CORSAllowedHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+	}))
+
+	// Response compression
+	router.Use(middleware.Compression())
+
+	// Request body size limits
+	router.Use(middleware.BodyLimit(cfg.MaxRequestBodyBytes, cfg.MaxUploadBytes))
+
+	// Response language, from Accept-Language
+	router.Use(middleware.Locale())
+
+	// Client address for actions services audit themselves
+	router.Use(middleware.AuditClient())
+
+	// Liveness and readiness probes; /health stays for existing health checks
+	router.GET("/health", healthHandler.Live)
+	router.GET("/healthz", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
+
+	// Prometheus metrics, exempt from load shedding so scrapes still land
+	// when the instance is busiest
+	router.GET("/metrics", telemetry.MetricsHandler(cfg.MetricsToken))
+
+	// Concurrency limit for everything below; health checks stay exempt so
+	// a busy instance is not mistaken for a dead one
+	router.Use(middleware.LoadShed(cfg.MaxInFlightRequests, cfg.MaxQueuedRequests, cfg.RequestQueueTimeout))
+
+	// API routes, one group per version sharing the same handlers
+	routes := apiHandlers{
+		authService:  a.authService,
+		auth:         authHandler,
+		need:         needHandler,
+		volunteer:    volunteerHandler,
+		websocket:    websocketHandler,
+		admin:        adminHandler,
+		settings:     settingsHandler,
+		moderation:   moderationHandler,
+		neighborhood: neighborhoodHandler,
+		announcement: announcementHandler,
+		audit:        auditHandler,
+		export:       exportHandler,
+		erasure:      erasureHandler,
+		consent:      consentHandler,
+		privacy:      privacyHandler,
+		notification: notificationHandler,
+		device:       deviceHandler,
+		group:        groupHandler,
+		event:        eventHandler,
+		contribution: contributionHandler,
+		referral:     referralHandler,
+		emergency:    emergencyHandler,
+		post:         postHandler,
+		offer:        offerHandler,
+		partner:      partnerHandler,
+		feedback:     feedbackHandler,
+		kudos:        kudosHandler,
+		message:      messageHandler,
+		points:       pointsHandler,
+		record:       recordHandler,
+		maps:         mapHandler,
+		sla:          slaHandler,
+		stats:        statsHandler,
+		category:     categoryHandler,
+		boost:        boostHandler,
+		upload:       uploadHandler,
+
+		consentService: a.consentService,
+		partnerService: a.partnerService,
+
+		authRateLimit: middleware.RateLimit(a.redisClient, "auth", func(ctx context.Context) int {
+			return a.settings.Get(ctx).RateLimitAuthPerMinute
+		}),
+		apiRateLimit: middleware.RateLimit(a.redisClient, "api", func(ctx context.Context) int {
+			return a.settings.Get(ctx).RateLimitAPIPerMinute
+		}),
+	}
+
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.APIVersion("v1"))
+	if cfg.APIV1Sunset != "" {
+		v1.Use(middleware.Deprecation(cfg.APIV1Sunset, "/api/v2"))
+	}
+	registerRoutes(v1, routes)
+
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIVersion("v2"), middleware.ResponseEnvelope())
+	registerRoutes(v2, routes)
+
+	// Webhook endpoint (authenticated by provider signatures, not JWTs)
+	router.POST("/webhooks/:provider", middleware.RawBody(), webhookHandler.Receive)
+
+	// Profiling and runtime diagnostics for admins (importing net/http/pprof also
+	// registers on http.DefaultServeMux, which this server never serves)
+	debugHandler := handlers.NewDebugHandler()
+	debug := router.Group("/debug")
+	debug.Use(middleware.AuthMiddleware(a.authService), middleware.RequireRole(models.RoleAdmin))
+	{
+		debug.GET("/version", debugHandler.GetVersion)
+		debug.GET("/runtime", debugHandler.GetRuntime)
+		debug.GET("/goroutines", debugHandler.GetGoroutines)
+
+		debug.GET("/pprof/", gin.WrapF(pprof.Index))
+		debug.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+		for _, profile := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+			debug.GET("/pprof/"+profile, gin.WrapH(pprof.Handler(profile)))
+		}
+	}
+
+	// GraphQL endpoint
+	router.POST("/graphql", middleware.AuthMiddleware(a.authService), middleware.RequireConsent(a.consentService), graphqlHandler.Query)
+
+	// Start server
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	redirectServer := configureTLS(cfg, server)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 2)
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			log.Printf("Starting NeighborNexus server with TLS on port %s", cfg.HTTPSPort)
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			log.Printf("Starting NeighborNexus server on port %s", cfg.Port)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+	if redirectServer != nil {
+		go func() {
+			log.Printf("Redirecting HTTP on port %s to HTTPS", cfg.Port)
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				serveErr <- err
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		group.Shutdown(context.Background())
+		a.Close()
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	stop()
+	log.Println("Shutting down server...")
+
+	// Fail readiness, then give load balancers the drain delay to stop
+	// routing here before connections are refused. The rest of shutdown
+	// stops accepting connections and finishes in-flight requests,
+	// disconnects WebSocket clients, and stops background jobs, all within
+	// one deadline.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	healthHandler.Drain()
+	time.Sleep(cfg.DrainDelay)
+
+	if redirectServer != nil {
+		redirectServer.Shutdown(shutdownCtx)
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server did not shut down cleanly: %v", err)
+	}
+	a.websocketService.Drain(shutdownCtx)
+	if err := group.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Background jobs did not stop in time: %v", err)
+	}
+	a.Close()
+
+	log.Println("Server stopped")
+	return nil
+}
+
+// apiHandlers bundles the handlers mounted on each API version
+type apiHandlers struct {
+	authService  *services.AuthService
+	auth         *handlers.AuthHandler
+	need         *handlers.NeedHandler
+	volunteer    *handlers.VolunteerHandler
+	websocket    *handlers.WebSocketHandler
+	admin        *handlers.AdminHandler
+	settings     *handlers.SettingsHandler
+	moderation   *handlers.ModerationHandler
+	neighborhood *handlers.NeighborhoodHandler
+	announcement *handlers.AnnouncementHandler
+	audit        *handlers.AuditHandler
+	export       *handlers.DataExportHandler
+	erasure      *handlers.ErasureHandler
+	consent      *handlers.ConsentHandler
+	privacy      *handlers.PrivacyHandler
+	notification *handlers.NotificationHandler
+	device       *handlers.DeviceHandler
+	group        *handlers.GroupHandler
+	event        *handlers.EventHandler
+	contribution *handlers.ContributionHandler
+	referral     *handlers.ReferralHandler
+	emergency    *handlers.EmergencyHandler
+	post         *handlers.PostHandler
+	offer        *handlers.OfferHandler
+	partner      *handlers.PartnerHandler
+	feedback     *handlers.FeedbackHandler
+	record       *handlers.RecordHandler
+	maps         *handlers.MapHandler
+	sla          *handlers.SLAHandler
+	stats        *handlers.StatsHandler
+	category     *handlers.CategoryHandler
+	boost        *handlers.BoostHandler
+	kudos        *handlers.KudosHandler
+	message      *handlers.MessageHandler
+	points       *handlers.PointsHandler
+	upload       *handlers.UploadHandler
+
+	// consentService gates routes on acceptance of the current policies
+	consentService *services.ConsentService
+	// partnerService authenticates partner organizations' API keys
+	partnerService *services.PartnerService
+
+	// Rate limits for unauthenticated auth routes and authenticated API routes
+	authRateLimit gin.HandlerFunc
+	apiRateLimit  gin.HandlerFunc
+}
+
+// registerRoutes mounts the API routes on a versioned router group
+func registerRoutes(api *gin.RouterGroup, h apiHandlers) {
+	// Auth routes
+	auth := api.Group("/auth")
+	auth.Use(h.authRateLimit)
+	{
+		auth.POST("/register", h.auth.Register)
+		auth.POST("/login", h.auth.Login)
+		auth.POST("/refresh", h.auth.RefreshToken)
+		auth.POST("/forgot-password", h.auth.ForgotPassword)
+		auth.POST("/reset-password", h.auth.ResetPassword)
+		auth.POST("/logout", h.auth.Logout)
+		auth.GET("/oauth/:provider", h.auth.StartOAuth)
+		auth.GET("/oauth/:provider/callback", h.auth.OAuthCallback)
+		auth.POST("/oauth/:provider/callback", h.auth.OAuthCallback)
+
+		// Signed-in devices, which users can sign out remotely
+		sessions := auth.Group("/sessions")
+		sessions.Use(middleware.AuthMiddleware(h.authService))
+		{
+			sessions.GET("", h.auth.ListSessions)
+			sessions.DELETE("", h.auth.RevokeOtherSessions)
+			sessions.DELETE("/:id", h.auth.RevokeSession)
+		}
+	}
+
+	// Partner intake, authenticated by organization API keys rather than JWTs
+	partner := api.Group("/partner")
+	partner.Use(middleware.PartnerAuth(h.partnerService), h.apiRateLimit)
+	{
+		partner.POST("/referrals", h.partner.CreateReferral)
+		partner.GET("/referrals/:externalId", h.partner.GetReferral)
+		partner.DELETE("/referrals/:externalId", h.partner.CancelReferral)
+	}
+
+	// Protected routes
+	protected := api.Group("/")
+	protected.Use(middleware.AuthMiddleware(h.authService), h.apiRateLimit)
+	{
+		// User profile, reachable before accepting updated policies so users
+		// can review and accept them, or export their data and leave instead
+		protected.GET("/profile", h.auth.GetProfile)
+		protected.PUT("/profile", h.auth.UpdateProfile)
+		protected.DELETE("/profile", h.erasure.DeleteAccount)
+		protected.POST("/profile/export", h.export.RequestExport)
+		protected.GET("/profile/export", h.export.GetExport)
+		protected.GET("/profile/consent", h.consent.GetConsent)
+		protected.POST("/profile/consent", h.consent.AcceptConsent)
+
+		// Push notification devices, which apps register on sign-in and
+		// unregister on sign-out
+		protected.POST("/devices", h.device.RegisterDevice)
+		protected.DELETE("/devices", h.device.UnregisterDevice)
+	}
+
+	// Everything else requires the current policies to have been accepted
+	consented := protected.Group("")
+	consented.Use(middleware.RequireConsent(h.consentService))
+	{
+		// Privacy settings
+		consented.GET("/profile/privacy", h.privacy.GetPrivacySettings)
+		consented.PUT("/profile/privacy", h.privacy.UpdatePrivacySettings)
+
+		// Email notification preferences
+		consented.GET("/profile/notifications", h.notification.GetPreferences)
+		consented.PUT("/profile/notifications", h.notification.UpdatePreferences)
+
+		// Notification inbox
+		consented.GET("/notifications", h.notification.ListNotifications)
+		consented.POST("/notifications/read", h.notification.MarkAllNotificationsRead)
+		consented.POST("/notifications/:id/read", h.notification.MarkNotificationRead)
+
+		// Profile photo and need image uploads
+		consented.POST("/uploads", h.upload.CreateUpload)
+		consented.GET("/uploads/:id", h.upload.GetUpload)
+		consented.PUT("/profile/photo", h.upload.SetPhoto)
+		consented.DELETE("/profile/photo", h.upload.RemovePhoto)
+
+		// Streamed CSV and NDJSON downloads of the user's own records
+		records := consented.Group("/records")
+		{
+			records.GET("/needs", h.record.ExportNeeds)
+			records.GET("/tasks", h.record.ExportTasks)
+			records.GET("/hours", h.record.ExportHours)
+		}
+
+		// Content reports
+		consented.POST("/reports", h.moderation.ReportContent)
+		consented.GET("/reports", h.moderation.ListMyReports)
+
+		// Announcements targeted at the current user
+		consented.GET("/announcements", h.announcement.GetMyAnnouncements)
+
+		// The need category taxonomy
+		consented.GET("/categories", h.category.ListCategories)
+
+		// Neighborhood announcement feeds
+		posts := consented.Group("/posts")
+		{
+			posts.POST("/", h.post.CreatePost)
+			posts.GET("/", h.post.GetFeed)
+			posts.GET("/:id", h.post.GetPost)
+			posts.DELETE("/:id", h.post.DeletePost)
+			posts.PUT("/:id/reaction", h.post.React)
+			posts.DELETE("/:id/reaction", h.post.Unreact)
+		}
+
+		// Emergencies in force and the needs inside them
+		consented.GET("/emergencies", h.emergency.ListEmergencies)
+		consented.GET("/emergencies/needs", h.emergency.GetFeed)
+
+		// Invites and referral counts
+		consented.POST("/invites", h.referral.CreateInvite)
+		consented.GET("/invites", h.referral.GetMyInvites)
+		consented.DELETE("/invites/:id", h.referral.RevokeInvite)
+		consented.GET("/referrals", h.referral.GetMyReferrals)
+
+		// Neighborhood groups
+		groups := consented.Group("/groups")
+		{
+			groups.POST("/", h.group.CreateGroup)
+			groups.GET("/", h.group.ListGroups)
+			groups.GET("/:id", h.group.GetGroup)
+			groups.PUT("/:id", h.group.UpdateGroup)
+			groups.DELETE("/:id", h.group.DeleteGroup)
+			groups.POST("/:id/join", h.group.JoinGroup)
+			groups.POST("/:id/leave", h.group.LeaveGroup)
+			groups.GET("/:id/members", h.group.GetMembers)
+			groups.PUT("/:id/members/:userId", h.group.UpdateMember)
+			groups.DELETE("/:id/members/:userId", h.group.RemoveMember)
+			groups.GET("/:id/needs", h.group.GetFeed)
+		}
+
+		// Group volunteering events
+		events := consented.Group("/events")
+		{
+			events.POST("/", h.event.CreateEvent)
+			events.GET("/", h.event.ListEvents)
+			events.GET("/:id", h.event.GetEvent)
+			events.DELETE("/:id", h.event.CancelEvent)
+			events.POST("/:id/signups", h.event.SignUp)
+			events.GET("/:id/signups", h.event.GetSignups)
+			events.DELETE("/:id/signups/:signupId", h.event.CancelSignup)
+			events.PUT("/:id/signups/:signupId/attendance", h.event.RecordAttendance)
+		}
+
+		// Needs
+		needs := consented.Group("/needs")
+		{
+			needs.POST("/", h.need.CreateNeed)
+			needs.POST("/draft-from-text", h.need.DraftNeed)
+			needs.POST("/draft-from-voice", h.need.DraftNeedFromVoice)
+			needs.POST("/bulk/cancel", h.need.BulkCancelNeeds)
+			needs.POST("/bulk/tags", h.need.BulkTagNeeds)
+			needs.GET("/", middleware.ETag(), h.need.GetNeeds)
+			needs.GET("/map", h.maps.GetNeedMap)
+			needs.GET("/:id", h.need.GetNeed)
+			needs.PUT("/:id", h.need.UpdateNeed)
+			needs.DELETE("/:id", h.need.DeleteNeed)
+			needs.POST("/:id/accept", h.need.AcceptNeed)
+			needs.POST("/:id/boost", h.boost.BoostNeed)
+			needs.POST("/:id/extend", h.need.ExtendNeed)
+			needs.PUT("/:id/material-cost", h.contribution.SetMaterialCost)
+			needs.PUT("/:id/images", h.upload.SetNeedImages)
+			needs.POST("/:id/contributions", h.contribution.Contribute)
+			needs.GET("/:id/offers", h.offer.GetNeedOffers)
+		}
+
+		// Volunteers' standing offers
+		offers := consented.Group("/offers")
+		{
+			offers.POST("/", h.offer.CreateOffer)
+			offers.GET("/", h.offer.GetOffers)
+			offers.GET("/:id", h.offer.GetOffer)
+			offers.PUT("/:id", h.offer.UpdateOffer)
+			offers.DELETE("/:id", h.offer.CloseOffer)
+			offers.GET("/:id/matches", h.offer.GetOfferMatches)
+			offers.POST("/:id/accept", h.offer.AcceptOffer)
+		}
+
+		// Contributions toward material costs, and payouts to volunteers
+		consented.GET("/contributions", h.contribution.GetMyContributions)
+		consented.GET("/contributions/:id/receipt", h.contribution.GetReceipt)
+		consented.GET("/payouts", h.contribution.GetMyPayouts)
+
+		// Volunteers
+		volunteers := consented.Group("/volunteers")
+		{
+			volunteers.POST("/profile", h.volunteer.CreateProfile)
+			volunteers.GET("/profile", h.volunteer.GetProfile)
+			volunteers.PUT("/profile", h.volunteer.UpdateProfile)
+			volunteers.GET("/availability/exceptions", h.volunteer.ListAvailabilityExceptions)
+			volunteers.POST("/availability/exceptions", h.volunteer.AddAvailabilityException)
+			volunteers.PUT("/availability/exceptions/:exceptionId", h.volunteer.UpdateAvailabilityException)
+			volunteers.DELETE("/availability/exceptions/:exceptionId", h.volunteer.DeleteAvailabilityException)
+			volunteers.GET("/matches", middleware.ETag(), h.volunteer.GetMatches)
+			volunteers.POST("/matches/:id/decline", h.volunteer.DeclineMatch)
+			volunteers.DELETE("/matches/:id/decline", h.volunteer.RestoreMatch)
+			volunteers.GET("/points", h.points.GetMyPoints)
+			volunteers.GET("/feedback-insights", h.feedback.GetFeedbackInsights)
+			volunteers.GET("/:id/kudos", h.kudos.ListKudos)
+			volunteers.GET("/:id/testimonials", h.feedback.ListTestimonials)
+		}
+		// Why a volunteer matched a need; :id is the need's ID
+		consented.GET("/matches/:id/explanation", h.volunteer.ExplainMatch)
+		consented.GET("/leaderboard", h.points.GetLeaderboard)
+
+		// Supply and demand across the community
+		consented.GET("/community/coverage", h.maps.GetCoverage)
+
+		// Tasks
+		tasks := consented.Group("/tasks")
+		{
+			tasks.GET("/", middleware.ETag(), h.need.GetTasks)
+			tasks.POST("/bulk/status", h.need.BulkUpdateTaskStatus)
+			tasks.GET("/:id", h.need.GetTask)
+			tasks.PUT("/:id/status", h.need.UpdateTaskStatus)
+			tasks.GET("/:id/contact", h.privacy.GetTaskContact)
+			tasks.GET("/:id/feedback", h.feedback.GetTaskFeedback)
+			tasks.POST("/:id/feedback", h.feedback.SubmitFeedback)
+			tasks.POST("/:id/kudos", h.kudos.SendKudos)
+			tasks.GET("/:id/messages", h.message.ListMessages)
+			tasks.POST("/:id/messages", h.message.SendMessage)
+			tasks.POST("/:id/messages/read", h.message.MarkMessagesRead)
+		}
+
+		feedback := consented.Group("/feedback")
+		{
+			feedback.POST("/:id/response", h.feedback.RespondToFeedback)
+			feedback.POST("/:id/appeal", h.feedback.AppealFeedback)
+			feedback.PUT("/:id/testimonial", h.feedback.ShareTestimonial)
+			feedback.POST("/:id/testimonial/approve", h.feedback.ApproveTestimonial)
+			feedback.DELETE("/:id/testimonial", h.feedback.WithdrawTestimonial)
+		}
+		consented.GET("/highlights", h.feedback.GetHighlights)
+
+		// Moderation, open to moderators as well as admins
+		moderation := consented.Group("/admin")
+		moderation.Use(middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+		{
+			moderation.POST("/users/:id/suspension", h.admin.SuspendUser)
+			moderation.DELETE("/users/:id/suspension", h.admin.ReinstateUser)
+			moderation.GET("/users/:id/reports", h.moderation.GetUserReports)
+			moderation.GET("/needs", h.admin.ListNeeds)
+			moderation.DELETE("/needs/:id", h.admin.DeleteNeed)
+			moderation.GET("/reports", h.admin.ListReports)
+
+			// Moderation queue
+			moderation.GET("/moderation", h.moderation.ListQueue)
+			moderation.GET("/moderation/:id", h.moderation.GetItem)
+			moderation.POST("/moderation/:id/approve", h.moderation.Approve)
+			moderation.POST("/moderation/:id/edit", h.moderation.Edit)
+			moderation.POST("/moderation/:id/hide", h.moderation.Hide)
+			moderation.POST("/moderation/:id/escalate", h.moderation.Escalate)
+		}
+
+		// Admin
+		admin := consented.Group("/admin")
+		admin.Use(middleware.RequireRole(models.RoleAdmin))
+		{
+			admin.GET("/users", h.admin.ListUsers)
+			admin.GET("/users/:id", h.admin.GetUser)
+			admin.PUT("/users/:id/role", h.admin.UpdateUserRole)
+			admin.PUT("/users/:id/supervision", h.admin.UpdateSupervision)
+			admin.PUT("/users/:id/organizer", h.admin.UpdateOrganizer)
+			admin.PUT("/users/:id/identity", h.admin.UpdateIdentity)
+			admin.DELETE("/users/:id", h.erasure.EraseUser)
+			admin.GET("/tasks", h.admin.ListTasks)
+			admin.GET("/metrics", h.admin.GetMetrics)
+			admin.GET("/metrics/sla", h.sla.GetMetrics)
+			admin.GET("/stats", h.stats.GetOverview)
+			admin.GET("/stats/time-to-match", h.stats.GetTimeToMatch)
+			admin.POST("/categories", h.category.CreateCategory)
+			admin.PUT("/categories/:id", h.category.UpdateCategory)
+			admin.DELETE("/categories/:id", h.category.DeleteCategory)
+			admin.GET("/neighborhoods", h.neighborhood.GetHealth)
+			admin.GET("/referrals", h.referral.GetNeighborhoodReferrals)
+			admin.GET("/emergencies", h.emergency.ListAllEmergencies)
+			admin.POST("/emergencies", h.emergency.DeclareEmergency)
+			admin.DELETE("/emergencies/:id", h.emergency.EndEmergency)
+			admin.GET("/announcements", h.announcement.ListAnnouncements)
+			admin.POST("/announcements", h.announcement.CreateAnnouncement)
+			admin.DELETE("/announcements/:id", h.announcement.CancelAnnouncement)
+			admin.GET("/payouts", h.contribution.ListPayouts)
+			admin.POST("/payouts/:id/paid", h.contribution.MarkPayoutPaid)
+			admin.GET("/partners", h.partner.ListPartners)
+			admin.POST("/partners", h.partner.CreatePartner)
+			admin.PUT("/partners/:id", h.partner.UpdatePartner)
+			admin.POST("/partners/:id/key", h.partner.RotatePartnerKey)
+			admin.DELETE("/partners/:id", h.partner.RevokePartner)
+			admin.GET("/audit", h.audit.ListEntries)
+			admin.GET("/audit/export", h.audit.ExportEntries)
+			admin.GET("/records/activity", h.record.ExportActivity)
+			admin.GET("/settings", h.settings.GetSettings)
+			admin.PUT("/settings", h.settings.UpdateSettings)
+			admin.DELETE("/settings", h.settings.ResetSettings)
+			admin.GET("/policies", h.consent.ListPolicies)
+			admin.POST("/policies", h.consent.PublishPolicy)
+		}
+	}
+
+	// Data export downloads are authorized by their signed link
+	api.GET("/exports/:id/download", h.export.DownloadExport)
+
+	// WebSocket endpoint
+	api.GET("/ws", middleware.AuthMiddleware(h.authService), middleware.RequireConsent(h.consentService), h.websocket.HandleWebSocket)
+} 
\ No newline at end of file
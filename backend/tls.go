@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+	"neighborenexus/internal/config"
+)
+
+// configureTLS moves server to the HTTPS port and sets its TLS config, using
+// Let's Encrypt when autocert domains are configured. It returns the plain
+// HTTP server that redirects to HTTPS and answers ACME challenges, or nil
+// when TLS is disabled. Health checks are answered on both ports.
+func configureTLS(cfg *config.Config, server *http.Server) *http.Server {
+	if !cfg.TLSEnabled() {
+		return nil
+	}
+
+	server.Addr = ":" + cfg.HTTPSPort
+	httpHandler := redirectToHTTPS(cfg.HTTPSPort, server.Handler)
+
+	if len(cfg.TLSAutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+			Email:      cfg.TLSAutocertEmail,
+		}
+		server.TLSConfig = manager.TLSConfig()
+		httpHandler = manager.HTTPHandler(httpHandler)
+	} else {
+		server.TLSConfig = &tls.Config{}
+	}
+	server.TLSConfig.MinVersion = tls.VersionTLS12
+
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           httpHandler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// redirectToHTTPS redirects requests to the same URL on the HTTPS port,
+// passing /health through to the application so probes needn't speak TLS
+func redirectToHTTPS(httpsPort string, app http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			app.ServeHTTP(w, r)
+			return
+		}
+
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		status := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+} 
\ No newline at end of file
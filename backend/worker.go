@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"neighborenexus/internal/background"
+	"neighborenexus/internal/config"
+	"neighborenexus/internal/jobs"
+)
+
+// runWorker runs the background job consumers until SIGINT or SIGTERM
+func runWorker(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("worker", flag.ExitOnError)
+	only := flags.String("only", "", "comma-separated jobs to run (default all): "+strings.Join(workerJobNames, ", "))
+	flags.Parse(args)
+
+	var selected []string
+	if *only != "" {
+		selected = strings.Split(*only, ",")
+		for _, name := range selected {
+			if !contains(workerJobNames, name) {
+				return fmt.Errorf("unknown job %q", name)
+			}
+		}
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	group := background.NewGroup()
+	startWorkers(a, group, selected)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down worker...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := group.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Background jobs did not stop in time: %v", err)
+	}
+
+	log.Println("Worker stopped")
+	return nil
+}
+
+// workerJobNames are the jobs a worker can run
+var workerJobNames = []string{"embeddings", "matching", "notifications", "digests", "announcements", "exports", "partner-events", "analytics", "event-reminders", "payouts", "partner-referrals", "feedback-reveal", "task-confirmations", "need-expiry", "uploads", "volunteer-points", "trust-scores", "ratings", "need-slas", "match-decisions", "notification-purge", "emails", "push"}
+
+// startWorkers runs the named background jobs on group, or all of them if names is empty
+func startWorkers(a *app, group *background.Group, names []string) {
+	if len(names) == 0 {
+		names = workerJobNames
+	}
+
+	// consume runs the configured number of consumers on queue
+	consume := func(queue string, handler jobs.Handler, attempts int) {
+		for i := 1; i <= a.cfg.WorkerConcurrency; i++ {
+			consumer := jobs.NewConsumer(a.redisClient, queue, handler, attempts)
+			group.Go(fmt.Sprintf("%s-%d", queue, i), consumer.Run)
+		}
+	}
+
+	for _, name := range names {
+		switch name {
+		case "embeddings":
+			consume(jobs.QueueEmbeddings, jobs.EmbeddingHandler(a.matchingService, a.mongoClient, a.redisClient), a.cfg.JobMaxAttempts)
+		case "matching":
+			consume(jobs.QueueMatching, jobs.MatchingHandler(a.matchingService, a.analyticsService, a.slaService, a.waitlistService, a.notificationService, a.mongoClient, a.redisClient, a.settings), a.cfg.JobMaxAttempts)
+		case "notifications":
+			consume(jobs.QueueNotifications, jobs.NotificationHandler(a.redisClient, a.pushService, a.inboxService), a.cfg.JobMaxAttempts)
+		case "digests":
+			if a.cfg.DigestInterval > 0 {
+				group.Go(name, jobs.Digests(a.matchingService, a.announcementService, a.mongoClient, a.redisClient, a.cfg.DigestInterval))
+			}
+		case "announcements":
+			group.Go(name, jobs.Announcements(a.announcementService, a.redisClient))
+		case "exports":
+			consume(jobs.QueueExports, jobs.ExportHandler(a.exportService, a.redisClient), a.cfg.JobMaxAttempts)
+			group.Go("export-purge", jobs.PurgeExports(a.exportService))
+		case "partner-events":
+			consume(jobs.QueuePartnerEvents, jobs.PartnerEventHandler(a.partnerSender), jobs.PartnerEventAttempts)
+		case "analytics":
+			consume(jobs.QueueAnalytics, jobs.AnalyticsHandler(a.mongoClient, a.analyticsSink), a.cfg.JobMaxAttempts)
+		case "event-reminders":
+			if a.cfg.EventReminderLead > 0 {
+				group.Go(name, jobs.EventReminders(a.eventService, a.redisClient, a.cfg.EventReminderLead))
+			}
+		case "payouts":
+			if a.contributionService.Enabled() {
+				group.Go(name, jobs.Payouts(a.contributionService))
+			}
+		case "partner-referrals":
+			consume(jobs.QueueReferralEvents, jobs.ReferralEventHandler(a.partnerService), jobs.PartnerEventAttempts)
+			group.Go("referral-status", jobs.ReferralStatus(a.partnerService, a.redisClient))
+		case "feedback-reveal":
+			group.Go(name, jobs.FeedbackReveal(a.feedbackService))
+		case "task-confirmations":
+			group.Go(name, jobs.TaskConfirmations(a.taskService))
+		case "need-expiry":
+			group.Go(name, jobs.NeedExpiry(a.expiryService))
+		case "uploads":
+			if a.uploadService.Enabled() {
+				group.Go(name, jobs.UploadPurge(a.uploadService))
+			}
+		case "volunteer-points":
+			group.Go(name, jobs.VolunteerPoints(a.pointsService))
+		case "trust-scores":
+			group.Go(name, jobs.TrustScores(a.trustService))
+		case "ratings":
+			group.Go(name, jobs.Ratings(a.ratingService))
+		case "need-slas":
+			group.Go(name, jobs.NeedSLAs(a.slaService))
+		case "match-decisions":
+			if a.cfg.MatchDecisionRetention > 0 {
+				group.Go(name, jobs.PurgeMatchDecisions(a.matchDecisions))
+			}
+		case "notification-purge":
+			if a.cfg.NotificationRetention > 0 {
+				group.Go(name, jobs.PurgeNotifications(a.inboxService))
+			}
+		case "emails":
+			consume(jobs.QueueEmails, jobs.EmailHandler(a.mailer), a.cfg.JobMaxAttempts)
+		case "push":
+			consume(jobs.QueuePush, jobs.PushHandler(a.pushService), a.cfg.JobMaxAttempts)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+} 
\ No newline at end of file
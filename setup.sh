@@ -56,7 +56,7 @@ if docker-compose ps | grep -q "Up"; then
     echo ""
     echo "🌐 NeighborNexus is now available at:"
     echo "   - Backend API: http://localhost:8080"
-    echo "   - Health check: http://localhost:8080/health"
+    echo "   - Health check: http://localhost:8080/healthz"
     echo ""
     echo "📝 Next steps:"
     echo "   1. Update the .env file with your OpenAI and Pinecone API keys"